@@ -13,17 +13,96 @@ import (
 )
 
 // NewSpare returns a new Spare struct.
-func NewSpare(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) (*Spare, error) {
+func NewSpare(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) (*Spare, error) {
 	wire.Build(
 		interactor.StorageCreatorSet,
 		interactor.FileUploaderSet,
 		interactor.CDNCreatorSet,
+		interactor.ReleaseSwitcherSet,
+		interactor.ReleasePublisherSet,
+		interactor.ReleaseListerSet,
+		interactor.ReleaseRollbackerSet,
+		interactor.GarbageCollectorSet,
+		interactor.PreviewPublisherSet,
+		interactor.PreviewListerSet,
+		interactor.PreviewRemoverSet,
+		interactor.PreviewDeleterSet,
+		interactor.PreviewExpirerSet,
+		interactor.CanaryDeployerSet,
+		interactor.CanaryPromoterSet,
+		interactor.CanaryAborterSet,
+		interactor.StatusGetterSet,
+		interactor.ViewerFunctionSet,
+		interactor.OriginAccessSet,
+		interactor.ViewerRequestApplierSet,
+		interactor.MaintenanceSwitcherSet,
+		interactor.SigningKeyOptionsSet,
+		interactor.SigningKeyCreatorSet,
+		interactor.SigningKeyRotatorSet,
+		interactor.AccessLogAnalyzerSet,
+		interactor.StackListerSet,
+		interactor.PreflightCheckerSet,
+		interactor.IAMPolicyGeneratorSet,
+		interactor.IdentityResolverSet,
+		interactor.ReleaseManifestRecorderSet,
+		interactor.AuthRotatorSet,
 		external.BuckerCreatorSet,
 		external.FileUploaderSet,
 		external.BucketPublicAccessBlockerSet,
 		external.BucketPolicySetterSet,
 		external.CDNCreatorSet,
-		external.OAICreatorSet,
+		external.CDNFinderSet,
+		external.CDNOriginPathUpdaterSet,
+		external.CDNCacheInvalidatorSet,
+		external.ReleaseHistoryGetterSet,
+		external.ReleaseHistoryPutterSet,
+		external.BucketObjectListerSet,
+		external.BucketObjectDeleterSet,
+		external.PreviewListGetterSet,
+		external.PreviewListPutterSet,
+		external.CDNFunctionPublisherSet,
+		external.CDNPreviewRouteCreatorSet,
+		external.CDNStagingCreatorSet,
+		external.CDNContinuousDeploymentPolicySetterSet,
+		external.CDNContinuousDeploymentPolicyDisablerSet,
+		external.CDNCacheBehaviorApplierSet,
+		external.CDNResponseHeadersPolicyApplierSet,
+		external.CDNResponseHeadersGetterSet,
+		external.BucketCORSSetterSet,
+		external.WebACLApplierSet,
+		external.WebACLDeleterSet,
+		external.CDNWebACLAssociatorSet,
+		external.CDNViewerFunctionAssociatorSet,
+		external.MaintenanceGetterSet,
+		external.MaintenancePutterSet,
+		external.CDNCustomOriginApplierSet,
+		external.KeyGroupGetterSet,
+		external.KeyGroupApplierSet,
+		external.PublicKeyCreatorSet,
+		external.PublicKeyDeleterSet,
+		external.BucketOwnershipSetterSet,
+		external.BucketLifecycleSetterSet,
+		external.BucketLoggingSetterSet,
+		external.BucketVersioningSetterSet,
+		external.BucketObjectGetterSet,
+		external.CDNLoggingApplierSet,
+		external.BucketEncryptionSetterSet,
+		external.EncryptionKeyCreatorSet,
+		external.CDNOriginAccessControlApplierSet,
+		external.EncryptionKeyPolicyApplierSet,
+		external.ResourceTaggerSet,
+		external.TaggedResourceListerSet,
+		external.CallerIdentityGetterSet,
+		external.ProfileFinderSet,
+		external.RegionOptInStatusGetterSet,
+		external.BucketAvailabilityCheckerSet,
+		external.IAMActionsSimulatorSet,
+		external.ReleaseManifestPutterSet,
+		external.CDNStagingSyncerSet,
+		external.CDNStagingPromoterSet,
+		external.ViewerRulesGetterSet,
+		external.ViewerRulesPutterSet,
+		external.CDNARNsListerSet,
 		newSpare,
 	)
 	return nil, nil
@@ -37,6 +116,52 @@ type Spare struct {
 	CDNCreator usecase.CDNCreator
 	// FileUploader is an interface for uploading files to external storage.
 	FileUploader usecase.FileUploader
+	// ReleasePublisher is an interface for publishing the uploaded release.
+	ReleasePublisher usecase.ReleasePublisher
+	// ReleaseLister is an interface for listing the releases.
+	ReleaseLister usecase.ReleaseLister
+	// ReleaseRollbacker is an interface for switching the CDN back to the past release.
+	ReleaseRollbacker usecase.ReleaseRollbacker
+	// GarbageCollector is an interface for deleting old releases and unreferenced objects.
+	GarbageCollector usecase.GarbageCollector
+	// PreviewPublisher is an interface for publishing the uploaded preview.
+	PreviewPublisher usecase.PreviewPublisher
+	// PreviewLister is an interface for listing the previews.
+	PreviewLister usecase.PreviewLister
+	// PreviewDeleter is an interface for deleting the previews.
+	PreviewDeleter usecase.PreviewDeleter
+	// PreviewExpirer is an interface for deleting the stale previews.
+	PreviewExpirer usecase.PreviewExpirer
+	// CanaryDeployer is an interface for serving the uploaded release to a part of the viewers.
+	CanaryDeployer usecase.CanaryDeployer
+	// CanaryPromoter is an interface for making the canary release live.
+	CanaryPromoter usecase.CanaryPromoter
+	// CanaryAborter is an interface for stopping the canary release.
+	CanaryAborter usecase.CanaryAborter
+	// StatusGetter is an interface for getting the status of the SPA delivery infrastructure.
+	StatusGetter usecase.StatusGetter
+	// ViewerRequestApplier is an interface for applying the features of the viewer request function.
+	ViewerRequestApplier usecase.ViewerRequestApplier
+	// MaintenanceSwitcher is an interface for turning on or off the maintenance mode.
+	MaintenanceSwitcher usecase.MaintenanceSwitcher
+	// SigningKeyCreator is an interface for creating the signing key and the key group.
+	SigningKeyCreator usecase.SigningKeyCreator
+	// SigningKeyRotator is an interface for rotating the signing keys.
+	SigningKeyRotator usecase.SigningKeyRotator
+	// AccessLogAnalyzer is an interface for analyzing the access logs.
+	AccessLogAnalyzer usecase.AccessLogAnalyzer
+	// StackLister is an interface for listing the spare-managed stacks.
+	StackLister usecase.StackLister
+	// PreflightChecker is an interface for checking that spare can build and deploy with the credentials.
+	PreflightChecker usecase.PreflightChecker
+	// IAMPolicyGenerator is an interface for generating the least-privilege IAM policy that spare needs.
+	IAMPolicyGenerator usecase.IAMPolicyGenerator
+	// IdentityResolver is an interface for resolving the AWS account and the IAM identity of the credentials.
+	IdentityResolver usecase.IdentityResolver
+	// ReleaseManifestRecorder is an interface for recording the S3 keys of the release before uploading them.
+	ReleaseManifestRecorder usecase.ReleaseManifestRecorder
+	// AuthRotator is an interface for updating the credentials of the basic auth.
+	AuthRotator usecase.AuthRotator
 }
 
 // newSpare returns a new Spare struct.
@@ -44,10 +169,56 @@ func newSpare(
 	storageCreator usecase.StorageCreator,
 	cdncreator usecase.CDNCreator,
 	fileUploader usecase.FileUploader,
+	releasePublisher usecase.ReleasePublisher,
+	releaseLister usecase.ReleaseLister,
+	releaseRollbacker usecase.ReleaseRollbacker,
+	garbageCollector usecase.GarbageCollector,
+	previewPublisher usecase.PreviewPublisher,
+	previewLister usecase.PreviewLister,
+	previewDeleter usecase.PreviewDeleter,
+	previewExpirer usecase.PreviewExpirer,
+	canaryDeployer usecase.CanaryDeployer,
+	canaryPromoter usecase.CanaryPromoter,
+	canaryAborter usecase.CanaryAborter,
+	statusGetter usecase.StatusGetter,
+	viewerRequestApplier usecase.ViewerRequestApplier,
+	maintenanceSwitcher usecase.MaintenanceSwitcher,
+	signingKeyCreator usecase.SigningKeyCreator,
+	signingKeyRotator usecase.SigningKeyRotator,
+	accessLogAnalyzer usecase.AccessLogAnalyzer,
+	stackLister usecase.StackLister,
+	preflightChecker usecase.PreflightChecker,
+	iamPolicyGenerator usecase.IAMPolicyGenerator,
+	identityResolver usecase.IdentityResolver,
+	releaseManifestRecorder usecase.ReleaseManifestRecorder,
+	authRotator usecase.AuthRotator,
 ) *Spare {
 	return &Spare{
-		StorageCreator: storageCreator,
-		CDNCreator:     cdncreator,
-		FileUploader:   fileUploader,
+		StorageCreator:          storageCreator,
+		CDNCreator:              cdncreator,
+		FileUploader:            fileUploader,
+		ReleasePublisher:        releasePublisher,
+		ReleaseLister:           releaseLister,
+		ReleaseRollbacker:       releaseRollbacker,
+		GarbageCollector:        garbageCollector,
+		PreviewPublisher:        previewPublisher,
+		PreviewLister:           previewLister,
+		PreviewDeleter:          previewDeleter,
+		PreviewExpirer:          previewExpirer,
+		CanaryDeployer:          canaryDeployer,
+		CanaryPromoter:          canaryPromoter,
+		CanaryAborter:           canaryAborter,
+		StatusGetter:            statusGetter,
+		ViewerRequestApplier:    viewerRequestApplier,
+		MaintenanceSwitcher:     maintenanceSwitcher,
+		SigningKeyCreator:       signingKeyCreator,
+		SigningKeyRotator:       signingKeyRotator,
+		AccessLogAnalyzer:       accessLogAnalyzer,
+		StackLister:             stackLister,
+		PreflightChecker:        preflightChecker,
+		IAMPolicyGenerator:      iamPolicyGenerator,
+		IdentityResolver:        identityResolver,
+		ReleaseManifestRecorder: releaseManifestRecorder,
+		AuthRotator:             authRotator,
 	}
 }
@@ -16,29 +16,250 @@ import (
 // Injectors from wire.go:
 
 // NewSpare returns a new Spare struct.
-func NewSpare(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) (*Spare, error) {
-	s3BucketCreator := external.NewS3BucketCreator(profile, region, endpoint)
-	s3BucketPublicAccessBlocker := external.NewS3BucketPublicAccessBlocker(profile, region, endpoint)
-	s3BucketPolicySetter := external.NewS3BucketPolicySetter(profile, region, endpoint)
+func NewSpare(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) (*Spare, error) {
+	s3BucketCreator := external.NewS3BucketCreator(credentials, region, endpoint, storage)
+	s3BucketPublicAccessBlocker := external.NewS3BucketPublicAccessBlocker(credentials, region, endpoint, storage)
+	s3BucketPolicySetter := external.NewS3BucketPolicySetter(credentials, region, endpoint, storage)
+	s3BucketCORSSetter := external.NewS3BucketCORSSetter(credentials, region, endpoint, storage)
+	s3BucketOwnershipSetter := external.NewS3BucketOwnershipSetter(credentials, region, endpoint, storage)
+	s3BucketLifecycleSetter := external.NewS3BucketLifecycleSetter(credentials, region, endpoint, storage)
+	s3BucketLoggingSetter := external.NewS3BucketLoggingSetter(credentials, region, endpoint, storage)
+	s3BucketVersioningSetter := external.NewS3BucketVersioningSetter(credentials, region, endpoint, storage)
+	s3BucketEncryptionSetter := external.NewS3BucketEncryptionSetter(credentials, region, endpoint, storage)
+	kmsEncryptionKeyCreator := external.NewKMSEncryptionKeyCreator(credentials, region, endpoint)
+	resourceGroupsResourceTagger := external.NewResourceGroupsResourceTagger(credentials, region, endpoint)
+	cloudFrontCDNARNsLister := external.NewCloudFrontCDNARNsLister(credentials, region, endpoint)
 	storageCreatorOptions := &interactor.StorageCreatorOptions{
 		BucketCreator:             s3BucketCreator,
 		BucketPublicAccessBlocker: s3BucketPublicAccessBlocker,
 		BucketPolicySetter:        s3BucketPolicySetter,
+		BucketCORSSetter:          s3BucketCORSSetter,
+		BucketOwnershipSetter:     s3BucketOwnershipSetter,
+		BucketLifecycleSetter:     s3BucketLifecycleSetter,
+		BucketLoggingSetter:       s3BucketLoggingSetter,
+		BucketVersioningSetter:    s3BucketVersioningSetter,
+		BucketEncryptionSetter:    s3BucketEncryptionSetter,
+		EncryptionKeyCreator:      kmsEncryptionKeyCreator,
+		ResourceTagger:            resourceGroupsResourceTagger,
+		CDNARNsLister:             cloudFrontCDNARNsLister,
 	}
 	storageCreator := interactor.NewStorageCreator(storageCreatorOptions)
-	cloudFrontCDNCreator := external.NewCloudFrontCDNCreator(profile, region, endpoint)
-	cloudFrontOAICreator := external.NewCloudFrontOAICreator(profile, region, endpoint)
+	cloudFrontCDNCreator := external.NewCloudFrontCDNCreator(credentials, region, endpoint)
+	cdnFinder := external.NewCDNFinder(credentials, region, endpoint, storage)
+	cloudFrontCDNCacheBehaviorApplier := external.NewCloudFrontCDNCacheBehaviorApplier(credentials, region, endpoint)
+	cloudFrontCDNResponseHeadersPolicyApplier := external.NewCloudFrontCDNResponseHeadersPolicyApplier(credentials, region, endpoint)
+	wafWebACLApplier := external.NewWAFWebACLApplier(credentials, region, endpoint)
+	wafWebACLDeleter := external.NewWAFWebACLDeleter(credentials, region, endpoint)
+	cloudFrontCDNWebACLAssociator := external.NewCloudFrontCDNWebACLAssociator(credentials, region, endpoint)
+	cloudFrontCDNFunctionPublisher := external.NewCloudFrontCDNFunctionPublisher(credentials, region, endpoint)
+	cloudFrontCDNViewerFunctionAssociator := external.NewCloudFrontCDNViewerFunctionAssociator(credentials, region, endpoint)
+	s3MaintenanceGetter := external.NewS3MaintenanceGetter(credentials, region, endpoint, storage)
+	s3ViewerRulesPutter := external.NewS3ViewerRulesPutter(credentials, region, endpoint, storage)
+	viewerFunctionOptions := &interactor.ViewerFunctionOptions{
+		CDNFunctionPublisher:        cloudFrontCDNFunctionPublisher,
+		CDNViewerFunctionAssociator: cloudFrontCDNViewerFunctionAssociator,
+		MaintenanceGetter:           s3MaintenanceGetter,
+		ViewerRulesPutter:           s3ViewerRulesPutter,
+	}
+	cloudFrontCDNCustomOriginApplier := external.NewCloudFrontCDNCustomOriginApplier(credentials, region, endpoint)
+	cloudFrontKeyGroupGetter := external.NewCloudFrontKeyGroupGetter(credentials, region, endpoint)
+	cloudFrontCDNLoggingApplier := external.NewCloudFrontCDNLoggingApplier(credentials, region, endpoint)
+	cloudFrontCDNOriginAccessControlApplier := external.NewCloudFrontCDNOriginAccessControlApplier(credentials, region, endpoint)
+	kmsEncryptionKeyPolicyApplier := external.NewKMSEncryptionKeyPolicyApplier(credentials, region, endpoint)
+	cloudFrontCDNStagingSyncer := external.NewCloudFrontCDNStagingSyncer(credentials, region, endpoint)
+	originAccessOptions := &interactor.OriginAccessOptions{
+		CDNARNsLister:              cloudFrontCDNARNsLister,
+		BucketPolicySetter:         s3BucketPolicySetter,
+		EncryptionKeyPolicyApplier: kmsEncryptionKeyPolicyApplier,
+	}
 	cdnCreatorOptions := &interactor.CDNCreatorOptions{
-		CDNCreator: cloudFrontCDNCreator,
-		OAICreator: cloudFrontOAICreator,
+		CDNCreator:                      cloudFrontCDNCreator,
+		CDNFinder:                       cdnFinder,
+		CDNCacheBehaviorApplier:         cloudFrontCDNCacheBehaviorApplier,
+		CDNResponseHeadersPolicyApplier: cloudFrontCDNResponseHeadersPolicyApplier,
+		WebACLApplier:                   wafWebACLApplier,
+		WebACLDeleter:                   wafWebACLDeleter,
+		CDNWebACLAssociator:             cloudFrontCDNWebACLAssociator,
+		ViewerFunctionOptions:           viewerFunctionOptions,
+		CDNCustomOriginApplier:          cloudFrontCDNCustomOriginApplier,
+		KeyGroupGetter:                  cloudFrontKeyGroupGetter,
+		CDNLoggingApplier:               cloudFrontCDNLoggingApplier,
+		CDNOriginAccessControlApplier:   cloudFrontCDNOriginAccessControlApplier,
+		ResourceTagger:                  resourceGroupsResourceTagger,
+		CDNStagingSyncer:                cloudFrontCDNStagingSyncer,
+		OriginAccessOptions:             originAccessOptions,
 	}
 	cdnCreator := interactor.NewCDNCreator(cdnCreatorOptions)
-	s3Uploader := external.NewS3Uploader(profile, region, endpoint)
+	s3Uploader := external.NewS3Uploader(credentials, region, endpoint, storage)
 	fileUploaderOptions := &interactor.FileUploaderOptions{
 		FileUploader: s3Uploader,
 	}
 	fileUploader := interactor.NewFileUploader(fileUploaderOptions)
-	spare := newSpare(storageCreator, cdnCreator, fileUploader)
+	s3ReleaseHistoryGetter := external.NewS3ReleaseHistoryGetter(credentials, region, endpoint, storage)
+	s3ReleaseHistoryPutter := external.NewS3ReleaseHistoryPutter(credentials, region, endpoint, storage)
+	cdnOriginPathUpdater := external.NewCDNOriginPathUpdater(credentials, region, endpoint, storage)
+	cdnCacheInvalidator := external.NewCDNCacheInvalidator(credentials, region, endpoint, storage)
+	releaseSwitcherOptions := &interactor.ReleaseSwitcherOptions{
+		ReleaseHistoryGetter: s3ReleaseHistoryGetter,
+		ReleaseHistoryPutter: s3ReleaseHistoryPutter,
+		CDNFinder:            cdnFinder,
+		CDNOriginPathUpdater: cdnOriginPathUpdater,
+		CDNCacheInvalidator:  cdnCacheInvalidator,
+	}
+	releasePublisher := interactor.NewReleasePublisher(releaseSwitcherOptions)
+	releaseListerOptions := &interactor.ReleaseListerOptions{
+		ReleaseHistoryGetter: s3ReleaseHistoryGetter,
+	}
+	releaseLister := interactor.NewReleaseLister(releaseListerOptions)
+	releaseRollbacker := interactor.NewReleaseRollbacker(releaseSwitcherOptions)
+	s3BucketObjectLister := external.NewS3BucketObjectLister(credentials, region, endpoint, storage)
+	s3BucketObjectDeleter := external.NewS3BucketObjectDeleter(credentials, region, endpoint, storage)
+	s3BucketObjectGetter := external.NewS3BucketObjectGetter(credentials, region, endpoint, storage)
+	garbageCollectorOptions := &interactor.GarbageCollectorOptions{
+		ReleaseHistoryGetter: s3ReleaseHistoryGetter,
+		ReleaseHistoryPutter: s3ReleaseHistoryPutter,
+		BucketObjectLister:   s3BucketObjectLister,
+		BucketObjectDeleter:  s3BucketObjectDeleter,
+		BucketObjectGetter:   s3BucketObjectGetter,
+	}
+	garbageCollector := interactor.NewGarbageCollector(garbageCollectorOptions)
+	cloudFrontCDNPreviewRouteCreator := external.NewCloudFrontCDNPreviewRouteCreator(credentials, region, endpoint)
+	s3PreviewListGetter := external.NewS3PreviewListGetter(credentials, region, endpoint, storage)
+	s3PreviewListPutter := external.NewS3PreviewListPutter(credentials, region, endpoint, storage)
+	previewPublisherOptions := &interactor.PreviewPublisherOptions{
+		CDNFinder:              cdnFinder,
+		CDNFunctionPublisher:   cloudFrontCDNFunctionPublisher,
+		CDNPreviewRouteCreator: cloudFrontCDNPreviewRouteCreator,
+		CDNCacheInvalidator:    cdnCacheInvalidator,
+		BucketObjectLister:     s3BucketObjectLister,
+		BucketObjectDeleter:    s3BucketObjectDeleter,
+		PreviewListGetter:      s3PreviewListGetter,
+		PreviewListPutter:      s3PreviewListPutter,
+	}
+	previewPublisher := interactor.NewPreviewPublisher(previewPublisherOptions)
+	previewListerOptions := &interactor.PreviewListerOptions{
+		PreviewListGetter: s3PreviewListGetter,
+		CDNFinder:         cdnFinder,
+	}
+	previewLister := interactor.NewPreviewLister(previewListerOptions)
+	previewRemoverOptions := &interactor.PreviewRemoverOptions{
+		PreviewListGetter:   s3PreviewListGetter,
+		PreviewListPutter:   s3PreviewListPutter,
+		BucketObjectLister:  s3BucketObjectLister,
+		BucketObjectDeleter: s3BucketObjectDeleter,
+	}
+	previewDeleter := interactor.NewPreviewDeleter(previewRemoverOptions)
+	previewExpirer := interactor.NewPreviewExpirer(previewRemoverOptions)
+	cloudFrontCDNStagingCreator := external.NewCloudFrontCDNStagingCreator(credentials, region, endpoint)
+	cloudFrontCDNContinuousDeploymentPolicySetter := external.NewCloudFrontCDNContinuousDeploymentPolicySetter(credentials, region, endpoint)
+	canaryDeployerOptions := &interactor.CanaryDeployerOptions{
+		ReleaseHistoryGetter:                s3ReleaseHistoryGetter,
+		ReleaseHistoryPutter:                s3ReleaseHistoryPutter,
+		CDNFinder:                           cdnFinder,
+		CDNStagingCreator:                   cloudFrontCDNStagingCreator,
+		CDNOriginPathUpdater:                cdnOriginPathUpdater,
+		CDNCacheInvalidator:                 cdnCacheInvalidator,
+		CDNContinuousDeploymentPolicySetter: cloudFrontCDNContinuousDeploymentPolicySetter,
+		OriginAccessOptions:                 originAccessOptions,
+	}
+	canaryDeployer := interactor.NewCanaryDeployer(canaryDeployerOptions)
+	cloudFrontCDNContinuousDeploymentPolicyDisabler := external.NewCloudFrontCDNContinuousDeploymentPolicyDisabler(credentials, region, endpoint)
+	cloudFrontCDNStagingPromoter := external.NewCloudFrontCDNStagingPromoter(credentials, region, endpoint)
+	canaryPromoterOptions := &interactor.CanaryPromoterOptions{
+		ReleaseHistoryGetter:                  s3ReleaseHistoryGetter,
+		ReleaseHistoryPutter:                  s3ReleaseHistoryPutter,
+		CDNFinder:                             cdnFinder,
+		CDNStagingPromoter:                    cloudFrontCDNStagingPromoter,
+		CDNCacheInvalidator:                   cdnCacheInvalidator,
+		CDNContinuousDeploymentPolicyDisabler: cloudFrontCDNContinuousDeploymentPolicyDisabler,
+	}
+	canaryPromoter := interactor.NewCanaryPromoter(canaryPromoterOptions)
+	canaryAborterOptions := &interactor.CanaryAborterOptions{
+		ReleaseHistoryGetter:                  s3ReleaseHistoryGetter,
+		ReleaseHistoryPutter:                  s3ReleaseHistoryPutter,
+		CDNFinder:                             cdnFinder,
+		CDNContinuousDeploymentPolicyDisabler: cloudFrontCDNContinuousDeploymentPolicyDisabler,
+	}
+	canaryAborter := interactor.NewCanaryAborter(canaryAborterOptions)
+	cdnResponseHeadersGetter := external.NewCDNResponseHeadersGetter(credentials, region, endpoint, storage)
+	statusGetterOptions := &interactor.StatusGetterOptions{
+		CDNFinder:                cdnFinder,
+		ReleaseHistoryGetter:     s3ReleaseHistoryGetter,
+		CDNResponseHeadersGetter: cdnResponseHeadersGetter,
+		MaintenanceGetter:        s3MaintenanceGetter,
+	}
+	statusGetter := interactor.NewStatusGetter(statusGetterOptions)
+	viewerRequestApplierOptions := &interactor.ViewerRequestApplierOptions{
+		CDNFinder:             cdnFinder,
+		ViewerFunctionOptions: viewerFunctionOptions,
+	}
+	viewerRequestApplier := interactor.NewViewerRequestApplier(viewerRequestApplierOptions)
+	s3MaintenancePutter := external.NewS3MaintenancePutter(credentials, region, endpoint, storage)
+	maintenanceSwitcherOptions := &interactor.MaintenanceSwitcherOptions{
+		CDNFinder:             cdnFinder,
+		ReleaseHistoryGetter:  s3ReleaseHistoryGetter,
+		FileUploader:          s3Uploader,
+		MaintenancePutter:     s3MaintenancePutter,
+		ViewerFunctionOptions: viewerFunctionOptions,
+	}
+	maintenanceSwitcher := interactor.NewMaintenanceSwitcher(maintenanceSwitcherOptions)
+	cloudFrontKeyGroupApplier := external.NewCloudFrontKeyGroupApplier(credentials, region, endpoint)
+	cloudFrontPublicKeyCreator := external.NewCloudFrontPublicKeyCreator(credentials, region, endpoint)
+	cloudFrontPublicKeyDeleter := external.NewCloudFrontPublicKeyDeleter(credentials, region, endpoint)
+	signingKeyOptions := &interactor.SigningKeyOptions{
+		KeyGroupGetter:   cloudFrontKeyGroupGetter,
+		KeyGroupApplier:  cloudFrontKeyGroupApplier,
+		PublicKeyCreator: cloudFrontPublicKeyCreator,
+		PublicKeyDeleter: cloudFrontPublicKeyDeleter,
+	}
+	signingKeyCreator := interactor.NewSigningKeyCreator(signingKeyOptions)
+	signingKeyRotator := interactor.NewSigningKeyRotator(signingKeyOptions)
+	accessLogAnalyzerOptions := &interactor.AccessLogAnalyzerOptions{
+		BucketObjectLister: s3BucketObjectLister,
+		BucketObjectGetter: s3BucketObjectGetter,
+	}
+	accessLogAnalyzer := interactor.NewAccessLogAnalyzer(accessLogAnalyzerOptions)
+	resourceGroupsTaggedResourceLister := external.NewResourceGroupsTaggedResourceLister(credentials, region, endpoint)
+	stackListerOptions := &interactor.StackListerOptions{
+		TaggedResourceLister: resourceGroupsTaggedResourceLister,
+	}
+	stackLister := interactor.NewStackLister(stackListerOptions)
+	sharedConfigProfileFinder := external.NewSharedConfigProfileFinder()
+	stsCallerIdentityGetter := external.NewSTSCallerIdentityGetter(credentials, region, endpoint)
+	accountRegionOptInStatusGetter := external.NewAccountRegionOptInStatusGetter(credentials, region, endpoint)
+	s3BucketAvailabilityChecker := external.NewS3BucketAvailabilityChecker(credentials, region, endpoint, storage)
+	iamPolicySimulator := external.NewIAMPolicySimulator(credentials, region, endpoint)
+	preflightCheckerOptions := &interactor.PreflightCheckerOptions{
+		ProfileFinder:             sharedConfigProfileFinder,
+		CallerIdentityGetter:      stsCallerIdentityGetter,
+		RegionOptInStatusGetter:   accountRegionOptInStatusGetter,
+		BucketAvailabilityChecker: s3BucketAvailabilityChecker,
+		IAMActionsSimulator:       iamPolicySimulator,
+	}
+	preflightChecker := interactor.NewPreflightChecker(preflightCheckerOptions)
+	iamPolicyGeneratorOptions := &interactor.IAMPolicyGeneratorOptions{
+		CallerIdentityGetter: stsCallerIdentityGetter,
+		CDNFinder:            cdnFinder,
+	}
+	iamPolicyGenerator := interactor.NewIAMPolicyGenerator(iamPolicyGeneratorOptions)
+	identityResolverOptions := &interactor.IdentityResolverOptions{
+		CallerIdentityGetter: stsCallerIdentityGetter,
+		ProfileFinder:        sharedConfigProfileFinder,
+	}
+	identityResolver := interactor.NewIdentityResolver(identityResolverOptions)
+	s3ReleaseManifestPutter := external.NewS3ReleaseManifestPutter(credentials, region, endpoint, storage)
+	releaseManifestRecorderOptions := &interactor.ReleaseManifestRecorderOptions{
+		ReleaseManifestPutter: s3ReleaseManifestPutter,
+	}
+	releaseManifestRecorder := interactor.NewReleaseManifestRecorder(releaseManifestRecorderOptions)
+	s3ViewerRulesGetter := external.NewS3ViewerRulesGetter(credentials, region, endpoint, storage)
+	authRotatorOptions := &interactor.AuthRotatorOptions{
+		CDNFinder:             cdnFinder,
+		ViewerRulesGetter:     s3ViewerRulesGetter,
+		ViewerFunctionOptions: viewerFunctionOptions,
+	}
+	authRotator := interactor.NewAuthRotator(authRotatorOptions)
+	spare := newSpare(storageCreator, cdnCreator, fileUploader, releasePublisher, releaseLister, releaseRollbacker, garbageCollector, previewPublisher, previewLister, previewDeleter, previewExpirer, canaryDeployer, canaryPromoter, canaryAborter, statusGetter, viewerRequestApplier, maintenanceSwitcher, signingKeyCreator, signingKeyRotator, accessLogAnalyzer, stackLister, preflightChecker, iamPolicyGenerator, identityResolver, releaseManifestRecorder, authRotator)
 	return spare, nil
 }
 
@@ -52,6 +273,52 @@ type Spare struct {
 	CDNCreator usecase.CDNCreator
 	// FileUploader is an interface for uploading files to external storage.
 	FileUploader usecase.FileUploader
+	// ReleasePublisher is an interface for publishing the uploaded release.
+	ReleasePublisher usecase.ReleasePublisher
+	// ReleaseLister is an interface for listing the releases.
+	ReleaseLister usecase.ReleaseLister
+	// ReleaseRollbacker is an interface for switching the CDN back to the past release.
+	ReleaseRollbacker usecase.ReleaseRollbacker
+	// GarbageCollector is an interface for deleting old releases and unreferenced objects.
+	GarbageCollector usecase.GarbageCollector
+	// PreviewPublisher is an interface for publishing the uploaded preview.
+	PreviewPublisher usecase.PreviewPublisher
+	// PreviewLister is an interface for listing the previews.
+	PreviewLister usecase.PreviewLister
+	// PreviewDeleter is an interface for deleting the previews.
+	PreviewDeleter usecase.PreviewDeleter
+	// PreviewExpirer is an interface for deleting the stale previews.
+	PreviewExpirer usecase.PreviewExpirer
+	// CanaryDeployer is an interface for serving the uploaded release to a part of the viewers.
+	CanaryDeployer usecase.CanaryDeployer
+	// CanaryPromoter is an interface for making the canary release live.
+	CanaryPromoter usecase.CanaryPromoter
+	// CanaryAborter is an interface for stopping the canary release.
+	CanaryAborter usecase.CanaryAborter
+	// StatusGetter is an interface for getting the status of the SPA delivery infrastructure.
+	StatusGetter usecase.StatusGetter
+	// ViewerRequestApplier is an interface for applying the features of the viewer request function.
+	ViewerRequestApplier usecase.ViewerRequestApplier
+	// MaintenanceSwitcher is an interface for turning on or off the maintenance mode.
+	MaintenanceSwitcher usecase.MaintenanceSwitcher
+	// SigningKeyCreator is an interface for creating the signing key and the key group.
+	SigningKeyCreator usecase.SigningKeyCreator
+	// SigningKeyRotator is an interface for rotating the signing keys.
+	SigningKeyRotator usecase.SigningKeyRotator
+	// AccessLogAnalyzer is an interface for analyzing the access logs.
+	AccessLogAnalyzer usecase.AccessLogAnalyzer
+	// StackLister is an interface for listing the spare-managed stacks.
+	StackLister usecase.StackLister
+	// PreflightChecker is an interface for checking that spare can build and deploy with the credentials.
+	PreflightChecker usecase.PreflightChecker
+	// IAMPolicyGenerator is an interface for generating the least-privilege IAM policy that spare needs.
+	IAMPolicyGenerator usecase.IAMPolicyGenerator
+	// IdentityResolver is an interface for resolving the AWS account and the IAM identity of the credentials.
+	IdentityResolver usecase.IdentityResolver
+	// ReleaseManifestRecorder is an interface for recording the S3 keys of the release before uploading them.
+	ReleaseManifestRecorder usecase.ReleaseManifestRecorder
+	// AuthRotator is an interface for updating the credentials of the basic auth.
+	AuthRotator usecase.AuthRotator
 }
 
 // newSpare returns a new Spare struct.
@@ -59,10 +326,56 @@ func newSpare(
 	storageCreator usecase.StorageCreator,
 	cdncreator usecase.CDNCreator,
 	fileUploader usecase.FileUploader,
+	releasePublisher usecase.ReleasePublisher,
+	releaseLister usecase.ReleaseLister,
+	releaseRollbacker usecase.ReleaseRollbacker,
+	garbageCollector usecase.GarbageCollector,
+	previewPublisher usecase.PreviewPublisher,
+	previewLister usecase.PreviewLister,
+	previewDeleter usecase.PreviewDeleter,
+	previewExpirer usecase.PreviewExpirer,
+	canaryDeployer usecase.CanaryDeployer,
+	canaryPromoter usecase.CanaryPromoter,
+	canaryAborter usecase.CanaryAborter,
+	statusGetter usecase.StatusGetter,
+	viewerRequestApplier usecase.ViewerRequestApplier,
+	maintenanceSwitcher usecase.MaintenanceSwitcher,
+	signingKeyCreator usecase.SigningKeyCreator,
+	signingKeyRotator usecase.SigningKeyRotator,
+	accessLogAnalyzer usecase.AccessLogAnalyzer,
+	stackLister usecase.StackLister,
+	preflightChecker usecase.PreflightChecker,
+	iamPolicyGenerator usecase.IAMPolicyGenerator,
+	identityResolver usecase.IdentityResolver,
+	releaseManifestRecorder usecase.ReleaseManifestRecorder,
+	authRotator usecase.AuthRotator,
 ) *Spare {
 	return &Spare{
-		StorageCreator: storageCreator,
-		CDNCreator:     cdncreator,
-		FileUploader:   fileUploader,
+		StorageCreator:          storageCreator,
+		CDNCreator:              cdncreator,
+		FileUploader:            fileUploader,
+		ReleasePublisher:        releasePublisher,
+		ReleaseLister:           releaseLister,
+		ReleaseRollbacker:       releaseRollbacker,
+		GarbageCollector:        garbageCollector,
+		PreviewPublisher:        previewPublisher,
+		PreviewLister:           previewLister,
+		PreviewDeleter:          previewDeleter,
+		PreviewExpirer:          previewExpirer,
+		CanaryDeployer:          canaryDeployer,
+		CanaryPromoter:          canaryPromoter,
+		CanaryAborter:           canaryAborter,
+		StatusGetter:            statusGetter,
+		ViewerRequestApplier:    viewerRequestApplier,
+		MaintenanceSwitcher:     maintenanceSwitcher,
+		SigningKeyCreator:       signingKeyCreator,
+		SigningKeyRotator:       signingKeyRotator,
+		AccessLogAnalyzer:       accessLogAnalyzer,
+		StackLister:             stackLister,
+		PreflightChecker:        preflightChecker,
+		IAMPolicyGenerator:      iamPolicyGenerator,
+		IdentityResolver:        identityResolver,
+		ReleaseManifestRecorder: releaseManifestRecorder,
+		AuthRotator:             authRotator,
 	}
 }
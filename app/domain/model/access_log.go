@@ -0,0 +1,360 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// CloudFrontLogPrefix is the S3 key prefix of the CloudFront standard logs in the log bucket.
+	CloudFrontLogPrefix = "cloudfront/"
+	// S3AccessLogPrefix is the S3 key prefix of the S3 server access logs in the log bucket.
+	S3AccessLogPrefix = "s3/"
+	// logBucketSuffix is the suffix of the default log bucket name.
+	logBucketSuffix = "-logs"
+	// bucketNameMaxLen is the maximum length of the bucket name.
+	bucketNameMaxLen = 63
+)
+
+// AccessLogging is a type that represents the access logging of the CloudFront distribution and the S3 bucket.
+// Both logs are delivered to the dedicated log bucket and expire after ExpirationDays.
+type AccessLogging struct {
+	// Bucket is the name of the log bucket.
+	Bucket BucketName
+	// ExpirationDays is the number of days after which the logs are deleted.
+	ExpirationDays int
+}
+
+// NewLogBucketName returns the default name of the log bucket. e.g. my-bucket-logs
+func NewLogBucketName(bucket BucketName) BucketName {
+	name := bucket.String()
+	if maxLen := bucketNameMaxLen - len(logBucketSuffix); len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], ".-")
+	}
+	return BucketName(name + logBucketSuffix)
+}
+
+// LifecycleRules returns the lifecycle rules of the log bucket.
+func (a *AccessLogging) LifecycleRules() []LifecycleRule {
+	return []LifecycleRule{
+		{ID: "spare-expire-logs", Prefix: "", ExpirationDays: a.ExpirationDays},
+	}
+}
+
+// CloudFrontLogEntry is a request in the CloudFront standard logs.
+// https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/AccessLogs.html#LogFileFormat
+type CloudFrontLogEntry struct {
+	// Time is the time when CloudFront finished responding to the request (UTC).
+	Time time.Time
+	// Path is the path portion of the URI. e.g. /index.html
+	Path string
+	// Status is the HTTP status code. 0 means the viewer closed the connection before CloudFront responded.
+	Status int
+	// Bytes is the total number of bytes that CloudFront served to the viewer.
+	Bytes int64
+	// Referrer is the value of the Referer header. Empty means no Referer header.
+	Referrer string
+	// ResultType is how CloudFront classified the response. e.g. Hit, RefreshHit, Miss, Error
+	ResultType string
+}
+
+// Hit returns whether CloudFront served the response from the cache.
+func (e CloudFrontLogEntry) Hit() bool {
+	return e.ResultType == "Hit" || e.ResultType == "RefreshHit"
+}
+
+// Cacheable returns whether the response counts for the cache hit ratio.
+// Errors, redirects and the requests over the limit are excluded, as the CloudFront console does.
+func (e CloudFrontLogEntry) Cacheable() bool {
+	return e.Hit() || e.ResultType == "Miss"
+}
+
+// cloudFrontLogFields are the fields that spare reads from the CloudFront standard logs.
+var cloudFrontLogFields = []string{ //nolint:gochecknoglobals
+	"date", "time", "sc-bytes", "cs-uri-stem", "sc-status", "cs(Referer)", "x-edge-result-type",
+}
+
+// ParseCloudFrontLog parses the CloudFront standard log (W3C extended log format, not compressed).
+// The columns are read from the #Fields line, so the logs with the additional fields can be parsed.
+func ParseCloudFrontLog(r io.Reader) ([]CloudFrontLogEntry, error) {
+	entries := make([]CloudFrontLogEntry, 0)
+	columns := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if fields, ok := strings.CutPrefix(line, "#Fields:"); ok {
+			columns = map[string]int{}
+			for i, f := range strings.Fields(fields) {
+				columns[f] = i
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry, err := parseCloudFrontLogLine(strings.Split(line, "\t"), columns)
+		if err != nil {
+			return nil, errfmt.Wrap(ErrInvalidAccessLog, fmt.Sprintf("line %d: %s", lineNo, err.Error()))
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errfmt.Wrap(err, "failed to read the access log")
+	}
+	return entries, nil
+}
+
+// parseCloudFrontLogLine parses the fields of the CloudFront standard log.
+func parseCloudFrontLogLine(values []string, columns map[string]int) (CloudFrontLogEntry, error) {
+	field := make(map[string]string, len(cloudFrontLogFields))
+	for _, name := range cloudFrontLogFields {
+		i, ok := columns[name]
+		if !ok {
+			return CloudFrontLogEntry{}, fmt.Errorf("%s field is not defined by #Fields", name)
+		}
+		if i >= len(values) {
+			return CloudFrontLogEntry{}, fmt.Errorf("%s field is missing", name)
+		}
+		field[name] = values[i]
+	}
+
+	t, err := time.Parse("2006-01-02 15:04:05", field["date"]+" "+field["time"])
+	if err != nil {
+		return CloudFrontLogEntry{}, fmt.Errorf("invalid date and time: %w", err)
+	}
+	status, err := strconv.Atoi(field["sc-status"])
+	if err != nil && field["sc-status"] != "-" {
+		return CloudFrontLogEntry{}, fmt.Errorf("invalid status code: %s", field["sc-status"])
+	}
+	bytes, err := strconv.ParseInt(field["sc-bytes"], 10, 64)
+	if err != nil && field["sc-bytes"] != "-" {
+		return CloudFrontLogEntry{}, fmt.Errorf("invalid bytes: %s", field["sc-bytes"])
+	}
+	referrer := field["cs(Referer)"]
+	if referrer == "-" {
+		referrer = ""
+	}
+	return CloudFrontLogEntry{
+		Time:       t.UTC(),
+		Path:       field["cs-uri-stem"],
+		Status:     status,
+		Bytes:      bytes,
+		Referrer:   referrer,
+		ResultType: field["x-edge-result-type"],
+	}, nil
+}
+
+// CloudFrontLogFileHour returns the hour of the CloudFront standard log file.
+// The file name is "<DISTRIBUTION_ID>.YYYY-MM-DD-HH.<UNIQUE_ID>.gz", and the file has the requests in the hour.
+func CloudFrontLogFileHour(key string) (time.Time, bool) {
+	parts := strings.Split(path.Base(key), ".")
+	if len(parts) < 3 { //nolint:gomnd
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02-15", parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+const (
+	// spikeInterval is the interval to find the 4xx/5xx spikes.
+	spikeInterval = 5 * time.Minute
+	// spikeMinErrors is the minimum number of errors in the interval to be a spike.
+	spikeMinErrors = 10
+	// spikeRatio is how many times the error rate of the interval must be higher than the error rate of the window.
+	spikeRatio = 2.0
+)
+
+// AccessLogCount is the number of requests grouped by Name (e.g. path, status code, referrer).
+type AccessLogCount struct {
+	// Name is the value that the requests are grouped by.
+	Name string `json:"name"`
+	// Requests is the number of requests.
+	Requests int `json:"requests"`
+	// Bytes is the number of bytes served.
+	Bytes int64 `json:"bytes"`
+}
+
+// ErrorSpike is the interval where the error rate of 4xx or 5xx is much higher than the rest of the window.
+type ErrorSpike struct {
+	// Start is the start time of the interval.
+	Start time.Time `json:"start"`
+	// Class is the class of the status codes. 4xx or 5xx.
+	Class string `json:"class"`
+	// Errors is the number of errors in the interval.
+	Errors int `json:"errors"`
+	// Requests is the number of all requests in the interval.
+	Requests int `json:"requests"`
+}
+
+// AccessLogReport is the summary of the CloudFront standard logs in the time window.
+type AccessLogReport struct {
+	// Since is the start of the time window.
+	Since time.Time `json:"since"`
+	// Until is the end of the time window.
+	Until time.Time `json:"until"`
+	// Requests is the number of requests.
+	Requests int `json:"requests"`
+	// Bytes is the number of bytes served.
+	Bytes int64 `json:"bytes"`
+	// CacheHitRatio is the ratio of Hit and RefreshHit to Hit, RefreshHit and Miss. 0 if there is no cacheable request.
+	CacheHitRatio float64 `json:"cacheHitRatio"`
+	// StatusCodes is the number of requests per status code, in ascending order of the status code.
+	StatusCodes []AccessLogCount `json:"statusCodes"`
+	// TopPaths is the paths with the most requests.
+	TopPaths []AccessLogCount `json:"topPaths"`
+	// TopReferrers is the referrers with the most requests. The requests without Referer are not counted.
+	TopReferrers []AccessLogCount `json:"topReferrers"`
+	// ErrorSpikes is the 5-minute intervals where 4xx or 5xx spiked, in chronological order.
+	ErrorSpikes []ErrorSpike `json:"errorSpikes"`
+}
+
+// NewAccessLogReport returns the report of the entries in [since, until).
+// top is the number of the paths and the referrers in the report.
+func NewAccessLogReport(entries []CloudFrontLogEntry, since, until time.Time, top int) *AccessLogReport {
+	report := &AccessLogReport{
+		Since:        since,
+		Until:        until,
+		StatusCodes:  []AccessLogCount{},
+		TopPaths:     []AccessLogCount{},
+		TopReferrers: []AccessLogCount{},
+		ErrorSpikes:  []ErrorSpike{},
+	}
+
+	paths := map[string]*AccessLogCount{}
+	referrers := map[string]*AccessLogCount{}
+	statuses := map[string]*AccessLogCount{}
+	hits, cacheable := 0, 0
+	intervals := map[time.Time]*errorInterval{}
+	for _, e := range entries {
+		if e.Time.Before(since) || !e.Time.Before(until) {
+			continue
+		}
+		report.Requests++
+		report.Bytes += e.Bytes
+		countAccessLog(paths, e.Path, e.Bytes)
+		countAccessLog(statuses, strconv.Itoa(e.Status), e.Bytes)
+		if e.Referrer != "" {
+			countAccessLog(referrers, e.Referrer, e.Bytes)
+		}
+		if e.Cacheable() {
+			cacheable++
+			if e.Hit() {
+				hits++
+			}
+		}
+		start := e.Time.Truncate(spikeInterval)
+		if intervals[start] == nil {
+			intervals[start] = &errorInterval{}
+		}
+		intervals[start].add(e.Status)
+	}
+
+	if cacheable > 0 {
+		report.CacheHitRatio = float64(hits) / float64(cacheable)
+	}
+	report.StatusCodes = sortedAccessLogCounts(statuses, 0, func(a, b AccessLogCount) bool { return a.Name < b.Name })
+	report.TopPaths = sortedAccessLogCounts(paths, top, moreRequests)
+	report.TopReferrers = sortedAccessLogCounts(referrers, top, moreRequests)
+	report.ErrorSpikes = findErrorSpikes(intervals)
+	return report
+}
+
+// countAccessLog adds the request to the count of name.
+func countAccessLog(counts map[string]*AccessLogCount, name string, bytes int64) {
+	c, ok := counts[name]
+	if !ok {
+		c = &AccessLogCount{Name: name}
+		counts[name] = c
+	}
+	c.Requests++
+	c.Bytes += bytes
+}
+
+// moreRequests orders the counts by the number of requests (descending), and then by the name.
+func moreRequests(a, b AccessLogCount) bool {
+	if a.Requests != b.Requests {
+		return a.Requests > b.Requests
+	}
+	return a.Name < b.Name
+}
+
+// sortedAccessLogCounts returns the counts sorted by less. If top is greater than 0, only the first top counts are returned.
+func sortedAccessLogCounts(counts map[string]*AccessLogCount, top int, less func(a, b AccessLogCount) bool) []AccessLogCount {
+	sorted := make([]AccessLogCount, 0, len(counts))
+	for _, c := range counts {
+		sorted = append(sorted, *c)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	if top > 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+	return sorted
+}
+
+// errorInterval is the number of requests and errors in the interval.
+type errorInterval struct {
+	requests   int
+	clientErrs int
+	serverErrs int
+}
+
+// add adds the request with the status code.
+func (e *errorInterval) add(status int) {
+	e.requests++
+	switch {
+	case status >= 400 && status < 500:
+		e.clientErrs++
+	case status >= 500 && status < 600:
+		e.serverErrs++
+	}
+}
+
+// findErrorSpikes returns the intervals where the error rate is spikeRatio times higher than the error rate of the window,
+// and the number of errors is at least spikeMinErrors.
+func findErrorSpikes(intervals map[time.Time]*errorInterval) []ErrorSpike {
+	total := errorInterval{}
+	for _, i := range intervals {
+		total.requests += i.requests
+		total.clientErrs += i.clientErrs
+		total.serverErrs += i.serverErrs
+	}
+
+	spikes := make([]ErrorSpike, 0)
+	for start, i := range intervals {
+		for _, c := range []struct {
+			class       string
+			errors      int
+			totalErrors int
+		}{
+			{class: "4xx", errors: i.clientErrs, totalErrors: total.clientErrs},
+			{class: "5xx", errors: i.serverErrs, totalErrors: total.serverErrs},
+		} {
+			if c.errors < spikeMinErrors {
+				continue
+			}
+			rate := float64(c.errors) / float64(i.requests)
+			baseline := float64(c.totalErrors) / float64(total.requests)
+			if rate >= baseline*spikeRatio {
+				spikes = append(spikes, ErrorSpike{Start: start, Class: c.class, Errors: c.errors, Requests: i.requests})
+			}
+		}
+	}
+	sort.Slice(spikes, func(i, j int) bool {
+		if !spikes[i].Start.Equal(spikes[j].Start) {
+			return spikes[i].Start.Before(spikes[j].Start)
+		}
+		return spikes[i].Class < spikes[j].Class
+	})
+	return spikes
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testCloudFrontLog = `#Version: 1.0
+#Fields: date time x-edge-location sc-bytes c-ip cs-method cs(Host) cs-uri-stem sc-status cs(Referer) cs(User-Agent) cs-uri-query cs(Cookie) x-edge-result-type
+2023-10-19	12:00:01	NRT57-P2	1024	203.0.113.10	GET	d111111abcdef8.cloudfront.net	/index.html	200	https://www.google.com/	Mozilla/5.0	-	-	Hit
+2023-10-19	12:00:02	NRT57-P2	512	203.0.113.11	GET	d111111abcdef8.cloudfront.net	/app.js	200	-	Mozilla/5.0	-	-	Miss
+2023-10-19	12:06:00	NRT57-P2	0	203.0.113.12	GET	d111111abcdef8.cloudfront.net	/missing	404	-	Mozilla/5.0	-	-	Error
+`
+
+func TestParseCloudFrontLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		got, err := ParseCloudFrontLog(strings.NewReader(testCloudFrontLog))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []CloudFrontLogEntry{
+			{
+				Time: time.Date(2023, 10, 19, 12, 0, 1, 0, time.UTC), Path: "/index.html", Status: 200,
+				Bytes: 1024, Referrer: "https://www.google.com/", ResultType: "Hit",
+			},
+			{
+				Time: time.Date(2023, 10, 19, 12, 0, 2, 0, time.UTC), Path: "/app.js", Status: 200,
+				Bytes: 512, Referrer: "", ResultType: "Miss",
+			},
+			{
+				Time: time.Date(2023, 10, 19, 12, 6, 0, 0, time.UTC), Path: "/missing", Status: 404,
+				Bytes: 0, Referrer: "", ResultType: "Error",
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("failure. no #Fields line", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseCloudFrontLog(strings.NewReader("2023-10-19\t12:00:01\tNRT57-P2\t1024\n"))
+		if !errors.Is(err, ErrInvalidAccessLog) {
+			t.Errorf("ParseCloudFrontLog() error = %v, want %v", err, ErrInvalidAccessLog)
+		}
+	})
+
+	t.Run("failure. invalid status code", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseCloudFrontLog(strings.NewReader(strings.Replace(testCloudFrontLog, "\t404\t", "\tabc\t", 1)))
+		if !errors.Is(err, ErrInvalidAccessLog) {
+			t.Errorf("ParseCloudFrontLog() error = %v, want %v", err, ErrInvalidAccessLog)
+		}
+	})
+}
+
+func TestCloudFrontLogFileHour(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		key    string
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "success",
+			key:    "cloudfront/E2EXAMPLE.2023-10-19-12.a1b2c3d4.gz",
+			want:   time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "failure. not a log file",
+			key:    "cloudfront/README",
+			want:   time.Time{},
+			wantOK: false,
+		},
+		{
+			name:   "failure. invalid hour",
+			key:    "cloudfront/E2EXAMPLE.2023-10-19.a1b2c3d4.gz",
+			want:   time.Time{},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := CloudFrontLogFileHour(tt.key)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("CloudFrontLogFileHour() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewLogBucketName(t *testing.T) {
+	t.Parallel()
+
+	if got := NewLogBucketName("my-bucket"); got != "my-bucket-logs" {
+		t.Errorf("NewLogBucketName() = %s, want my-bucket-logs", got)
+	}
+	got := NewLogBucketName(BucketName(strings.Repeat("b", 57) + "-c.d"))
+	if err := got.Validate(); err != nil {
+		t.Errorf("NewLogBucketName() = %s is invalid: %v", got, err)
+	}
+}
+
+func TestNewAccessLogReport(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC)
+	entries := []CloudFrontLogEntry{
+		{Time: since.Add(-time.Second), Path: "/old", Status: 200, Bytes: 100, ResultType: "Hit"},
+		{Time: since, Path: "/index.html", Status: 200, Bytes: 100, Referrer: "https://a.example.com/", ResultType: "Hit"},
+		{Time: since.Add(time.Minute), Path: "/index.html", Status: 200, Bytes: 100, Referrer: "https://a.example.com/", ResultType: "RefreshHit"},
+		{Time: since.Add(time.Minute), Path: "/app.js", Status: 200, Bytes: 50, Referrer: "https://b.example.com/", ResultType: "Miss"},
+		{Time: since.Add(time.Minute), Path: "/old-page", Status: 301, Bytes: 0, ResultType: "Redirect"},
+	}
+	// 20 requests without errors in each 5 minutes from 12:00 to 13:00, and 12 server errors at 12:30.
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 20; j++ {
+			entries = append(entries, CloudFrontLogEntry{Time: since.Add(time.Duration(i) * spikeInterval), Path: "/ok", Status: 200, ResultType: "Error"})
+		}
+	}
+	for i := 0; i < 12; i++ {
+		entries = append(entries, CloudFrontLogEntry{Time: since.Add(30 * time.Minute), Path: fmt.Sprintf("/api/%d", i), Status: 503, ResultType: "Error"})
+	}
+
+	got := NewAccessLogReport(entries, since, since.Add(time.Hour), 2)
+	want := &AccessLogReport{
+		Since:         since,
+		Until:         since.Add(time.Hour),
+		Requests:      256,
+		Bytes:         250,
+		CacheHitRatio: 2.0 / 3.0,
+		StatusCodes: []AccessLogCount{
+			{Name: "200", Requests: 243, Bytes: 250},
+			{Name: "301", Requests: 1, Bytes: 0},
+			{Name: "503", Requests: 12, Bytes: 0},
+		},
+		TopPaths: []AccessLogCount{
+			{Name: "/ok", Requests: 240, Bytes: 0},
+			{Name: "/index.html", Requests: 2, Bytes: 200},
+		},
+		TopReferrers: []AccessLogCount{
+			{Name: "https://a.example.com/", Requests: 2, Bytes: 200},
+			{Name: "https://b.example.com/", Requests: 1, Bytes: 50},
+		},
+		ErrorSpikes: []ErrorSpike{
+			{Start: since.Add(30 * time.Minute), Class: "5xx", Errors: 12, Requests: 32},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
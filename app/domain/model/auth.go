@@ -0,0 +1,111 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// BasicAuthCredentialsEnv is the environment variable that contains the basic auth credentials.
+// The format is "USERNAME:PASSWORD[,USERNAME:PASSWORD...]".
+const BasicAuthCredentialsEnv = "SPARE_BASIC_AUTH_CREDENTIALS"
+
+// BasicAuthUser is a type that represents a user of the basic auth.
+type BasicAuthUser struct {
+	// Username is the name of the user.
+	Username string
+	// PasswordHash is the hex encoded SHA-256 hash of "USERNAME:PASSWORD".
+	// CloudFront Function compares it with the hash of the decoded Authorization header.
+	PasswordHash string
+}
+
+// NewBasicAuthUser returns a new BasicAuthUser whose password is hashed.
+func NewBasicAuthUser(username, password string) BasicAuthUser {
+	sum := sha256.Sum256([]byte(username + ":" + password))
+	return BasicAuthUser{
+		Username:     username,
+		PasswordHash: hex.EncodeToString(sum[:]),
+	}
+}
+
+var passwordHashRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates BasicAuthUser. If BasicAuthUser is invalid, it returns an error.
+func (b BasicAuthUser) Validate() error {
+	if b.Username == "" {
+		return errfmt.Wrap(ErrInvalidBasicAuth, "username is empty")
+	}
+	if strings.Contains(b.Username, ":") {
+		return errfmt.Wrap(ErrInvalidBasicAuth, fmt.Sprintf("username %s must not contain ':'", b.Username))
+	}
+	passwordHashRegexPattern.InitOnce(`^[0-9a-f]{64}$`)
+	if err := passwordHashRegexPattern.MatchString(b.PasswordHash); err != nil {
+		return errfmt.Wrap(ErrInvalidBasicAuth,
+			fmt.Sprintf("password hash of %s must be a hex encoded SHA-256 hash", b.Username))
+	}
+	return nil
+}
+
+// ParseBasicAuthCredentials parses the credentials in the BasicAuthCredentialsEnv format.
+// e.g. "alice:password1,bob:password2"
+func ParseBasicAuthCredentials(s string) ([]BasicAuthUser, error) {
+	users := []BasicAuthUser{}
+	for _, credential := range strings.Split(s, ",") {
+		if strings.TrimSpace(credential) == "" {
+			continue
+		}
+		username, password, found := strings.Cut(credential, ":")
+		if !found || username == "" || password == "" {
+			return nil, errfmt.Wrap(ErrInvalidBasicAuth,
+				fmt.Sprintf("credential must be USERNAME:PASSWORD in %s", BasicAuthCredentialsEnv))
+		}
+		users = append(users, NewBasicAuthUser(username, password))
+	}
+	return users, nil
+}
+
+// BasicAuth is a type that represents the basic auth that CloudFront Function checks.
+type BasicAuth struct {
+	// Realm is the realm that the browser shows in the login dialog.
+	Realm string
+	// Users is the list of users who can access the site.
+	Users []BasicAuthUser
+}
+
+// Validate validates BasicAuth. If BasicAuth is invalid, it returns an error.
+func (b *BasicAuth) Validate() error {
+	if b.Realm == "" {
+		return errfmt.Wrap(ErrInvalidBasicAuth, "realm is empty")
+	}
+	if strings.ContainsAny(b.Realm, "\"\\") {
+		return errfmt.Wrap(ErrInvalidBasicAuth, fmt.Sprintf("realm %s must not contain '\"' or '\\'", b.Realm))
+	}
+	if len(b.Users) == 0 {
+		return errfmt.Wrap(ErrInvalidBasicAuth,
+			fmt.Sprintf("no users. add users to .spare.yml or set %s", BasicAuthCredentialsEnv))
+	}
+	seen := make(map[string]bool, len(b.Users))
+	for _, u := range b.Users {
+		if err := u.Validate(); err != nil {
+			return err
+		}
+		if seen[u.Username] {
+			return errfmt.Wrap(ErrInvalidBasicAuth, fmt.Sprintf("user %s is duplicated", u.Username))
+		}
+		seen[u.Username] = true
+	}
+	return nil
+}
+
+// PasswordHashes returns the password hashes of the users.
+func (b *BasicAuth) PasswordHashes() []string {
+	hashes := make([]string, 0, len(b.Users))
+	for _, u := range b.Users {
+		hashes = append(hashes, u.PasswordHash)
+	}
+	return hashes
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewBasicAuthUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("password is hashed with username", func(t *testing.T) {
+		t.Parallel()
+		got := NewBasicAuthUser("alice", "secret")
+		want := BasicAuthUser{
+			Username:     "alice",
+			PasswordHash: "3d11dc479c08e3b368773103d64766c2e420ce39727932fcf2d8f4d9d599be59",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestParseBasicAuthCredentials(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []BasicAuthUser
+		wantErr bool
+	}{
+		{
+			name: "success",
+			s:    "alice:secret,bob:pass:word",
+			want: []BasicAuthUser{
+				NewBasicAuthUser("alice", "secret"),
+				NewBasicAuthUser("bob", "pass:word"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "success. empty",
+			s:       "",
+			want:    []BasicAuthUser{},
+			wantErr: false,
+		},
+		{
+			name:    "failure. password is empty",
+			s:       "alice:",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "failure. no separator",
+			s:       "alice",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseBasicAuthCredentials(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBasicAuthCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBasicAuthValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		b       *BasicAuth
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			b:       &BasicAuth{Realm: "spare", Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}},
+			wantErr: false,
+		},
+		{
+			name:    "failure. realm is empty",
+			b:       &BasicAuth{Realm: "", Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. realm contains double quote",
+			b:       &BasicAuth{Realm: `"spare"`, Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. no users",
+			b:       &BasicAuth{Realm: "spare", Users: []BasicAuthUser{}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. password hash is not SHA-256",
+			b:       &BasicAuth{Realm: "spare", Users: []BasicAuthUser{{Username: "alice", PasswordHash: "secret"}}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. username contains colon",
+			b:       &BasicAuth{Realm: "spare", Users: []BasicAuthUser{NewBasicAuthUser("ali:ce", "secret")}},
+			wantErr: true,
+		},
+		{
+			name: "failure. user is duplicated",
+			b: &BasicAuth{Realm: "spare", Users: []BasicAuthUser{
+				NewBasicAuthUser("alice", "secret"),
+				NewBasicAuthUser("alice", "password"),
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.b.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BasicAuth.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ObjectOwnership is the object ownership setting of the bucket.
+type ObjectOwnership string
+
+const (
+	// ObjectOwnershipBucketOwnerEnforced is the object ownership that disables ACLs. The bucket owner owns all objects.
+	ObjectOwnershipBucketOwnerEnforced ObjectOwnership = "BucketOwnerEnforced"
+	// ObjectOwnershipBucketOwnerPreferred is the object ownership that keeps ACLs enabled.
+	// CloudFront standard logs are delivered with ACLs, so the log bucket needs it.
+	ObjectOwnershipBucketOwnerPreferred ObjectOwnership = "BucketOwnerPreferred"
+	// ObjectOwnershipObjectWriter is the object ownership that the uploader owns the objects.
+	ObjectOwnershipObjectWriter ObjectOwnership = "ObjectWriter"
+)
+
+// String returns the string representation of the ObjectOwnership.
+func (o ObjectOwnership) String() string {
+	return string(o)
+}
+
+// Validate validates ObjectOwnership. If ObjectOwnership is invalid, it returns an error.
+func (o ObjectOwnership) Validate() error {
+	switch o {
+	case ObjectOwnershipBucketOwnerEnforced, ObjectOwnershipBucketOwnerPreferred, ObjectOwnershipObjectWriter:
+		return nil
+	default:
+		return errfmt.Wrap(ErrInvalidBucketSettings, fmt.Sprintf("unknown object ownership: %s", o))
+	}
+}
+
+// VersioningStatus is the versioning state of the bucket.
+type VersioningStatus string
+
+const (
+	// VersioningStatusUnversioned is the state of the bucket whose versioning has never been enabled.
+	VersioningStatusUnversioned VersioningStatus = ""
+	// VersioningStatusEnabled is the state of the bucket that keeps the previous versions of the objects.
+	VersioningStatusEnabled VersioningStatus = "Enabled"
+	// VersioningStatusSuspended is the state of the bucket whose versioning was enabled and then disabled.
+	// S3 can not return to the unversioned state, and the previous versions are kept.
+	VersioningStatusSuspended VersioningStatus = "Suspended"
+)
+
+// String returns the string representation of the VersioningStatus. The unversioned state is "Unversioned".
+func (v VersioningStatus) String() string {
+	if v == VersioningStatusUnversioned {
+		return "Unversioned"
+	}
+	return string(v)
+}
+
+// StorageClass is the S3 storage class that the objects are transitioned to.
+type StorageClass string
+
+const (
+	// StorageClassStandardIA is S3 Standard-Infrequent Access.
+	StorageClassStandardIA StorageClass = "STANDARD_IA"
+	// StorageClassOneZoneIA is S3 One Zone-Infrequent Access.
+	StorageClassOneZoneIA StorageClass = "ONEZONE_IA"
+	// StorageClassIntelligentTiering is S3 Intelligent-Tiering.
+	StorageClassIntelligentTiering StorageClass = "INTELLIGENT_TIERING"
+	// StorageClassGlacierIR is S3 Glacier Instant Retrieval.
+	StorageClassGlacierIR StorageClass = "GLACIER_IR"
+)
+
+// String returns the string representation of the StorageClass.
+func (s StorageClass) String() string {
+	return string(s)
+}
+
+// Validate validates StorageClass. Only the storage classes that CloudFront can read without restoring are allowed,
+// because the rollback switches CloudFront to the old release.
+func (s StorageClass) Validate() error {
+	switch s {
+	case StorageClassStandardIA, StorageClassOneZoneIA, StorageClassIntelligentTiering, StorageClassGlacierIR:
+		return nil
+	default:
+		return errfmt.Wrap(ErrInvalidBucketSettings,
+			fmt.Sprintf("storage class must be STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING or GLACIER_IR: %s", s))
+	}
+}
+
+// minTransitionDays returns the minimum days after the creation when S3 can transition the objects to the storage class.
+func (s StorageClass) minTransitionDays() int {
+	const infrequentAccessMinDays = 30
+	if s == StorageClassStandardIA || s == StorageClassOneZoneIA {
+		return infrequentAccessMinDays
+	}
+	return 1
+}
+
+// LifecycleTransition is a type that represents the transition of the objects to another storage class.
+type LifecycleTransition struct {
+	// Days is the number of days after the creation when the objects are transitioned.
+	Days int
+	// StorageClass is the storage class that the objects are transitioned to.
+	StorageClass StorageClass
+}
+
+// LifecycleRule is a type that represents the lifecycle rule of the bucket.
+type LifecycleRule struct {
+	// ID is the unique identifier of the rule.
+	ID string
+	// Prefix is the S3 key prefix of the objects that the rule applies to. Empty means all objects.
+	Prefix string
+	// ExpirationDays is the number of days after which the objects are deleted. 0 means the objects do not expire.
+	ExpirationDays int
+	// ExpiredObjectDeleteMarker is whether the delete markers without the noncurrent versions are removed.
+	// It can not be used with ExpirationDays.
+	ExpiredObjectDeleteMarker bool
+	// NoncurrentVersionExpirationDays is the number of days after which the noncurrent versions are deleted.
+	// 0 means the noncurrent versions do not expire.
+	NoncurrentVersionExpirationDays int
+	// AbortIncompleteMultipartUploadDays is the number of days after which the incomplete multipart uploads are aborted.
+	// 0 means the incomplete multipart uploads are not aborted.
+	AbortIncompleteMultipartUploadDays int
+	// Transitions is the transitions of the objects to other storage classes.
+	Transitions []LifecycleTransition
+}
+
+// BucketSettings is a type that represents the hardening of the bucket that serves the SPA.
+// Each setting is reconciled with the bucket by 'spare build'.
+type BucketSettings struct {
+	// Versioning is whether the bucket keeps the previous versions of the objects.
+	Versioning bool
+	// Ownership is the object ownership of the bucket. If it's empty, the object ownership is left as it is.
+	Ownership ObjectOwnership
+	// LifecycleRules is the lifecycle rules of the bucket. If it's empty, the lifecycle configuration is deleted.
+	LifecycleRules []LifecycleRule
+}
+
+// Validate validates BucketSettings. If BucketSettings is invalid, it returns an error.
+func (b *BucketSettings) Validate() error {
+	if b.Ownership != "" {
+		if err := b.Ownership.Validate(); err != nil {
+			return err
+		}
+	}
+	ids := map[string]bool{}
+	for _, r := range b.LifecycleRules {
+		if ids[r.ID] {
+			return errfmt.Wrap(ErrInvalidBucketSettings, fmt.Sprintf("lifecycle rule %s is duplicated", r.ID))
+		}
+		ids[r.ID] = true
+		for _, t := range r.Transitions {
+			if t.Days < 1 {
+				return errfmt.Wrap(ErrInvalidBucketSettings, fmt.Sprintf("transition days must be greater than 0: %d", t.Days))
+			}
+			if err := t.StorageClass.Validate(); err != nil {
+				return err
+			}
+			if t.StorageClass.minTransitionDays() > t.Days {
+				return errfmt.Wrap(ErrInvalidBucketSettings,
+					fmt.Sprintf("objects can be transitioned to %s after %d days or more: %d", t.StorageClass, t.StorageClass.minTransitionDays(), t.Days))
+			}
+		}
+	}
+	return nil
+}
+
+// BucketAvailability is whether the bucket name can be used by the account. Bucket names are global in the partition.
+type BucketAvailability string
+
+const (
+	// BucketAvailabilityOwned is the bucket that already exists in the account.
+	BucketAvailabilityOwned BucketAvailability = "owned"
+	// BucketAvailabilityAvailable is the bucket name that nobody uses. 'spare build' creates the bucket.
+	BucketAvailabilityAvailable BucketAvailability = "available"
+	// BucketAvailabilityTaken is the bucket that is owned by another account, or the account has no permission to access it.
+	BucketAvailabilityTaken BucketAvailability = "taken"
+	// BucketAvailabilityOtherRegion is the bucket that exists in another region.
+	BucketAvailabilityOtherRegion BucketAvailability = "other region"
+)
+
+// String returns the string representation of the BucketAvailability.
+func (b BucketAvailability) String() string {
+	return string(b)
+}
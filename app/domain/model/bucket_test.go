@@ -0,0 +1,100 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucketSettingsValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		b       *BucketSettings
+		wantErr bool
+	}{
+		{
+			name:    "success. no lifecycle rule",
+			b:       &BucketSettings{Ownership: ObjectOwnershipBucketOwnerEnforced, LifecycleRules: []LifecycleRule{}},
+			wantErr: false,
+		},
+		{
+			name: "success. all lifecycle actions",
+			b: &BucketSettings{
+				Versioning: true,
+				Ownership:  ObjectOwnershipBucketOwnerEnforced,
+				LifecycleRules: []LifecycleRule{
+					{ID: "noncurrent", NoncurrentVersionExpirationDays: 30, ExpiredObjectDeleteMarker: true},
+					{ID: "multipart", AbortIncompleteMultipartUploadDays: 7},
+					{ID: "transition", Prefix: "releases/", Transitions: []LifecycleTransition{{Days: 30, StorageClass: StorageClassStandardIA}}},
+					{ID: "tiering", Prefix: "releases/", Transitions: []LifecycleTransition{{Days: 1, StorageClass: StorageClassIntelligentTiering}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "success. object ownership is left as it is",
+			b:       &BucketSettings{Ownership: "", LifecycleRules: []LifecycleRule{}},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown object ownership",
+			b:       &BucketSettings{Ownership: "BucketOwner"},
+			wantErr: true,
+		},
+		{
+			name: "failure. duplicated rule ID",
+			b: &BucketSettings{
+				Ownership: ObjectOwnershipBucketOwnerEnforced,
+				LifecycleRules: []LifecycleRule{
+					{ID: "multipart", AbortIncompleteMultipartUploadDays: 7},
+					{ID: "multipart", AbortIncompleteMultipartUploadDays: 1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. storage class that CloudFront can not read",
+			b: &BucketSettings{
+				Ownership: ObjectOwnershipBucketOwnerEnforced,
+				LifecycleRules: []LifecycleRule{
+					{ID: "transition", Transitions: []LifecycleTransition{{Days: 90, StorageClass: "GLACIER"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. STANDARD_IA before 30 days",
+			b: &BucketSettings{
+				Ownership: ObjectOwnershipBucketOwnerEnforced,
+				LifecycleRules: []LifecycleRule{
+					{ID: "transition", Transitions: []LifecycleTransition{{Days: 7, StorageClass: StorageClassStandardIA}}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.b.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BucketSettings.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidBucketSettings) {
+				t.Errorf("BucketSettings.Validate() error = %v, want %v", err, ErrInvalidBucketSettings)
+			}
+		})
+	}
+}
+
+func TestVersioningStatusString(t *testing.T) {
+	t.Parallel()
+
+	if got := VersioningStatusUnversioned.String(); got != "Unversioned" {
+		t.Errorf("VersioningStatus.String() = %s, want Unversioned", got)
+	}
+	if got := VersioningStatusSuspended.String(); got != "Suspended" {
+		t.Errorf("VersioningStatus.String() = %s, want Suspended", got)
+	}
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// managedCachePolicyIDs is the list of CloudFront managed cache policies.
+// https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/using-managed-cache-policies.html
+var managedCachePolicyIDs = map[string]string{ //nolint:gochecknoglobals
+	"CachingOptimized":                       "658327ea-f89d-4fab-a63d-7e88639e58f6",
+	"CachingOptimizedForUncompressedObjects": "b2884449-e4de-46a7-ac36-70bc7f1ddd6d",
+	"CachingDisabled":                        "4135ea2d-6df8-44a3-9df3-4b5a84be39ad",
+	"Amplify":                                "2e54312d-136d-493c-8eb9-b001f22f67d2",
+}
+
+// managedOriginRequestPolicyIDs is the list of CloudFront managed origin request policies.
+// https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/using-managed-origin-request-policies.html
+var managedOriginRequestPolicyIDs = map[string]string{ //nolint:gochecknoglobals
+	"AllViewer":                             "216adef6-5c7f-47e4-b989-5492eafa07d3",
+	"AllViewerExceptHostHeader":             "b689b0a8-53d0-40ab-baf2-68738e2966ac",
+	"AllViewerAndCloudFrontHeaders-2022-06": "33f36d7e-f396-46d9-90e0-52428a34d9dc",
+	"CORS-S3Origin":                         "88a5eaf4-2fd4-4709-b370-b4c650ea3fcf",
+	"CORS-CustomOrigin":                     "59781a5b-3903-41f3-afcb-af62929ccde1",
+	"UserAgentRefererHeaders":               "acba4595-bd28-49b8-b9fe-13317c0390fa",
+}
+
+const (
+	// cacheKeyMaxItems is the maximum number of query strings (or headers) in the cache key.
+	cacheKeyMaxItems = 10
+	// pathPatternMaxLen is the maximum length of the path pattern of the cache behavior.
+	pathPatternMaxLen = 255
+	// cachePolicyNameMaxLen is the maximum length of the cache policy name.
+	cachePolicyNameMaxLen = 128
+	// AllQueryStrings is the query string allowlist that includes all query strings in the cache key.
+	AllQueryStrings = "*"
+)
+
+// CacheBehavior is a type that represents how the CDN caches the responses for the path pattern.
+type CacheBehavior struct {
+	// PathPattern is the path pattern of the cache behavior. e.g. /assets/*
+	// It's empty for the default cache behavior.
+	PathPattern string
+	// CachePolicy is the name of the managed cache policy. e.g. CachingOptimized
+	// If it's empty, spare creates the custom cache policy from TTLs, QueryStrings and Headers.
+	CachePolicy string
+	// OriginRequestPolicy is the name of the managed origin request policy. e.g. CORS-S3Origin
+	// If it's empty, the CDN forwards only the values in the cache key to the origin.
+	OriginRequestPolicy string
+	// MinTTL is the minimum time in seconds that objects stay in the CDN cache.
+	MinTTL int64
+	// DefaultTTL is the time in seconds that objects stay in the CDN cache if the origin does not send Cache-Control.
+	DefaultTTL int64
+	// MaxTTL is the maximum time in seconds that objects stay in the CDN cache.
+	MaxTTL int64
+	// Compress is whether the CDN compresses the responses with gzip or brotli.
+	Compress bool
+	// QueryStrings is the allowlist of query strings in the cache key. AllQueryStrings means all query strings.
+	QueryStrings []string
+	// Headers is the allowlist of headers in the cache key.
+	Headers []string
+	// Signed is whether the viewers need the signed URL or the signed cookies created with the key group of spare.
+	Signed bool
+}
+
+// IsDefault returns true if the cache behavior is the default cache behavior.
+func (c CacheBehavior) IsDefault() bool {
+	return c.PathPattern == ""
+}
+
+// IsManaged returns true if the cache behavior uses the managed cache policy.
+func (c CacheBehavior) IsManaged() bool {
+	return c.CachePolicy != ""
+}
+
+// CachingDisabled returns true if the custom cache policy does not cache anything.
+// CloudFront does not allow the cache key settings in such a policy.
+func (c CacheBehavior) CachingDisabled() bool {
+	return c.MinTTL == 0 && c.DefaultTTL == 0 && c.MaxTTL == 0
+}
+
+// AllQueryStrings returns true if all query strings are in the cache key.
+func (c CacheBehavior) AllQueryStrings() bool {
+	return len(c.QueryStrings) == 1 && c.QueryStrings[0] == AllQueryStrings
+}
+
+// ManagedCachePolicyID returns the ID of the managed cache policy.
+// If the cache behavior does not use the managed cache policy, it returns false.
+func (c CacheBehavior) ManagedCachePolicyID() (string, bool) {
+	id, ok := managedCachePolicyIDs[c.CachePolicy]
+	return id, ok
+}
+
+// OriginRequestPolicyID returns the ID of the managed origin request policy.
+// If the cache behavior does not use the origin request policy, it returns empty string.
+func (c CacheBehavior) OriginRequestPolicyID() string {
+	return managedOriginRequestPolicyIDs[c.OriginRequestPolicy]
+}
+
+// CachePolicyName returns the name of the custom cache policy for the bucket.
+// e.g. spare-my-bucket-default, spare-my-bucket-1a2b3c4d
+func (c CacheBehavior) CachePolicyName(bucket BucketName) string {
+	suffix := "default"
+	if !c.IsDefault() {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(c.PathPattern))
+		suffix = fmt.Sprintf("%08x", h.Sum32())
+	}
+	prefix := strings.ReplaceAll(fmt.Sprintf("spare-%s", bucket), ".", "-")
+	if maxLen := cachePolicyNameMaxLen - len(suffix) - 1; len(prefix) > maxLen {
+		prefix = prefix[:maxLen]
+	}
+	return prefix + "-" + suffix
+}
+
+// Validate validates CacheBehavior. If CacheBehavior is invalid, it returns an error.
+func (c CacheBehavior) Validate() error {
+	name := c.PathPattern
+	if c.IsDefault() {
+		name = "default"
+	}
+
+	if !c.IsDefault() {
+		if strings.TrimSpace(c.PathPattern) != c.PathPattern || strings.ContainsAny(c.PathPattern, " \t") {
+			return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("path pattern %q must not contain spaces", c.PathPattern))
+		}
+		if c.PathPattern == "*" || c.PathPattern == "/*" {
+			return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("path pattern %s is the default cache behavior", c.PathPattern))
+		}
+		if len(c.PathPattern) > pathPatternMaxLen {
+			return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("path pattern %s is longer than %d characters", c.PathPattern, pathPatternMaxLen))
+		}
+	}
+
+	if c.OriginRequestPolicy != "" && c.OriginRequestPolicyID() == "" {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: unknown origin request policy %s", name, c.OriginRequestPolicy))
+	}
+	if c.IsManaged() {
+		if _, ok := c.ManagedCachePolicyID(); !ok {
+			return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: unknown managed cache policy %s", name, c.CachePolicy))
+		}
+		return nil
+	}
+
+	if c.MinTTL < 0 || c.DefaultTTL < 0 || c.MaxTTL < 0 {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: TTL must not be negative", name))
+	}
+	if c.MinTTL > c.DefaultTTL || c.DefaultTTL > c.MaxTTL {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: TTL must be minTTL <= defaultTTL <= maxTTL", name))
+	}
+	if len(c.QueryStrings) > cacheKeyMaxItems || len(c.Headers) > cacheKeyMaxItems {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: cache key can contain at most %d query strings and %d headers", name, cacheKeyMaxItems, cacheKeyMaxItems))
+	}
+	for _, q := range c.QueryStrings {
+		if q == AllQueryStrings && !c.AllQueryStrings() {
+			return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: %s must be the only query string", name, AllQueryStrings))
+		}
+	}
+	if c.CachingDisabled() && (len(c.QueryStrings) != 0 || len(c.Headers) != 0) {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("%s: query strings and headers can not be in the cache key when TTLs are 0", name))
+	}
+	return nil
+}
+
+// CacheSettings is a type that represents the cache behaviors of the CDN.
+type CacheSettings struct {
+	// Default is the default cache behavior.
+	Default CacheBehavior
+	// Behaviors is the list of cache behaviors for the path patterns. The first match is used.
+	Behaviors []CacheBehavior
+}
+
+// NewCacheSettings returns the cache settings that spare used before the cache settings became configurable.
+func NewCacheSettings() *CacheSettings {
+	const defaultTTL = 300
+	return &CacheSettings{
+		Default: CacheBehavior{
+			MinTTL:       defaultTTL,
+			DefaultTTL:   defaultTTL,
+			MaxTTL:       defaultTTL,
+			Compress:     true,
+			QueryStrings: []string{AllQueryStrings},
+			Headers:      []string{},
+		},
+		Behaviors: []CacheBehavior{},
+	}
+}
+
+// All returns the default cache behavior and the cache behaviors for the path patterns.
+func (c *CacheSettings) All() []CacheBehavior {
+	return append([]CacheBehavior{c.Default}, c.Behaviors...)
+}
+
+// Signed returns true if any cache behavior requires the signed URLs or the signed cookies.
+func (c *CacheSettings) Signed() bool {
+	for _, b := range c.All() {
+		if b.Signed {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates CacheSettings. If CacheSettings is invalid, it returns an error.
+func (c *CacheSettings) Validate() error {
+	if !c.Default.IsDefault() {
+		return errfmt.Wrap(ErrInvalidCacheBehavior, "default cache behavior must not have path pattern")
+	}
+	err := c.Default.Validate()
+	seen := make(map[string]bool, len(c.Behaviors))
+	for _, b := range c.Behaviors {
+		if b.IsDefault() {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidCacheBehavior, "path pattern is empty"))
+			continue
+		}
+		if seen[b.PathPattern] {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("path pattern %s is duplicated", b.PathPattern)))
+		}
+		seen[b.PathPattern] = true
+		if strings.HasPrefix(strings.TrimPrefix(b.PathPattern, "/"), PreviewsRootPrefix) {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidCacheBehavior, fmt.Sprintf("path pattern %s is reserved for previews", b.PathPattern)))
+		}
+		if e := b.Validate(); e != nil {
+			err = errors.Join(err, e)
+		}
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCacheBehaviorValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		c       CacheBehavior
+		wantErr bool
+	}{
+		{
+			name:    "success. custom cache policy",
+			c:       CacheBehavior{PathPattern: "/assets/*", MinTTL: 0, DefaultTTL: 86400, MaxTTL: 31536000, QueryStrings: []string{"v"}},
+			wantErr: false,
+		},
+		{
+			name:    "success. no cache",
+			c:       CacheBehavior{PathPattern: "/index.html"},
+			wantErr: false,
+		},
+		{
+			name:    "success. managed cache policy",
+			c:       CacheBehavior{PathPattern: "/assets/*", CachePolicy: "CachingOptimized", OriginRequestPolicy: "CORS-S3Origin"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown managed cache policy",
+			c:       CacheBehavior{PathPattern: "/assets/*", CachePolicy: "CachingForever"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. unknown origin request policy",
+			c:       CacheBehavior{PathPattern: "/assets/*", OriginRequestPolicy: "AllCookies"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. path pattern is the default cache behavior",
+			c:       CacheBehavior{PathPattern: "*"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. path pattern contains space",
+			c:       CacheBehavior{PathPattern: "/assets /*"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. TTL is negative",
+			c:       CacheBehavior{MinTTL: -1, DefaultTTL: 0, MaxTTL: 0},
+			wantErr: true,
+		},
+		{
+			name:    "failure. defaultTTL is greater than maxTTL",
+			c:       CacheBehavior{MinTTL: 0, DefaultTTL: 600, MaxTTL: 300},
+			wantErr: true,
+		},
+		{
+			name:    "failure. all query strings and other query string",
+			c:       CacheBehavior{MaxTTL: 300, QueryStrings: []string{"*", "v"}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. cache key settings when caching is disabled",
+			c:       CacheBehavior{Headers: []string{"Origin"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CacheBehavior.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCacheBehavior) {
+				t.Errorf("CacheBehavior.Validate() error = %v, want %v", err, ErrInvalidCacheBehavior)
+			}
+		})
+	}
+}
+
+func TestCacheSettingsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		c       *CacheSettings
+		wantErr bool
+	}{
+		{
+			name:    "success. default settings",
+			c:       NewCacheSettings(),
+			wantErr: false,
+		},
+		{
+			name: "success. path patterns",
+			c: &CacheSettings{
+				Default: NewCacheSettings().Default,
+				Behaviors: []CacheBehavior{
+					{PathPattern: "/assets/*", CachePolicy: "CachingOptimized"},
+					{PathPattern: "/index.html"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failure. path pattern is duplicated",
+			c: &CacheSettings{
+				Default: NewCacheSettings().Default,
+				Behaviors: []CacheBehavior{
+					{PathPattern: "/assets/*"},
+					{PathPattern: "/assets/*"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. path pattern is empty",
+			c: &CacheSettings{
+				Default:   NewCacheSettings().Default,
+				Behaviors: []CacheBehavior{{CachePolicy: "CachingOptimized"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. path pattern is reserved for previews",
+			c: &CacheSettings{
+				Default:   NewCacheSettings().Default,
+				Behaviors: []CacheBehavior{{PathPattern: "/previews/*"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CacheSettings.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCacheBehaviorCachePolicyName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default cache behavior", func(t *testing.T) {
+		t.Parallel()
+		got := CacheBehavior{}.CachePolicyName("my.bucket")
+		if got != "spare-my-bucket-default" {
+			t.Errorf("CacheBehavior.CachePolicyName() = %s, want spare-my-bucket-default", got)
+		}
+	})
+
+	t.Run("path patterns have different names", func(t *testing.T) {
+		t.Parallel()
+		a := CacheBehavior{PathPattern: "/a/*"}.CachePolicyName("bucket")
+		b := CacheBehavior{PathPattern: "/a*"}.CachePolicyName("bucket")
+		if a == b {
+			t.Errorf("CacheBehavior.CachePolicyName() = %s for both path patterns", a)
+		}
+	})
+
+	t.Run("name is truncated", func(t *testing.T) {
+		t.Parallel()
+		got := CacheBehavior{PathPattern: "/a/*"}.CachePolicyName(BucketName(strings.Repeat("b", 200)))
+		if len(got) != cachePolicyNameMaxLen {
+			t.Errorf("len(CacheBehavior.CachePolicyName()) = %d, want %d", len(got), cachePolicyNameMaxLen)
+		}
+	})
+}
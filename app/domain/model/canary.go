@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// canaryMaxWeight is the maximum percentage of the traffic that CloudFront sends to the staging distribution.
+	canaryMaxWeight = 15
+	// canaryHeaderPrefix is the prefix that CloudFront requires for the header of header-based routing.
+	canaryHeaderPrefix = "aws-cf-cd-"
+)
+
+// CanaryTraffic is a type that represents how the CDN routes the viewers to the canary (staged) release.
+// Either Weight or Header must be set.
+type CanaryTraffic struct {
+	// Weight is the percentage of the traffic sent to the canary release. e.g. 5
+	Weight float64
+	// Header is the request header that routes the viewer to the canary release. e.g. aws-cf-cd-canary
+	Header string
+	// HeaderValue is the value of Header. e.g. true
+	HeaderValue string
+}
+
+// NewWeightCanaryTraffic returns CanaryTraffic that sends the percentage of the traffic to the canary release.
+func NewWeightCanaryTraffic(percentage float64) CanaryTraffic {
+	return CanaryTraffic{Weight: percentage}
+}
+
+// ParseHeaderCanaryTraffic parses "NAME=VALUE" and returns CanaryTraffic that routes
+// the viewers who send the header to the canary release. e.g. "aws-cf-cd-canary=true"
+func ParseHeaderCanaryTraffic(s string) (CanaryTraffic, error) {
+	name, value, found := strings.Cut(s, "=")
+	if !found {
+		return CanaryTraffic{}, errfmt.Wrap(ErrInvalidCanaryTraffic, fmt.Sprintf("header must be NAME=VALUE: %s", s))
+	}
+	return CanaryTraffic{
+		Header:      strings.ToLower(strings.TrimSpace(name)),
+		HeaderValue: strings.TrimSpace(value),
+	}, nil
+}
+
+// IsHeader returns true if the viewers are routed by the header.
+func (c CanaryTraffic) IsHeader() bool {
+	return c.Header != ""
+}
+
+// Ratio returns Weight as a ratio. e.g. 5 -> 0.05
+func (c CanaryTraffic) Ratio() float64 {
+	const percent = 100
+	return c.Weight / percent
+}
+
+// String returns the string representation of CanaryTraffic.
+func (c CanaryTraffic) String() string {
+	if c.IsHeader() {
+		return fmt.Sprintf("%s=%s", c.Header, c.HeaderValue)
+	}
+	return fmt.Sprintf("%g%%", c.Weight)
+}
+
+// Validate validates CanaryTraffic. If CanaryTraffic is invalid, it returns an error.
+// CloudFront sends at most 15% of the traffic to the staging distribution,
+// and the header for the header-based routing must start with "aws-cf-cd-".
+func (c CanaryTraffic) Validate() error {
+	switch {
+	case c.IsHeader() && c.Weight != 0:
+		return errfmt.Wrap(ErrInvalidCanaryTraffic, "specify either weight or header, not both")
+	case c.IsHeader():
+		if !strings.HasPrefix(c.Header, canaryHeaderPrefix) || len(c.Header) == len(canaryHeaderPrefix) {
+			return errfmt.Wrap(ErrInvalidCanaryTraffic, fmt.Sprintf("header %s must start with %s", c.Header, canaryHeaderPrefix))
+		}
+		if c.HeaderValue == "" {
+			return errfmt.Wrap(ErrInvalidCanaryTraffic, fmt.Sprintf("value of header %s is empty", c.Header))
+		}
+	case c.Weight <= 0 || c.Weight > canaryMaxWeight:
+		return errfmt.Wrap(ErrInvalidCanaryTraffic, fmt.Sprintf("weight must be greater than 0 and less than or equal to %d: %g", canaryMaxWeight, c.Weight))
+	}
+	return nil
+}
+
+// Stage records the release as the canary release. It does not change the live release.
+func (h *ReleaseHistory) Stage(id ReleaseID) error {
+	if _, err := h.Find(id); err != nil {
+		return err
+	}
+	h.Staged = id
+	return nil
+}
+
+// ValidateNotStaged returns ErrCanaryInProgress if there is the canary release.
+// The live release must not be changed during the canary release, because 'spare promote' copies the config of
+// the staging CDN to the primary CDN and undoes the change.
+func (h *ReleaseHistory) ValidateNotStaged() error {
+	if h.Staged.Empty() {
+		return nil
+	}
+	return errfmt.Wrap(ErrCanaryInProgress, fmt.Sprintf("canary release is %s. run 'spare promote' or 'spare abort' first", h.Staged))
+}
+
+// Abort clears the canary release, marks it as aborted and returns it.
+// 'spare rollback' never switches to the aborted release unless its ID is specified.
+func (h *ReleaseHistory) Abort() (*Release, error) {
+	release, err := h.Unstage()
+	if err != nil {
+		return nil, err
+	}
+	release.State = ReleaseStateAborted
+	return release, nil
+}
+
+// Unstage clears the canary release and returns it.
+// If there is no canary release, it returns ErrNoStagedRelease.
+func (h *ReleaseHistory) Unstage() (*Release, error) {
+	if h.Staged.Empty() {
+		return nil, ErrNoStagedRelease
+	}
+	release, err := h.Find(h.Staged)
+	if err != nil {
+		return nil, err
+	}
+	h.Staged = ""
+	return release, nil
+}
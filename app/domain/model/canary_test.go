@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCanaryTrafficValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		c       CanaryTraffic
+		wantErr bool
+	}{
+		{
+			name:    "success. weight",
+			c:       NewWeightCanaryTraffic(5),
+			wantErr: false,
+		},
+		{
+			name:    "success. max weight",
+			c:       NewWeightCanaryTraffic(15),
+			wantErr: false,
+		},
+		{
+			name:    "success. header",
+			c:       CanaryTraffic{Header: "aws-cf-cd-canary", HeaderValue: "true"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. weight is zero",
+			c:       NewWeightCanaryTraffic(0),
+			wantErr: true,
+		},
+		{
+			name:    "failure. weight is over 15%",
+			c:       NewWeightCanaryTraffic(15.5),
+			wantErr: true,
+		},
+		{
+			name:    "failure. header does not start with aws-cf-cd-",
+			c:       CanaryTraffic{Header: "x-canary", HeaderValue: "true"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. header value is empty",
+			c:       CanaryTraffic{Header: "aws-cf-cd-canary"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. both weight and header",
+			c:       CanaryTraffic{Weight: 5, Header: "aws-cf-cd-canary", HeaderValue: "true"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanaryTraffic.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCanaryTraffic) {
+				t.Errorf("CanaryTraffic.Validate() error = %v, want %v", err, ErrInvalidCanaryTraffic)
+			}
+		})
+	}
+}
+
+func TestParseHeaderCanaryTraffic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    CanaryTraffic
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			s:       "AWS-CF-CD-Canary = true",
+			want:    CanaryTraffic{Header: "aws-cf-cd-canary", HeaderValue: "true"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. no value",
+			s:       "aws-cf-cd-canary",
+			want:    CanaryTraffic{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseHeaderCanaryTraffic(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHeaderCanaryTraffic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCanaryTrafficRatio(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		if got := NewWeightCanaryTraffic(5).Ratio(); got != 0.05 {
+			t.Errorf("CanaryTraffic.Ratio() = %v, want 0.05", got)
+		}
+	})
+}
+
+func TestReleaseHistoryStageAndUnstage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, testReleaseID2)
+		if err := h.Stage(testReleaseID3); err != nil {
+			t.Fatal(err)
+		}
+		if h.Live != testReleaseID2 {
+			t.Errorf("live release = %s, want %s", h.Live, testReleaseID2)
+		}
+		if err := h.ValidateNotStaged(); !errors.Is(err, ErrCanaryInProgress) {
+			t.Errorf("ReleaseHistory.ValidateNotStaged() error = %v, want %v", err, ErrCanaryInProgress)
+		}
+
+		got, err := h.Unstage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h.ValidateNotStaged(); err != nil {
+			t.Errorf("ReleaseHistory.ValidateNotStaged() error = %v, want nil", err)
+		}
+		if got.ID != testReleaseID3 {
+			t.Errorf("ReleaseHistory.Unstage() = %s, want %s", got.ID, testReleaseID3)
+		}
+		if !h.Staged.Empty() {
+			t.Errorf("staged release = %s, want empty", h.Staged)
+		}
+	})
+
+	t.Run("failure. release does not exist", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, testReleaseID2)
+		if err := h.Stage("20991231T000000Z"); !errors.Is(err, ErrReleaseNotFound) {
+			t.Errorf("ReleaseHistory.Stage() error = %v, want %v", err, ErrReleaseNotFound)
+		}
+	})
+
+	t.Run("failure. no canary release", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, testReleaseID2)
+		if _, err := h.Unstage(); !errors.Is(err, ErrNoStagedRelease) {
+			t.Errorf("ReleaseHistory.Unstage() error = %v, want %v", err, ErrNoStagedRelease)
+		}
+	})
+}
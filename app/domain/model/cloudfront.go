@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DistributionID is the ID of the CloudFront distribution.
+type DistributionID string
+
+// String returns the string representation of DistributionID.
+func (d DistributionID) String() string {
+	return string(d)
+}
+
+// Empty is whether DistributionID is empty.
+func (d DistributionID) Empty() bool {
+	return d == ""
+}
+
+// cdnFunctionNameMaxLen is the maximum length of the CloudFront Function name.
+const cdnFunctionNameMaxLen = 64
+
+// CDNFunctionName is the name of the CloudFront Function.
+type CDNFunctionName string
+
+// NewCDNFunctionName returns the name of the CloudFront Function for the bucket.
+// The name is unique per purpose and bucket. e.g. spare-preview-my-bucket
+func NewCDNFunctionName(purpose string, bucket BucketName) CDNFunctionName {
+	name := strings.ReplaceAll(fmt.Sprintf("spare-%s-%s", purpose, bucket), ".", "-")
+	if len(name) > cdnFunctionNameMaxLen {
+		name = name[:cdnFunctionNameMaxLen]
+	}
+	return CDNFunctionName(name)
+}
+
+// String returns the string representation of CDNFunctionName.
+func (c CDNFunctionName) String() string {
+	return string(c)
+}
+
+// CDNFunction is a type that represents a CloudFront Function.
+type CDNFunction struct {
+	// Name is the name of the function.
+	Name CDNFunctionName
+	// Comment is the description of the function.
+	Comment string
+	// Code is the JavaScript code of the function.
+	Code string
+}
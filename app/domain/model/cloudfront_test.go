@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewCDNFunctionName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		purpose string
+		bucket  BucketName
+		want    CDNFunctionName
+	}{
+		{
+			name:    "success",
+			purpose: "preview",
+			bucket:  "my.bucket",
+			want:    "spare-preview-my-bucket",
+		},
+		{
+			name:    "success. name is truncated",
+			purpose: "preview",
+			bucket:  BucketName(strings.Repeat("a", 63)),
+			want:    CDNFunctionName("spare-preview-" + strings.Repeat("a", 50)),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewCDNFunctionName(tt.purpose, tt.bucket); got != tt.want {
+				t.Errorf("NewCDNFunctionName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+// CORS is a type that represents the CORS settings of the SPA.
+// It's used for both the S3 CORS rules and the CloudFront response headers policy.
+type CORS struct {
+	// AllowOrigins is the list of origins that are allowed to access the SPA. e.g. https://example.com
+	AllowOrigins []string
+	// AllowMethods is the list of HTTP methods that are allowed in the cross-origin requests.
+	AllowMethods []string
+	// AllowHeaders is the list of headers that are allowed in the cross-origin requests. "*" means all headers.
+	AllowHeaders []string
+	// MaxAge is the time in seconds that browsers cache the result of the preflight request.
+	MaxAge int64
+}
+
+// NewCORS returns the CORS settings that allow the origins to read the SPA.
+// If there are no origins, it returns nil. It means CORS is disabled.
+func NewCORS(origins AllowOrigins) *CORS {
+	const maxAge = 3600
+	allowOrigins := origins.Origins()
+	if len(allowOrigins) == 0 {
+		return nil
+	}
+	return &CORS{
+		AllowOrigins: allowOrigins,
+		AllowMethods: []string{"GET", "HEAD", "OPTIONS"},
+		AllowHeaders: []string{"*"},
+		MaxAge:       maxAge,
+	}
+}
+
+// OriginRequestPolicyID returns the ID of the managed origin request policy (CORS-S3Origin).
+// It forwards the Origin and Access-Control-Request-* headers, so that S3 can answer the preflight requests.
+func (c *CORS) OriginRequestPolicyID() string {
+	return managedOriginRequestPolicyIDs["CORS-S3Origin"]
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAllowOriginsOrigins(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		a    AllowOrigins
+		want []string
+	}{
+		{
+			name: "success. https is used if the scheme is omitted",
+			a:    AllowOrigins{exampleCom, "http://localhost:3000", "*.example.net", ""},
+			want: []string{"https://example.com", "http://localhost:3000", "https://*.example.net"},
+		},
+		{
+			name: "success. all origins",
+			a:    AllowOrigins{exampleCom, "*"},
+			want: []string{"*"},
+		},
+		{
+			name: "success. empty",
+			a:    AllowOrigins{},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, tt.a.Origins()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCORS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no origins disable CORS", func(t *testing.T) {
+		t.Parallel()
+		if got := NewCORS(AllowOrigins{}); got != nil {
+			t.Errorf("NewCORS() = %v, want nil", got)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		want := &CORS{
+			AllowOrigins: []string{"https://example.com"},
+			AllowMethods: []string{"GET", "HEAD", "OPTIONS"},
+			AllowHeaders: []string{"*"},
+			MaxAge:       3600,
+		}
+		if diff := cmp.Diff(want, NewCORS(AllowOrigins{exampleCom})); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
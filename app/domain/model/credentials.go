@@ -0,0 +1,152 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+const (
+	// MinAssumeRoleDuration is the minimum duration of the assumed role session.
+	MinAssumeRoleDuration = 15 * time.Minute
+	// MaxAssumeRoleDuration is the maximum duration of the assumed role session.
+	// The maximum session duration of the role must be longer than the duration.
+	MaxAssumeRoleDuration = 12 * time.Hour
+	// minExternalIDLen is the minimum length of the external ID.
+	minExternalIDLen = 2
+	// maxExternalIDLen is the maximum length of the external ID.
+	maxExternalIDLen = 1224
+)
+
+// AWSCredentials is how spare gets the credentials of AWS.
+type AWSCredentials struct {
+	// Profile is the AWS profile. The profile can have the static keys, a role (role_arn),
+	// an AWS IAM Identity Center (AWS SSO) session or a web identity token file.
+	Profile AWSProfile
+	// AssumeRole is the IAM role that spare assumes. If it's nil, the credentials of the profile are used.
+	AssumeRole *AssumeRole
+	// MFATokenProvider returns the token code of the MFA device. It's called when the role
+	// (AssumeRole or the role of the profile) requires MFA.
+	MFATokenProvider func() (string, error)
+}
+
+// NewAWSCredentials returns a new AWSCredentials that uses the credentials of the profile.
+func NewAWSCredentials(profile AWSProfile) *AWSCredentials {
+	return &AWSCredentials{Profile: profile}
+}
+
+// Validate validates AWSCredentials. If AWSCredentials is invalid, it returns an error.
+func (c *AWSCredentials) Validate() error {
+	if c.AssumeRole == nil {
+		return nil
+	}
+	return c.AssumeRole.Validate()
+}
+
+// AssumeRole is the IAM role that spare assumes with the credentials of the profile (sts:AssumeRole),
+// or with the web identity token (sts:AssumeRoleWithWebIdentity). The role can be in another account,
+// so each environment can deploy to its own account.
+type AssumeRole struct {
+	// RoleARN is the ARN of the role. e.g. arn:aws:iam::123456789012:role/spare-deploy
+	RoleARN string
+	// ExternalID is the external ID that the trust policy of the role requires. It's optional.
+	ExternalID string
+	// SessionName is the name of the role session. It's in CloudTrail. If it's empty, the SDK generates it.
+	SessionName string
+	// MFASerial is the ARN (or the serial number) of the MFA device that the trust policy of the role requires.
+	// If it's set, spare prompts the token code.
+	MFASerial string
+	// Duration is the duration of the role session. If it's zero, the default of the SDK (15 minutes) is used.
+	Duration time.Duration
+	// WebIdentityTokenFile is the path of the OIDC token file (e.g. the token of the CI).
+	// If it's set, the role is assumed with the token instead of the credentials of the profile.
+	WebIdentityTokenFile string
+}
+
+var (
+	roleARNRegexPattern     xregex.Regex //nolint:gochecknoglobals
+	externalIDRegexPattern  xregex.Regex //nolint:gochecknoglobals
+	sessionNameRegexPattern xregex.Regex //nolint:gochecknoglobals
+	mfaSerialRegexPattern   xregex.Regex //nolint:gochecknoglobals
+)
+
+// Validate validates AssumeRole. If AssumeRole is invalid, it returns an error.
+func (a *AssumeRole) Validate() error {
+	roleARNRegexPattern.InitOnce(`^arn:aws(-cn|-us-gov)?:iam::\d{12}:role/[\w+=,.@/-]{1,512}$`)
+	externalIDRegexPattern.InitOnce(`^[\w+=,.@:/-]+$`)
+	sessionNameRegexPattern.InitOnce(`^[\w+=,.@-]{2,64}$`)
+	mfaSerialRegexPattern.InitOnce(`^[\w+=/:,.@-]{9,256}$`)
+
+	if err := roleARNRegexPattern.MatchString(a.RoleARN); err != nil {
+		return errfmt.Wrap(ErrInvalidAssumeRole, fmt.Sprintf("role ARN must be arn:PARTITION:iam::ACCOUNT:role/NAME: %s", a.RoleARN))
+	}
+	if a.ExternalID != "" {
+		if err := externalIDRegexPattern.MatchString(a.ExternalID); err != nil || len(a.ExternalID) < minExternalIDLen || len(a.ExternalID) > maxExternalIDLen {
+			return errfmt.Wrap(ErrInvalidAssumeRole, "external ID must be 2-1224 characters of letters, digits and +=,.@:/-")
+		}
+	}
+	if a.SessionName != "" {
+		if err := sessionNameRegexPattern.MatchString(a.SessionName); err != nil {
+			return errfmt.Wrap(ErrInvalidAssumeRole, fmt.Sprintf("session name must be 2-64 characters of letters, digits and +=,.@-: %s", a.SessionName))
+		}
+	}
+	if a.MFASerial != "" {
+		if err := mfaSerialRegexPattern.MatchString(a.MFASerial); err != nil {
+			return errfmt.Wrap(ErrInvalidAssumeRole, fmt.Sprintf("MFA serial must be the ARN or the serial number of the MFA device: %s", a.MFASerial))
+		}
+	}
+	if a.Duration != 0 && (a.Duration < MinAssumeRoleDuration || a.Duration > MaxAssumeRoleDuration) {
+		return errfmt.Wrap(ErrInvalidAssumeRole, fmt.Sprintf("duration must be between %s and %s: %s", MinAssumeRoleDuration, MaxAssumeRoleDuration, a.Duration))
+	}
+	if a.WebIdentity() && (a.ExternalID != "" || a.MFASerial != "") {
+		return errfmt.Wrap(ErrInvalidAssumeRole, "external ID and MFA serial can not be used with the web identity token")
+	}
+	return nil
+}
+
+// WebIdentity returns whether the role is assumed with the web identity token.
+func (a *AssumeRole) WebIdentity() bool {
+	return a.WebIdentityTokenFile != ""
+}
+
+// Account returns the AWS account ID of the role. e.g. 123456789012
+func (a *AssumeRole) Account() string {
+	parts := strings.SplitN(a.RoleARN, ":", 6)
+	if len(parts) != 6 {
+		return ""
+	}
+	return parts[4]
+}
+
+// Partition returns the partition of the role.
+func (a *AssumeRole) Partition() Partition {
+	parts := strings.SplitN(a.RoleARN, ":", 6)
+	if len(parts) != 6 {
+		return ""
+	}
+	return Partition(parts[1])
+}
+
+// String returns the short description of the role. e.g. arn:aws:iam::123456789012:role/deploy (external ID, MFA)
+func (a *AssumeRole) String() string {
+	details := make([]string, 0)
+	if a.WebIdentity() {
+		details = append(details, "web identity "+a.WebIdentityTokenFile)
+	}
+	if a.ExternalID != "" {
+		details = append(details, "external ID")
+	}
+	if a.MFASerial != "" {
+		details = append(details, "MFA")
+	}
+	if a.SessionName != "" {
+		details = append(details, "session "+a.SessionName)
+	}
+	if len(details) == 0 {
+		return a.RoleARN
+	}
+	return fmt.Sprintf("%s (%s)", a.RoleARN, strings.Join(details, ", "))
+}
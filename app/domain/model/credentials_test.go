@@ -0,0 +1,172 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssumeRoleValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		a       *AssumeRole
+		wantErr bool
+	}{
+		{
+			name:    "success. role only",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy"},
+			wantErr: false,
+		},
+		{
+			name: "success. external ID, session name, MFA and duration",
+			a: &AssumeRole{
+				RoleARN:     "arn:aws-cn:iam::123456789012:role/path/spare-deploy",
+				ExternalID:  "spare-external-id",
+				SessionName: "spare@ci",
+				MFASerial:   "arn:aws-cn:iam::123456789012:mfa/alice",
+				Duration:    time.Hour,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "success. web identity",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", WebIdentityTokenFile: "/tmp/token"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. role ARN is empty",
+			a:       &AssumeRole{},
+			wantErr: true,
+		},
+		{
+			name:    "failure. ARN is not a role",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:user/alice"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. account is not 12 digits",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::1234:role/spare-deploy"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. external ID has a space",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", ExternalID: "spare id"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. session name is too short",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", SessionName: "s"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. duration is too short",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", Duration: time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "failure. duration is too long",
+			a:       &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", Duration: 13 * time.Hour},
+			wantErr: true,
+		},
+		{
+			name: "failure. MFA with web identity",
+			a: &AssumeRole{
+				RoleARN:              "arn:aws:iam::123456789012:role/spare-deploy",
+				MFASerial:            "arn:aws:iam::123456789012:mfa/alice",
+				WebIdentityTokenFile: "/tmp/token",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.a.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AssumeRole.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssumeRoleAccount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		a             *AssumeRole
+		wantAccount   string
+		wantPartition Partition
+	}{
+		{
+			name:          "aws",
+			a:             &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy"},
+			wantAccount:   "123456789012",
+			wantPartition: PartitionAWS,
+		},
+		{
+			name:          "aws-cn",
+			a:             &AssumeRole{RoleARN: "arn:aws-cn:iam::210987654321:role/spare-deploy"},
+			wantAccount:   "210987654321",
+			wantPartition: PartitionAWSCN,
+		},
+		{
+			name:          "not an ARN",
+			a:             &AssumeRole{RoleARN: "spare-deploy"},
+			wantAccount:   "",
+			wantPartition: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.a.Account(); got != tt.wantAccount {
+				t.Errorf("AssumeRole.Account() = %v, want %v", got, tt.wantAccount)
+			}
+			if got := tt.a.Partition(); got != tt.wantPartition {
+				t.Errorf("AssumeRole.Partition() = %v, want %v", got, tt.wantPartition)
+			}
+		})
+	}
+}
+
+func TestAssumeRoleString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    *AssumeRole
+		want string
+	}{
+		{
+			name: "role only",
+			a:    &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy"},
+			want: "arn:aws:iam::123456789012:role/spare-deploy",
+		},
+		{
+			name: "external ID and MFA",
+			a: &AssumeRole{
+				RoleARN:    "arn:aws:iam::123456789012:role/spare-deploy",
+				ExternalID: "secret",
+				MFASerial:  "arn:aws:iam::123456789012:mfa/alice",
+			},
+			want: "arn:aws:iam::123456789012:role/spare-deploy (external ID, MFA)",
+		},
+		{
+			name: "web identity",
+			a:    &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", WebIdentityTokenFile: "/tmp/token"},
+			want: "arn:aws:iam::123456789012:role/spare-deploy (web identity /tmp/token)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("AssumeRole.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// OriginProtocol is a type that represents the protocol that the CDN uses to connect to the custom origin.
+type OriginProtocol string
+
+const (
+	// OriginProtocolHTTPSOnly connects to the origin with HTTPS.
+	OriginProtocolHTTPSOnly OriginProtocol = "https-only"
+	// OriginProtocolHTTPOnly connects to the origin with HTTP.
+	OriginProtocolHTTPOnly OriginProtocol = "http-only"
+	// OriginProtocolMatchViewer connects to the origin with the same protocol as the viewer.
+	OriginProtocolMatchViewer OriginProtocol = "match-viewer"
+)
+
+// String returns the string representation of OriginProtocol.
+func (o OriginProtocol) String() string {
+	return string(o)
+}
+
+const (
+	// customOriginIDPrefix is the prefix of the ID of the custom origin generated by spare.
+	customOriginIDPrefix = "Custom Origin "
+	// customOriginIDSuffix is the suffix of the ID of the custom origin generated by spare.
+	customOriginIDSuffix = " Generated by Spare"
+	// ForwardAll is the allowlist of headers, cookies or query strings that forwards all of them to the custom origin.
+	ForwardAll = "*"
+	// originRequestPolicyNameMaxLen is the maximum length of the origin request policy name.
+	originRequestPolicyNameMaxLen = 128
+)
+
+// CustomOrigin is a type that represents the origin other than the bucket. e.g. ALB, API Gateway
+// The requests that match PathPatterns are routed to it instead of the bucket.
+type CustomOrigin struct {
+	// Name is the name of the origin. It's used in the origin ID and the origin request policy name.
+	Name string
+	// DomainName is the domain name of the origin. e.g. api.example.com
+	DomainName string
+	// OriginPath is the path that the CDN prepends to the request path. e.g. /prod
+	OriginPath string
+	// Protocol is the protocol that the CDN uses to connect to the origin.
+	Protocol OriginProtocol
+	// HTTPPort is the HTTP port of the origin.
+	HTTPPort int64
+	// HTTPSPort is the HTTPS port of the origin.
+	HTTPSPort int64
+	// PathPatterns is the list of path patterns that are routed to the origin. e.g. /api/*
+	PathPatterns []string
+	// CachePolicy is the name of the managed cache policy. e.g. CachingDisabled
+	CachePolicy string
+	// Headers is the allowlist of headers forwarded to the origin. ForwardAll means all headers except Host.
+	Headers []string
+	// Cookies is the allowlist of cookies forwarded to the origin. ForwardAll means all cookies.
+	Cookies []string
+	// QueryStrings is the allowlist of query strings forwarded to the origin. ForwardAll means all query strings.
+	QueryStrings []string
+	// ConnectionTimeout is the time in seconds that the CDN waits to connect to the origin.
+	ConnectionTimeout int64
+	// ReadTimeout is the time in seconds that the CDN waits for the response from the origin.
+	ReadTimeout int64
+	// KeepaliveTimeout is the time in seconds that the CDN keeps the connection to the origin.
+	KeepaliveTimeout int64
+}
+
+// NewCustomOrigin returns the custom origin with the default values.
+// It forwards all headers except Host, all cookies and all query strings, and the responses are not cached.
+func NewCustomOrigin(name, domainName string, pathPatterns []string) CustomOrigin {
+	const (
+		httpPort          = 80
+		httpsPort         = 443
+		connectionTimeout = 10
+		readTimeout       = 30
+		keepaliveTimeout  = 5
+	)
+	return CustomOrigin{
+		Name:              name,
+		DomainName:        domainName,
+		Protocol:          OriginProtocolHTTPSOnly,
+		HTTPPort:          httpPort,
+		HTTPSPort:         httpsPort,
+		PathPatterns:      pathPatterns,
+		CachePolicy:       "CachingDisabled",
+		Headers:           []string{ForwardAll},
+		Cookies:           []string{ForwardAll},
+		QueryStrings:      []string{ForwardAll},
+		ConnectionTimeout: connectionTimeout,
+		ReadTimeout:       readTimeout,
+		KeepaliveTimeout:  keepaliveTimeout,
+	}
+}
+
+// OriginID returns the ID of the origin in the CDN. e.g. "Custom Origin api Generated by Spare"
+func (o CustomOrigin) OriginID() string {
+	return customOriginIDPrefix + o.Name + customOriginIDSuffix
+}
+
+// IsCustomOriginID returns true if id is the ID of the custom origin generated by spare.
+func IsCustomOriginID(id string) bool {
+	return strings.HasPrefix(id, customOriginIDPrefix) && strings.HasSuffix(id, customOriginIDSuffix)
+}
+
+// ManagedCachePolicyID returns the ID of the managed cache policy.
+func (o CustomOrigin) ManagedCachePolicyID() (string, bool) {
+	id, ok := managedCachePolicyIDs[o.CachePolicy]
+	return id, ok
+}
+
+// OriginRequestPolicyName returns the name of the origin request policy for the origin.
+// e.g. spare-my-bucket-origin-api
+func (o CustomOrigin) OriginRequestPolicyName(bucket BucketName) string {
+	suffix := "origin-" + o.Name
+	prefix := strings.ReplaceAll(fmt.Sprintf("spare-%s", bucket), ".", "-")
+	if maxLen := originRequestPolicyNameMaxLen - len(suffix) - 1; len(prefix) > maxLen {
+		prefix = prefix[:maxLen]
+	}
+	return prefix + "-" + suffix
+}
+
+// ForwardAllHeaders returns true if all headers except Host are forwarded to the origin.
+func (o CustomOrigin) ForwardAllHeaders() bool {
+	return forwardAll(o.Headers)
+}
+
+// ForwardAllCookies returns true if all cookies are forwarded to the origin.
+func (o CustomOrigin) ForwardAllCookies() bool {
+	return forwardAll(o.Cookies)
+}
+
+// ForwardAllQueryStrings returns true if all query strings are forwarded to the origin.
+func (o CustomOrigin) ForwardAllQueryStrings() bool {
+	return forwardAll(o.QueryStrings)
+}
+
+// forwardAll returns true if the allowlist is ForwardAll.
+func forwardAll(list []string) bool {
+	return len(list) == 1 && list[0] == ForwardAll
+}
+
+var customOriginNameRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates CustomOrigin. If CustomOrigin is invalid, it returns an error.
+func (o CustomOrigin) Validate() error {
+	customOriginNameRegexPattern.InitOnce(`^[a-z0-9][a-z0-9-]{0,31}$`)
+	if err := customOriginNameRegexPattern.MatchString(o.Name); err != nil {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("name %q must be 1-32 lowercase letters, numbers and '-'", o.Name))
+	}
+	if o.DomainName == "" || strings.Contains(o.DomainName, "/") || strings.Contains(o.DomainName, ":") {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("%s: domain name %q must be a host name without scheme, port and path", o.Name, o.DomainName))
+	}
+	if err := Domain(o.DomainName).ValidateHost(); err != nil {
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: %s", o.Name, err.Error()))
+	}
+	if o.OriginPath != "" && (!strings.HasPrefix(o.OriginPath, "/") || strings.HasSuffix(o.OriginPath, "/")) {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("%s: origin path %s must start with '/' and must not end with '/'", o.Name, o.OriginPath))
+	}
+	switch o.Protocol {
+	case OriginProtocolHTTPSOnly, OriginProtocolHTTPOnly, OriginProtocolMatchViewer:
+	default:
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: protocol must be %s, %s or %s: %s",
+			o.Name, OriginProtocolHTTPSOnly, OriginProtocolHTTPOnly, OriginProtocolMatchViewer, o.Protocol))
+	}
+	if !validPort(o.HTTPPort) || !validPort(o.HTTPSPort) {
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: port must be between 1 and 65535", o.Name))
+	}
+	if len(o.PathPatterns) == 0 {
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: path patterns are empty", o.Name))
+	}
+	for _, p := range o.PathPatterns {
+		if err := validateCustomOriginPathPattern(p); err != nil {
+			return errfmt.Wrap(err, o.Name)
+		}
+	}
+	if _, ok := o.ManagedCachePolicyID(); !ok {
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: unknown managed cache policy %s", o.Name, o.CachePolicy))
+	}
+	if err := o.validateForwardedValues(); err != nil {
+		return err
+	}
+	return o.validateTimeouts()
+}
+
+// validPort returns true if port is a valid TCP port.
+func validPort(port int64) bool {
+	return port >= 1 && port <= 65535
+}
+
+// validateCustomOriginPathPattern validates the path pattern of the custom origin.
+func validateCustomOriginPathPattern(p string) error {
+	switch {
+	case p == "" || strings.ContainsAny(p, " \t"):
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("path pattern %q must not be empty or contain spaces", p))
+	case p == "*" || p == "/*":
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("path pattern %s is the default cache behavior", p))
+	case len(p) > pathPatternMaxLen:
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("path pattern %s is longer than %d characters", p, pathPatternMaxLen))
+	case strings.HasPrefix(strings.TrimPrefix(p, "/"), PreviewsRootPrefix):
+		return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("path pattern %s is reserved for previews", p))
+	}
+	return nil
+}
+
+// validateForwardedValues validates the headers, cookies and query strings forwarded to the origin.
+func (o CustomOrigin) validateForwardedValues() error {
+	for kind, list := range map[string][]string{"headers": o.Headers, "cookies": o.Cookies, "query strings": o.QueryStrings} {
+		if len(list) > cacheKeyMaxItems {
+			return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: at most %d %s can be forwarded", o.Name, cacheKeyMaxItems, kind))
+		}
+		if contains(list, ForwardAll) && !forwardAll(list) {
+			return errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: %s must be the only item of %s", o.Name, ForwardAll, kind))
+		}
+	}
+	for _, h := range o.Headers {
+		// CloudFront forwards Authorization only if it's in the cache key, and does not allow it in the origin request policy.
+		if http.CanonicalHeaderKey(h) == "Authorization" {
+			return errfmt.Wrap(ErrInvalidCustomOrigin,
+				fmt.Sprintf("%s: Authorization can not be forwarded by the origin request policy", o.Name))
+		}
+	}
+	return nil
+}
+
+// validateTimeouts validates the timeouts. The ranges are the default quotas of CloudFront.
+func (o CustomOrigin) validateTimeouts() error {
+	const (
+		maxConnectionTimeout = 10
+		maxReadTimeout       = 60
+		maxKeepaliveTimeout  = 60
+	)
+	if o.ConnectionTimeout < 1 || o.ConnectionTimeout > maxConnectionTimeout {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("%s: connection timeout must be between 1 and %d: %d", o.Name, maxConnectionTimeout, o.ConnectionTimeout))
+	}
+	if o.ReadTimeout < 1 || o.ReadTimeout > maxReadTimeout {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("%s: read timeout must be between 1 and %d: %d", o.Name, maxReadTimeout, o.ReadTimeout))
+	}
+	if o.KeepaliveTimeout < 1 || o.KeepaliveTimeout > maxKeepaliveTimeout {
+		return errfmt.Wrap(ErrInvalidCustomOrigin,
+			fmt.Sprintf("%s: keepalive timeout must be between 1 and %d: %d", o.Name, maxKeepaliveTimeout, o.KeepaliveTimeout))
+	}
+	return nil
+}
+
+// CustomOrigins is the list of custom origins. The cache behaviors of the custom origins are evaluated
+// before the cache behaviors of the bucket.
+type CustomOrigins []CustomOrigin
+
+// Validate validates CustomOrigins. The names and the path patterns must be unique,
+// and the path patterns must not be used by the cache behaviors of the bucket.
+func (c CustomOrigins) Validate(cache *CacheSettings) error {
+	bucketPatterns := []string{}
+	if cache != nil {
+		for _, b := range cache.Behaviors {
+			bucketPatterns = append(bucketPatterns, b.PathPattern)
+		}
+	}
+
+	var err error
+	names := make(map[string]bool, len(c))
+	patterns := make(map[string]bool)
+	for _, o := range c {
+		if e := o.Validate(); e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+		if names[o.Name] {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("name %s is duplicated", o.Name)))
+		}
+		names[o.Name] = true
+		for _, p := range o.PathPatterns {
+			if patterns[p] || contains(bucketPatterns, p) {
+				err = errors.Join(err, errfmt.Wrap(ErrInvalidCustomOrigin, fmt.Sprintf("%s: path pattern %s is duplicated", o.Name, p)))
+			}
+			patterns[p] = true
+		}
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCustomOriginValidate(t *testing.T) {
+	t.Parallel()
+
+	api := func(f func(o *CustomOrigin)) CustomOrigin {
+		o := NewCustomOrigin("api", "api.example.com", []string{"/api/*"})
+		f(&o)
+		return o
+	}
+
+	tests := []struct {
+		name    string
+		o       CustomOrigin
+		wantErr bool
+	}{
+		{
+			name:    "success. default values",
+			o:       api(func(o *CustomOrigin) {}),
+			wantErr: false,
+		},
+		{
+			name: "success. allowlists and origin path",
+			o: api(func(o *CustomOrigin) {
+				o.OriginPath = "/prod"
+				o.Headers = []string{"Accept", "X-Api-Key"}
+				o.Cookies = []string{"session"}
+				o.QueryStrings = []string{}
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "failure. name has uppercase letters",
+			o:       api(func(o *CustomOrigin) { o.Name = "API" }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. domain name has scheme",
+			o:       api(func(o *CustomOrigin) { o.DomainName = "https://api.example.com" }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. origin path ends with slash",
+			o:       api(func(o *CustomOrigin) { o.OriginPath = "/prod/" }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. unknown protocol",
+			o:       api(func(o *CustomOrigin) { o.Protocol = "tcp" }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid port",
+			o:       api(func(o *CustomOrigin) { o.HTTPSPort = 0 }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. no path pattern",
+			o:       api(func(o *CustomOrigin) { o.PathPatterns = []string{} }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. path pattern is the default cache behavior",
+			o:       api(func(o *CustomOrigin) { o.PathPatterns = []string{"/*"} }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. path pattern is reserved for previews",
+			o:       api(func(o *CustomOrigin) { o.PathPatterns = []string{"previews/api/*"} }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. unknown cache policy",
+			o:       api(func(o *CustomOrigin) { o.CachePolicy = "CachingForever" }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. all cookies and other cookie",
+			o:       api(func(o *CustomOrigin) { o.Cookies = []string{"*", "session"} }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. Authorization header",
+			o:       api(func(o *CustomOrigin) { o.Headers = []string{"authorization"} }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. read timeout is too long",
+			o:       api(func(o *CustomOrigin) { o.ReadTimeout = 61 }),
+			wantErr: true,
+		},
+		{
+			name:    "failure. connection timeout is zero",
+			o:       api(func(o *CustomOrigin) { o.ConnectionTimeout = 0 }),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.o.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CustomOrigin.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCustomOrigin) {
+				t.Errorf("CustomOrigin.Validate() error = %v, want %v", err, ErrInvalidCustomOrigin)
+			}
+		})
+	}
+}
+
+func TestCustomOriginsValidate(t *testing.T) {
+	t.Parallel()
+
+	cache := &CacheSettings{
+		Default:   NewCacheSettings().Default,
+		Behaviors: []CacheBehavior{{PathPattern: "/assets/*"}},
+	}
+	tests := []struct {
+		name    string
+		c       CustomOrigins
+		wantErr bool
+	}{
+		{
+			name:    "success. no custom origins",
+			c:       CustomOrigins{},
+			wantErr: false,
+		},
+		{
+			name: "success. several custom origins",
+			c: CustomOrigins{
+				NewCustomOrigin("api", "api.example.com", []string{"/api/*"}),
+				NewCustomOrigin("auth", "auth.example.com", []string{"/auth/*", "/oauth/*"}),
+			},
+			wantErr: false,
+		},
+		{
+			name: "failure. name is duplicated",
+			c: CustomOrigins{
+				NewCustomOrigin("api", "api.example.com", []string{"/api/*"}),
+				NewCustomOrigin("api", "api.example.com", []string{"/v2/*"}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. path pattern is duplicated",
+			c: CustomOrigins{
+				NewCustomOrigin("api", "api.example.com", []string{"/api/*"}),
+				NewCustomOrigin("v2", "v2.example.com", []string{"/api/*"}),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "failure. path pattern is used by the cache behavior",
+			c:       CustomOrigins{NewCustomOrigin("api", "api.example.com", []string{"/assets/*"})},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.c.Validate(cache); (err != nil) != tt.wantErr {
+				t.Errorf("CustomOrigins.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomOriginOriginID(t *testing.T) {
+	t.Parallel()
+
+	id := NewCustomOrigin("api", "api.example.com", []string{"/api/*"}).OriginID()
+	if id != "Custom Origin api Generated by Spare" {
+		t.Errorf("CustomOrigin.OriginID() = %s, want Custom Origin api Generated by Spare", id)
+	}
+	if !IsCustomOriginID(id) {
+		t.Errorf("IsCustomOriginID(%s) = false, want true", id)
+	}
+	if IsCustomOriginID("S3 Origin ID Generated by Spare") {
+		t.Errorf("IsCustomOriginID() = true for the S3 origin, want false")
+	}
+}
+
+func TestCustomOriginOriginRequestPolicyName(t *testing.T) {
+	t.Parallel()
+
+	o := NewCustomOrigin("api", "api.example.com", []string{"/api/*"})
+	if got := o.OriginRequestPolicyName("my.bucket"); got != "spare-my-bucket-origin-api" {
+		t.Errorf("CustomOrigin.OriginRequestPolicyName() = %s, want spare-my-bucket-origin-api", got)
+	}
+	if got := o.OriginRequestPolicyName(BucketName(strings.Repeat("b", 200))); len(got) != originRequestPolicyNameMaxLen {
+		t.Errorf("len(CustomOrigin.OriginRequestPolicyName()) = %d, want %d", len(got), originRequestPolicyNameMaxLen)
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// CheckStatus is the result of a preflight check.
+type CheckStatus string
+
+const (
+	// CheckStatusPass is the status of the check that passed.
+	CheckStatusPass CheckStatus = "PASS"
+	// CheckStatusFail is the status of the check that failed. spare build and spare deploy stop if a check fails.
+	CheckStatusFail CheckStatus = "FAIL"
+	// CheckStatusWarn is the status of the check that could not be confirmed (e.g. spare has no permission to check it).
+	CheckStatusWarn CheckStatus = "WARN"
+	// CheckStatusSkip is the status of the check that was not run, because the check it depends on failed.
+	CheckStatusSkip CheckStatus = "SKIP"
+)
+
+// String returns the string representation of the CheckStatus.
+func (s CheckStatus) String() string {
+	return string(s)
+}
+
+// Check is a result of a preflight check that 'spare doctor' runs.
+type Check struct {
+	// Name is the name of the check. e.g. credentials
+	Name string `json:"name"`
+	// Status is the result of the check.
+	Status CheckStatus `json:"status"`
+	// Detail is what the check found.
+	Detail string `json:"detail"`
+	// Hint is how to fix the problem. It's empty if the check passed.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Checklist is the results of the preflight checks in the order they were run.
+type Checklist []Check
+
+// Failed returns the checks that failed.
+func (c Checklist) Failed() Checklist {
+	failed := Checklist{}
+	for _, check := range c {
+		if check.Status == CheckStatusFail {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// Err returns an error that lists the names of the failed checks. If no check failed, it returns nil.
+func (c Checklist) Err() error {
+	failed := c.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(failed))
+	for _, check := range failed {
+		names = append(names, check.Name)
+	}
+	return errfmt.Wrap(ErrPreflightCheck, fmt.Sprintf("%s (run 'spare doctor' to see the hints)", strings.Join(names, ", ")))
+}
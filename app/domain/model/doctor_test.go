@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChecklistErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		c          Checklist
+		wantFailed int
+		wantErr    bool
+	}{
+		{
+			name: "all checks passed or warned",
+			c: Checklist{
+				{Name: "credentials", Status: CheckStatusPass},
+				{Name: "iam permissions", Status: CheckStatusWarn},
+			},
+			wantFailed: 0,
+			wantErr:    false,
+		},
+		{
+			name: "a check failed",
+			c: Checklist{
+				{Name: "credentials", Status: CheckStatusFail},
+				{Name: "region", Status: CheckStatusSkip},
+			},
+			wantFailed: 1,
+			wantErr:    true,
+		},
+		{
+			name:       "no checks",
+			c:          Checklist{},
+			wantFailed: 0,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := len(tt.c.Failed()); got != tt.wantFailed {
+				t.Errorf("Checklist.Failed() = %d checks, want %d", got, tt.wantFailed)
+			}
+			err := tt.c.Err()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Checklist.Err() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrPreflightCheck) {
+				t.Errorf("Checklist.Err() error = %v, want %v", err, ErrPreflightCheck)
+			}
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nao1215/spare/utils/errfmt"
+	"golang.org/x/net/idna"
 )
 
 // Domain is a type that represents a domain name.
@@ -17,15 +19,109 @@ func (d Domain) String() string {
 	return string(d)
 }
 
+const (
+	// maxDomainLength is the maximum length of the domain name in the ASCII form (RFC 1035).
+	maxDomainLength = 253
+	// maxLabelLength is the maximum length of the label in the ASCII form (RFC 1035).
+	maxLabelLength = 63
+	// wildcardLabel is the leftmost label that matches any subdomain.
+	wildcardLabel = "*"
+)
+
 // Validate validates Domain. If Domain is invalid, it returns an error.
 // If domain is empty, it returns nil and the default CloudFront domain will be used.
+// The leftmost label can be the wildcard "*" (e.g. *.example.com), because CloudFront accepts it as
+// the alternate domain name.
 func (d Domain) Validate() error {
-	for _, part := range strings.Split(d.String(), ".") {
-		if !isAlphaNumeric(part) {
-			return errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("domain %s is invalid", d))
+	if d.Empty() {
+		return nil
+	}
+	_, err := d.toASCII(true)
+	return err
+}
+
+// ValidateHost validates Domain as the host name without the wildcard (e.g. the domain name of the origin).
+func (d Domain) ValidateHost() error {
+	_, err := d.toASCII(false)
+	return err
+}
+
+// ASCII returns the domain name in the ASCII form. The internationalized labels are converted to
+// the punycode (e.g. 例え.jp is xn--r8jz45g.jp), and the letters are lowercased.
+func (d Domain) ASCII() (Domain, error) {
+	return d.toASCII(true)
+}
+
+// Wildcard returns true if the leftmost label is the wildcard "*".
+func (d Domain) Wildcard() bool {
+	return strings.HasPrefix(d.String(), wildcardLabel+".")
+}
+
+// toASCII validates the domain name by the host name rules (RFC 1123 and RFC 5891) and returns it in the ASCII form.
+// If allowWildcard is true, the leftmost label can be the wildcard "*".
+func (d Domain) toASCII(allowWildcard bool) (Domain, error) {
+	if d.Empty() {
+		return "", errfmt.Wrap(ErrInvalidDomain, "domain is empty")
+	}
+	labels := strings.Split(d.String(), ".")
+	for i, label := range labels {
+		if i == 0 && label == wildcardLabel && len(labels) > 1 {
+			if !allowWildcard {
+				return "", errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("domain %s: wildcard label is not allowed", d))
+			}
+			continue
 		}
+		ascii, err := toASCIILabel(label)
+		if err != nil {
+			return "", errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("domain %s: label %q %s", d, label, err.Error()))
+		}
+		labels[i] = ascii
 	}
-	return nil
+	ascii := strings.Join(labels, ".")
+	if len(ascii) > maxDomainLength {
+		return "", errfmt.Wrap(ErrInvalidDomain,
+			fmt.Sprintf("domain %s must be %d characters or less in the ASCII form: %d", d, maxDomainLength, len(ascii)))
+	}
+	return Domain(ascii), nil
+}
+
+// toASCIILabel validates the label and returns it in the ASCII form. The error is the reason why the label is invalid.
+func toASCIILabel(label string) (string, error) {
+	if label == "" {
+		return "", errors.New("is empty")
+	}
+	ascii := strings.ToLower(label)
+	if !isASCII(label) {
+		converted, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", errors.New("can not be converted to punycode")
+		}
+		ascii = converted
+	} else if strings.HasPrefix(ascii, "xn--") {
+		if _, err := idna.Lookup.ToUnicode(ascii); err != nil {
+			return "", errors.New("is invalid punycode")
+		}
+	}
+	if len(ascii) > maxLabelLength {
+		return "", fmt.Errorf("must be %d characters or less in the ASCII form", maxLabelLength)
+	}
+	if !isAlphaNumeric(strings.ReplaceAll(ascii, "-", "")) {
+		return "", errors.New("must use only letters, numbers and hyphens")
+	}
+	if strings.HasPrefix(ascii, "-") || strings.HasSuffix(ascii, "-") {
+		return "", errors.New("must not start or end with a hyphen")
+	}
+	return ascii, nil
+}
+
+// isASCII returns true if s has only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
 }
 
 // isAlphaNumeric　returns true if s is alphanumeric.
@@ -43,20 +139,44 @@ func (d Domain) Empty() bool {
 	return d == ""
 }
 
-// AllowOrigins is list of origins (domain names) that CloudFront can use as
+// AllowOrigins is list of origins that CloudFront can use as
 // the value for the Access-Control-Allow-Origin HTTP response header.
+// Each origin is parsed by ParseOrigin, and "*" allows all origins.
 type AllowOrigins []Domain
 
 // Validate validates AllowOrigins. If AllowOrigins is invalid, it returns an error.
 func (a AllowOrigins) Validate() (err error) {
 	for _, origin := range a {
-		if e := origin.Validate(); e != nil {
+		if origin.Empty() {
+			continue
+		}
+		if _, e := ParseOrigin(origin.String()); e != nil {
 			err = errors.Join(err, e)
 		}
 	}
 	return err
 }
 
+// Origins returns the origins with the scheme. e.g. https://example.com, http://localhost:3000
+// If AllowOrigins contains "*", it returns only "*".
+func (a AllowOrigins) Origins() []string {
+	origins := make([]string, 0, len(a))
+	for _, origin := range a {
+		if origin.Empty() {
+			continue
+		}
+		o, err := ParseOrigin(origin.String())
+		if err != nil {
+			continue
+		}
+		if o.All() {
+			return []string{o.String()}
+		}
+		origins = append(origins, o.String())
+	}
+	return origins
+}
+
 // String returns the string representation of AllowOrigins.
 func (a AllowOrigins) String() string {
 	origins := make([]string, 0, len(a))
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -60,6 +61,56 @@ func TestDomainValidate(t *testing.T) {
 			d:       "",
 			wantErr: nil,
 		},
+		{
+			name:    "success. label has hyphens",
+			d:       "my-app.example.com",
+			wantErr: nil,
+		},
+		{
+			name:    "success. wildcard",
+			d:       "*.example.com",
+			wantErr: nil,
+		},
+		{
+			name:    "success. internationalized domain name",
+			d:       "例え.jp",
+			wantErr: nil,
+		},
+		{
+			name:    "success. punycode",
+			d:       "xn--r8jz45g.jp",
+			wantErr: nil,
+		},
+		{
+			name:    "failure. empty label",
+			d:       "a..b",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. label starts with a hyphen",
+			d:       "-app.example.com",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. label is too long",
+			d:       Domain(strings.Repeat("a", 64) + ".example.com"),
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. domain is too long",
+			d:       Domain(strings.Repeat(strings.Repeat("a", 63)+".", 4) + "com"),
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. wildcard is not the leftmost label",
+			d:       "app.*.example.com",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. invalid punycode",
+			d:       "xn--a.example.com",
+			wantErr: ErrInvalidDomain,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -70,6 +121,43 @@ func TestDomainValidate(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("error names the invalid label", func(t *testing.T) {
+		t.Parallel()
+		err := Domain("www.my_app.example.com").Validate()
+		if err == nil || !strings.Contains(err.Error(), `label "my_app"`) {
+			t.Errorf("Domain.Validate() error = %v, want the error that names the label my_app", err)
+		}
+	})
+}
+
+func TestDomainValidateHost(t *testing.T) {
+	t.Parallel()
+
+	if err := Domain("api.example.com").ValidateHost(); err != nil {
+		t.Errorf("Domain.ValidateHost() error = %v, want nil", err)
+	}
+	if err := Domain("*.example.com").ValidateHost(); !errors.Is(err, ErrInvalidDomain) {
+		t.Errorf("Domain.ValidateHost() error = %v, want %v", err, ErrInvalidDomain)
+	}
+	if err := Domain("").ValidateHost(); !errors.Is(err, ErrInvalidDomain) {
+		t.Errorf("Domain.ValidateHost() error = %v, want %v", err, ErrInvalidDomain)
+	}
+}
+
+func TestDomainASCII(t *testing.T) {
+	t.Parallel()
+
+	got, err := Domain("*.例え.JP").ASCII()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "*.xn--r8jz45g.jp" {
+		t.Errorf("Domain.ASCII() = %s, want *.xn--r8jz45g.jp", got)
+	}
+	if !got.Wildcard() {
+		t.Errorf("Domain.Wildcard() = false, want true")
+	}
 }
 
 func TestDomainEmpty(t *testing.T) {
@@ -153,8 +241,33 @@ func TestAllowOriginsValidate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "failure. origin is invalid",
+			name:    "success. origin with scheme",
 			a:       AllowOrigins{exampleCom, exampleComWithProtocol},
+			wantErr: false,
+		},
+		{
+			name:    "success. origin with wildcard and port",
+			a:       AllowOrigins{"*.example.com", "http://localhost:3000", "*"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. scheme is not http or https",
+			a:       AllowOrigins{"ftp://example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. origin has path",
+			a:       AllowOrigins{"https://example.com/app"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. wildcard is not the leftmost label",
+			a:       AllowOrigins{"app.*.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. port is out of range",
+			a:       AllowOrigins{"https://example.com:70000"},
 			wantErr: true,
 		},
 	}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// EncryptionType is the server-side encryption of the objects in the bucket.
+type EncryptionType string
+
+const (
+	// EncryptionTypeSSES3 is the server-side encryption with the keys that S3 manages.
+	EncryptionTypeSSES3 EncryptionType = "SSE-S3"
+	// EncryptionTypeSSEKMS is the server-side encryption with the customer managed KMS key.
+	// CloudFront can read the objects only through the origin access control (OAC).
+	EncryptionTypeSSEKMS EncryptionType = "SSE-KMS"
+)
+
+// String returns the string representation of the EncryptionType.
+func (e EncryptionType) String() string {
+	return string(e)
+}
+
+// Validate validates EncryptionType. If EncryptionType is invalid, it returns an error.
+func (e EncryptionType) Validate() error {
+	switch e {
+	case EncryptionTypeSSES3, EncryptionTypeSSEKMS:
+		return nil
+	default:
+		return errfmt.Wrap(ErrInvalidEncryption, fmt.Sprintf("encryption type must be SSE-S3 or SSE-KMS: %s", e))
+	}
+}
+
+// KMSKeyARN is the ARN of the KMS key (e.g. arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab).
+type KMSKeyARN string
+
+// String returns the string representation of the KMSKeyARN.
+func (k KMSKeyARN) String() string {
+	return string(k)
+}
+
+// Empty is whether the KMSKeyARN is empty.
+func (k KMSKeyARN) Empty() bool {
+	return k == ""
+}
+
+// Validate validates KMSKeyARN. The alias ARN is not allowed, because the key policy is applied to the key itself.
+func (k KMSKeyARN) Validate() error {
+	// arn:partition:kms:region:account:key/id
+	parts := strings.Split(k.String(), ":")
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "kms" || parts[3] == "" || parts[4] == "" ||
+		!strings.HasPrefix(parts[5], "key/") || parts[5] == "key/" {
+		return errfmt.Wrap(ErrInvalidEncryption, fmt.Sprintf("kms key arn must be arn:<partition>:kms:<region>:<account>:key/<id>: %s", k))
+	}
+	return nil
+}
+
+// Region returns the region of the KMS key. If the KMSKeyARN is invalid, it returns empty string.
+func (k KMSKeyARN) Region() Region {
+	if err := k.Validate(); err != nil {
+		return ""
+	}
+	return Region(strings.Split(k.String(), ":")[3])
+}
+
+// NewKMSKeyAlias returns the alias of the KMS key that spare creates for the bucket.
+func NewKMSKeyAlias(bucket BucketName) string {
+	return "alias/spare-" + bucket.String()
+}
+
+// Encryption is a type that represents the default server-side encryption of the bucket.
+// The objects uploaded by spare are encrypted with the same settings.
+type Encryption struct {
+	// Type is the server-side encryption.
+	Type EncryptionType
+	// KMSKeyID is the ARN or the alias of the KMS key. It's used only with SSE-KMS.
+	KMSKeyID string
+	// CreateKey is whether spare creates the KMS key with the alias KMSKeyID if it does not exist.
+	CreateKey bool
+}
+
+// KMS is whether the objects are encrypted with the KMS key.
+func (e *Encryption) KMS() bool {
+	return e != nil && e.Type == EncryptionTypeSSEKMS
+}
+
+// AllowCloudFrontKMSKeySid is the Sid of the key policy statement that spare adds to the KMS key.
+const AllowCloudFrontKMSKeySid = "AllowCloudFrontServicePrincipalSSE-KMS"
+
+// NewAllowCloudFrontKMSKeyStatement returns a new key policy statement that allows CloudFront to decrypt the objects
+// through OAC. distributionARNs is the ARNs of the distributions whose origin is the bucket: the distribution and
+// the staging distribution for the canary release, because the staging distribution reads the same objects.
+// The other distributions of the account can not use the key.
+func NewAllowCloudFrontKMSKeyStatement(distributionARNs []string) Statement {
+	return Statement{
+		Sid:       AllowCloudFrontKMSKeySid,
+		Effect:    "Allow",
+		Principal: Principal{Service: "cloudfront.amazonaws.com"},
+		Action: []string{
+			"kms:Decrypt",
+			"kms:Encrypt",
+			"kms:GenerateDataKey*",
+		},
+		Resource: []string{"*"},
+		Condition: map[string]map[string]interface{}{
+			"StringEquals": {
+				"AWS:SourceArn": distributionARNs,
+			},
+		},
+	}
+}
+
+// MergeKeyPolicy returns the key policy whose statement with the same Sid as stmt is replaced by stmt.
+// If the key policy does not have the statement, stmt is appended. The other statements are kept as they are.
+func MergeKeyPolicy(policy string, stmt Statement) (string, error) {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return "", errfmt.Wrap(ErrInvalidEncryption, "failed to parse key policy: "+err.Error())
+	}
+	statements := []json.RawMessage{}
+	if raw, ok := doc["Statement"]; ok {
+		if err := json.Unmarshal(raw, &statements); err != nil {
+			return "", errfmt.Wrap(ErrInvalidEncryption, "failed to parse key policy statements: "+err.Error())
+		}
+	}
+
+	newStatement, err := json.Marshal(stmt)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal key policy statement")
+	}
+	merged := make([]json.RawMessage, 0, len(statements)+1)
+	for _, s := range statements {
+		var sid struct {
+			Sid string `json:"Sid"` //nolint
+		}
+		if err := json.Unmarshal(s, &sid); err != nil {
+			return "", errfmt.Wrap(ErrInvalidEncryption, "failed to parse key policy statement: "+err.Error())
+		}
+		if sid.Sid != stmt.Sid {
+			merged = append(merged, s)
+		}
+	}
+	merged = append(merged, newStatement)
+
+	if doc["Statement"], err = json.Marshal(merged); err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal key policy statements")
+	}
+	if _, ok := doc["Version"]; !ok {
+		doc["Version"] = json.RawMessage(`"2012-10-17"`)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal key policy")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKMSKeyARNValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		k          KMSKeyARN
+		wantRegion Region
+		wantErr    bool
+	}{
+		{
+			name:       "success",
+			k:          "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantRegion: RegionUSEast1,
+			wantErr:    false,
+		},
+		{
+			name:       "failure. alias arn",
+			k:          "arn:aws:kms:us-east-1:123456789012:alias/spare",
+			wantRegion: "",
+			wantErr:    true,
+		},
+		{
+			name:       "failure. not kms",
+			k:          "arn:aws:s3:::my-bucket",
+			wantRegion: "",
+			wantErr:    true,
+		},
+		{
+			name:       "failure. key id without arn",
+			k:          "1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantRegion: "",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.k.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("KMSKeyARN.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidEncryption) {
+				t.Errorf("KMSKeyARN.Validate() error = %v, want %v", err, ErrInvalidEncryption)
+			}
+			if got := tt.k.Region(); got != tt.wantRegion {
+				t.Errorf("KMSKeyARN.Region() = %s, want %s", got, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestMergeKeyPolicy(t *testing.T) {
+	t.Parallel()
+
+	stmt := NewAllowCloudFrontKMSKeyStatement([]string{
+		"arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE",
+		"arn:aws:cloudfront::123456789012:distribution/E2STAGINGEXAMPLE",
+	})
+	const rootStatement = `{"Sid":"Enable IAM User Permissions","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}`
+	const want = `{"Id":"key-default-1","Statement":[` + rootStatement + `,` +
+		`{"Sid":"AllowCloudFrontServicePrincipalSSE-KMS","Effect":"Allow","Principal":{"Service":"cloudfront.amazonaws.com"},` +
+		`"Action":["kms:Decrypt","kms:Encrypt","kms:GenerateDataKey*"],"Resource":["*"],` +
+		`"Condition":{"StringEquals":{"AWS:SourceArn":["arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE",` +
+		`"arn:aws:cloudfront::123456789012:distribution/E2STAGINGEXAMPLE"]}}}],"Version":"2012-10-17"}`
+
+	t.Run("append the statement", func(t *testing.T) {
+		t.Parallel()
+		got, err := MergeKeyPolicy(`{"Version":"2012-10-17","Id":"key-default-1","Statement":[`+rootStatement+`]}`, stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("replace the statement with the same Sid", func(t *testing.T) {
+		t.Parallel()
+		old := `{"Sid":"AllowCloudFrontServicePrincipalSSE-KMS","Effect":"Allow","Principal":{"Service":"cloudfront.amazonaws.com"},"Action":"kms:Decrypt","Resource":"*"}`
+		got, err := MergeKeyPolicy(`{"Version":"2012-10-17","Id":"key-default-1","Statement":[`+rootStatement+`,`+old+`]}`, stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("failure. invalid key policy", func(t *testing.T) {
+		t.Parallel()
+		if _, err := MergeKeyPolicy("{", stmt); !errors.Is(err, ErrInvalidEncryption) {
+			t.Errorf("MergeKeyPolicy() error = %v, want %v", err, ErrInvalidEncryption)
+		}
+	})
+}
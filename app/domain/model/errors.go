@@ -7,10 +7,68 @@ var (
 	ErrInvalidRegion = errors.New("invalid region")
 	// ErrEmptyRegion is an error that occurs when the region is empty.
 	ErrEmptyRegion = errors.New("region is empty")
+	// ErrUnsupportedRegion is an error that occurs when the feature that spare uses is not available in the region.
+	ErrUnsupportedRegion = errors.New("unsupported region")
 	// ErrInvalidBucketName is an error that occurs when the bucket name is invalid.
 	ErrInvalidBucketName = errors.New("bucket name is invalid")
 	// ErrInvalidDomain is an error that occurs when the domain is invalid.
 	ErrInvalidDomain = errors.New("invalid domain")
 	// ErrInvalidEndpoint is an error that occurs when the endpoint is invalid.
 	ErrInvalidEndpoint = errors.New("invalid endpoint")
+	// ErrInvalidReleaseID is an error that occurs when the release id is invalid.
+	ErrInvalidReleaseID = errors.New("invalid release id")
+	// ErrReleaseNotFound is an error that occurs when the release does not exist in the release history.
+	ErrReleaseNotFound = errors.New("release not found")
+	// ErrViewerRulesNotFound is an error that occurs when the rules of the viewer request function are not recorded in the bucket.
+	ErrViewerRulesNotFound = errors.New("viewer request rules not found")
+	// ErrNoPreviousRelease is an error that occurs when there is no release to roll back to.
+	ErrNoPreviousRelease = errors.New("no previous release")
+	// ErrReleaseRecorded is an error that occurs when the release to abandon is recorded in the release history.
+	ErrReleaseRecorded = errors.New("release is recorded in the release history")
+	// ErrInvalidPreviewName is an error that occurs when the preview name is invalid.
+	ErrInvalidPreviewName = errors.New("invalid preview name")
+	// ErrPreviewNotFound is an error that occurs when the preview does not exist in the preview list.
+	ErrPreviewNotFound = errors.New("preview not found")
+	// ErrInvalidCanaryTraffic is an error that occurs when the canary traffic setting is invalid.
+	ErrInvalidCanaryTraffic = errors.New("invalid canary traffic")
+	// ErrNoStagedRelease is an error that occurs when there is no canary release to promote or abort.
+	ErrNoStagedRelease = errors.New("no canary release")
+	// ErrCanaryInProgress is an error that occurs when the live release is changed while the canary release is in progress.
+	ErrCanaryInProgress = errors.New("canary release is in progress")
+	// ErrInvalidCacheBehavior is an error that occurs when the cache behavior is invalid.
+	ErrInvalidCacheBehavior = errors.New("invalid cache behavior")
+	// ErrInvalidSecurityHeaders is an error that occurs when the security headers are invalid.
+	ErrInvalidSecurityHeaders = errors.New("invalid security headers")
+	// ErrInvalidWAF is an error that occurs when the WAF rules are invalid.
+	ErrInvalidWAF = errors.New("invalid WAF rules")
+	// ErrInvalidBasicAuth is an error that occurs when the basic auth settings are invalid.
+	ErrInvalidBasicAuth = errors.New("invalid basic auth")
+	// ErrInvalidRedirectRule is an error that occurs when the redirect rule is invalid or not supported.
+	ErrInvalidRedirectRule = errors.New("invalid redirect rule")
+	// ErrInvalidPrettyURLs is an error that occurs when the pretty URLs settings are invalid.
+	ErrInvalidPrettyURLs = errors.New("invalid pretty URLs settings")
+	// ErrInvalidMaintenance is an error that occurs when the maintenance mode settings are invalid.
+	ErrInvalidMaintenance = errors.New("invalid maintenance mode settings")
+	// ErrInvalidCustomOrigin is an error that occurs when the custom origin settings are invalid.
+	ErrInvalidCustomOrigin = errors.New("invalid custom origin")
+	// ErrInvalidCDNFunction is an error that occurs when the CDN function is invalid.
+	ErrInvalidCDNFunction = errors.New("invalid CDN function")
+	// ErrInvalidAccessLog is an error that occurs when the access log can not be parsed.
+	ErrInvalidAccessLog = errors.New("invalid access log")
+	// ErrInvalidBucketSettings is an error that occurs when the bucket settings (e.g. lifecycle rules) are invalid.
+	ErrInvalidBucketSettings = errors.New("invalid bucket settings")
+	// ErrInvalidEncryption is an error that occurs when the server-side encryption settings are invalid.
+	ErrInvalidEncryption = errors.New("invalid encryption settings")
+	// ErrInvalidTags is an error that occurs when the tags are invalid.
+	ErrInvalidTags = errors.New("invalid tags")
+	// ErrPreflightCheck is an error that occurs when the preflight checks (spare doctor) fail.
+	ErrPreflightCheck = errors.New("preflight check failed")
+	// ErrInvalidIAMCommand is an error that occurs when the command of the IAM permissions is unknown.
+	ErrInvalidIAMCommand = errors.New("invalid IAM command")
+	// ErrInvalidGitHubOIDC is an error that occurs when the GitHub OIDC settings are invalid.
+	ErrInvalidGitHubOIDC = errors.New("invalid GitHub OIDC settings")
+	// ErrInvalidAssumeRole is an error that occurs when the settings of the role to assume are invalid.
+	ErrInvalidAssumeRole = errors.New("invalid assume role settings")
+	// ErrInvalidStorageEndpoint is an error that occurs when the settings of the S3-compatible storage are invalid.
+	ErrInvalidStorageEndpoint = errors.New("invalid storage endpoint")
 )
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// RetentionPolicy is a type that represents which releases are kept by the garbage collection.
+// A release is kept if it matches at least one rule. The live release, the canary release and
+// pinned releases are always kept.
+type RetentionPolicy struct {
+	// KeepLast is the number of the latest releases to keep.
+	KeepLast int
+	// KeepNewerThan is the age of releases to keep. Releases newer than this are kept.
+	// If it's zero, releases are not kept by age.
+	KeepNewerThan time.Duration
+	// Pinned is the list of releases that are never deleted.
+	Pinned []ReleaseID
+}
+
+// Retain splits the releases into the releases to keep and the releases to delete.
+func (h *ReleaseHistory) Retain(policy RetentionPolicy, now time.Time) (retained, expired []Release) {
+	pinned := make(map[ReleaseID]bool, len(policy.Pinned))
+	for _, id := range policy.Pinned {
+		pinned[id] = true
+	}
+
+	retained = make([]Release, 0, len(h.Releases))
+	expired = make([]Release, 0, len(h.Releases))
+	for i, release := range h.Releases {
+		switch {
+		case release.ID == h.Live, release.ID == h.Staged, pinned[release.ID]:
+			retained = append(retained, release)
+		case len(h.Releases)-i <= policy.KeepLast:
+			retained = append(retained, release)
+		case policy.KeepNewerThan > 0 && release.CreatedAt.After(now.Add(-policy.KeepNewerThan)):
+			retained = append(retained, release)
+		default:
+			expired = append(expired, release)
+		}
+	}
+	return retained, expired
+}
+
+// Remove removes the releases from the history. The live release and the canary release are never removed.
+func (h *ReleaseHistory) Remove(releases []Release) {
+	removed := make(map[ReleaseID]bool, len(releases))
+	for _, r := range releases {
+		if r.ID != h.Live && r.ID != h.Staged {
+			removed[r.ID] = true
+		}
+	}
+
+	kept := make([]Release, 0, len(h.Releases))
+	for _, r := range h.Releases {
+		if !removed[r.ID] {
+			kept = append(kept, r)
+		}
+	}
+	h.Releases = kept
+}
+
+// ReleasesRootPrefix is the S3 key prefix that contains all releases.
+const ReleasesRootPrefix = releasePrefix + "/"
+
+// ReleaseIDFromKey returns the release ID of the S3 key.
+// If the key is not under ReleasesRootPrefix, it returns false.
+func ReleaseIDFromKey(key string) (ReleaseID, bool) {
+	if !strings.HasPrefix(key, ReleasesRootPrefix) {
+		return "", false
+	}
+	id, _, found := strings.Cut(strings.TrimPrefix(key, ReleasesRootPrefix), "/")
+	if !found || id == "" {
+		return "", false
+	}
+	return ReleaseID(id), true
+}
+
+// BucketObject is a type that represents an object in the S3 bucket.
+type BucketObject struct {
+	// Key is the S3 key.
+	Key string
+	// Size is the size of the object in bytes.
+	Size int64
+}
+
+// BucketObjects is a list of BucketObject.
+type BucketObjects []BucketObject
+
+// TotalSize returns the total size of the objects in bytes.
+func (b BucketObjects) TotalSize() int64 {
+	var total int64
+	for _, o := range b {
+		total += o.Size
+	}
+	return total
+}
+
+// Keys returns the S3 keys of the objects.
+func (b BucketObjects) Keys() []string {
+	keys := make([]string, 0, len(b))
+	for _, o := range b {
+		keys = append(keys, o.Key)
+	}
+	return keys
+}
+
+// SplitManifests splits the release manifests into the manifests to keep, the pending manifests and the manifests to delete.
+// The manifests of the retained releases are kept, and the manifests of the expired releases are deleted.
+// A manifest of a release that is not in the release history belongs to a deploy in progress or a deploy that failed.
+// It's pending and kept however old it is, unless the release is abandoned.
+func (h *ReleaseHistory) SplitManifests(manifests []*ReleaseManifest, retained []Release, abandoned []ReleaseID) (kept, pending, deleted []*ReleaseManifest) {
+	keep := make(map[ReleaseID]bool, len(retained))
+	for _, r := range retained {
+		keep[r.ID] = true
+	}
+	abandon := make(map[ReleaseID]bool, len(abandoned))
+	for _, id := range abandoned {
+		abandon[id] = true
+	}
+
+	kept = make([]*ReleaseManifest, 0, len(manifests))
+	pending = make([]*ReleaseManifest, 0)
+	deleted = make([]*ReleaseManifest, 0)
+	for _, m := range manifests {
+		_, err := h.Find(m.ID)
+		switch {
+		case keep[m.ID]:
+			kept = append(kept, m)
+		case err == nil, abandon[m.ID]:
+			deleted = append(deleted, m)
+		default:
+			pending = append(pending, m)
+		}
+	}
+	return kept, pending, deleted
+}
+
+// ValidateAbandoned validates the releases to abandon. Only a deploy that is not recorded in the release history
+// can be abandoned, because the recorded releases are deleted by the retention policy.
+func (h *ReleaseHistory) ValidateAbandoned(abandoned []ReleaseID) error {
+	for _, id := range abandoned {
+		if _, err := h.Find(id); err == nil {
+			return errfmt.Wrap(ErrReleaseRecorded, fmt.Sprintf("release %s can not be abandoned", id))
+		}
+	}
+	return nil
+}
+
+// Unreachable returns the objects under ReleasesRootPrefix that belong to none of the manifests.
+// The manifests are the manifests of the retained releases and the pending manifests of the deploys in progress.
+// The releases deployed before spare recorded the manifests have no manifest, so all objects under the prefix
+// of such a retained release are kept. Objects outside ReleasesRootPrefix are never returned.
+func (b BucketObjects) Unreachable(retained []Release, manifests []*ReleaseManifest) BucketObjects {
+	reachable := make(map[string]bool)
+	manifested := make(map[ReleaseID]bool, len(manifests))
+	for _, m := range manifests {
+		manifested[m.ID] = true
+		for _, key := range m.Keys {
+			reachable[key] = true
+		}
+	}
+	unmanifested := make(map[ReleaseID]bool, len(retained))
+	for _, r := range retained {
+		if !manifested[r.ID] {
+			unmanifested[r.ID] = true
+		}
+	}
+
+	unreachable := make(BucketObjects, 0, len(b))
+	for _, o := range b {
+		id, ok := ReleaseIDFromKey(o.Key)
+		if !ok || reachable[o.Key] || unmanifested[id] {
+			continue
+		}
+		unreachable = append(unreachable, o)
+	}
+	return unreachable
+}
@@ -0,0 +1,270 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func releaseIDs(releases []Release) []ReleaseID {
+	ids := make([]ReleaseID, 0, len(releases))
+	for _, r := range releases {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestReleaseHistoryRetain(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 21, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		live         ReleaseID
+		staged       ReleaseID
+		policy       RetentionPolicy
+		wantRetained []ReleaseID
+		wantExpired  []ReleaseID
+	}{
+		{
+			name:         "keep last 2 releases",
+			live:         testReleaseID3,
+			policy:       RetentionPolicy{KeepLast: 2},
+			wantRetained: []ReleaseID{testReleaseID2, testReleaseID3},
+			wantExpired:  []ReleaseID{testReleaseID1},
+		},
+		{
+			name:         "keep releases newer than 30 hours",
+			live:         testReleaseID3,
+			policy:       RetentionPolicy{KeepNewerThan: 30 * time.Hour},
+			wantRetained: []ReleaseID{testReleaseID2, testReleaseID3},
+			wantExpired:  []ReleaseID{testReleaseID1},
+		},
+		{
+			name:         "live release is always kept",
+			live:         testReleaseID1,
+			policy:       RetentionPolicy{},
+			wantRetained: []ReleaseID{testReleaseID1},
+			wantExpired:  []ReleaseID{testReleaseID2, testReleaseID3},
+		},
+		{
+			name:         "canary release is always kept",
+			live:         testReleaseID2,
+			staged:       testReleaseID1,
+			policy:       RetentionPolicy{KeepLast: 1},
+			wantRetained: []ReleaseID{testReleaseID1, testReleaseID2, testReleaseID3},
+			wantExpired:  []ReleaseID{},
+		},
+		{
+			name:         "pinned release is always kept",
+			live:         testReleaseID3,
+			policy:       RetentionPolicy{KeepLast: 1, Pinned: []ReleaseID{testReleaseID1}},
+			wantRetained: []ReleaseID{testReleaseID1, testReleaseID3},
+			wantExpired:  []ReleaseID{testReleaseID2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestReleaseHistory(t, tt.live)
+			h.Staged = tt.staged
+			retained, expired := h.Retain(tt.policy, now)
+			if diff := cmp.Diff(tt.wantRetained, releaseIDs(retained)); diff != "" {
+				t.Errorf("retained releases are mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantExpired, releaseIDs(expired)); diff != "" {
+				t.Errorf("expired releases are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReleaseHistoryRemove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("live release is not removed", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, testReleaseID3)
+		h.Remove([]Release{{ID: testReleaseID1}, {ID: testReleaseID3}})
+
+		want := []ReleaseID{testReleaseID2, testReleaseID3}
+		if diff := cmp.Diff(want, releaseIDs(h.Releases)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestReleaseIDFromKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		key    string
+		want   ReleaseID
+		wantOK bool
+	}{
+		{
+			name:   "success",
+			key:    "releases/20231019T120000Z/css/style.css",
+			want:   testReleaseID1,
+			wantOK: true,
+		},
+		{
+			name:   "failure. key is not under releases",
+			key:    "_spare/releases.json",
+			want:   "",
+			wantOK: false,
+		},
+		{
+			name:   "failure. key has no release id",
+			key:    "releases/index.html",
+			want:   "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ReleaseIDFromKey(tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReleaseIDFromKey() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestReleaseHistorySplitManifests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, testReleaseID3)
+		retained, _ := h.Retain(RetentionPolicy{KeepLast: 2}, time.Date(2023, 10, 21, 13, 0, 0, 0, time.UTC))
+		manifests := []*ReleaseManifest{
+			NewReleaseManifest(testReleaseID1, nil),            // expired
+			NewReleaseManifest(testReleaseID3, nil),            // live
+			NewReleaseManifest("20231021T110000Z-aaaaaa", nil), // deploy in progress
+			NewReleaseManifest("20231021T100000Z-bbbbbb", nil), // abandoned deploy
+		}
+
+		kept, pending, deleted := h.SplitManifests(manifests, retained, []ReleaseID{"20231021T100000Z-bbbbbb"})
+		if diff := cmp.Diff([]*ReleaseManifest{manifests[1]}, kept); diff != "" {
+			t.Errorf("kept manifests are mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]*ReleaseManifest{manifests[2]}, pending); diff != "" {
+			t.Errorf("pending manifests are mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]*ReleaseManifest{manifests[0], manifests[3]}, deleted); diff != "" {
+			t.Errorf("deleted manifests are mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestReleaseHistoryValidateAbandoned(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		abandoned []ReleaseID
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			abandoned: []ReleaseID{"20231021T110000Z-aaaaaa"},
+			wantErr:   nil,
+		},
+		{
+			name:      "failure. release is recorded",
+			abandoned: []ReleaseID{testReleaseID2},
+			wantErr:   ErrReleaseRecorded,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestReleaseHistory(t, testReleaseID3)
+			if err := h.ValidateAbandoned(tt.abandoned); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReleaseHistory.ValidateAbandoned() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBucketObjectsUnreachable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		objects := BucketObjects{
+			{Key: "releases/20231019T120000Z/index.html", Size: 10},
+			{Key: "releases/20231020T120000Z/index.html", Size: 20},
+			{Key: "releases/20231020T120000Z/app.js", Size: 30},
+			{Key: "releases/20230101T000000Z/index.html", Size: 40}, // failed deploy
+			{Key: "releases/20231021T125000Z/index.html", Size: 50}, // deploy in progress
+			{Key: "_spare/releases.json", Size: 60},
+		}
+		manifests := []*ReleaseManifest{
+			NewReleaseManifest("20231021T125000Z", []string{"releases/20231021T125000Z/index.html"}),
+		}
+
+		got := objects.Unreachable([]Release{{ID: testReleaseID1}}, manifests)
+		want := BucketObjects{
+			{Key: "releases/20231020T120000Z/index.html", Size: 20},
+			{Key: "releases/20231020T120000Z/app.js", Size: 30},
+			{Key: "releases/20230101T000000Z/index.html", Size: 40},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if got.TotalSize() != 90 {
+			t.Errorf("BucketObjects.TotalSize() = %d, want 90", got.TotalSize())
+		}
+	})
+
+	t.Run("deploy still running after a long time is kept", func(t *testing.T) {
+		t.Parallel()
+		// The deploy started 3 hours ago and it's still uploading. It's not in the release history yet,
+		// but its manifest was put before the upload, so gc keeps the uploaded objects.
+		objects := BucketObjects{
+			{Key: "releases/20231021T100000Z-aaaaaa/index.html", Size: 10},
+			{Key: "releases/20231021T100000Z-aaaaaa/app.js", Size: 20},
+		}
+		h := newTestReleaseHistory(t, testReleaseID3)
+		retained, _ := h.Retain(RetentionPolicy{KeepLast: 1}, time.Date(2023, 10, 21, 13, 0, 0, 0, time.UTC))
+		kept, pending, _ := h.SplitManifests([]*ReleaseManifest{
+			NewReleaseManifest("20231021T100000Z-aaaaaa", []string{
+				"releases/20231021T100000Z-aaaaaa/index.html",
+				"releases/20231021T100000Z-aaaaaa/app.js",
+			}),
+		}, retained, nil)
+
+		got := objects.Unreachable(retained, append(kept, pending...))
+		if diff := cmp.Diff(BucketObjects{}, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("object that is not in the manifest of a retained release is unreachable", func(t *testing.T) {
+		t.Parallel()
+		objects := BucketObjects{
+			{Key: "releases/20231021T120000Z/index.html", Size: 10},
+			{Key: "releases/20231021T120000Z/old.js", Size: 20},
+		}
+		manifests := []*ReleaseManifest{
+			NewReleaseManifest(testReleaseID3, []string{"releases/20231021T120000Z/index.html"}),
+		}
+
+		got := objects.Unreachable([]Release{{ID: testReleaseID3}}, manifests)
+		want := BucketObjects{
+			{Key: "releases/20231021T120000Z/old.js", Size: 20},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
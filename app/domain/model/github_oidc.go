@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+const (
+	// githubOIDCHost is the issuer of the OIDC tokens of GitHub Actions.
+	githubOIDCHost = "token.actions.githubusercontent.com"
+	// githubOIDCAudience is the audience of the OIDC tokens that aws-actions/configure-aws-credentials requests.
+	githubOIDCAudience = "sts.amazonaws.com"
+	// roleNameMaxLen is the maximum length of the IAM role name.
+	roleNameMaxLen = 64
+)
+
+// githubOIDCThumbprints is the thumbprints of the certificates of the GitHub OIDC provider.
+// IAM no longer uses them for GitHub, but CloudFormation requires at least one.
+var githubOIDCThumbprints = []string{ //nolint:gochecknoglobals
+	"6938fd4d98bab03faadb97b34396831e3780aea1",
+	"1c58a3a8518e8759bf075b76b750d4f2df264fcd",
+}
+
+// GitHubOIDC is the GitHub Actions workflows that can assume the role with the OIDC token (keyless deploys).
+type GitHubOIDC struct {
+	// Repository is the repository in the form of OWNER/REPO.
+	Repository string
+	// Branch is the branch that can assume the role. If it's empty, any branch can assume the role.
+	Branch string
+	// Environment is the GitHub environment that can assume the role. It can not be used with Branch,
+	// because the subject of the token of the job with an environment does not have the branch.
+	Environment string
+}
+
+var githubRepositoryRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates GitHubOIDC. If GitHubOIDC is invalid, it returns an error.
+func (g GitHubOIDC) Validate() error {
+	githubRepositoryRegexPattern.InitOnce(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+	if err := githubRepositoryRegexPattern.MatchString(g.Repository); err != nil {
+		return errfmt.Wrap(ErrInvalidGitHubOIDC, fmt.Sprintf("repository must be OWNER/REPO: %s", g.Repository))
+	}
+	if g.Branch != "" && g.Environment != "" {
+		return errfmt.Wrap(ErrInvalidGitHubOIDC, "branch and environment can not be used together")
+	}
+	if strings.ContainsAny(g.Branch+g.Environment, ":*?") {
+		return errfmt.Wrap(ErrInvalidGitHubOIDC, "branch and environment must not contain ':', '*' or '?'")
+	}
+	return nil
+}
+
+// Subject returns the subject (sub claim) of the OIDC token that can assume the role.
+// e.g. repo:OWNER/REPO:ref:refs/heads/main, repo:OWNER/REPO:environment:production, repo:OWNER/REPO:*
+func (g GitHubOIDC) Subject() string {
+	switch {
+	case g.Environment != "":
+		return fmt.Sprintf("repo:%s:environment:%s", g.Repository, g.Environment)
+	case g.Branch != "":
+		return fmt.Sprintf("repo:%s:ref:refs/heads/%s", g.Repository, g.Branch)
+	default:
+		return fmt.Sprintf("repo:%s:*", g.Repository)
+	}
+}
+
+// ProviderARN returns the ARN of the GitHub OIDC provider in the account.
+func (g GitHubOIDC) ProviderARN(partition Partition, account string) string {
+	return partition.ARN("iam", "", account, "oidc-provider/"+githubOIDCHost)
+}
+
+// TrustPolicy returns the trust policy of the role that allows the workflows to assume the role
+// with the GitHub OIDC provider in the account.
+func (g GitHubOIDC) TrustPolicy(partition Partition, account string) *IAMPolicy {
+	return g.trustPolicy(g.ProviderARN(partition, account))
+}
+
+// trustPolicy returns the trust policy whose federated principal is provider.
+func (g GitHubOIDC) trustPolicy(provider interface{}) *IAMPolicy {
+	return &IAMPolicy{
+		Version: "2012-10-17",
+		Statement: []IAMPolicyStatement{
+			{
+				Sid:       "GitHubActions",
+				Effect:    "Allow",
+				Principal: map[string]interface{}{"Federated": provider},
+				Action:    []string{"sts:AssumeRoleWithWebIdentity"},
+				Condition: map[string]map[string]string{
+					"StringEquals": {githubOIDCHost + ":aud": githubOIDCAudience},
+					"StringLike":   {githubOIDCHost + ":sub": g.Subject()},
+				},
+			},
+		},
+	}
+}
+
+// NewRoleName returns the name of the IAM role for the bucket. e.g. spare-my-bucket
+func NewRoleName(bucket BucketName) string {
+	name := fmt.Sprintf("spare-%s", bucket)
+	if len(name) > roleNameMaxLen {
+		name = name[:roleNameMaxLen]
+	}
+	return name
+}
+
+// CloudFormationTemplate is the AWS CloudFormation template in the JSON format.
+type CloudFormationTemplate struct {
+	// AWSTemplateFormatVersion is the version of the template format.
+	AWSTemplateFormatVersion string `json:"AWSTemplateFormatVersion"` //nolint
+	// Description is the description of the template.
+	Description string `json:"Description"` //nolint
+	// Parameters is the input values of the stack.
+	Parameters map[string]interface{} `json:"Parameters,omitempty"` //nolint
+	// Conditions is the conditions that decide whether the resources are created.
+	Conditions map[string]interface{} `json:"Conditions,omitempty"` //nolint
+	// Resources is the resources of the stack.
+	Resources map[string]interface{} `json:"Resources"` //nolint
+	// Outputs is the output values of the stack.
+	Outputs map[string]interface{} `json:"Outputs,omitempty"` //nolint
+}
+
+// CloudFormationTemplate returns the template that creates the role with the policy for the workflows.
+// The GitHub OIDC provider is created unless the OIDCProviderArn parameter is set,
+// because an account can have only one provider for GitHub.
+func (g GitHubOIDC) CloudFormationTemplate(roleName string, policy *IAMPolicy) *CloudFormationTemplate {
+	const (
+		providerParam     = "OIDCProviderArn"
+		providerResource  = "GitHubOIDCProvider"
+		createProviderKey = "CreateOIDCProvider"
+	)
+	provider := map[string]interface{}{
+		"Fn::If": []interface{}{
+			createProviderKey,
+			map[string]interface{}{"Ref": providerResource},
+			map[string]interface{}{"Ref": providerParam},
+		},
+	}
+	return &CloudFormationTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Description:              fmt.Sprintf("IAM role for spare that GitHub Actions of %s assume with OIDC", g.Repository),
+		Parameters: map[string]interface{}{
+			providerParam: map[string]interface{}{
+				"Type":        "String",
+				"Default":     "",
+				"Description": "ARN of the existing GitHub OIDC provider. If it's empty, the provider is created.",
+			},
+		},
+		Conditions: map[string]interface{}{
+			createProviderKey: map[string]interface{}{
+				"Fn::Equals": []interface{}{map[string]interface{}{"Ref": providerParam}, ""},
+			},
+		},
+		Resources: map[string]interface{}{
+			providerResource: map[string]interface{}{
+				"Type":      "AWS::IAM::OIDCProvider",
+				"Condition": createProviderKey,
+				"Properties": map[string]interface{}{
+					"Url":            "https://" + githubOIDCHost,
+					"ClientIdList":   []string{githubOIDCAudience},
+					"ThumbprintList": githubOIDCThumbprints,
+				},
+			},
+			"SpareRole": map[string]interface{}{
+				"Type": "AWS::IAM::Role",
+				"Properties": map[string]interface{}{
+					"RoleName":                 roleName,
+					"AssumeRolePolicyDocument": g.trustPolicy(provider),
+					"Policies": []interface{}{
+						map[string]interface{}{
+							"PolicyName":     "spare",
+							"PolicyDocument": policy,
+						},
+					},
+				},
+			},
+		},
+		Outputs: map[string]interface{}{
+			"RoleArn": map[string]interface{}{
+				"Description": "role-to-assume of aws-actions/configure-aws-credentials",
+				"Value":       map[string]interface{}{"Fn::GetAtt": []string{"SpareRole", "Arn"}},
+			},
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGitHubOIDCValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		g       GitHubOIDC
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			g:       GitHubOIDC{Repository: "nao1215/spare", Branch: "main"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. repository is not OWNER/REPO",
+			g:       GitHubOIDC{Repository: "spare"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. branch and environment are used together",
+			g:       GitHubOIDC{Repository: "nao1215/spare", Branch: "main", Environment: "production"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. branch has a wildcard",
+			g:       GitHubOIDC{Repository: "nao1215/spare", Branch: "release/*"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.g.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("GitHubOIDC.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitHubOIDCSubject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		g    GitHubOIDC
+		want string
+	}{
+		{
+			name: "branch",
+			g:    GitHubOIDC{Repository: "nao1215/spare", Branch: "main"},
+			want: "repo:nao1215/spare:ref:refs/heads/main",
+		},
+		{
+			name: "environment",
+			g:    GitHubOIDC{Repository: "nao1215/spare", Environment: "production"},
+			want: "repo:nao1215/spare:environment:production",
+		},
+		{
+			name: "any branch",
+			g:    GitHubOIDC{Repository: "nao1215/spare"},
+			want: "repo:nao1215/spare:*",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.g.Subject(); got != tt.want {
+				t.Errorf("GitHubOIDC.Subject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubOIDCTrustPolicy(t *testing.T) {
+	t.Parallel()
+
+	g := GitHubOIDC{Repository: "nao1215/spare", Branch: "main"}
+	got, err := json.Marshal(g.TrustPolicy(PartitionAWS, "123456789012"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Version":"2012-10-17","Statement":[{"Sid":"GitHubActions","Effect":"Allow",` +
+		`"Principal":{"Federated":"arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"},` +
+		`"Action":["sts:AssumeRoleWithWebIdentity"],` +
+		`"Condition":{"StringEquals":{"token.actions.githubusercontent.com:aud":"sts.amazonaws.com"},` +
+		`"StringLike":{"token.actions.githubusercontent.com:sub":"repo:nao1215/spare:ref:refs/heads/main"}}}]}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("GitHubOIDC.TrustPolicy() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitHubOIDCCloudFormationTemplate(t *testing.T) {
+	t.Parallel()
+
+	g := GitHubOIDC{Repository: "nao1215/spare", Environment: "production"}
+	policy := NewIAMPolicy([]IAMPermission{{Sid: "SpareObjects", Actions: []string{"s3:PutObject"}, Resources: []string{"arn:aws:s3:::my-bucket/*"}}})
+	got, err := json.Marshal(g.CloudFormationTemplate("spare-my-bucket", policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"Type":"AWS::IAM::OIDCProvider"`,
+		`"Type":"AWS::IAM::Role"`,
+		`"RoleName":"spare-my-bucket"`,
+		`"Federated":{"Fn::If":["CreateOIDCProvider",{"Ref":"GitHubOIDCProvider"},{"Ref":"OIDCProviderArn"}]}`,
+		`"repo:nao1215/spare:environment:production"`,
+		`"PolicyDocument":{"Version":"2012-10-17","Statement":[{"Sid":"SpareObjects","Effect":"Allow","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::my-bucket/*"]}]}`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("GitHubOIDC.CloudFormationTemplate() does not contain %s", want)
+		}
+	}
+}
+
+func TestNewRoleName(t *testing.T) {
+	t.Parallel()
+
+	if got := NewRoleName("my-bucket"); got != "spare-my-bucket" {
+		t.Errorf("NewRoleName() = %v, want spare-my-bucket", got)
+	}
+	if got := NewRoleName(BucketName(strings.Repeat("a", 63))); len(got) != roleNameMaxLen {
+		t.Errorf("NewRoleName() length = %d, want %d", len(got), roleNameMaxLen)
+	}
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// HeadersFileName is the name of the Netlify-style header rules file in the deploy target.
+// The file is not uploaded to S3.
+const HeadersFileName = "_headers"
+
+// objectMetadataHeaders is the list of headers that S3 stores as the object metadata and returns with the object.
+// Other headers can not be set per path, because the response headers policy is applied to all paths.
+var objectMetadataHeaders = []string{ //nolint:gochecknoglobals
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Type",
+	"Expires",
+}
+
+// HeaderRule is a type that represents a rule of the _headers file.
+type HeaderRule struct {
+	// Path is the path pattern. ':NAME' matches a path segment, and the trailing '*' matches the rest of the path.
+	Path string
+	// Headers is the list of headers that are returned with the objects matched by Path.
+	Headers []HTTPHeader
+}
+
+// HeaderRules is the list of header rules. All matched rules are applied.
+type HeaderRules []HeaderRule
+
+// ParseHeaderRules parses the _headers file. The headers that spare does not support are skipped,
+// and the reasons are returned as warnings. e.g. "_headers:3: X-Frame-Options can not be set per path"
+func ParseHeaderRules(r io.Reader) (HeaderRules, []string, error) {
+	rules := HeaderRules{}
+	warnings := []string{}
+	var current *HeaderRule
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		warn := func(msg string) {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s", HeadersFileName, n, msg))
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			rules, current = appendHeaderRule(rules, current), nil
+			if _, _, err := compilePathPattern(trimmed); err != nil || !strings.HasPrefix(trimmed, "/") {
+				warn(fmt.Sprintf("%s is not a path pattern. the headers for it are skipped", trimmed))
+				continue
+			}
+			current = &HeaderRule{Path: trimmed, Headers: []HTTPHeader{}}
+			continue
+		}
+
+		if current == nil {
+			warn(fmt.Sprintf("header %s has no path. it's skipped", trimmed))
+			continue
+		}
+		name, value, found := strings.Cut(trimmed, ":")
+		header := HTTPHeader{Name: http.CanonicalHeaderKey(strings.TrimSpace(name)), Value: strings.TrimSpace(value)}
+		if !found {
+			warn(fmt.Sprintf("%s is not a header. it must be NAME: VALUE", trimmed))
+			continue
+		}
+		if err := header.Validate(); err != nil {
+			warn(fmt.Sprintf("header %s is invalid. it's skipped", trimmed))
+			continue
+		}
+		if _, err := http.ParseTime(header.Value); header.Name == "Expires" && err != nil {
+			warn(fmt.Sprintf("Expires for %s is not a HTTP date. it's skipped", current.Path))
+			continue
+		}
+		if !contains(objectMetadataHeaders, header.Name) {
+			warn(fmt.Sprintf("%s for %s can not be set per path. it's skipped. use securityHeaders.customHeaders in .spare.yml for all paths",
+				header.Name, current.Path))
+			continue
+		}
+		current.Headers = append(current.Headers, header)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errfmt.Wrap(err, fmt.Sprintf("failed to read %s", HeadersFileName))
+	}
+	return appendHeaderRule(rules, current), warnings, nil
+}
+
+// appendHeaderRule appends the rule to the rules if the rule has headers.
+func appendHeaderRule(rules HeaderRules, rule *HeaderRule) HeaderRules {
+	if rule == nil || len(rule.Headers) == 0 {
+		return rules
+	}
+	return append(rules, *rule)
+}
+
+// ObjectHeaders returns the headers of the object whose key (relative to the release root) is key.
+// e.g. "docs/index.html" matches the rules for /docs/index.html and /docs/.
+// If several rules set the same header, the values are joined with ", ".
+func (h HeaderRules) ObjectHeaders(key string) []HTTPHeader {
+	paths := []string{"/" + key}
+	if path.Base(key) == "index.html" {
+		paths = append(paths, "/"+strings.TrimSuffix(key, "index.html"))
+	}
+
+	headers := []HTTPHeader{}
+	for _, rule := range h {
+		if !rule.matchAny(paths) {
+			continue
+		}
+		for _, header := range rule.Headers {
+			headers = mergeHeader(headers, header)
+		}
+	}
+	return headers
+}
+
+// matchAny returns true if the path pattern of the rule matches any of paths.
+func (r HeaderRule) matchAny(paths []string) bool {
+	expr, _, err := compilePathPattern(r.Path)
+	if err != nil {
+		return false
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	for _, p := range paths {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeHeader adds the header to headers. If headers already has the header, the value is appended.
+func mergeHeader(headers []HTTPHeader, header HTTPHeader) []HTTPHeader {
+	for i := range headers {
+		if headers[i].Name == header.Name {
+			headers[i].Value += ", " + header.Value
+			return headers
+		}
+	}
+	return append(headers, header)
+}
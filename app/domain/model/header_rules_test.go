@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseHeaderRules(t *testing.T) {
+	t.Parallel()
+
+	input := `  Cache-Control: no-cache
+/*
+  X-Frame-Options: DENY
+  cache-control: public, max-age=300
+
+/assets/*
+  Cache-Control: max-age=31536000
+  Cache-Control: immutable
+/docs/:page
+  Content-Language: en
+/index.html
+  Cache-Control no-cache
+  Expires: tomorrow
+`
+	rules, warnings, err := ParseHeaderRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRules := HeaderRules{
+		{Path: "/*", Headers: []HTTPHeader{{Name: "Cache-Control", Value: "public, max-age=300"}}},
+		{Path: "/assets/*", Headers: []HTTPHeader{
+			{Name: "Cache-Control", Value: "max-age=31536000"},
+			{Name: "Cache-Control", Value: "immutable"},
+		}},
+		{Path: "/docs/:page", Headers: []HTTPHeader{{Name: "Content-Language", Value: "en"}}},
+	}
+	if diff := cmp.Diff(wantRules, rules); diff != "" {
+		t.Errorf("rules are mismatch (-want +got):\n%s", diff)
+	}
+
+	wantWarnings := []string{
+		"_headers:1: header Cache-Control: no-cache has no path",
+		"_headers:3: X-Frame-Options for /* can not be set per path",
+		"_headers:12: Cache-Control no-cache is not a header",
+		"_headers:13: Expires for /index.html is not a HTTP date",
+	}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("warnings = %v, want %d warnings", warnings, len(wantWarnings))
+	}
+	for i, w := range wantWarnings {
+		if !strings.HasPrefix(warnings[i], w) {
+			t.Errorf("warnings[%d] = %q, want prefix %q", i, warnings[i], w)
+		}
+	}
+}
+
+func TestHeaderRulesObjectHeaders(t *testing.T) {
+	t.Parallel()
+
+	rules := HeaderRules{
+		{Path: "/assets/*", Headers: []HTTPHeader{{Name: "Cache-Control", Value: "max-age=31536000"}}},
+		{Path: "/*", Headers: []HTTPHeader{{Name: "Cache-Control", Value: "public"}}},
+		{Path: "/docs/", Headers: []HTTPHeader{{Name: "Content-Language", Value: "en"}}},
+	}
+	tests := []struct {
+		name string
+		key  string
+		want []HTTPHeader
+	}{
+		{
+			name: "values of the same header are joined",
+			key:  "assets/app.js",
+			want: []HTTPHeader{{Name: "Cache-Control", Value: "max-age=31536000, public"}},
+		},
+		{
+			name: "index.html matches the directory path",
+			key:  "docs/index.html",
+			want: []HTTPHeader{{Name: "Cache-Control", Value: "public"}, {Name: "Content-Language", Value: "en"}},
+		},
+		{
+			name: "only the rule for all paths matches",
+			key:  "favicon.ico",
+			want: []HTTPHeader{{Name: "Cache-Control", Value: "public"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, rules.ObjectHeaders(tt.key)); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
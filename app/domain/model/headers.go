@@ -0,0 +1,200 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// HTTPHeader is a type that represents an HTTP header.
+type HTTPHeader struct {
+	// Name is the name of the header. e.g. X-Frame-Options
+	Name string
+	// Value is the value of the header. e.g. DENY
+	Value string
+}
+
+// String returns the string representation of HTTPHeader. e.g. X-Frame-Options: DENY
+func (h HTTPHeader) String() string {
+	return fmt.Sprintf("%s: %s", h.Name, h.Value)
+}
+
+var httpHeaderNameRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates the name of HTTPHeader. If the name is not an HTTP token, it returns an error.
+func (h HTTPHeader) Validate() error {
+	httpHeaderNameRegexPattern.InitOnce("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+	if err := httpHeaderNameRegexPattern.MatchString(h.Name); err != nil {
+		return errfmt.Wrap(ErrInvalidSecurityHeaders, fmt.Sprintf("invalid header name %q", h.Name))
+	}
+	if h.Value == "" {
+		return errfmt.Wrap(ErrInvalidSecurityHeaders, fmt.Sprintf("value of header %s is empty", h.Name))
+	}
+	return nil
+}
+
+const (
+	// hstsPreloadMinMaxAge is the minimum max-age that the HSTS preload list requires.
+	hstsPreloadMinMaxAge = 31536000
+	// FrameOptionsDeny is the X-Frame-Options value that denies any framing.
+	FrameOptionsDeny = "DENY"
+	// FrameOptionsSameOrigin is the X-Frame-Options value that allows the framing from the same origin.
+	FrameOptionsSameOrigin = "SAMEORIGIN"
+)
+
+// referrerPolicies is the list of Referrer-Policy values that CloudFront supports.
+var referrerPolicies = []string{ //nolint:gochecknoglobals
+	"no-referrer",
+	"no-referrer-when-downgrade",
+	"origin",
+	"origin-when-cross-origin",
+	"same-origin",
+	"strict-origin",
+	"strict-origin-when-cross-origin",
+	"unsafe-url",
+}
+
+// StrictTransportSecurity is a type that represents the Strict-Transport-Security (HSTS) header.
+type StrictTransportSecurity struct {
+	// MaxAge is the time in seconds that browsers access the site only with HTTPS.
+	// If it's zero, the header is not sent.
+	MaxAge int64
+	// IncludeSubdomains is whether HSTS applies to the subdomains.
+	IncludeSubdomains bool
+	// Preload is whether the site is in the HSTS preload list.
+	Preload bool
+}
+
+// String returns the value of the Strict-Transport-Security header.
+func (s StrictTransportSecurity) String() string {
+	value := fmt.Sprintf("max-age=%d", s.MaxAge)
+	if s.IncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if s.Preload {
+		value += "; preload"
+	}
+	return value
+}
+
+// SecurityHeaders is a type that represents the security headers that the CDN adds to the responses.
+// The empty field means the header is not sent.
+type SecurityHeaders struct {
+	// StrictTransportSecurity is the Strict-Transport-Security header.
+	StrictTransportSecurity StrictTransportSecurity
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header.
+	ContentSecurityPolicy string
+	// ContentTypeOptions is whether the X-Content-Type-Options: nosniff header is sent.
+	ContentTypeOptions bool
+	// FrameOptions is the value of the X-Frame-Options header. DENY or SAMEORIGIN.
+	FrameOptions string
+	// ReferrerPolicy is the value of the Referrer-Policy header.
+	ReferrerPolicy string
+	// PermissionsPolicy is the value of the Permissions-Policy header.
+	PermissionsPolicy string
+	// CustomHeaders is the list of other headers.
+	CustomHeaders []HTTPHeader
+}
+
+// NewSecurityHeaders returns the secure default security headers for SPA.
+func NewSecurityHeaders() *SecurityHeaders {
+	const twoYears = 63072000
+	return &SecurityHeaders{
+		StrictTransportSecurity: StrictTransportSecurity{
+			MaxAge:            twoYears,
+			IncludeSubdomains: true,
+			Preload:           false,
+		},
+		ContentSecurityPolicy: "default-src 'self'; img-src 'self' data: https:; style-src 'self' 'unsafe-inline'; " +
+			"font-src 'self' data: https:; connect-src 'self' https:; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+		ContentTypeOptions: true,
+		FrameOptions:       FrameOptionsDeny,
+		ReferrerPolicy:     "strict-origin-when-cross-origin",
+		PermissionsPolicy:  "camera=(), microphone=(), geolocation=(), payment=()",
+		CustomHeaders:      []HTTPHeader{},
+	}
+}
+
+// Headers returns the list of headers that the CDN adds to the responses.
+func (s *SecurityHeaders) Headers() []HTTPHeader {
+	headers := make([]HTTPHeader, 0, len(s.CustomHeaders)+6) //nolint:gomnd
+	if s.StrictTransportSecurity.MaxAge > 0 {
+		headers = append(headers, HTTPHeader{Name: "Strict-Transport-Security", Value: s.StrictTransportSecurity.String()})
+	}
+	if s.ContentSecurityPolicy != "" {
+		headers = append(headers, HTTPHeader{Name: "Content-Security-Policy", Value: s.ContentSecurityPolicy})
+	}
+	if s.ContentTypeOptions {
+		headers = append(headers, HTTPHeader{Name: "X-Content-Type-Options", Value: "nosniff"})
+	}
+	if s.FrameOptions != "" {
+		headers = append(headers, HTTPHeader{Name: "X-Frame-Options", Value: s.FrameOptions})
+	}
+	if s.ReferrerPolicy != "" {
+		headers = append(headers, HTTPHeader{Name: "Referrer-Policy", Value: s.ReferrerPolicy})
+	}
+	if s.PermissionsPolicy != "" {
+		headers = append(headers, HTTPHeader{Name: "Permissions-Policy", Value: s.PermissionsPolicy})
+	}
+	return append(headers, s.CustomHeaders...)
+}
+
+// Validate validates SecurityHeaders. If SecurityHeaders is invalid, it returns an error.
+func (s *SecurityHeaders) Validate() (err error) {
+	hsts := s.StrictTransportSecurity
+	if hsts.MaxAge < 0 {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidSecurityHeaders, fmt.Sprintf("HSTS max-age must not be negative: %d", hsts.MaxAge)))
+	}
+	if hsts.Preload && (!hsts.IncludeSubdomains || hsts.MaxAge < hstsPreloadMinMaxAge) {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidSecurityHeaders,
+			fmt.Sprintf("HSTS preload requires includeSubdomains and max-age >= %d", hstsPreloadMinMaxAge)))
+	}
+	if s.FrameOptions != "" && s.FrameOptions != FrameOptionsDeny && s.FrameOptions != FrameOptionsSameOrigin {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidSecurityHeaders,
+			fmt.Sprintf("X-Frame-Options must be %s or %s: %s", FrameOptionsDeny, FrameOptionsSameOrigin, s.FrameOptions)))
+	}
+	if s.ReferrerPolicy != "" && !contains(referrerPolicies, s.ReferrerPolicy) {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidSecurityHeaders,
+			fmt.Sprintf("Referrer-Policy must be one of %s: %s", strings.Join(referrerPolicies, ", "), s.ReferrerPolicy)))
+	}
+
+	seen := make(map[string]bool, len(s.CustomHeaders))
+	for _, h := range s.Headers() {
+		name := http.CanonicalHeaderKey(h.Name)
+		if seen[name] {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidSecurityHeaders, fmt.Sprintf("header %s is duplicated", h.Name)))
+		}
+		seen[name] = true
+	}
+	for _, h := range s.CustomHeaders {
+		if e := h.Validate(); e != nil {
+			err = errors.Join(err, e)
+		}
+	}
+	return err
+}
+
+// NewResponseHeadersPolicyName returns the name of the response headers policy for the bucket.
+// e.g. spare-my-bucket
+func NewResponseHeadersPolicyName(bucket BucketName) string {
+	const maxLen = 128
+	name := strings.ReplaceAll(fmt.Sprintf("spare-%s", bucket), ".", "-")
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	return name
+}
+
+// contains returns true if list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
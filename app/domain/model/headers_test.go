@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSecurityHeadersValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       *SecurityHeaders
+		wantErr bool
+	}{
+		{
+			name:    "success. default security headers",
+			s:       NewSecurityHeaders(),
+			wantErr: false,
+		},
+		{
+			name:    "success. no header",
+			s:       &SecurityHeaders{},
+			wantErr: false,
+		},
+		{
+			name: "success. HSTS preload",
+			s: &SecurityHeaders{
+				StrictTransportSecurity: StrictTransportSecurity{MaxAge: 63072000, IncludeSubdomains: true, Preload: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failure. HSTS preload without includeSubdomains",
+			s: &SecurityHeaders{
+				StrictTransportSecurity: StrictTransportSecurity{MaxAge: 63072000, Preload: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. HSTS preload with short max-age",
+			s: &SecurityHeaders{
+				StrictTransportSecurity: StrictTransportSecurity{MaxAge: 300, IncludeSubdomains: true, Preload: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "failure. HSTS max-age is negative",
+			s:       &SecurityHeaders{StrictTransportSecurity: StrictTransportSecurity{MaxAge: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid X-Frame-Options",
+			s:       &SecurityHeaders{FrameOptions: "ALLOW-FROM https://example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid Referrer-Policy",
+			s:       &SecurityHeaders{ReferrerPolicy: "never"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid custom header name",
+			s:       &SecurityHeaders{CustomHeaders: []HTTPHeader{{Name: "X Powered By", Value: "spare"}}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. custom header value is empty",
+			s:       &SecurityHeaders{CustomHeaders: []HTTPHeader{{Name: "X-Powered-By", Value: ""}}},
+			wantErr: true,
+		},
+		{
+			name: "failure. custom header overrides security header",
+			s: &SecurityHeaders{
+				FrameOptions:  FrameOptionsDeny,
+				CustomHeaders: []HTTPHeader{{Name: "x-frame-options", Value: "SAMEORIGIN"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.s.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecurityHeaders.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidSecurityHeaders) {
+				t.Errorf("SecurityHeaders.Validate() error = %v, want %v", err, ErrInvalidSecurityHeaders)
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		s := &SecurityHeaders{
+			StrictTransportSecurity: StrictTransportSecurity{MaxAge: 63072000, IncludeSubdomains: true, Preload: true},
+			ContentTypeOptions:      true,
+			FrameOptions:            FrameOptionsSameOrigin,
+			CustomHeaders:           []HTTPHeader{{Name: "X-Powered-By", Value: "spare"}},
+		}
+		want := []HTTPHeader{
+			{Name: "Strict-Transport-Security", Value: "max-age=63072000; includeSubDomains; preload"},
+			{Name: "X-Content-Type-Options", Value: "nosniff"},
+			{Name: "X-Frame-Options", Value: "SAMEORIGIN"},
+			{Name: "X-Powered-By", Value: "spare"},
+		}
+		if diff := cmp.Diff(want, s.Headers()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// IAMCommand is the spare command that the IAM permissions are granted for.
+type IAMCommand string
+
+const (
+	// IAMCommandBuild is 'spare build'. It creates and updates the bucket, CloudFront and the optional resources.
+	IAMCommandBuild IAMCommand = "build"
+	// IAMCommandDeploy is 'spare deploy' and the other commands that change only the releases
+	// (e.g. spare rollback, spare promote).
+	IAMCommandDeploy IAMCommand = "deploy"
+)
+
+// String returns the string representation of the IAMCommand.
+func (c IAMCommand) String() string {
+	return string(c)
+}
+
+// Validate validates IAMCommand. If IAMCommand is unknown, it returns an error.
+func (c IAMCommand) Validate() error {
+	if _, ok := iamActions[c]; !ok {
+		return errfmt.Wrap(ErrInvalidIAMCommand, fmt.Sprintf("command must be build or deploy: %s", c))
+	}
+	return nil
+}
+
+// iamResource is the kind of the resources that the IAM actions are allowed on.
+type iamResource int
+
+const (
+	// iamResourceBucket is the bucket that serves the SPA.
+	iamResourceBucket iamResource = iota
+	// iamResourceObjects is the objects in the bucket that serves the SPA.
+	iamResourceObjects
+	// iamResourceLogBucket is the bucket of the access logs.
+	iamResourceLogBucket
+	// iamResourceDistribution is the CloudFront distribution.
+	iamResourceDistribution
+	// iamResourceFunction is the CloudFront Functions that spare creates. Their names start with "spare-".
+	iamResourceFunction
+	// iamResourceCloudFront is the other CloudFront resources. Their actions do not support the resource-level permissions,
+	// or spare does not know the ARNs of the resources before they are created (e.g. cache policies).
+	iamResourceCloudFront
+	// iamResourceWAF is the web ACL and the IP sets.
+	iamResourceWAF
+	// iamResourceKMSCreate is the creation of the KMS key for SSE-KMS. CreateKey does not support the resource-level permissions.
+	iamResourceKMSCreate
+	// iamResourceKMS is the KMS key for SSE-KMS.
+	iamResourceKMS
+	// iamResourceTagging is the Resource Groups Tagging API, that does not support the resource-level permissions.
+	iamResourceTagging
+)
+
+// iamResources is the order of the permissions in the policy.
+var iamResources = []iamResource{ //nolint:gochecknoglobals
+	iamResourceBucket, iamResourceObjects, iamResourceLogBucket, iamResourceDistribution, iamResourceFunction,
+	iamResourceCloudFront, iamResourceWAF, iamResourceKMSCreate, iamResourceKMS, iamResourceTagging,
+}
+
+// iamSids is the Sid of the permission for each kind of the resources.
+var iamSids = map[iamResource]string{ //nolint:gochecknoglobals
+	iamResourceBucket:       "SpareBucket",
+	iamResourceObjects:      "SpareObjects",
+	iamResourceLogBucket:    "SpareLogBucket",
+	iamResourceDistribution: "SpareDistribution",
+	iamResourceFunction:     "SpareFunctions",
+	iamResourceCloudFront:   "SpareCloudFront",
+	iamResourceWAF:          "SpareWAF",
+	iamResourceKMSCreate:    "SpareKMSCreate",
+	iamResourceKMS:          "SpareKMS",
+	iamResourceTagging:      "SpareTagging",
+}
+
+// iamActions is the IAM actions that each command calls. It must be updated when app/external calls a new AWS API.
+var iamActions = map[IAMCommand]map[iamResource][]string{ //nolint:gochecknoglobals
+	IAMCommandBuild: {
+		iamResourceBucket: {
+			"s3:CreateBucket",
+			"s3:GetBucketVersioning",
+			"s3:ListBucket",
+			"s3:PutBucketCORS",
+			"s3:PutBucketLogging",
+			"s3:PutBucketOwnershipControls",
+			"s3:PutBucketPolicy",
+			"s3:PutBucketPublicAccessBlock",
+			"s3:PutBucketTagging",
+			"s3:PutBucketVersioning",
+			"s3:PutEncryptionConfiguration",
+			"s3:PutLifecycleConfiguration",
+		},
+		iamResourceObjects: {
+			"s3:GetObject",
+			"s3:PutObject",
+		},
+		iamResourceLogBucket: {
+			"s3:CreateBucket",
+			"s3:GetBucketAcl",
+			"s3:PutBucketAcl",
+			"s3:PutBucketOwnershipControls",
+			"s3:PutBucketPolicy",
+			"s3:PutBucketPublicAccessBlock",
+			"s3:PutBucketTagging",
+			"s3:PutLifecycleConfiguration",
+		},
+		iamResourceDistribution: {
+			"cloudfront:GetDistribution",
+			"cloudfront:GetDistributionConfig",
+			"cloudfront:TagResource",
+			"cloudfront:UpdateDistribution",
+		},
+		iamResourceFunction: {
+			"cloudfront:CreateFunction",
+			"cloudfront:DescribeFunction",
+			"cloudfront:PublishFunction",
+			"cloudfront:UpdateFunction",
+		},
+		iamResourceCloudFront: {
+			"cloudfront:CreateCachePolicy",
+			"cloudfront:CreateDistribution",
+			"cloudfront:CreateKeyGroup",
+			"cloudfront:CreateOriginAccessControl",
+			"cloudfront:CreateOriginRequestPolicy",
+			"cloudfront:CreatePublicKey",
+			"cloudfront:CreateResponseHeadersPolicy",
+			"cloudfront:DeletePublicKey",
+			"cloudfront:GetCachePolicy",
+			"cloudfront:GetKeyGroup",
+			"cloudfront:GetOriginRequestPolicy",
+			"cloudfront:GetPublicKey",
+			"cloudfront:GetResponseHeadersPolicy",
+			"cloudfront:ListCachePolicies",
+			"cloudfront:ListDistributions",
+			"cloudfront:ListKeyGroups",
+			"cloudfront:ListOriginAccessControls",
+			"cloudfront:ListOriginRequestPolicies",
+			"cloudfront:ListResponseHeadersPolicies",
+			"cloudfront:UpdateCachePolicy",
+			"cloudfront:UpdateKeyGroup",
+			"cloudfront:UpdateOriginRequestPolicy",
+			"cloudfront:UpdateResponseHeadersPolicy",
+		},
+		iamResourceWAF: {
+			"wafv2:CreateIPSet",
+			"wafv2:CreateWebACL",
+			"wafv2:DeleteIPSet",
+			"wafv2:DeleteWebACL",
+			"wafv2:GetWebACL",
+			"wafv2:ListIPSets",
+			"wafv2:ListWebACLs",
+			"wafv2:TagResource",
+			"wafv2:UpdateIPSet",
+			"wafv2:UpdateWebACL",
+		},
+		iamResourceKMSCreate: {
+			"kms:CreateAlias",
+			"kms:CreateKey",
+		},
+		iamResourceKMS: {
+			"kms:DescribeKey",
+			"kms:EnableKeyRotation",
+			"kms:GetKeyPolicy",
+			"kms:PutKeyPolicy",
+			"kms:TagResource",
+		},
+		iamResourceTagging: {
+			"tag:GetResources",
+			"tag:TagResources",
+		},
+	},
+	IAMCommandDeploy: {
+		iamResourceBucket: {
+			"s3:ListBucket",
+		},
+		iamResourceObjects: {
+			"s3:DeleteObject",
+			"s3:GetObject",
+			"s3:PutObject",
+		},
+		iamResourceDistribution: {
+			"cloudfront:CreateInvalidation",
+			"cloudfront:GetDistribution",
+			"cloudfront:GetDistributionConfig",
+			"cloudfront:UpdateDistribution",
+		},
+		iamResourceFunction: {
+			"cloudfront:CreateFunction",
+			"cloudfront:DescribeFunction",
+			"cloudfront:PublishFunction",
+			"cloudfront:UpdateFunction",
+		},
+		iamResourceCloudFront: {
+			"cloudfront:ListDistributions",
+		},
+		iamResourceKMS: {
+			"kms:Decrypt",
+			"kms:GenerateDataKey",
+		},
+	},
+}
+
+// iamCanaryActions is the IAM actions that the canary release (spare deploy --canary, spare promote, spare abort) calls
+// in addition to the actions of spare deploy. The staging distribution is created by copying the primary distribution,
+// and spare promote copies the config of the staging distribution back to the primary distribution.
+// The bucket policy and the key policy are updated to allow the staging distribution to read the objects.
+var iamCanaryActions = map[iamResource][]string{ //nolint:gochecknoglobals
+	iamResourceBucket: {
+		"s3:PutBucketPolicy",
+	},
+	iamResourceKMS: {
+		"kms:GetKeyPolicy",
+		"kms:PutKeyPolicy",
+	},
+	iamResourceDistribution: {
+		"cloudfront:CopyDistribution",
+		"cloudfront:UpdateDistributionWithStagingConfig",
+	},
+	iamResourceCloudFront: {
+		"cloudfront:CreateContinuousDeploymentPolicy",
+		"cloudfront:CreateDistribution",
+		"cloudfront:GetContinuousDeploymentPolicy",
+		"cloudfront:UpdateContinuousDeploymentPolicy",
+	},
+}
+
+// IAMPermission is the IAM actions that spare needs on the resources.
+type IAMPermission struct {
+	// Sid is the identifier of the permission. It's used as the Sid of the policy statement.
+	Sid string `json:"sid"`
+	// Actions is the IAM actions sorted by the name. e.g. s3:PutObject
+	Actions []string `json:"actions"`
+	// Resources is the ARNs of the resources. "*" means all resources.
+	Resources []string `json:"resources"`
+}
+
+// IAMPermissionOptions is the features of .spare.yml that decide the IAM permissions.
+type IAMPermissionOptions struct {
+	// Commands is the spare commands that the permissions are granted for.
+	Commands []IAMCommand
+	// Bucket is the bucket that serves the SPA.
+	Bucket BucketName
+	// LogBucket is the bucket of the access logs. If it's empty, the access logging is disabled.
+	LogBucket BucketName
+	// Partition is the partition of the region.
+	Partition Partition
+	// WAF is whether the web ACL is enabled.
+	WAF bool
+	// KMS is whether the bucket is encrypted with SSE-KMS.
+	KMS bool
+	// KMSKeyARN is the ARN of the existing KMS key for SSE-KMS. If it's empty, spare creates the KMS key.
+	KMSKeyARN KMSKeyARN
+	// Canary is whether the canary release is used. The staging distribution is not known before it's created,
+	// so the distribution actions are allowed on all distributions in the account.
+	Canary bool
+	// Account is the AWS account ID. If it's empty, the CloudFront actions are allowed on all resources.
+	Account string
+	// DistributionID is the ID of the distribution. If it's empty (e.g. before spare build), the distribution actions
+	// are allowed on all distributions in the account.
+	DistributionID DistributionID
+}
+
+// NewIAMPermissions returns the IAM permissions that the commands need with the options.
+// The actions of the commands are merged, and the permissions of the disabled features are omitted.
+func NewIAMPermissions(opts IAMPermissionOptions) ([]IAMPermission, error) {
+	actions := map[iamResource]map[string]bool{}
+	add := func(groups map[iamResource][]string) {
+		for resource, names := range groups {
+			if actions[resource] == nil {
+				actions[resource] = map[string]bool{}
+			}
+			for _, name := range names {
+				actions[resource][name] = true
+			}
+		}
+	}
+	for _, c := range opts.Commands {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+		add(iamActions[c])
+		if c == IAMCommandDeploy && opts.Canary {
+			add(iamCanaryActions)
+		}
+	}
+
+	permissions := []IAMPermission{}
+	for _, resource := range iamResources {
+		arns := opts.resourceARNs(resource)
+		if len(actions[resource]) == 0 || len(arns) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(actions[resource]))
+		for name := range actions[resource] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		permissions = append(permissions, IAMPermission{
+			Sid:       iamSids[resource],
+			Actions:   names,
+			Resources: arns,
+		})
+	}
+	return permissions, nil
+}
+
+// resourceARNs returns the ARNs of the kind of the resources. If the feature is disabled, it returns nil.
+func (o IAMPermissionOptions) resourceARNs(resource iamResource) []string {
+	switch resource {
+	case iamResourceBucket:
+		return []string{o.Bucket.ARN(o.Partition)}
+	case iamResourceObjects:
+		return []string{o.Bucket.ARN(o.Partition) + "/*"}
+	case iamResourceLogBucket:
+		if o.LogBucket.Empty() {
+			return nil
+		}
+		return []string{o.LogBucket.ARN(o.Partition)}
+	case iamResourceDistribution:
+		if o.Account == "" {
+			return []string{"*"}
+		}
+		if o.DistributionID.Empty() || o.Canary {
+			return []string{o.Partition.ARN("cloudfront", "", o.Account, "distribution/*")}
+		}
+		return []string{o.Partition.ARN("cloudfront", "", o.Account, "distribution/"+o.DistributionID.String())}
+	case iamResourceFunction:
+		if o.Account == "" {
+			return []string{"*"}
+		}
+		return []string{o.Partition.ARN("cloudfront", "", o.Account, "function/spare-*")}
+	case iamResourceWAF:
+		if !o.WAF {
+			return nil
+		}
+		return []string{"*"}
+	case iamResourceKMSCreate:
+		if !o.KMS || !o.KMSKeyARN.Empty() {
+			return nil
+		}
+		return []string{"*"}
+	case iamResourceKMS:
+		if !o.KMS {
+			return nil
+		}
+		if !o.KMSKeyARN.Empty() {
+			return []string{o.KMSKeyARN.String()}
+		}
+		return []string{"*"}
+	default:
+		return []string{"*"}
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+// IAMPolicyStatement is a statement of the IAM policy. The identity-based policy has no principal,
+// and the trust policy of the role has the principal that can assume the role.
+type IAMPolicyStatement struct {
+	// Sid is an identifier for the statement.
+	Sid string `json:"Sid,omitempty"` //nolint
+	// Effect is whether the statement allows or denies access.
+	Effect string `json:"Effect"` //nolint
+	// Principal is the principal that can assume the role. e.g. {"Federated": "arn:aws:iam::123456789012:oidc-provider/..."}
+	// The value is interface{}, because the CloudFormation template uses the intrinsic functions.
+	Principal map[string]interface{} `json:"Principal,omitempty"` //nolint
+	// Action is the IAM actions.
+	Action []string `json:"Action"` //nolint
+	// Resource is the ARNs of the resources. The trust policy has no resource.
+	Resource []string `json:"Resource,omitempty"` //nolint
+	// Condition is the conditions for when the statement is in effect.
+	Condition map[string]map[string]string `json:"Condition,omitempty"` //nolint
+}
+
+// IAMPolicy is the IAM policy document.
+type IAMPolicy struct {
+	// Version is the policy language version.
+	Version string `json:"Version"` //nolint
+	// Statement is the policy statement.
+	Statement []IAMPolicyStatement `json:"Statement"` //nolint
+}
+
+// NewIAMPolicy returns the identity-based policy that allows the permissions.
+func NewIAMPolicy(permissions []IAMPermission) *IAMPolicy {
+	policy := &IAMPolicy{
+		Version:   "2012-10-17",
+		Statement: make([]IAMPolicyStatement, 0, len(permissions)),
+	}
+	for _, p := range permissions {
+		policy.Statement = append(policy.Statement, IAMPolicyStatement{
+			Sid:      p.Sid,
+			Effect:   "Allow",
+			Action:   p.Actions,
+			Resource: p.Resources,
+		})
+	}
+	return policy
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewIAMPermissions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deploy is scoped to the bucket, the distribution and the KMS key", func(t *testing.T) {
+		t.Parallel()
+		got, err := NewIAMPermissions(IAMPermissionOptions{
+			Commands:       []IAMCommand{IAMCommandDeploy},
+			Bucket:         "my-bucket",
+			Partition:      PartitionAWS,
+			KMS:            true,
+			KMSKeyARN:      "arn:aws:kms:us-east-1:123456789012:key/1234abcd",
+			Account:        "123456789012",
+			DistributionID: "EDFDVBD6EXAMPLE",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []IAMPermission{
+			{Sid: "SpareBucket", Actions: []string{"s3:ListBucket"}, Resources: []string{"arn:aws:s3:::my-bucket"}},
+			{Sid: "SpareObjects", Actions: []string{"s3:DeleteObject", "s3:GetObject", "s3:PutObject"}, Resources: []string{"arn:aws:s3:::my-bucket/*"}},
+			{
+				Sid:       "SpareDistribution",
+				Actions:   []string{"cloudfront:CreateInvalidation", "cloudfront:GetDistribution", "cloudfront:GetDistributionConfig", "cloudfront:UpdateDistribution"},
+				Resources: []string{"arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE"},
+			},
+			{
+				Sid:       "SpareFunctions",
+				Actions:   []string{"cloudfront:CreateFunction", "cloudfront:DescribeFunction", "cloudfront:PublishFunction", "cloudfront:UpdateFunction"},
+				Resources: []string{"arn:aws:cloudfront::123456789012:function/spare-*"},
+			},
+			{Sid: "SpareCloudFront", Actions: []string{"cloudfront:ListDistributions"}, Resources: []string{"*"}},
+			{Sid: "SpareKMS", Actions: []string{"kms:Decrypt", "kms:GenerateDataKey"}, Resources: []string{"arn:aws:kms:us-east-1:123456789012:key/1234abcd"}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("NewIAMPermissions() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("canary is allowed on all distributions in the account", func(t *testing.T) {
+		t.Parallel()
+		got, err := NewIAMPermissions(IAMPermissionOptions{
+			Commands:       []IAMCommand{IAMCommandDeploy},
+			Bucket:         "my-bucket",
+			Partition:      PartitionAWS,
+			Canary:         true,
+			Account:        "123456789012",
+			DistributionID: "EDFDVBD6EXAMPLE",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range got {
+			if p.Sid != "SpareDistribution" {
+				continue
+			}
+			if diff := cmp.Diff([]string{"arn:aws:cloudfront::123456789012:distribution/*"}, p.Resources); diff != "" {
+				t.Errorf("SpareDistribution resources mismatch (-want +got):\n%s", diff)
+			}
+			if p.Actions[0] != "cloudfront:CopyDistribution" {
+				t.Errorf("SpareDistribution actions = %v, want cloudfront:CopyDistribution", p.Actions)
+			}
+		}
+	})
+
+	t.Run("actions of the commands are merged without duplicates", func(t *testing.T) {
+		t.Parallel()
+		got, err := NewIAMPermissions(IAMPermissionOptions{
+			Commands:  []IAMCommand{IAMCommandBuild, IAMCommandDeploy},
+			Bucket:    "my-bucket",
+			LogBucket: "my-bucket-logs",
+			Partition: PartitionAWSCN,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range got {
+			seen := map[string]bool{}
+			for _, a := range p.Actions {
+				if seen[a] {
+					t.Errorf("%s has duplicated action %s", p.Sid, a)
+				}
+				seen[a] = true
+			}
+			if p.Sid == "SpareWAF" || p.Sid == "SpareKMS" || p.Sid == "SpareKMSCreate" {
+				t.Errorf("%s is granted though the feature is disabled", p.Sid)
+			}
+			if p.Sid == "SpareLogBucket" && p.Resources[0] != "arn:aws-cn:s3:::my-bucket-logs" {
+				t.Errorf("SpareLogBucket resources = %v", p.Resources)
+			}
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		t.Parallel()
+		if _, err := NewIAMPermissions(IAMPermissionOptions{Commands: []IAMCommand{"destroy"}}); err == nil {
+			t.Error("NewIAMPermissions() error = nil, want error")
+		}
+	})
+}
+
+func TestIAMActionsSorted(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string]map[iamResource][]string{"canary": iamCanaryActions}
+	for command, resources := range iamActions {
+		groups[command.String()] = resources
+	}
+	for name, resources := range groups {
+		for resource, actions := range resources {
+			for i := 1; i < len(actions); i++ {
+				if actions[i-1] >= actions[i] {
+					t.Errorf("actions of %s (resource %d) are not sorted: %s, %s", name, resource, actions[i-1], actions[i])
+				}
+			}
+		}
+	}
+}
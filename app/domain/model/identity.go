@@ -0,0 +1,44 @@
+package model
+
+import "strings"
+
+// CallerIdentity is the AWS account and the IAM identity that the credentials belong to.
+type CallerIdentity struct {
+	// Account is the AWS account ID. e.g. 123456789012
+	Account string `json:"account"`
+	// ARN is the ARN of the identity. e.g. arn:aws:sts::123456789012:assumed-role/deploy/session
+	ARN string `json:"arn"`
+	// UserID is the unique identifier of the identity.
+	UserID string `json:"user_id"`
+}
+
+// Root returns whether the identity is the root user of the account.
+func (c CallerIdentity) Root() bool {
+	return strings.HasSuffix(c.ARN, ":root")
+}
+
+// PrincipalARN returns the ARN of the IAM user or the IAM role that the identity belongs to.
+// The assumed role session is converted to the role. e.g. arn:aws:iam::123456789012:role/deploy
+// The second return value is false if the identity is not an IAM user or an IAM role (e.g. the root user, the federated user).
+// The path of the role is not in the assumed role ARN, so the role with a path is not converted correctly.
+func (c CallerIdentity) PrincipalARN() (string, bool) {
+	// arn:partition:service:region:account:resource
+	parts := strings.SplitN(c.ARN, ":", 6)
+	if len(parts) != 6 {
+		return "", false
+	}
+	switch {
+	case parts[2] == "iam" && strings.HasPrefix(parts[5], "user/"):
+		return c.ARN, true
+	case parts[2] == "iam" && strings.HasPrefix(parts[5], "role/"):
+		return c.ARN, true
+	case parts[2] == "sts" && strings.HasPrefix(parts[5], "assumed-role/"):
+		fields := strings.Split(parts[5], "/")
+		if len(fields) < 2 || fields[1] == "" {
+			return "", false
+		}
+		return Partition(parts[1]).ARN("iam", "", parts[4], "role/"+fields[1]), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import "testing"
+
+func TestCallerIdentityPrincipalARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		arn      string
+		want     string
+		wantOK   bool
+		wantRoot bool
+	}{
+		{
+			name:   "iam user",
+			arn:    "arn:aws:iam::123456789012:user/alice",
+			want:   "arn:aws:iam::123456789012:user/alice",
+			wantOK: true,
+		},
+		{
+			name:   "assumed role is converted to the role",
+			arn:    "arn:aws:sts::123456789012:assumed-role/deploy/github-actions",
+			want:   "arn:aws:iam::123456789012:role/deploy",
+			wantOK: true,
+		},
+		{
+			name:   "assumed role in the China partition",
+			arn:    "arn:aws-cn:sts::123456789012:assumed-role/deploy/session",
+			want:   "arn:aws-cn:iam::123456789012:role/deploy",
+			wantOK: true,
+		},
+		{
+			name:     "root user can not be simulated",
+			arn:      "arn:aws:iam::123456789012:root",
+			want:     "",
+			wantOK:   false,
+			wantRoot: true,
+		},
+		{
+			name:   "federated user can not be simulated",
+			arn:    "arn:aws:sts::123456789012:federated-user/bob",
+			want:   "",
+			wantOK: false,
+		},
+		{
+			name:   "invalid arn",
+			arn:    "not-an-arn",
+			want:   "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := CallerIdentity{Account: "123456789012", ARN: tt.arn}
+			got, ok := c.PrincipalARN()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CallerIdentity.PrincipalARN() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+			if c.Root() != tt.wantRoot {
+				t.Errorf("CallerIdentity.Root() = %v, want %v", c.Root(), tt.wantRoot)
+			}
+		})
+	}
+}
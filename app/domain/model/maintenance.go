@@ -0,0 +1,178 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// MaintenanceKey is the S3 key of the maintenance mode state. The object exists only while the maintenance mode is on.
+const MaintenanceKey = "_spare/maintenance.json"
+
+// MaintenancePagePath is the path of the maintenance page in each release. e.g. releases/20231019T120000Z/_spare/maintenance.html
+// The viewer request function rewrites the requests to it, because CloudFront Function can not change the origin path.
+const MaintenancePagePath = "_spare/maintenance.html"
+
+// maintenanceMarkerHeader is the object metadata of the maintenance page.
+// The viewer response function changes the status code of the responses that have it.
+const maintenanceMarkerHeader = "X-Amz-Meta-Spare-Maintenance"
+
+// MaintenancePageHeaders returns the headers of the maintenance page object.
+func MaintenancePageHeaders() []HTTPHeader {
+	return []HTTPHeader{
+		{Name: "Content-Type", Value: "text/html; charset=utf-8"},
+		{Name: maintenanceMarkerHeader, Value: "true"},
+	}
+}
+
+// Maintenance is a type that represents the maintenance mode. While it's on, CloudFront returns the maintenance page
+// with 503 Service Unavailable to all viewers except the allowed IP addresses and the viewers with the bypass cookie.
+type Maintenance struct {
+	// RetryAfter is the value of the Retry-After header in seconds.
+	RetryAfter int64 `json:"retry_after"`
+	// AllowIPs is the list of IP addresses or IPv4 CIDRs that can access the site.
+	AllowIPs []string `json:"allow_ips"`
+	// BypassCookie is the name of the cookie that bypasses the maintenance mode.
+	BypassCookie string `json:"bypass_cookie"`
+	// BypassToken is the value of the bypass cookie. If it's empty, the cookie can not bypass the maintenance mode.
+	BypassToken string `json:"bypass_token"`
+	// User is the name of the user who turned on the maintenance mode.
+	User string `json:"user"`
+	// StartedAt is the time when the maintenance mode was turned on.
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ParseMaintenance parses the JSON representation of Maintenance.
+func ParseMaintenance(data []byte) (*Maintenance, error) {
+	m := &Maintenance{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errfmt.Wrap(err, "failed to unmarshal maintenance mode")
+	}
+	return m, nil
+}
+
+// String returns the JSON representation of Maintenance.
+func (m *Maintenance) String() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal maintenance mode")
+	}
+	return string(data), nil
+}
+
+var maintenanceTokenRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates Maintenance. If Maintenance is invalid, it returns an error.
+func (m *Maintenance) Validate() error {
+	if m.RetryAfter <= 0 {
+		return errfmt.Wrap(ErrInvalidMaintenance, fmt.Sprintf("retry after must be greater than 0: %d", m.RetryAfter))
+	}
+	for _, ip := range m.AllowIPs {
+		if err := validateMaintenanceAllowIP(ip); err != nil {
+			return err
+		}
+	}
+	maintenanceTokenRegexPattern.InitOnce(`^[A-Za-z0-9_-]*$`)
+	if m.BypassCookie == "" || maintenanceTokenRegexPattern.MatchString(m.BypassCookie) != nil {
+		return errfmt.Wrap(ErrInvalidMaintenance,
+			fmt.Sprintf("bypass cookie name must use only letters, numbers, '_' and '-': %s", m.BypassCookie))
+	}
+	if err := maintenanceTokenRegexPattern.MatchString(m.BypassToken); err != nil {
+		return errfmt.Wrap(ErrInvalidMaintenance, "bypass token must use only letters, numbers, '_' and '-'")
+	}
+	return nil
+}
+
+// validateMaintenanceAllowIP validates the IP address or the IPv4 CIDR.
+// IPv6 CIDRs are not supported, because CloudFront Function compares the addresses as 32-bit numbers.
+func validateMaintenanceAllowIP(s string) error {
+	if !strings.Contains(s, "/") {
+		if net.ParseIP(s) == nil {
+			return errfmt.Wrap(ErrInvalidMaintenance, fmt.Sprintf("%s is not an IP address", s))
+		}
+		return nil
+	}
+	ip, _, err := net.ParseCIDR(s)
+	if err != nil {
+		return errfmt.Wrap(ErrInvalidMaintenance, fmt.Sprintf("%s is not a CIDR", s))
+	}
+	if ip.To4() == nil {
+		return errfmt.Wrap(ErrInvalidMaintenance, fmt.Sprintf("IPv6 CIDR %s is not supported. use IPv6 addresses", s))
+	}
+	return nil
+}
+
+// NewMaintenanceResponseFunction returns the CloudFront Function that runs on viewer responses while the maintenance mode is on.
+// It changes the response of the maintenance page to 503 Service Unavailable with the Retry-After header.
+func NewMaintenanceResponseFunction(bucket BucketName, m *Maintenance) *CDNFunction {
+	marker := strings.ToLower(maintenanceMarkerHeader)
+	return &CDNFunction{
+		Name:    NewCDNFunctionName("maintenance", bucket),
+		Comment: "Maintenance mode generated by spare",
+		Code: `function handler(event) {
+    var response = event.response;
+    if (!response.headers[` + jsLiteral(marker) + `]) {
+        return response;
+    }
+    delete response.headers[` + jsLiteral(marker) + `];
+    response.statusCode = 503;
+    response.statusDescription = 'Service Unavailable';
+    response.headers['retry-after'] = { value: ` + jsLiteral(fmt.Sprint(m.RetryAfter)) + ` };
+    response.headers['cache-control'] = { value: 'no-store' };
+    return response;
+}
+`,
+	}
+}
+
+// addMaintenance adds the maintenance mode. If m is nil, it does nothing.
+// The requests from the viewers who can not bypass it are rewritten to the maintenance page,
+// and the other features (e.g. basic auth) are skipped for them.
+func (f *viewerFunction) addMaintenance(m *Maintenance) {
+	if m == nil {
+		return
+	}
+	f.add(`var maintenanceAllowIPs = `+jsLiteral(m.AllowIPs)+`;
+var maintenanceCookie = `+jsLiteral(m.BypassCookie)+`;
+var maintenanceToken = `+jsLiteral(m.BypassToken)+`;
+function ipv4(ip) {
+    var parts = ip.split('.');
+    if (parts.length !== 4) {
+        return -1;
+    }
+    return ((parts[0] << 24) >>> 0) + (parts[1] << 16) + (parts[2] << 8) + (+parts[3]);
+}
+function maintenanceBypass(request, ip) {
+    var cookie = request.cookies[maintenanceCookie];
+    if (maintenanceToken !== '' && cookie && cookie.value === maintenanceToken) {
+        return true;
+    }
+    for (var i = 0; i < maintenanceAllowIPs.length; i++) {
+        var allow = maintenanceAllowIPs[i];
+        var slash = allow.indexOf('/');
+        if (slash === -1) {
+            if (allow === ip) {
+                return true;
+            }
+            continue;
+        }
+        var addr = ipv4(ip);
+        var bits = +allow.substring(slash + 1);
+        var mask = bits === 0 ? 0 : (~0 << (32 - bits)) >>> 0;
+        if (addr !== -1 && ((addr & mask) >>> 0) === ((ipv4(allow.substring(0, slash)) & mask) >>> 0)) {
+            return true;
+        }
+    }
+    return false;
+}
+`, `    if (!maintenanceBypass(request, event.viewer.ip)) {
+        request.uri = '/`+MaintenancePagePath+`';
+        return request;
+    }
+`)
+}
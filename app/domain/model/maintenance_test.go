@@ -0,0 +1,123 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaintenanceValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		m       *Maintenance
+		wantErr bool
+	}{
+		{
+			name: "success",
+			m: &Maintenance{
+				RetryAfter:   3600,
+				AllowIPs:     []string{"203.0.113.1", "198.51.100.0/24", "2001:db8::1"},
+				BypassCookie: "spare_maintenance_bypass",
+				BypassToken:  "abc123",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "success. no bypass token",
+			m:       &Maintenance{RetryAfter: 3600, AllowIPs: []string{}, BypassCookie: "bypass"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. retry after is zero",
+			m:       &Maintenance{RetryAfter: 0, AllowIPs: []string{}, BypassCookie: "bypass"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid IP address",
+			m:       &Maintenance{RetryAfter: 3600, AllowIPs: []string{"203.0.113"}, BypassCookie: "bypass"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. IPv6 CIDR",
+			m:       &Maintenance{RetryAfter: 3600, AllowIPs: []string{"2001:db8::/32"}, BypassCookie: "bypass"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid cookie name",
+			m:       &Maintenance{RetryAfter: 3600, AllowIPs: []string{}, BypassCookie: "by pass"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid bypass token",
+			m:       &Maintenance{RetryAfter: 3600, AllowIPs: []string{}, BypassCookie: "bypass", BypassToken: "';alert(1);'"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Maintenance.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMaintenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal and unmarshal", func(t *testing.T) {
+		t.Parallel()
+		want := &Maintenance{
+			RetryAfter:   600,
+			AllowIPs:     []string{"203.0.113.0/24"},
+			BypassCookie: "bypass",
+			BypassToken:  "abc123",
+			User:         "alice",
+			StartedAt:    time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC),
+		}
+		data, err := want.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseMaintenance([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ParseMaintenance([]byte("{")); err == nil {
+			t.Error("ParseMaintenance() error = nil, want error")
+		}
+	})
+}
+
+func TestNewMaintenanceResponseFunction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("503 with Retry-After", func(t *testing.T) {
+		t.Parallel()
+		got := NewMaintenanceResponseFunction("my-bucket", &Maintenance{RetryAfter: 600})
+		if got.Name != "spare-maintenance-my-bucket" {
+			t.Errorf("NewMaintenanceResponseFunction().Name = %v, want spare-maintenance-my-bucket", got.Name)
+		}
+		for _, s := range []string{
+			`response.headers["x-amz-meta-spare-maintenance"]`,
+			"response.statusCode = 503;",
+			`response.headers['retry-after'] = { value: "600" };`,
+		} {
+			if !strings.Contains(got.Code, s) {
+				t.Errorf("NewMaintenanceResponseFunction().Code does not contain %q:\n%s", s, got.Code)
+			}
+		}
+	})
+}
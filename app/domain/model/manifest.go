@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ReleaseManifestPrefix is the S3 key prefix of the release manifests.
+// The manifests are stored outside the release prefix, so CloudFront never serves them.
+const ReleaseManifestPrefix = "_spare/manifests/"
+
+// ReleaseManifest is a type that represents the list of the S3 keys that a release uploads.
+// deploy puts the manifest before it uploads the files, so 'spare gc' never deletes the files
+// of a deploy in progress, however long the deploy takes.
+type ReleaseManifest struct {
+	// ID is the identifier of the release.
+	ID ReleaseID `json:"id"`
+	// Keys is the list of the S3 keys of the release. e.g. releases/20231019T120000Z-k3x9q2/index.html
+	Keys []string `json:"keys"`
+}
+
+// NewReleaseManifest returns a new ReleaseManifest.
+func NewReleaseManifest(id ReleaseID, keys []string) *ReleaseManifest {
+	return &ReleaseManifest{
+		ID:   id,
+		Keys: keys,
+	}
+}
+
+// ParseReleaseManifest parses the JSON representation of ReleaseManifest.
+func ParseReleaseManifest(data []byte) (*ReleaseManifest, error) {
+	manifest := &ReleaseManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errfmt.Wrap(err, "failed to unmarshal release manifest")
+	}
+	return manifest, nil
+}
+
+// String returns the JSON representation of ReleaseManifest.
+func (m *ReleaseManifest) String() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal release manifest")
+	}
+	return string(data), nil
+}
+
+// Key returns the S3 key of the manifest. e.g. _spare/manifests/20231019T120000Z-k3x9q2.json
+func (m *ReleaseManifest) Key() string {
+	return ReleaseManifestKey(m.ID)
+}
+
+// ReleaseManifestKey returns the S3 key of the manifest of the release.
+func ReleaseManifestKey(id ReleaseID) string {
+	return ReleaseManifestPrefix + id.String() + ".json"
+}
+
+// ReleaseIDFromManifestKey returns the release ID of the manifest key.
+// If the key is not a manifest key, it returns false.
+func ReleaseIDFromManifestKey(key string) (ReleaseID, bool) {
+	if !strings.HasPrefix(key, ReleaseManifestPrefix) || !strings.HasSuffix(key, ".json") {
+		return "", false
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(key, ReleaseManifestPrefix), ".json")
+	if id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+	return ReleaseID(id), true
+}
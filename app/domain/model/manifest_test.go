@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReleaseManifestString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		m := NewReleaseManifest(testReleaseID1, []string{"releases/20231019T120000Z/index.html"})
+		got, err := m.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"id":"20231019T120000Z","keys":["releases/20231019T120000Z/index.html"]}`
+		if got != want {
+			t.Errorf("ReleaseManifest.String() = %v, want %v", got, want)
+		}
+
+		parsed, err := ParseReleaseManifest([]byte(got))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(m, parsed); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if m.Key() != "_spare/manifests/20231019T120000Z.json" {
+			t.Errorf("ReleaseManifest.Key() = %v", m.Key())
+		}
+	})
+}
+
+func TestReleaseIDFromManifestKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		key    string
+		want   ReleaseID
+		wantOK bool
+	}{
+		{
+			name:   "success",
+			key:    "_spare/manifests/20231019T120000Z.json",
+			want:   testReleaseID1,
+			wantOK: true,
+		},
+		{
+			name:   "failure. key is not under manifests",
+			key:    "_spare/releases.json",
+			want:   "",
+			wantOK: false,
+		},
+		{
+			name:   "failure. key is not json",
+			key:    "_spare/manifests/20231019T120000Z",
+			want:   "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ReleaseIDFromManifestKey(tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReleaseIDFromManifestKey() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// allOrigins is the origin that allows all origins.
+	allOrigins = "*"
+	// maxPort is the maximum TCP port number.
+	maxPort = 65535
+)
+
+// defaultPorts is the port of each scheme that browsers omit from the Origin header.
+var defaultPorts = map[string]int{"http": 80, "https": 443} //nolint:gochecknoglobals,gomnd
+
+// Origin is the origin of the cross-origin requests (RFC 6454). e.g. https://example.com, http://localhost:3000
+type Origin struct {
+	// Scheme is http or https.
+	Scheme string
+	// Host is the host name in the ASCII form or the IP address. The leftmost label can be the wildcard "*".
+	// If Host is "*", the Origin matches all origins.
+	Host Domain
+	// Port is the port number. 0 means the default port of the scheme.
+	Port int
+}
+
+// ParseOrigin parses the origin in the form of [SCHEME://]HOST[:PORT]. The scheme is http or https,
+// and https is used if it's omitted. The host is converted to the ASCII form, and the default port of
+// the scheme is omitted, because browsers send the Origin header in that form. "*" is the origin that matches all origins.
+func ParseOrigin(s string) (Origin, error) {
+	if s == allOrigins {
+		return Origin{Host: allOrigins}, nil
+	}
+
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Origin{}, errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("origin %s is invalid: %s", s, err.Error()))
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[scheme]; !ok {
+		return Origin{}, errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("scheme of origin %s must be http or https", s))
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return Origin{}, errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("origin %s must not have user info, path, query or fragment", s))
+	}
+
+	origin := Origin{Scheme: scheme}
+	if port := u.Port(); port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > maxPort {
+			return Origin{}, errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("port of origin %s must be between 1 and %d", s, maxPort))
+		}
+		if n != defaultPorts[scheme] {
+			origin.Port = n
+		}
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		origin.Host = Domain(ip.String())
+		return origin, nil
+	}
+	if host == "" {
+		return Origin{}, errfmt.Wrap(ErrInvalidDomain, fmt.Sprintf("host of origin %s is empty", s))
+	}
+	ascii, err := Domain(host).ASCII()
+	if err != nil {
+		return Origin{}, errfmt.Wrap(err, "origin "+s)
+	}
+	origin.Host = ascii
+	return origin, nil
+}
+
+// All returns true if the Origin matches all origins.
+func (o Origin) All() bool {
+	return o.Host == allOrigins
+}
+
+// String returns the serialized origin. e.g. https://example.com, http://[::1]:3000
+func (o Origin) String() string {
+	if o.All() {
+		return allOrigins
+	}
+	host := o.Host.String()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if o.Port != 0 {
+		host = net.JoinHostPort(o.Host.String(), strconv.Itoa(o.Port))
+	}
+	return fmt.Sprintf("%s://%s", o.Scheme, host)
+}
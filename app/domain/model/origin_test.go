@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		s          string
+		want       Origin
+		wantString string
+		wantErr    error
+	}{
+		{
+			name:       "success. scheme is omitted",
+			s:          exampleCom,
+			want:       Origin{Scheme: "https", Host: exampleCom},
+			wantString: exampleComWithProtocol,
+			wantErr:    nil,
+		},
+		{
+			name:       "success. http with port",
+			s:          "http://localhost:3000",
+			want:       Origin{Scheme: "http", Host: "localhost", Port: 3000},
+			wantString: "http://localhost:3000",
+			wantErr:    nil,
+		},
+		{
+			name:       "success. default port is omitted",
+			s:          "HTTPS://My-App.Example.com:443",
+			want:       Origin{Scheme: "https", Host: "my-app.example.com"},
+			wantString: "https://my-app.example.com",
+			wantErr:    nil,
+		},
+		{
+			name:       "success. internationalized domain name is converted to punycode",
+			s:          "https://例え.jp",
+			want:       Origin{Scheme: "https", Host: "xn--r8jz45g.jp"},
+			wantString: "https://xn--r8jz45g.jp",
+			wantErr:    nil,
+		},
+		{
+			name:       "success. wildcard",
+			s:          "*.example.com",
+			want:       Origin{Scheme: "https", Host: "*.example.com"},
+			wantString: "https://*.example.com",
+			wantErr:    nil,
+		},
+		{
+			name:       "success. IPv6",
+			s:          "http://[::1]:8080",
+			want:       Origin{Scheme: "http", Host: "::1", Port: 8080},
+			wantString: "http://[::1]:8080",
+			wantErr:    nil,
+		},
+		{
+			name:       "success. all origins",
+			s:          "*",
+			want:       Origin{Host: "*"},
+			wantString: "*",
+			wantErr:    nil,
+		},
+		{
+			name:    "failure. scheme is not http or https",
+			s:       "ftp://example.com",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. port is out of range",
+			s:       "https://example.com:0",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. origin has path",
+			s:       "https://example.com/app",
+			wantErr: ErrInvalidDomain,
+		},
+		{
+			name:    "failure. host has an empty label",
+			s:       "https://a..example.com",
+			wantErr: ErrInvalidDomain,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseOrigin(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseOrigin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("Origin.String() = %s, want %s", got.String(), tt.wantString)
+			}
+		})
+	}
+}
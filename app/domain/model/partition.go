@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Partition is the AWS partition. A partition is a group of regions that has its own ARN prefix,
+// DNS suffix and set of services.
+type Partition string
+
+const (
+	// PartitionAWS is the commercial partition.
+	PartitionAWS Partition = "aws"
+	// PartitionAWSCN is the China partition (Beijing and Ningxia).
+	PartitionAWSCN Partition = "aws-cn"
+	// PartitionAWSUSGov is the AWS GovCloud (US) partition.
+	PartitionAWSUSGov Partition = "aws-us-gov"
+)
+
+// String returns the string representation of the Partition.
+func (p Partition) String() string {
+	return string(p)
+}
+
+// DNSSuffix returns the DNS suffix of the service endpoints in the Partition.
+func (p Partition) DNSSuffix() string {
+	if p == PartitionAWSCN {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// ARN returns the ARN of the resource in the Partition. region and account are empty for
+// the resources that are global in the partition (e.g. S3 buckets).
+func (p Partition) ARN(service, region, account, resource string) string {
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s", p, service, region, account, resource)
+}
+
+// GlobalRegion returns the region where the global services of the Partition (CloudFront, the web ACLs
+// for CloudFront and their tags) are managed.
+func (p Partition) GlobalRegion() Region {
+	switch p {
+	case PartitionAWSCN:
+		return RegionCNNorthwest1
+	case PartitionAWSUSGov:
+		return RegionUSGovWest1
+	default:
+		return RegionUSEast1
+	}
+}
+
+// CloudFrontAvailable returns whether CloudFront is available in the Partition.
+// AWS GovCloud (US) does not have CloudFront.
+func (p Partition) CloudFrontAvailable() bool {
+	return p != PartitionAWSUSGov
+}
+
+// Partition returns the partition that the Region belongs to.
+func (r Region) Partition() Partition {
+	switch {
+	case strings.HasPrefix(r.String(), "cn-"):
+		return PartitionAWSCN
+	case strings.HasPrefix(r.String(), "us-gov-"):
+		return PartitionAWSUSGov
+	default:
+		return PartitionAWS
+	}
+}
+
+// ARNPartition returns the partition of the ARN. e.g. aws for arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE
+// If the ARN is invalid, it returns PartitionAWS.
+func ARNPartition(arn string) Partition {
+	fields := strings.Split(arn, ":")
+	if len(fields) < 2 || fields[0] != "arn" || fields[1] == "" {
+		return PartitionAWS
+	}
+	return Partition(fields[1])
+}
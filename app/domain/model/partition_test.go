@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestRegionPartition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		r                Region
+		want             Partition
+		wantDNSSuffix    string
+		wantGlobalRegion Region
+		wantCloudFront   bool
+	}{
+		{
+			name:             "aws partition",
+			r:                RegionAPNortheast1,
+			want:             PartitionAWS,
+			wantDNSSuffix:    "amazonaws.com",
+			wantGlobalRegion: RegionUSEast1,
+			wantCloudFront:   true,
+		},
+		{
+			name:             "aws-cn partition",
+			r:                RegionCNNorth1,
+			want:             PartitionAWSCN,
+			wantDNSSuffix:    "amazonaws.com.cn",
+			wantGlobalRegion: RegionCNNorthwest1,
+			wantCloudFront:   true,
+		},
+		{
+			name:             "aws-us-gov partition does not have CloudFront",
+			r:                RegionUSGovEast1,
+			want:             PartitionAWSUSGov,
+			wantDNSSuffix:    "amazonaws.com",
+			wantGlobalRegion: RegionUSGovWest1,
+			wantCloudFront:   false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.r.Partition()
+			if got != tt.want {
+				t.Errorf("Region.Partition() = %v, want %v", got, tt.want)
+			}
+			if got.DNSSuffix() != tt.wantDNSSuffix {
+				t.Errorf("Partition.DNSSuffix() = %v, want %v", got.DNSSuffix(), tt.wantDNSSuffix)
+			}
+			if got.GlobalRegion() != tt.wantGlobalRegion {
+				t.Errorf("Partition.GlobalRegion() = %v, want %v", got.GlobalRegion(), tt.wantGlobalRegion)
+			}
+			if got.CloudFrontAvailable() != tt.wantCloudFront {
+				t.Errorf("Partition.CloudFrontAvailable() = %v, want %v", got.CloudFrontAvailable(), tt.wantCloudFront)
+			}
+		})
+	}
+}
+
+func TestPartitionARN(t *testing.T) {
+	t.Parallel()
+
+	got := PartitionAWSCN.ARN("kms", "cn-north-1", "123456789012", "key/abc")
+	if want := "arn:aws-cn:kms:cn-north-1:123456789012:key/abc"; got != want {
+		t.Errorf("Partition.ARN() = %v, want %v", got, want)
+	}
+}
+
+func TestARNPartition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn  string
+		want Partition
+	}{
+		{arn: "arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE", want: PartitionAWS},
+		{arn: "arn:aws-cn:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE", want: PartitionAWSCN},
+		{arn: "invalid", want: PartitionAWS},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+			if got := ARNPartition(tt.arn); got != tt.want {
+				t.Errorf("ARNPartition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
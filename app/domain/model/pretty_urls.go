@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// TrailingSlash is a type that represents how the viewer request function normalizes the trailing slash.
+type TrailingSlash string
+
+const (
+	// TrailingSlashIgnore serves both /about and /about/ without redirecting.
+	TrailingSlashIgnore TrailingSlash = "ignore"
+	// TrailingSlashAlways redirects /about to /about/.
+	TrailingSlashAlways TrailingSlash = "always"
+	// TrailingSlashNever redirects /about/ to /about.
+	TrailingSlashNever TrailingSlash = "never"
+)
+
+// String returns the string representation of TrailingSlash.
+func (t TrailingSlash) String() string {
+	return string(t)
+}
+
+// Validate validates TrailingSlash. If TrailingSlash is invalid, it returns an error.
+func (t TrailingSlash) Validate() error {
+	switch t {
+	case TrailingSlashIgnore, TrailingSlashAlways, TrailingSlashNever:
+		return nil
+	default:
+		return errfmt.Wrap(ErrInvalidPrettyURLs,
+			fmt.Sprintf("trailing slash must be %s, %s or %s: %s", TrailingSlashIgnore, TrailingSlashAlways, TrailingSlashNever, t))
+	}
+}
+
+// PrettyURLs is a type that represents the rewrite of the extensionless paths and the directory paths.
+// CloudFront Function can not check whether the object exists, so the paths are rewritten by the convention:
+// /about is served from about.html, and /docs/ is served from docs/index.html. With TrailingSlashIgnore,
+// the directory pages without <PATH>.html (e.g. docs/intro/index.html of Astro) are embedded in the function,
+// so /docs/intro is served from docs/intro/index.html, too.
+type PrettyURLs struct {
+	// TrailingSlash is how the trailing slash is normalized.
+	TrailingSlash TrailingSlash `json:"trailing_slash"`
+	// DirectoryPages is the paths of the pages that have <PATH>/index.html, but no <PATH>.html. e.g. /docs/intro
+	// It's used only with TrailingSlashIgnore.
+	DirectoryPages []string `json:"directory_pages,omitempty"`
+}
+
+// NewPrettyURLs returns a new PrettyURLs for the HTML files in the deploy target.
+// files is the slash-separated paths of the HTML files relative to the deploy target. e.g. about.html, docs/index.html
+func NewPrettyURLs(trailingSlash TrailingSlash, files []string) *PrettyURLs {
+	p := &PrettyURLs{TrailingSlash: trailingSlash}
+	if trailingSlash == TrailingSlashIgnore {
+		p.DirectoryPages = directoryOnlyPages(files)
+	}
+	return p
+}
+
+// directoryOnlyPages returns the paths of the pages that have <PATH>/index.html, but no <PATH>.html.
+func directoryOnlyPages(files []string) []string {
+	exists := make(map[string]bool, len(files))
+	for _, f := range files {
+		exists[f] = true
+	}
+	pages := []string{}
+	for _, f := range files {
+		if f == "index.html" || !strings.HasSuffix(f, "/index.html") {
+			continue
+		}
+		page := strings.TrimSuffix(f, "/index.html")
+		if !exists[page+".html"] {
+			pages = append(pages, "/"+page)
+		}
+	}
+	return pages
+}
+
+// Validate validates PrettyURLs. If PrettyURLs is invalid, it returns an error.
+func (p *PrettyURLs) Validate() error {
+	return p.TrailingSlash.Validate()
+}
+
+// Warnings returns the warnings about the HTML files that the convention can not serve at their pretty URLs.
+// files is the slash-separated paths of the HTML files relative to the deploy target. e.g. about.html, docs/index.html
+// With TrailingSlashIgnore, every page is served, so there is no warning.
+func (p *PrettyURLs) Warnings(files []string) []string {
+	filePages := []string{}
+	dirPages := []string{}
+	for _, f := range files {
+		switch {
+		case f == "index.html":
+			continue
+		case strings.HasSuffix(f, "/index.html"):
+			dirPages = append(dirPages, strings.TrimSuffix(f, "/index.html"))
+		case strings.HasSuffix(f, ".html"):
+			filePages = append(filePages, strings.TrimSuffix(f, ".html"))
+		}
+	}
+
+	switch p.TrailingSlash {
+	case TrailingSlashAlways:
+		if len(filePages) == 0 {
+			return nil
+		}
+		page := filePages[0]
+		return []string{fmt.Sprintf("%d pages (e.g. %s.html) can not be served, because trailing slash is %s and /%s/ is served from %s/index.html. move them to <PATH>/index.html or set trailing slash to %s",
+			len(filePages), page, TrailingSlashAlways, page, page, TrailingSlashIgnore)}
+	case TrailingSlashNever:
+		if len(dirPages) == 0 {
+			return nil
+		}
+		page := dirPages[0]
+		return []string{fmt.Sprintf("%d pages (e.g. %s/index.html) can not be served, because trailing slash is %s and /%s is served from %s.html. move them to <PATH>.html or set trailing slash to %s",
+			len(dirPages), page, TrailingSlashNever, page, page, TrailingSlashIgnore)}
+	default:
+		return nil
+	}
+}
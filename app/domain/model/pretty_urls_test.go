@@ -0,0 +1,123 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrettyURLsValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		p       *PrettyURLs
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			p:       &PrettyURLs{TrailingSlash: TrailingSlashIgnore},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown trailing slash",
+			p:       &PrettyURLs{TrailingSlash: "sometimes"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PrettyURLs.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrettyURLsWarnings(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"index.html", "about.html", "blog/post.html", "docs.html", "docs/index.html", "docs/intro/index.html"}
+	tests := []struct {
+		name         string
+		p            *PrettyURLs
+		files        []string
+		wantContains []string
+	}{
+		{
+			name:  "ignore. directory page without <PATH>.html is embedded in the function",
+			p:     NewPrettyURLs(TrailingSlashIgnore, files),
+			files: files,
+		},
+		{
+			name:         "always. <PATH>.html can not be served",
+			p:            &PrettyURLs{TrailingSlash: TrailingSlashAlways},
+			files:        files,
+			wantContains: []string{"3 pages (e.g. about.html) can not be served"},
+		},
+		{
+			name:         "never. <PATH>/index.html can not be served",
+			p:            &PrettyURLs{TrailingSlash: TrailingSlashNever},
+			files:        files,
+			wantContains: []string{"2 pages (e.g. docs/index.html) can not be served"},
+		},
+		{
+			name:  "ignore. all pages are served",
+			p:     &PrettyURLs{TrailingSlash: TrailingSlashIgnore},
+			files: []string{"index.html", "about.html", "docs.html", "docs/index.html"},
+		},
+		{
+			name:  "never. only the root index.html",
+			p:     &PrettyURLs{TrailingSlash: TrailingSlashNever},
+			files: []string{"index.html", "about.html"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.p.Warnings(tt.files)
+			if len(got) != len(tt.wantContains) {
+				t.Fatalf("PrettyURLs.Warnings() = %v, want %d warnings", got, len(tt.wantContains))
+			}
+			for i, s := range tt.wantContains {
+				if !strings.Contains(got[i], s) {
+					t.Errorf("PrettyURLs.Warnings()[%d] = %q, want to contain %q", i, got[i], s)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPrettyURLs(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"index.html", "about.html", "docs.html", "docs/index.html", "docs/intro/index.html", "blog/index.html"}
+	tests := []struct {
+		name          string
+		trailingSlash TrailingSlash
+		want          *PrettyURLs
+	}{
+		{
+			name:          "ignore. directory pages without <PATH>.html",
+			trailingSlash: TrailingSlashIgnore,
+			want:          &PrettyURLs{TrailingSlash: TrailingSlashIgnore, DirectoryPages: []string{"/docs/intro", "/blog"}},
+		},
+		{
+			name:          "always. every path is served from <PATH>/index.html",
+			trailingSlash: TrailingSlashAlways,
+			want:          &PrettyURLs{TrailingSlash: TrailingSlashAlways},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, NewPrettyURLs(tt.trailingSlash, files)); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// PreviewListKey is the S3 key of the preview list.
+const PreviewListKey = "_spare/previews.json"
+
+// PreviewsRootPrefix is the S3 key prefix that contains all previews.
+const PreviewsRootPrefix = "previews/"
+
+// previewNameMaxLen is the maximum length of PreviewName. It's the same as the DNS label.
+const previewNameMaxLen = 63
+
+// PreviewName is the name of the preview environment. e.g. feature-x
+type PreviewName string
+
+// NewPreviewName converts s (e.g. git branch name) to PreviewName.
+// Uppercase letters are converted to lowercase, and other invalid characters are converted to hyphens.
+// e.g. "Feature/Login_Page" -> "feature-login-page"
+func NewPreviewName(s string) PreviewName {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			continue
+		}
+		if !strings.HasSuffix(b.String(), "-") {
+			b.WriteRune('-')
+		}
+	}
+	name := strings.Trim(b.String(), "-")
+	if len(name) > previewNameMaxLen {
+		name = strings.TrimRight(name[:previewNameMaxLen], "-")
+	}
+	return PreviewName(name)
+}
+
+// String returns the string representation of PreviewName.
+func (p PreviewName) String() string {
+	return string(p)
+}
+
+var previewNameRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates PreviewName. If PreviewName is invalid, it returns an error.
+// PreviewName must use only lowercase letters, numbers and hyphens, and must be 1-63 characters long.
+func (p PreviewName) Validate() error {
+	if p == "" {
+		return errfmt.Wrap(ErrInvalidPreviewName, "preview name is empty")
+	}
+	if len(p) > previewNameMaxLen {
+		return errfmt.Wrap(ErrInvalidPreviewName, fmt.Sprintf("preview name %s is longer than %d characters", p, previewNameMaxLen))
+	}
+	previewNameRegexPattern.InitOnce(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	if err := previewNameRegexPattern.MatchString(p.String()); err != nil {
+		return errfmt.Wrap(ErrInvalidPreviewName,
+			fmt.Sprintf("preview name %s must use only lowercase letters, numbers and hyphens, and must not start or end with a hyphen", p))
+	}
+	return nil
+}
+
+// Prefix returns the S3 key prefix of the preview. e.g. previews/feature-x/
+func (p PreviewName) Prefix() string {
+	return PreviewsRootPrefix + p.String() + "/"
+}
+
+// Path returns the URL path of the preview. e.g. /previews/feature-x/
+func (p PreviewName) Path() string {
+	return "/" + p.Prefix()
+}
+
+// previewURLRegexp matches the URLs in the src and href attributes of HTML and in url() of CSS.
+var previewURLRegexp = regexp.MustCompile(`(?:\b(?:src|href)\s*=\s*["']?|url\(\s*["']?)(/[^"'()\s>]*)`) //nolint:gochecknoglobals
+
+// RootAbsoluteURLs returns the root-absolute URLs (e.g. /assets/app.js) in the HTML or CSS content that are outside the preview.
+// The preview is served under Path(), so the browser gets these URLs from the live release, not from the preview.
+// The SPA must be built with Path() as the base path (e.g. vite --base, PUBLIC_URL). Protocol-relative URLs are not returned.
+func (p PreviewName) RootAbsoluteURLs(content string) []string {
+	urls := make([]string, 0)
+	found := make(map[string]bool)
+	for _, m := range previewURLRegexp.FindAllStringSubmatch(content, -1) {
+		url := m[1]
+		if strings.HasPrefix(url, "//") || strings.HasPrefix(url, p.Path()) || found[url] {
+			continue
+		}
+		found[url] = true
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// Preview is a type that represents a preview environment.
+type Preview struct {
+	// Name is the name of the preview.
+	Name PreviewName `json:"name"`
+	// GitSHA is the git commit hash of the deploy target. It's empty if it can't be detected.
+	GitSHA string `json:"git_sha"`
+	// User is the name of the user who deployed the preview.
+	User string `json:"user"`
+	// UpdatedAt is the time when the preview was deployed last.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PreviewList is a type that represents the list of previews.
+type PreviewList struct {
+	// Previews is the list of previews. It's sorted by Name.
+	Previews []Preview `json:"previews"`
+}
+
+// NewPreviewList returns a new empty PreviewList.
+func NewPreviewList() *PreviewList {
+	return &PreviewList{
+		Previews: []Preview{},
+	}
+}
+
+// ParsePreviewList parses the JSON representation of PreviewList.
+func ParsePreviewList(data []byte) (*PreviewList, error) {
+	list := NewPreviewList()
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, errfmt.Wrap(err, "failed to unmarshal preview list")
+	}
+	list.sort()
+	return list, nil
+}
+
+// String returns the JSON representation of PreviewList.
+func (p *PreviewList) String() (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal preview list")
+	}
+	return string(data), nil
+}
+
+// Upsert adds the preview to the list. If the preview already exists, it's replaced.
+func (p *PreviewList) Upsert(preview Preview) {
+	p.Remove(preview.Name)
+	p.Previews = append(p.Previews, preview)
+	p.sort()
+}
+
+// Remove removes the preview from the list. If the preview does not exist, it does nothing.
+func (p *PreviewList) Remove(name PreviewName) {
+	kept := make([]Preview, 0, len(p.Previews))
+	for _, preview := range p.Previews {
+		if preview.Name != name {
+			kept = append(kept, preview)
+		}
+	}
+	p.Previews = kept
+}
+
+// Find returns the preview whose name is name.
+func (p *PreviewList) Find(name PreviewName) (*Preview, error) {
+	for i := range p.Previews {
+		if p.Previews[i].Name == name {
+			return &p.Previews[i], nil
+		}
+	}
+	return nil, errfmt.Wrap(ErrPreviewNotFound, fmt.Sprintf("preview %s does not exist", name))
+}
+
+// Stale returns the previews that have not been deployed for ttl.
+// If ttl is zero, previews never become stale.
+func (p *PreviewList) Stale(ttl time.Duration, now time.Time) []Preview {
+	stale := make([]Preview, 0)
+	if ttl <= 0 {
+		return stale
+	}
+	for _, preview := range p.Previews {
+		if preview.UpdatedAt.Before(now.Add(-ttl)) {
+			stale = append(stale, preview)
+		}
+	}
+	return stale
+}
+
+// sort sorts the previews by name.
+func (p *PreviewList) sort() {
+	sort.SliceStable(p.Previews, func(i, j int) bool {
+		return p.Previews[i].Name < p.Previews[j].Name
+	})
+}
+
+// NewPreviewRouterFunction returns the CloudFront Function that serves the previews as SPA.
+// The function is associated with the previews/* cache behavior. If auth is not nil,
+// the previews are protected by the basic auth, too.
+//   - /previews/feature-x and /previews/feature-x/ -> /previews/feature-x/index.html
+//   - /previews/feature-x/about (no file extension) -> /previews/feature-x/index.html
+//   - /previews/feature-x/app.js -> /previews/feature-x/app.js
+func NewPreviewRouterFunction(bucket BucketName, auth *BasicAuth) *CDNFunction {
+	f := &viewerFunction{}
+	f.addBasicAuth(auth)
+	f.add("", `    var parts = request.uri.split('/');
+    if (parts.length >= 3 && parts[2] !== '') {
+        var last = parts[parts.length - 1];
+        if (parts.length === 3 || last === '' || last.indexOf('.') === -1) {
+            request.uri = '/previews/' + parts[2] + '/index.html';
+        }
+    }
+`)
+	return &CDNFunction{
+		Name:    NewCDNFunctionName("preview", bucket),
+		Comment: "Preview router generated by spare",
+		Code:    f.code(),
+	}
+}
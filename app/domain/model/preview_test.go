@@ -0,0 +1,211 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewPreviewName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    string
+		want PreviewName
+	}{
+		{
+			name: "success. name is already valid",
+			s:    "feature-x",
+			want: "feature-x",
+		},
+		{
+			name: "success. git branch name",
+			s:    "Feature/Login_Page",
+			want: "feature-login-page",
+		},
+		{
+			name: "success. consecutive invalid characters",
+			s:    "--fix//bug--",
+			want: "fix-bug",
+		},
+		{
+			name: "success. too long",
+			s:    strings.Repeat("a", 62) + "-b",
+			want: PreviewName(strings.Repeat("a", 62)),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewPreviewName(tt.s); got != tt.want {
+				t.Errorf("NewPreviewName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewNameValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		p       PreviewName
+		wantErr error
+	}{
+		{
+			name:    "success",
+			p:       "feature-x",
+			wantErr: nil,
+		},
+		{
+			name:    "failure. name is empty",
+			p:       "",
+			wantErr: ErrInvalidPreviewName,
+		},
+		{
+			name:    "failure. name contains slash",
+			p:       "feature/x",
+			wantErr: ErrInvalidPreviewName,
+		},
+		{
+			name:    "failure. name starts with hyphen",
+			p:       "-x",
+			wantErr: ErrInvalidPreviewName,
+		},
+		{
+			name:    "failure. name is too long",
+			p:       PreviewName(strings.Repeat("a", 64)),
+			wantErr: ErrInvalidPreviewName,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.p.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PreviewName.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreviewNamePrefixAndPath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		p := PreviewName("feature-x")
+		if got := p.Prefix(); got != "previews/feature-x/" {
+			t.Errorf("PreviewName.Prefix() = %v", got)
+		}
+		if got := p.Path(); got != "/previews/feature-x/" {
+			t.Errorf("PreviewName.Path() = %v", got)
+		}
+	})
+}
+
+func TestPreviewNameRootAbsoluteURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "html with root-absolute assets",
+			content: `<link rel="stylesheet" href="/assets/style.css"><script type="module" src='/assets/app.js'></script><img src=/logo.png>`,
+			want:    []string{"/assets/style.css", "/assets/app.js", "/logo.png"},
+		},
+		{
+			name:    "css with root-absolute url",
+			content: `body { background: url("/img/bg.png"); } h1 { background: url(/img/bg.png); }`,
+			want:    []string{"/img/bg.png"},
+		},
+		{
+			name:    "assets under the base path, relative assets and protocol-relative assets",
+			content: `<script src="/previews/feature-x/assets/app.js"></script><script src="./app.js"></script><script src="//cdn.example.com/lib.js"></script>`,
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := PreviewName("feature-x").RootAbsoluteURLs(tt.content)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreviewList(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 21, 0, 0, 0, 0, time.UTC)
+	newList := func() *PreviewList {
+		list := NewPreviewList()
+		list.Upsert(Preview{Name: "b", UpdatedAt: now.Add(-48 * time.Hour)})
+		list.Upsert(Preview{Name: "a", UpdatedAt: now.Add(-1 * time.Hour)})
+		return list
+	}
+
+	t.Run("upsert replaces the preview and keeps the order", func(t *testing.T) {
+		t.Parallel()
+		list := newList()
+		list.Upsert(Preview{Name: "b", GitSHA: "new", UpdatedAt: now})
+
+		want := []Preview{
+			{Name: "a", UpdatedAt: now.Add(-1 * time.Hour)},
+			{Name: "b", GitSHA: "new", UpdatedAt: now},
+		}
+		if diff := cmp.Diff(want, list.Previews); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("stale previews", func(t *testing.T) {
+		t.Parallel()
+		got := newList().Stale(24*time.Hour, now)
+		if len(got) != 1 || got[0].Name != "b" {
+			t.Errorf("PreviewList.Stale() = %v, want [b]", got)
+		}
+		if got := newList().Stale(0, now); len(got) != 0 {
+			t.Errorf("PreviewList.Stale() with zero ttl = %v, want []", got)
+		}
+	})
+
+	t.Run("find and remove", func(t *testing.T) {
+		t.Parallel()
+		list := newList()
+		if _, err := list.Find("a"); err != nil {
+			t.Fatal(err)
+		}
+		list.Remove("a")
+		if _, err := list.Find("a"); !errors.Is(err, ErrPreviewNotFound) {
+			t.Errorf("PreviewList.Find() error = %v, wantErr %v", err, ErrPreviewNotFound)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		want := newList()
+		data, err := want.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParsePreviewList([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// RedirectsFileName is the name of the Netlify-style redirect rules file in the deploy target.
+// The file is not uploaded to S3.
+const RedirectsFileName = "_redirects"
+
+// defaultRedirectStatus is the status code of the redirect rule whose status is omitted.
+const defaultRedirectStatus = 301
+
+// redirectStatuses is the list of status codes that spare supports.
+// 200 rewrites the path, and the others redirect the viewer.
+var redirectStatuses = []int{200, 301, 302, 303, 307, 308} //nolint:gochecknoglobals
+
+// splatParam is the placeholder name of the splat (*) in the redirect rule.
+const splatParam = "splat"
+
+// RedirectRule is a type that represents a rule of the _redirects file.
+// e.g. "/news/:year/*  /blog/:year/:splat  301!"
+type RedirectRule struct {
+	// From is the path pattern. ':NAME' matches a path segment, and the trailing '*' matches the rest of the path.
+	From string `json:"from"`
+	// To is the path or URL. ':NAME' and ':splat' are replaced with the matched values.
+	To string `json:"to"`
+	// Status is the status code. 200 rewrites the path without redirecting.
+	Status int `json:"status"`
+	// Force is whether the rule is applied even if the path is a file.
+	// If it's false, the rule is not applied to the path that has a file extension (e.g. /app.js),
+	// because CloudFront Function can not check whether the object exists.
+	Force bool `json:"force"`
+}
+
+// Redirects is the list of redirect rules. The first matched rule is applied.
+type Redirects []RedirectRule
+
+// ParseRedirects parses the _redirects file. The rules that spare does not support are skipped,
+// and the reasons are returned as warnings. e.g. "_redirects:3: conditions (Country=JP) are not supported"
+func ParseRedirects(r io.Reader) (Redirects, []string, error) {
+	rules := Redirects{}
+	warnings := []string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseRedirectRule(strings.Fields(line))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s. the rule is skipped", RedirectsFileName, n, err.Error()))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errfmt.Wrap(err, fmt.Sprintf("failed to read %s", RedirectsFileName))
+	}
+	return rules, warnings, nil
+}
+
+// parseRedirectRule parses the fields of a line in the _redirects file.
+func parseRedirectRule(fields []string) (RedirectRule, error) {
+	if len(fields) < 2 { //nolint:gomnd
+		return RedirectRule{}, fmt.Errorf("destination of %s is missing", fields[0])
+	}
+	if strings.Contains(fields[1], "=") && !isRedirectDestination(fields[1]) {
+		return RedirectRule{}, fmt.Errorf("query parameter matching (%s) is not supported", fields[1])
+	}
+
+	rule := RedirectRule{From: fields[0], To: fields[1], Status: defaultRedirectStatus}
+	rest := fields[2:]
+	if len(rest) > 0 && !strings.Contains(rest[0], "=") {
+		status, force := strings.CutSuffix(rest[0], "!")
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			return RedirectRule{}, fmt.Errorf("status %s is not a number", rest[0])
+		}
+		rule.Status, rule.Force = code, force
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return RedirectRule{}, fmt.Errorf("conditions (%s) are not supported", strings.Join(rest, " "))
+	}
+	return rule, rule.Validate()
+}
+
+// isRedirectDestination returns true if s is a path or a URL.
+func isRedirectDestination(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// Validate validates RedirectRule. If RedirectRule is not supported, it returns an error.
+func (r RedirectRule) Validate() error {
+	if !strings.HasPrefix(r.From, "/") {
+		return errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("%s is not a path. domain level redirects are not supported", r.From))
+	}
+	if !isRedirectDestination(r.To) {
+		return errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("destination %s must be a path or a URL", r.To))
+	}
+	if !containsInt(redirectStatuses, r.Status) {
+		return errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("status %d is not supported. use one of %v", r.Status, redirectStatuses))
+	}
+	if r.Status == 200 && !strings.HasPrefix(r.To, "/") {
+		return errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("proxy to %s is not supported", r.To))
+	}
+
+	_, params, err := compilePathPattern(r.From)
+	if err != nil {
+		return err
+	}
+	for _, p := range redirectPlaceholders(r.To) {
+		if !contains(params, p) {
+			return errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("placeholder :%s is not in %s", p, r.From))
+		}
+	}
+	return nil
+}
+
+// redirectPlaceholderRegexp matches the placeholder in the destination. e.g. :year
+var redirectPlaceholderRegexp = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`) //nolint:gochecknoglobals
+
+// redirectPlaceholders returns the placeholder names in the destination.
+func redirectPlaceholders(to string) []string {
+	names := []string{}
+	for _, m := range redirectPlaceholderRegexp.FindAllStringSubmatch(to, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// compilePathPattern returns the regular expression of the path pattern and the placeholder names of its groups.
+// ':NAME' matches a path segment, and the trailing '*' matches the rest of the path (the name is "splat").
+// The trailing slash is optional, so "/about" matches "/about/", too.
+// The regular expression is valid in both Go and JavaScript.
+func compilePathPattern(pattern string) (string, []string, error) {
+	segments := strings.Split(pattern, "/")
+	params := []string{}
+	for i, s := range segments {
+		switch {
+		case s == "*" && i == len(segments)-1:
+			segments[i] = "(.*)"
+			params = append(params, splatParam)
+		case strings.Contains(s, "*"):
+			return "", nil, errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("'*' is allowed only at the end of %s", pattern))
+		case strings.HasPrefix(s, ":"):
+			if contains(params, s[1:]) {
+				return "", nil, errfmt.Wrap(ErrInvalidRedirectRule, fmt.Sprintf("placeholder %s is duplicated in %s", s, pattern))
+			}
+			segments[i] = "([^/]+)"
+			params = append(params, s[1:])
+		default:
+			segments[i] = regexp.QuoteMeta(s)
+		}
+	}
+	expr := strings.Join(segments, "/")
+	if !strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, "*") {
+		expr += "/?"
+	}
+	return "^" + expr + "$", params, nil
+}
+
+// redirectFunctionRule is the representation of RedirectRule in the viewer request function.
+type redirectFunctionRule struct {
+	// Pattern is the regular expression of From.
+	Pattern string `json:"pattern"`
+	// To is the list of the string literals and the group numbers of Pattern. They are joined in the function.
+	To []interface{} `json:"to"`
+	// Status is the status code.
+	Status int `json:"status"`
+	// Force is whether the rule is applied to the path that has a file extension.
+	Force bool `json:"force"`
+}
+
+// functionRule returns the representation of the rule in the viewer request function.
+func (r RedirectRule) functionRule() (redirectFunctionRule, error) {
+	pattern, params, err := compilePathPattern(r.From)
+	if err != nil {
+		return redirectFunctionRule{}, err
+	}
+
+	to := []interface{}{}
+	last := 0
+	for _, m := range redirectPlaceholderRegexp.FindAllStringSubmatchIndex(r.To, -1) {
+		group := indexOf(params, r.To[m[2]:m[3]]) + 1
+		if group == 0 {
+			return redirectFunctionRule{}, errfmt.Wrap(ErrInvalidRedirectRule,
+				fmt.Sprintf("placeholder %s is not in %s", r.To[m[0]:m[1]], r.From))
+		}
+		to = append(to, r.To[last:m[0]], group)
+		last = m[1]
+	}
+	to = append(to, r.To[last:])
+	return redirectFunctionRule{Pattern: pattern, To: to, Status: r.Status, Force: r.Force}, nil
+}
+
+// containsInt returns true if list contains n.
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// indexOf returns the index of s in list. If list does not contain s, it returns -1.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRedirects(t *testing.T) {
+	t.Parallel()
+
+	input := `# comment
+/home              /                       301
+/news/:year/*      /blog/:year/:splat      302!
+/*                 /index.html             200
+/old               https://example.com/new
+/store id=:id      /blog/:id               301
+/jp/*              /ja/:splat              302  Country=jp
+/admin/*           /admin/index.html       404
+/api/*             https://api.example.com/:splat  200
+/a/*/b             /c
+/user              /profile/:id
+`
+	rules, warnings, err := ParseRedirects(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRules := Redirects{
+		{From: "/home", To: "/", Status: 301, Force: false},
+		{From: "/news/:year/*", To: "/blog/:year/:splat", Status: 302, Force: true},
+		{From: "/*", To: "/index.html", Status: 200, Force: false},
+		{From: "/old", To: "https://example.com/new", Status: 301, Force: false},
+	}
+	if diff := cmp.Diff(wantRules, rules); diff != "" {
+		t.Errorf("rules are mismatch (-want +got):\n%s", diff)
+	}
+
+	wantWarnings := []string{
+		"_redirects:6: query parameter matching",
+		"_redirects:7: conditions (Country=jp) are not supported",
+		"_redirects:8: invalid redirect rule: status 404 is not supported",
+		"_redirects:9: invalid redirect rule: proxy to https://api.example.com/:splat is not supported",
+		"_redirects:10: invalid redirect rule: '*' is allowed only at the end of /a/*/b",
+		"_redirects:11: invalid redirect rule: placeholder :id is not in /user",
+	}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("warnings = %v, want %d warnings", warnings, len(wantWarnings))
+	}
+	for i, w := range wantWarnings {
+		if !strings.HasPrefix(warnings[i], w) {
+			t.Errorf("warnings[%d] = %q, want prefix %q", i, warnings[i], w)
+		}
+	}
+}
+
+func TestRedirectRuleFunctionRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rule RedirectRule
+		want redirectFunctionRule
+	}{
+		{
+			name: "placeholders and splat",
+			rule: RedirectRule{From: "/news/:year/*", To: "/blog/:year/:splat", Status: 301},
+			want: redirectFunctionRule{
+				Pattern: "^/news/([^/]+)/(.*)$",
+				To:      []interface{}{"/blog/", 1, "/", 2, ""},
+				Status:  301,
+			},
+		},
+		{
+			name: "trailing slash is optional",
+			rule: RedirectRule{From: "/about.us", To: "https://example.com/about", Status: 308, Force: true},
+			want: redirectFunctionRule{
+				Pattern: `^/about\.us/?$`,
+				To:      []interface{}{"https://example.com/about"},
+				Status:  308,
+				Force:   true,
+			},
+		},
+		{
+			name: "root",
+			rule: RedirectRule{From: "/", To: "/en/", Status: 302},
+			want: redirectFunctionRule{
+				Pattern: "^/$",
+				To:      []interface{}{"/en/"},
+				Status:  302,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.rule.functionRule()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
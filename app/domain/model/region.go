@@ -0,0 +1,255 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// Region is the name of the AWS region.
+type Region string
+
+const (
+	// RegionUSEast1 US East (N. Virginia)
+	RegionUSEast1 Region = "us-east-1"
+	// RegionUSEast2 US East (Ohio)
+	RegionUSEast2 Region = "us-east-2"
+	// RegionUSWest1 US West (N. California)
+	RegionUSWest1 Region = "us-west-1"
+	// RegionUSWest2 US West (Oregon)
+	RegionUSWest2 Region = "us-west-2"
+	// RegionAFSouth1 Africa (Cape Town)
+	RegionAFSouth1 Region = "af-south-1"
+	// RegionAPEast1 Asia Pacific (Hong Kong)
+	RegionAPEast1 Region = "ap-east-1"
+	// RegionAPEast2 Asia Pacific (Taipei)
+	RegionAPEast2 Region = "ap-east-2"
+	// RegionAPSouth1 Asia Pacific (Mumbai)
+	RegionAPSouth1 Region = "ap-south-1"
+	// RegionAPSouth2 Asia Pacific (Hyderabad)
+	RegionAPSouth2 Region = "ap-south-2"
+	// RegionAPNortheast1 Asia Pacific (Tokyo)
+	RegionAPNortheast1 Region = "ap-northeast-1"
+	// RegionAPNortheast2 Asia Pacific (Seoul)
+	RegionAPNortheast2 Region = "ap-northeast-2"
+	// RegionAPNortheast3 Asia Pacific (Osaka)
+	RegionAPNortheast3 Region = "ap-northeast-3"
+	// RegionAPSoutheast1 Asia Pacific (Singapore)
+	RegionAPSoutheast1 Region = "ap-southeast-1"
+	// RegionAPSoutheast2 Asia Pacific (Sydney)
+	RegionAPSoutheast2 Region = "ap-southeast-2"
+	// RegionAPSoutheast3 Asia Pacific (Jakarta)
+	RegionAPSoutheast3 Region = "ap-southeast-3"
+	// RegionAPSoutheast4 Asia Pacific (Melbourne)
+	RegionAPSoutheast4 Region = "ap-southeast-4"
+	// RegionAPSoutheast5 Asia Pacific (Malaysia)
+	RegionAPSoutheast5 Region = "ap-southeast-5"
+	// RegionAPSoutheast7 Asia Pacific (Thailand)
+	RegionAPSoutheast7 Region = "ap-southeast-7"
+	// RegionCACentral1 Canada (Central)
+	RegionCACentral1 Region = "ca-central-1"
+	// RegionCAWest1 Canada West (Calgary)
+	RegionCAWest1 Region = "ca-west-1"
+	// RegionCNNorth1 China (Beijing)
+	RegionCNNorth1 Region = "cn-north-1"
+	// RegionCNNorthwest1 China (Ningxia)
+	RegionCNNorthwest1 Region = "cn-northwest-1"
+	// RegionEUCentral1 Europe (Frankfurt)
+	RegionEUCentral1 Region = "eu-central-1"
+	// RegionEUCentral2 Europe (Zurich)
+	RegionEUCentral2 Region = "eu-central-2"
+	// RegionEUNorth1 Europe (Stockholm)
+	RegionEUNorth1 Region = "eu-north-1"
+	// RegionEUSouth1 Europe (Milan)
+	RegionEUSouth1 Region = "eu-south-1"
+	// RegionEUSouth2 Europe (Spain)
+	RegionEUSouth2 Region = "eu-south-2"
+	// RegionEUWest1 Europe (Ireland)
+	RegionEUWest1 Region = "eu-west-1"
+	// RegionEUWest2 Europe (London)
+	RegionEUWest2 Region = "eu-west-2"
+	// RegionEUWest3 Europe (Paris)
+	RegionEUWest3 Region = "eu-west-3"
+	// RegionILCentral1 Israel (Tel Aviv)
+	RegionILCentral1 Region = "il-central-1"
+	// RegionMECentral1 Middle East (UAE)
+	RegionMECentral1 Region = "me-central-1"
+	// RegionMESouth1 Middle East (Bahrain)
+	RegionMESouth1 Region = "me-south-1"
+	// RegionMXCentral1 Mexico (Central)
+	RegionMXCentral1 Region = "mx-central-1"
+	// RegionSAEast1 South America (São Paulo)
+	RegionSAEast1 Region = "sa-east-1"
+	// RegionUSGovEast1 AWS GovCloud (US-East)
+	RegionUSGovEast1 Region = "us-gov-east-1"
+	// RegionUSGovWest1 AWS GovCloud (US-West)
+	RegionUSGovWest1 Region = "us-gov-west-1"
+)
+
+// RegionFeature is a flag of the AWS service that spare uses in the region.
+type RegionFeature uint
+
+const (
+	// RegionFeatureCloudFront is whether CloudFront can serve the bucket in the region.
+	RegionFeatureCloudFront RegionFeature = 1 << iota
+	// RegionFeatureKMS is whether the bucket in the region can be encrypted with SSE-KMS.
+	RegionFeatureKMS
+)
+
+// String returns the name of the AWS service.
+func (f RegionFeature) String() string {
+	switch f {
+	case RegionFeatureCloudFront:
+		return "CloudFront"
+	case RegionFeatureKMS:
+		return "KMS"
+	default:
+		return fmt.Sprintf("RegionFeature(%d)", uint(f))
+	}
+}
+
+// RegionInfo is the metadata of the region in the region catalog.
+type RegionInfo struct {
+	// Region is the name of the region.
+	Region Region
+	// DisplayName is the name of the region in the AWS console (e.g. Asia Pacific (Tokyo)).
+	DisplayName string
+	// Partition is the partition that the region belongs to.
+	Partition Partition
+	// OptIn is whether the region must be enabled in the account before it's used.
+	OptIn bool
+	// Features is the AWS services that spare uses and the region supports.
+	Features RegionFeature
+}
+
+// Supports returns whether the region supports the feature.
+func (i RegionInfo) Supports(f RegionFeature) bool {
+	return i.Features&f == f
+}
+
+// ValidateFeature returns an error if the region does not support the feature.
+func (i RegionInfo) ValidateFeature(f RegionFeature) error {
+	if !i.Supports(f) {
+		return errfmt.Wrap(ErrUnsupportedRegion,
+			fmt.Sprintf("%s is not available in %s (partition %s)", f, i.Region, i.Partition))
+	}
+	return nil
+}
+
+// newRegionInfo returns the RegionInfo whose features are the default of the partition.
+func newRegionInfo(r Region, displayName string, optIn bool) RegionInfo {
+	features := RegionFeatureKMS
+	if r.Partition().CloudFrontAvailable() {
+		features |= RegionFeatureCloudFront
+	}
+	return RegionInfo{
+		Region:      r,
+		DisplayName: displayName,
+		Partition:   r.Partition(),
+		OptIn:       optIn,
+		Features:    features,
+	}
+}
+
+// regionCatalog is the regions that spare knows. The regions launched after this list are accepted
+// with the region override in .spare.yml.
+var regionCatalog = map[Region]RegionInfo{ //nolint:gochecknoglobals
+	RegionUSEast1:      newRegionInfo(RegionUSEast1, "US East (N. Virginia)", false),
+	RegionUSEast2:      newRegionInfo(RegionUSEast2, "US East (Ohio)", false),
+	RegionUSWest1:      newRegionInfo(RegionUSWest1, "US West (N. California)", false),
+	RegionUSWest2:      newRegionInfo(RegionUSWest2, "US West (Oregon)", false),
+	RegionAFSouth1:     newRegionInfo(RegionAFSouth1, "Africa (Cape Town)", true),
+	RegionAPEast1:      newRegionInfo(RegionAPEast1, "Asia Pacific (Hong Kong)", true),
+	RegionAPEast2:      newRegionInfo(RegionAPEast2, "Asia Pacific (Taipei)", true),
+	RegionAPSouth1:     newRegionInfo(RegionAPSouth1, "Asia Pacific (Mumbai)", false),
+	RegionAPSouth2:     newRegionInfo(RegionAPSouth2, "Asia Pacific (Hyderabad)", true),
+	RegionAPNortheast1: newRegionInfo(RegionAPNortheast1, "Asia Pacific (Tokyo)", false),
+	RegionAPNortheast2: newRegionInfo(RegionAPNortheast2, "Asia Pacific (Seoul)", false),
+	RegionAPNortheast3: newRegionInfo(RegionAPNortheast3, "Asia Pacific (Osaka)", false),
+	RegionAPSoutheast1: newRegionInfo(RegionAPSoutheast1, "Asia Pacific (Singapore)", false),
+	RegionAPSoutheast2: newRegionInfo(RegionAPSoutheast2, "Asia Pacific (Sydney)", false),
+	RegionAPSoutheast3: newRegionInfo(RegionAPSoutheast3, "Asia Pacific (Jakarta)", true),
+	RegionAPSoutheast4: newRegionInfo(RegionAPSoutheast4, "Asia Pacific (Melbourne)", true),
+	RegionAPSoutheast5: newRegionInfo(RegionAPSoutheast5, "Asia Pacific (Malaysia)", true),
+	RegionAPSoutheast7: newRegionInfo(RegionAPSoutheast7, "Asia Pacific (Thailand)", true),
+	RegionCACentral1:   newRegionInfo(RegionCACentral1, "Canada (Central)", false),
+	RegionCAWest1:      newRegionInfo(RegionCAWest1, "Canada West (Calgary)", true),
+	RegionCNNorth1:     newRegionInfo(RegionCNNorth1, "China (Beijing)", false),
+	RegionCNNorthwest1: newRegionInfo(RegionCNNorthwest1, "China (Ningxia)", false),
+	RegionEUCentral1:   newRegionInfo(RegionEUCentral1, "Europe (Frankfurt)", false),
+	RegionEUCentral2:   newRegionInfo(RegionEUCentral2, "Europe (Zurich)", true),
+	RegionEUNorth1:     newRegionInfo(RegionEUNorth1, "Europe (Stockholm)", false),
+	RegionEUSouth1:     newRegionInfo(RegionEUSouth1, "Europe (Milan)", true),
+	RegionEUSouth2:     newRegionInfo(RegionEUSouth2, "Europe (Spain)", true),
+	RegionEUWest1:      newRegionInfo(RegionEUWest1, "Europe (Ireland)", false),
+	RegionEUWest2:      newRegionInfo(RegionEUWest2, "Europe (London)", false),
+	RegionEUWest3:      newRegionInfo(RegionEUWest3, "Europe (Paris)", false),
+	RegionILCentral1:   newRegionInfo(RegionILCentral1, "Israel (Tel Aviv)", true),
+	RegionMECentral1:   newRegionInfo(RegionMECentral1, "Middle East (UAE)", true),
+	RegionMESouth1:     newRegionInfo(RegionMESouth1, "Middle East (Bahrain)", true),
+	RegionMXCentral1:   newRegionInfo(RegionMXCentral1, "Mexico (Central)", true),
+	RegionSAEast1:      newRegionInfo(RegionSAEast1, "South America (São Paulo)", false),
+	RegionUSGovEast1:   newRegionInfo(RegionUSGovEast1, "AWS GovCloud (US-East)", false),
+	RegionUSGovWest1:   newRegionInfo(RegionUSGovWest1, "AWS GovCloud (US-West)", false),
+}
+
+// Regions returns the regions in the region catalog sorted by the name.
+func Regions() []RegionInfo {
+	regions := make([]RegionInfo, 0, len(regionCatalog))
+	for _, info := range regionCatalog {
+		regions = append(regions, info)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Region < regions[j].Region
+	})
+	return regions
+}
+
+// NewRegionOverride returns the RegionInfo of the region that is not in the region catalog yet.
+// The features are the default of the partition that the region belongs to.
+func NewRegionOverride(r Region, displayName string, optIn bool) (RegionInfo, error) {
+	if err := r.ValidateFormat(); err != nil {
+		return RegionInfo{}, err
+	}
+	return newRegionInfo(r, displayName, optIn), nil
+}
+
+// Info returns the metadata of the Region in the region catalog. The second return value is false
+// if the Region is not in the region catalog.
+func (r Region) Info() (RegionInfo, bool) {
+	info, ok := regionCatalog[r]
+	return info, ok
+}
+
+// Validate returns an error if the Region is not in the region catalog.
+func (r Region) Validate() error {
+	if r == "" {
+		return ErrEmptyRegion
+	}
+	if _, ok := r.Info(); !ok {
+		return errfmt.Wrap(ErrInvalidRegion, fmt.Sprintf("%s is not in the region catalog", r))
+	}
+	return nil
+}
+
+var regionRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// ValidateFormat returns an error if the Region is not in the form of the region name (e.g. ap-northeast-1).
+// It's used for the regions that are not in the region catalog.
+func (r Region) ValidateFormat() error {
+	if r == "" {
+		return ErrEmptyRegion
+	}
+	regionRegexPattern.InitOnce(`^[a-z]{2}(-gov)?-[a-z]+-[1-9][0-9]*$`)
+	if err := regionRegexPattern.MatchString(r.String()); err != nil {
+		return errfmt.Wrap(ErrInvalidRegion, fmt.Sprintf("%s is not a region name (e.g. ap-northeast-1)", r))
+	}
+	return nil
+}
+
+// String returns the string representation of the Region.
+func (r Region) String() string {
+	return string(r)
+}
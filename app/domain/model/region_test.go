@@ -0,0 +1,178 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegionInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		r              Region
+		wantOK         bool
+		wantOptIn      bool
+		wantPartition  Partition
+		wantCloudFront bool
+	}{
+		{
+			name:           "success. region enabled by default",
+			r:              RegionAPNortheast1,
+			wantOK:         true,
+			wantOptIn:      false,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. opt-in region",
+			r:              RegionILCentral1,
+			wantOK:         true,
+			wantOptIn:      true,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. opt-in region ap-east-2",
+			r:              RegionAPEast2,
+			wantOK:         true,
+			wantOptIn:      true,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. opt-in region ap-southeast-5",
+			r:              RegionAPSoutheast5,
+			wantOK:         true,
+			wantOptIn:      true,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. opt-in region ap-southeast-7",
+			r:              RegionAPSoutheast7,
+			wantOK:         true,
+			wantOptIn:      true,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. opt-in region mx-central-1",
+			r:              RegionMXCentral1,
+			wantOK:         true,
+			wantOptIn:      true,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. sa-east-1",
+			r:              RegionSAEast1,
+			wantOK:         true,
+			wantOptIn:      false,
+			wantPartition:  PartitionAWS,
+			wantCloudFront: true,
+		},
+		{
+			name:           "success. region without CloudFront",
+			r:              RegionUSGovWest1,
+			wantOK:         true,
+			wantOptIn:      false,
+			wantPartition:  PartitionAWSUSGov,
+			wantCloudFront: false,
+		},
+		{
+			name:   "failure. region is not in the catalog",
+			r:      Region("sa-south-1"),
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tt.r.Info()
+			if ok != tt.wantOK {
+				t.Fatalf("Region.Info() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Region != tt.r || got.DisplayName == "" {
+				t.Errorf("Region.Info() = %+v, want the metadata of %s", got, tt.r)
+			}
+			if got.OptIn != tt.wantOptIn {
+				t.Errorf("RegionInfo.OptIn = %v, want %v", got.OptIn, tt.wantOptIn)
+			}
+			if got.Partition != tt.wantPartition {
+				t.Errorf("RegionInfo.Partition = %v, want %v", got.Partition, tt.wantPartition)
+			}
+			if got.Supports(RegionFeatureCloudFront) != tt.wantCloudFront {
+				t.Errorf("RegionInfo.Supports(CloudFront) = %v, want %v", got.Supports(RegionFeatureCloudFront), tt.wantCloudFront)
+			}
+			if err := got.ValidateFeature(RegionFeatureCloudFront); (err == nil) != tt.wantCloudFront {
+				t.Errorf("RegionInfo.ValidateFeature(CloudFront) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	t.Parallel()
+
+	regions := Regions()
+	if len(regions) != len(regionCatalog) {
+		t.Fatalf("len(Regions()) = %d, want %d", len(regions), len(regionCatalog))
+	}
+	for i := 1; i < len(regions); i++ {
+		if regions[i-1].Region >= regions[i].Region {
+			t.Errorf("Regions() is not sorted: %s, %s", regions[i-1].Region, regions[i].Region)
+		}
+	}
+}
+
+func TestNewRegionOverride(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		r       Region
+		wantErr error
+	}{
+		{
+			name:    "success. new region",
+			r:       Region("ap-southeast-9"),
+			wantErr: nil,
+		},
+		{
+			name:    "success. new region in aws-cn",
+			r:       Region("cn-south-1"),
+			wantErr: nil,
+		},
+		{
+			name:    "failure. empty",
+			r:       Region(""),
+			wantErr: ErrEmptyRegion,
+		},
+		{
+			name:    "failure. not a region name",
+			r:       Region("Tokyo"),
+			wantErr: ErrInvalidRegion,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewRegionOverride(tt.r, "New Region", true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRegionOverride() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Partition != tt.r.Partition() || !got.OptIn || !got.Supports(RegionFeatureCloudFront|RegionFeatureKMS) {
+				t.Errorf("NewRegionOverride() = %+v", got)
+			}
+		})
+	}
+}
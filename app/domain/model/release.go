@@ -0,0 +1,221 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xrand"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+// ReleaseHistoryKey is the S3 key of the release history.
+// The release history is stored outside the release prefix, so CloudFront never serves it.
+const ReleaseHistoryKey = "_spare/releases.json"
+
+// releasePrefix is the S3 key prefix under which every release is uploaded.
+const releasePrefix = "releases"
+
+// releaseIDLayout is the time layout of ReleaseID.
+const releaseIDLayout = "20060102T150405Z"
+
+// releaseIDSuffixLen is the length of the random suffix of ReleaseID.
+const releaseIDSuffixLen = 6
+
+// ReleaseID is the identifier of a release. It's the UTC time when the release was created and a random suffix.
+// e.g. 20231019T120000Z-k3x9q2. The suffix keeps two deploys in the same second (e.g. parallel CI jobs) from
+// uploading to the same prefix. The releases deployed before the suffix was added have no suffix.
+type ReleaseID string
+
+// NewReleaseID returns a new ReleaseID generated from t.
+func NewReleaseID(t time.Time) ReleaseID {
+	suffix, err := xrand.RandomLowerAlphanumericStr(releaseIDSuffixLen)
+	if err != nil {
+		// The random source is broken. The nanoseconds still tell the deploys in the same second apart.
+		suffix = fmt.Sprintf("%09d", t.Nanosecond())
+	}
+	return ReleaseID(t.UTC().Format(releaseIDLayout) + "-" + suffix)
+}
+
+// String returns the string representation of ReleaseID.
+func (r ReleaseID) String() string {
+	return string(r)
+}
+
+// Empty is whether ReleaseID is empty.
+func (r ReleaseID) Empty() bool {
+	return r == ""
+}
+
+var releaseIDSuffixRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates ReleaseID. If ReleaseID is invalid, it returns an error.
+func (r ReleaseID) Validate() error {
+	releaseIDSuffixRegexPattern.InitOnce(`^[a-z0-9]{1,16}$`)
+
+	createdAt, suffix, found := strings.Cut(r.String(), "-")
+	if _, err := time.Parse(releaseIDLayout, createdAt); err != nil {
+		return errfmt.Wrap(ErrInvalidReleaseID, fmt.Sprintf("release id %s is invalid", r))
+	}
+	if found {
+		if err := releaseIDSuffixRegexPattern.MatchString(suffix); err != nil {
+			return errfmt.Wrap(ErrInvalidReleaseID, fmt.Sprintf("release id %s is invalid", r))
+		}
+	}
+	return nil
+}
+
+// CreatedAt returns the time in ReleaseID. If ReleaseID is invalid, it returns an error.
+func (r ReleaseID) CreatedAt() (time.Time, error) {
+	if err := r.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	createdAt, _, _ := strings.Cut(r.String(), "-")
+	return time.Parse(releaseIDLayout, createdAt)
+}
+
+// Prefix returns the S3 key prefix of the release. e.g. releases/20231019T120000Z/
+func (r ReleaseID) Prefix() string {
+	return fmt.Sprintf("%s/%s/", releasePrefix, r.String())
+}
+
+// OriginPath returns the CloudFront origin path of the release. e.g. /releases/20231019T120000Z
+func (r ReleaseID) OriginPath() string {
+	return fmt.Sprintf("/%s/%s", releasePrefix, r.String())
+}
+
+// ReleaseState is the state of a release. It tells the releases that 'spare rollback' can switch back to.
+type ReleaseState string
+
+const (
+	// ReleaseStatePending is the release that is recorded, but has never been live
+	// (e.g. the canary release, or the release deployed in debug mode without CloudFront).
+	ReleaseStatePending ReleaseState = "pending"
+	// ReleaseStateReleased is the release that has been live at least once.
+	ReleaseStateReleased ReleaseState = "released"
+	// ReleaseStateAborted is the canary release that has been aborted.
+	ReleaseStateAborted ReleaseState = "aborted"
+)
+
+// Release is a type that represents a deployed version of the SPA.
+type Release struct {
+	// ID is the identifier of the release.
+	ID ReleaseID `json:"id"`
+	// GitSHA is the git commit hash of the deploy target. It's empty if it can't be detected.
+	GitSHA string `json:"git_sha"`
+	// User is the name of the user who deployed the release.
+	User string `json:"user"`
+	// CreatedAt is the time when the release was created.
+	CreatedAt time.Time `json:"created_at"`
+	// State is the state of the release. The releases recorded before the state was added have no state,
+	// and they are regarded as released.
+	State ReleaseState `json:"state,omitempty"`
+}
+
+// Released is whether the release has been live at least once. The pending and aborted releases have not.
+func (r Release) Released() bool {
+	return r.State != ReleaseStatePending && r.State != ReleaseStateAborted
+}
+
+// NewRelease returns a new Release created at t.
+func NewRelease(t time.Time, gitSHA, user string) *Release {
+	return &Release{
+		ID:        NewReleaseID(t),
+		GitSHA:    gitSHA,
+		User:      user,
+		CreatedAt: t.UTC(),
+		State:     ReleaseStatePending,
+	}
+}
+
+// ReleaseHistory is a type that represents the list of releases and the live release.
+type ReleaseHistory struct {
+	// Live is the ID of the release that CloudFront serves.
+	Live ReleaseID `json:"live"`
+	// Staged is the ID of the canary release that CloudFront serves to a part of the viewers.
+	// It's empty if there is no canary release.
+	Staged ReleaseID `json:"staged,omitempty"`
+	// Releases is the list of releases. It's sorted by CreatedAt in ascending order.
+	Releases []Release `json:"releases"`
+}
+
+// NewReleaseHistory returns a new empty ReleaseHistory.
+func NewReleaseHistory() *ReleaseHistory {
+	return &ReleaseHistory{
+		Live:     "",
+		Releases: []Release{},
+	}
+}
+
+// ParseReleaseHistory parses the JSON representation of ReleaseHistory.
+func ParseReleaseHistory(data []byte) (*ReleaseHistory, error) {
+	history := NewReleaseHistory()
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, errfmt.Wrap(err, "failed to unmarshal release history")
+	}
+	history.sort()
+	return history, nil
+}
+
+// String returns the JSON representation of ReleaseHistory.
+func (h *ReleaseHistory) String() (string, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal release history")
+	}
+	return string(data), nil
+}
+
+// Add adds the release to the history. It does not change the live release.
+func (h *ReleaseHistory) Add(r Release) {
+	h.Releases = append(h.Releases, r)
+	h.sort()
+}
+
+// Find returns the release whose ID is id.
+func (h *ReleaseHistory) Find(id ReleaseID) (*Release, error) {
+	for i := range h.Releases {
+		if h.Releases[i].ID == id {
+			return &h.Releases[i], nil
+		}
+	}
+	return nil, errfmt.Wrap(ErrReleaseNotFound, fmt.Sprintf("release %s does not exist", id))
+}
+
+// Previous returns the release that was live just before the live release.
+// The releases that have never been live (e.g. the aborted canary releases) are skipped.
+func (h *ReleaseHistory) Previous() (*Release, error) {
+	for i := range h.Releases {
+		if h.Releases[i].ID != h.Live {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if h.Releases[j].Released() {
+				return &h.Releases[j], nil
+			}
+		}
+		break
+	}
+	return nil, errfmt.Wrap(ErrNoPreviousRelease, fmt.Sprintf("live release is %s", h.Live))
+}
+
+// Activate changes the live release to the release whose ID is id, and marks it as released.
+func (h *ReleaseHistory) Activate(id ReleaseID) error {
+	release, err := h.Find(id)
+	if err != nil {
+		return err
+	}
+	release.State = ReleaseStateReleased
+	h.Live = id
+	return nil
+}
+
+// sort sorts the releases by CreatedAt in ascending order.
+func (h *ReleaseHistory) sort() {
+	sort.SliceStable(h.Releases, func(i, j int) bool {
+		return h.Releases[i].CreatedAt.Before(h.Releases[j].CreatedAt)
+	})
+}
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testReleaseID1 ReleaseID = "20231019T120000Z"
+	testReleaseID2 ReleaseID = "20231020T120000Z"
+	testReleaseID3 ReleaseID = "20231021T120000Z"
+)
+
+func newTestReleaseHistory(t *testing.T, live ReleaseID) *ReleaseHistory {
+	t.Helper()
+
+	h := NewReleaseHistory()
+	for _, id := range []ReleaseID{testReleaseID3, testReleaseID1, testReleaseID2} {
+		createdAt, err := id.CreatedAt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Add(Release{ID: id, GitSHA: "abc", User: "spare", CreatedAt: createdAt})
+	}
+	h.Live = live
+	return h
+}
+
+func TestNewReleaseID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		jst := time.FixedZone("JST", 9*60*60)
+		got := NewReleaseID(time.Date(2023, 10, 19, 21, 0, 0, 0, jst))
+		if !strings.HasPrefix(got.String(), testReleaseID1.String()+"-") {
+			t.Errorf("NewReleaseID() = %v, want %v-<suffix>", got, testReleaseID1)
+		}
+		if err := got.Validate(); err != nil {
+			t.Errorf("NewReleaseID() = %v is invalid: %v", got, err)
+		}
+	})
+
+	t.Run("success. deploys in the same second get different ids", func(t *testing.T) {
+		t.Parallel()
+		now := time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC)
+		if a, b := NewReleaseID(now), NewReleaseID(now); a == b {
+			t.Errorf("NewReleaseID() = %v twice", a)
+		}
+	})
+}
+
+func TestReleaseIDValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		r       ReleaseID
+		wantErr error
+	}{
+		{
+			name:    "success",
+			r:       testReleaseID1,
+			wantErr: nil,
+		},
+		{
+			name:    "success. with suffix",
+			r:       "20231019T120000Z-k3x9q2",
+			wantErr: nil,
+		},
+		{
+			name:    "failure. suffix is not alphanumeric",
+			r:       "20231019T120000Z-../x",
+			wantErr: ErrInvalidReleaseID,
+		},
+		{
+			name:    "failure. release id is empty",
+			r:       "",
+			wantErr: ErrInvalidReleaseID,
+		},
+		{
+			name:    "failure. release id is not time",
+			r:       "../../etc",
+			wantErr: ErrInvalidReleaseID,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.r.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReleaseID.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReleaseIDPrefixAndOriginPath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		if got := testReleaseID1.Prefix(); got != "releases/20231019T120000Z/" {
+			t.Errorf("ReleaseID.Prefix() = %v", got)
+		}
+		if got := testReleaseID1.OriginPath(); got != "/releases/20231019T120000Z" {
+			t.Errorf("ReleaseID.OriginPath() = %v", got)
+		}
+	})
+}
+
+func TestReleaseHistoryAdd(t *testing.T) {
+	t.Parallel()
+
+	t.Run("releases are sorted by created time", func(t *testing.T) {
+		t.Parallel()
+		h := newTestReleaseHistory(t, "")
+
+		got := make([]ReleaseID, 0, len(h.Releases))
+		for _, r := range h.Releases {
+			got = append(got, r.ID)
+		}
+		want := []ReleaseID{testReleaseID1, testReleaseID2, testReleaseID3}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestReleaseHistoryPrevious(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		live    ReleaseID
+		want    ReleaseID
+		wantErr error
+	}{
+		{
+			name:    "success",
+			live:    testReleaseID3,
+			want:    testReleaseID2,
+			wantErr: nil,
+		},
+		{
+			name:    "failure. live release is the oldest",
+			live:    testReleaseID1,
+			want:    "",
+			wantErr: ErrNoPreviousRelease,
+		},
+		{
+			name:    "failure. no live release",
+			live:    "",
+			want:    "",
+			wantErr: ErrNoPreviousRelease,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := newTestReleaseHistory(t, tt.live).Previous()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReleaseHistory.Previous() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != tt.want {
+				t.Errorf("ReleaseHistory.Previous() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseHistoryPreviousSkipsUnreleased(t *testing.T) {
+	t.Parallel()
+
+	newRelease := func(day int) *Release {
+		return NewRelease(time.Date(2023, 10, day, 12, 0, 0, 0, time.UTC), "abc", "spare")
+	}
+
+	t.Run("success. abort, deploy and rollback skips the aborted canary release", func(t *testing.T) {
+		t.Parallel()
+		h := NewReleaseHistory()
+		a, b, c := newRelease(19), newRelease(20), newRelease(21)
+
+		h.Add(*a)
+		if err := h.Activate(a.ID); err != nil {
+			t.Fatal(err)
+		}
+		h.Add(*b)
+		if err := h.Stage(b.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := h.Abort(); err != nil {
+			t.Fatal(err)
+		}
+		h.Add(*c)
+		if err := h.Activate(c.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := h.Previous()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != a.ID {
+			t.Errorf("ReleaseHistory.Previous() = %v, want %v", got.ID, a.ID)
+		}
+		aborted, err := h.Find(b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if aborted.State != ReleaseStateAborted {
+			t.Errorf("state of the aborted release = %s, want %s", aborted.State, ReleaseStateAborted)
+		}
+	})
+
+	t.Run("failure. the release recorded in debug mode has never been live", func(t *testing.T) {
+		t.Parallel()
+		h := NewReleaseHistory()
+		pending, live := newRelease(19), newRelease(20)
+
+		h.Add(*pending)
+		h.Add(*live)
+		if err := h.Activate(live.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := h.Previous(); !errors.Is(err, ErrNoPreviousRelease) {
+			t.Errorf("ReleaseHistory.Previous() error = %v, wantErr %v", err, ErrNoPreviousRelease)
+		}
+	})
+}
+
+func TestReleaseHistoryActivate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       ReleaseID
+		wantLive ReleaseID
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			id:       testReleaseID1,
+			wantLive: testReleaseID1,
+			wantErr:  nil,
+		},
+		{
+			name:     "failure. release does not exist",
+			id:       "20200101T000000Z",
+			wantLive: testReleaseID3,
+			wantErr:  ErrReleaseNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestReleaseHistory(t, testReleaseID3)
+			if err := h.Activate(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReleaseHistory.Activate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if h.Live != tt.wantLive {
+				t.Errorf("ReleaseHistory.Live = %v, want %v", h.Live, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestParseReleaseHistory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success. round trip", func(t *testing.T) {
+		t.Parallel()
+		want := newTestReleaseHistory(t, testReleaseID2)
+		data, err := want.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ParseReleaseHistory([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("failure. invalid json", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ParseReleaseHistory([]byte("{")); err == nil {
+			t.Error("expect error, but got nil")
+		}
+	})
+}
@@ -9,85 +9,6 @@ import (
 	"github.com/nao1215/spare/utils/xregex"
 )
 
-// Region is the name of the AWS region.
-type Region string
-
-const (
-	// RegionUSEast1 US East (N. Virginia)
-	RegionUSEast1 Region = "us-east-1"
-	// RegionUSEast2 US East (Ohio)
-	RegionUSEast2 Region = "us-east-2"
-	// RegionUSWest1 US West (N. California)
-	RegionUSWest1 Region = "us-west-1"
-	// RegionUSWest2 US West (Oregon)
-	RegionUSWest2 Region = "us-west-2"
-	// RegionAFSouth1 Africa (Cape Town)
-	RegionAFSouth1 Region = "af-south-1"
-	// RegionAPEast1 Asia Pacific (Hong Kong)
-	RegionAPEast1 Region = "ap-east-1"
-	// RegionAPSouth1 Asia Pacific (Mumbai)
-	RegionAPSouth1 Region = "ap-south-1"
-	// RegionAPNortheast1 Asia Pacific (Tokyo)
-	RegionAPNortheast1 Region = "ap-northeast-1"
-	// RegionAPNortheast2 Asia Pacific (Seoul)
-	RegionAPNortheast2 Region = "ap-northeast-2"
-	// RegionAPNortheast3 Asia Pacific (Osaka-Local)
-	RegionAPNortheast3 Region = "ap-northeast-3"
-	// RegionAPSoutheast1 Asia Pacific (Singapore)
-	RegionAPSoutheast1 Region = "ap-southeast-1"
-	// RegionAPSoutheast2 Asia Pacific (Sydney)
-	RegionAPSoutheast2 Region = "ap-southeast-2"
-	// RegionCACentral1 Canada (Central)
-	RegionCACentral1 Region = "ca-central-1"
-	// RegionCNNorth1 China (Beijing)
-	RegionCNNorth1 Region = "cn-north-1"
-	// RegionCNNorthwest1 China (Ningxia)
-	RegionCNNorthwest1 Region = "cn-northwest-1"
-	// RegionEUCentral1 Europe (Frankfurt)
-	RegionEUCentral1 Region = "eu-central-1"
-	// RegionEUNorth1 Europe (Stockholm)
-	RegionEUNorth1 Region = "eu-north-1"
-	// RegionEUSouth1 Europe (Milan)
-	RegionEUSouth1 Region = "eu-south-1"
-	// RegionEUWest1 Europe (Ireland)
-	RegionEUWest1 Region = "eu-west-1"
-	// RegionEUWest2 Europe (London)
-	RegionEUWest2 Region = "eu-west-2"
-	// RegionEUWest3 Europe (Paris)
-	RegionEUWest3 Region = "eu-west-3"
-	// RegionMESouth1 Middle East (Bahrain)
-	RegionMESouth1 Region = "me-south-1"
-	// RegionSASouth1 South America (São Paulo)
-	RegionSASouth1 Region = "sa-south-1"
-	// RegionUSGovEast1 AWS GovCloud (US-East)
-	RegionUSGovEast1 Region = "us-gov-east-1"
-	// RegionUSGovWest1 AWS GovCloud (US)
-	RegionUSGovWest1 Region = "us-gov-west-1"
-)
-
-// Validate returns true if the Region exists.
-func (r Region) Validate() error {
-	switch r {
-	case
-		RegionUSEast1, RegionUSEast2, RegionUSWest1, RegionUSWest2, RegionAFSouth1,
-		RegionAPEast1, RegionAPSouth1, RegionAPNortheast1, RegionAPNortheast2,
-		RegionAPNortheast3, RegionAPSoutheast1, RegionAPSoutheast2, RegionCACentral1,
-		RegionCNNorth1, RegionCNNorthwest1, RegionEUCentral1, RegionEUNorth1,
-		RegionEUSouth1, RegionEUWest1, RegionEUWest2, RegionEUWest3, RegionMESouth1,
-		RegionSASouth1, RegionUSGovEast1, RegionUSGovWest1:
-		return nil
-	case Region(""):
-		return ErrEmptyRegion
-	default:
-		return ErrInvalidRegion
-	}
-}
-
-// String returns the string representation of the Region.
-func (r Region) String() string {
-	return string(r)
-}
-
 // BucketName is the name of the S3 bucket.
 type BucketName string
 
@@ -101,9 +22,39 @@ func (b BucketName) Empty() bool {
 	return b == ""
 }
 
-// Domain returns the domain name of the Bucket.
-func (b BucketName) Domain() string {
-	return fmt.Sprintf("%s.s3.amazonaws.com", b.String())
+// Domain returns the regional domain name of the Bucket in the region (e.g. bucket.s3.cn-north-1.amazonaws.com.cn).
+func (b BucketName) Domain(region Region) string {
+	return fmt.Sprintf("%s.s3.%s.%s", b.String(), region.String(), region.Partition().DNSSuffix())
+}
+
+// LegacyDomain returns the global domain name of the Bucket (e.g. bucket.s3.amazonaws.com).
+// It exists only in the aws partition. The distributions created by the older spare use it as the origin domain,
+// and CloudFront standard logs require it as the log bucket.
+func (b BucketName) LegacyDomain(region Region) string {
+	if region.Partition() != PartitionAWS {
+		return b.Domain(region)
+	}
+	return fmt.Sprintf("%s.s3.%s", b.String(), PartitionAWS.DNSSuffix())
+}
+
+// IsDomain returns whether the domain is the regional or the global domain name of the Bucket.
+// The bucket name is unique in the partition, so the domain in any region (including the regions that are
+// not in the region catalog yet) is the Bucket.
+func (b BucketName) IsDomain(domain string) bool {
+	rest, ok := strings.CutPrefix(domain, b.String()+".s3.")
+	if !ok {
+		return false
+	}
+	if rest == PartitionAWS.DNSSuffix() {
+		return true
+	}
+	region, suffix, ok := strings.Cut(rest, ".")
+	return ok && Region(region).ValidateFormat() == nil && suffix == Region(region).Partition().DNSSuffix()
+}
+
+// ARN returns the ARN of the Bucket in the partition.
+func (b BucketName) ARN(partition Partition) string {
+	return partition.ARN("s3", "", "", b.String())
 }
 
 // Validate returns true if the Bucket is valid.
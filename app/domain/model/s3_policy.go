@@ -21,13 +21,16 @@ type Statement struct {
 	Resource []string `json:"Resource"` //nolint
 	// The Condition element (or Condition block) lets you specify conditions for when a policy is in effect.
 	// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_condition.html
-	Condition map[string]map[string]string `json:"Condition,omitempty"` //nolint
+	// The value of a condition key is a string or a list of strings. The list matches any of the strings.
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"` //nolint
 }
 
 // Principal is a type that represents a principal.
 type Principal struct {
 	// Service is the AWS service to which the principal belongs.
-	Service string `json:"Service"` //nolint
+	Service string `json:"Service,omitempty"` //nolint
+	// AWS is the AWS accounts or the IAM principals. "*" is anyone (the anonymous access).
+	AWS []string `json:"AWS,omitempty"` //nolint
 }
 
 // BucketPolicy is a type that represents a bucket policy.
@@ -39,21 +42,113 @@ type BucketPolicy struct {
 }
 
 // NewAllowCloudFrontS3BucketPolicy returns a new BucketPolicy that allows CloudFront to access the S3 bucket.
-func NewAllowCloudFrontS3BucketPolicy(bucketName BucketName) *BucketPolicy {
+// The ARNs of the bucket are in the partition. distributionARNs is the ARNs of the distributions whose origin is the bucket
+// (the distribution and the staging distribution for the canary release). If it's not empty, the other distributions
+// can not read the bucket through OAC. It's empty only before 'spare build' creates the distribution.
+func NewAllowCloudFrontS3BucketPolicy(bucketName BucketName, partition Partition, distributionARNs []string) *BucketPolicy {
+	allow := Statement{
+		Sid:       "Allow CloudFront to GetObject",
+		Effect:    "Allow",
+		Principal: Principal{Service: "cloudfront.amazonaws.com"},
+		Action: []string{
+			"s3:GetObject",
+			"s3:ListBucket",
+		},
+		Resource: []string{
+			bucketName.ARN(partition),
+			bucketName.ARN(partition) + "/*",
+		},
+	}
+	if len(distributionARNs) != 0 {
+		allow.Condition = map[string]map[string]interface{}{
+			"StringEquals": {
+				"AWS:SourceArn": distributionARNs,
+			},
+		}
+	}
 	return &BucketPolicy{
 		Version: "2012-10-17",
 		Statement: []Statement{
+			allow,
 			{
-				Sid:       "Allow CloudFront to GetObject",
+				Sid:       "Secure Access",
+				Effect:    "Deny",
+				Principal: Principal{Service: "*"},
+				Action: []string{
+					"s3:*",
+				},
+				Resource: []string{
+					bucketName.ARN(partition),
+					bucketName.ARN(partition) + "/*",
+				},
+				Condition: map[string]map[string]interface{}{
+					"Bool": {
+						"aws:SecureTransport": "false",
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewPublicReadS3BucketPolicy returns a new BucketPolicy that allows anyone to get the objects of the site at the
+// root of the bucket. It's for the S3-compatible storage (e.g. MinIO, Ceph) that serves the bucket without CloudFront.
+// The deny statement keeps the releases, the previews and the metadata of spare (e.g. the git SHA and the user of
+// each release) private, because an explicit deny overrides the allow statement.
+// The storage has no partition, so the ARNs are in the aws partition.
+func NewPublicReadS3BucketPolicy(bucketName BucketName) *BucketPolicy {
+	bucketARN := bucketName.ARN(PartitionAWS)
+	private := make([]string, 0, len(nonSiteRootPrefixes()))
+	for _, prefix := range nonSiteRootPrefixes() {
+		private = append(private, bucketARN+"/"+prefix+"*")
+	}
+	return &BucketPolicy{
+		Version: "2012-10-17",
+		Statement: []Statement{
+			{
+				Sid:       "Allow anyone to GetObject",
 				Effect:    "Allow",
-				Principal: Principal{Service: "cloudfront.amazonaws.com"},
+				Principal: Principal{AWS: []string{"*"}},
+				Action: []string{
+					"s3:GetObject",
+				},
+				Resource: []string{
+					bucketARN + "/*",
+				},
+			},
+			{
+				Sid:       "Deny anyone to GetObject outside the site root",
+				Effect:    "Deny",
+				Principal: Principal{AWS: []string{"*"}},
 				Action: []string{
 					"s3:GetObject",
-					"s3:ListBucket",
+				},
+				Resource: private,
+			},
+		},
+	}
+}
+
+// NewS3AccessLogsBucketPolicy returns a new BucketPolicy that allows S3 to deliver the server access logs
+// of the source bucket to the log bucket. The ARNs of the buckets are in the partition.
+func NewS3AccessLogsBucketPolicy(logBucket, source BucketName, partition Partition) *BucketPolicy {
+	return &BucketPolicy{
+		Version: "2012-10-17",
+		Statement: []Statement{
+			{
+				Sid:       "Allow S3 to PutObject server access logs",
+				Effect:    "Allow",
+				Principal: Principal{Service: "logging.s3.amazonaws.com"},
+				Action: []string{
+					"s3:PutObject",
 				},
 				Resource: []string{
-					fmt.Sprintf("arn::aws:s3:::%s", bucketName.String()),
-					fmt.Sprintf("arn::aws:s3:::%s/*", bucketName.String()),
+					fmt.Sprintf("%s/%s*", logBucket.ARN(partition), S3AccessLogPrefix),
+				},
+				Condition: map[string]map[string]interface{}{
+					"ArnLike": {
+						"aws:SourceArn": source.ARN(partition),
+					},
 				},
 			},
 			{
@@ -64,10 +159,10 @@ func NewAllowCloudFrontS3BucketPolicy(bucketName BucketName) *BucketPolicy {
 					"s3:*",
 				},
 				Resource: []string{
-					fmt.Sprintf("arn::aws:s3:::%s", bucketName.String()),
-					fmt.Sprintf("arn::aws:s3:::%s/*", bucketName.String()),
+					logBucket.ARN(partition),
+					logBucket.ARN(partition) + "/*",
 				},
-				Condition: map[string]map[string]string{
+				Condition: map[string]map[string]interface{}{
 					"Bool": {
 						"aws:SecureTransport": "false",
 					},
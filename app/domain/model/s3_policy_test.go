@@ -3,6 +3,7 @@ package model
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -18,7 +19,7 @@ func TestBucketPolicyString(t *testing.T) {
 			t.Fatal()
 		}
 
-		bp := NewAllowCloudFrontS3BucketPolicy("bucket")
+		bp := NewAllowCloudFrontS3BucketPolicy("bucket", PartitionAWS, nil)
 		got, err := bp.String()
 		if err != nil {
 			t.Fatal(err)
@@ -29,3 +30,35 @@ func TestBucketPolicyString(t *testing.T) {
 		}
 	})
 }
+
+func TestNewAllowCloudFrontS3BucketPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scoped to the distributions of the bucket", func(t *testing.T) {
+		t.Parallel()
+		arns := []string{"arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE"}
+		got, err := NewAllowCloudFrontS3BucketPolicy("bucket", PartitionAWS, arns).String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"Sid":"Allow CloudFront to GetObject","Effect":"Allow","Principal":{"Service":"cloudfront.amazonaws.com"},` +
+			`"Action":["s3:GetObject","s3:ListBucket"],"Resource":["arn:aws:s3:::bucket","arn:aws:s3:::bucket/*"],` +
+			`"Condition":{"StringEquals":{"AWS:SourceArn":["arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE"]}}}`
+		if !strings.Contains(got, want) {
+			t.Errorf("NewAllowCloudFrontS3BucketPolicy() = %s, want to contain %s", got, want)
+		}
+	})
+}
+
+func TestNewPublicReadS3BucketPolicy(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewPublicReadS3BucketPolicy("bucket").String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Version":"2012-10-17","Statement":[{"Sid":"Allow anyone to GetObject","Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]},{"Sid":"Deny anyone to GetObject outside the site root","Effect":"Deny","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/releases/*","arn:aws:s3:::bucket/previews/*","arn:aws:s3:::bucket/_spare/*"]}]}`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
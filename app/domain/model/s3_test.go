@@ -331,23 +331,100 @@ func TestBucketNameDomain(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name string
-		b    BucketName
-		want string
+		name       string
+		b          BucketName
+		region     Region
+		want       string
+		wantLegacy string
 	}{
 		{
-			name: "success",
-			b:    BucketName("abc"),
-			want: "abc.s3.amazonaws.com",
+			name:       "success. aws partition",
+			b:          BucketName("abc"),
+			region:     RegionAPNortheast1,
+			want:       "abc.s3.ap-northeast-1.amazonaws.com",
+			wantLegacy: "abc.s3.amazonaws.com",
+		},
+		{
+			name:       "success. aws-cn partition has no global domain",
+			b:          BucketName("abc"),
+			region:     RegionCNNorth1,
+			want:       "abc.s3.cn-north-1.amazonaws.com.cn",
+			wantLegacy: "abc.s3.cn-north-1.amazonaws.com.cn",
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			if got := tt.b.Domain(); got != tt.want {
+			if got := tt.b.Domain(tt.region); got != tt.want {
 				t.Errorf("BucketName.Domain() = %v, want %v", got, tt.want)
 			}
+			if got := tt.b.LegacyDomain(tt.region); got != tt.wantLegacy {
+				t.Errorf("BucketName.LegacyDomain() = %v, want %v", got, tt.wantLegacy)
+			}
+			if !tt.b.IsDomain(tt.want) || !tt.b.IsDomain(tt.wantLegacy) {
+				t.Errorf("BucketName.IsDomain() = false, want true")
+			}
+		})
+	}
+}
+
+func TestBucketNameIsDomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{name: "global domain", domain: "abc.s3.amazonaws.com", want: true},
+		{name: "regional domain", domain: "abc.s3.us-west-2.amazonaws.com", want: true},
+		{name: "regional domain in aws-cn", domain: "abc.s3.cn-northwest-1.amazonaws.com.cn", want: true},
+		{name: "other bucket", domain: "abcd.s3.amazonaws.com", want: false},
+		{name: "dns suffix of other partition", domain: "abc.s3.us-west-2.amazonaws.com.cn", want: false},
+		{name: "website endpoint", domain: "abc.s3-website-us-west-2.amazonaws.com", want: false},
+		{name: "custom origin", domain: "abc.s3.example.com", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := BucketName("abc").IsDomain(tt.domain); got != tt.want {
+				t.Errorf("BucketName.IsDomain(%s) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketNameARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		b         BucketName
+		partition Partition
+		want      string
+	}{
+		{
+			name:      "success. aws partition",
+			b:         BucketName("abc"),
+			partition: PartitionAWS,
+			want:      "arn:aws:s3:::abc",
+		},
+		{
+			name:      "success. aws-cn partition",
+			b:         BucketName("abc"),
+			partition: PartitionAWSCN,
+			want:      "arn:aws-cn:s3:::abc",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.ARN(tt.partition); got != tt.want {
+				t.Errorf("BucketName.ARN() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// signingKeyBits is the size of the RSA key. CloudFront supports only 2048-bit RSA keys.
+	signingKeyBits = 2048
+	// keyNameMaxLen is the maximum length of the public key name and the key group name.
+	keyNameMaxLen = 128
+)
+
+// SigningKeyPair is a pair of the RSA keys for the signed URLs and the signed cookies.
+// The public key is registered to CloudFront, and the private key is kept by the user to sign URLs.
+type SigningKeyPair struct {
+	// PrivateKey is the PEM encoded private key (PKCS #1).
+	PrivateKey []byte
+	// PublicKey is the PEM encoded public key (PKIX).
+	PublicKey []byte
+}
+
+// NewSigningKeyPair generates a new SigningKeyPair.
+func NewSigningKeyPair() (*SigningKeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to generate a RSA key")
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to marshal a RSA public key")
+	}
+	return &SigningKeyPair{
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		PublicKey:  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}),
+	}, nil
+}
+
+// NewKeyGroupName returns the name of the key group for the bucket. e.g. spare-my-bucket
+func NewKeyGroupName(bucket BucketName) string {
+	return truncateKeyName(fmt.Sprintf("spare-%s", bucket), "")
+}
+
+// NewPublicKeyName returns the name of the public key that is created at now. e.g. spare-my-bucket-20231019T120000Z
+// CloudFront does not allow the same name, so the name has the creation time.
+func NewPublicKeyName(bucket BucketName, now time.Time) string {
+	return truncateKeyName(fmt.Sprintf("spare-%s", bucket), now.UTC().Format("20060102T150405Z"))
+}
+
+// truncateKeyName returns the name that CloudFront accepts. '.' is not allowed in the name.
+func truncateKeyName(prefix, suffix string) string {
+	prefix = strings.ReplaceAll(prefix, ".", "-")
+	if suffix == "" {
+		if len(prefix) > keyNameMaxLen {
+			return prefix[:keyNameMaxLen]
+		}
+		return prefix
+	}
+	if maxLen := keyNameMaxLen - len(suffix) - 1; len(prefix) > maxLen {
+		prefix = prefix[:maxLen]
+	}
+	return prefix + "-" + suffix
+}
+
+// RotateSigningKeys returns the public keys of the key group after newKey is added, and the public keys that are retired.
+// current is the public keys of the key group from the oldest to the newest. The newest key in current is kept,
+// so that the URLs signed with it are valid until they expire. The older keys are retired.
+func RotateSigningKeys(current []string, newKey string) ([]string, []string) {
+	if len(current) == 0 {
+		return []string{newKey}, []string{}
+	}
+	previous := current[len(current)-1]
+	retired := make([]string, 0, len(current)-1)
+	retired = append(retired, current[:len(current)-1]...)
+	return []string{previous, newKey}, retired
+}
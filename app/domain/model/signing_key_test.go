@@ -0,0 +1,97 @@
+package model
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewSigningKeyPair(t *testing.T) {
+	t.Parallel()
+
+	pair, err := NewSigningKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, _ := pem.Decode(pair.PrivateKey)
+	if private == nil || private.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("private key is not PEM encoded RSA private key: %s", pair.PrivateKey)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(private.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.N.BitLen() != signingKeyBits {
+		t.Errorf("key size = %d, want %d", key.N.BitLen(), signingKeyBits)
+	}
+	public, _ := pem.Decode(pair.PublicKey)
+	if public == nil || public.Type != "PUBLIC KEY" {
+		t.Fatalf("public key is not PEM encoded public key: %s", pair.PublicKey)
+	}
+}
+
+func TestKeyNames(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC)
+	if got := NewKeyGroupName("my.bucket"); got != "spare-my-bucket" {
+		t.Errorf("NewKeyGroupName() = %s, want spare-my-bucket", got)
+	}
+	if got := NewPublicKeyName("my.bucket", now); got != "spare-my-bucket-20231019T120000Z" {
+		t.Errorf("NewPublicKeyName() = %s, want spare-my-bucket-20231019T120000Z", got)
+	}
+	long := BucketName(strings.Repeat("b", 200))
+	if got := NewPublicKeyName(long, now); len(got) != keyNameMaxLen || !strings.HasSuffix(got, "-20231019T120000Z") {
+		t.Errorf("NewPublicKeyName() = %s, want %d characters with the creation time", got, keyNameMaxLen)
+	}
+	if got := NewKeyGroupName(long); len(got) != keyNameMaxLen {
+		t.Errorf("len(NewKeyGroupName()) = %d, want %d", len(got), keyNameMaxLen)
+	}
+}
+
+func TestRotateSigningKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		current     []string
+		wantKeys    []string
+		wantRetired []string
+	}{
+		{
+			name:        "no key",
+			current:     []string{},
+			wantKeys:    []string{"K3"},
+			wantRetired: []string{},
+		},
+		{
+			name:        "one key is kept",
+			current:     []string{"K2"},
+			wantKeys:    []string{"K2", "K3"},
+			wantRetired: []string{},
+		},
+		{
+			name:        "older keys are retired",
+			current:     []string{"K0", "K1", "K2"},
+			wantKeys:    []string{"K2", "K3"},
+			wantRetired: []string{"K0", "K1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			keys, retired := RotateSigningKeys(tt.current, "K3")
+			if diff := cmp.Diff(tt.wantKeys, keys); diff != "" {
+				t.Errorf("keys mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRetired, retired); diff != "" {
+				t.Errorf("retired keys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// spareMetadataPrefix is the S3 key prefix of the metadata of spare (e.g. the release history).
+const spareMetadataPrefix = "_spare/"
+
+// StorageProvider is the provider of the S3-compatible storage.
+type StorageProvider string
+
+const (
+	// StorageProviderMinIO is MinIO.
+	StorageProviderMinIO StorageProvider = "minio"
+	// StorageProviderR2 is Cloudflare R2.
+	StorageProviderR2 StorageProvider = "r2"
+	// StorageProviderCeph is the Ceph Object Gateway (RGW).
+	StorageProviderCeph StorageProvider = "ceph"
+	// StorageProviderOther is the other S3-compatible storage.
+	StorageProviderOther StorageProvider = "other"
+)
+
+// String returns the string representation of the StorageProvider.
+func (p StorageProvider) String() string {
+	return string(p)
+}
+
+// Validate validates StorageProvider. If StorageProvider is invalid, it returns an error.
+func (p StorageProvider) Validate() error {
+	switch p {
+	case StorageProviderMinIO, StorageProviderR2, StorageProviderCeph, StorageProviderOther:
+		return nil
+	default:
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, fmt.Sprintf("provider must be minio, r2, ceph or other: %s", p))
+	}
+}
+
+// DefaultPathStyle returns whether the requests use the path-style URL (https://HOST/BUCKET/KEY) by default.
+// MinIO and Ceph are often served without the wildcard DNS records that the virtual-hosted-style URL needs.
+func (p StorageProvider) DefaultPathStyle() bool {
+	return p == StorageProviderMinIO || p == StorageProviderCeph
+}
+
+// DefaultSigningRegion returns the region in the signature (SigV4) by default.
+// R2 requires "auto", and MinIO and Ceph use "us-east-1" unless their region is configured.
+func (p StorageProvider) DefaultSigningRegion() string {
+	if p == StorageProviderR2 {
+		return "auto"
+	}
+	return RegionUSEast1.String()
+}
+
+// StorageEndpoint is the S3-compatible storage (e.g. MinIO, Cloudflare R2, Ceph) that spare uses instead of Amazon S3.
+// The storage has no CloudFront, so the live release is copied to the root of the bucket, and the storage serves it.
+type StorageEndpoint struct {
+	// URL is the URL of the S3 API of the storage. e.g. https://ACCOUNT_ID.r2.cloudflarestorage.com, http://localhost:9000
+	URL Endpoint
+	// Provider is the provider of the storage.
+	Provider StorageProvider
+	// PathStyle is whether the requests use the path-style URL instead of the virtual-hosted-style URL.
+	PathStyle bool
+	// SigningRegion is the region in the signature (SigV4).
+	SigningRegion string
+	// UnsignedPayload is whether the payload is not signed (UNSIGNED-PAYLOAD). It's faster for the large files,
+	// and it needs TLS because the body is protected only by TLS.
+	UnsignedPayload bool
+	// CABundle is the PEM-encoded CA certificates that verify the certificate of the storage in addition to the system pool.
+	CABundle []byte
+	// InsecureSkipVerify is whether the certificate of the storage is not verified. It's only for testing.
+	InsecureSkipVerify bool
+	// PublicURL is the URL where the storage serves the bucket to the viewers (e.g. the custom domain of R2).
+	// If it's empty, the URL of the bucket on the S3 API is used.
+	PublicURL string
+}
+
+// Validate validates StorageEndpoint. If StorageEndpoint is invalid, it returns an error.
+func (s *StorageEndpoint) Validate() error {
+	if err := s.URL.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, fmt.Sprintf("url is invalid: %s", err.Error()))
+	}
+	u, err := url.Parse(s.URL.String())
+	if err != nil {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, err.Error())
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, fmt.Sprintf("url must start with http:// or https://: %s", s.URL))
+	}
+	if err := s.Provider.Validate(); err != nil {
+		return err
+	}
+	if s.SigningRegion == "" {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, "signing region is empty")
+	}
+	if !s.TLS() && (s.UnsignedPayload || len(s.CABundle) > 0 || s.InsecureSkipVerify) {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, "unsignedPayload, caBundle and insecureSkipVerify need an https:// url")
+	}
+	if len(s.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(s.CABundle) {
+		return errfmt.Wrap(ErrInvalidStorageEndpoint, "caBundle has no PEM-encoded certificate")
+	}
+	if s.PublicURL != "" {
+		if err := Endpoint(s.PublicURL).Validate(); err != nil {
+			return errfmt.Wrap(ErrInvalidStorageEndpoint, fmt.Sprintf("publicUrl is invalid: %s", err.Error()))
+		}
+	}
+	return nil
+}
+
+// TLS returns whether the requests to the storage are sent over TLS.
+func (s *StorageEndpoint) TLS() bool {
+	return strings.HasPrefix(s.URL.String(), "https://")
+}
+
+// SiteDomain returns the domain (and the path) where the bucket is served. It's shown instead of the CloudFront domain.
+// e.g. www.example.com, localhost:9000/spare-bucket
+func (s *StorageEndpoint) SiteDomain(bucket BucketName) Domain {
+	if s.PublicURL != "" {
+		return Domain(strings.TrimSuffix(trimScheme(s.PublicURL), "/"))
+	}
+	host := strings.TrimSuffix(trimScheme(s.URL.String()), "/")
+	if s.PathStyle {
+		return Domain(host + "/" + bucket.String())
+	}
+	return Domain(bucket.String() + "." + host)
+}
+
+// trimScheme returns the URL without the scheme. e.g. https://example.com -> example.com
+func trimScheme(u string) string {
+	if _, after, ok := strings.Cut(u, "://"); ok {
+		return after
+	}
+	return u
+}
+
+// nonSiteRootPrefixes returns the S3 key prefixes of the objects that are not the site at the root of the bucket.
+func nonSiteRootPrefixes() []string {
+	return []string{ReleasesRootPrefix, PreviewsRootPrefix, spareMetadataPrefix}
+}
+
+// SiteRootKey returns whether the S3 key is an object of the site at the root of the bucket.
+// The releases, the previews and the metadata of spare (_spare/) are not.
+func SiteRootKey(key string) bool {
+	for _, prefix := range nonSiteRootPrefixes() {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// SortSiteCopyOrder sorts the S3 keys of the site in the order that they are copied to the root of the bucket:
+// the assets first, then the HTML pages, and index.html last. The viewers get the new index.html only after
+// the assets and the pages that it refers to are copied.
+func SortSiteCopyOrder(keys []string) {
+	rank := func(key string) int {
+		switch {
+		case key == "index.html":
+			return 2
+		case strings.HasSuffix(key, ".html"):
+			return 1
+		default:
+			return 0
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return rank(keys[i]) < rank(keys[j])
+	})
+}
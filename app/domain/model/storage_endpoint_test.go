@@ -0,0 +1,148 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStorageEndpointValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       *StorageEndpoint
+		wantErr bool
+	}{
+		{
+			name:    "success. MinIO over HTTP",
+			s:       &StorageEndpoint{URL: "http://localhost:9000", Provider: StorageProviderMinIO, PathStyle: true, SigningRegion: "us-east-1"},
+			wantErr: false,
+		},
+		{
+			name: "success. R2 with unsigned payload",
+			s: &StorageEndpoint{
+				URL:             "https://0123456789abcdef.r2.cloudflarestorage.com",
+				Provider:        StorageProviderR2,
+				SigningRegion:   "auto",
+				UnsignedPayload: true,
+				PublicURL:       "https://www.example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failure. url is empty",
+			s:       &StorageEndpoint{Provider: StorageProviderMinIO, SigningRegion: "us-east-1"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. url is not http or https",
+			s:       &StorageEndpoint{URL: "ftp://localhost:9000", Provider: StorageProviderMinIO, SigningRegion: "us-east-1"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. unknown provider",
+			s:       &StorageEndpoint{URL: "http://localhost:9000", Provider: "gcs", SigningRegion: "us-east-1"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. signing region is empty",
+			s:       &StorageEndpoint{URL: "http://localhost:9000", Provider: StorageProviderMinIO},
+			wantErr: true,
+		},
+		{
+			name:    "failure. unsigned payload without TLS",
+			s:       &StorageEndpoint{URL: "http://localhost:9000", Provider: StorageProviderMinIO, SigningRegion: "us-east-1", UnsignedPayload: true},
+			wantErr: true,
+		},
+		{
+			name: "failure. CA bundle is not PEM",
+			s: &StorageEndpoint{
+				URL:           "https://minio.example.com",
+				Provider:      StorageProviderMinIO,
+				SigningRegion: "us-east-1",
+				CABundle:      []byte("not a certificate"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("StorageEndpoint.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStorageEndpointSiteDomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    *StorageEndpoint
+		want Domain
+	}{
+		{
+			name: "path style",
+			s:    &StorageEndpoint{URL: "http://localhost:9000/", PathStyle: true},
+			want: "localhost:9000/spare-bucket",
+		},
+		{
+			name: "virtual-hosted style",
+			s:    &StorageEndpoint{URL: "https://s3.example.com"},
+			want: "spare-bucket.s3.example.com",
+		},
+		{
+			name: "public URL",
+			s:    &StorageEndpoint{URL: "https://0123456789abcdef.r2.cloudflarestorage.com", PublicURL: "https://www.example.com/"},
+			want: "www.example.com",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.s.SiteDomain("spare-bucket"); got != tt.want {
+				t.Errorf("StorageEndpoint.SiteDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSiteRootKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "index.html", want: true},
+		{key: "assets/app.js", want: true},
+		{key: "releases/20231019T120000Z/index.html", want: false},
+		{key: "previews/pr-1/index.html", want: false},
+		{key: "_spare/releases.json", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			if got := SiteRootKey(tt.key); got != tt.want {
+				t.Errorf("SiteRootKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortSiteCopyOrder(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"index.html", "about.html", "assets/app.js", "docs/index.html", "favicon.ico"}
+	SortSiteCopyOrder(keys)
+	want := []string{"assets/app.js", "favicon.ico", "about.html", "docs/index.html", "index.html"}
+	if diff := cmp.Diff(want, keys); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
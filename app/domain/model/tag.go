@@ -0,0 +1,165 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xregex"
+)
+
+const (
+	// TagKeyProject is the tag key of the project. The resources with the same value are one spare-managed stack.
+	TagKeyProject = "spare:project"
+	// TagKeyEnv is the tag key of the environment (e.g. production, staging).
+	TagKeyEnv = "spare:env"
+	// TagKeyVersion is the tag key of the spare version that created or updated the resource.
+	TagKeyVersion = "spare:version"
+	// spareTagKeyPrefix is the prefix of the tag keys that spare manages.
+	spareTagKeyPrefix = "spare:"
+	// awsTagKeyPrefix is the prefix of the tag keys that AWS reserves.
+	awsTagKeyPrefix = "aws:"
+	// maxTagKeyLength is the maximum length of the tag key.
+	maxTagKeyLength = 128
+	// maxTagValueLength is the maximum length of the tag value.
+	maxTagValueLength = 256
+)
+
+// Tags is the tags of the AWS resources. The key is the tag key, and the value is the tag value.
+type Tags map[string]string
+
+// NewSpareTags returns the tags that spare applies to the resources. The tags are the user-defined tags with
+// spare:project (the bucket name), spare:env and spare:version.
+func NewSpareTags(project BucketName, env, version string, tags Tags) Tags {
+	t := make(Tags, len(tags)+3) //nolint:gomnd
+	for k, v := range tags {
+		t[k] = v
+	}
+	t[TagKeyProject] = project.String()
+	t[TagKeyEnv] = env
+	t[TagKeyVersion] = version
+	return t
+}
+
+var tagRegexPattern xregex.Regex //nolint:gochecknoglobals
+
+// Validate validates the user-defined Tags. The keys that start with "aws:" or "spare:" are reserved.
+func (t Tags) Validate() error {
+	tagRegexPattern.InitOnce(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+	for _, k := range t.Keys() {
+		v := t[k]
+		if k == "" || utf8.RuneCountInString(k) > maxTagKeyLength {
+			return errfmt.Wrap(ErrInvalidTags, fmt.Sprintf("tag key must be between 1 and %d characters long: %s", maxTagKeyLength, k))
+		}
+		if utf8.RuneCountInString(v) > maxTagValueLength {
+			return errfmt.Wrap(ErrInvalidTags, fmt.Sprintf("tag value of %s must be %d characters or less", k, maxTagValueLength))
+		}
+		if strings.HasPrefix(strings.ToLower(k), awsTagKeyPrefix) || strings.HasPrefix(k, spareTagKeyPrefix) {
+			return errfmt.Wrap(ErrInvalidTags, fmt.Sprintf("tag key must not start with aws: or spare: (reserved): %s", k))
+		}
+		if err := tagRegexPattern.MatchString(k); err != nil {
+			return errfmt.Wrap(ErrInvalidTags, fmt.Sprintf("tag key %s has invalid characters: %s", k, err.Error()))
+		}
+		if err := tagRegexPattern.MatchString(v); err != nil {
+			return errfmt.Wrap(ErrInvalidTags, fmt.Sprintf("tag value of %s has invalid characters: %s", k, err.Error()))
+		}
+	}
+	return nil
+}
+
+// Keys returns the sorted tag keys.
+func (t Tags) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TaggedResource is a type that represents the AWS resource with its tags.
+type TaggedResource struct {
+	// ARN is the ARN of the resource.
+	ARN string `json:"arn"`
+	// Tags is the tags of the resource.
+	Tags Tags `json:"tags"`
+}
+
+// Type returns the service and the resource type of the resource (e.g. s3, cloudfront:distribution, wafv2:webacl).
+func (r TaggedResource) Type() string {
+	// arn:partition:service:region:account:resource
+	parts := strings.SplitN(r.ARN, ":", 6) //nolint:gomnd
+	if len(parts) != 6 {
+		return ""
+	}
+	resource := parts[5]
+	if i := strings.IndexAny(resource, "/:"); i >= 0 {
+		resource = resource[:i]
+	} else {
+		// e.g. arn:aws:s3:::bucket
+		return parts[2]
+	}
+	if parts[2] == "wafv2" {
+		// e.g. arn:aws:wafv2:us-east-1:123456789012:global/webacl/name/id
+		if fields := strings.Split(parts[5], "/"); len(fields) > 1 {
+			resource = fields[1]
+		}
+	}
+	return parts[2] + ":" + resource
+}
+
+// Stack is a type that represents the resources that spare manages for one project.
+type Stack struct {
+	// Project is the value of the spare:project tag.
+	Project string `json:"project"`
+	// Env is the values of the spare:env tag. They are joined with ',' if the resources have different values.
+	Env string `json:"env"`
+	// Version is the values of the spare:version tag. They are joined with ',' if the resources have different values.
+	Version string `json:"version"`
+	// Resources is the resources of the stack sorted by ARN.
+	Resources []TaggedResource `json:"resources"`
+}
+
+// NewStacks groups the resources by the spare:project tag. The resources without the tag are ignored.
+// The stacks are sorted by the project.
+func NewStacks(resources []TaggedResource) []Stack {
+	byProject := map[string][]TaggedResource{}
+	for _, r := range resources {
+		project, ok := r.Tags[TagKeyProject]
+		if !ok {
+			continue
+		}
+		byProject[project] = append(byProject[project], r)
+	}
+
+	stacks := make([]Stack, 0, len(byProject))
+	for project, rs := range byProject {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].ARN < rs[j].ARN })
+		stacks = append(stacks, Stack{
+			Project:   project,
+			Env:       joinTagValues(rs, TagKeyEnv),
+			Version:   joinTagValues(rs, TagKeyVersion),
+			Resources: rs,
+		})
+	}
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].Project < stacks[j].Project })
+	return stacks
+}
+
+// joinTagValues returns the distinct values of the tag key in the resources joined with ','.
+func joinTagValues(resources []TaggedResource, key string) string {
+	seen := map[string]bool{}
+	values := make([]string, 0, 1)
+	for _, r := range resources {
+		v, ok := r.Tags[key]
+		if !ok || seen[v] {
+			continue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
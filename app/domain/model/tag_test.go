@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTagsValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		t       Tags
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			t:       Tags{"team": "web", "cost-center": "1234", "owner": "alice@example.com", "Name": "my site"},
+			wantErr: false,
+		},
+		{
+			name:    "success. empty value",
+			t:       Tags{"team": ""},
+			wantErr: false,
+		},
+		{
+			name:    "failure. empty key",
+			t:       Tags{"": "web"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. key is too long",
+			t:       Tags{strings.Repeat("k", 129): "web"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. value is too long",
+			t:       Tags{"team": strings.Repeat("v", 257)},
+			wantErr: true,
+		},
+		{
+			name:    "failure. key reserved by AWS",
+			t:       Tags{"AWS:team": "web"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. key reserved by spare",
+			t:       Tags{"spare:project": "web"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid character",
+			t:       Tags{"team": "web;app"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.t.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Tags.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTags) {
+				t.Errorf("Tags.Validate() error = %v, want %v", err, ErrInvalidTags)
+			}
+		})
+	}
+}
+
+func TestNewSpareTags(t *testing.T) {
+	t.Parallel()
+
+	got := NewSpareTags("my-bucket", "production", "v0.1.0", Tags{"team": "web"})
+	want := Tags{
+		"team":          "web",
+		"spare:project": "my-bucket",
+		"spare:env":     "production",
+		"spare:version": "v0.1.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTaggedResourceType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		arn  string
+		want string
+	}{
+		{arn: "arn:aws:s3:::my-bucket", want: "s3"},
+		{arn: "arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE", want: "cloudfront:distribution"},
+		{arn: "arn:aws:wafv2:us-east-1:123456789012:global/webacl/spare-my-bucket/a1b2c3d4", want: "wafv2:webacl"},
+		{arn: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab", want: "kms:key"},
+		{arn: "invalid", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+			if got := (TaggedResource{ARN: tt.arn}).Type(); got != tt.want {
+				t.Errorf("TaggedResource.Type() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStacks(t *testing.T) {
+	t.Parallel()
+
+	resources := []TaggedResource{
+		{ARN: "arn:aws:s3:::site-b", Tags: Tags{TagKeyProject: "site-b", TagKeyEnv: "staging", TagKeyVersion: "v0.2.0"}},
+		{ARN: "arn:aws:s3:::site-a", Tags: Tags{TagKeyProject: "site-a", TagKeyEnv: "production", TagKeyVersion: "v0.2.0"}},
+		{
+			ARN:  "arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE",
+			Tags: Tags{TagKeyProject: "site-a", TagKeyEnv: "production", TagKeyVersion: "v0.1.0"},
+		},
+		{ARN: "arn:aws:s3:::other", Tags: Tags{"team": "web"}},
+	}
+	want := []Stack{
+		{
+			Project: "site-a",
+			Env:     "production",
+			Version: "v0.1.0,v0.2.0",
+			Resources: []TaggedResource{
+				{
+					ARN:  "arn:aws:cloudfront::123456789012:distribution/EDFDVBD6EXAMPLE",
+					Tags: Tags{TagKeyProject: "site-a", TagKeyEnv: "production", TagKeyVersion: "v0.1.0"},
+				},
+				{ARN: "arn:aws:s3:::site-a", Tags: Tags{TagKeyProject: "site-a", TagKeyEnv: "production", TagKeyVersion: "v0.2.0"}},
+			},
+		},
+		{
+			Project: "site-b",
+			Env:     "staging",
+			Version: "v0.2.0",
+			Resources: []TaggedResource{
+				{ARN: "arn:aws:s3:::site-b", Tags: Tags{TagKeyProject: "site-b", TagKeyEnv: "staging", TagKeyVersion: "v0.2.0"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, NewStacks(resources)); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,388 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ViewerRequest is a type that represents the features of the CloudFront Function that runs on viewer requests.
+// CloudFront allows only one viewer request function per cache behavior, so spare generates one function
+// that contains all enabled features.
+type ViewerRequest struct {
+	// BasicAuth is the basic auth. If it's nil, the basic auth is disabled.
+	BasicAuth *BasicAuth
+	// Redirects is the redirect rules read from the _redirects file.
+	Redirects Redirects
+	// Maintenance is the maintenance mode. If it's nil, the maintenance mode is off.
+	// It's read from the bucket, not from .spare.yml, because 'spare maintenance' changes it without deploying.
+	Maintenance *Maintenance
+	// PrettyURLs is the rewrite of the extensionless paths and the directory paths. If it's nil, the paths are not rewritten.
+	PrettyURLs *PrettyURLs
+}
+
+// Empty returns true if no feature is enabled.
+func (v *ViewerRequest) Empty() bool {
+	return v == nil || (v.BasicAuth == nil && len(v.Redirects) == 0 && v.PrettyURLs == nil && v.Maintenance == nil)
+}
+
+// Validate validates ViewerRequest. If ViewerRequest is invalid, it returns an error.
+func (v *ViewerRequest) Validate() error {
+	if v == nil {
+		return nil
+	}
+	if v.BasicAuth != nil {
+		if err := v.BasicAuth.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range v.Redirects {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	if v.Maintenance != nil {
+		if err := v.Maintenance.Validate(); err != nil {
+			return err
+		}
+	}
+	if v.PrettyURLs != nil {
+		return v.PrettyURLs.Validate()
+	}
+	return nil
+}
+
+// WithMaintenance returns a copy of ViewerRequest whose maintenance mode is m.
+func (v *ViewerRequest) WithMaintenance(m *Maintenance) *ViewerRequest {
+	copied := ViewerRequest{}
+	if v != nil {
+		copied = *v
+	}
+	copied.Maintenance = m
+	return &copied
+}
+
+// WithBasicAuth returns a copy of ViewerRequest whose basic auth is b.
+func (v *ViewerRequest) WithBasicAuth(b *BasicAuth) *ViewerRequest {
+	copied := ViewerRequest{}
+	if v != nil {
+		copied = *v
+	}
+	copied.BasicAuth = b
+	return &copied
+}
+
+// Rules returns the rules of ViewerRequest that are read from the deploy target.
+func (v *ViewerRequest) Rules() *ViewerRules {
+	if v == nil {
+		return &ViewerRules{}
+	}
+	return &ViewerRules{
+		Redirects:  v.Redirects,
+		PrettyURLs: v.PrettyURLs,
+	}
+}
+
+// ViewerRulesKey is the S3 key of the rules of the published viewer request function.
+const ViewerRulesKey = "_spare/viewer.json"
+
+// ViewerRules is a type that represents the features of the viewer request function that are read from the deploy target.
+// They are recorded in the bucket whenever the function is published, so 'spare auth rotate' can publish
+// the function again with the live rules instead of the rules in the local deploy target.
+// The basic auth is not recorded because .spare.yml has it, and the maintenance mode has its own state.
+type ViewerRules struct {
+	// Redirects is the redirect rules read from the _redirects file.
+	Redirects Redirects `json:"redirects"`
+	// PrettyURLs is the rewrite of the extensionless paths and the directory paths. If it's nil, the paths are not rewritten.
+	PrettyURLs *PrettyURLs `json:"pretty_urls"`
+}
+
+// ParseViewerRules parses the JSON representation of ViewerRules.
+func ParseViewerRules(data []byte) (*ViewerRules, error) {
+	r := &ViewerRules{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, errfmt.Wrap(err, "failed to unmarshal viewer request rules")
+	}
+	return r, nil
+}
+
+// String returns the JSON representation of ViewerRules.
+func (r *ViewerRules) String() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to marshal viewer request rules")
+	}
+	return string(data), nil
+}
+
+// ViewerRequest returns the ViewerRequest that has the rules and the basic auth b.
+func (r *ViewerRules) ViewerRequest(b *BasicAuth) *ViewerRequest {
+	return &ViewerRequest{
+		BasicAuth:  b,
+		Redirects:  r.Redirects,
+		PrettyURLs: r.PrettyURLs,
+	}
+}
+
+// cdnFunctionMaxSize is the maximum size of the CloudFront Function code in bytes.
+const cdnFunctionMaxSize = 10 * 1024
+
+// NewViewerRequestFunction returns the CloudFront Function that is associated with the default cache behavior
+// and the cache behaviors except previews/*. If no feature is enabled, it returns nil.
+// The features are applied in the order: maintenance mode, basic auth, redirects, pretty URLs.
+func NewViewerRequestFunction(bucket BucketName, v *ViewerRequest) (*CDNFunction, error) {
+	if v.Empty() {
+		return nil, nil
+	}
+	f := &viewerFunction{}
+	f.addMaintenance(v.Maintenance)
+	f.addBasicAuth(v.BasicAuth)
+	if err := f.addRedirects(v.Redirects); err != nil {
+		return nil, err
+	}
+	f.addPrettyURLs(v.PrettyURLs)
+
+	code := f.code()
+	if len(code) > cdnFunctionMaxSize {
+		return nil, errfmt.Wrap(ErrInvalidCDNFunction,
+			fmt.Sprintf("viewer request function is %d bytes. it must be %d bytes or less: %s", len(code), cdnFunctionMaxSize, functionSizes(v)))
+	}
+	return &CDNFunction{
+		Name:    NewCDNFunctionName("viewer", bucket),
+		Comment: "Viewer request function generated by spare",
+		Code:    code,
+	}, nil
+}
+
+// functionSizes returns the bytes that each enabled feature adds to the viewer request function, the largest first.
+// e.g. "redirect rules (120 rules) 9800 bytes, basic auth (2 users) 700 bytes"
+func functionSizes(v *ViewerRequest) string {
+	type featureSize struct {
+		name string
+		size int
+	}
+	base := len((&viewerFunction{}).code())
+	size := func(add func(f *viewerFunction)) int {
+		f := &viewerFunction{}
+		add(f)
+		return len(f.code()) - base
+	}
+
+	sizes := []featureSize{}
+	if v.Maintenance != nil {
+		sizes = append(sizes, featureSize{
+			name: fmt.Sprintf("maintenance mode (%d allowed IPs)", len(v.Maintenance.AllowIPs)),
+			size: size(func(f *viewerFunction) { f.addMaintenance(v.Maintenance) }),
+		})
+	}
+	if v.BasicAuth != nil {
+		sizes = append(sizes, featureSize{
+			name: fmt.Sprintf("basic auth (%d users)", len(v.BasicAuth.Users)),
+			size: size(func(f *viewerFunction) { f.addBasicAuth(v.BasicAuth) }),
+		})
+	}
+	if len(v.Redirects) != 0 {
+		// The error is ignored, because the rules have been already added to the function once.
+		sizes = append(sizes, featureSize{
+			name: fmt.Sprintf("redirect rules (%d rules)", len(v.Redirects)),
+			size: size(func(f *viewerFunction) { _ = f.addRedirects(v.Redirects) }),
+		})
+	}
+	if v.PrettyURLs != nil {
+		sizes = append(sizes, featureSize{
+			name: fmt.Sprintf("pretty URLs (%d directory pages)", len(v.PrettyURLs.DirectoryPages)),
+			size: size(func(f *viewerFunction) { f.addPrettyURLs(v.PrettyURLs) }),
+		})
+	}
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].size > sizes[j].size
+	})
+
+	descriptions := make([]string, 0, len(sizes))
+	for _, s := range sizes {
+		descriptions = append(descriptions, fmt.Sprintf("%s %d bytes", s.name, s.size))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// viewerFunction is a builder of the viewer request function code.
+// Each feature adds the top-level declarations and the statements of the handler.
+// A statement can return the response to stop the request, otherwise the request is passed to the next statement.
+type viewerFunction struct {
+	declarations []string
+	statements   []string
+}
+
+// add adds the declaration and the statement to the function.
+// Empty strings and the declarations that have been already added are ignored.
+func (f *viewerFunction) add(declaration, statement string) {
+	if declaration != "" && !contains(f.declarations, declaration) {
+		f.declarations = append(f.declarations, declaration)
+	}
+	if statement != "" {
+		f.statements = append(f.statements, statement)
+	}
+}
+
+// code returns the JavaScript code of the function.
+func (f *viewerFunction) code() string {
+	var b strings.Builder
+	for _, d := range f.declarations {
+		b.WriteString(d)
+		b.WriteString("\n")
+	}
+	b.WriteString("function handler(event) {\n    var request = event.request;\n")
+	for _, s := range f.statements {
+		b.WriteString(s)
+	}
+	b.WriteString("    return request;\n}\n")
+	return b.String()
+}
+
+// addBasicAuth adds the basic auth. If auth is nil, it does nothing.
+// The function decodes the Authorization header and compares its SHA-256 hash with the password hashes,
+// so the plain passwords are never stored in the function code.
+func (f *viewerFunction) addBasicAuth(auth *BasicAuth) {
+	if auth == nil {
+		return
+	}
+	f.add(`var crypto = require('crypto');
+var basicAuthHashes = `+jsLiteral(auth.PasswordHashes())+`;
+var basicAuthRealm = `+jsLiteral(auth.Realm)+`;
+function authorized(request) {
+    var header = request.headers.authorization;
+    if (!header || header.value.indexOf('Basic ') !== 0) {
+        return false;
+    }
+    var credential = String.bytesFrom(header.value.substring(6), 'base64');
+    var hash = crypto.createHash('sha256').update(credential).digest('hex');
+    return basicAuthHashes.indexOf(hash) !== -1;
+}
+`, `    if (!authorized(request)) {
+        return {
+            statusCode: 401,
+            statusDescription: 'Unauthorized',
+            headers: {
+                'www-authenticate': { value: 'Basic realm="' + basicAuthRealm + '", charset="UTF-8"' }
+            }
+        };
+    }
+`)
+}
+
+// addRedirects adds the redirect rules. If there are no rules, it does nothing.
+// The query string of the request is passed to the redirect destination unless the destination has its own query string.
+func (f *viewerFunction) addRedirects(redirects Redirects) error {
+	if len(redirects) == 0 {
+		return nil
+	}
+	rules := make([]redirectFunctionRule, 0, len(redirects))
+	for _, r := range redirects {
+		rule, err := r.functionRule()
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	f.add(jsRedirectQuery, "")
+	f.add(`var redirectRules = `+jsLiteral(rules)+`;
+function matchRedirect(request) {
+    var file = request.uri.split('/').pop().indexOf('.') !== -1;
+    for (var i = 0; i < redirectRules.length; i++) {
+        var rule = redirectRules[i];
+        if (file && !rule.force) {
+            continue;
+        }
+        var m = request.uri.match(new RegExp(rule.pattern));
+        if (!m) {
+            continue;
+        }
+        var to = '';
+        for (var j = 0; j < rule.to.length; j++) {
+            to += typeof rule.to[j] === 'number' ? (m[rule.to[j]] || '') : rule.to[j];
+        }
+        return { status: rule.status, to: to };
+    }
+    return null;
+}
+`, `    var redirect = matchRedirect(request);
+    if (redirect && redirect.status === 200) {
+        request.uri = redirect.to;
+    } else if (redirect) {
+        var location = redirect.to.indexOf('?') === -1 ? redirect.to + redirectQuery(request.querystring) : redirect.to;
+        return {
+            statusCode: redirect.status,
+            statusDescription: 'Redirect',
+            headers: { location: { value: location } }
+        };
+    }
+`)
+	return nil
+}
+
+// addPrettyURLs adds the rewrite of the extensionless paths and the directory paths. If p is nil, it does nothing.
+// The paths that have a file extension are not rewritten. The trailing slash is normalized with 301 redirects,
+// and then /about is rewritten to /about.html and /docs/ is rewritten to /docs/index.html.
+func (f *viewerFunction) addPrettyURLs(p *PrettyURLs) {
+	if p == nil {
+		return
+	}
+	f.add(jsRedirectQuery, "")
+	directoryPages := make(map[string]bool, len(p.DirectoryPages))
+	for _, page := range p.DirectoryPages {
+		directoryPages[page] = true
+	}
+	f.add(`var prettyUrlTrailingSlash = `+jsLiteral(p.TrailingSlash)+`;
+var prettyUrlDirectoryPages = `+jsLiteral(directoryPages)+`;
+function prettyUrl(request) {
+    var uri = request.uri;
+    if (uri.split('/').pop().indexOf('.') !== -1) {
+        return null;
+    }
+    var slash = uri.charAt(uri.length - 1) === '/';
+    var path = slash ? uri.substring(0, uri.length - 1) : uri;
+    if (prettyUrlTrailingSlash === 'always' && !slash) {
+        return path + '/';
+    }
+    if (prettyUrlTrailingSlash === 'never' && slash && path !== '') {
+        return path;
+    }
+    request.uri = slash || prettyUrlDirectoryPages[path] === true ? path + '/index.html' : path + '.html';
+    return null;
+}
+`, `    var canonical = prettyUrl(request);
+    if (canonical) {
+        return {
+            statusCode: 301,
+            statusDescription: 'Moved Permanently',
+            headers: { location: { value: canonical + redirectQuery(request.querystring) } }
+        };
+    }
+`)
+}
+
+// jsRedirectQuery is the function that returns the query string of the redirect destination.
+const jsRedirectQuery = `function redirectQuery(querystring) {
+    var params = [];
+    for (var name in querystring) {
+        var values = querystring[name].multiValue || [querystring[name]];
+        for (var i = 0; i < values.length; i++) {
+            params.push(values[i].value === '' ? name : name + '=' + values[i].value);
+        }
+    }
+    return params.length > 0 ? '?' + params.join('&') : '';
+}
+`
+
+// jsLiteral returns the JavaScript literal of v. v must be a string, a slice of strings, a map or a JSON-serializable struct.
+func jsLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
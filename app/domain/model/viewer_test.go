@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewViewerRequestFunction(t *testing.T) {
+	t.Parallel()
+
+	auth := &BasicAuth{Realm: "spare", Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}}
+	tests := []struct {
+		name         string
+		v            *ViewerRequest
+		wantNil      bool
+		wantContains []string
+	}{
+		{
+			name:    "no feature is enabled",
+			v:       &ViewerRequest{},
+			wantNil: true,
+		},
+		{
+			name:    "nil",
+			v:       nil,
+			wantNil: true,
+		},
+		{
+			name:    "basic auth",
+			v:       &ViewerRequest{BasicAuth: auth},
+			wantNil: false,
+			wantContains: []string{
+				`var basicAuthHashes = ["3d11dc479c08e3b368773103d64766c2e420ce39727932fcf2d8f4d9d599be59"];`,
+				`var basicAuthRealm = "spare";`,
+				"statusCode: 401",
+			},
+		},
+		{
+			name:    "pretty URLs",
+			v:       &ViewerRequest{PrettyURLs: &PrettyURLs{TrailingSlash: TrailingSlashAlways}},
+			wantNil: false,
+			wantContains: []string{
+				`var prettyUrlTrailingSlash = "always";`,
+				`var prettyUrlDirectoryPages = {};`,
+				`request.uri = slash || prettyUrlDirectoryPages[path] === true ? path + '/index.html' : path + '.html';`,
+				"function redirectQuery(querystring)",
+			},
+		},
+		{
+			name:    "pretty URLs serve the directory pages without the trailing slash",
+			v:       &ViewerRequest{PrettyURLs: &PrettyURLs{TrailingSlash: TrailingSlashIgnore, DirectoryPages: []string{"/docs/intro"}}},
+			wantNil: false,
+			wantContains: []string{
+				`var prettyUrlTrailingSlash = "ignore";`,
+				`var prettyUrlDirectoryPages = {"/docs/intro":true};`,
+			},
+		},
+		{
+			name: "maintenance mode is checked before basic auth",
+			v: &ViewerRequest{
+				BasicAuth:   auth,
+				Maintenance: &Maintenance{RetryAfter: 600, AllowIPs: []string{"203.0.113.0/24"}, BypassCookie: "bypass", BypassToken: "abc"},
+			},
+			wantNil: false,
+			wantContains: []string{
+				`var maintenanceAllowIPs = ["203.0.113.0/24"];`,
+				`var maintenanceToken = "abc";`,
+				"        request.uri = '/_spare/maintenance.html';\n        return request;\n    }\n    if (!authorized(request))",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewViewerRequestFunction("my.bucket", tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("NewViewerRequestFunction() = %v, want nil", got)
+				}
+				return
+			}
+			if got.Name != "spare-viewer-my-bucket" {
+				t.Errorf("NewViewerRequestFunction().Name = %v, want spare-viewer-my-bucket", got.Name)
+			}
+			for _, s := range tt.wantContains {
+				if !strings.Contains(got.Code, s) {
+					t.Errorf("NewViewerRequestFunction().Code does not contain %q:\n%s", s, got.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestNewViewerRequestFunctionTooLarge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the error names the largest feature", func(t *testing.T) {
+		t.Parallel()
+		redirects := Redirects{}
+		for i := 0; i < 300; i++ {
+			redirects = append(redirects, RedirectRule{From: fmt.Sprintf("/old/%d", i), To: fmt.Sprintf("/new/%d", i), Status: 301})
+		}
+		_, err := NewViewerRequestFunction("my-bucket", &ViewerRequest{
+			BasicAuth:  &BasicAuth{Realm: "spare", Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}},
+			Redirects:  redirects,
+			PrettyURLs: &PrettyURLs{TrailingSlash: TrailingSlashIgnore},
+		})
+		if !errors.Is(err, ErrInvalidCDNFunction) {
+			t.Fatalf("NewViewerRequestFunction() error = %v, want %v", err, ErrInvalidCDNFunction)
+		}
+		if !strings.Contains(err.Error(), "bytes or less: redirect rules (300 rules) ") {
+			t.Errorf("NewViewerRequestFunction() error = %v, want the redirect rules first", err)
+		}
+	})
+}
+
+func TestNewPreviewRouterFunction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("previews are protected by the basic auth", func(t *testing.T) {
+		t.Parallel()
+		auth := &BasicAuth{Realm: "spare", Users: []BasicAuthUser{NewBasicAuthUser("alice", "secret")}}
+		if got := NewPreviewRouterFunction("my-bucket", auth); !strings.Contains(got.Code, "if (!authorized(request))") {
+			t.Errorf("NewPreviewRouterFunction().Code does not check the basic auth:\n%s", got.Code)
+		}
+		if got := NewPreviewRouterFunction("my-bucket", nil); strings.Contains(got.Code, "authorized") {
+			t.Errorf("NewPreviewRouterFunction().Code checks the basic auth:\n%s", got.Code)
+		}
+	})
+}
+
+func TestParseViewerRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal and unmarshal", func(t *testing.T) {
+		t.Parallel()
+		want := &ViewerRules{
+			Redirects:  Redirects{{From: "/news/:year/*", To: "/blog/:year/:splat", Status: 301, Force: true}},
+			PrettyURLs: &PrettyURLs{TrailingSlash: TrailingSlashNever},
+		}
+		data, err := want.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseViewerRules([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ParseViewerRules([]byte("{")); err == nil {
+			t.Error("ParseViewerRules() error = nil, want error")
+		}
+	})
+}
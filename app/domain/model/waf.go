@@ -0,0 +1,171 @@
+package model
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// managedRuleGroups is the list of AWS managed rule groups that spare supports.
+// https://docs.aws.amazon.com/waf/latest/developerguide/aws-managed-rule-groups-list.html
+var managedRuleGroups = map[string]string{ //nolint:gochecknoglobals
+	"common":         "AWSManagedRulesCommonRuleSet",
+	"knownBadInputs": "AWSManagedRulesKnownBadInputsRuleSet",
+	"ipReputation":   "AWSManagedRulesAmazonIpReputationList",
+}
+
+const (
+	// wafRateLimitMin is the minimum rate limit of the rate-based rule.
+	wafRateLimitMin = 100
+	// wafRateLimitMax is the maximum rate limit of the rate-based rule.
+	wafRateLimitMax = 2000000000
+	// wafNameMaxLen is the maximum length of the names of the web ACL and the IP sets.
+	wafNameMaxLen = 128
+)
+
+// WAF is a type that represents the rules of the web ACL that protects the CDN.
+type WAF struct {
+	// ManagedRules is the list of AWS managed rule groups. common, knownBadInputs or ipReputation.
+	ManagedRules []string
+	// RateLimit is the maximum number of requests from an IP address in 5 minutes.
+	// If it's zero, the rate-based rule is not used.
+	RateLimit int64
+	// AllowIPs is the list of CIDRs that are always allowed.
+	AllowIPs []string
+	// DenyIPs is the list of CIDRs that are always blocked.
+	DenyIPs []string
+	// BlockCountries is the list of ISO 3166-1 alpha-2 country codes to block. e.g. KP
+	BlockCountries []string
+}
+
+// Validate validates WAF. If WAF is invalid, it returns an error.
+func (w *WAF) Validate() (err error) {
+	for _, rule := range w.ManagedRules {
+		if _, ok := managedRuleGroups[rule]; !ok {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidWAF,
+				fmt.Sprintf("managed rule must be one of %s: %s", strings.Join(ManagedRuleNames(), ", "), rule)))
+		}
+	}
+	if w.RateLimit != 0 && (w.RateLimit < wafRateLimitMin || w.RateLimit > wafRateLimitMax) {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidWAF,
+			fmt.Sprintf("rate limit must be 0 or between %d and %d: %d", wafRateLimitMin, wafRateLimitMax, w.RateLimit)))
+	}
+	for _, cidr := range append(append([]string{}, w.AllowIPs...), w.DenyIPs...) {
+		if _, _, e := net.ParseCIDR(cidr); e != nil {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidWAF, fmt.Sprintf("invalid CIDR: %s", cidr)))
+		}
+	}
+	for _, country := range w.BlockCountries {
+		if !isCountryCode(country) {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidWAF, fmt.Sprintf("invalid ISO 3166-1 alpha-2 country code: %s", country)))
+		}
+	}
+	return err
+}
+
+// ManagedRuleGroupName returns the name of the AWS managed rule group. e.g. common -> AWSManagedRulesCommonRuleSet
+func (w *WAF) ManagedRuleGroupName(rule string) string {
+	return managedRuleGroups[rule]
+}
+
+// ManagedRuleNames returns the names of the managed rules that spare supports.
+func ManagedRuleNames() []string {
+	names := make([]string, 0, len(managedRuleGroups))
+	for name := range managedRuleGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IPv4 returns the IPv4 CIDRs in cidrs.
+func IPv4(cidrs []string) []string {
+	return filterCIDRs(cidrs, true)
+}
+
+// IPv6 returns the IPv6 CIDRs in cidrs.
+func IPv6(cidrs []string) []string {
+	return filterCIDRs(cidrs, false)
+}
+
+// filterCIDRs returns the IPv4 CIDRs if v4 is true. Otherwise, it returns the IPv6 CIDRs.
+func filterCIDRs(cidrs []string, v4 bool) []string {
+	filtered := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if (ip.To4() != nil) == v4 {
+			filtered = append(filtered, cidr)
+		}
+	}
+	return filtered
+}
+
+// isCountryCode returns true if s looks like an ISO 3166-1 alpha-2 country code.
+func isCountryCode(s string) bool {
+	const countryCodeLen = 2
+	if len(s) != countryCodeLen {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseCIDRList parses the list of CIDRs. The list has one CIDR (or IP address) per line.
+// Empty lines and the text after '#' are ignored. An IP address is converted to the CIDR of the single address.
+func ParseCIDRList(r io.Reader) ([]string, error) {
+	cidrs := []string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if !strings.Contains(text, "/") {
+			ip := net.ParseIP(text)
+			if ip == nil {
+				return nil, errfmt.Wrap(ErrInvalidWAF, fmt.Sprintf("line %d: invalid IP address: %s", line, text))
+			}
+			if ip.To4() != nil {
+				text += "/32"
+			} else {
+				text += "/128"
+			}
+		}
+		if _, _, err := net.ParseCIDR(text); err != nil {
+			return nil, errfmt.Wrap(ErrInvalidWAF, fmt.Sprintf("line %d: invalid CIDR: %s", line, text))
+		}
+		cidrs = append(cidrs, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cidrs, nil
+}
+
+// NewWAFResourceName returns the name of the WAF resource (web ACL or IP set) for the bucket.
+// e.g. spare-my-bucket, spare-my-bucket-allow-ipv4
+func NewWAFResourceName(bucket BucketName, suffix string) string {
+	name := fmt.Sprintf("spare-%s", bucket)
+	if suffix != "" {
+		name += "-" + suffix
+	}
+	name = strings.ReplaceAll(name, ".", "-")
+	if len(name) > wafNameMaxLen {
+		name = name[:wafNameMaxLen]
+	}
+	return name
+}
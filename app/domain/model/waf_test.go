@@ -0,0 +1,121 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWAFValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		w       *WAF
+		wantErr bool
+	}{
+		{
+			name: "success",
+			w: &WAF{
+				ManagedRules:   []string{"common", "knownBadInputs", "ipReputation"},
+				RateLimit:      2000,
+				AllowIPs:       []string{"192.0.2.0/24"},
+				DenyIPs:        []string{"2001:db8::/32"},
+				BlockCountries: []string{"KP"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "success. no rules",
+			w:       &WAF{},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown managed rule",
+			w:       &WAF{ManagedRules: []string{"sqlInjection"}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. rate limit is too small",
+			w:       &WAF{RateLimit: 10},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid CIDR",
+			w:       &WAF{DenyIPs: []string{"192.0.2.0/33"}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid country code",
+			w:       &WAF{BlockCountries: []string{"jp"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.w.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WAF.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidWAF) {
+				t.Errorf("WAF.Validate() error = %v, want %v", err, ErrInvalidWAF)
+			}
+		})
+	}
+}
+
+func TestParseCIDRList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			list:    "# office\n192.0.2.0/24\n\n198.51.100.1 # vpn\n2001:db8::1\n",
+			want:    []string{"192.0.2.0/24", "198.51.100.1/32", "2001:db8::1/128"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. invalid IP address",
+			list:    "192.0.2.0/24\n192.0.2.256\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCIDRList(strings.NewReader(tt.list))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCIDRList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIPv4IPv6(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		cidrs := []string{"192.0.2.0/24", "2001:db8::/32", "198.51.100.1/32"}
+		if diff := cmp.Diff([]string{"192.0.2.0/24", "198.51.100.1/32"}, IPv4(cidrs)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"2001:db8::/32"}, IPv6(cidrs)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -10,12 +10,14 @@ import (
 type CDNCreatorInput struct {
 	// BucketName is the name of the  bucket.
 	BucketName model.BucketName
-	// OAIID is the ID of the OAI.
-	OAIID *string
 }
 
 // CDNCreatorOutput is an output struct for CDNCreator.
 type CDNCreatorOutput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// ARN is the ARN of the CDN.
+	ARN string
 	// Domain is the domain of the CDN.
 	Domain model.Domain
 }
@@ -25,18 +27,368 @@ type CDNCreator interface {
 	CreateCDN(context.Context, *CDNCreatorInput) (*CDNCreatorOutput, error)
 }
 
-// OAICreatorInput is an input struct for OAICreator.
-type OAICreatorInput struct {
+// CDNFinderInput is an input struct for CDNFinder.
+type CDNFinderInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+}
+
+// CDNFinderOutput is an output struct for CDNFinder.
+type CDNFinderOutput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// ARN is the ARN of the CDN.
+	ARN string
+	// Domain is the domain of the CDN.
+	Domain model.Domain
+}
+
+// CDNFinder is an interface for finding the CDN whose origin is the bucket.
+type CDNFinder interface {
+	FindCDN(context.Context, *CDNFinderInput) (*CDNFinderOutput, error)
+}
+
+// CDNARNsListerInput is an input struct for CDNARNsLister.
+type CDNARNsListerInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDNs.
+	BucketName model.BucketName
+}
+
+// CDNARNsListerOutput is an output struct for CDNARNsLister.
+type CDNARNsListerOutput struct {
+	// ARNs is the ARNs of the CDNs. It's empty if the CDN does not exist.
+	ARNs []string
+}
+
+// CDNARNsLister is an interface for listing the ARNs of the CDNs whose origin is the bucket.
+// The staging CDN for the canary release is listed, too, because it reads the same objects.
+type CDNARNsLister interface {
+	ListCDNARNs(context.Context, *CDNARNsListerInput) (*CDNARNsListerOutput, error)
+}
+
+// CDNOriginPathUpdaterInput is an input struct for CDNOriginPathUpdater.
+type CDNOriginPathUpdaterInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// OriginPath is the directory in the bucket that the CDN requests content from.
+	OriginPath string
+}
+
+// CDNOriginPathUpdaterOutput is an output struct for CDNOriginPathUpdater.
+type CDNOriginPathUpdaterOutput struct{}
+
+// CDNOriginPathUpdater is an interface for updating the origin path of the CDN.
+type CDNOriginPathUpdater interface {
+	UpdateCDNOriginPath(context.Context, *CDNOriginPathUpdaterInput) (*CDNOriginPathUpdaterOutput, error)
+}
+
+// CDNCacheInvalidatorInput is an input struct for CDNCacheInvalidator.
+type CDNCacheInvalidatorInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// Paths is the list of paths to invalidate. e.g. /*
+	Paths []string
+}
+
+// CDNCacheInvalidatorOutput is an output struct for CDNCacheInvalidator.
+type CDNCacheInvalidatorOutput struct{}
+
+// CDNCacheInvalidator is an interface for removing the content from the CDN cache.
+type CDNCacheInvalidator interface {
+	InvalidateCDNCache(context.Context, *CDNCacheInvalidatorInput) (*CDNCacheInvalidatorOutput, error)
+}
+
+// CDNFunctionPublisherInput is an input struct for CDNFunctionPublisher.
+type CDNFunctionPublisherInput struct {
+	// Function is the function to publish.
+	Function *model.CDNFunction
+}
+
+// CDNFunctionPublisherOutput is an output struct for CDNFunctionPublisher.
+type CDNFunctionPublisherOutput struct {
+	// ARN is the ARN of the published function.
+	ARN string
+}
+
+// CDNFunctionPublisher is an interface for creating (or updating) and publishing the CDN function.
+type CDNFunctionPublisher interface {
+	PublishCDNFunction(context.Context, *CDNFunctionPublisherInput) (*CDNFunctionPublisherOutput, error)
+}
+
+// CDNPreviewRouteCreatorInput is an input struct for CDNPreviewRouteCreator.
+type CDNPreviewRouteCreatorInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// RouterFunctionARN is the ARN of the function that routes the preview requests.
+	RouterFunctionARN string
+}
+
+// CDNPreviewRouteCreatorOutput is an output struct for CDNPreviewRouteCreator.
+type CDNPreviewRouteCreatorOutput struct {
+	// Created is whether the route has been created. It's false if the route already exists.
+	Created bool
+}
+
+// CDNPreviewRouteCreator is an interface for creating the route for previews in the CDN.
+// The route serves /previews/* from the bucket without the origin path of the live release.
+type CDNPreviewRouteCreator interface {
+	CreateCDNPreviewRoute(context.Context, *CDNPreviewRouteCreatorInput) (*CDNPreviewRouteCreatorOutput, error)
+}
+
+// CDNStagingCreatorInput is an input struct for CDNStagingCreator.
+type CDNStagingCreatorInput struct {
+	// DistributionID is the ID of the primary CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+}
+
+// CDNStagingCreatorOutput is an output struct for CDNStagingCreator.
+type CDNStagingCreatorOutput struct {
+	// DistributionID is the ID of the staging CDN.
+	DistributionID model.DistributionID
+	// Domain is the domain of the staging CDN.
+	Domain model.Domain
+}
+
+// CDNStagingCreator is an interface for creating the staging CDN that is a copy of the primary CDN.
+// If the staging CDN already exists, it syncs the config of the staging CDN with the primary CDN and returns it.
+type CDNStagingCreator interface {
+	CreateCDNStaging(context.Context, *CDNStagingCreatorInput) (*CDNStagingCreatorOutput, error)
+}
+
+// CDNStagingSyncerInput is an input struct for CDNStagingSyncer.
+type CDNStagingSyncerInput struct {
+	// DistributionID is the ID of the primary CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+}
+
+// CDNStagingSyncerOutput is an output struct for CDNStagingSyncer.
+type CDNStagingSyncerOutput struct {
+	// Synced is whether the staging CDN exists and its config is synced.
+	Synced bool
+}
+
+// CDNStagingSyncer is an interface for replacing the config of the staging CDN with the config of the primary CDN.
+// The staging CDN keeps the origin path of the canary release. If there is no staging CDN, it does nothing.
+type CDNStagingSyncer interface {
+	SyncCDNStaging(context.Context, *CDNStagingSyncerInput) (*CDNStagingSyncerOutput, error)
+}
+
+// CDNStagingPromoterInput is an input struct for CDNStagingPromoter.
+type CDNStagingPromoterInput struct {
+	// DistributionID is the ID of the primary CDN.
+	DistributionID model.DistributionID
+}
+
+// CDNStagingPromoterOutput is an output struct for CDNStagingPromoter.
+type CDNStagingPromoterOutput struct{}
+
+// CDNStagingPromoter is an interface for copying the config of the staging CDN (including the origin path
+// of the canary release) to the primary CDN. So, the primary CDN serves what the canary viewers have tested.
+type CDNStagingPromoter interface {
+	PromoteCDNStaging(context.Context, *CDNStagingPromoterInput) (*CDNStagingPromoterOutput, error)
+}
+
+// CDNContinuousDeploymentPolicySetterInput is an input struct for CDNContinuousDeploymentPolicySetter.
+type CDNContinuousDeploymentPolicySetterInput struct {
+	// DistributionID is the ID of the primary CDN.
+	DistributionID model.DistributionID
+	// StagingDomain is the domain of the staging CDN.
+	StagingDomain model.Domain
+	// Traffic is how the primary CDN routes the viewers to the staging CDN.
+	Traffic model.CanaryTraffic
+}
+
+// CDNContinuousDeploymentPolicySetterOutput is an output struct for CDNContinuousDeploymentPolicySetter.
+type CDNContinuousDeploymentPolicySetterOutput struct{}
+
+// CDNContinuousDeploymentPolicySetter is an interface for enabling the continuous deployment policy
+// that routes a part of the viewers from the primary CDN to the staging CDN.
+type CDNContinuousDeploymentPolicySetter interface {
+	SetCDNContinuousDeploymentPolicy(context.Context, *CDNContinuousDeploymentPolicySetterInput) (*CDNContinuousDeploymentPolicySetterOutput, error)
+}
+
+// CDNContinuousDeploymentPolicyDisablerInput is an input struct for CDNContinuousDeploymentPolicyDisabler.
+type CDNContinuousDeploymentPolicyDisablerInput struct {
+	// DistributionID is the ID of the primary CDN.
+	DistributionID model.DistributionID
+}
+
+// CDNContinuousDeploymentPolicyDisablerOutput is an output struct for CDNContinuousDeploymentPolicyDisabler.
+type CDNContinuousDeploymentPolicyDisablerOutput struct{}
+
+// CDNContinuousDeploymentPolicyDisabler is an interface for disabling the continuous deployment policy.
+// After that, the primary CDN serves all viewers.
+type CDNContinuousDeploymentPolicyDisabler interface {
+	DisableCDNContinuousDeploymentPolicy(context.Context, *CDNContinuousDeploymentPolicyDisablerInput) (*CDNContinuousDeploymentPolicyDisablerOutput, error)
+}
+
+// CDNCacheBehaviorApplierInput is an input struct for CDNCacheBehaviorApplier.
+type CDNCacheBehaviorApplierInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// Cache is the cache behaviors to apply.
+	Cache *model.CacheSettings
+	// KeyGroupID is the ID of the key group that the signed cache behaviors trust.
+	// It's empty if no cache behavior is signed.
+	KeyGroupID string
+}
+
+// CDNCacheBehaviorApplierOutput is an output struct for CDNCacheBehaviorApplier.
+type CDNCacheBehaviorApplierOutput struct{}
+
+// CDNCacheBehaviorApplier is an interface for applying the cache behaviors and the cache policies to the CDN.
+// The cache behaviors that are not in the input are removed, except for the ones that spare manages (e.g. previews, custom origins).
+type CDNCacheBehaviorApplier interface {
+	ApplyCDNCacheBehaviors(context.Context, *CDNCacheBehaviorApplierInput) (*CDNCacheBehaviorApplierOutput, error)
+}
+
+// CDNCustomOriginApplierInput is an input struct for CDNCustomOriginApplier.
+type CDNCustomOriginApplierInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// Origins is the custom origins to apply. If it's empty, the custom origins created by spare are removed.
+	Origins model.CustomOrigins
+}
+
+// CDNCustomOriginApplierOutput is an output struct for CDNCustomOriginApplier.
+type CDNCustomOriginApplierOutput struct{}
+
+// CDNCustomOriginApplier is an interface for applying the custom origins (e.g. ALB, API Gateway) and their cache behaviors
+// to the CDN. The cache behaviors of the custom origins are evaluated before the cache behaviors of the bucket.
+// The custom origins that are not in the input are removed with their cache behaviors.
+type CDNCustomOriginApplier interface {
+	ApplyCDNCustomOrigins(context.Context, *CDNCustomOriginApplierInput) (*CDNCustomOriginApplierOutput, error)
+}
+
+// CDNResponseHeadersPolicyApplierInput is an input struct for CDNResponseHeadersPolicyApplier.
+type CDNResponseHeadersPolicyApplierInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// SecurityHeaders is the security headers that the CDN adds to the responses.
+	// If it's nil, the CDN does not add the security headers.
+	SecurityHeaders *model.SecurityHeaders
+	// CORS is the CORS settings of the CDN. If it's nil, the CDN does not add the CORS headers.
+	// If both SecurityHeaders and CORS are nil, the response headers policy is detached from the CDN.
+	CORS *model.CORS
+}
+
+// CDNResponseHeadersPolicyApplierOutput is an output struct for CDNResponseHeadersPolicyApplier.
+type CDNResponseHeadersPolicyApplierOutput struct{}
+
+// CDNResponseHeadersPolicyApplier is an interface for creating (or updating) the response headers policy
+// and attaching it to all cache behaviors of the CDN. If CORS is enabled, the cache behaviors of the bucket also
+// allow and cache the preflight (OPTIONS) requests.
+type CDNResponseHeadersPolicyApplier interface {
+	ApplyCDNResponseHeadersPolicy(context.Context, *CDNResponseHeadersPolicyApplierInput) (*CDNResponseHeadersPolicyApplierOutput, error)
+}
+
+// CDNResponseHeadersGetterInput is an input struct for CDNResponseHeadersGetter.
+type CDNResponseHeadersGetterInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+}
+
+// CDNResponseHeadersGetterOutput is an output struct for CDNResponseHeadersGetter.
+type CDNResponseHeadersGetterOutput struct {
+	// PolicyName is the name of the response headers policy attached to the default cache behavior.
+	// If no policy is attached, it's empty.
+	PolicyName string
+	// Headers is the list of headers that the CDN adds to the responses.
+	Headers []model.HTTPHeader
+}
+
+// CDNResponseHeadersGetter is an interface for getting the headers that the CDN adds to the responses.
+type CDNResponseHeadersGetter interface {
+	GetCDNResponseHeaders(context.Context, *CDNResponseHeadersGetterInput) (*CDNResponseHeadersGetterOutput, error)
+}
+
+// CDNWebACLAssociatorInput is an input struct for CDNWebACLAssociator.
+type CDNWebACLAssociatorInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// WebACLARN is the ARN of the web ACL. If it's empty, the web ACL is disassociated from the CDN.
+	WebACLARN string
+}
+
+// CDNWebACLAssociatorOutput is an output struct for CDNWebACLAssociator.
+type CDNWebACLAssociatorOutput struct{}
+
+// CDNWebACLAssociator is an interface for associating the web ACL with the CDN.
+type CDNWebACLAssociator interface {
+	AssociateCDNWebACL(context.Context, *CDNWebACLAssociatorInput) (*CDNWebACLAssociatorOutput, error)
+}
+
+// CDNViewerFunctionAssociatorInput is an input struct for CDNViewerFunctionAssociator.
+type CDNViewerFunctionAssociatorInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// FunctionARN is the ARN of the viewer request function. If it's empty, the function is disassociated from the CDN.
+	FunctionARN string
+	// ResponseFunctionARN is the ARN of the viewer response function. If it's empty, the function is disassociated from the CDN.
+	ResponseFunctionARN string
+}
+
+// CDNViewerFunctionAssociatorOutput is an output struct for CDNViewerFunctionAssociator.
+type CDNViewerFunctionAssociatorOutput struct {
+	// PreviewRoute is whether the CDN has the route for previews.
+	// The route uses the preview router function instead of the viewer request function.
+	PreviewRoute bool
+}
+
+// CDNViewerFunctionAssociator is an interface for associating the viewer request and response functions with the CDN.
+// The function is associated with all routes of the bucket except the route for previews.
+// The routes of the custom origins do not run the function.
+type CDNViewerFunctionAssociator interface {
+	AssociateCDNViewerFunction(context.Context, *CDNViewerFunctionAssociatorInput) (*CDNViewerFunctionAssociatorOutput, error)
+}
+
+// CDNLoggingApplierInput is an input struct for CDNLoggingApplier.
+type CDNLoggingApplierInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// Logging is the access logging settings. If it's nil, the standard logging is disabled.
+	Logging *model.AccessLogging
+}
+
+// CDNLoggingApplierOutput is an output struct for CDNLoggingApplier.
+type CDNLoggingApplierOutput struct{}
+
+// CDNLoggingApplier is an interface for enabling or disabling the standard logging of the CDN.
+type CDNLoggingApplier interface {
+	ApplyCDNLogging(context.Context, *CDNLoggingApplierInput) (*CDNLoggingApplierOutput, error)
+}
+
+// CDNOriginAccessControlApplierInput is an input struct for CDNOriginAccessControlApplier.
+type CDNOriginAccessControlApplierInput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
 }
 
-// OAICreatorOutput is an output struct for OAICreator.
-type OAICreatorOutput struct {
-	// ID is the ID of the OAI.
-	ID *string
+// CDNOriginAccessControlApplierOutput is an output struct for CDNOriginAccessControlApplier.
+type CDNOriginAccessControlApplierOutput struct {
+	// DistributionARN is the ARN of the CDN.
+	DistributionARN string
 }
 
-// OAICreator is an interface for creating OAI.
-// OAI is an Origin Access Identity.
-type OAICreator interface {
-	CreateOAI(context.Context, *OAICreatorInput) (*OAICreatorOutput, error)
+// CDNOriginAccessControlApplier is an interface for switching the S3 origins of the CDN to the origin access
+// control (OAC). The bucket policy allows only the CDNs that read through OAC, and CloudFront reads the objects
+// encrypted with SSE-KMS only through OAC.
+type CDNOriginAccessControlApplier interface {
+	ApplyCDNOriginAccessControl(context.Context, *CDNOriginAccessControlApplierInput) (*CDNOriginAccessControlApplierOutput, error)
 }
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// EncryptionKeyCreatorInput is an input struct for EncryptionKeyCreator.
+type EncryptionKeyCreatorInput struct {
+	// Alias is the alias of the KMS key (e.g. alias/spare-<bucket>).
+	Alias string
+	// Description is the description of the KMS key.
+	Description string
+}
+
+// EncryptionKeyCreatorOutput is an output struct for EncryptionKeyCreator.
+type EncryptionKeyCreatorOutput struct {
+	// ARN is the ARN of the KMS key.
+	ARN model.KMSKeyARN
+	// Created is whether the KMS key is created. If it's false, the KMS key already exists.
+	Created bool
+}
+
+// EncryptionKeyCreator is an interface for creating the KMS key with the alias.
+// If the alias already exists, it returns the KMS key of the alias.
+type EncryptionKeyCreator interface {
+	CreateEncryptionKey(context.Context, *EncryptionKeyCreatorInput) (*EncryptionKeyCreatorOutput, error)
+}
+
+// EncryptionKeyPolicyApplierInput is an input struct for EncryptionKeyPolicyApplier.
+type EncryptionKeyPolicyApplierInput struct {
+	// KeyID is the ARN or the alias of the KMS key.
+	KeyID string
+	// Statement is the statement that is added to the key policy.
+	// The statement with the same Sid is replaced, and the other statements are kept.
+	Statement model.Statement
+}
+
+// EncryptionKeyPolicyApplierOutput is an output struct for EncryptionKeyPolicyApplier.
+type EncryptionKeyPolicyApplierOutput struct{}
+
+// EncryptionKeyPolicyApplier is an interface for adding the statement to the key policy of the KMS key.
+type EncryptionKeyPolicyApplier interface {
+	ApplyEncryptionKeyPolicy(context.Context, *EncryptionKeyPolicyApplierInput) (*EncryptionKeyPolicyApplierOutput, error)
+}
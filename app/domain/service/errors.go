@@ -14,10 +14,74 @@ var (
 	ErrBucketPolicySet = errors.New("failed to set bucket policy")
 	// ErrCDNAlreadyExist is an error that occurs when the CDN already exists.
 	ErrCDNAlreadyExists = errors.New("CDN already exists")
-	// ErrOriginAccessIdentifyAlreadyExist is an error that occurs when the origin access identify already exists.
-	ErrOriginAccessIdentifyAlreadyExists = errors.New("origin access identify already exists")
+	// ErrCDNNotFound is an error that occurs when the CDN whose origin is the bucket does not exist.
+	ErrCDNNotFound = errors.New("CDN not found")
 	// ErrNotDetectContentType is an error that occurs when the content type cannot be detected.
 	ErrNotDetectContentType = errors.New("failed to detect content type")
 	// ErrFileUpload is an error that occurs when the file upload fails.
 	ErrFileUpload = errors.New("failed to upload file")
+	// ErrReleaseHistoryGet is an error that occurs when getting the release history fails.
+	ErrReleaseHistoryGet = errors.New("failed to get release history")
+	// ErrReleaseHistoryPut is an error that occurs when putting the release history fails.
+	ErrReleaseHistoryPut = errors.New("failed to put release history")
+	// ErrReleaseManifestPut is an error that occurs when putting the release manifest fails.
+	ErrReleaseManifestPut = errors.New("failed to put release manifest")
+	// ErrBucketObjectList is an error that occurs when listing objects in the bucket fails.
+	ErrBucketObjectList = errors.New("failed to list objects")
+	// ErrBucketObjectDelete is an error that occurs when deleting objects in the bucket fails.
+	ErrBucketObjectDelete = errors.New("failed to delete objects")
+	// ErrBucketObjectCopy is an error that occurs when copying objects in the bucket fails.
+	ErrBucketObjectCopy = errors.New("failed to copy objects")
+	// ErrPreviewListGet is an error that occurs when getting the preview list fails.
+	ErrPreviewListGet = errors.New("failed to get preview list")
+	// ErrPreviewListPut is an error that occurs when putting the preview list fails.
+	ErrPreviewListPut = errors.New("failed to put preview list")
+	// ErrCDNFunctionPublish is an error that occurs when publishing the CDN function fails.
+	ErrCDNFunctionPublish = errors.New("failed to publish CDN function")
+	// ErrCDNStagingNotFound is an error that occurs when the staging CDN of the continuous deployment policy does not exist.
+	ErrCDNStagingNotFound = errors.New("staging CDN not found")
+	// ErrBucketCORSSet is an error that occurs when setting the CORS rules of the bucket fails.
+	ErrBucketCORSSet = errors.New("failed to set bucket CORS rules")
+	// ErrMaintenanceGet is an error that occurs when getting the maintenance mode state fails.
+	ErrMaintenanceGet = errors.New("failed to get maintenance mode state")
+	// ErrMaintenancePut is an error that occurs when putting the maintenance mode state fails.
+	ErrMaintenancePut = errors.New("failed to put maintenance mode state")
+	// ErrViewerRulesGet is an error that occurs when getting the rules of the viewer request function fails.
+	ErrViewerRulesGet = errors.New("failed to get viewer request rules")
+	// ErrViewerRulesPut is an error that occurs when putting the rules of the viewer request function fails.
+	ErrViewerRulesPut = errors.New("failed to put viewer request rules")
+	// ErrKeyGroupNotFound is an error that occurs when the key group for the signed URLs does not exist.
+	ErrKeyGroupNotFound = errors.New("key group not found")
+	// ErrKeyGroupAlreadyExists is an error that occurs when the key group for the signed URLs already exists.
+	ErrKeyGroupAlreadyExists = errors.New("key group already exists")
+	// ErrBucketLoggingSet is an error that occurs when setting the server access logging of the bucket fails.
+	ErrBucketLoggingSet = errors.New("failed to set bucket logging")
+	// ErrBucketLifecycleSet is an error that occurs when setting the lifecycle rules of the bucket fails.
+	ErrBucketLifecycleSet = errors.New("failed to set bucket lifecycle rules")
+	// ErrBucketOwnershipSet is an error that occurs when setting the object ownership of the bucket fails.
+	ErrBucketOwnershipSet = errors.New("failed to set bucket object ownership")
+	// ErrBucketVersioningSet is an error that occurs when setting the versioning of the bucket fails.
+	ErrBucketVersioningSet = errors.New("failed to set bucket versioning")
+	// ErrBucketObjectGet is an error that occurs when getting an object in the bucket fails.
+	ErrBucketObjectGet = errors.New("failed to get object")
+	// ErrBucketEncryptionSet is an error that occurs when setting the default encryption of the bucket fails.
+	ErrBucketEncryptionSet = errors.New("failed to set bucket encryption")
+	// ErrEncryptionKeyCreate is an error that occurs when creating the KMS key fails.
+	ErrEncryptionKeyCreate = errors.New("failed to create kms key")
+	// ErrEncryptionKeyPolicyApply is an error that occurs when applying the key policy of the KMS key fails.
+	ErrEncryptionKeyPolicyApply = errors.New("failed to apply kms key policy")
+	// ErrResourceTag is an error that occurs when tagging the resources fails.
+	ErrResourceTag = errors.New("failed to tag resources")
+	// ErrTaggedResourceList is an error that occurs when listing the tagged resources fails.
+	ErrTaggedResourceList = errors.New("failed to list tagged resources")
+	// ErrCallerIdentityGet is an error that occurs when getting the identity of the credentials fails.
+	ErrCallerIdentityGet = errors.New("failed to get caller identity")
+	// ErrProfileFind is an error that occurs when reading the shared config files fails.
+	ErrProfileFind = errors.New("failed to find aws profile")
+	// ErrBucketHead is an error that occurs when checking the bucket fails.
+	ErrBucketHead = errors.New("failed to check bucket")
+	// ErrRegionOptInStatusGet is an error that occurs when getting the opt-in status of the region fails.
+	ErrRegionOptInStatusGet = errors.New("failed to get region opt-in status")
+	// ErrIAMActionsSimulate is an error that occurs when simulating the IAM policies fails.
+	ErrIAMActionsSimulate = errors.New("failed to simulate iam policies")
 )
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// IAMActionsSimulatorInput is an input struct for IAMActionsSimulator.
+type IAMActionsSimulatorInput struct {
+	// PrincipalARN is the ARN of the IAM user or the IAM role.
+	PrincipalARN string
+	// Permissions is the IAM actions and the resources to simulate.
+	Permissions []model.IAMPermission
+}
+
+// IAMActionsSimulatorOutput is an output struct for IAMActionsSimulator.
+type IAMActionsSimulatorOutput struct {
+	// Denied is the IAM actions that are not allowed. It's empty if all actions are allowed.
+	Denied []string
+}
+
+// IAMActionsSimulator is an interface for simulating whether the IAM policies of the principal allow the actions.
+// The simulation evaluates the identity-based policies, the permissions boundary and the organization SCPs,
+// but not the resource-based policies.
+type IAMActionsSimulator interface {
+	SimulateIAMActions(context.Context, *IAMActionsSimulatorInput) (*IAMActionsSimulatorOutput, error)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// CallerIdentityGetterInput is an input struct for CallerIdentityGetter.
+type CallerIdentityGetterInput struct{}
+
+// CallerIdentityGetterOutput is an output struct for CallerIdentityGetter.
+type CallerIdentityGetterOutput struct {
+	// Identity is the AWS account and the IAM identity of the credentials.
+	Identity model.CallerIdentity
+}
+
+// CallerIdentityGetter is an interface for getting the identity of the credentials.
+// It returns an error if the credentials can not be resolved or are invalid.
+type CallerIdentityGetter interface {
+	GetCallerIdentity(context.Context, *CallerIdentityGetterInput) (*CallerIdentityGetterOutput, error)
+}
+
+// ProfileFinderInput is an input struct for ProfileFinder.
+type ProfileFinderInput struct {
+	// Profile is the name of the AWS profile.
+	Profile model.AWSProfile
+}
+
+// ProfileFinderOutput is an output struct for ProfileFinder.
+type ProfileFinderOutput struct {
+	// Found is whether the profile is in the shared config file or the shared credentials file.
+	Found bool
+	// Files is the paths of the shared config file and the shared credentials file that are searched.
+	Files []string
+	// SSO is whether the profile gets the credentials from AWS IAM Identity Center (AWS SSO).
+	// The credentials of the profile need 'aws sso login'.
+	SSO bool
+	// EnvCredentials is whether the credentials are set in the environment variables.
+	// They are used instead of the profile, so the profile does not have to exist.
+	EnvCredentials bool
+}
+
+// ProfileFinder is an interface for finding the AWS profile in the shared config files (~/.aws/config, ~/.aws/credentials).
+type ProfileFinder interface {
+	FindProfile(context.Context, *ProfileFinderInput) (*ProfileFinderOutput, error)
+}
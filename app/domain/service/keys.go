@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// KeyGroupGetterInput is an input struct for KeyGroupGetter.
+type KeyGroupGetterInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+}
+
+// KeyGroupGetterOutput is an output struct for KeyGroupGetter.
+type KeyGroupGetterOutput struct {
+	// ID is the ID of the key group.
+	ID string
+	// PublicKeyIDs is the IDs of the public keys in the key group from the oldest to the newest.
+	// The ID of the public key is the key pair ID of the signed URLs.
+	PublicKeyIDs []string
+}
+
+// KeyGroupGetter is an interface for getting the key group that spare created for the bucket.
+// If the key group does not exist, it returns ErrKeyGroupNotFound.
+type KeyGroupGetter interface {
+	GetKeyGroup(context.Context, *KeyGroupGetterInput) (*KeyGroupGetterOutput, error)
+}
+
+// KeyGroupApplierInput is an input struct for KeyGroupApplier.
+type KeyGroupApplierInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// PublicKeyIDs is the IDs of the public keys in the key group from the oldest to the newest.
+	PublicKeyIDs []string
+}
+
+// KeyGroupApplierOutput is an output struct for KeyGroupApplier.
+type KeyGroupApplierOutput struct {
+	// ID is the ID of the key group.
+	ID string
+}
+
+// KeyGroupApplier is an interface for creating (or updating) the key group that the CDN trusts.
+type KeyGroupApplier interface {
+	ApplyKeyGroup(context.Context, *KeyGroupApplierInput) (*KeyGroupApplierOutput, error)
+}
+
+// PublicKeyCreatorInput is an input struct for PublicKeyCreator.
+type PublicKeyCreatorInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// PublicKey is the PEM encoded public key.
+	PublicKey []byte
+}
+
+// PublicKeyCreatorOutput is an output struct for PublicKeyCreator.
+type PublicKeyCreatorOutput struct {
+	// ID is the ID of the public key. It's the key pair ID of the signed URLs.
+	ID string
+}
+
+// PublicKeyCreator is an interface for registering the public key to the CDN.
+type PublicKeyCreator interface {
+	CreatePublicKey(context.Context, *PublicKeyCreatorInput) (*PublicKeyCreatorOutput, error)
+}
+
+// PublicKeyDeleterInput is an input struct for PublicKeyDeleter.
+type PublicKeyDeleterInput struct {
+	// ID is the ID of the public key.
+	ID string
+}
+
+// PublicKeyDeleterOutput is an output struct for PublicKeyDeleter.
+type PublicKeyDeleterOutput struct{}
+
+// PublicKeyDeleter is an interface for deleting the public key. The public key must not be in any key group.
+type PublicKeyDeleter interface {
+	DeletePublicKey(context.Context, *PublicKeyDeleterInput) (*PublicKeyDeleterOutput, error)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// RegionOptInStatusGetterInput is an input struct for RegionOptInStatusGetter.
+type RegionOptInStatusGetterInput struct {
+	// Region is the opt-in region.
+	Region model.Region
+}
+
+// RegionOptInStatusGetterOutput is an output struct for RegionOptInStatusGetter.
+type RegionOptInStatusGetterOutput struct {
+	// Enabled is whether the region can be used by the account.
+	Enabled bool
+	// Status is the opt-in status of the region. e.g. ENABLED, DISABLED, ENABLING
+	Status string
+}
+
+// RegionOptInStatusGetter is an interface for getting whether the opt-in region is enabled in the account.
+type RegionOptInStatusGetter interface {
+	GetRegionOptInStatus(context.Context, *RegionOptInStatusGetterInput) (*RegionOptInStatusGetterOutput, error)
+}
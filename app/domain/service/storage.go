@@ -38,6 +38,11 @@ type FileUploaderInput struct {
 	Key string
 	// Data is the data to upload.
 	Data io.Reader
+	// Headers is the headers that are stored as the object metadata. e.g. Cache-Control
+	// The Content-Type header overrides the detected MIME type, and X-Amz-Meta-* headers are stored as the user-defined metadata.
+	Headers []model.HTTPHeader
+	// Encryption is the server-side encryption of the object. If it's nil, the default encryption of the bucket is used.
+	Encryption *model.Encryption
 }
 
 // FileUploaderOutput is an output struct for FileUploader.
@@ -68,6 +73,25 @@ type BucketCreator interface {
 	CreateBucket(context.Context, *BucketCreatorInput) (*BucketCreatorOutput, error)
 }
 
+// BucketAvailabilityCheckerInput is an input struct for BucketAvailabilityChecker.
+type BucketAvailabilityCheckerInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Account is the AWS account ID that is expected to own the bucket.
+	Account string
+}
+
+// BucketAvailabilityCheckerOutput is an output struct for BucketAvailabilityChecker.
+type BucketAvailabilityCheckerOutput struct {
+	// Availability is whether the bucket name can be used by the account.
+	Availability model.BucketAvailability
+}
+
+// BucketAvailabilityChecker is an interface for checking whether the bucket is owned by the account or the name is available.
+type BucketAvailabilityChecker interface {
+	CheckBucketAvailability(context.Context, *BucketAvailabilityCheckerInput) (*BucketAvailabilityCheckerOutput, error)
+}
+
 // BucketPublicAccessBlockerInput is an input struct for BucketAccessBlocker.
 type BucketPublicAccessBlockerInput struct {
 	// Bucket is the name of the  bucket.
@@ -99,3 +123,305 @@ type BucketPolicySetterOutput struct{}
 type BucketPolicySetter interface {
 	SetBucketPolicy(context.Context, *BucketPolicySetterInput) (*BucketPolicySetterOutput, error)
 }
+
+// BucketCORSSetterInput is an input struct for BucketCORSSetter.
+type BucketCORSSetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// CORS is the CORS settings to set. If it's nil, the CORS rules are deleted from the bucket.
+	CORS *model.CORS
+}
+
+// BucketCORSSetterOutput is an output struct for BucketCORSSetter.
+type BucketCORSSetterOutput struct{}
+
+// BucketCORSSetter is an interface for setting the CORS rules of a bucket.
+type BucketCORSSetter interface {
+	SetBucketCORS(context.Context, *BucketCORSSetterInput) (*BucketCORSSetterOutput, error)
+}
+
+// ReleaseHistoryGetterInput is an input struct for ReleaseHistoryGetter.
+type ReleaseHistoryGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+}
+
+// ReleaseHistoryGetterOutput is an output struct for ReleaseHistoryGetter.
+type ReleaseHistoryGetterOutput struct {
+	// History is the release history. If no release has been deployed yet, it's empty.
+	History *model.ReleaseHistory
+}
+
+// ReleaseHistoryGetter is an interface for getting the release history from external storage.
+type ReleaseHistoryGetter interface {
+	GetReleaseHistory(context.Context, *ReleaseHistoryGetterInput) (*ReleaseHistoryGetterOutput, error)
+}
+
+// ReleaseHistoryPutterInput is an input struct for ReleaseHistoryPutter.
+type ReleaseHistoryPutterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// History is the release history to put.
+	History *model.ReleaseHistory
+}
+
+// ReleaseHistoryPutterOutput is an output struct for ReleaseHistoryPutter.
+type ReleaseHistoryPutterOutput struct{}
+
+// ReleaseHistoryPutter is an interface for putting the release history to external storage.
+type ReleaseHistoryPutter interface {
+	PutReleaseHistory(context.Context, *ReleaseHistoryPutterInput) (*ReleaseHistoryPutterOutput, error)
+}
+
+// ReleaseManifestPutterInput is an input struct for ReleaseManifestPutter.
+type ReleaseManifestPutterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Manifest is the release manifest to put.
+	Manifest *model.ReleaseManifest
+}
+
+// ReleaseManifestPutterOutput is an output struct for ReleaseManifestPutter.
+type ReleaseManifestPutterOutput struct{}
+
+// ReleaseManifestPutter is an interface for putting the release manifest to external storage.
+type ReleaseManifestPutter interface {
+	PutReleaseManifest(context.Context, *ReleaseManifestPutterInput) (*ReleaseManifestPutterOutput, error)
+}
+
+// BucketObjectListerInput is an input struct for BucketObjectLister.
+type BucketObjectListerInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Prefix is the S3 key prefix of the objects to list. If it's empty, all objects are listed.
+	Prefix string
+}
+
+// BucketObjectListerOutput is an output struct for BucketObjectLister.
+type BucketObjectListerOutput struct {
+	// Objects is the list of objects.
+	Objects model.BucketObjects
+}
+
+// BucketObjectLister is an interface for listing objects in a bucket.
+type BucketObjectLister interface {
+	ListBucketObjects(context.Context, *BucketObjectListerInput) (*BucketObjectListerOutput, error)
+}
+
+// BucketObjectDeleterInput is an input struct for BucketObjectDeleter.
+type BucketObjectDeleterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Keys is the list of S3 keys to delete.
+	Keys []string
+}
+
+// BucketObjectDeleterOutput is an output struct for BucketObjectDeleter.
+type BucketObjectDeleterOutput struct{}
+
+// BucketObjectDeleter is an interface for deleting objects in a bucket.
+type BucketObjectDeleter interface {
+	DeleteBucketObjects(context.Context, *BucketObjectDeleterInput) (*BucketObjectDeleterOutput, error)
+}
+
+// PreviewListGetterInput is an input struct for PreviewListGetter.
+type PreviewListGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+}
+
+// PreviewListGetterOutput is an output struct for PreviewListGetter.
+type PreviewListGetterOutput struct {
+	// List is the preview list. If no preview has been deployed yet, it's empty.
+	List *model.PreviewList
+}
+
+// PreviewListGetter is an interface for getting the preview list from external storage.
+type PreviewListGetter interface {
+	GetPreviewList(context.Context, *PreviewListGetterInput) (*PreviewListGetterOutput, error)
+}
+
+// PreviewListPutterInput is an input struct for PreviewListPutter.
+type PreviewListPutterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// List is the preview list to put.
+	List *model.PreviewList
+}
+
+// PreviewListPutterOutput is an output struct for PreviewListPutter.
+type PreviewListPutterOutput struct{}
+
+// PreviewListPutter is an interface for putting the preview list to external storage.
+type PreviewListPutter interface {
+	PutPreviewList(context.Context, *PreviewListPutterInput) (*PreviewListPutterOutput, error)
+}
+
+// MaintenanceGetterInput is an input struct for MaintenanceGetter.
+type MaintenanceGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+}
+
+// MaintenanceGetterOutput is an output struct for MaintenanceGetter.
+type MaintenanceGetterOutput struct {
+	// Maintenance is the maintenance mode. If the maintenance mode is off, it's nil.
+	Maintenance *model.Maintenance
+}
+
+// MaintenanceGetter is an interface for getting the maintenance mode state from external storage.
+type MaintenanceGetter interface {
+	GetMaintenance(context.Context, *MaintenanceGetterInput) (*MaintenanceGetterOutput, error)
+}
+
+// MaintenancePutterInput is an input struct for MaintenancePutter.
+type MaintenancePutterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Maintenance is the maintenance mode to put. If it's nil, the state is deleted (the maintenance mode is off).
+	Maintenance *model.Maintenance
+}
+
+// MaintenancePutterOutput is an output struct for MaintenancePutter.
+type MaintenancePutterOutput struct{}
+
+// MaintenancePutter is an interface for putting the maintenance mode state to external storage.
+type MaintenancePutter interface {
+	PutMaintenance(context.Context, *MaintenancePutterInput) (*MaintenancePutterOutput, error)
+}
+
+// ViewerRulesGetterInput is an input struct for ViewerRulesGetter.
+type ViewerRulesGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+}
+
+// ViewerRulesGetterOutput is an output struct for ViewerRulesGetter.
+type ViewerRulesGetterOutput struct {
+	// Rules is the rules of the published viewer request function. If they are not recorded, it's nil.
+	Rules *model.ViewerRules
+}
+
+// ViewerRulesGetter is an interface for getting the rules of the viewer request function from external storage.
+type ViewerRulesGetter interface {
+	GetViewerRules(context.Context, *ViewerRulesGetterInput) (*ViewerRulesGetterOutput, error)
+}
+
+// ViewerRulesPutterInput is an input struct for ViewerRulesPutter.
+type ViewerRulesPutterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Rules is the rules of the published viewer request function.
+	Rules *model.ViewerRules
+}
+
+// ViewerRulesPutterOutput is an output struct for ViewerRulesPutter.
+type ViewerRulesPutterOutput struct{}
+
+// ViewerRulesPutter is an interface for putting the rules of the viewer request function to external storage.
+type ViewerRulesPutter interface {
+	PutViewerRules(context.Context, *ViewerRulesPutterInput) (*ViewerRulesPutterOutput, error)
+}
+
+// BucketOwnershipSetterInput is an input struct for BucketOwnershipSetter.
+type BucketOwnershipSetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Ownership is the object ownership to set.
+	Ownership model.ObjectOwnership
+}
+
+// BucketOwnershipSetterOutput is an output struct for BucketOwnershipSetter.
+type BucketOwnershipSetterOutput struct{}
+
+// BucketOwnershipSetter is an interface for setting the object ownership of a bucket.
+type BucketOwnershipSetter interface {
+	SetBucketOwnership(context.Context, *BucketOwnershipSetterInput) (*BucketOwnershipSetterOutput, error)
+}
+
+// BucketLifecycleSetterInput is an input struct for BucketLifecycleSetter.
+type BucketLifecycleSetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Rules is the lifecycle rules to set. If it's empty, the lifecycle configuration is deleted from the bucket.
+	Rules []model.LifecycleRule
+}
+
+// BucketLifecycleSetterOutput is an output struct for BucketLifecycleSetter.
+type BucketLifecycleSetterOutput struct{}
+
+// BucketLifecycleSetter is an interface for setting the lifecycle rules of a bucket.
+type BucketLifecycleSetter interface {
+	SetBucketLifecycle(context.Context, *BucketLifecycleSetterInput) (*BucketLifecycleSetterOutput, error)
+}
+
+// BucketVersioningSetterInput is an input struct for BucketVersioningSetter.
+type BucketVersioningSetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Enabled is whether the bucket keeps the previous versions of the objects.
+	Enabled bool
+}
+
+// BucketVersioningSetterOutput is an output struct for BucketVersioningSetter.
+type BucketVersioningSetterOutput struct {
+	// Status is the versioning state of the bucket after the setting.
+	Status model.VersioningStatus
+}
+
+// BucketVersioningSetter is an interface for setting the versioning of a bucket.
+type BucketVersioningSetter interface {
+	SetBucketVersioning(context.Context, *BucketVersioningSetterInput) (*BucketVersioningSetterOutput, error)
+}
+
+// BucketEncryptionSetterInput is an input struct for BucketEncryptionSetter.
+type BucketEncryptionSetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Encryption is the default encryption of the bucket. KMSKeyID must be the ARN of the KMS key with SSE-KMS.
+	Encryption *model.Encryption
+}
+
+// BucketEncryptionSetterOutput is an output struct for BucketEncryptionSetter.
+type BucketEncryptionSetterOutput struct{}
+
+// BucketEncryptionSetter is an interface for setting the default encryption of a bucket.
+// The bucket key is enabled with SSE-KMS to reduce the requests to KMS.
+type BucketEncryptionSetter interface {
+	SetBucketEncryption(context.Context, *BucketEncryptionSetterInput) (*BucketEncryptionSetterOutput, error)
+}
+
+// BucketLoggingSetterInput is an input struct for BucketLoggingSetter.
+type BucketLoggingSetterInput struct {
+	// Bucket is the name of the bucket whose server access logs are delivered.
+	Bucket model.BucketName
+	// Logging is the access logging settings. If it's nil, the server access logging is disabled.
+	Logging *model.AccessLogging
+}
+
+// BucketLoggingSetterOutput is an output struct for BucketLoggingSetter.
+type BucketLoggingSetterOutput struct{}
+
+// BucketLoggingSetter is an interface for setting the server access logging of a bucket.
+type BucketLoggingSetter interface {
+	SetBucketLogging(context.Context, *BucketLoggingSetterInput) (*BucketLoggingSetterOutput, error)
+}
+
+// BucketObjectGetterInput is an input struct for BucketObjectGetter.
+type BucketObjectGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.BucketName
+	// Key is the S3 key of the object.
+	Key string
+}
+
+// BucketObjectGetterOutput is an output struct for BucketObjectGetter.
+type BucketObjectGetterOutput struct {
+	// Data is the content of the object.
+	Data []byte
+}
+
+// BucketObjectGetter is an interface for getting an object in a bucket.
+type BucketObjectGetter interface {
+	GetBucketObject(context.Context, *BucketObjectGetterInput) (*BucketObjectGetterOutput, error)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// ResourceTaggerInput is an input struct for ResourceTagger.
+type ResourceTaggerInput struct {
+	// ARNs is the ARNs of the resources.
+	ARNs []string
+	// Tags is the tags to add. The tags with the same key are overwritten, and the other tags are kept.
+	Tags model.Tags
+}
+
+// ResourceTaggerOutput is an output struct for ResourceTagger.
+type ResourceTaggerOutput struct{}
+
+// ResourceTagger is an interface for adding the tags to the resources.
+type ResourceTagger interface {
+	TagResources(context.Context, *ResourceTaggerInput) (*ResourceTaggerOutput, error)
+}
+
+// TaggedResourceListerInput is an input struct for TaggedResourceLister.
+type TaggedResourceListerInput struct {
+	// TagKey is the tag key that the resources have. The value of the tag is not filtered.
+	TagKey string
+}
+
+// TaggedResourceListerOutput is an output struct for TaggedResourceLister.
+type TaggedResourceListerOutput struct {
+	// Resources is the resources that have the tag key.
+	Resources []model.TaggedResource
+}
+
+// TaggedResourceLister is an interface for listing the resources that have the tag key
+// in the region and in the global services (e.g. CloudFront).
+type TaggedResourceLister interface {
+	ListTaggedResources(context.Context, *TaggedResourceListerInput) (*TaggedResourceListerOutput, error)
+}
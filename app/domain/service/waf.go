@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// WebACLApplierInput is an input struct for WebACLApplier.
+type WebACLApplierInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// WAF is the rules of the web ACL.
+	WAF *model.WAF
+}
+
+// WebACLApplierOutput is an output struct for WebACLApplier.
+type WebACLApplierOutput struct {
+	// ARN is the ARN of the web ACL.
+	ARN string
+}
+
+// WebACLApplier is an interface for creating (or updating) the web ACL that protects the CDN.
+type WebACLApplier interface {
+	ApplyWebACL(context.Context, *WebACLApplierInput) (*WebACLApplierOutput, error)
+}
+
+// WebACLDeleterInput is an input struct for WebACLDeleter.
+type WebACLDeleterInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+}
+
+// WebACLDeleterOutput is an output struct for WebACLDeleter.
+type WebACLDeleterOutput struct {
+	// Deleted is whether the web ACL has been deleted. It's false if the web ACL does not exist,
+	// or if the web ACL is still associated with the CDN.
+	Deleted bool
+}
+
+// WebACLDeleter is an interface for deleting the web ACL and the IP sets created by spare.
+type WebACLDeleter interface {
+	DeleteWebACL(context.Context, *WebACLDeleterInput) (*WebACLDeleterOutput, error)
+}
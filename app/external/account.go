@@ -0,0 +1,50 @@
+package external
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/account"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// RegionOptInStatusGetterSet is a provider set for RegionOptInStatusGetter.
+//
+//nolint:gochecknoglobals
+var RegionOptInStatusGetterSet = wire.NewSet(
+	NewAccountRegionOptInStatusGetter,
+	wire.Bind(new(service.RegionOptInStatusGetter), new(*AccountRegionOptInStatusGetter)),
+)
+
+// AccountRegionOptInStatusGetter is an implementation for RegionOptInStatusGetter.
+type AccountRegionOptInStatusGetter struct {
+	*account.Account
+}
+
+var _ service.RegionOptInStatusGetter = &AccountRegionOptInStatusGetter{}
+
+// NewAccountRegionOptInStatusGetter returns a new AccountRegionOptInStatusGetter struct.
+// The Account Management API is called in the global region of the partition.
+func NewAccountRegionOptInStatusGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *AccountRegionOptInStatusGetter {
+	return &AccountRegionOptInStatusGetter{
+		Account: account.New(newS3Session(credentials, region.Partition().GlobalRegion(), endpoint)),
+	}
+}
+
+// GetRegionOptInStatus returns whether the opt-in region is enabled in the account.
+func (a *AccountRegionOptInStatusGetter) GetRegionOptInStatus(ctx context.Context, input *service.RegionOptInStatusGetterInput) (*service.RegionOptInStatusGetterOutput, error) {
+	output, err := a.GetRegionOptStatusWithContext(ctx, &account.GetRegionOptStatusInput{
+		RegionName: aws.String(input.Region.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrRegionOptInStatusGet, err.Error())
+	}
+	status := aws.StringValue(output.RegionOptStatus)
+	return &service.RegionOptInStatusGetterOutput{
+		Enabled: status == account.RegionOptStatusEnabled || status == account.RegionOptStatusEnabledByDefault,
+		Status:  status,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -27,29 +28,44 @@ var CDNCreatorSet = wire.NewSet(
 // CloudFrontCDNCreator is an implementation for CDNCreator.
 type CloudFrontCDNCreator struct {
 	*cloudfront.CloudFront
+	// region is the region of the bucket.
+	region model.Region
 }
 
 var _ service.CDNCreator = &CloudFrontCDNCreator{}
 
 // NewCloudFrontCDNCreator returns a new CloudFrontCDNCreator struct.
-func NewCloudFrontCDNCreator(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNCreator {
+func NewCloudFrontCDNCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNCreator {
 	return &CloudFrontCDNCreator{
-		CloudFront: cloudfront.New(newS3Session(profile, region, endpoint)),
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+		region:     region,
 	}
 }
 
-// CreateCDN creates a CDN.
+const (
+	// s3OriginID is the ID of the origin that serves the live release.
+	s3OriginID = "S3 Origin ID Generated by Spare"
+	// previewOriginID is the ID of the origin that serves the previews.
+	// It's the same bucket as s3OriginID, but without the origin path.
+	previewOriginID = "S3 Preview Origin ID Generated by Spare"
+	// previewPathPattern is the path pattern of the cache behavior for the previews.
+	previewPathPattern = "previews/*"
+	// initialCachePolicyID is the ID of the managed cache policy (CachingOptimized) for the new distribution.
+	initialCachePolicyID = "658327ea-f89d-4fab-a63d-7e88639e58f6"
+)
+
+// CreateCDN creates a CDN. The S3 origin has no OAI, and CDNOriginAccessControlApplier switches it to OAC.
 func (c *CloudFrontCDNCreator) CreateCDN(_ context.Context, input *service.CDNCreatorInput) (*service.CDNCreatorOutput, error) {
 	createDistributionInput := &cloudfront.CreateDistributionInput{
 		DistributionConfig: &cloudfront.DistributionConfig{
 			Comment:         aws.String("CloudFront Distribution Generated by Spare"),
 			CallerReference: aws.String(uuid.New().String()),
 			DefaultCacheBehavior: &cloudfront.DefaultCacheBehavior{
-				TargetOriginId:       aws.String("S3 Origin ID Generated by Spare"),
+				TargetOriginId:       aws.String(s3OriginID),
 				ViewerProtocolPolicy: aws.String("redirect-to-https"),
-				MinTTL:               aws.Int64(300), //nolint:gomnd
-				MaxTTL:               aws.Int64(300), //nolint:gomnd
-				DefaultTTL:           aws.Int64(300), //nolint:gomnd
+				// The cache policy is replaced with the one in .spare.yml by CDNCacheBehaviorApplier.
+				CachePolicyId: aws.String(initialCachePolicyID),
+				Compress:      aws.Bool(true),
 				AllowedMethods: &cloudfront.AllowedMethods{
 					Items: []*string{
 						aws.String("GET"),
@@ -65,13 +81,6 @@ func (c *CloudFrontCDNCreator) CreateCDN(_ context.Context, input *service.CDNCr
 						Quantity: aws.Int64(2), //nolint:gomnd
 					},
 				},
-				// Deprecated fields
-				ForwardedValues: &cloudfront.ForwardedValues{
-					QueryString: aws.Bool(true),
-					Cookies: &cloudfront.CookiePreference{
-						Forward: aws.String("none"),
-					},
-				},
 			},
 			DefaultRootObject: aws.String("index.html"),
 			HttpVersion:       aws.String("http2and3"),
@@ -79,10 +88,10 @@ func (c *CloudFrontCDNCreator) CreateCDN(_ context.Context, input *service.CDNCr
 			Origins: &cloudfront.Origins{
 				Items: []*cloudfront.Origin{
 					{
-						Id:         aws.String("S3 Origin ID Generated by Spare"),
-						DomainName: aws.String(input.BucketName.Domain()),
+						Id:         aws.String(s3OriginID),
+						DomainName: aws.String(input.BucketName.Domain(c.region)),
 						S3OriginConfig: &cloudfront.S3OriginConfig{
-							OriginAccessIdentity: aws.String(fmt.Sprintf("origin-access-identity/cloudfront/%s", *input.OAIID)),
+							OriginAccessIdentity: aws.String(""),
 						},
 					},
 				},
@@ -106,55 +115,1998 @@ func (c *CloudFrontCDNCreator) CreateCDN(_ context.Context, input *service.CDNCr
 	}
 
 	return &service.CDNCreatorOutput{
-		Domain: model.Domain(*output.Distribution.DomainName),
+		DistributionID: model.DistributionID(aws.StringValue(output.Distribution.Id)),
+		ARN:            aws.StringValue(output.Distribution.ARN),
+		Domain:         model.Domain(*output.Distribution.DomainName),
+	}, nil
+}
+
+// CDNFinderSet is a provider set for CDNFinder.
+//
+//nolint:gochecknoglobals
+var CDNFinderSet = wire.NewSet(
+	NewCDNFinder,
+)
+
+// CloudFrontCDNFinder is an implementation for CDNFinder.
+type CloudFrontCDNFinder struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNFinder = &CloudFrontCDNFinder{}
+
+// NewCloudFrontCDNFinder returns a new CloudFrontCDNFinder struct.
+func NewCloudFrontCDNFinder(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNFinder {
+	return &CloudFrontCDNFinder{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// FindCDN finds the CloudFront distribution whose origin is the bucket.
+func (c *CloudFrontCDNFinder) FindCDN(ctx context.Context, input *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	var found *cloudfront.DistributionSummary
+	err := c.ListDistributionsPagesWithContext(ctx, &cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, _ bool) bool {
+			if page.DistributionList == nil {
+				return false
+			}
+			for _, summary := range page.DistributionList.Items {
+				// The staging distribution for the canary release has the same origin as the primary.
+				if aws.BoolValue(summary.Staging) {
+					continue
+				}
+				if hasBucketOrigin(summary.Origins, input.BucketName) {
+					found = summary
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to list cloudfront distributions")
+	}
+	if found == nil {
+		return nil, errfmt.Wrap(service.ErrCDNNotFound, fmt.Sprintf("origin bucket is %s", input.BucketName))
+	}
+	return &service.CDNFinderOutput{
+		DistributionID: model.DistributionID(aws.StringValue(found.Id)),
+		ARN:            aws.StringValue(found.ARN),
+		Domain:         model.Domain(aws.StringValue(found.DomainName)),
+	}, nil
+}
+
+// CDNARNsListerSet is a provider set for CDNARNsLister.
+//
+//nolint:gochecknoglobals
+var CDNARNsListerSet = wire.NewSet(
+	NewCloudFrontCDNARNsLister,
+	wire.Bind(new(service.CDNARNsLister), new(*CloudFrontCDNARNsLister)),
+)
+
+// CloudFrontCDNARNsLister is an implementation for CDNARNsLister.
+type CloudFrontCDNARNsLister struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNARNsLister = &CloudFrontCDNARNsLister{}
+
+// NewCloudFrontCDNARNsLister returns a new CloudFrontCDNARNsLister struct.
+func NewCloudFrontCDNARNsLister(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNARNsLister {
+	return &CloudFrontCDNARNsLister{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ListCDNARNs lists the ARNs of the CloudFront distributions whose origin is the bucket,
+// including the staging distribution for the canary release.
+func (c *CloudFrontCDNARNsLister) ListCDNARNs(ctx context.Context, input *service.CDNARNsListerInput) (*service.CDNARNsListerOutput, error) {
+	arns := []string{}
+	err := c.ListDistributionsPagesWithContext(ctx, &cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, _ bool) bool {
+			if page.DistributionList == nil {
+				return false
+			}
+			for _, summary := range page.DistributionList.Items {
+				if hasBucketOrigin(summary.Origins, input.BucketName) {
+					arns = append(arns, aws.StringValue(summary.ARN))
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to list cloudfront distributions")
+	}
+	return &service.CDNARNsListerOutput{
+		ARNs: arns,
 	}, nil
 }
 
-// OAICreatorSet is a provider set for OAICreator.
+// hasBucketOrigin returns true if origins contain the bucket.
+func hasBucketOrigin(origins *cloudfront.Origins, bucket model.BucketName) bool {
+	return findBucketOrigin(origins, bucket) != nil
+}
+
+// findBucketOrigin returns the origin whose domain is the bucket. If not found, it returns nil.
+// The origin for the previews is ignored, because it's not the origin of the live release.
+func findBucketOrigin(origins *cloudfront.Origins, bucket model.BucketName) *cloudfront.Origin {
+	if origins == nil {
+		return nil
+	}
+	for _, origin := range origins.Items {
+		if aws.StringValue(origin.Id) == previewOriginID {
+			continue
+		}
+		if bucket.IsDomain(aws.StringValue(origin.DomainName)) {
+			return origin
+		}
+	}
+	return nil
+}
+
+// CDNOriginPathUpdaterSet is a provider set for CDNOriginPathUpdater.
+//
+//nolint:gochecknoglobals
+var CDNOriginPathUpdaterSet = wire.NewSet(
+	NewCDNOriginPathUpdater,
+)
+
+// CloudFrontCDNOriginPathUpdater is an implementation for CDNOriginPathUpdater.
+type CloudFrontCDNOriginPathUpdater struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNOriginPathUpdater = &CloudFrontCDNOriginPathUpdater{}
+
+// NewCloudFrontCDNOriginPathUpdater returns a new CloudFrontCDNOriginPathUpdater struct.
+func NewCloudFrontCDNOriginPathUpdater(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNOriginPathUpdater {
+	return &CloudFrontCDNOriginPathUpdater{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// UpdateCDNOriginPath updates the origin path of the bucket origin.
+func (c *CloudFrontCDNOriginPathUpdater) UpdateCDNOriginPath(ctx context.Context, input *service.CDNOriginPathUpdaterInput) (*service.CDNOriginPathUpdaterOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+
+	origin := findBucketOrigin(config.DistributionConfig.Origins, input.BucketName)
+	if origin == nil {
+		return nil, errfmt.Wrap(service.ErrCDNNotFound, fmt.Sprintf("origin bucket is %s", input.BucketName))
+	}
+	origin.OriginPath = aws.String(input.OriginPath)
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: config.DistributionConfig,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNOriginPathUpdaterOutput{}, nil
+}
+
+// CDNCacheInvalidatorSet is a provider set for CDNCacheInvalidator.
 //
 //nolint:gochecknoglobals
-var OAICreatorSet = wire.NewSet(
-	NewCloudFrontOAICreator,
-	wire.Bind(new(service.OAICreator), new(*CloudFrontOAICreator)),
+var CDNCacheInvalidatorSet = wire.NewSet(
+	NewCDNCacheInvalidator,
 )
 
-// CloudFrontOAICreator is an implementation for OAICreator.
-type CloudFrontOAICreator struct {
+// CloudFrontCDNCacheInvalidator is an implementation for CDNCacheInvalidator.
+type CloudFrontCDNCacheInvalidator struct {
 	*cloudfront.CloudFront
 }
 
-var _ service.OAICreator = &CloudFrontOAICreator{}
+var _ service.CDNCacheInvalidator = &CloudFrontCDNCacheInvalidator{}
 
-// NewCloudFrontOAICreator returns a new CloudFrontOAICreator struct.
-func NewCloudFrontOAICreator(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *CloudFrontOAICreator {
-	return &CloudFrontOAICreator{
-		CloudFront: cloudfront.New(newS3Session(profile, region, endpoint)),
+// NewCloudFrontCDNCacheInvalidator returns a new CloudFrontCDNCacheInvalidator struct.
+func NewCloudFrontCDNCacheInvalidator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNCacheInvalidator {
+	return &CloudFrontCDNCacheInvalidator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
 	}
 }
 
-// CreateOAI creates a new OAI.
-func (c *CloudFrontOAICreator) CreateOAI(_ context.Context, _ *service.OAICreatorInput) (*service.OAICreatorOutput, error) {
-	createOAIInput := &cloudfront.CreateCloudFrontOriginAccessIdentityInput{
-		CloudFrontOriginAccessIdentityConfig: &cloudfront.OriginAccessIdentityConfig{
+// InvalidateCDNCache creates an invalidation for the paths.
+func (c *CloudFrontCDNCacheInvalidator) InvalidateCDNCache(ctx context.Context, input *service.CDNCacheInvalidatorInput) (*service.CDNCacheInvalidatorOutput, error) {
+	if _, err := c.CreateInvalidationWithContext(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(input.DistributionID.String()),
+		InvalidationBatch: &cloudfront.InvalidationBatch{
 			CallerReference: aws.String(uuid.NewString()),
-			Comment:         aws.String("Origin Access Identity (OAI) Generated by Spare"),
+			Paths: &cloudfront.Paths{
+				Items:    aws.StringSlice(input.Paths),
+				Quantity: aws.Int64(int64(len(input.Paths))),
+			},
 		},
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to create a cloudfront invalidation")
+	}
+	return &service.CDNCacheInvalidatorOutput{}, nil
+}
+
+// CDNFunctionPublisherSet is a provider set for CDNFunctionPublisher.
+//
+//nolint:gochecknoglobals
+var CDNFunctionPublisherSet = wire.NewSet(
+	NewCloudFrontCDNFunctionPublisher,
+	wire.Bind(new(service.CDNFunctionPublisher), new(*CloudFrontCDNFunctionPublisher)),
+)
+
+// CloudFrontCDNFunctionPublisher is an implementation for CDNFunctionPublisher.
+type CloudFrontCDNFunctionPublisher struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNFunctionPublisher = &CloudFrontCDNFunctionPublisher{}
+
+// NewCloudFrontCDNFunctionPublisher returns a new CloudFrontCDNFunctionPublisher struct.
+func NewCloudFrontCDNFunctionPublisher(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNFunctionPublisher {
+	return &CloudFrontCDNFunctionPublisher{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// PublishCDNFunction creates the CloudFront Function if it does not exist, otherwise updates it.
+// Then, it publishes the function to the LIVE stage.
+func (c *CloudFrontCDNFunctionPublisher) PublishCDNFunction(ctx context.Context, input *service.CDNFunctionPublisherInput) (*service.CDNFunctionPublisherOutput, error) {
+	name := aws.String(input.Function.Name.String())
+	config := &cloudfront.FunctionConfig{
+		Comment: aws.String(input.Function.Comment),
+		Runtime: aws.String(cloudfront.FunctionRuntimeCloudfrontJs10),
 	}
 
-	output, err := c.CreateCloudFrontOriginAccessIdentity(createOAIInput)
+	var etag *string
+	describeOutput, err := c.DescribeFunctionWithContext(ctx, &cloudfront.DescribeFunctionInput{
+		Name:  name,
+		Stage: aws.String(cloudfront.FunctionStageDevelopment),
+	})
 	if err != nil {
 		var awsErr awserr.Error
-		if errors.As(err, &awsErr) {
-			switch awsErr.Code() {
-			case cloudfront.ErrCodeOriginAccessControlAlreadyExists:
-				return nil, service.ErrOriginAccessIdentifyAlreadyExists
-			default:
+		if !errors.As(err, &awsErr) || awsErr.Code() != cloudfront.ErrCodeNoSuchFunctionExists {
+			return nil, errfmt.Wrap(service.ErrCDNFunctionPublish, err.Error())
+		}
+		createOutput, err := c.CreateFunctionWithContext(ctx, &cloudfront.CreateFunctionInput{
+			Name:           name,
+			FunctionConfig: config,
+			FunctionCode:   []byte(input.Function.Code),
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrCDNFunctionPublish, err.Error())
+		}
+		etag = createOutput.ETag
+	} else {
+		updateOutput, err := c.UpdateFunctionWithContext(ctx, &cloudfront.UpdateFunctionInput{
+			Name:           name,
+			IfMatch:        describeOutput.ETag,
+			FunctionConfig: config,
+			FunctionCode:   []byte(input.Function.Code),
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrCDNFunctionPublish, err.Error())
+		}
+		etag = updateOutput.ETag
+	}
+
+	publishOutput, err := c.PublishFunctionWithContext(ctx, &cloudfront.PublishFunctionInput{
+		Name:    name,
+		IfMatch: etag,
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrCDNFunctionPublish, err.Error())
+	}
+	return &service.CDNFunctionPublisherOutput{
+		ARN: aws.StringValue(publishOutput.FunctionSummary.FunctionMetadata.FunctionARN),
+	}, nil
+}
+
+// CDNPreviewRouteCreatorSet is a provider set for CDNPreviewRouteCreator.
+//
+//nolint:gochecknoglobals
+var CDNPreviewRouteCreatorSet = wire.NewSet(
+	NewCloudFrontCDNPreviewRouteCreator,
+	wire.Bind(new(service.CDNPreviewRouteCreator), new(*CloudFrontCDNPreviewRouteCreator)),
+)
+
+// CloudFrontCDNPreviewRouteCreator is an implementation for CDNPreviewRouteCreator.
+type CloudFrontCDNPreviewRouteCreator struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNPreviewRouteCreator = &CloudFrontCDNPreviewRouteCreator{}
+
+// NewCloudFrontCDNPreviewRouteCreator returns a new CloudFrontCDNPreviewRouteCreator struct.
+func NewCloudFrontCDNPreviewRouteCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNPreviewRouteCreator {
+	return &CloudFrontCDNPreviewRouteCreator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// CreateCDNPreviewRoute adds the origin without the origin path and the previews/* cache behavior to the distribution.
+// If the cache behavior already exists, it does nothing.
+func (c *CloudFrontCDNPreviewRouteCreator) CreateCDNPreviewRoute(ctx context.Context, input *service.CDNPreviewRouteCreatorInput) (*service.CDNPreviewRouteCreatorOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+	if findCacheBehavior(dist.CacheBehaviors, previewPathPattern) != nil {
+		return &service.CDNPreviewRouteCreatorOutput{Created: false}, nil
+	}
+
+	bucketOrigin := findBucketOrigin(dist.Origins, input.BucketName)
+	if bucketOrigin == nil {
+		return nil, errfmt.Wrap(service.ErrCDNNotFound, fmt.Sprintf("origin bucket is %s", input.BucketName))
+	}
+	dist.Origins.Items = append(dist.Origins.Items, &cloudfront.Origin{
+		Id:             aws.String(previewOriginID),
+		DomainName:     bucketOrigin.DomainName,
+		OriginPath:     aws.String(""),
+		S3OriginConfig: bucketOrigin.S3OriginConfig,
+	})
+	dist.Origins.Quantity = aws.Int64(int64(len(dist.Origins.Items)))
+
+	behavior := newCacheBehaviorFromDefault(dist.DefaultCacheBehavior, previewPathPattern, previewOriginID)
+	behavior.FunctionAssociations = &cloudfront.FunctionAssociations{
+		Items: []*cloudfront.FunctionAssociation{
+			{
+				EventType:   aws.String(cloudfront.EventTypeViewerRequest),
+				FunctionARN: aws.String(input.RouterFunctionARN),
+			},
+		},
+		Quantity: aws.Int64(1),
+	}
+	addCacheBehavior(dist, behavior)
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNPreviewRouteCreatorOutput{Created: true}, nil
+}
+
+// findCacheBehavior returns the cache behavior whose path pattern is pathPattern. If not found, it returns nil.
+func findCacheBehavior(behaviors *cloudfront.CacheBehaviors, pathPattern string) *cloudfront.CacheBehavior {
+	if behaviors == nil {
+		return nil
+	}
+	for _, b := range behaviors.Items {
+		if aws.StringValue(b.PathPattern) == pathPattern {
+			return b
+		}
+	}
+	return nil
+}
+
+// addCacheBehavior adds the cache behavior to the distribution config.
+func addCacheBehavior(dist *cloudfront.DistributionConfig, behavior *cloudfront.CacheBehavior) {
+	if dist.CacheBehaviors == nil {
+		dist.CacheBehaviors = &cloudfront.CacheBehaviors{}
+	}
+	dist.CacheBehaviors.Items = append(dist.CacheBehaviors.Items, behavior)
+	dist.CacheBehaviors.Quantity = aws.Int64(int64(len(dist.CacheBehaviors.Items)))
+}
+
+// newCacheBehaviorFromDefault returns a new cache behavior that has the same cache settings as the default cache behavior.
+func newCacheBehaviorFromDefault(d *cloudfront.DefaultCacheBehavior, pathPattern, originID string) *cloudfront.CacheBehavior {
+	return &cloudfront.CacheBehavior{
+		PathPattern:             aws.String(pathPattern),
+		TargetOriginId:          aws.String(originID),
+		ViewerProtocolPolicy:    d.ViewerProtocolPolicy,
+		AllowedMethods:          d.AllowedMethods,
+		CachePolicyId:           d.CachePolicyId,
+		OriginRequestPolicyId:   d.OriginRequestPolicyId,
+		ResponseHeadersPolicyId: d.ResponseHeadersPolicyId,
+		Compress:                d.Compress,
+		MinTTL:                  d.MinTTL,
+		MaxTTL:                  d.MaxTTL,
+		DefaultTTL:              d.DefaultTTL,
+		ForwardedValues:         d.ForwardedValues,
+		SmoothStreaming:         d.SmoothStreaming,
+		FieldLevelEncryptionId:  d.FieldLevelEncryptionId,
+		TrustedKeyGroups:        d.TrustedKeyGroups,
+		TrustedSigners:          d.TrustedSigners,
+		FunctionAssociations:    d.FunctionAssociations,
+	}
+}
+
+// CDNStagingCreatorSet is a provider set for CDNStagingCreator.
+//
+//nolint:gochecknoglobals
+var CDNStagingCreatorSet = wire.NewSet(
+	NewCloudFrontCDNStagingCreator,
+	wire.Bind(new(service.CDNStagingCreator), new(*CloudFrontCDNStagingCreator)),
+)
+
+// CloudFrontCDNStagingCreator is an implementation for CDNStagingCreator.
+type CloudFrontCDNStagingCreator struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNStagingCreator = &CloudFrontCDNStagingCreator{}
+
+// NewCloudFrontCDNStagingCreator returns a new CloudFrontCDNStagingCreator struct.
+func NewCloudFrontCDNStagingCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNStagingCreator {
+	return &CloudFrontCDNStagingCreator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// CreateCDNStaging copies the primary distribution as the staging distribution.
+// If the continuous deployment policy of the primary distribution already points to the staging distribution,
+// it replaces the config of the staging distribution with the config of the primary distribution, and returns it.
+// So, the canary release is always served with the current config of the primary distribution.
+func (c *CloudFrontCDNStagingCreator) CreateCDNStaging(ctx context.Context, input *service.CDNStagingCreatorInput) (*service.CDNStagingCreatorOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+
+	if policyID := aws.StringValue(config.DistributionConfig.ContinuousDeploymentPolicyId); policyID != "" {
+		output, err := findStagingDistribution(ctx, c.CloudFront, policyID)
+		if err == nil {
+			if err := syncStagingDistribution(ctx, c.CloudFront, config, output.DistributionID, input.BucketName); err != nil {
 				return nil, err
 			}
+			return output, nil
 		}
-		return nil, err
+		if !errors.Is(err, service.ErrCDNStagingNotFound) {
+			return nil, err
+		}
+	}
+
+	copyOutput, err := c.CopyDistributionWithContext(ctx, &cloudfront.CopyDistributionInput{
+		PrimaryDistributionId: aws.String(input.DistributionID.String()),
+		CallerReference:       aws.String(uuid.New().String()),
+		IfMatch:               config.ETag,
+		Staging:               aws.Bool(true),
+		Enabled:               aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to copy a cloudfront distribution")
+	}
+	return &service.CDNStagingCreatorOutput{
+		DistributionID: model.DistributionID(aws.StringValue(copyOutput.Distribution.Id)),
+		Domain:         model.Domain(aws.StringValue(copyOutput.Distribution.DomainName)),
+	}, nil
+}
+
+// findStagingDistribution returns the staging distribution of the continuous deployment policy.
+func findStagingDistribution(ctx context.Context, cf *cloudfront.CloudFront, policyID string) (*service.CDNStagingCreatorOutput, error) {
+	policy, err := cf.GetContinuousDeploymentPolicyWithContext(ctx, &cloudfront.GetContinuousDeploymentPolicyInput{
+		Id: aws.String(policyID),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront continuous deployment policy")
+	}
+	dnsNames := policy.ContinuousDeploymentPolicy.ContinuousDeploymentPolicyConfig.StagingDistributionDnsNames
+	if dnsNames == nil || len(dnsNames.Items) == 0 {
+		return nil, errfmt.Wrap(service.ErrCDNStagingNotFound, fmt.Sprintf("policy %s has no staging distribution", policyID))
+	}
+	domain := aws.StringValue(dnsNames.Items[0])
+
+	var found *cloudfront.DistributionSummary
+	err = cf.ListDistributionsPagesWithContext(ctx, &cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, _ bool) bool {
+			if page.DistributionList == nil {
+				return false
+			}
+			for _, summary := range page.DistributionList.Items {
+				if aws.BoolValue(summary.Staging) && aws.StringValue(summary.DomainName) == domain {
+					found = summary
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to list cloudfront distributions")
+	}
+	if found == nil {
+		return nil, errfmt.Wrap(service.ErrCDNStagingNotFound, fmt.Sprintf("staging domain is %s", domain))
 	}
-	return &service.OAICreatorOutput{
-		ID: output.CloudFrontOriginAccessIdentity.Id,
+	return &service.CDNStagingCreatorOutput{
+		DistributionID: model.DistributionID(aws.StringValue(found.Id)),
+		Domain:         model.Domain(domain),
 	}, nil
 }
+
+// syncStagingDistribution replaces the config of the staging distribution with the config of the primary distribution.
+// The bucket origin of the staging distribution keeps its origin path, so the staging distribution keeps serving the canary release.
+func syncStagingDistribution(ctx context.Context, cf *cloudfront.CloudFront, primary *cloudfront.GetDistributionConfigOutput, stagingID model.DistributionID, bucket model.BucketName) error {
+	staging, err := cf.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(stagingID.String()),
+	})
+	if err != nil {
+		return errfmt.Wrap(err, "failed to get a cloudfront staging distribution config")
+	}
+
+	config := primary.DistributionConfig
+	if origin := findBucketOrigin(config.Origins, bucket); origin != nil {
+		if stagingOrigin := findBucketOrigin(staging.DistributionConfig.Origins, bucket); stagingOrigin != nil {
+			origin.OriginPath = stagingOrigin.OriginPath
+		}
+	}
+	// The staging distribution can not have the alternate domain names and the continuous deployment policy.
+	config.CallerReference = staging.DistributionConfig.CallerReference
+	config.Aliases = staging.DistributionConfig.Aliases
+	config.ContinuousDeploymentPolicyId = staging.DistributionConfig.ContinuousDeploymentPolicyId
+	config.Staging = aws.Bool(true)
+	config.Enabled = aws.Bool(true)
+
+	if _, err := cf.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(stagingID.String()),
+		IfMatch:            staging.ETag,
+		DistributionConfig: config,
+	}); err != nil {
+		return errfmt.Wrap(err, "failed to update a cloudfront staging distribution")
+	}
+	return nil
+}
+
+// CDNStagingSyncerSet is a provider set for CDNStagingSyncer.
+//
+//nolint:gochecknoglobals
+var CDNStagingSyncerSet = wire.NewSet(
+	NewCloudFrontCDNStagingSyncer,
+	wire.Bind(new(service.CDNStagingSyncer), new(*CloudFrontCDNStagingSyncer)),
+)
+
+// CloudFrontCDNStagingSyncer is an implementation for CDNStagingSyncer.
+type CloudFrontCDNStagingSyncer struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNStagingSyncer = &CloudFrontCDNStagingSyncer{}
+
+// NewCloudFrontCDNStagingSyncer returns a new CloudFrontCDNStagingSyncer struct.
+func NewCloudFrontCDNStagingSyncer(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNStagingSyncer {
+	return &CloudFrontCDNStagingSyncer{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// SyncCDNStaging replaces the config of the staging distribution with the config of the primary distribution.
+// If the primary distribution has no staging distribution, it does nothing.
+func (c *CloudFrontCDNStagingSyncer) SyncCDNStaging(ctx context.Context, input *service.CDNStagingSyncerInput) (*service.CDNStagingSyncerOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	policyID := aws.StringValue(config.DistributionConfig.ContinuousDeploymentPolicyId)
+	if policyID == "" {
+		return &service.CDNStagingSyncerOutput{}, nil
+	}
+	staging, err := findStagingDistribution(ctx, c.CloudFront, policyID)
+	if errors.Is(err, service.ErrCDNStagingNotFound) {
+		return &service.CDNStagingSyncerOutput{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syncStagingDistribution(ctx, c.CloudFront, config, staging.DistributionID, input.BucketName); err != nil {
+		return nil, err
+	}
+	return &service.CDNStagingSyncerOutput{Synced: true}, nil
+}
+
+// CDNStagingPromoterSet is a provider set for CDNStagingPromoter.
+//
+//nolint:gochecknoglobals
+var CDNStagingPromoterSet = wire.NewSet(
+	NewCloudFrontCDNStagingPromoter,
+	wire.Bind(new(service.CDNStagingPromoter), new(*CloudFrontCDNStagingPromoter)),
+)
+
+// CloudFrontCDNStagingPromoter is an implementation for CDNStagingPromoter.
+type CloudFrontCDNStagingPromoter struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNStagingPromoter = &CloudFrontCDNStagingPromoter{}
+
+// NewCloudFrontCDNStagingPromoter returns a new CloudFrontCDNStagingPromoter struct.
+func NewCloudFrontCDNStagingPromoter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNStagingPromoter {
+	return &CloudFrontCDNStagingPromoter{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// PromoteCDNStaging copies the config of the staging distribution to the primary distribution with
+// UpdateDistributionWithStagingConfig. The primary distribution keeps its alternate domain names and
+// its continuous deployment policy.
+func (c *CloudFrontCDNStagingPromoter) PromoteCDNStaging(ctx context.Context, input *service.CDNStagingPromoterInput) (*service.CDNStagingPromoterOutput, error) {
+	primary, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	policyID := aws.StringValue(primary.DistributionConfig.ContinuousDeploymentPolicyId)
+	if policyID == "" {
+		return nil, errfmt.Wrap(service.ErrCDNStagingNotFound, fmt.Sprintf("distribution %s has no continuous deployment policy", input.DistributionID))
+	}
+	staging, err := findStagingDistribution(ctx, c.CloudFront, policyID)
+	if err != nil {
+		return nil, err
+	}
+	stagingConfig, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(staging.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront staging distribution config")
+	}
+
+	if _, err := c.UpdateDistributionWithStagingConfigWithContext(ctx, &cloudfront.UpdateDistributionWithStagingConfigInput{
+		Id:                    aws.String(input.DistributionID.String()),
+		StagingDistributionId: aws.String(staging.DistributionID.String()),
+		// The ETags of the primary distribution and the staging distribution, in this order.
+		IfMatch: aws.String(aws.StringValue(primary.ETag) + ", " + aws.StringValue(stagingConfig.ETag)),
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution with the staging config")
+	}
+	return &service.CDNStagingPromoterOutput{}, nil
+}
+
+// CDNContinuousDeploymentPolicySetterSet is a provider set for CDNContinuousDeploymentPolicySetter.
+//
+//nolint:gochecknoglobals
+var CDNContinuousDeploymentPolicySetterSet = wire.NewSet(
+	NewCloudFrontCDNContinuousDeploymentPolicySetter,
+	wire.Bind(new(service.CDNContinuousDeploymentPolicySetter), new(*CloudFrontCDNContinuousDeploymentPolicySetter)),
+)
+
+// CloudFrontCDNContinuousDeploymentPolicySetter is an implementation for CDNContinuousDeploymentPolicySetter.
+type CloudFrontCDNContinuousDeploymentPolicySetter struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNContinuousDeploymentPolicySetter = &CloudFrontCDNContinuousDeploymentPolicySetter{}
+
+// NewCloudFrontCDNContinuousDeploymentPolicySetter returns a new CloudFrontCDNContinuousDeploymentPolicySetter struct.
+func NewCloudFrontCDNContinuousDeploymentPolicySetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNContinuousDeploymentPolicySetter {
+	return &CloudFrontCDNContinuousDeploymentPolicySetter{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// SetCDNContinuousDeploymentPolicy enables the continuous deployment policy of the primary distribution.
+// If the primary distribution has no policy, it creates the policy and attaches it to the primary distribution.
+func (c *CloudFrontCDNContinuousDeploymentPolicySetter) SetCDNContinuousDeploymentPolicy(ctx context.Context, input *service.CDNContinuousDeploymentPolicySetterInput) (*service.CDNContinuousDeploymentPolicySetterOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+
+	policyConfig := &cloudfront.ContinuousDeploymentPolicyConfig{
+		Enabled: aws.Bool(true),
+		StagingDistributionDnsNames: &cloudfront.StagingDistributionDnsNames{
+			Items:    []*string{aws.String(input.StagingDomain.String())},
+			Quantity: aws.Int64(1),
+		},
+		TrafficConfig: newTrafficConfig(input.Traffic),
+	}
+
+	if policyID := config.DistributionConfig.ContinuousDeploymentPolicyId; aws.StringValue(policyID) != "" {
+		policy, err := c.GetContinuousDeploymentPolicyWithContext(ctx, &cloudfront.GetContinuousDeploymentPolicyInput{
+			Id: policyID,
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to get a cloudfront continuous deployment policy")
+		}
+		if _, err := c.UpdateContinuousDeploymentPolicyWithContext(ctx, &cloudfront.UpdateContinuousDeploymentPolicyInput{
+			Id:                               policyID,
+			IfMatch:                          policy.ETag,
+			ContinuousDeploymentPolicyConfig: policyConfig,
+		}); err != nil {
+			return nil, errfmt.Wrap(err, "failed to update a cloudfront continuous deployment policy")
+		}
+		return &service.CDNContinuousDeploymentPolicySetterOutput{}, nil
+	}
+
+	createOutput, err := c.CreateContinuousDeploymentPolicyWithContext(ctx, &cloudfront.CreateContinuousDeploymentPolicyInput{
+		ContinuousDeploymentPolicyConfig: policyConfig,
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to create a cloudfront continuous deployment policy")
+	}
+	config.DistributionConfig.ContinuousDeploymentPolicyId = createOutput.ContinuousDeploymentPolicy.Id
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: config.DistributionConfig,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNContinuousDeploymentPolicySetterOutput{}, nil
+}
+
+// newTrafficConfig returns the traffic config of the continuous deployment policy.
+func newTrafficConfig(traffic model.CanaryTraffic) *cloudfront.TrafficConfig {
+	if traffic.IsHeader() {
+		return &cloudfront.TrafficConfig{
+			Type: aws.String(cloudfront.ContinuousDeploymentPolicyTypeSingleHeader),
+			SingleHeaderConfig: &cloudfront.ContinuousDeploymentSingleHeaderConfig{
+				Header: aws.String(traffic.Header),
+				Value:  aws.String(traffic.HeaderValue),
+			},
+		}
+	}
+	return &cloudfront.TrafficConfig{
+		Type: aws.String(cloudfront.ContinuousDeploymentPolicyTypeSingleWeight),
+		SingleWeightConfig: &cloudfront.ContinuousDeploymentSingleWeightConfig{
+			Weight: aws.Float64(traffic.Ratio()),
+			// The viewer keeps seeing the same release while reloading the SPA.
+			SessionStickinessConfig: &cloudfront.SessionStickinessConfig{
+				IdleTTL:    aws.Int64(300),  //nolint:gomnd
+				MaximumTTL: aws.Int64(3600), //nolint:gomnd
+			},
+		},
+	}
+}
+
+// CDNContinuousDeploymentPolicyDisablerSet is a provider set for CDNContinuousDeploymentPolicyDisabler.
+//
+//nolint:gochecknoglobals
+var CDNContinuousDeploymentPolicyDisablerSet = wire.NewSet(
+	NewCloudFrontCDNContinuousDeploymentPolicyDisabler,
+	wire.Bind(new(service.CDNContinuousDeploymentPolicyDisabler), new(*CloudFrontCDNContinuousDeploymentPolicyDisabler)),
+)
+
+// CloudFrontCDNContinuousDeploymentPolicyDisabler is an implementation for CDNContinuousDeploymentPolicyDisabler.
+type CloudFrontCDNContinuousDeploymentPolicyDisabler struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNContinuousDeploymentPolicyDisabler = &CloudFrontCDNContinuousDeploymentPolicyDisabler{}
+
+// NewCloudFrontCDNContinuousDeploymentPolicyDisabler returns a new CloudFrontCDNContinuousDeploymentPolicyDisabler struct.
+func NewCloudFrontCDNContinuousDeploymentPolicyDisabler(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNContinuousDeploymentPolicyDisabler {
+	return &CloudFrontCDNContinuousDeploymentPolicyDisabler{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// DisableCDNContinuousDeploymentPolicy disables the continuous deployment policy of the primary distribution.
+// The policy and the staging distribution are kept for the next canary release.
+// If the primary distribution has no policy, it does nothing.
+func (c *CloudFrontCDNContinuousDeploymentPolicyDisabler) DisableCDNContinuousDeploymentPolicy(ctx context.Context, input *service.CDNContinuousDeploymentPolicyDisablerInput) (*service.CDNContinuousDeploymentPolicyDisablerOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	policyID := config.DistributionConfig.ContinuousDeploymentPolicyId
+	if aws.StringValue(policyID) == "" {
+		return &service.CDNContinuousDeploymentPolicyDisablerOutput{}, nil
+	}
+
+	policy, err := c.GetContinuousDeploymentPolicyWithContext(ctx, &cloudfront.GetContinuousDeploymentPolicyInput{
+		Id: policyID,
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront continuous deployment policy")
+	}
+	policyConfig := policy.ContinuousDeploymentPolicy.ContinuousDeploymentPolicyConfig
+	if !aws.BoolValue(policyConfig.Enabled) {
+		return &service.CDNContinuousDeploymentPolicyDisablerOutput{}, nil
+	}
+	policyConfig.Enabled = aws.Bool(false)
+
+	if _, err := c.UpdateContinuousDeploymentPolicyWithContext(ctx, &cloudfront.UpdateContinuousDeploymentPolicyInput{
+		Id:                               policyID,
+		IfMatch:                          policy.ETag,
+		ContinuousDeploymentPolicyConfig: policyConfig,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront continuous deployment policy")
+	}
+	return &service.CDNContinuousDeploymentPolicyDisablerOutput{}, nil
+}
+
+// CDNCacheBehaviorApplierSet is a provider set for CDNCacheBehaviorApplier.
+//
+//nolint:gochecknoglobals
+var CDNCacheBehaviorApplierSet = wire.NewSet(
+	NewCloudFrontCDNCacheBehaviorApplier,
+	wire.Bind(new(service.CDNCacheBehaviorApplier), new(*CloudFrontCDNCacheBehaviorApplier)),
+)
+
+// CloudFrontCDNCacheBehaviorApplier is an implementation for CDNCacheBehaviorApplier.
+type CloudFrontCDNCacheBehaviorApplier struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNCacheBehaviorApplier = &CloudFrontCDNCacheBehaviorApplier{}
+
+// NewCloudFrontCDNCacheBehaviorApplier returns a new CloudFrontCDNCacheBehaviorApplier struct.
+func NewCloudFrontCDNCacheBehaviorApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNCacheBehaviorApplier {
+	return &CloudFrontCDNCacheBehaviorApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyCDNCacheBehaviors creates (or updates) the custom cache policies, and then replaces
+// the cache behaviors of the distribution. The cache behavior for the previews is kept,
+// and it uses the same cache policy and key group as the default cache behavior.
+// The cache behaviors of the custom origins are kept in front of the others, because CDNCustomOriginApplier manages them.
+func (c *CloudFrontCDNCacheBehaviorApplier) ApplyCDNCacheBehaviors(ctx context.Context, input *service.CDNCacheBehaviorApplierInput) (*service.CDNCacheBehaviorApplierOutput, error) {
+	policyIDs := make(map[string]string, len(input.Cache.Behaviors)+1)
+	for _, b := range input.Cache.All() {
+		id, err := c.cachePolicyID(ctx, input.BucketName, b)
+		if err != nil {
+			return nil, err
+		}
+		policyIDs[b.PathPattern] = id
+	}
+
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+
+	d := dist.DefaultCacheBehavior
+	d.CachePolicyId = aws.String(policyIDs[""])
+	d.OriginRequestPolicyId = optionalString(input.Cache.Default.OriginRequestPolicyID())
+	d.Compress = aws.Bool(input.Cache.Default.Compress)
+	d.TrustedKeyGroups = newTrustedKeyGroups(input.Cache.Default.Signed, input.KeyGroupID)
+	// The legacy cache settings can not be used with the cache policy.
+	d.ForwardedValues, d.MinTTL, d.DefaultTTL, d.MaxTTL = nil, nil, nil, nil
+
+	behaviors := make([]*cloudfront.CacheBehavior, 0, len(input.Cache.Behaviors)+1)
+	if dist.CacheBehaviors != nil {
+		for _, b := range dist.CacheBehaviors.Items {
+			if model.IsCustomOriginID(aws.StringValue(b.TargetOriginId)) {
+				behaviors = append(behaviors, b)
+			}
+		}
+	}
+	if preview := findCacheBehavior(dist.CacheBehaviors, previewPathPattern); preview != nil {
+		preview.CachePolicyId = d.CachePolicyId
+		preview.OriginRequestPolicyId = d.OriginRequestPolicyId
+		preview.Compress = d.Compress
+		preview.TrustedKeyGroups = d.TrustedKeyGroups
+		preview.ForwardedValues, preview.MinTTL, preview.DefaultTTL, preview.MaxTTL = nil, nil, nil, nil
+		behaviors = append(behaviors, preview)
+	}
+	for _, b := range input.Cache.Behaviors {
+		behavior := newCacheBehaviorFromDefault(d, b.PathPattern, s3OriginID)
+		behavior.CachePolicyId = aws.String(policyIDs[b.PathPattern])
+		behavior.OriginRequestPolicyId = optionalString(b.OriginRequestPolicyID())
+		behavior.Compress = aws.Bool(b.Compress)
+		behavior.TrustedKeyGroups = newTrustedKeyGroups(b.Signed, input.KeyGroupID)
+		behaviors = append(behaviors, behavior)
+	}
+	dist.CacheBehaviors = &cloudfront.CacheBehaviors{
+		Items:    behaviors,
+		Quantity: aws.Int64(int64(len(behaviors))),
+	}
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNCacheBehaviorApplierOutput{}, nil
+}
+
+// newTrustedKeyGroups returns the key groups that the cache behavior trusts.
+// If signed is false, the viewers can access the cache behavior without the signed URLs.
+func newTrustedKeyGroups(signed bool, keyGroupID string) *cloudfront.TrustedKeyGroups {
+	if !signed {
+		return &cloudfront.TrustedKeyGroups{
+			Enabled:  aws.Bool(false),
+			Quantity: aws.Int64(0),
+		}
+	}
+	return &cloudfront.TrustedKeyGroups{
+		Enabled:  aws.Bool(true),
+		Items:    aws.StringSlice([]string{keyGroupID}),
+		Quantity: aws.Int64(1),
+	}
+}
+
+// cachePolicyID returns the ID of the cache policy for the cache behavior.
+// If the cache behavior does not use the managed cache policy, it creates or updates the custom cache policy.
+func (c *CloudFrontCDNCacheBehaviorApplier) cachePolicyID(ctx context.Context, bucket model.BucketName, b model.CacheBehavior) (string, error) {
+	if id, ok := b.ManagedCachePolicyID(); ok {
+		return id, nil
+	}
+
+	policyConfig := newCachePolicyConfig(b.CachePolicyName(bucket), b)
+	id, err := c.findCustomCachePolicy(ctx, aws.StringValue(policyConfig.Name))
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		output, err := c.CreateCachePolicyWithContext(ctx, &cloudfront.CreateCachePolicyInput{
+			CachePolicyConfig: policyConfig,
+		})
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to create a cloudfront cache policy")
+		}
+		return aws.StringValue(output.CachePolicy.Id), nil
+	}
+
+	policy, err := c.GetCachePolicyWithContext(ctx, &cloudfront.GetCachePolicyInput{
+		Id: aws.String(id),
+	})
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to get a cloudfront cache policy")
+	}
+	if _, err := c.UpdateCachePolicyWithContext(ctx, &cloudfront.UpdateCachePolicyInput{
+		Id:                aws.String(id),
+		IfMatch:           policy.ETag,
+		CachePolicyConfig: policyConfig,
+	}); err != nil {
+		return "", errfmt.Wrap(err, "failed to update a cloudfront cache policy")
+	}
+	return id, nil
+}
+
+// findCustomCachePolicy returns the ID of the custom cache policy whose name is name.
+// If not found, it returns empty string.
+func (c *CloudFrontCDNCacheBehaviorApplier) findCustomCachePolicy(ctx context.Context, name string) (string, error) {
+	input := &cloudfront.ListCachePoliciesInput{
+		Type: aws.String(cloudfront.CachePolicyTypeCustom),
+	}
+	for {
+		output, err := c.ListCachePoliciesWithContext(ctx, input)
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to list cloudfront cache policies")
+		}
+		if output.CachePolicyList == nil {
+			return "", nil
+		}
+		for _, summary := range output.CachePolicyList.Items {
+			if aws.StringValue(summary.CachePolicy.CachePolicyConfig.Name) == name {
+				return aws.StringValue(summary.CachePolicy.Id), nil
+			}
+		}
+		if aws.StringValue(output.CachePolicyList.NextMarker) == "" {
+			return "", nil
+		}
+		input.Marker = output.CachePolicyList.NextMarker
+	}
+}
+
+// newCachePolicyConfig returns the custom cache policy config for the cache behavior.
+func newCachePolicyConfig(name string, b model.CacheBehavior) *cloudfront.CachePolicyConfig {
+	queryStrings := &cloudfront.CachePolicyQueryStringsConfig{
+		QueryStringBehavior: aws.String(cloudfront.CachePolicyQueryStringBehaviorNone),
+	}
+	switch {
+	case b.AllQueryStrings():
+		queryStrings.QueryStringBehavior = aws.String(cloudfront.CachePolicyQueryStringBehaviorAll)
+	case len(b.QueryStrings) > 0:
+		queryStrings.QueryStringBehavior = aws.String(cloudfront.CachePolicyQueryStringBehaviorWhitelist)
+		queryStrings.QueryStrings = &cloudfront.QueryStringNames{
+			Items:    aws.StringSlice(b.QueryStrings),
+			Quantity: aws.Int64(int64(len(b.QueryStrings))),
+		}
+	}
+
+	headers := &cloudfront.CachePolicyHeadersConfig{
+		HeaderBehavior: aws.String(cloudfront.CachePolicyHeaderBehaviorNone),
+	}
+	if len(b.Headers) > 0 {
+		headers.HeaderBehavior = aws.String(cloudfront.CachePolicyHeaderBehaviorWhitelist)
+		headers.Headers = &cloudfront.Headers{
+			Items:    aws.StringSlice(b.Headers),
+			Quantity: aws.Int64(int64(len(b.Headers))),
+		}
+	}
+
+	// When the caching is disabled, CloudFront does not allow the compression in the cache key.
+	acceptEncoding := b.Compress && !b.CachingDisabled()
+	return &cloudfront.CachePolicyConfig{
+		Name:       aws.String(name),
+		Comment:    aws.String(fmt.Sprintf("Cache policy for %s generated by spare", cachePolicyTarget(b))),
+		MinTTL:     aws.Int64(b.MinTTL),
+		DefaultTTL: aws.Int64(b.DefaultTTL),
+		MaxTTL:     aws.Int64(b.MaxTTL),
+		ParametersInCacheKeyAndForwardedToOrigin: &cloudfront.ParametersInCacheKeyAndForwardedToOrigin{
+			EnableAcceptEncodingGzip:   aws.Bool(acceptEncoding),
+			EnableAcceptEncodingBrotli: aws.Bool(acceptEncoding),
+			CookiesConfig: &cloudfront.CachePolicyCookiesConfig{
+				CookieBehavior: aws.String(cloudfront.CachePolicyCookieBehaviorNone),
+			},
+			HeadersConfig:      headers,
+			QueryStringsConfig: queryStrings,
+		},
+	}
+}
+
+// cachePolicyTarget returns the description of the cache behavior. e.g. default, /assets/*
+func cachePolicyTarget(b model.CacheBehavior) string {
+	if b.IsDefault() {
+		return "default"
+	}
+	return b.PathPattern
+}
+
+// optionalString returns nil if s is empty. Otherwise, it returns the pointer of s.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+// CDNCustomOriginApplierSet is a provider set for CDNCustomOriginApplier.
+//
+//nolint:gochecknoglobals
+var CDNCustomOriginApplierSet = wire.NewSet(
+	NewCloudFrontCDNCustomOriginApplier,
+	wire.Bind(new(service.CDNCustomOriginApplier), new(*CloudFrontCDNCustomOriginApplier)),
+)
+
+// CloudFrontCDNCustomOriginApplier is an implementation for CDNCustomOriginApplier.
+type CloudFrontCDNCustomOriginApplier struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNCustomOriginApplier = &CloudFrontCDNCustomOriginApplier{}
+
+// NewCloudFrontCDNCustomOriginApplier returns a new CloudFrontCDNCustomOriginApplier struct.
+func NewCloudFrontCDNCustomOriginApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNCustomOriginApplier {
+	return &CloudFrontCDNCustomOriginApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyCDNCustomOrigins creates (or updates) the origin request policies of the custom origins, and then replaces
+// the custom origins and their cache behaviors of the distribution. The cache behaviors of the custom origins
+// are placed in front of the others, so that they take precedence over the cache behaviors of the bucket.
+func (c *CloudFrontCDNCustomOriginApplier) ApplyCDNCustomOrigins(ctx context.Context, input *service.CDNCustomOriginApplierInput) (*service.CDNCustomOriginApplierOutput, error) {
+	policyIDs := make(map[string]string, len(input.Origins))
+	for _, o := range input.Origins {
+		id, err := c.upsertOriginRequestPolicy(ctx, newOriginRequestPolicyConfig(o.OriginRequestPolicyName(input.BucketName), o))
+		if err != nil {
+			return nil, err
+		}
+		policyIDs[o.Name] = id
+	}
+
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+	if len(input.Origins) == 0 && !hasCustomOrigin(dist.Origins) {
+		return &service.CDNCustomOriginApplierOutput{}, nil
+	}
+
+	origins := []*cloudfront.Origin{}
+	for _, origin := range dist.Origins.Items {
+		if !model.IsCustomOriginID(aws.StringValue(origin.Id)) {
+			origins = append(origins, origin)
+		}
+	}
+	behaviors := []*cloudfront.CacheBehavior{}
+	for _, o := range input.Origins {
+		origins = append(origins, newCustomOrigin(o))
+		cachePolicyID, _ := o.ManagedCachePolicyID()
+		for _, p := range o.PathPatterns {
+			behaviors = append(behaviors, newCustomOriginCacheBehavior(o, p, cachePolicyID, policyIDs[o.Name]))
+		}
+	}
+	if dist.CacheBehaviors != nil {
+		for _, b := range dist.CacheBehaviors.Items {
+			if !model.IsCustomOriginID(aws.StringValue(b.TargetOriginId)) {
+				behaviors = append(behaviors, b)
+			}
+		}
+	}
+	dist.Origins = &cloudfront.Origins{
+		Items:    origins,
+		Quantity: aws.Int64(int64(len(origins))),
+	}
+	dist.CacheBehaviors = &cloudfront.CacheBehaviors{
+		Items:    behaviors,
+		Quantity: aws.Int64(int64(len(behaviors))),
+	}
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNCustomOriginApplierOutput{}, nil
+}
+
+// hasCustomOrigin returns true if the origins have the custom origin generated by spare.
+func hasCustomOrigin(origins *cloudfront.Origins) bool {
+	if origins == nil {
+		return false
+	}
+	for _, origin := range origins.Items {
+		if model.IsCustomOriginID(aws.StringValue(origin.Id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCustomOrigin returns the origin config of the custom origin.
+func newCustomOrigin(o model.CustomOrigin) *cloudfront.Origin {
+	return &cloudfront.Origin{
+		Id:                aws.String(o.OriginID()),
+		DomainName:        aws.String(o.DomainName),
+		OriginPath:        aws.String(o.OriginPath),
+		ConnectionTimeout: aws.Int64(o.ConnectionTimeout),
+		CustomHeaders: &cloudfront.CustomHeaders{
+			Quantity: aws.Int64(0),
+		},
+		CustomOriginConfig: &cloudfront.CustomOriginConfig{
+			HTTPPort:               aws.Int64(o.HTTPPort),
+			HTTPSPort:              aws.Int64(o.HTTPSPort),
+			OriginProtocolPolicy:   aws.String(o.Protocol.String()),
+			OriginReadTimeout:      aws.Int64(o.ReadTimeout),
+			OriginKeepaliveTimeout: aws.Int64(o.KeepaliveTimeout),
+			OriginSslProtocols: &cloudfront.OriginSslProtocols{
+				Items:    aws.StringSlice([]string{cloudfront.SslProtocolTlsv12}),
+				Quantity: aws.Int64(1),
+			},
+		},
+	}
+}
+
+// newCustomOriginCacheBehavior returns the cache behavior that routes the path pattern to the custom origin.
+// It allows all methods, because the custom origins are usually APIs.
+func newCustomOriginCacheBehavior(o model.CustomOrigin, pathPattern, cachePolicyID, originRequestPolicyID string) *cloudfront.CacheBehavior {
+	methods := []string{
+		cloudfront.MethodGet, cloudfront.MethodHead, cloudfront.MethodOptions, cloudfront.MethodPut,
+		cloudfront.MethodPost, cloudfront.MethodPatch, cloudfront.MethodDelete,
+	}
+	cachedMethods := []string{cloudfront.MethodGet, cloudfront.MethodHead}
+	return &cloudfront.CacheBehavior{
+		PathPattern:           aws.String(pathPattern),
+		TargetOriginId:        aws.String(o.OriginID()),
+		ViewerProtocolPolicy:  aws.String(cloudfront.ViewerProtocolPolicyRedirectToHttps),
+		CachePolicyId:         aws.String(cachePolicyID),
+		OriginRequestPolicyId: aws.String(originRequestPolicyID),
+		Compress:              aws.Bool(true),
+		AllowedMethods: &cloudfront.AllowedMethods{
+			Items:    aws.StringSlice(methods),
+			Quantity: aws.Int64(int64(len(methods))),
+			CachedMethods: &cloudfront.CachedMethods{
+				Items:    aws.StringSlice(cachedMethods),
+				Quantity: aws.Int64(int64(len(cachedMethods))),
+			},
+		},
+	}
+}
+
+// upsertOriginRequestPolicy creates the custom origin request policy. If the policy already exists, it updates the policy.
+// It returns the ID of the policy.
+func (c *CloudFrontCDNCustomOriginApplier) upsertOriginRequestPolicy(ctx context.Context, policyConfig *cloudfront.OriginRequestPolicyConfig) (string, error) {
+	id, err := c.findCustomOriginRequestPolicy(ctx, aws.StringValue(policyConfig.Name))
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		output, err := c.CreateOriginRequestPolicyWithContext(ctx, &cloudfront.CreateOriginRequestPolicyInput{
+			OriginRequestPolicyConfig: policyConfig,
+		})
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to create a cloudfront origin request policy")
+		}
+		return aws.StringValue(output.OriginRequestPolicy.Id), nil
+	}
+
+	policy, err := c.GetOriginRequestPolicyWithContext(ctx, &cloudfront.GetOriginRequestPolicyInput{
+		Id: aws.String(id),
+	})
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to get a cloudfront origin request policy")
+	}
+	if _, err := c.UpdateOriginRequestPolicyWithContext(ctx, &cloudfront.UpdateOriginRequestPolicyInput{
+		Id:                        aws.String(id),
+		IfMatch:                   policy.ETag,
+		OriginRequestPolicyConfig: policyConfig,
+	}); err != nil {
+		return "", errfmt.Wrap(err, "failed to update a cloudfront origin request policy")
+	}
+	return id, nil
+}
+
+// findCustomOriginRequestPolicy returns the ID of the custom origin request policy whose name is name.
+// If not found, it returns empty string.
+func (c *CloudFrontCDNCustomOriginApplier) findCustomOriginRequestPolicy(ctx context.Context, name string) (string, error) {
+	input := &cloudfront.ListOriginRequestPoliciesInput{
+		Type: aws.String(cloudfront.OriginRequestPolicyTypeCustom),
+	}
+	for {
+		output, err := c.ListOriginRequestPoliciesWithContext(ctx, input)
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to list cloudfront origin request policies")
+		}
+		if output.OriginRequestPolicyList == nil {
+			return "", nil
+		}
+		for _, summary := range output.OriginRequestPolicyList.Items {
+			if aws.StringValue(summary.OriginRequestPolicy.OriginRequestPolicyConfig.Name) == name {
+				return aws.StringValue(summary.OriginRequestPolicy.Id), nil
+			}
+		}
+		if aws.StringValue(output.OriginRequestPolicyList.NextMarker) == "" {
+			return "", nil
+		}
+		input.Marker = output.OriginRequestPolicyList.NextMarker
+	}
+}
+
+// newOriginRequestPolicyConfig returns the origin request policy config for the custom origin.
+// When all headers are forwarded, Host is excluded, because ALB and API Gateway expect their own domain name.
+func newOriginRequestPolicyConfig(name string, o model.CustomOrigin) *cloudfront.OriginRequestPolicyConfig {
+	headers := &cloudfront.OriginRequestPolicyHeadersConfig{
+		HeaderBehavior: aws.String(cloudfront.OriginRequestPolicyHeaderBehaviorNone),
+	}
+	switch {
+	case o.ForwardAllHeaders():
+		headers.HeaderBehavior = aws.String(cloudfront.OriginRequestPolicyHeaderBehaviorAllExcept)
+		headers.Headers = &cloudfront.Headers{
+			Items:    aws.StringSlice([]string{"Host"}),
+			Quantity: aws.Int64(1),
+		}
+	case len(o.Headers) > 0:
+		headers.HeaderBehavior = aws.String(cloudfront.OriginRequestPolicyHeaderBehaviorWhitelist)
+		headers.Headers = &cloudfront.Headers{
+			Items:    aws.StringSlice(o.Headers),
+			Quantity: aws.Int64(int64(len(o.Headers))),
+		}
+	}
+
+	cookies := &cloudfront.OriginRequestPolicyCookiesConfig{
+		CookieBehavior: aws.String(cloudfront.OriginRequestPolicyCookieBehaviorNone),
+	}
+	switch {
+	case o.ForwardAllCookies():
+		cookies.CookieBehavior = aws.String(cloudfront.OriginRequestPolicyCookieBehaviorAll)
+	case len(o.Cookies) > 0:
+		cookies.CookieBehavior = aws.String(cloudfront.OriginRequestPolicyCookieBehaviorWhitelist)
+		cookies.Cookies = &cloudfront.CookieNames{
+			Items:    aws.StringSlice(o.Cookies),
+			Quantity: aws.Int64(int64(len(o.Cookies))),
+		}
+	}
+
+	queryStrings := &cloudfront.OriginRequestPolicyQueryStringsConfig{
+		QueryStringBehavior: aws.String(cloudfront.OriginRequestPolicyQueryStringBehaviorNone),
+	}
+	switch {
+	case o.ForwardAllQueryStrings():
+		queryStrings.QueryStringBehavior = aws.String(cloudfront.OriginRequestPolicyQueryStringBehaviorAll)
+	case len(o.QueryStrings) > 0:
+		queryStrings.QueryStringBehavior = aws.String(cloudfront.OriginRequestPolicyQueryStringBehaviorWhitelist)
+		queryStrings.QueryStrings = &cloudfront.QueryStringNames{
+			Items:    aws.StringSlice(o.QueryStrings),
+			Quantity: aws.Int64(int64(len(o.QueryStrings))),
+		}
+	}
+
+	return &cloudfront.OriginRequestPolicyConfig{
+		Name:               aws.String(name),
+		Comment:            aws.String(fmt.Sprintf("Origin request policy for %s generated by spare", o.Name)),
+		HeadersConfig:      headers,
+		CookiesConfig:      cookies,
+		QueryStringsConfig: queryStrings,
+	}
+}
+
+// CDNResponseHeadersPolicyApplierSet is a provider set for CDNResponseHeadersPolicyApplier.
+//
+//nolint:gochecknoglobals
+var CDNResponseHeadersPolicyApplierSet = wire.NewSet(
+	NewCloudFrontCDNResponseHeadersPolicyApplier,
+	wire.Bind(new(service.CDNResponseHeadersPolicyApplier), new(*CloudFrontCDNResponseHeadersPolicyApplier)),
+)
+
+// CloudFrontCDNResponseHeadersPolicyApplier is an implementation for CDNResponseHeadersPolicyApplier.
+type CloudFrontCDNResponseHeadersPolicyApplier struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNResponseHeadersPolicyApplier = &CloudFrontCDNResponseHeadersPolicyApplier{}
+
+// NewCloudFrontCDNResponseHeadersPolicyApplier returns a new CloudFrontCDNResponseHeadersPolicyApplier struct.
+func NewCloudFrontCDNResponseHeadersPolicyApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNResponseHeadersPolicyApplier {
+	return &CloudFrontCDNResponseHeadersPolicyApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyCDNResponseHeadersPolicy creates (or updates) the custom response headers policy, and then
+// attaches it to the default cache behavior and all cache behaviors of the distribution, including the custom origins.
+// If both the security headers and CORS are nil, it detaches the response headers policy.
+func (c *CloudFrontCDNResponseHeadersPolicyApplier) ApplyCDNResponseHeadersPolicy(ctx context.Context, input *service.CDNResponseHeadersPolicyApplierInput) (*service.CDNResponseHeadersPolicyApplierOutput, error) {
+	var policyID *string
+	if input.SecurityHeaders != nil || input.CORS != nil {
+		id, err := c.upsertResponseHeadersPolicy(ctx, newResponseHeadersPolicyConfig(
+			model.NewResponseHeadersPolicyName(input.BucketName), input.SecurityHeaders, input.CORS))
+		if err != nil {
+			return nil, err
+		}
+		policyID = aws.String(id)
+	}
+
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+
+	d := dist.DefaultCacheBehavior
+	d.ResponseHeadersPolicyId = policyID
+	d.AllowedMethods = newAllowedMethods(input.CORS)
+	if input.CORS != nil && d.OriginRequestPolicyId == nil {
+		d.OriginRequestPolicyId = aws.String(input.CORS.OriginRequestPolicyID())
+	}
+	if dist.CacheBehaviors != nil {
+		for _, b := range dist.CacheBehaviors.Items {
+			b.ResponseHeadersPolicyId = policyID
+			if model.IsCustomOriginID(aws.StringValue(b.TargetOriginId)) {
+				// The custom origins allow all methods, and their origin request policies are managed by CDNCustomOriginApplier.
+				continue
+			}
+			b.AllowedMethods = newAllowedMethods(input.CORS)
+			if input.CORS != nil && b.OriginRequestPolicyId == nil {
+				b.OriginRequestPolicyId = aws.String(input.CORS.OriginRequestPolicyID())
+			}
+		}
+	}
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNResponseHeadersPolicyApplierOutput{}, nil
+}
+
+// newAllowedMethods returns the methods that the cache behavior allows and caches.
+// If CORS is enabled, the preflight (OPTIONS) requests are allowed and cached, too.
+// The response headers policy adds the CORS headers for each viewer, even if the response is cached.
+func newAllowedMethods(cors *model.CORS) *cloudfront.AllowedMethods {
+	methods := []string{cloudfront.MethodGet, cloudfront.MethodHead}
+	if cors != nil {
+		methods = append(methods, cloudfront.MethodOptions)
+	}
+	return &cloudfront.AllowedMethods{
+		Items:    aws.StringSlice(methods),
+		Quantity: aws.Int64(int64(len(methods))),
+		CachedMethods: &cloudfront.CachedMethods{
+			Items:    aws.StringSlice(methods),
+			Quantity: aws.Int64(int64(len(methods))),
+		},
+	}
+}
+
+// upsertResponseHeadersPolicy creates the custom response headers policy. If the policy already exists, it updates the policy.
+// It returns the ID of the policy.
+func (c *CloudFrontCDNResponseHeadersPolicyApplier) upsertResponseHeadersPolicy(ctx context.Context, policyConfig *cloudfront.ResponseHeadersPolicyConfig) (string, error) {
+	id, err := c.findCustomResponseHeadersPolicy(ctx, aws.StringValue(policyConfig.Name))
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		output, err := c.CreateResponseHeadersPolicyWithContext(ctx, &cloudfront.CreateResponseHeadersPolicyInput{
+			ResponseHeadersPolicyConfig: policyConfig,
+		})
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to create a cloudfront response headers policy")
+		}
+		return aws.StringValue(output.ResponseHeadersPolicy.Id), nil
+	}
+
+	policy, err := c.GetResponseHeadersPolicyWithContext(ctx, &cloudfront.GetResponseHeadersPolicyInput{
+		Id: aws.String(id),
+	})
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to get a cloudfront response headers policy")
+	}
+	if _, err := c.UpdateResponseHeadersPolicyWithContext(ctx, &cloudfront.UpdateResponseHeadersPolicyInput{
+		Id:                          aws.String(id),
+		IfMatch:                     policy.ETag,
+		ResponseHeadersPolicyConfig: policyConfig,
+	}); err != nil {
+		return "", errfmt.Wrap(err, "failed to update a cloudfront response headers policy")
+	}
+	return id, nil
+}
+
+// findCustomResponseHeadersPolicy returns the ID of the custom response headers policy whose name is name.
+// If not found, it returns empty string.
+func (c *CloudFrontCDNResponseHeadersPolicyApplier) findCustomResponseHeadersPolicy(ctx context.Context, name string) (string, error) {
+	input := &cloudfront.ListResponseHeadersPoliciesInput{
+		Type: aws.String(cloudfront.ResponseHeadersPolicyTypeCustom),
+	}
+	for {
+		output, err := c.ListResponseHeadersPoliciesWithContext(ctx, input)
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to list cloudfront response headers policies")
+		}
+		if output.ResponseHeadersPolicyList == nil {
+			return "", nil
+		}
+		for _, summary := range output.ResponseHeadersPolicyList.Items {
+			policy := summary.ResponseHeadersPolicy
+			if aws.StringValue(policy.ResponseHeadersPolicyConfig.Name) == name {
+				return aws.StringValue(policy.Id), nil
+			}
+		}
+		if aws.StringValue(output.ResponseHeadersPolicyList.NextMarker) == "" {
+			return "", nil
+		}
+		input.Marker = output.ResponseHeadersPolicyList.NextMarker
+	}
+}
+
+// permissionsPolicyHeader is the name of the Permissions-Policy header.
+// CloudFront does not support it as a security header, so it's sent as a custom header.
+const permissionsPolicyHeader = "Permissions-Policy"
+
+// newResponseHeadersPolicyConfig returns the response headers policy config for the security headers and CORS.
+// The headers in the policy override the headers that the origin sends. Nil means the headers are not sent.
+func newResponseHeadersPolicyConfig(name string, s *model.SecurityHeaders, cors *model.CORS) *cloudfront.ResponseHeadersPolicyConfig {
+	if s == nil {
+		s = &model.SecurityHeaders{}
+	}
+	security := &cloudfront.ResponseHeadersPolicySecurityHeadersConfig{}
+	if hsts := s.StrictTransportSecurity; hsts.MaxAge > 0 {
+		security.StrictTransportSecurity = &cloudfront.ResponseHeadersPolicyStrictTransportSecurity{
+			AccessControlMaxAgeSec: aws.Int64(hsts.MaxAge),
+			IncludeSubdomains:      aws.Bool(hsts.IncludeSubdomains),
+			Preload:                aws.Bool(hsts.Preload),
+			Override:               aws.Bool(true),
+		}
+	}
+	if s.ContentSecurityPolicy != "" {
+		security.ContentSecurityPolicy = &cloudfront.ResponseHeadersPolicyContentSecurityPolicy{
+			ContentSecurityPolicy: aws.String(s.ContentSecurityPolicy),
+			Override:              aws.Bool(true),
+		}
+	}
+	if s.ContentTypeOptions {
+		security.ContentTypeOptions = &cloudfront.ResponseHeadersPolicyContentTypeOptions{
+			Override: aws.Bool(true),
+		}
+	}
+	if s.FrameOptions != "" {
+		security.FrameOptions = &cloudfront.ResponseHeadersPolicyFrameOptions{
+			FrameOption: aws.String(s.FrameOptions),
+			Override:    aws.Bool(true),
+		}
+	}
+	if s.ReferrerPolicy != "" {
+		security.ReferrerPolicy = &cloudfront.ResponseHeadersPolicyReferrerPolicy{
+			ReferrerPolicy: aws.String(s.ReferrerPolicy),
+			Override:       aws.Bool(true),
+		}
+	}
+
+	customHeaders := make([]*cloudfront.ResponseHeadersPolicyCustomHeader, 0, len(s.CustomHeaders)+1)
+	if s.PermissionsPolicy != "" {
+		customHeaders = append(customHeaders, &cloudfront.ResponseHeadersPolicyCustomHeader{
+			Header:   aws.String(permissionsPolicyHeader),
+			Value:    aws.String(s.PermissionsPolicy),
+			Override: aws.Bool(true),
+		})
+	}
+	for _, h := range s.CustomHeaders {
+		customHeaders = append(customHeaders, &cloudfront.ResponseHeadersPolicyCustomHeader{
+			Header:   aws.String(h.Name),
+			Value:    aws.String(h.Value),
+			Override: aws.Bool(true),
+		})
+	}
+
+	return &cloudfront.ResponseHeadersPolicyConfig{
+		Name:                  aws.String(name),
+		Comment:               aws.String("Response headers policy generated by spare"),
+		SecurityHeadersConfig: security,
+		CustomHeadersConfig: &cloudfront.ResponseHeadersPolicyCustomHeadersConfig{
+			Items:    customHeaders,
+			Quantity: aws.Int64(int64(len(customHeaders))),
+		},
+		CorsConfig: newCorsConfig(cors),
+	}
+}
+
+// newCorsConfig returns the CORS config of the response headers policy. If CORS is nil, it returns nil.
+func newCorsConfig(cors *model.CORS) *cloudfront.ResponseHeadersPolicyCorsConfig {
+	if cors == nil {
+		return nil
+	}
+	return &cloudfront.ResponseHeadersPolicyCorsConfig{
+		AccessControlAllowCredentials: aws.Bool(false),
+		AccessControlAllowOrigins: &cloudfront.ResponseHeadersPolicyAccessControlAllowOrigins{
+			Items:    aws.StringSlice(cors.AllowOrigins),
+			Quantity: aws.Int64(int64(len(cors.AllowOrigins))),
+		},
+		AccessControlAllowMethods: &cloudfront.ResponseHeadersPolicyAccessControlAllowMethods{
+			Items:    aws.StringSlice(cors.AllowMethods),
+			Quantity: aws.Int64(int64(len(cors.AllowMethods))),
+		},
+		AccessControlAllowHeaders: &cloudfront.ResponseHeadersPolicyAccessControlAllowHeaders{
+			Items:    aws.StringSlice(cors.AllowHeaders),
+			Quantity: aws.Int64(int64(len(cors.AllowHeaders))),
+		},
+		AccessControlMaxAgeSec: aws.Int64(cors.MaxAge),
+		OriginOverride:         aws.Bool(true),
+	}
+}
+
+// newCORSHeaders returns the CORS headers of the response headers policy config.
+func newCORSHeaders(config *cloudfront.ResponseHeadersPolicyConfig) []model.HTTPHeader {
+	cors := config.CorsConfig
+	if cors == nil {
+		return []model.HTTPHeader{}
+	}
+	headers := []model.HTTPHeader{}
+	if cors.AccessControlAllowOrigins != nil {
+		headers = append(headers, model.HTTPHeader{
+			Name:  "Access-Control-Allow-Origin",
+			Value: strings.Join(aws.StringValueSlice(cors.AccessControlAllowOrigins.Items), ", "),
+		})
+	}
+	if cors.AccessControlAllowMethods != nil {
+		headers = append(headers, model.HTTPHeader{
+			Name:  "Access-Control-Allow-Methods",
+			Value: strings.Join(aws.StringValueSlice(cors.AccessControlAllowMethods.Items), ", "),
+		})
+	}
+	if cors.AccessControlAllowHeaders != nil {
+		headers = append(headers, model.HTTPHeader{
+			Name:  "Access-Control-Allow-Headers",
+			Value: strings.Join(aws.StringValueSlice(cors.AccessControlAllowHeaders.Items), ", "),
+		})
+	}
+	if cors.AccessControlMaxAgeSec != nil {
+		headers = append(headers, model.HTTPHeader{
+			Name:  "Access-Control-Max-Age",
+			Value: fmt.Sprintf("%d", aws.Int64Value(cors.AccessControlMaxAgeSec)),
+		})
+	}
+	return headers
+}
+
+// newSecurityHeaders converts the response headers policy config to model.SecurityHeaders.
+func newSecurityHeaders(config *cloudfront.ResponseHeadersPolicyConfig) *model.SecurityHeaders {
+	s := &model.SecurityHeaders{CustomHeaders: []model.HTTPHeader{}}
+	if security := config.SecurityHeadersConfig; security != nil {
+		if hsts := security.StrictTransportSecurity; hsts != nil {
+			s.StrictTransportSecurity = model.StrictTransportSecurity{
+				MaxAge:            aws.Int64Value(hsts.AccessControlMaxAgeSec),
+				IncludeSubdomains: aws.BoolValue(hsts.IncludeSubdomains),
+				Preload:           aws.BoolValue(hsts.Preload),
+			}
+		}
+		if csp := security.ContentSecurityPolicy; csp != nil {
+			s.ContentSecurityPolicy = aws.StringValue(csp.ContentSecurityPolicy)
+		}
+		s.ContentTypeOptions = security.ContentTypeOptions != nil
+		if frame := security.FrameOptions; frame != nil {
+			s.FrameOptions = aws.StringValue(frame.FrameOption)
+		}
+		if referrer := security.ReferrerPolicy; referrer != nil {
+			s.ReferrerPolicy = aws.StringValue(referrer.ReferrerPolicy)
+		}
+	}
+	if config.CustomHeadersConfig != nil {
+		for _, h := range config.CustomHeadersConfig.Items {
+			if aws.StringValue(h.Header) == permissionsPolicyHeader {
+				s.PermissionsPolicy = aws.StringValue(h.Value)
+				continue
+			}
+			s.CustomHeaders = append(s.CustomHeaders, model.HTTPHeader{
+				Name:  aws.StringValue(h.Header),
+				Value: aws.StringValue(h.Value),
+			})
+		}
+	}
+	return s
+}
+
+// CDNResponseHeadersGetterSet is a provider set for CDNResponseHeadersGetter.
+//
+//nolint:gochecknoglobals
+var CDNResponseHeadersGetterSet = wire.NewSet(
+	NewCDNResponseHeadersGetter,
+)
+
+// CloudFrontCDNResponseHeadersGetter is an implementation for CDNResponseHeadersGetter.
+type CloudFrontCDNResponseHeadersGetter struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNResponseHeadersGetter = &CloudFrontCDNResponseHeadersGetter{}
+
+// NewCloudFrontCDNResponseHeadersGetter returns a new CloudFrontCDNResponseHeadersGetter struct.
+func NewCloudFrontCDNResponseHeadersGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNResponseHeadersGetter {
+	return &CloudFrontCDNResponseHeadersGetter{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// GetCDNResponseHeaders returns the headers of the response headers policy attached to the default cache behavior.
+// The CORS headers are returned as the list of allowed values, because the CDN sends only the matched origin.
+func (c *CloudFrontCDNResponseHeadersGetter) GetCDNResponseHeaders(ctx context.Context, input *service.CDNResponseHeadersGetterInput) (*service.CDNResponseHeadersGetterOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+
+	policyID := aws.StringValue(config.DistributionConfig.DefaultCacheBehavior.ResponseHeadersPolicyId)
+	if policyID == "" {
+		return &service.CDNResponseHeadersGetterOutput{Headers: []model.HTTPHeader{}}, nil
+	}
+
+	policy, err := c.GetResponseHeadersPolicyWithContext(ctx, &cloudfront.GetResponseHeadersPolicyInput{
+		Id: aws.String(policyID),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront response headers policy")
+	}
+	policyConfig := policy.ResponseHeadersPolicy.ResponseHeadersPolicyConfig
+	return &service.CDNResponseHeadersGetterOutput{
+		PolicyName: aws.StringValue(policyConfig.Name),
+		Headers:    append(newSecurityHeaders(policyConfig).Headers(), newCORSHeaders(policyConfig)...),
+	}, nil
+}
+
+// CDNWebACLAssociatorSet is a provider set for CDNWebACLAssociator.
+//
+//nolint:gochecknoglobals
+var CDNWebACLAssociatorSet = wire.NewSet(
+	NewCloudFrontCDNWebACLAssociator,
+	wire.Bind(new(service.CDNWebACLAssociator), new(*CloudFrontCDNWebACLAssociator)),
+)
+
+// CloudFrontCDNWebACLAssociator is an implementation for CDNWebACLAssociator.
+type CloudFrontCDNWebACLAssociator struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNWebACLAssociator = &CloudFrontCDNWebACLAssociator{}
+
+// NewCloudFrontCDNWebACLAssociator returns a new CloudFrontCDNWebACLAssociator struct.
+func NewCloudFrontCDNWebACLAssociator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNWebACLAssociator {
+	return &CloudFrontCDNWebACLAssociator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// AssociateCDNWebACL sets the web ACL of the distribution. If the distribution already uses the web ACL, it does nothing.
+func (c *CloudFrontCDNWebACLAssociator) AssociateCDNWebACL(ctx context.Context, input *service.CDNWebACLAssociatorInput) (*service.CDNWebACLAssociatorOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+	if aws.StringValue(dist.WebACLId) == input.WebACLARN {
+		return &service.CDNWebACLAssociatorOutput{}, nil
+	}
+
+	dist.WebACLId = aws.String(input.WebACLARN)
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNWebACLAssociatorOutput{}, nil
+}
+
+// CDNViewerFunctionAssociatorSet is a provider set for CDNViewerFunctionAssociator.
+//
+//nolint:gochecknoglobals
+var CDNViewerFunctionAssociatorSet = wire.NewSet(
+	NewCloudFrontCDNViewerFunctionAssociator,
+	wire.Bind(new(service.CDNViewerFunctionAssociator), new(*CloudFrontCDNViewerFunctionAssociator)),
+)
+
+// CloudFrontCDNViewerFunctionAssociator is an implementation for CDNViewerFunctionAssociator.
+type CloudFrontCDNViewerFunctionAssociator struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNViewerFunctionAssociator = &CloudFrontCDNViewerFunctionAssociator{}
+
+// NewCloudFrontCDNViewerFunctionAssociator returns a new CloudFrontCDNViewerFunctionAssociator struct.
+func NewCloudFrontCDNViewerFunctionAssociator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNViewerFunctionAssociator {
+	return &CloudFrontCDNViewerFunctionAssociator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// AssociateCDNViewerFunction sets the viewer request and response functions of the default cache behavior and the cache behaviors
+// except previews/* and the custom origins. The functions of the other event types are kept. If nothing is changed, it does not update the distribution.
+func (c *CloudFrontCDNViewerFunctionAssociator) AssociateCDNViewerFunction(ctx context.Context, input *service.CDNViewerFunctionAssociatorInput) (*service.CDNViewerFunctionAssociatorOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+	dist := config.DistributionConfig
+
+	changed := false
+	d := dist.DefaultCacheBehavior
+	if associations, ok := withViewerFunctions(d.FunctionAssociations, input); ok {
+		d.FunctionAssociations = associations
+		changed = true
+	}
+	previewRoute := false
+	if dist.CacheBehaviors != nil {
+		for _, b := range dist.CacheBehaviors.Items {
+			if aws.StringValue(b.PathPattern) == previewPathPattern {
+				previewRoute = true
+				continue
+			}
+			if model.IsCustomOriginID(aws.StringValue(b.TargetOriginId)) {
+				continue
+			}
+			if associations, ok := withViewerFunctions(b.FunctionAssociations, input); ok {
+				b.FunctionAssociations = associations
+				changed = true
+			}
+		}
+	}
+	output := &service.CDNViewerFunctionAssociatorOutput{PreviewRoute: previewRoute}
+	if !changed {
+		return output, nil
+	}
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: dist,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return output, nil
+}
+
+// withViewerFunctions returns the function associations whose viewer request and response functions are replaced.
+// The second return value is false if nothing is changed.
+func withViewerFunctions(associations *cloudfront.FunctionAssociations, input *service.CDNViewerFunctionAssociatorInput) (*cloudfront.FunctionAssociations, bool) {
+	associations, requestChanged := withViewerFunction(associations, cloudfront.EventTypeViewerRequest, input.FunctionARN)
+	associations, responseChanged := withViewerFunction(associations, cloudfront.EventTypeViewerResponse, input.ResponseFunctionARN)
+	return associations, requestChanged || responseChanged
+}
+
+// withViewerFunction returns the function associations whose function for the event type is replaced with arn.
+// If arn is empty, the function is removed. The second return value is false if nothing is changed.
+func withViewerFunction(associations *cloudfront.FunctionAssociations, eventType, arn string) (*cloudfront.FunctionAssociations, bool) {
+	current := ""
+	items := []*cloudfront.FunctionAssociation{}
+	if associations != nil {
+		for _, a := range associations.Items {
+			if aws.StringValue(a.EventType) == eventType {
+				current = aws.StringValue(a.FunctionARN)
+				continue
+			}
+			items = append(items, a)
+		}
+	}
+	if current == arn {
+		return associations, false
+	}
+	if arn != "" {
+		items = append(items, &cloudfront.FunctionAssociation{
+			EventType:   aws.String(eventType),
+			FunctionARN: aws.String(arn),
+		})
+	}
+	return &cloudfront.FunctionAssociations{
+		Items:    items,
+		Quantity: aws.Int64(int64(len(items))),
+	}, true
+}
+
+// CDNLoggingApplierSet is a provider set for CDNLoggingApplier.
+//
+//nolint:gochecknoglobals
+var CDNLoggingApplierSet = wire.NewSet(
+	NewCloudFrontCDNLoggingApplier,
+	wire.Bind(new(service.CDNLoggingApplier), new(*CloudFrontCDNLoggingApplier)),
+)
+
+// CloudFrontCDNLoggingApplier is an implementation for CDNLoggingApplier.
+type CloudFrontCDNLoggingApplier struct {
+	*cloudfront.CloudFront
+	// region is the region of the log bucket.
+	region model.Region
+}
+
+var _ service.CDNLoggingApplier = &CloudFrontCDNLoggingApplier{}
+
+// NewCloudFrontCDNLoggingApplier returns a new CloudFrontCDNLoggingApplier struct.
+func NewCloudFrontCDNLoggingApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNLoggingApplier {
+	return &CloudFrontCDNLoggingApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+		region:     region,
+	}
+}
+
+// ApplyCDNLogging enables the standard logging to model.CloudFrontLogPrefix in the log bucket.
+// If the logging settings are nil, it disables the standard logging. The distribution is not updated if nothing is changed.
+func (c *CloudFrontCDNLoggingApplier) ApplyCDNLogging(ctx context.Context, input *service.CDNLoggingApplierInput) (*service.CDNLoggingApplierOutput, error) {
+	config, err := c.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution config")
+	}
+
+	logging := &cloudfront.LoggingConfig{
+		Enabled:        aws.Bool(false),
+		IncludeCookies: aws.Bool(false),
+		Bucket:         aws.String(""),
+		Prefix:         aws.String(""),
+	}
+	if input.Logging != nil {
+		logging.Enabled = aws.Bool(true)
+		logging.Bucket = aws.String(input.Logging.Bucket.LegacyDomain(c.region))
+		logging.Prefix = aws.String(model.CloudFrontLogPrefix)
+	}
+	current := config.DistributionConfig.Logging
+	if current != nil && aws.BoolValue(current.Enabled) == aws.BoolValue(logging.Enabled) &&
+		aws.StringValue(current.Bucket) == aws.StringValue(logging.Bucket) &&
+		aws.StringValue(current.Prefix) == aws.StringValue(logging.Prefix) {
+		return &service.CDNLoggingApplierOutput{}, nil
+	}
+	config.DistributionConfig.Logging = logging
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            config.ETag,
+		DistributionConfig: config.DistributionConfig,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return &service.CDNLoggingApplierOutput{}, nil
+}
+
+// CDNOriginAccessControlApplierSet is a provider set for CDNOriginAccessControlApplier.
+//
+//nolint:gochecknoglobals
+var CDNOriginAccessControlApplierSet = wire.NewSet(
+	NewCloudFrontCDNOriginAccessControlApplier,
+	wire.Bind(new(service.CDNOriginAccessControlApplier), new(*CloudFrontCDNOriginAccessControlApplier)),
+)
+
+// CloudFrontCDNOriginAccessControlApplier is an implementation for CDNOriginAccessControlApplier.
+type CloudFrontCDNOriginAccessControlApplier struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.CDNOriginAccessControlApplier = &CloudFrontCDNOriginAccessControlApplier{}
+
+// NewCloudFrontCDNOriginAccessControlApplier returns a new CloudFrontCDNOriginAccessControlApplier struct.
+func NewCloudFrontCDNOriginAccessControlApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontCDNOriginAccessControlApplier {
+	return &CloudFrontCDNOriginAccessControlApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyCDNOriginAccessControl creates the OAC for the bucket if it does not exist, and switches all origins of the bucket
+// (the live release and the previews) to the OAC. The OAI of the distributions built by the older spare is detached. The distribution is not updated if nothing is changed.
+func (c *CloudFrontCDNOriginAccessControlApplier) ApplyCDNOriginAccessControl(ctx context.Context, input *service.CDNOriginAccessControlApplierInput) (*service.CDNOriginAccessControlApplierOutput, error) {
+	oacID, err := c.originAccessControlID(ctx, input.BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution, err := c.GetDistributionWithContext(ctx, &cloudfront.GetDistributionInput{
+		Id: aws.String(input.DistributionID.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront distribution")
+	}
+	config := distribution.Distribution.DistributionConfig
+
+	changed := false
+	for _, origin := range config.Origins.Items {
+		if !input.BucketName.IsDomain(aws.StringValue(origin.DomainName)) || aws.StringValue(origin.OriginAccessControlId) == oacID {
+			continue
+		}
+		origin.OriginAccessControlId = aws.String(oacID)
+		// The origin can not have both OAI and OAC.
+		origin.S3OriginConfig = &cloudfront.S3OriginConfig{OriginAccessIdentity: aws.String("")}
+		changed = true
+	}
+	output := &service.CDNOriginAccessControlApplierOutput{
+		DistributionARN: aws.StringValue(distribution.Distribution.ARN),
+	}
+	if !changed {
+		return output, nil
+	}
+
+	if _, err := c.UpdateDistributionWithContext(ctx, &cloudfront.UpdateDistributionInput{
+		Id:                 aws.String(input.DistributionID.String()),
+		IfMatch:            distribution.ETag,
+		DistributionConfig: config,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront distribution")
+	}
+	return output, nil
+}
+
+// originAccessControlID returns the ID of the OAC for the bucket. If the OAC does not exist, it creates the OAC
+// that signs all requests to S3.
+func (c *CloudFrontCDNOriginAccessControlApplier) originAccessControlID(ctx context.Context, bucket model.BucketName) (string, error) {
+	const maxNameLength = 64
+	name := "spare-" + bucket.String()
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	input := &cloudfront.ListOriginAccessControlsInput{}
+	for {
+		output, err := c.ListOriginAccessControlsWithContext(ctx, input)
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to list cloudfront origin access controls")
+		}
+		if output.OriginAccessControlList == nil {
+			break
+		}
+		for _, oac := range output.OriginAccessControlList.Items {
+			if aws.StringValue(oac.Name) == name {
+				return aws.StringValue(oac.Id), nil
+			}
+		}
+		if !aws.BoolValue(output.OriginAccessControlList.IsTruncated) {
+			break
+		}
+		input.Marker = output.OriginAccessControlList.NextMarker
+	}
+
+	output, err := c.CreateOriginAccessControlWithContext(ctx, &cloudfront.CreateOriginAccessControlInput{
+		OriginAccessControlConfig: &cloudfront.OriginAccessControlConfig{
+			Name:                          aws.String(name),
+			Description:                   aws.String("Origin Access Control (OAC) Generated by Spare"),
+			OriginAccessControlOriginType: aws.String(cloudfront.OriginAccessControlOriginTypesS3),
+			SigningBehavior:               aws.String(cloudfront.OriginAccessControlSigningBehaviorsAlways),
+			SigningProtocol:               aws.String(cloudfront.OriginAccessControlSigningProtocolsSigv4),
+		},
+	})
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to create a cloudfront origin access control")
+	}
+	return aws.StringValue(output.OriginAccessControl.Id), nil
+}
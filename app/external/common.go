@@ -13,14 +13,12 @@ import (
 	"github.com/nao1215/spare/utils/errfmt"
 )
 
-// newS3Session returns a new session.
-func newS3Session(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *session.Session {
-	session := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable, // Ref. ~/.aws/config
-		Profile:           profile.String(),
-	}))
-
-	session.Config.Region = aws.String(region.String())
+// newS3Session returns a new session of the region. It shares the credentials with the other sessions of
+// the same credentials (see baseSession).
+func newS3Session(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *session.Session {
+	session := baseSession(credentials, region.Partition()).Copy(&aws.Config{
+		Region: aws.String(region.String()),
+	})
 	if endpoint != nil {
 		// If you want to debug, uncomment the following lines.
 		// session.Config.WithLogLevel(aws.LogDebugWithHTTPBody)
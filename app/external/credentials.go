@@ -0,0 +1,71 @@
+package external
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+var (
+	// baseSessions is the sessions that resolve the credentials. All clients of the same credentials share
+	// the credentials of the session, so the role is assumed (and the MFA token code is prompted) once, not once per client.
+	baseSessions = map[*model.AWSCredentials]*session.Session{} //nolint:gochecknoglobals
+	// baseSessionsMu protects baseSessions.
+	baseSessionsMu sync.Mutex //nolint:gochecknoglobals
+)
+
+// baseSession returns the session that resolves the credentials of c. The profile in the shared config files
+// can have the static keys, a role (role_arn, mfa_serial), an AWS IAM Identity Center (AWS SSO) session or
+// a web identity token file. If c.AssumeRole is set, the role is assumed on top of it.
+// The STS requests are sent to the global region of the partition, because it's always enabled.
+func baseSession(c *model.AWSCredentials, partition model.Partition) *session.Session {
+	baseSessionsMu.Lock()
+	defer baseSessionsMu.Unlock()
+	if sess, ok := baseSessions[c]; ok {
+		return sess
+	}
+
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		Config:                  aws.Config{Region: aws.String(partition.GlobalRegion().String())},
+		SharedConfigState:       session.SharedConfigEnable, // Ref. ~/.aws/config
+		Profile:                 c.Profile.String(),
+		AssumeRoleTokenProvider: c.MFATokenProvider,
+	}))
+	if c.AssumeRole != nil {
+		sess.Config.Credentials = assumeRoleCredentials(sess, c.AssumeRole, c.MFATokenProvider)
+	}
+	baseSessions[c] = sess
+	return sess
+}
+
+// assumeRoleCredentials returns the credentials of the role. The role is assumed with the web identity token
+// if the token file is set, otherwise with the credentials of sess.
+func assumeRoleCredentials(sess *session.Session, role *model.AssumeRole, tokenProvider func() (string, error)) *credentials.Credentials {
+	if role.WebIdentity() {
+		return credentials.NewCredentials(stscreds.NewWebIdentityRoleProviderWithOptions(
+			sts.New(sess), role.RoleARN, role.SessionName, stscreds.FetchTokenPath(role.WebIdentityTokenFile),
+			func(p *stscreds.WebIdentityRoleProvider) {
+				p.Duration = role.Duration
+			}))
+	}
+	return stscreds.NewCredentials(sess, role.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if role.ExternalID != "" {
+			p.ExternalID = aws.String(role.ExternalID)
+		}
+		if role.SessionName != "" {
+			p.RoleSessionName = role.SessionName
+		}
+		if role.MFASerial != "" {
+			p.SerialNumber = aws.String(role.MFASerial)
+			p.TokenProvider = tokenProvider
+		}
+		if role.Duration != 0 {
+			p.Duration = role.Duration
+		}
+	})
+}
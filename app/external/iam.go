@@ -0,0 +1,67 @@
+package external
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// IAMActionsSimulatorSet is a provider set for IAMActionsSimulator.
+//
+//nolint:gochecknoglobals
+var IAMActionsSimulatorSet = wire.NewSet(
+	NewIAMPolicySimulator,
+	wire.Bind(new(service.IAMActionsSimulator), new(*IAMPolicySimulator)),
+)
+
+// IAMPolicySimulator is an implementation for IAMActionsSimulator.
+type IAMPolicySimulator struct {
+	*iam.IAM
+}
+
+var _ service.IAMActionsSimulator = &IAMPolicySimulator{}
+
+// NewIAMPolicySimulator returns a new IAMPolicySimulator struct.
+func NewIAMPolicySimulator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *IAMPolicySimulator {
+	return &IAMPolicySimulator{
+		IAM: iam.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// SimulateIAMActions simulates the actions of each permission with SimulatePrincipalPolicy.
+// The denied actions are returned in the form of "ACTION on RESOURCE".
+func (i *IAMPolicySimulator) SimulateIAMActions(ctx context.Context, input *service.IAMActionsSimulatorInput) (*service.IAMActionsSimulatorOutput, error) {
+	denied := []string{}
+	for _, p := range input.Permissions {
+		simulateInput := &iam.SimulatePrincipalPolicyInput{
+			PolicySourceArn: aws.String(input.PrincipalARN),
+			ActionNames:     aws.StringSlice(p.Actions),
+		}
+		if len(p.Resources) != 1 || p.Resources[0] != "*" {
+			simulateInput.ResourceArns = aws.StringSlice(p.Resources)
+		}
+		err := i.SimulatePrincipalPolicyPagesWithContext(ctx, simulateInput, func(page *iam.SimulatePolicyResponse, _ bool) bool {
+			for _, result := range page.EvaluationResults {
+				if aws.StringValue(result.EvalDecision) == iam.PolicyEvaluationDecisionTypeAllowed {
+					continue
+				}
+				resource := aws.StringValue(result.EvalResourceName)
+				if resource == "" {
+					resource = "*"
+				}
+				denied = append(denied, fmt.Sprintf("%s on %s", aws.StringValue(result.EvalActionName), resource))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrIAMActionsSimulate, err.Error())
+		}
+	}
+	return &service.IAMActionsSimulatorOutput{Denied: denied}, nil
+}
@@ -0,0 +1,161 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// CallerIdentityGetterSet is a provider set for CallerIdentityGetter.
+//
+//nolint:gochecknoglobals
+var CallerIdentityGetterSet = wire.NewSet(
+	NewSTSCallerIdentityGetter,
+	wire.Bind(new(service.CallerIdentityGetter), new(*STSCallerIdentityGetter)),
+)
+
+// STSCallerIdentityGetter is an implementation for CallerIdentityGetter.
+type STSCallerIdentityGetter struct {
+	*sts.STS
+}
+
+var _ service.CallerIdentityGetter = &STSCallerIdentityGetter{}
+
+// NewSTSCallerIdentityGetter returns a new STSCallerIdentityGetter struct.
+func NewSTSCallerIdentityGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *STSCallerIdentityGetter {
+	return &STSCallerIdentityGetter{
+		STS: sts.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// GetCallerIdentity returns the identity of the credentials. It needs no permission.
+func (s *STSCallerIdentityGetter) GetCallerIdentity(ctx context.Context, _ *service.CallerIdentityGetterInput) (*service.CallerIdentityGetterOutput, error) {
+	output, err := s.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrCallerIdentityGet, err.Error())
+	}
+	return &service.CallerIdentityGetterOutput{
+		Identity: model.CallerIdentity{
+			Account: aws.StringValue(output.Account),
+			ARN:     aws.StringValue(output.Arn),
+			UserID:  aws.StringValue(output.UserId),
+		},
+	}, nil
+}
+
+// ProfileFinderSet is a provider set for ProfileFinder.
+//
+//nolint:gochecknoglobals
+var ProfileFinderSet = wire.NewSet(
+	NewSharedConfigProfileFinder,
+	wire.Bind(new(service.ProfileFinder), new(*SharedConfigProfileFinder)),
+)
+
+// SharedConfigProfileFinder is an implementation for ProfileFinder.
+type SharedConfigProfileFinder struct{}
+
+var _ service.ProfileFinder = &SharedConfigProfileFinder{}
+
+// NewSharedConfigProfileFinder returns a new SharedConfigProfileFinder struct.
+func NewSharedConfigProfileFinder() *SharedConfigProfileFinder {
+	return &SharedConfigProfileFinder{}
+}
+
+// FindProfile finds the profile in the shared config file ($AWS_CONFIG_FILE or ~/.aws/config) and
+// the shared credentials file ($AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials) in the same way as the AWS SDK.
+// The files that do not exist are skipped.
+func (s *SharedConfigProfileFinder) FindProfile(_ context.Context, input *service.ProfileFinderInput) (*service.ProfileFinderOutput, error) {
+	output := &service.ProfileFinderOutput{
+		Files:          []string{},
+		EnvCredentials: os.Getenv("AWS_ACCESS_KEY_ID") != "" || os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") != "",
+	}
+
+	files := []struct {
+		path   string
+		config bool
+	}{
+		{path: envOrDefault("AWS_CONFIG_FILE", defaults.SharedConfigFilename()), config: true},
+		{path: envOrDefault("AWS_SHARED_CREDENTIALS_FILE", defaults.SharedCredentialsFilename()), config: false},
+	}
+	for _, f := range files {
+		output.Files = append(output.Files, f.path)
+		profiles, err := readProfiles(f.path, f.config)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrProfileFind, err.Error())
+		}
+		keys, ok := profiles[input.Profile.String()]
+		if !ok {
+			continue
+		}
+		output.Found = true
+		if keys["sso_session"] != "" || keys["sso_start_url"] != "" {
+			output.SSO = true
+		}
+	}
+	return output, nil
+}
+
+// readProfiles returns the profiles in the shared config file or the shared credentials file, and their keys.
+// The sections of the shared config file are "[default]" and "[profile NAME]", and the sections of
+// the shared credentials file are "[NAME]". The other sections (e.g. "[sso-session NAME]") are skipped.
+func readProfiles(path string, config bool) (_ map[string]map[string]string, err error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = nil
+			section := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+			if !config || section == "default" {
+				current = map[string]string{}
+				profiles[section] = current
+				continue
+			}
+			if name, ok := strings.CutPrefix(section, "profile "); ok {
+				current = map[string]string{}
+				profiles[strings.TrimSpace(name)] = current
+			}
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && current != nil {
+			current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+// envOrDefault returns the value of the environment variable. If it's empty, it returns def.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
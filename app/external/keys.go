@@ -0,0 +1,212 @@
+package external
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// KeyGroupGetterSet is a provider set for KeyGroupGetter.
+//
+//nolint:gochecknoglobals
+var KeyGroupGetterSet = wire.NewSet(
+	NewCloudFrontKeyGroupGetter,
+	wire.Bind(new(service.KeyGroupGetter), new(*CloudFrontKeyGroupGetter)),
+)
+
+// CloudFrontKeyGroupGetter is an implementation for KeyGroupGetter.
+type CloudFrontKeyGroupGetter struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.KeyGroupGetter = &CloudFrontKeyGroupGetter{}
+
+// NewCloudFrontKeyGroupGetter returns a new CloudFrontKeyGroupGetter struct.
+func NewCloudFrontKeyGroupGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontKeyGroupGetter {
+	return &CloudFrontKeyGroupGetter{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// GetKeyGroup returns the key group whose name is model.NewKeyGroupName(bucket).
+func (c *CloudFrontKeyGroupGetter) GetKeyGroup(ctx context.Context, input *service.KeyGroupGetterInput) (*service.KeyGroupGetterOutput, error) {
+	group, err := findKeyGroup(ctx, c.CloudFront, model.NewKeyGroupName(input.BucketName))
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, errfmt.Wrap(service.ErrKeyGroupNotFound, model.NewKeyGroupName(input.BucketName))
+	}
+	return &service.KeyGroupGetterOutput{
+		ID:           aws.StringValue(group.Id),
+		PublicKeyIDs: aws.StringValueSlice(group.KeyGroupConfig.Items),
+	}, nil
+}
+
+// findKeyGroup returns the key group whose name is name. If not found, it returns nil.
+func findKeyGroup(ctx context.Context, cf *cloudfront.CloudFront, name string) (*cloudfront.KeyGroup, error) {
+	input := &cloudfront.ListKeyGroupsInput{}
+	for {
+		output, err := cf.ListKeyGroupsWithContext(ctx, input)
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to list cloudfront key groups")
+		}
+		if output.KeyGroupList == nil {
+			return nil, nil
+		}
+		for _, summary := range output.KeyGroupList.Items {
+			if aws.StringValue(summary.KeyGroup.KeyGroupConfig.Name) == name {
+				return summary.KeyGroup, nil
+			}
+		}
+		if aws.StringValue(output.KeyGroupList.NextMarker) == "" {
+			return nil, nil
+		}
+		input.Marker = output.KeyGroupList.NextMarker
+	}
+}
+
+// KeyGroupApplierSet is a provider set for KeyGroupApplier.
+//
+//nolint:gochecknoglobals
+var KeyGroupApplierSet = wire.NewSet(
+	NewCloudFrontKeyGroupApplier,
+	wire.Bind(new(service.KeyGroupApplier), new(*CloudFrontKeyGroupApplier)),
+)
+
+// CloudFrontKeyGroupApplier is an implementation for KeyGroupApplier.
+type CloudFrontKeyGroupApplier struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.KeyGroupApplier = &CloudFrontKeyGroupApplier{}
+
+// NewCloudFrontKeyGroupApplier returns a new CloudFrontKeyGroupApplier struct.
+func NewCloudFrontKeyGroupApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontKeyGroupApplier {
+	return &CloudFrontKeyGroupApplier{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyKeyGroup creates the key group. If the key group already exists, it replaces the public keys of the key group.
+func (c *CloudFrontKeyGroupApplier) ApplyKeyGroup(ctx context.Context, input *service.KeyGroupApplierInput) (*service.KeyGroupApplierOutput, error) {
+	name := model.NewKeyGroupName(input.BucketName)
+	groupConfig := &cloudfront.KeyGroupConfig{
+		Name:    aws.String(name),
+		Comment: aws.String("Key group for signed URLs generated by spare"),
+		Items:   aws.StringSlice(input.PublicKeyIDs),
+	}
+
+	group, err := findKeyGroup(ctx, c.CloudFront, name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		output, err := c.CreateKeyGroupWithContext(ctx, &cloudfront.CreateKeyGroupInput{
+			KeyGroupConfig: groupConfig,
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to create a cloudfront key group")
+		}
+		return &service.KeyGroupApplierOutput{ID: aws.StringValue(output.KeyGroup.Id)}, nil
+	}
+
+	current, err := c.GetKeyGroupWithContext(ctx, &cloudfront.GetKeyGroupInput{
+		Id: group.Id,
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront key group")
+	}
+	if _, err := c.UpdateKeyGroupWithContext(ctx, &cloudfront.UpdateKeyGroupInput{
+		Id:             group.Id,
+		IfMatch:        current.ETag,
+		KeyGroupConfig: groupConfig,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a cloudfront key group")
+	}
+	return &service.KeyGroupApplierOutput{ID: aws.StringValue(group.Id)}, nil
+}
+
+// PublicKeyCreatorSet is a provider set for PublicKeyCreator.
+//
+//nolint:gochecknoglobals
+var PublicKeyCreatorSet = wire.NewSet(
+	NewCloudFrontPublicKeyCreator,
+	wire.Bind(new(service.PublicKeyCreator), new(*CloudFrontPublicKeyCreator)),
+)
+
+// CloudFrontPublicKeyCreator is an implementation for PublicKeyCreator.
+type CloudFrontPublicKeyCreator struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.PublicKeyCreator = &CloudFrontPublicKeyCreator{}
+
+// NewCloudFrontPublicKeyCreator returns a new CloudFrontPublicKeyCreator struct.
+func NewCloudFrontPublicKeyCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontPublicKeyCreator {
+	return &CloudFrontPublicKeyCreator{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// CreatePublicKey registers the public key to CloudFront.
+func (c *CloudFrontPublicKeyCreator) CreatePublicKey(ctx context.Context, input *service.PublicKeyCreatorInput) (*service.PublicKeyCreatorOutput, error) {
+	output, err := c.CreatePublicKeyWithContext(ctx, &cloudfront.CreatePublicKeyInput{
+		PublicKeyConfig: &cloudfront.PublicKeyConfig{
+			CallerReference: aws.String(uuid.New().String()),
+			Name:            aws.String(model.NewPublicKeyName(input.BucketName, time.Now())),
+			Comment:         aws.String("Public key for signed URLs generated by spare"),
+			EncodedKey:      aws.String(string(input.PublicKey)),
+		},
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to create a cloudfront public key")
+	}
+	return &service.PublicKeyCreatorOutput{ID: aws.StringValue(output.PublicKey.Id)}, nil
+}
+
+// PublicKeyDeleterSet is a provider set for PublicKeyDeleter.
+//
+//nolint:gochecknoglobals
+var PublicKeyDeleterSet = wire.NewSet(
+	NewCloudFrontPublicKeyDeleter,
+	wire.Bind(new(service.PublicKeyDeleter), new(*CloudFrontPublicKeyDeleter)),
+)
+
+// CloudFrontPublicKeyDeleter is an implementation for PublicKeyDeleter.
+type CloudFrontPublicKeyDeleter struct {
+	*cloudfront.CloudFront
+}
+
+var _ service.PublicKeyDeleter = &CloudFrontPublicKeyDeleter{}
+
+// NewCloudFrontPublicKeyDeleter returns a new CloudFrontPublicKeyDeleter struct.
+func NewCloudFrontPublicKeyDeleter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *CloudFrontPublicKeyDeleter {
+	return &CloudFrontPublicKeyDeleter{
+		CloudFront: cloudfront.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// DeletePublicKey deletes the public key.
+func (c *CloudFrontPublicKeyDeleter) DeletePublicKey(ctx context.Context, input *service.PublicKeyDeleterInput) (*service.PublicKeyDeleterOutput, error) {
+	key, err := c.GetPublicKeyWithContext(ctx, &cloudfront.GetPublicKeyInput{
+		Id: aws.String(input.ID),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to get a cloudfront public key")
+	}
+	if _, err := c.DeletePublicKeyWithContext(ctx, &cloudfront.DeletePublicKeyInput{
+		Id:      aws.String(input.ID),
+		IfMatch: key.ETag,
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to delete a cloudfront public key")
+	}
+	return &service.PublicKeyDeleterOutput{}, nil
+}
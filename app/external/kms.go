@@ -0,0 +1,138 @@
+package external
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// defaultKeyPolicyName is the name of the key policy. KMS keys have only the default key policy.
+const defaultKeyPolicyName = "default"
+
+// EncryptionKeyCreatorSet is a provider set for EncryptionKeyCreator.
+//
+//nolint:gochecknoglobals
+var EncryptionKeyCreatorSet = wire.NewSet(
+	NewKMSEncryptionKeyCreator,
+	wire.Bind(new(service.EncryptionKeyCreator), new(*KMSEncryptionKeyCreator)),
+)
+
+// KMSEncryptionKeyCreator is an implementation for EncryptionKeyCreator.
+type KMSEncryptionKeyCreator struct {
+	*kms.KMS
+}
+
+var _ service.EncryptionKeyCreator = &KMSEncryptionKeyCreator{}
+
+// NewKMSEncryptionKeyCreator returns a new KMSEncryptionKeyCreator struct.
+func NewKMSEncryptionKeyCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *KMSEncryptionKeyCreator {
+	return &KMSEncryptionKeyCreator{
+		KMS: kms.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// CreateEncryptionKey creates the symmetric KMS key with the alias, and enables the automatic key rotation.
+// If the alias already exists, it returns the KMS key of the alias.
+func (k *KMSEncryptionKeyCreator) CreateEncryptionKey(ctx context.Context, input *service.EncryptionKeyCreatorInput) (*service.EncryptionKeyCreatorOutput, error) {
+	describeOutput, err := k.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(input.Alias),
+	})
+	if err == nil {
+		return &service.EncryptionKeyCreatorOutput{
+			ARN:     model.KMSKeyARN(aws.StringValue(describeOutput.KeyMetadata.Arn)),
+			Created: false,
+		}, nil
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != kms.ErrCodeNotFoundException {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyCreate, err.Error())
+	}
+
+	createOutput, err := k.CreateKeyWithContext(ctx, &kms.CreateKeyInput{
+		Description: aws.String(input.Description),
+		KeySpec:     aws.String(kms.KeySpecSymmetricDefault),
+		KeyUsage:    aws.String(kms.KeyUsageTypeEncryptDecrypt),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyCreate, err.Error())
+	}
+	keyID := createOutput.KeyMetadata.KeyId
+
+	if _, err := k.CreateAliasWithContext(ctx, &kms.CreateAliasInput{
+		AliasName:   aws.String(input.Alias),
+		TargetKeyId: keyID,
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyCreate, err.Error())
+	}
+	if _, err := k.EnableKeyRotationWithContext(ctx, &kms.EnableKeyRotationInput{
+		KeyId: keyID,
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyCreate, err.Error())
+	}
+	return &service.EncryptionKeyCreatorOutput{
+		ARN:     model.KMSKeyARN(aws.StringValue(createOutput.KeyMetadata.Arn)),
+		Created: true,
+	}, nil
+}
+
+// EncryptionKeyPolicyApplierSet is a provider set for EncryptionKeyPolicyApplier.
+//
+//nolint:gochecknoglobals
+var EncryptionKeyPolicyApplierSet = wire.NewSet(
+	NewKMSEncryptionKeyPolicyApplier,
+	wire.Bind(new(service.EncryptionKeyPolicyApplier), new(*KMSEncryptionKeyPolicyApplier)),
+)
+
+// KMSEncryptionKeyPolicyApplier is an implementation for EncryptionKeyPolicyApplier.
+type KMSEncryptionKeyPolicyApplier struct {
+	*kms.KMS
+}
+
+var _ service.EncryptionKeyPolicyApplier = &KMSEncryptionKeyPolicyApplier{}
+
+// NewKMSEncryptionKeyPolicyApplier returns a new KMSEncryptionKeyPolicyApplier struct.
+func NewKMSEncryptionKeyPolicyApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *KMSEncryptionKeyPolicyApplier {
+	return &KMSEncryptionKeyPolicyApplier{
+		KMS: kms.New(newS3Session(credentials, region, endpoint)),
+	}
+}
+
+// ApplyEncryptionKeyPolicy merges the statement into the key policy of the KMS key.
+func (k *KMSEncryptionKeyPolicyApplier) ApplyEncryptionKeyPolicy(ctx context.Context, input *service.EncryptionKeyPolicyApplierInput) (*service.EncryptionKeyPolicyApplierOutput, error) {
+	// GetKeyPolicy and PutKeyPolicy do not accept the alias.
+	describeOutput, err := k.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(input.KeyID),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyPolicyApply, err.Error())
+	}
+	keyID := describeOutput.KeyMetadata.Arn
+
+	policyOutput, err := k.GetKeyPolicyWithContext(ctx, &kms.GetKeyPolicyInput{
+		KeyId:      keyID,
+		PolicyName: aws.String(defaultKeyPolicyName),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyPolicyApply, err.Error())
+	}
+	policy, err := model.MergeKeyPolicy(aws.StringValue(policyOutput.Policy), input.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := k.PutKeyPolicyWithContext(ctx, &kms.PutKeyPolicyInput{
+		KeyId:      keyID,
+		PolicyName: aws.String(defaultKeyPolicyName),
+		Policy:     aws.String(policy),
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrEncryptionKeyPolicyApply, err.Error())
+	}
+	return &service.EncryptionKeyPolicyApplierOutput{}, nil
+}
@@ -5,6 +5,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -67,13 +71,18 @@ var FileUploaderSet = wire.NewSet(
 // S3Uploader is an implementation for FileUploader.
 type S3Uploader struct {
 	*s3manager.Uploader
+	// storage is the S3-compatible storage. If it's nil, the files are uploaded to Amazon S3.
+	storage *model.StorageEndpoint
 }
 
 var _ service.FileUploader = &S3Uploader{}
 
 // NewS3Uploader returns a new S3Uploader struct.
-func NewS3Uploader(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *S3Uploader {
-	return &S3Uploader{s3manager.NewUploader(newS3Session(profile, region, endpoint))}
+func NewS3Uploader(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3Uploader {
+	return &S3Uploader{
+		Uploader: s3manager.NewUploaderWithClient(newS3Client(credentials, region, endpoint, storage)),
+		storage:  storage,
+	}
 }
 
 // UploadFile uploads a file to S3.
@@ -93,15 +102,71 @@ func (s *S3Uploader) UploadFile(_ context.Context, input *service.FileUploaderIn
 		Key:         aws.String(input.Key),
 		ContentType: aws.String(contentType),
 	}
+	if err := setObjectHeaders(uploadInput, input.Headers); err != nil {
+		return nil, errfmt.Wrap(service.ErrFileUpload, err.Error())
+	}
+	// The S3-compatible storage encrypts the objects with its own settings (e.g. R2 always encrypts them),
+	// and the SSE headers fail on the storage without KMS (e.g. MinIO).
+	if s.storage == nil {
+		setObjectEncryption(uploadInput, input.Encryption)
+	}
 
 	if _, err := s.Upload(uploadInput); err != nil {
 		return nil, err
 	}
 	return &service.FileUploaderOutput{
-		DetectedMIMEType: contentType,
+		DetectedMIMEType: aws.StringValue(uploadInput.ContentType),
 	}, nil
 }
 
+// setObjectHeaders sets the headers to the object metadata of the upload input.
+// S3 returns them with the object. The headers that S3 does not store are ignored.
+func setObjectHeaders(uploadInput *s3manager.UploadInput, headers []model.HTTPHeader) error {
+	for _, h := range headers {
+		switch h.Name {
+		case "Cache-Control":
+			uploadInput.CacheControl = aws.String(h.Value)
+		case "Content-Disposition":
+			uploadInput.ContentDisposition = aws.String(h.Value)
+		case "Content-Encoding":
+			uploadInput.ContentEncoding = aws.String(h.Value)
+		case "Content-Language":
+			uploadInput.ContentLanguage = aws.String(h.Value)
+		case "Content-Type":
+			uploadInput.ContentType = aws.String(h.Value)
+		case "Expires":
+			expires, err := http.ParseTime(h.Value)
+			if err != nil {
+				return fmt.Errorf("expires header %s is not a HTTP date: %w", h.Value, err)
+			}
+			uploadInput.Expires = aws.Time(expires)
+		default:
+			// e.g. X-Amz-Meta-Spare-Maintenance is stored as the user-defined metadata "spare-maintenance".
+			if name, found := strings.CutPrefix(h.Name, "X-Amz-Meta-"); found {
+				if uploadInput.Metadata == nil {
+					uploadInput.Metadata = map[string]*string{}
+				}
+				uploadInput.Metadata[strings.ToLower(name)] = aws.String(h.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// setObjectEncryption sets the SSE headers that match the default encryption of the bucket to the upload input.
+func setObjectEncryption(uploadInput *s3manager.UploadInput, encryption *model.Encryption) {
+	if encryption == nil {
+		return
+	}
+	if !encryption.KMS() {
+		uploadInput.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+		return
+	}
+	uploadInput.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+	uploadInput.SSEKMSKeyId = aws.String(encryption.KMSKeyID)
+	uploadInput.BucketKeyEnabled = aws.Bool(true)
+}
+
 // BuckerCreatorSet is a provider set for BuckerCreator.
 //
 //nolint:gochecknoglobals
@@ -113,24 +178,30 @@ var BuckerCreatorSet = wire.NewSet(
 // S3BucketCreator is an implementation for BucketCreator.
 type S3BucketCreator struct {
 	svc *s3.S3
+	// storage is the S3-compatible storage. If it's nil, the bucket is created in Amazon S3.
+	storage *model.StorageEndpoint
 }
 
 var _ service.BucketCreator = &S3BucketCreator{}
 
 // NewS3BucketCreator returns a new S3BucketCreator struct.
-func NewS3BucketCreator(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *S3BucketCreator {
-	return &S3BucketCreator{s3.New(newS3Session(profile, region, endpoint))}
+func NewS3BucketCreator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketCreator {
+	return &S3BucketCreator{svc: newS3Client(credentials, region, endpoint, storage), storage: storage}
 }
 
 // CreateBucket creates a bucket on S3.
 func (s *S3BucketCreator) CreateBucket(_ context.Context, input *service.BucketCreatorInput) (*service.BucketCreatorOutput, error) {
-	createBucketConfig := &s3.CreateBucketConfiguration{}
-	createBucketConfig.SetLocationConstraint(input.Region.String())
+	createBucketInput := &s3.CreateBucketInput{
+		Bucket: aws.String(input.Bucket.String()),
+	}
+	// The S3-compatible storage has its own regions (e.g. "auto" of R2), so the location constraint is omitted.
+	if s.storage == nil {
+		createBucketConfig := &s3.CreateBucketConfiguration{}
+		createBucketConfig.SetLocationConstraint(input.Region.String())
+		createBucketInput.CreateBucketConfiguration = createBucketConfig
+	}
 
-	if _, err := s.svc.CreateBucket(&s3.CreateBucketInput{
-		Bucket:                    aws.String(input.Bucket.String()),
-		CreateBucketConfiguration: createBucketConfig,
-	}); err != nil {
+	if _, err := s.svc.CreateBucket(createBucketInput); err != nil {
 		var awsErr awserr.Error
 		if errors.As(err, &awsErr) {
 			switch awsErr.Code() {
@@ -147,6 +218,56 @@ func (s *S3BucketCreator) CreateBucket(_ context.Context, input *service.BucketC
 	return &service.BucketCreatorOutput{}, nil
 }
 
+// BucketAvailabilityCheckerSet is a provider set for BucketAvailabilityChecker.
+//
+//nolint:gochecknoglobals
+var BucketAvailabilityCheckerSet = wire.NewSet(
+	NewS3BucketAvailabilityChecker,
+	wire.Bind(new(service.BucketAvailabilityChecker), new(*S3BucketAvailabilityChecker)),
+)
+
+// S3BucketAvailabilityChecker is an implementation for BucketAvailabilityChecker.
+type S3BucketAvailabilityChecker struct {
+	svc *s3.S3
+}
+
+var _ service.BucketAvailabilityChecker = &S3BucketAvailabilityChecker{}
+
+// NewS3BucketAvailabilityChecker returns a new S3BucketAvailabilityChecker struct.
+func NewS3BucketAvailabilityChecker(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketAvailabilityChecker {
+	return &S3BucketAvailabilityChecker{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// CheckBucketAvailability checks the bucket with HeadBucket. S3 returns 404 if nobody owns the bucket,
+// 403 if another account owns the bucket, and 301 if the bucket is in another region.
+func (s *S3BucketAvailabilityChecker) CheckBucketAvailability(ctx context.Context, input *service.BucketAvailabilityCheckerInput) (*service.BucketAvailabilityCheckerOutput, error) {
+	headInput := &s3.HeadBucketInput{
+		Bucket: aws.String(input.Bucket.String()),
+	}
+	if input.Account != "" {
+		headInput.ExpectedBucketOwner = aws.String(input.Account)
+	}
+	_, err := s.svc.HeadBucketWithContext(ctx, headInput)
+	if err == nil {
+		return &service.BucketAvailabilityCheckerOutput{Availability: model.BucketAvailabilityOwned}, nil
+	}
+
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) {
+		return nil, errfmt.Wrap(service.ErrBucketHead, err.Error())
+	}
+	switch reqErr.StatusCode() {
+	case http.StatusNotFound:
+		return &service.BucketAvailabilityCheckerOutput{Availability: model.BucketAvailabilityAvailable}, nil
+	case http.StatusForbidden:
+		return &service.BucketAvailabilityCheckerOutput{Availability: model.BucketAvailabilityTaken}, nil
+	case http.StatusMovedPermanently:
+		return &service.BucketAvailabilityCheckerOutput{Availability: model.BucketAvailabilityOtherRegion}, nil
+	default:
+		return nil, errfmt.Wrap(service.ErrBucketHead, err.Error())
+	}
+}
+
 // BucketPublicAccessBlockerSet is a provider set for BucketPublicAccessBlocker.
 //
 //nolint:gochecknoglobals
@@ -163,8 +284,8 @@ type S3BucketPublicAccessBlocker struct {
 var _ service.BucketPublicAccessBlocker = &S3BucketPublicAccessBlocker{}
 
 // NewS3BucketPublicAccessBlocker returns a new S3BucketPublicAccessBlocker struct.
-func NewS3BucketPublicAccessBlocker(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *S3BucketPublicAccessBlocker {
-	return &S3BucketPublicAccessBlocker{s3.New(newS3Session(profile, region, endpoint))}
+func NewS3BucketPublicAccessBlocker(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketPublicAccessBlocker {
+	return &S3BucketPublicAccessBlocker{newS3Client(credentials, region, endpoint, storage)}
 }
 
 // BlockBucketPublicAccess blocks public access to a bucket on S3.
@@ -200,8 +321,8 @@ type S3BucketPolicySetter struct {
 var _ service.BucketPolicySetter = &S3BucketPolicySetter{}
 
 // NewS3BucketPolicySetter returns a new S3BucketPolicySetter struct.
-func NewS3BucketPolicySetter(profile model.AWSProfile, region model.Region, endpoint *model.Endpoint) *S3BucketPolicySetter {
-	return &S3BucketPolicySetter{s3.New(newS3Session(profile, region, endpoint))}
+func NewS3BucketPolicySetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketPolicySetter {
+	return &S3BucketPolicySetter{newS3Client(credentials, region, endpoint, storage)}
 }
 
 // SetBucketPolicy sets a bucket policy on S3.
@@ -219,3 +340,804 @@ func (s *S3BucketPolicySetter) SetBucketPolicy(_ context.Context, input *service
 	}
 	return &service.BucketPolicySetterOutput{}, nil
 }
+
+// BucketCORSSetterSet is a provider set for BucketCORSSetter.
+//
+//nolint:gochecknoglobals
+var BucketCORSSetterSet = wire.NewSet(
+	NewS3BucketCORSSetter,
+	wire.Bind(new(service.BucketCORSSetter), new(*S3BucketCORSSetter)),
+)
+
+// S3BucketCORSSetter is an implementation for BucketCORSSetter.
+type S3BucketCORSSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketCORSSetter = &S3BucketCORSSetter{}
+
+// NewS3BucketCORSSetter returns a new S3BucketCORSSetter struct.
+func NewS3BucketCORSSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketCORSSetter {
+	return &S3BucketCORSSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketCORS sets the CORS rules on S3. If the CORS settings are nil, it deletes the CORS rules.
+func (s *S3BucketCORSSetter) SetBucketCORS(ctx context.Context, input *service.BucketCORSSetterInput) (*service.BucketCORSSetterOutput, error) {
+	if input.CORS == nil {
+		if _, err := s.svc.DeleteBucketCorsWithContext(ctx, &s3.DeleteBucketCorsInput{
+			Bucket: aws.String(input.Bucket.String()),
+		}); err != nil {
+			return nil, errfmt.Wrap(service.ErrBucketCORSSet, err.Error())
+		}
+		return &service.BucketCORSSetterOutput{}, nil
+	}
+
+	// S3 answers the preflight requests by itself, so OPTIONS is not an allowed method of the CORS rule.
+	methods := make([]string, 0, len(input.CORS.AllowMethods))
+	for _, m := range input.CORS.AllowMethods {
+		if m != http.MethodOptions {
+			methods = append(methods, m)
+		}
+	}
+	if _, err := s.svc.PutBucketCorsWithContext(ctx, &s3.PutBucketCorsInput{
+		Bucket: aws.String(input.Bucket.String()),
+		CORSConfiguration: &s3.CORSConfiguration{
+			CORSRules: []*s3.CORSRule{
+				{
+					AllowedOrigins: aws.StringSlice(input.CORS.AllowOrigins),
+					AllowedMethods: aws.StringSlice(methods),
+					AllowedHeaders: aws.StringSlice(input.CORS.AllowHeaders),
+					MaxAgeSeconds:  aws.Int64(input.CORS.MaxAge),
+				},
+			},
+		},
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketCORSSet, err.Error())
+	}
+	return &service.BucketCORSSetterOutput{}, nil
+}
+
+// ReleaseHistoryGetterSet is a provider set for ReleaseHistoryGetter.
+//
+//nolint:gochecknoglobals
+var ReleaseHistoryGetterSet = wire.NewSet(
+	NewS3ReleaseHistoryGetter,
+	wire.Bind(new(service.ReleaseHistoryGetter), new(*S3ReleaseHistoryGetter)),
+)
+
+// S3ReleaseHistoryGetter is an implementation for ReleaseHistoryGetter.
+type S3ReleaseHistoryGetter struct {
+	svc *s3.S3
+}
+
+var _ service.ReleaseHistoryGetter = &S3ReleaseHistoryGetter{}
+
+// NewS3ReleaseHistoryGetter returns a new S3ReleaseHistoryGetter struct.
+func NewS3ReleaseHistoryGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3ReleaseHistoryGetter {
+	return &S3ReleaseHistoryGetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// GetReleaseHistory gets the release history from S3.
+// If the release history does not exist, it returns an empty history.
+func (s *S3ReleaseHistoryGetter) GetReleaseHistory(ctx context.Context, input *service.ReleaseHistoryGetterInput) (*service.ReleaseHistoryGetterOutput, error) {
+	data, err := getStateObject(ctx, s.svc, input.Bucket, model.ReleaseHistoryKey)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseHistoryGet, err.Error())
+	}
+	if data == nil {
+		return &service.ReleaseHistoryGetterOutput{
+			History: model.NewReleaseHistory(),
+		}, nil
+	}
+
+	history, err := model.ParseReleaseHistory(data)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseHistoryGet, err.Error())
+	}
+	return &service.ReleaseHistoryGetterOutput{
+		History: history,
+	}, nil
+}
+
+// ReleaseHistoryPutterSet is a provider set for ReleaseHistoryPutter.
+//
+//nolint:gochecknoglobals
+var ReleaseHistoryPutterSet = wire.NewSet(
+	NewS3ReleaseHistoryPutter,
+	wire.Bind(new(service.ReleaseHistoryPutter), new(*S3ReleaseHistoryPutter)),
+)
+
+// S3ReleaseHistoryPutter is an implementation for ReleaseHistoryPutter.
+type S3ReleaseHistoryPutter struct {
+	svc *s3.S3
+}
+
+var _ service.ReleaseHistoryPutter = &S3ReleaseHistoryPutter{}
+
+// NewS3ReleaseHistoryPutter returns a new S3ReleaseHistoryPutter struct.
+func NewS3ReleaseHistoryPutter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3ReleaseHistoryPutter {
+	return &S3ReleaseHistoryPutter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// PutReleaseHistory puts the release history to S3.
+func (s *S3ReleaseHistoryPutter) PutReleaseHistory(ctx context.Context, input *service.ReleaseHistoryPutterInput) (*service.ReleaseHistoryPutterOutput, error) {
+	history, err := input.History.String()
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseHistoryPut, err.Error())
+	}
+	if err := putStateObject(ctx, s.svc, input.Bucket, model.ReleaseHistoryKey, history); err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseHistoryPut, err.Error())
+	}
+	return &service.ReleaseHistoryPutterOutput{}, nil
+}
+
+// ReleaseManifestPutterSet is a provider set for ReleaseManifestPutter.
+//
+//nolint:gochecknoglobals
+var ReleaseManifestPutterSet = wire.NewSet(
+	NewS3ReleaseManifestPutter,
+	wire.Bind(new(service.ReleaseManifestPutter), new(*S3ReleaseManifestPutter)),
+)
+
+// S3ReleaseManifestPutter is an implementation for ReleaseManifestPutter.
+type S3ReleaseManifestPutter struct {
+	svc *s3.S3
+}
+
+var _ service.ReleaseManifestPutter = &S3ReleaseManifestPutter{}
+
+// NewS3ReleaseManifestPutter returns a new S3ReleaseManifestPutter struct.
+func NewS3ReleaseManifestPutter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3ReleaseManifestPutter {
+	return &S3ReleaseManifestPutter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// PutReleaseManifest puts the release manifest to S3.
+func (s *S3ReleaseManifestPutter) PutReleaseManifest(ctx context.Context, input *service.ReleaseManifestPutterInput) (*service.ReleaseManifestPutterOutput, error) {
+	manifest, err := input.Manifest.String()
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseManifestPut, err.Error())
+	}
+	if err := putStateObject(ctx, s.svc, input.Bucket, input.Manifest.Key(), manifest); err != nil {
+		return nil, errfmt.Wrap(service.ErrReleaseManifestPut, err.Error())
+	}
+	return &service.ReleaseManifestPutterOutput{}, nil
+}
+
+// getStateObject gets the JSON object that spare uses to manage the state (e.g. release history).
+// If the object does not exist, it returns nil without error.
+func getStateObject(ctx context.Context, svc *s3.S3, bucket model.BucketName, key string) ([]byte, error) {
+	output, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket.String()),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer output.Body.Close() //nolint:errcheck
+
+	return io.ReadAll(output.Body)
+}
+
+// putStateObject puts the JSON object that spare uses to manage the state (e.g. release history).
+func putStateObject(ctx context.Context, svc *s3.S3, bucket model.BucketName, key, data string) error {
+	_, err := svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket.String()),
+		Key:         aws.String(key),
+		Body:        strings.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+// BucketObjectListerSet is a provider set for BucketObjectLister.
+//
+//nolint:gochecknoglobals
+var BucketObjectListerSet = wire.NewSet(
+	NewS3BucketObjectLister,
+	wire.Bind(new(service.BucketObjectLister), new(*S3BucketObjectLister)),
+)
+
+// S3BucketObjectLister is an implementation for BucketObjectLister.
+type S3BucketObjectLister struct {
+	svc *s3.S3
+}
+
+var _ service.BucketObjectLister = &S3BucketObjectLister{}
+
+// NewS3BucketObjectLister returns a new S3BucketObjectLister struct.
+func NewS3BucketObjectLister(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketObjectLister {
+	return &S3BucketObjectLister{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// ListBucketObjects lists objects in the bucket on S3.
+func (s *S3BucketObjectLister) ListBucketObjects(ctx context.Context, input *service.BucketObjectListerInput) (*service.BucketObjectListerOutput, error) {
+	objects := make(model.BucketObjects, 0)
+	err := s.svc.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(input.Bucket.String()),
+		Prefix: aws.String(input.Prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, o := range page.Contents {
+			objects = append(objects, model.BucketObject{
+				Key:  aws.StringValue(o.Key),
+				Size: aws.Int64Value(o.Size),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketObjectList, err.Error())
+	}
+	return &service.BucketObjectListerOutput{
+		Objects: objects,
+	}, nil
+}
+
+// BucketObjectDeleterSet is a provider set for BucketObjectDeleter.
+//
+//nolint:gochecknoglobals
+var BucketObjectDeleterSet = wire.NewSet(
+	NewS3BucketObjectDeleter,
+	wire.Bind(new(service.BucketObjectDeleter), new(*S3BucketObjectDeleter)),
+)
+
+// S3BucketObjectDeleter is an implementation for BucketObjectDeleter.
+type S3BucketObjectDeleter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketObjectDeleter = &S3BucketObjectDeleter{}
+
+// NewS3BucketObjectDeleter returns a new S3BucketObjectDeleter struct.
+func NewS3BucketObjectDeleter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketObjectDeleter {
+	return &S3BucketObjectDeleter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// deleteObjectsBatchSize is the maximum number of keys in a DeleteObjects request.
+const deleteObjectsBatchSize = 1000
+
+// DeleteBucketObjects deletes objects in the bucket on S3.
+// The objects are deleted in batches of 1000 keys, that is the limit of DeleteObjects.
+func (s *S3BucketObjectDeleter) DeleteBucketObjects(ctx context.Context, input *service.BucketObjectDeleterInput) (*service.BucketObjectDeleterOutput, error) {
+	for start := 0; start < len(input.Keys); start += deleteObjectsBatchSize {
+		end := start + deleteObjectsBatchSize
+		if end > len(input.Keys) {
+			end = len(input.Keys)
+		}
+
+		identifiers := make([]*s3.ObjectIdentifier, 0, end-start)
+		for _, key := range input.Keys[start:end] {
+			identifiers = append(identifiers, &s3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		output, err := s.svc.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(input.Bucket.String()),
+			Delete: &s3.Delete{
+				Objects: identifiers,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrBucketObjectDelete, err.Error())
+		}
+		if len(output.Errors) > 0 {
+			e := output.Errors[0]
+			return nil, errfmt.Wrap(service.ErrBucketObjectDelete,
+				fmt.Sprintf("%d objects are not deleted. e.g. %s: %s", len(output.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message)))
+		}
+	}
+	return &service.BucketObjectDeleterOutput{}, nil
+}
+
+// PreviewListGetterSet is a provider set for PreviewListGetter.
+//
+//nolint:gochecknoglobals
+var PreviewListGetterSet = wire.NewSet(
+	NewS3PreviewListGetter,
+	wire.Bind(new(service.PreviewListGetter), new(*S3PreviewListGetter)),
+)
+
+// S3PreviewListGetter is an implementation for PreviewListGetter.
+type S3PreviewListGetter struct {
+	svc *s3.S3
+}
+
+var _ service.PreviewListGetter = &S3PreviewListGetter{}
+
+// NewS3PreviewListGetter returns a new S3PreviewListGetter struct.
+func NewS3PreviewListGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3PreviewListGetter {
+	return &S3PreviewListGetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// GetPreviewList gets the preview list from S3.
+// If the preview list does not exist, it returns an empty list.
+func (s *S3PreviewListGetter) GetPreviewList(ctx context.Context, input *service.PreviewListGetterInput) (*service.PreviewListGetterOutput, error) {
+	data, err := getStateObject(ctx, s.svc, input.Bucket, model.PreviewListKey)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrPreviewListGet, err.Error())
+	}
+	if data == nil {
+		return &service.PreviewListGetterOutput{
+			List: model.NewPreviewList(),
+		}, nil
+	}
+
+	list, err := model.ParsePreviewList(data)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrPreviewListGet, err.Error())
+	}
+	return &service.PreviewListGetterOutput{
+		List: list,
+	}, nil
+}
+
+// PreviewListPutterSet is a provider set for PreviewListPutter.
+//
+//nolint:gochecknoglobals
+var PreviewListPutterSet = wire.NewSet(
+	NewS3PreviewListPutter,
+	wire.Bind(new(service.PreviewListPutter), new(*S3PreviewListPutter)),
+)
+
+// S3PreviewListPutter is an implementation for PreviewListPutter.
+type S3PreviewListPutter struct {
+	svc *s3.S3
+}
+
+var _ service.PreviewListPutter = &S3PreviewListPutter{}
+
+// NewS3PreviewListPutter returns a new S3PreviewListPutter struct.
+func NewS3PreviewListPutter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3PreviewListPutter {
+	return &S3PreviewListPutter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// PutPreviewList puts the preview list to S3.
+func (s *S3PreviewListPutter) PutPreviewList(ctx context.Context, input *service.PreviewListPutterInput) (*service.PreviewListPutterOutput, error) {
+	list, err := input.List.String()
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrPreviewListPut, err.Error())
+	}
+	if err := putStateObject(ctx, s.svc, input.Bucket, model.PreviewListKey, list); err != nil {
+		return nil, errfmt.Wrap(service.ErrPreviewListPut, err.Error())
+	}
+	return &service.PreviewListPutterOutput{}, nil
+}
+
+// MaintenanceGetterSet is a provider set for MaintenanceGetter.
+//
+//nolint:gochecknoglobals
+var MaintenanceGetterSet = wire.NewSet(
+	NewS3MaintenanceGetter,
+	wire.Bind(new(service.MaintenanceGetter), new(*S3MaintenanceGetter)),
+)
+
+// S3MaintenanceGetter is an implementation for MaintenanceGetter.
+type S3MaintenanceGetter struct {
+	svc *s3.S3
+}
+
+var _ service.MaintenanceGetter = &S3MaintenanceGetter{}
+
+// NewS3MaintenanceGetter returns a new S3MaintenanceGetter struct.
+func NewS3MaintenanceGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3MaintenanceGetter {
+	return &S3MaintenanceGetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// GetMaintenance gets the maintenance mode state from S3.
+// If the state does not exist, the maintenance mode is off.
+func (s *S3MaintenanceGetter) GetMaintenance(ctx context.Context, input *service.MaintenanceGetterInput) (*service.MaintenanceGetterOutput, error) {
+	data, err := getStateObject(ctx, s.svc, input.Bucket, model.MaintenanceKey)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrMaintenanceGet, err.Error())
+	}
+	if data == nil {
+		return &service.MaintenanceGetterOutput{}, nil
+	}
+
+	maintenance, err := model.ParseMaintenance(data)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrMaintenanceGet, err.Error())
+	}
+	return &service.MaintenanceGetterOutput{
+		Maintenance: maintenance,
+	}, nil
+}
+
+// MaintenancePutterSet is a provider set for MaintenancePutter.
+//
+//nolint:gochecknoglobals
+var MaintenancePutterSet = wire.NewSet(
+	NewS3MaintenancePutter,
+	wire.Bind(new(service.MaintenancePutter), new(*S3MaintenancePutter)),
+)
+
+// S3MaintenancePutter is an implementation for MaintenancePutter.
+type S3MaintenancePutter struct {
+	svc *s3.S3
+}
+
+var _ service.MaintenancePutter = &S3MaintenancePutter{}
+
+// NewS3MaintenancePutter returns a new S3MaintenancePutter struct.
+func NewS3MaintenancePutter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3MaintenancePutter {
+	return &S3MaintenancePutter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// PutMaintenance puts the maintenance mode state to S3. If the maintenance mode is nil, the state is deleted.
+func (s *S3MaintenancePutter) PutMaintenance(ctx context.Context, input *service.MaintenancePutterInput) (*service.MaintenancePutterOutput, error) {
+	if input.Maintenance == nil {
+		if _, err := s.svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(input.Bucket.String()),
+			Key:    aws.String(model.MaintenanceKey),
+		}); err != nil {
+			return nil, errfmt.Wrap(service.ErrMaintenancePut, err.Error())
+		}
+		return &service.MaintenancePutterOutput{}, nil
+	}
+
+	maintenance, err := input.Maintenance.String()
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrMaintenancePut, err.Error())
+	}
+	if err := putStateObject(ctx, s.svc, input.Bucket, model.MaintenanceKey, maintenance); err != nil {
+		return nil, errfmt.Wrap(service.ErrMaintenancePut, err.Error())
+	}
+	return &service.MaintenancePutterOutput{}, nil
+}
+
+// ViewerRulesGetterSet is a provider set for ViewerRulesGetter.
+//
+//nolint:gochecknoglobals
+var ViewerRulesGetterSet = wire.NewSet(
+	NewS3ViewerRulesGetter,
+	wire.Bind(new(service.ViewerRulesGetter), new(*S3ViewerRulesGetter)),
+)
+
+// S3ViewerRulesGetter is an implementation for ViewerRulesGetter.
+type S3ViewerRulesGetter struct {
+	svc *s3.S3
+}
+
+var _ service.ViewerRulesGetter = &S3ViewerRulesGetter{}
+
+// NewS3ViewerRulesGetter returns a new S3ViewerRulesGetter struct.
+func NewS3ViewerRulesGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3ViewerRulesGetter {
+	return &S3ViewerRulesGetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// GetViewerRules gets the rules of the viewer request function from S3.
+// If the rules are not recorded, it returns nil rules.
+func (s *S3ViewerRulesGetter) GetViewerRules(ctx context.Context, input *service.ViewerRulesGetterInput) (*service.ViewerRulesGetterOutput, error) {
+	data, err := getStateObject(ctx, s.svc, input.Bucket, model.ViewerRulesKey)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrViewerRulesGet, err.Error())
+	}
+	if data == nil {
+		return &service.ViewerRulesGetterOutput{}, nil
+	}
+
+	rules, err := model.ParseViewerRules(data)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrViewerRulesGet, err.Error())
+	}
+	return &service.ViewerRulesGetterOutput{
+		Rules: rules,
+	}, nil
+}
+
+// ViewerRulesPutterSet is a provider set for ViewerRulesPutter.
+//
+//nolint:gochecknoglobals
+var ViewerRulesPutterSet = wire.NewSet(
+	NewS3ViewerRulesPutter,
+	wire.Bind(new(service.ViewerRulesPutter), new(*S3ViewerRulesPutter)),
+)
+
+// S3ViewerRulesPutter is an implementation for ViewerRulesPutter.
+type S3ViewerRulesPutter struct {
+	svc *s3.S3
+}
+
+var _ service.ViewerRulesPutter = &S3ViewerRulesPutter{}
+
+// NewS3ViewerRulesPutter returns a new S3ViewerRulesPutter struct.
+func NewS3ViewerRulesPutter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3ViewerRulesPutter {
+	return &S3ViewerRulesPutter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// PutViewerRules puts the rules of the viewer request function to S3.
+func (s *S3ViewerRulesPutter) PutViewerRules(ctx context.Context, input *service.ViewerRulesPutterInput) (*service.ViewerRulesPutterOutput, error) {
+	rules, err := input.Rules.String()
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrViewerRulesPut, err.Error())
+	}
+	if err := putStateObject(ctx, s.svc, input.Bucket, model.ViewerRulesKey, rules); err != nil {
+		return nil, errfmt.Wrap(service.ErrViewerRulesPut, err.Error())
+	}
+	return &service.ViewerRulesPutterOutput{}, nil
+}
+
+// BucketOwnershipSetterSet is a provider set for BucketOwnershipSetter.
+//
+//nolint:gochecknoglobals
+var BucketOwnershipSetterSet = wire.NewSet(
+	NewS3BucketOwnershipSetter,
+	wire.Bind(new(service.BucketOwnershipSetter), new(*S3BucketOwnershipSetter)),
+)
+
+// S3BucketOwnershipSetter is an implementation for BucketOwnershipSetter.
+type S3BucketOwnershipSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketOwnershipSetter = &S3BucketOwnershipSetter{}
+
+// NewS3BucketOwnershipSetter returns a new S3BucketOwnershipSetter struct.
+func NewS3BucketOwnershipSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketOwnershipSetter {
+	return &S3BucketOwnershipSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketOwnership sets the object ownership of the bucket on S3.
+func (s *S3BucketOwnershipSetter) SetBucketOwnership(ctx context.Context, input *service.BucketOwnershipSetterInput) (*service.BucketOwnershipSetterOutput, error) {
+	if _, err := s.svc.PutBucketOwnershipControlsWithContext(ctx, &s3.PutBucketOwnershipControlsInput{
+		Bucket: aws.String(input.Bucket.String()),
+		OwnershipControls: &s3.OwnershipControls{
+			Rules: []*s3.OwnershipControlsRule{
+				{ObjectOwnership: aws.String(input.Ownership.String())},
+			},
+		},
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketOwnershipSet, err.Error())
+	}
+	return &service.BucketOwnershipSetterOutput{}, nil
+}
+
+// BucketLifecycleSetterSet is a provider set for BucketLifecycleSetter.
+//
+//nolint:gochecknoglobals
+var BucketLifecycleSetterSet = wire.NewSet(
+	NewS3BucketLifecycleSetter,
+	wire.Bind(new(service.BucketLifecycleSetter), new(*S3BucketLifecycleSetter)),
+)
+
+// S3BucketLifecycleSetter is an implementation for BucketLifecycleSetter.
+type S3BucketLifecycleSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketLifecycleSetter = &S3BucketLifecycleSetter{}
+
+// NewS3BucketLifecycleSetter returns a new S3BucketLifecycleSetter struct.
+func NewS3BucketLifecycleSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketLifecycleSetter {
+	return &S3BucketLifecycleSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketLifecycle sets the lifecycle rules on S3. If the rules are empty, it deletes the lifecycle configuration.
+func (s *S3BucketLifecycleSetter) SetBucketLifecycle(ctx context.Context, input *service.BucketLifecycleSetterInput) (*service.BucketLifecycleSetterOutput, error) {
+	if len(input.Rules) == 0 {
+		if _, err := s.svc.DeleteBucketLifecycleWithContext(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(input.Bucket.String()),
+		}); err != nil {
+			return nil, errfmt.Wrap(service.ErrBucketLifecycleSet, err.Error())
+		}
+		return &service.BucketLifecycleSetterOutput{}, nil
+	}
+
+	rules := make([]*s3.LifecycleRule, 0, len(input.Rules))
+	for _, r := range input.Rules {
+		rule := &s3.LifecycleRule{
+			ID:     aws.String(r.ID),
+			Status: aws.String(s3.ExpirationStatusEnabled),
+			Filter: &s3.LifecycleRuleFilter{Prefix: aws.String(r.Prefix)},
+		}
+		if r.ExpirationDays > 0 {
+			rule.Expiration = &s3.LifecycleExpiration{Days: aws.Int64(int64(r.ExpirationDays))}
+		} else if r.ExpiredObjectDeleteMarker {
+			rule.Expiration = &s3.LifecycleExpiration{ExpiredObjectDeleteMarker: aws.Bool(true)}
+		}
+		if r.NoncurrentVersionExpirationDays > 0 {
+			rule.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int64(int64(r.NoncurrentVersionExpirationDays)),
+			}
+		}
+		if r.AbortIncompleteMultipartUploadDays > 0 {
+			rule.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int64(int64(r.AbortIncompleteMultipartUploadDays)),
+			}
+		}
+		for _, t := range r.Transitions {
+			rule.Transitions = append(rule.Transitions, &s3.Transition{
+				Days:         aws.Int64(int64(t.Days)),
+				StorageClass: aws.String(t.StorageClass.String()),
+			})
+		}
+		rules = append(rules, rule)
+	}
+	if _, err := s.svc.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(input.Bucket.String()),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{Rules: rules},
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketLifecycleSet, err.Error())
+	}
+	return &service.BucketLifecycleSetterOutput{}, nil
+}
+
+// BucketVersioningSetterSet is a provider set for BucketVersioningSetter.
+//
+//nolint:gochecknoglobals
+var BucketVersioningSetterSet = wire.NewSet(
+	NewS3BucketVersioningSetter,
+	wire.Bind(new(service.BucketVersioningSetter), new(*S3BucketVersioningSetter)),
+)
+
+// S3BucketVersioningSetter is an implementation for BucketVersioningSetter.
+type S3BucketVersioningSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketVersioningSetter = &S3BucketVersioningSetter{}
+
+// NewS3BucketVersioningSetter returns a new S3BucketVersioningSetter struct.
+func NewS3BucketVersioningSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketVersioningSetter {
+	return &S3BucketVersioningSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketVersioning enables or suspends the versioning of the bucket.
+// The versioning of the bucket that has never been versioned is left as it is when it is disabled,
+// because S3 can not return the bucket to the unversioned state.
+func (s *S3BucketVersioningSetter) SetBucketVersioning(ctx context.Context, input *service.BucketVersioningSetterInput) (*service.BucketVersioningSetterOutput, error) {
+	current, err := s.svc.GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(input.Bucket.String()),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketVersioningSet, err.Error())
+	}
+	status := model.VersioningStatus(aws.StringValue(current.Status))
+
+	want := model.VersioningStatusEnabled
+	if !input.Enabled {
+		if status == model.VersioningStatusUnversioned {
+			return &service.BucketVersioningSetterOutput{Status: status}, nil
+		}
+		want = model.VersioningStatusSuspended
+	}
+	if status == want {
+		return &service.BucketVersioningSetterOutput{Status: status}, nil
+	}
+
+	if _, err := s.svc.PutBucketVersioningWithContext(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(input.Bucket.String()),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(string(want)),
+		},
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketVersioningSet, err.Error())
+	}
+	return &service.BucketVersioningSetterOutput{Status: want}, nil
+}
+
+// BucketEncryptionSetterSet is a provider set for BucketEncryptionSetter.
+//
+//nolint:gochecknoglobals
+var BucketEncryptionSetterSet = wire.NewSet(
+	NewS3BucketEncryptionSetter,
+	wire.Bind(new(service.BucketEncryptionSetter), new(*S3BucketEncryptionSetter)),
+)
+
+// S3BucketEncryptionSetter is an implementation for BucketEncryptionSetter.
+type S3BucketEncryptionSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketEncryptionSetter = &S3BucketEncryptionSetter{}
+
+// NewS3BucketEncryptionSetter returns a new S3BucketEncryptionSetter struct.
+func NewS3BucketEncryptionSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketEncryptionSetter {
+	return &S3BucketEncryptionSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketEncryption sets the default encryption of the bucket. The objects that already exist are not re-encrypted.
+func (s *S3BucketEncryptionSetter) SetBucketEncryption(ctx context.Context, input *service.BucketEncryptionSetterInput) (*service.BucketEncryptionSetterOutput, error) {
+	rule := &s3.ServerSideEncryptionRule{
+		ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+			SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+		},
+	}
+	if input.Encryption.KMS() {
+		rule.ApplyServerSideEncryptionByDefault = &s3.ServerSideEncryptionByDefault{
+			SSEAlgorithm:   aws.String(s3.ServerSideEncryptionAwsKms),
+			KMSMasterKeyID: aws.String(input.Encryption.KMSKeyID),
+		}
+		rule.BucketKeyEnabled = aws.Bool(true)
+	}
+
+	if _, err := s.svc.PutBucketEncryptionWithContext(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: aws.String(input.Bucket.String()),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{rule},
+		},
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketEncryptionSet, err.Error())
+	}
+	return &service.BucketEncryptionSetterOutput{}, nil
+}
+
+// BucketLoggingSetterSet is a provider set for BucketLoggingSetter.
+//
+//nolint:gochecknoglobals
+var BucketLoggingSetterSet = wire.NewSet(
+	NewS3BucketLoggingSetter,
+	wire.Bind(new(service.BucketLoggingSetter), new(*S3BucketLoggingSetter)),
+)
+
+// S3BucketLoggingSetter is an implementation for BucketLoggingSetter.
+type S3BucketLoggingSetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketLoggingSetter = &S3BucketLoggingSetter{}
+
+// NewS3BucketLoggingSetter returns a new S3BucketLoggingSetter struct.
+func NewS3BucketLoggingSetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketLoggingSetter {
+	return &S3BucketLoggingSetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// SetBucketLogging sets the server access logging on S3. The logs are delivered to model.S3AccessLogPrefix in the log bucket.
+// If the logging settings are nil, it disables the server access logging.
+func (s *S3BucketLoggingSetter) SetBucketLogging(ctx context.Context, input *service.BucketLoggingSetterInput) (*service.BucketLoggingSetterOutput, error) {
+	status := &s3.BucketLoggingStatus{}
+	if input.Logging != nil {
+		status.LoggingEnabled = &s3.LoggingEnabled{
+			TargetBucket: aws.String(input.Logging.Bucket.String()),
+			TargetPrefix: aws.String(model.S3AccessLogPrefix),
+		}
+	}
+	if _, err := s.svc.PutBucketLoggingWithContext(ctx, &s3.PutBucketLoggingInput{
+		Bucket:              aws.String(input.Bucket.String()),
+		BucketLoggingStatus: status,
+	}); err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketLoggingSet, err.Error())
+	}
+	return &service.BucketLoggingSetterOutput{}, nil
+}
+
+// BucketObjectGetterSet is a provider set for BucketObjectGetter.
+//
+//nolint:gochecknoglobals
+var BucketObjectGetterSet = wire.NewSet(
+	NewS3BucketObjectGetter,
+	wire.Bind(new(service.BucketObjectGetter), new(*S3BucketObjectGetter)),
+)
+
+// S3BucketObjectGetter is an implementation for BucketObjectGetter.
+type S3BucketObjectGetter struct {
+	svc *s3.S3
+}
+
+var _ service.BucketObjectGetter = &S3BucketObjectGetter{}
+
+// NewS3BucketObjectGetter returns a new S3BucketObjectGetter struct.
+func NewS3BucketObjectGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *S3BucketObjectGetter {
+	return &S3BucketObjectGetter{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// GetBucketObject gets the object in the bucket on S3.
+func (s *S3BucketObjectGetter) GetBucketObject(ctx context.Context, input *service.BucketObjectGetterInput) (*service.BucketObjectGetterOutput, error) {
+	output, err := s.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(input.Bucket.String()),
+		Key:    aws.String(input.Key),
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketObjectGet, err.Error())
+	}
+	defer output.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, errfmt.Wrap(service.ErrBucketObjectGet, err.Error())
+	}
+	return &service.BucketObjectGetterOutput{Data: data}, nil
+}
@@ -0,0 +1,223 @@
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// newStorageSession returns a new session of the bucket. If storage is nil, the bucket is in Amazon S3,
+// and it's the same as newS3Session. Otherwise, the requests are sent to the S3-compatible storage
+// with the keys of the profile.
+func newStorageSession(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *session.Session {
+	if storage == nil {
+		return newS3Session(credentials, region, endpoint)
+	}
+	return baseSession(credentials, region.Partition()).Copy(&aws.Config{
+		Region:           aws.String(storage.SigningRegion),
+		Endpoint:         aws.String(storage.URL.String()),
+		S3ForcePathStyle: aws.Bool(storage.PathStyle),
+		HTTPClient:       newStorageHTTPClient(storage),
+	})
+}
+
+// newStorageHTTPClient returns the HTTP client that trusts the CA bundle of the storage in addition to
+// the system pool. If the storage has no TLS settings, it returns nil, and the default client is used.
+func newStorageHTTPClient(storage *model.StorageEndpoint) *http.Client {
+	if len(storage.CABundle) == 0 && !storage.InsecureSkipVerify {
+		return nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AppendCertsFromPEM(storage.CABundle)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            pool,
+		InsecureSkipVerify: storage.InsecureSkipVerify, //nolint:gosec // It's only for testing, and it's opted in by the user.
+	}
+	return &http.Client{Transport: transport}
+}
+
+// newS3Client returns a new S3 client of the bucket. If the storage uses the unsigned payload, the SigV4 signer
+// of the client is replaced, because the S3 client signs the payload by default.
+func newS3Client(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *s3.S3 {
+	svc := s3.New(newStorageSession(credentials, region, endpoint, storage))
+	if storage != nil && storage.UnsignedPayload {
+		svc.Handlers.Sign.Swap(v4.SignRequestHandler.Name, v4.BuildNamedHandler(v4.SignRequestHandler.Name, func(s *v4.Signer) {
+			s.DisableURIPathEscaping = true
+			s.UnsignedPayload = true
+		}))
+	}
+	return svc
+}
+
+// NewCDNFinder returns the CDNFinder of the storage. The S3-compatible storage has no CloudFront,
+// so the bucket itself is the CDN.
+func NewCDNFinder(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) service.CDNFinder {
+	if storage != nil {
+		return NewBucketRootCDNFinder(storage)
+	}
+	return NewCloudFrontCDNFinder(credentials, region, endpoint)
+}
+
+// NewCDNOriginPathUpdater returns the CDNOriginPathUpdater of the storage.
+func NewCDNOriginPathUpdater(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) service.CDNOriginPathUpdater {
+	if storage != nil {
+		return NewBucketRootCDNOriginPathUpdater(credentials, region, endpoint, storage)
+	}
+	return NewCloudFrontCDNOriginPathUpdater(credentials, region, endpoint)
+}
+
+// NewCDNCacheInvalidator returns the CDNCacheInvalidator of the storage.
+func NewCDNCacheInvalidator(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) service.CDNCacheInvalidator {
+	if storage != nil {
+		return &BucketRootCDNCacheInvalidator{}
+	}
+	return NewCloudFrontCDNCacheInvalidator(credentials, region, endpoint)
+}
+
+// NewCDNResponseHeadersGetter returns the CDNResponseHeadersGetter of the storage.
+func NewCDNResponseHeadersGetter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) service.CDNResponseHeadersGetter {
+	if storage != nil {
+		return &BucketRootCDNResponseHeadersGetter{}
+	}
+	return NewCloudFrontCDNResponseHeadersGetter(credentials, region, endpoint)
+}
+
+// BucketRootCDNFinder is an implementation for CDNFinder. The S3-compatible storage serves the bucket.
+type BucketRootCDNFinder struct {
+	storage *model.StorageEndpoint
+}
+
+var _ service.CDNFinder = &BucketRootCDNFinder{}
+
+// NewBucketRootCDNFinder returns a new BucketRootCDNFinder struct.
+func NewBucketRootCDNFinder(storage *model.StorageEndpoint) *BucketRootCDNFinder {
+	return &BucketRootCDNFinder{storage: storage}
+}
+
+// FindCDN returns the domain where the storage serves the bucket. The distribution ID is empty.
+func (b *BucketRootCDNFinder) FindCDN(_ context.Context, input *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	return &service.CDNFinderOutput{
+		Domain: b.storage.SiteDomain(input.BucketName),
+	}, nil
+}
+
+// BucketRootCDNOriginPathUpdater is an implementation for CDNOriginPathUpdater.
+// The storage can not serve a directory of the bucket, so the release is copied to the root of the bucket.
+type BucketRootCDNOriginPathUpdater struct {
+	svc *s3.S3
+}
+
+var _ service.CDNOriginPathUpdater = &BucketRootCDNOriginPathUpdater{}
+
+// NewBucketRootCDNOriginPathUpdater returns a new BucketRootCDNOriginPathUpdater struct.
+func NewBucketRootCDNOriginPathUpdater(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint, storage *model.StorageEndpoint) *BucketRootCDNOriginPathUpdater {
+	return &BucketRootCDNOriginPathUpdater{newS3Client(credentials, region, endpoint, storage)}
+}
+
+// UpdateCDNOriginPath copies the objects under the origin path (e.g. /releases/20231019T120000Z) to the root of
+// the bucket, and then deletes the objects at the root that are not in the release. The releases, the previews and
+// the metadata of spare are never deleted.
+//
+// The switch is not atomic, and neither is the rollback, because 'spare rollback' switches with the same copy.
+// While the objects are copied, the viewers can get the objects of both releases. The assets are copied first and
+// index.html last, so the new index.html never refers to the assets that are not copied yet. If a copy fails,
+// the site is partly switched: the error tells how many objects are copied, and running the same command again
+// completes the switch.
+func (b *BucketRootCDNOriginPathUpdater) UpdateCDNOriginPath(ctx context.Context, input *service.CDNOriginPathUpdaterInput) (*service.CDNOriginPathUpdaterOutput, error) {
+	prefix := strings.TrimPrefix(input.OriginPath, "/") + "/"
+	lister := &S3BucketObjectLister{svc: b.svc}
+	release, err := lister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: input.BucketName,
+		Prefix: prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(release.Objects) == 0 {
+		return nil, errfmt.Wrap(service.ErrBucketObjectCopy, fmt.Sprintf("no object under %s", prefix))
+	}
+
+	keys := make([]string, 0, len(release.Objects))
+	for _, o := range release.Objects {
+		keys = append(keys, strings.TrimPrefix(o.Key, prefix))
+	}
+	model.SortSiteCopyOrder(keys)
+
+	copied := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, err := b.svc.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:            aws.String(input.BucketName.String()),
+			Key:               aws.String(key),
+			CopySource:        aws.String((&url.URL{Path: input.BucketName.String() + "/" + prefix + key}).EscapedPath()),
+			MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+		}); err != nil {
+			if len(copied) == 0 {
+				return nil, errfmt.Wrap(service.ErrBucketObjectCopy, fmt.Sprintf("%s: %s", prefix+key, err.Error()))
+			}
+			return nil, errfmt.Wrap(service.ErrBucketObjectCopy, fmt.Sprintf(
+				"%s: %s. the site is partly switched: %d of %d objects of %s are copied to the root of the bucket. run the same command again to complete the switch",
+				prefix+key, err.Error(), len(copied), len(keys), prefix))
+		}
+		copied[key] = true
+	}
+
+	all, err := lister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stale := make([]string, 0)
+	for _, o := range all.Objects {
+		if model.SiteRootKey(o.Key) && !copied[o.Key] {
+			stale = append(stale, o.Key)
+		}
+	}
+	if _, err := (&S3BucketObjectDeleter{svc: b.svc}).DeleteBucketObjects(ctx, &service.BucketObjectDeleterInput{
+		Bucket: input.BucketName,
+		Keys:   stale,
+	}); err != nil {
+		return nil, err
+	}
+	return &service.CDNOriginPathUpdaterOutput{}, nil
+}
+
+// BucketRootCDNCacheInvalidator is an implementation for CDNCacheInvalidator.
+// The storage has no cache, so there is nothing to invalidate.
+type BucketRootCDNCacheInvalidator struct{}
+
+var _ service.CDNCacheInvalidator = &BucketRootCDNCacheInvalidator{}
+
+// InvalidateCDNCache does nothing.
+func (b *BucketRootCDNCacheInvalidator) InvalidateCDNCache(_ context.Context, _ *service.CDNCacheInvalidatorInput) (*service.CDNCacheInvalidatorOutput, error) {
+	return &service.CDNCacheInvalidatorOutput{}, nil
+}
+
+// BucketRootCDNResponseHeadersGetter is an implementation for CDNResponseHeadersGetter.
+// The storage adds no headers of spare to the responses.
+type BucketRootCDNResponseHeadersGetter struct{}
+
+var _ service.CDNResponseHeadersGetter = &BucketRootCDNResponseHeadersGetter{}
+
+// GetCDNResponseHeaders returns no headers.
+func (b *BucketRootCDNResponseHeadersGetter) GetCDNResponseHeaders(_ context.Context, _ *service.CDNResponseHeadersGetterInput) (*service.CDNResponseHeadersGetterOutput, error) {
+	return &service.CDNResponseHeadersGetterOutput{}, nil
+}
@@ -0,0 +1,157 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ResourceTaggerSet is a provider set for ResourceTagger.
+//
+//nolint:gochecknoglobals
+var ResourceTaggerSet = wire.NewSet(
+	NewResourceGroupsResourceTagger,
+	wire.Bind(new(service.ResourceTagger), new(*ResourceGroupsResourceTagger)),
+)
+
+// ResourceGroupsResourceTagger is an implementation for ResourceTagger.
+type ResourceGroupsResourceTagger struct {
+	// clients is the Resource Groups Tagging API clients by region.
+	clients map[model.Region]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+	// region is the region of the resources whose ARN does not have the region (e.g. S3 buckets).
+	region model.Region
+}
+
+var _ service.ResourceTagger = &ResourceGroupsResourceTagger{}
+
+// NewResourceGroupsResourceTagger returns a new ResourceGroupsResourceTagger struct.
+func NewResourceGroupsResourceTagger(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *ResourceGroupsResourceTagger {
+	return &ResourceGroupsResourceTagger{
+		clients: newTaggingClients(credentials, region, endpoint),
+		region:  region,
+	}
+}
+
+// TagResources adds the tags to the resources. The resources are tagged in the region of each resource.
+func (r *ResourceGroupsResourceTagger) TagResources(ctx context.Context, input *service.ResourceTaggerInput) (*service.ResourceTaggerOutput, error) {
+	tags := make(map[string]*string, len(input.Tags))
+	for k, v := range input.Tags {
+		tags[k] = aws.String(v)
+	}
+
+	byRegion := map[model.Region][]string{}
+	for _, arn := range input.ARNs {
+		region := r.resourceRegion(arn)
+		byRegion[region] = append(byRegion[region], arn)
+	}
+	for region, arns := range byRegion {
+		client, ok := r.clients[region]
+		if !ok {
+			return nil, errfmt.Wrap(service.ErrResourceTag, fmt.Sprintf("resources in %s are not managed by spare: %s", region, strings.Join(arns, ", ")))
+		}
+		output, err := client.TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+			ResourceARNList: aws.StringSlice(arns),
+			Tags:            tags,
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrResourceTag, err.Error())
+		}
+		if len(output.FailedResourcesMap) > 0 {
+			failed := make([]string, 0, len(output.FailedResourcesMap))
+			for arn, info := range output.FailedResourcesMap {
+				failed = append(failed, fmt.Sprintf("%s (%s)", arn, aws.StringValue(info.ErrorMessage)))
+			}
+			sort.Strings(failed)
+			return nil, errfmt.Wrap(service.ErrResourceTag, strings.Join(failed, ", "))
+		}
+	}
+	return &service.ResourceTaggerOutput{}, nil
+}
+
+// resourceRegion returns the region where the tags of the resource are managed.
+func (r *ResourceGroupsResourceTagger) resourceRegion(arn string) model.Region {
+	// arn:partition:service:region:account:resource
+	parts := strings.SplitN(arn, ":", 6) //nolint:gomnd
+	if len(parts) == 6 && parts[3] != "" {
+		return model.Region(parts[3])
+	}
+	if len(parts) == 6 && parts[2] == "cloudfront" {
+		// The tags of CloudFront are managed in the global region of the partition.
+		return r.region.Partition().GlobalRegion()
+	}
+	return r.region
+}
+
+// TaggedResourceListerSet is a provider set for TaggedResourceLister.
+//
+//nolint:gochecknoglobals
+var TaggedResourceListerSet = wire.NewSet(
+	NewResourceGroupsTaggedResourceLister,
+	wire.Bind(new(service.TaggedResourceLister), new(*ResourceGroupsTaggedResourceLister)),
+)
+
+// ResourceGroupsTaggedResourceLister is an implementation for TaggedResourceLister.
+type ResourceGroupsTaggedResourceLister struct {
+	// clients is the Resource Groups Tagging API clients by region.
+	clients map[model.Region]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+}
+
+var _ service.TaggedResourceLister = &ResourceGroupsTaggedResourceLister{}
+
+// NewResourceGroupsTaggedResourceLister returns a new ResourceGroupsTaggedResourceLister struct.
+func NewResourceGroupsTaggedResourceLister(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *ResourceGroupsTaggedResourceLister {
+	return &ResourceGroupsTaggedResourceLister{
+		clients: newTaggingClients(credentials, region, endpoint),
+	}
+}
+
+// ListTaggedResources lists the resources that have the tag key in the region and in the global region
+// of the partition (e.g. us-east-1), because the tags of CloudFront are managed in the global region.
+func (r *ResourceGroupsTaggedResourceLister) ListTaggedResources(ctx context.Context, input *service.TaggedResourceListerInput) (*service.TaggedResourceListerOutput, error) {
+	seen := map[string]bool{}
+	resources := make([]model.TaggedResource, 0)
+	for _, client := range r.clients {
+		err := client.GetResourcesPagesWithContext(ctx, &resourcegroupstaggingapi.GetResourcesInput{
+			TagFilters: []*resourcegroupstaggingapi.TagFilter{{Key: aws.String(input.TagKey)}},
+		}, func(page *resourcegroupstaggingapi.GetResourcesOutput, _ bool) bool {
+			for _, m := range page.ResourceTagMappingList {
+				arn := aws.StringValue(m.ResourceARN)
+				// S3 buckets are listed in every region.
+				if seen[arn] {
+					continue
+				}
+				seen[arn] = true
+				tags := make(model.Tags, len(m.Tags))
+				for _, t := range m.Tags {
+					tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+				}
+				resources = append(resources, model.TaggedResource{ARN: arn, Tags: tags})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(service.ErrTaggedResourceList, err.Error())
+		}
+	}
+	return &service.TaggedResourceListerOutput{Resources: resources}, nil
+}
+
+// newTaggingClients returns the Resource Groups Tagging API clients for the region and the global region of
+// the partition. The global region manages the tags of CloudFront and the web ACLs for CloudFront.
+func newTaggingClients(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) map[model.Region]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI {
+	clients := map[model.Region]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
+		region: resourcegroupstaggingapi.New(newS3Session(credentials, region, endpoint)),
+	}
+	if global := region.Partition().GlobalRegion(); region != global {
+		clients[global] = resourcegroupstaggingapi.New(newS3Session(credentials, global, endpoint))
+	}
+	return clients
+}
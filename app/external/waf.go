@@ -0,0 +1,305 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/wafv2"
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ipSetSuffixes is the list of suffixes of the IP sets that spare creates for the web ACL.
+var ipSetSuffixes = []string{"allow-ipv4", "allow-ipv6", "deny-ipv4", "deny-ipv6"} //nolint:gochecknoglobals
+
+// newWAFv2 returns a new WAFv2 client. The web ACL for CloudFront must be in the global region of
+// the partition that the region belongs to (e.g. us-east-1).
+func newWAFv2(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *wafv2.WAFV2 {
+	return wafv2.New(newS3Session(credentials, region.Partition().GlobalRegion(), endpoint))
+}
+
+// WebACLApplierSet is a provider set for WebACLApplier.
+//
+//nolint:gochecknoglobals
+var WebACLApplierSet = wire.NewSet(
+	NewWAFWebACLApplier,
+	wire.Bind(new(service.WebACLApplier), new(*WAFWebACLApplier)),
+)
+
+// WAFWebACLApplier is an implementation for WebACLApplier.
+type WAFWebACLApplier struct {
+	*wafv2.WAFV2
+}
+
+var _ service.WebACLApplier = &WAFWebACLApplier{}
+
+// NewWAFWebACLApplier returns a new WAFWebACLApplier struct.
+func NewWAFWebACLApplier(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *WAFWebACLApplier {
+	return &WAFWebACLApplier{
+		WAFV2: newWAFv2(credentials, region, endpoint),
+	}
+}
+
+// ApplyWebACL creates (or updates) the IP sets and the web ACL for the bucket.
+// The rules are evaluated in the order: allow list, deny list, geo blocking, rate limit and managed rule groups.
+func (w *WAFWebACLApplier) ApplyWebACL(ctx context.Context, input *service.WebACLApplierInput) (*service.WebACLApplierOutput, error) {
+	addresses := map[string][]string{
+		"allow-ipv4": model.IPv4(input.WAF.AllowIPs),
+		"allow-ipv6": model.IPv6(input.WAF.AllowIPs),
+		"deny-ipv4":  model.IPv4(input.WAF.DenyIPs),
+		"deny-ipv6":  model.IPv6(input.WAF.DenyIPs),
+	}
+	ipSetARNs := make(map[string]string, len(ipSetSuffixes))
+	for _, suffix := range ipSetSuffixes {
+		arn, err := w.upsertIPSet(ctx, model.NewWAFResourceName(input.BucketName, suffix), suffix, addresses[suffix])
+		if err != nil {
+			return nil, err
+		}
+		ipSetARNs[suffix] = arn
+	}
+
+	rules := make([]*wafv2.Rule, 0, len(ipSetSuffixes)+len(input.WAF.ManagedRules)+2) //nolint:gomnd
+	for _, suffix := range ipSetSuffixes {
+		if len(addresses[suffix]) == 0 {
+			continue
+		}
+		action := &wafv2.RuleAction{Block: &wafv2.BlockAction{}}
+		if strings.HasPrefix(suffix, "allow") {
+			action = &wafv2.RuleAction{Allow: &wafv2.AllowAction{}}
+		}
+		rules = append(rules, newWAFRule(suffix, len(rules), action, &wafv2.Statement{
+			IPSetReferenceStatement: &wafv2.IPSetReferenceStatement{ARN: aws.String(ipSetARNs[suffix])},
+		}))
+	}
+	if len(input.WAF.BlockCountries) > 0 {
+		rules = append(rules, newWAFRule("geo-block", len(rules), &wafv2.RuleAction{Block: &wafv2.BlockAction{}}, &wafv2.Statement{
+			GeoMatchStatement: &wafv2.GeoMatchStatement{CountryCodes: aws.StringSlice(input.WAF.BlockCountries)},
+		}))
+	}
+	if input.WAF.RateLimit > 0 {
+		rules = append(rules, newWAFRule("rate-limit", len(rules), &wafv2.RuleAction{Block: &wafv2.BlockAction{}}, &wafv2.Statement{
+			RateBasedStatement: &wafv2.RateBasedStatement{
+				AggregateKeyType: aws.String(wafv2.RateBasedStatementAggregateKeyTypeIp),
+				Limit:            aws.Int64(input.WAF.RateLimit),
+			},
+		}))
+	}
+	for _, name := range input.WAF.ManagedRules {
+		rule := newWAFRule(name, len(rules), nil, &wafv2.Statement{
+			ManagedRuleGroupStatement: &wafv2.ManagedRuleGroupStatement{
+				VendorName: aws.String("AWS"),
+				Name:       aws.String(input.WAF.ManagedRuleGroupName(name)),
+			},
+		})
+		rule.OverrideAction = &wafv2.OverrideAction{None: &wafv2.NoneAction{}}
+		rules = append(rules, rule)
+	}
+
+	name := model.NewWAFResourceName(input.BucketName, "")
+	summary, err := findWebACL(ctx, w.WAFV2, name)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		output, err := w.CreateWebACLWithContext(ctx, &wafv2.CreateWebACLInput{
+			Name:             aws.String(name),
+			Scope:            aws.String(wafv2.ScopeCloudfront),
+			Description:      aws.String("Web ACL generated by spare"),
+			DefaultAction:    &wafv2.DefaultAction{Allow: &wafv2.AllowAction{}},
+			Rules:            rules,
+			VisibilityConfig: newVisibilityConfig(name),
+		})
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to create a web ACL")
+		}
+		return &service.WebACLApplierOutput{ARN: aws.StringValue(output.Summary.ARN)}, nil
+	}
+
+	if _, err := w.UpdateWebACLWithContext(ctx, &wafv2.UpdateWebACLInput{
+		Name:             summary.Name,
+		Id:               summary.Id,
+		LockToken:        summary.LockToken,
+		Scope:            aws.String(wafv2.ScopeCloudfront),
+		Description:      aws.String("Web ACL generated by spare"),
+		DefaultAction:    &wafv2.DefaultAction{Allow: &wafv2.AllowAction{}},
+		Rules:            rules,
+		VisibilityConfig: newVisibilityConfig(name),
+	}); err != nil {
+		return nil, errfmt.Wrap(err, "failed to update a web ACL")
+	}
+	return &service.WebACLApplierOutput{ARN: aws.StringValue(summary.ARN)}, nil
+}
+
+// upsertIPSet creates the IP set. If the IP set already exists, it updates the addresses. It returns the ARN of the IP set.
+func (w *WAFWebACLApplier) upsertIPSet(ctx context.Context, name, suffix string, addresses []string) (string, error) {
+	summary, err := findIPSet(ctx, w.WAFV2, name)
+	if err != nil {
+		return "", err
+	}
+	if summary == nil {
+		version := wafv2.IPAddressVersionIpv4
+		if strings.HasSuffix(suffix, "ipv6") {
+			version = wafv2.IPAddressVersionIpv6
+		}
+		output, err := w.CreateIPSetWithContext(ctx, &wafv2.CreateIPSetInput{
+			Name:             aws.String(name),
+			Scope:            aws.String(wafv2.ScopeCloudfront),
+			IPAddressVersion: aws.String(version),
+			Addresses:        aws.StringSlice(addresses),
+			Description:      aws.String("IP set generated by spare"),
+		})
+		if err != nil {
+			return "", errfmt.Wrap(err, "failed to create an IP set")
+		}
+		return aws.StringValue(output.Summary.ARN), nil
+	}
+
+	if _, err := w.UpdateIPSetWithContext(ctx, &wafv2.UpdateIPSetInput{
+		Name:        summary.Name,
+		Id:          summary.Id,
+		LockToken:   summary.LockToken,
+		Scope:       aws.String(wafv2.ScopeCloudfront),
+		Addresses:   aws.StringSlice(addresses),
+		Description: aws.String("IP set generated by spare"),
+	}); err != nil {
+		return "", errfmt.Wrap(err, "failed to update an IP set")
+	}
+	return aws.StringValue(summary.ARN), nil
+}
+
+// newWAFRule returns the rule of the web ACL. The action is nil for the rule group.
+func newWAFRule(name string, priority int, action *wafv2.RuleAction, statement *wafv2.Statement) *wafv2.Rule {
+	return &wafv2.Rule{
+		Name:             aws.String(name),
+		Priority:         aws.Int64(int64(priority)),
+		Action:           action,
+		Statement:        statement,
+		VisibilityConfig: newVisibilityConfig(name),
+	}
+}
+
+// newVisibilityConfig returns the config of the CloudWatch metrics and the sampled requests.
+func newVisibilityConfig(metricName string) *wafv2.VisibilityConfig {
+	return &wafv2.VisibilityConfig{
+		CloudWatchMetricsEnabled: aws.Bool(true),
+		SampledRequestsEnabled:   aws.Bool(true),
+		MetricName:               aws.String(metricName),
+	}
+}
+
+// findWebACL returns the summary of the web ACL whose name is name. If not found, it returns nil.
+func findWebACL(ctx context.Context, svc *wafv2.WAFV2, name string) (*wafv2.WebACLSummary, error) {
+	input := &wafv2.ListWebACLsInput{
+		Scope: aws.String(wafv2.ScopeCloudfront),
+	}
+	for {
+		output, err := svc.ListWebACLsWithContext(ctx, input)
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to list web ACLs")
+		}
+		for _, summary := range output.WebACLs {
+			if aws.StringValue(summary.Name) == name {
+				return summary, nil
+			}
+		}
+		if aws.StringValue(output.NextMarker) == "" || len(output.WebACLs) == 0 {
+			return nil, nil
+		}
+		input.NextMarker = output.NextMarker
+	}
+}
+
+// findIPSet returns the summary of the IP set whose name is name. If not found, it returns nil.
+func findIPSet(ctx context.Context, svc *wafv2.WAFV2, name string) (*wafv2.IPSetSummary, error) {
+	input := &wafv2.ListIPSetsInput{
+		Scope: aws.String(wafv2.ScopeCloudfront),
+	}
+	for {
+		output, err := svc.ListIPSetsWithContext(ctx, input)
+		if err != nil {
+			return nil, errfmt.Wrap(err, "failed to list IP sets")
+		}
+		for _, summary := range output.IPSets {
+			if aws.StringValue(summary.Name) == name {
+				return summary, nil
+			}
+		}
+		if aws.StringValue(output.NextMarker) == "" || len(output.IPSets) == 0 {
+			return nil, nil
+		}
+		input.NextMarker = output.NextMarker
+	}
+}
+
+// WebACLDeleterSet is a provider set for WebACLDeleter.
+//
+//nolint:gochecknoglobals
+var WebACLDeleterSet = wire.NewSet(
+	NewWAFWebACLDeleter,
+	wire.Bind(new(service.WebACLDeleter), new(*WAFWebACLDeleter)),
+)
+
+// WAFWebACLDeleter is an implementation for WebACLDeleter.
+type WAFWebACLDeleter struct {
+	*wafv2.WAFV2
+}
+
+var _ service.WebACLDeleter = &WAFWebACLDeleter{}
+
+// NewWAFWebACLDeleter returns a new WAFWebACLDeleter struct.
+func NewWAFWebACLDeleter(credentials *model.AWSCredentials, region model.Region, endpoint *model.Endpoint) *WAFWebACLDeleter {
+	return &WAFWebACLDeleter{
+		WAFV2: newWAFv2(credentials, region, endpoint),
+	}
+}
+
+// DeleteWebACL deletes the web ACL and the IP sets for the bucket.
+// CloudFront may take a few minutes to release the web ACL after it's disassociated. In that case,
+// it returns Deleted=false without error, and the web ACL is deleted by the next call.
+func (w *WAFWebACLDeleter) DeleteWebACL(ctx context.Context, input *service.WebACLDeleterInput) (*service.WebACLDeleterOutput, error) {
+	deleted := false
+	summary, err := findWebACL(ctx, w.WAFV2, model.NewWAFResourceName(input.BucketName, ""))
+	if err != nil {
+		return nil, err
+	}
+	if summary != nil {
+		if _, err := w.DeleteWebACLWithContext(ctx, &wafv2.DeleteWebACLInput{
+			Name:      summary.Name,
+			Id:        summary.Id,
+			LockToken: summary.LockToken,
+			Scope:     aws.String(wafv2.ScopeCloudfront),
+		}); err != nil {
+			var awsErr awserr.Error
+			if errors.As(err, &awsErr) && awsErr.Code() == wafv2.ErrCodeWAFAssociatedItemException {
+				return &service.WebACLDeleterOutput{Deleted: false}, nil
+			}
+			return nil, errfmt.Wrap(err, "failed to delete a web ACL")
+		}
+		deleted = true
+	}
+
+	for _, suffix := range ipSetSuffixes {
+		summary, err := findIPSet(ctx, w.WAFV2, model.NewWAFResourceName(input.BucketName, suffix))
+		if err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			continue
+		}
+		if _, err := w.DeleteIPSetWithContext(ctx, &wafv2.DeleteIPSetInput{
+			Name:      summary.Name,
+			Id:        summary.Id,
+			LockToken: summary.LockToken,
+			Scope:     aws.String(wafv2.ScopeCloudfront),
+		}); err != nil {
+			return nil, errfmt.Wrap(err, fmt.Sprintf("failed to delete the IP set %s", aws.StringValue(summary.Name)))
+		}
+	}
+	return &service.WebACLDeleterOutput{Deleted: deleted}, nil
+}
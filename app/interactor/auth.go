@@ -0,0 +1,70 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// AuthRotatorSet is a provider set for AuthRotator.
+//
+//nolint:gochecknoglobals
+var AuthRotatorSet = wire.NewSet(
+	NewAuthRotator,
+	wire.Struct(new(AuthRotatorOptions), "*"),
+	wire.Bind(new(usecase.AuthRotator), new(*AuthRotator)),
+)
+
+var _ usecase.AuthRotator = (*AuthRotator)(nil)
+
+// AuthRotator is an implementation for AuthRotator.
+type AuthRotator struct {
+	opts *AuthRotatorOptions
+}
+
+// AuthRotatorOptions is an option struct for AuthRotator.
+type AuthRotatorOptions struct {
+	service.CDNFinder
+	service.ViewerRulesGetter
+	*ViewerFunctionOptions
+}
+
+// NewAuthRotator returns a new AuthRotator struct.
+func NewAuthRotator(opts *AuthRotatorOptions) *AuthRotator {
+	return &AuthRotator{
+		opts: opts,
+	}
+}
+
+// RotateAuth publishes the viewer request function that contains the new credentials.
+// The other rules are read from the bucket, so the live redirect rules and pretty URLs are kept
+// even if the local deploy target has been changed since the last deploy.
+// The function name is not changed, so CloudFront serves the new credentials without updating the cache behaviors.
+func (a *AuthRotator) RotateAuth(ctx context.Context, input *usecase.RotateAuthInput) (*usecase.RotateAuthOutput, error) {
+	cdn, err := a.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules, err := a.opts.ViewerRulesGetter.GetViewerRules(ctx, &service.ViewerRulesGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rules.Rules == nil {
+		return nil, errfmt.Wrap(model.ErrViewerRulesNotFound, "run 'spare build' or 'spare deploy' first")
+	}
+
+	if err := a.opts.applyViewerRequest(ctx, cdn.DistributionID, input.BucketName, rules.Rules.ViewerRequest(input.BasicAuth)); err != nil {
+		return nil, err
+	}
+	return &usecase.RotateAuthOutput{
+		Domain: cdn.Domain,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// fakeViewerFunction is the CDN and the viewer request function state in memory.
+type fakeViewerFunction struct {
+	published map[model.CDNFunctionName]string
+	rules     *model.ViewerRules
+}
+
+func (f *fakeViewerFunction) FindCDN(_ context.Context, _ *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	return &service.CDNFinderOutput{DistributionID: fakePrimaryID, Domain: "primary.cloudfront.net"}, nil
+}
+
+func (f *fakeViewerFunction) PublishCDNFunction(_ context.Context, input *service.CDNFunctionPublisherInput) (*service.CDNFunctionPublisherOutput, error) {
+	f.published[input.Function.Name] = input.Function.Code
+	return &service.CDNFunctionPublisherOutput{ARN: "arn:" + input.Function.Name.String()}, nil
+}
+
+func (f *fakeViewerFunction) AssociateCDNViewerFunction(_ context.Context, _ *service.CDNViewerFunctionAssociatorInput) (*service.CDNViewerFunctionAssociatorOutput, error) {
+	return &service.CDNViewerFunctionAssociatorOutput{}, nil
+}
+
+func (f *fakeViewerFunction) GetMaintenance(_ context.Context, _ *service.MaintenanceGetterInput) (*service.MaintenanceGetterOutput, error) {
+	return &service.MaintenanceGetterOutput{}, nil
+}
+
+func (f *fakeViewerFunction) GetViewerRules(_ context.Context, _ *service.ViewerRulesGetterInput) (*service.ViewerRulesGetterOutput, error) {
+	return &service.ViewerRulesGetterOutput{Rules: f.rules}, nil
+}
+
+func (f *fakeViewerFunction) PutViewerRules(_ context.Context, input *service.ViewerRulesPutterInput) (*service.ViewerRulesPutterOutput, error) {
+	f.rules = input.Rules
+	return &service.ViewerRulesPutterOutput{}, nil
+}
+
+func TestAuthRotatorRotateAuth(t *testing.T) {
+	t.Parallel()
+
+	bucket := model.BucketName("spare-bucket")
+	basicAuth := &model.BasicAuth{
+		Realm: "spare",
+		Users: []model.BasicAuthUser{model.NewBasicAuthUser("alice", "new-password")},
+	}
+	liveRules := &model.ViewerRules{
+		Redirects:  model.Redirects{{From: "/old", To: "/new", Status: 301}},
+		PrettyURLs: &model.PrettyURLs{TrailingSlash: model.TrailingSlashIgnore},
+	}
+
+	tests := []struct {
+		name    string
+		rules   *model.ViewerRules
+		wantErr error
+	}{
+		{
+			name:  "publish the new credentials with the live rules",
+			rules: liveRules,
+		},
+		{
+			name:  "publish the new credentials with no rules",
+			rules: &model.ViewerRules{},
+		},
+		{
+			name:    "rules are not recorded",
+			rules:   nil,
+			wantErr: model.ErrViewerRulesNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeViewerFunction{
+				published: map[model.CDNFunctionName]string{},
+				rules:     tt.rules,
+			}
+			rotator := NewAuthRotator(&AuthRotatorOptions{
+				CDNFinder:         f,
+				ViewerRulesGetter: f,
+				ViewerFunctionOptions: &ViewerFunctionOptions{
+					CDNFunctionPublisher:        f,
+					CDNViewerFunctionAssociator: f,
+					MaintenanceGetter:           f,
+					ViewerRulesPutter:           f,
+				},
+			})
+
+			output, err := rotator.RotateAuth(context.Background(), &usecase.RotateAuthInput{
+				BucketName: bucket,
+				BasicAuth:  basicAuth,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RotateAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(f.published) != 0 {
+					t.Errorf("RotateAuth() published functions %v, want none", f.published)
+				}
+				return
+			}
+			if output.Domain != "primary.cloudfront.net" {
+				t.Errorf("RotateAuth() domain = %s, want primary.cloudfront.net", output.Domain)
+			}
+
+			want, err := model.NewViewerRequestFunction(bucket, tt.rules.ViewerRequest(basicAuth))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want.Code, f.published[want.Name]); diff != "" {
+				t.Errorf("RotateAuth() viewer request function mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.rules, f.rules); diff != "" {
+				t.Errorf("RotateAuth() recorded rules mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// CanaryDeployerSet is a provider set for CanaryDeployer.
+//
+//nolint:gochecknoglobals
+var CanaryDeployerSet = wire.NewSet(
+	NewCanaryDeployer,
+	wire.Struct(new(CanaryDeployerOptions), "*"),
+	wire.Bind(new(usecase.CanaryDeployer), new(*CanaryDeployer)),
+)
+
+var _ usecase.CanaryDeployer = (*CanaryDeployer)(nil)
+
+// CanaryDeployer is an implementation for CanaryDeployer.
+type CanaryDeployer struct {
+	opts *CanaryDeployerOptions
+}
+
+// CanaryDeployerOptions is an option struct for CanaryDeployer.
+type CanaryDeployerOptions struct {
+	service.ReleaseHistoryGetter
+	service.ReleaseHistoryPutter
+	service.CDNFinder
+	service.CDNStagingCreator
+	service.CDNOriginPathUpdater
+	service.CDNCacheInvalidator
+	service.CDNContinuousDeploymentPolicySetter
+	*OriginAccessOptions
+}
+
+// NewCanaryDeployer returns a new CanaryDeployer struct.
+func NewCanaryDeployer(opts *CanaryDeployerOptions) *CanaryDeployer {
+	return &CanaryDeployer{
+		opts: opts,
+	}
+}
+
+// DeployCanary switches the staging CDN to the release and routes a part of the viewers to it.
+// The staging CDN is created as a copy of the primary CDN at the first canary release, and its config
+// is synced with the primary CDN at the next canary releases.
+// The live release is not changed until the canary release is promoted.
+func (c *CanaryDeployer) DeployCanary(ctx context.Context, input *usecase.DeployCanaryInput) (*usecase.DeployCanaryOutput, error) {
+	output, err := c.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := output.History
+	history.Add(*input.Release)
+	if err := history.Stage(input.Release.ID); err != nil {
+		return nil, err
+	}
+
+	primary, err := c.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	staging, err := c.opts.CDNStagingCreator.CreateCDNStaging(ctx, &service.CDNStagingCreatorInput{
+		DistributionID: primary.DistributionID,
+		BucketName:     input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The staging CDN created at the first canary release reads the objects through the same OAC.
+	if err := c.opts.restrictOriginAccess(ctx, input.BucketName, input.Encryption); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.opts.CDNOriginPathUpdater.UpdateCDNOriginPath(ctx, &service.CDNOriginPathUpdaterInput{
+		DistributionID: staging.DistributionID,
+		BucketName:     input.BucketName,
+		OriginPath:     input.Release.ID.OriginPath(),
+	}); err != nil {
+		return nil, err
+	}
+
+	// The staging CDN may cache the previous canary release.
+	if _, err := c.opts.CDNCacheInvalidator.InvalidateCDNCache(ctx, &service.CDNCacheInvalidatorInput{
+		DistributionID: staging.DistributionID,
+		Paths:          []string{"/*"},
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.opts.CDNContinuousDeploymentPolicySetter.SetCDNContinuousDeploymentPolicy(ctx, &service.CDNContinuousDeploymentPolicySetterInput{
+		DistributionID: primary.DistributionID,
+		StagingDomain:  staging.Domain,
+		Traffic:        input.Traffic,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.opts.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+		Bucket:  input.BucketName,
+		History: history,
+	}); err != nil {
+		return nil, err
+	}
+	return &usecase.DeployCanaryOutput{
+		StagingDomain: staging.Domain,
+	}, nil
+}
+
+// CanaryPromoterSet is a provider set for CanaryPromoter.
+//
+//nolint:gochecknoglobals
+var CanaryPromoterSet = wire.NewSet(
+	NewCanaryPromoter,
+	wire.Struct(new(CanaryPromoterOptions), "*"),
+	wire.Bind(new(usecase.CanaryPromoter), new(*CanaryPromoter)),
+)
+
+var _ usecase.CanaryPromoter = (*CanaryPromoter)(nil)
+
+// CanaryPromoter is an implementation for CanaryPromoter.
+type CanaryPromoter struct {
+	opts *CanaryPromoterOptions
+}
+
+// CanaryPromoterOptions is an option struct for CanaryPromoter.
+type CanaryPromoterOptions struct {
+	service.ReleaseHistoryGetter
+	service.ReleaseHistoryPutter
+	service.CDNFinder
+	service.CDNStagingPromoter
+	service.CDNCacheInvalidator
+	service.CDNContinuousDeploymentPolicyDisabler
+}
+
+// NewCanaryPromoter returns a new CanaryPromoter struct.
+func NewCanaryPromoter(opts *CanaryPromoterOptions) *CanaryPromoter {
+	return &CanaryPromoter{
+		opts: opts,
+	}
+}
+
+// PromoteCanary copies the config of the staging CDN to the primary CDN and stops routing the viewers to the staging CDN.
+// The primary CDN gets the origin path of the canary release and the config that the canary viewers have tested,
+// even if the config of the primary CDN was changed by 'spare build' after the canary release was deployed
+// ('spare build' syncs the staging CDN, too). The primary CDN is switched first, so the viewers never go back to the old release.
+func (c *CanaryPromoter) PromoteCanary(ctx context.Context, input *usecase.PromoteCanaryInput) (*usecase.PromoteCanaryOutput, error) {
+	output, err := c.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := output.History
+	release, err := history.Unstage()
+	if err != nil {
+		return nil, err
+	}
+	if err := history.Activate(release.ID); err != nil {
+		return nil, err
+	}
+
+	cdn, err := c.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.opts.CDNStagingPromoter.PromoteCDNStaging(ctx, &service.CDNStagingPromoterInput{
+		DistributionID: cdn.DistributionID,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := c.opts.CDNContinuousDeploymentPolicyDisabler.DisableCDNContinuousDeploymentPolicy(ctx, &service.CDNContinuousDeploymentPolicyDisablerInput{
+		DistributionID: cdn.DistributionID,
+	}); err != nil {
+		return nil, err
+	}
+
+	// The primary CDN caches the old release by the viewer path.
+	if _, err := c.opts.CDNCacheInvalidator.InvalidateCDNCache(ctx, &service.CDNCacheInvalidatorInput{
+		DistributionID: cdn.DistributionID,
+		Paths:          []string{"/*"},
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.opts.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+		Bucket:  input.BucketName,
+		History: history,
+	}); err != nil {
+		return nil, err
+	}
+	return &usecase.PromoteCanaryOutput{
+		Release: release,
+		Domain:  cdn.Domain,
+	}, nil
+}
+
+// CanaryAborterSet is a provider set for CanaryAborter.
+//
+//nolint:gochecknoglobals
+var CanaryAborterSet = wire.NewSet(
+	NewCanaryAborter,
+	wire.Struct(new(CanaryAborterOptions), "*"),
+	wire.Bind(new(usecase.CanaryAborter), new(*CanaryAborter)),
+)
+
+var _ usecase.CanaryAborter = (*CanaryAborter)(nil)
+
+// CanaryAborter is an implementation for CanaryAborter.
+type CanaryAborter struct {
+	opts *CanaryAborterOptions
+}
+
+// CanaryAborterOptions is an option struct for CanaryAborter.
+type CanaryAborterOptions struct {
+	service.ReleaseHistoryGetter
+	service.ReleaseHistoryPutter
+	service.CDNFinder
+	service.CDNContinuousDeploymentPolicyDisabler
+}
+
+// NewCanaryAborter returns a new CanaryAborter struct.
+func NewCanaryAborter(opts *CanaryAborterOptions) *CanaryAborter {
+	return &CanaryAborter{
+		opts: opts,
+	}
+}
+
+// AbortCanary stops routing the viewers to the staging CDN. The live release is not changed.
+// The aborted release stays in the release history, so 'spare gc' deletes it later,
+// but it's marked as aborted, so 'spare rollback' never switches to it by default.
+func (c *CanaryAborter) AbortCanary(ctx context.Context, input *usecase.AbortCanaryInput) (*usecase.AbortCanaryOutput, error) {
+	output, err := c.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := output.History
+	release, err := history.Abort()
+	if err != nil {
+		return nil, err
+	}
+
+	cdn, err := c.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.opts.CDNContinuousDeploymentPolicyDisabler.DisableCDNContinuousDeploymentPolicy(ctx, &service.CDNContinuousDeploymentPolicyDisablerInput{
+		DistributionID: cdn.DistributionID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.opts.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+		Bucket:  input.BucketName,
+		History: history,
+	}); err != nil {
+		return nil, err
+	}
+	return &usecase.AbortCanaryOutput{
+		Release: release,
+	}, nil
+}
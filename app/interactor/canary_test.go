@@ -0,0 +1,215 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+const (
+	fakePrimaryID model.DistributionID = "EPRIMARY"
+	fakeStagingID model.DistributionID = "ESTAGING"
+)
+
+// fakeDistributionConfig is the part of the distribution config that the canary release changes.
+type fakeDistributionConfig struct {
+	OriginPath    string
+	CachePolicyID string
+}
+
+// fakeContinuousDeployment is the primary distribution and the staging distribution in memory.
+type fakeContinuousDeployment struct {
+	primary       fakeDistributionConfig
+	staging       *fakeDistributionConfig
+	policyEnabled bool
+	// bucketPolicyARNs and keyPolicyARNs are the distributions that the bucket policy and the key policy allow.
+	bucketPolicyARNs []string
+	keyPolicyARNs    []string
+}
+
+func (f *fakeContinuousDeployment) FindCDN(_ context.Context, _ *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	return &service.CDNFinderOutput{DistributionID: fakePrimaryID, Domain: "primary.cloudfront.net"}, nil
+}
+
+func (f *fakeContinuousDeployment) CreateCDNStaging(ctx context.Context, input *service.CDNStagingCreatorInput) (*service.CDNStagingCreatorOutput, error) {
+	if f.staging == nil {
+		staging := f.primary
+		f.staging = &staging
+	} else if _, err := f.SyncCDNStaging(ctx, &service.CDNStagingSyncerInput{DistributionID: input.DistributionID}); err != nil {
+		return nil, err
+	}
+	return &service.CDNStagingCreatorOutput{DistributionID: fakeStagingID, Domain: "staging.cloudfront.net"}, nil
+}
+
+func (f *fakeContinuousDeployment) SyncCDNStaging(_ context.Context, _ *service.CDNStagingSyncerInput) (*service.CDNStagingSyncerOutput, error) {
+	if f.staging == nil {
+		return &service.CDNStagingSyncerOutput{}, nil
+	}
+	staging := f.primary
+	staging.OriginPath = f.staging.OriginPath
+	f.staging = &staging
+	return &service.CDNStagingSyncerOutput{Synced: true}, nil
+}
+
+func (f *fakeContinuousDeployment) PromoteCDNStaging(_ context.Context, _ *service.CDNStagingPromoterInput) (*service.CDNStagingPromoterOutput, error) {
+	if f.staging == nil {
+		return nil, service.ErrCDNStagingNotFound
+	}
+	f.primary = *f.staging
+	return &service.CDNStagingPromoterOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) UpdateCDNOriginPath(_ context.Context, input *service.CDNOriginPathUpdaterInput) (*service.CDNOriginPathUpdaterOutput, error) {
+	if input.DistributionID == fakeStagingID {
+		f.staging.OriginPath = input.OriginPath
+	} else {
+		f.primary.OriginPath = input.OriginPath
+	}
+	return &service.CDNOriginPathUpdaterOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) InvalidateCDNCache(_ context.Context, _ *service.CDNCacheInvalidatorInput) (*service.CDNCacheInvalidatorOutput, error) {
+	return &service.CDNCacheInvalidatorOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) SetCDNContinuousDeploymentPolicy(_ context.Context, _ *service.CDNContinuousDeploymentPolicySetterInput) (*service.CDNContinuousDeploymentPolicySetterOutput, error) {
+	f.policyEnabled = true
+	return &service.CDNContinuousDeploymentPolicySetterOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) DisableCDNContinuousDeploymentPolicy(_ context.Context, _ *service.CDNContinuousDeploymentPolicyDisablerInput) (*service.CDNContinuousDeploymentPolicyDisablerOutput, error) {
+	f.policyEnabled = false
+	return &service.CDNContinuousDeploymentPolicyDisablerOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) ListCDNARNs(_ context.Context, _ *service.CDNARNsListerInput) (*service.CDNARNsListerOutput, error) {
+	arns := []string{"arn:aws:cloudfront::123456789012:distribution/" + fakePrimaryID.String()}
+	if f.staging != nil {
+		arns = append(arns, "arn:aws:cloudfront::123456789012:distribution/"+fakeStagingID.String())
+	}
+	return &service.CDNARNsListerOutput{ARNs: arns}, nil
+}
+
+func (f *fakeContinuousDeployment) SetBucketPolicy(_ context.Context, input *service.BucketPolicySetterInput) (*service.BucketPolicySetterOutput, error) {
+	f.bucketPolicyARNs = input.Policy.Statement[0].Condition["StringEquals"]["AWS:SourceArn"].([]string)
+	return &service.BucketPolicySetterOutput{}, nil
+}
+
+func (f *fakeContinuousDeployment) ApplyEncryptionKeyPolicy(_ context.Context, input *service.EncryptionKeyPolicyApplierInput) (*service.EncryptionKeyPolicyApplierOutput, error) {
+	f.keyPolicyARNs = input.Statement.Condition["StringEquals"]["AWS:SourceArn"].([]string)
+	return &service.EncryptionKeyPolicyApplierOutput{}, nil
+}
+
+func TestCanaryPromoterPromoteCanary(t *testing.T) {
+	t.Parallel()
+
+	live := model.NewRelease(time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC), "abc123", "alice")
+	canary := model.NewRelease(time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC), "def456", "alice")
+	tests := []struct {
+		name string
+		// build is the config that 'spare build' applies after the canary release is deployed. If it's empty, build is not run.
+		build       string
+		wantPrimary fakeDistributionConfig
+	}{
+		{
+			name:        "success",
+			build:       "",
+			wantPrimary: fakeDistributionConfig{OriginPath: canary.ID.OriginPath(), CachePolicyID: "v1"},
+		},
+		{
+			name:        "success. promote after the config is changed by build",
+			build:       "v2",
+			wantPrimary: fakeDistributionConfig{OriginPath: canary.ID.OriginPath(), CachePolicyID: "v2"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			history := model.NewReleaseHistory()
+			history.Add(*live)
+			history.Live = live.ID
+			store := &fakeReleaseHistoryStore{history: history}
+			cdn := &fakeContinuousDeployment{
+				primary: fakeDistributionConfig{OriginPath: live.ID.OriginPath(), CachePolicyID: "v1"},
+			}
+
+			deployer := NewCanaryDeployer(&CanaryDeployerOptions{
+				ReleaseHistoryGetter:                store,
+				ReleaseHistoryPutter:                store,
+				CDNFinder:                           cdn,
+				CDNStagingCreator:                   cdn,
+				CDNOriginPathUpdater:                cdn,
+				CDNCacheInvalidator:                 cdn,
+				CDNContinuousDeploymentPolicySetter: cdn,
+				OriginAccessOptions: &OriginAccessOptions{
+					CDNARNsLister:              cdn,
+					BucketPolicySetter:         cdn,
+					EncryptionKeyPolicyApplier: cdn,
+				},
+			})
+			if _, err := deployer.DeployCanary(ctx, &usecase.DeployCanaryInput{
+				BucketName: "spare-bucket",
+				Release:    canary,
+				Traffic:    model.NewWeightCanaryTraffic(5),
+				Encryption: &model.Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyID: "alias/spare-spare-bucket"},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			// The staging distribution reads the objects through OAC, so the policies must allow it, too.
+			wantARNs := []string{
+				"arn:aws:cloudfront::123456789012:distribution/" + fakePrimaryID.String(),
+				"arn:aws:cloudfront::123456789012:distribution/" + fakeStagingID.String(),
+			}
+			if diff := cmp.Diff(wantARNs, cdn.bucketPolicyARNs); diff != "" {
+				t.Errorf("bucket policy source ARNs are mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantARNs, cdn.keyPolicyARNs); diff != "" {
+				t.Errorf("key policy source ARNs are mismatch (-want +got):\n%s", diff)
+			}
+
+			if tt.build != "" {
+				// 'spare build' changes the primary distribution and syncs the staging distribution.
+				cdn.primary.CachePolicyID = tt.build
+				if _, err := cdn.SyncCDNStaging(ctx, &service.CDNStagingSyncerInput{DistributionID: fakePrimaryID}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			promoter := NewCanaryPromoter(&CanaryPromoterOptions{
+				ReleaseHistoryGetter:                  store,
+				ReleaseHistoryPutter:                  store,
+				CDNFinder:                             cdn,
+				CDNStagingPromoter:                    cdn,
+				CDNCacheInvalidator:                   cdn,
+				CDNContinuousDeploymentPolicyDisabler: cdn,
+			})
+			output, err := promoter.PromoteCanary(ctx, &usecase.PromoteCanaryInput{
+				BucketName: "spare-bucket",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if output.Release.ID != canary.ID {
+				t.Errorf("promoted release = %s, want %s", output.Release.ID, canary.ID)
+			}
+			if diff := cmp.Diff(tt.wantPrimary, cdn.primary); diff != "" {
+				t.Errorf("primary distribution is mismatch (-want +got):\n%s", diff)
+			}
+			if cdn.policyEnabled {
+				t.Error("continuous deployment policy is still enabled")
+			}
+			if store.history.Live != canary.ID || !store.history.Staged.Empty() {
+				t.Errorf("release history live = %s, staged = %s, want live = %s", store.history.Live, store.history.Staged, canary.ID)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package interactor
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
 	"github.com/nao1215/spare/app/domain/service"
 	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
 )
 
 // CDNCreatorSet is a set of CDNCreator.
@@ -27,7 +30,20 @@ type CDNCreator struct {
 // CDNCreatorOptions is an option struct for CDNCreator.
 type CDNCreatorOptions struct {
 	service.CDNCreator
-	service.OAICreator
+	service.CDNFinder
+	service.CDNCacheBehaviorApplier
+	service.KeyGroupGetter
+	service.CDNCustomOriginApplier
+	service.CDNResponseHeadersPolicyApplier
+	service.WebACLApplier
+	service.WebACLDeleter
+	service.CDNWebACLAssociator
+	service.CDNLoggingApplier
+	service.CDNOriginAccessControlApplier
+	service.ResourceTagger
+	service.CDNStagingSyncer
+	*ViewerFunctionOptions
+	*OriginAccessOptions
 }
 
 // NewCDNCreator returns a new CDNCreator struct.
@@ -37,21 +53,178 @@ func NewCDNCreator(opts *CDNCreatorOptions) *CDNCreator {
 	}
 }
 
-// CreateCDN creates a CDN.
+// CreateCDN creates a CDN. If the CDN already exists, it reconciles the settings of the CDN with the input.
+// So, 'spare build' can be run again after .spare.yml is changed.
 func (c *CDNCreator) CreateCDN(ctx context.Context, input *usecase.CreateCDNInput) (*usecase.CreateCDNOutput, error) {
-	oaiOutput, err := c.opts.OAICreator.CreateOAI(ctx, &service.OAICreatorInput{})
+	created := false
+	cdn, err := c.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if errors.Is(err, service.ErrCDNNotFound) {
+		cdn, err = c.createCDN(ctx, input.BucketName)
+		created = true
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if err := c.reconcileCDN(ctx, cdn.DistributionID, input); err != nil {
+		return nil, err
+	}
+	// The staging CDN of the canary release gets the new settings, too.
+	// Otherwise 'spare promote' would copy the old settings of the staging CDN to the primary CDN.
+	if _, err := c.opts.CDNStagingSyncer.SyncCDNStaging(ctx, &service.CDNStagingSyncerInput{
+		DistributionID: cdn.DistributionID,
+		BucketName:     input.BucketName,
+	}); err != nil {
+		return nil, err
+	}
+	if err := c.opts.restrictOriginAccess(ctx, input.BucketName, input.Encryption); err != nil {
+		return nil, err
+	}
+	if err := c.tagResources(ctx, input.Tags, cdn.ARN); err != nil {
+		return nil, err
+	}
+	return &usecase.CreateCDNOutput{
+		Domain:  cdn.Domain,
+		Created: created,
+	}, nil
+}
+
+// createCDN creates the CDN whose origin is the bucket. reconcileCDN switches the origin to OAC.
+func (c *CDNCreator) createCDN(ctx context.Context, bucket model.BucketName) (*service.CDNFinderOutput, error) {
 	createCDNOutput, err := c.opts.CDNCreator.CreateCDN(ctx, &service.CDNCreatorInput{
-		BucketName: input.BucketName,
-		OAIID:      oaiOutput.ID,
+		BucketName: bucket,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &usecase.CreateCDNOutput{
-		Domain: createCDNOutput.Domain,
+	return &service.CDNFinderOutput{
+		DistributionID: createCDNOutput.DistributionID,
+		ARN:            createCDNOutput.ARN,
+		Domain:         createCDNOutput.Domain,
 	}, nil
 }
+
+// reconcileCDN applies the settings in the input to the CDN.
+func (c *CDNCreator) reconcileCDN(ctx context.Context, id model.DistributionID, input *usecase.CreateCDNInput) error {
+	keyGroupID, err := c.keyGroupID(ctx, input)
+	if err != nil {
+		return err
+	}
+	if _, err := c.opts.CDNCacheBehaviorApplier.ApplyCDNCacheBehaviors(ctx, &service.CDNCacheBehaviorApplierInput{
+		DistributionID: id,
+		BucketName:     input.BucketName,
+		Cache:          input.Cache,
+		KeyGroupID:     keyGroupID,
+	}); err != nil {
+		return err
+	}
+	if _, err := c.opts.CDNCustomOriginApplier.ApplyCDNCustomOrigins(ctx, &service.CDNCustomOriginApplierInput{
+		DistributionID: id,
+		BucketName:     input.BucketName,
+		Origins:        input.Origins,
+	}); err != nil {
+		return err
+	}
+	if _, err := c.opts.CDNResponseHeadersPolicyApplier.ApplyCDNResponseHeadersPolicy(ctx, &service.CDNResponseHeadersPolicyApplierInput{
+		DistributionID:  id,
+		BucketName:      input.BucketName,
+		SecurityHeaders: input.SecurityHeaders,
+		CORS:            input.CORS,
+	}); err != nil {
+		return err
+	}
+	if err := c.opts.applyViewerRequest(ctx, id, input.BucketName, input.ViewerRequest); err != nil {
+		return err
+	}
+	if _, err := c.opts.CDNLoggingApplier.ApplyCDNLogging(ctx, &service.CDNLoggingApplierInput{
+		DistributionID: id,
+		Logging:        input.Logging,
+	}); err != nil {
+		return err
+	}
+	if err := c.applyOriginAccessControl(ctx, id, input); err != nil {
+		return err
+	}
+	return c.reconcileWebACL(ctx, id, input)
+}
+
+// applyOriginAccessControl switches the S3 origins of the CDN to OAC, with or without SSE-KMS, because the bucket
+// policy that restrictOriginAccess sets allows only the CloudFront service principal, not OAI. The statement that
+// allows CloudFront to decrypt the objects with SSE-KMS is added by restrictOriginAccess after the staging CDN is synced.
+func (c *CDNCreator) applyOriginAccessControl(ctx context.Context, id model.DistributionID, input *usecase.CreateCDNInput) error {
+	if _, err := c.opts.CDNOriginAccessControlApplier.ApplyCDNOriginAccessControl(ctx, &service.CDNOriginAccessControlApplierInput{
+		DistributionID: id,
+		BucketName:     input.BucketName,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// keyGroupID returns the ID of the key group that the signed cache behaviors trust.
+// If no cache behavior is signed, it returns empty string.
+func (c *CDNCreator) keyGroupID(ctx context.Context, input *usecase.CreateCDNInput) (string, error) {
+	if !input.Cache.Signed() {
+		return "", nil
+	}
+	output, err := c.opts.KeyGroupGetter.GetKeyGroup(ctx, &service.KeyGroupGetterInput{
+		BucketName: input.BucketName,
+	})
+	if errors.Is(err, service.ErrKeyGroupNotFound) {
+		return "", errfmt.Wrap(err, "signed cache behaviors need the key group. run 'spare keys create' first")
+	}
+	if err != nil {
+		return "", err
+	}
+	return output.ID, nil
+}
+
+// reconcileWebACL creates (or updates) the web ACL and associates it with the CDN.
+// If WAF is disabled, it disassociates the web ACL and deletes it.
+func (c *CDNCreator) reconcileWebACL(ctx context.Context, id model.DistributionID, input *usecase.CreateCDNInput) error {
+	if input.WAF == nil {
+		if _, err := c.opts.CDNWebACLAssociator.AssociateCDNWebACL(ctx, &service.CDNWebACLAssociatorInput{
+			DistributionID: id,
+			WebACLARN:      "",
+		}); err != nil {
+			return err
+		}
+		if _, err := c.opts.WebACLDeleter.DeleteWebACL(ctx, &service.WebACLDeleterInput{
+			BucketName: input.BucketName,
+		}); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	output, err := c.opts.WebACLApplier.ApplyWebACL(ctx, &service.WebACLApplierInput{
+		BucketName: input.BucketName,
+		WAF:        input.WAF,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := c.opts.CDNWebACLAssociator.AssociateCDNWebACL(ctx, &service.CDNWebACLAssociatorInput{
+		DistributionID: id,
+		WebACLARN:      output.ARN,
+	}); err != nil {
+		return err
+	}
+	return c.tagResources(ctx, input.Tags, output.ARN)
+}
+
+// tagResources adds the tags to the resources. If the tags are empty, it does nothing.
+func (c *CDNCreator) tagResources(ctx context.Context, tags model.Tags, arns ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := c.opts.ResourceTagger.TagResources(ctx, &service.ResourceTaggerInput{
+		ARNs: arns,
+		Tags: tags,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// fakeDistribution is the distribution of the bucket and the policies that allow it to read the objects.
+// If created is false, the distribution does not exist.
+type fakeDistribution struct {
+	created bool
+	// originAccessControl is whether the S3 origins read the objects through OAC.
+	originAccessControl bool
+	bucketPolicy        *model.BucketPolicy
+	keyPolicyApplied    bool
+}
+
+func (f *fakeDistribution) FindCDN(_ context.Context, _ *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	if !f.created {
+		return nil, service.ErrCDNNotFound
+	}
+	return &service.CDNFinderOutput{DistributionID: fakePrimaryID, Domain: "primary.cloudfront.net"}, nil
+}
+
+func (f *fakeDistribution) CreateCDN(_ context.Context, _ *service.CDNCreatorInput) (*service.CDNCreatorOutput, error) {
+	f.created = true
+	return &service.CDNCreatorOutput{DistributionID: fakePrimaryID, Domain: "primary.cloudfront.net"}, nil
+}
+
+func (f *fakeDistribution) ApplyCDNCacheBehaviors(_ context.Context, _ *service.CDNCacheBehaviorApplierInput) (*service.CDNCacheBehaviorApplierOutput, error) {
+	return &service.CDNCacheBehaviorApplierOutput{}, nil
+}
+
+func (f *fakeDistribution) ApplyCDNCustomOrigins(_ context.Context, _ *service.CDNCustomOriginApplierInput) (*service.CDNCustomOriginApplierOutput, error) {
+	return &service.CDNCustomOriginApplierOutput{}, nil
+}
+
+func (f *fakeDistribution) ApplyCDNResponseHeadersPolicy(_ context.Context, _ *service.CDNResponseHeadersPolicyApplierInput) (*service.CDNResponseHeadersPolicyApplierOutput, error) {
+	return &service.CDNResponseHeadersPolicyApplierOutput{}, nil
+}
+
+func (f *fakeDistribution) ApplyCDNLogging(_ context.Context, _ *service.CDNLoggingApplierInput) (*service.CDNLoggingApplierOutput, error) {
+	return &service.CDNLoggingApplierOutput{}, nil
+}
+
+func (f *fakeDistribution) ApplyCDNOriginAccessControl(_ context.Context, _ *service.CDNOriginAccessControlApplierInput) (*service.CDNOriginAccessControlApplierOutput, error) {
+	f.originAccessControl = true
+	return &service.CDNOriginAccessControlApplierOutput{}, nil
+}
+
+func (f *fakeDistribution) AssociateCDNWebACL(_ context.Context, _ *service.CDNWebACLAssociatorInput) (*service.CDNWebACLAssociatorOutput, error) {
+	return &service.CDNWebACLAssociatorOutput{}, nil
+}
+
+func (f *fakeDistribution) DeleteWebACL(_ context.Context, _ *service.WebACLDeleterInput) (*service.WebACLDeleterOutput, error) {
+	return &service.WebACLDeleterOutput{}, nil
+}
+
+func (f *fakeDistribution) SyncCDNStaging(_ context.Context, _ *service.CDNStagingSyncerInput) (*service.CDNStagingSyncerOutput, error) {
+	return &service.CDNStagingSyncerOutput{}, nil
+}
+
+func (f *fakeDistribution) ListCDNARNs(_ context.Context, _ *service.CDNARNsListerInput) (*service.CDNARNsListerOutput, error) {
+	return &service.CDNARNsListerOutput{ARNs: []string{"arn:aws:cloudfront::123456789012:distribution/" + fakePrimaryID.String()}}, nil
+}
+
+func (f *fakeDistribution) SetBucketPolicy(_ context.Context, input *service.BucketPolicySetterInput) (*service.BucketPolicySetterOutput, error) {
+	f.bucketPolicy = input.Policy
+	return &service.BucketPolicySetterOutput{}, nil
+}
+
+func (f *fakeDistribution) ApplyEncryptionKeyPolicy(_ context.Context, _ *service.EncryptionKeyPolicyApplierInput) (*service.EncryptionKeyPolicyApplierOutput, error) {
+	f.keyPolicyApplied = true
+	return &service.EncryptionKeyPolicyApplierOutput{}, nil
+}
+
+func TestCDNCreatorCreateCDN(t *testing.T) {
+	t.Parallel()
+
+	bucket := model.BucketName("spare-bucket")
+	arns := []string{"arn:aws:cloudfront::123456789012:distribution/" + fakePrimaryID.String()}
+	tests := []struct {
+		name          string
+		created       bool
+		encryption    *model.Encryption
+		wantCreated   bool
+		wantKeyPolicy bool
+	}{
+		{
+			name:          "success. new distribution with SSE-S3 reads through OAC",
+			created:       false,
+			encryption:    &model.Encryption{Type: model.EncryptionTypeSSES3},
+			wantCreated:   true,
+			wantKeyPolicy: false,
+		},
+		{
+			name:          "success. existing distribution with SSE-S3 is switched to OAC",
+			created:       true,
+			encryption:    nil,
+			wantCreated:   false,
+			wantKeyPolicy: false,
+		},
+		{
+			name:          "success. existing distribution with SSE-KMS is switched to OAC and allowed to decrypt",
+			created:       true,
+			encryption:    &model.Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyID: "alias/spare-bucket"},
+			wantCreated:   false,
+			wantKeyPolicy: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			distribution := &fakeDistribution{created: tt.created}
+			viewer := &fakeViewerFunction{published: map[model.CDNFunctionName]string{}}
+			creator := NewCDNCreator(&CDNCreatorOptions{
+				CDNCreator:                      distribution,
+				CDNFinder:                       distribution,
+				CDNCacheBehaviorApplier:         distribution,
+				CDNCustomOriginApplier:          distribution,
+				CDNResponseHeadersPolicyApplier: distribution,
+				WebACLDeleter:                   distribution,
+				CDNWebACLAssociator:             distribution,
+				CDNLoggingApplier:               distribution,
+				CDNOriginAccessControlApplier:   distribution,
+				CDNStagingSyncer:                distribution,
+				ViewerFunctionOptions: &ViewerFunctionOptions{
+					CDNFunctionPublisher:        viewer,
+					CDNViewerFunctionAssociator: viewer,
+					MaintenanceGetter:           viewer,
+					ViewerRulesPutter:           viewer,
+				},
+				OriginAccessOptions: &OriginAccessOptions{
+					CDNARNsLister:              distribution,
+					BucketPolicySetter:         distribution,
+					EncryptionKeyPolicyApplier: distribution,
+				},
+			})
+			output, err := creator.CreateCDN(context.Background(), &usecase.CreateCDNInput{
+				BucketName: bucket,
+				Cache:      model.NewCacheSettings(),
+				Encryption: tt.encryption,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if output.Created != tt.wantCreated {
+				t.Errorf("CDNCreator.CreateCDN() created = %t, want %t", output.Created, tt.wantCreated)
+			}
+			if !distribution.originAccessControl {
+				t.Error("the S3 origins are not switched to OAC, but the bucket policy allows only OAC")
+			}
+			if distribution.keyPolicyApplied != tt.wantKeyPolicy {
+				t.Errorf("key policy is applied = %t, want %t", distribution.keyPolicyApplied, tt.wantKeyPolicy)
+			}
+			if distribution.bucketPolicy == nil {
+				t.Fatal("bucket policy is not set")
+			}
+			statement := distribution.bucketPolicy.Statement[0]
+			if statement.Principal.Service != "cloudfront.amazonaws.com" {
+				t.Errorf("principal = %s, want cloudfront.amazonaws.com", statement.Principal.Service)
+			}
+			if diff := cmp.Diff(arns, statement.Condition["StringEquals"]["AWS:SourceArn"]); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
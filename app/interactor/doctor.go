@@ -0,0 +1,291 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+const (
+	// checkProfile is the name of the check of the AWS profile.
+	checkProfile = "aws profile"
+	// checkCredentials is the name of the check of the credentials.
+	checkCredentials = "credentials"
+	// checkRegion is the name of the check of the region.
+	checkRegion = "region"
+	// checkBucket is the name of the check of the bucket.
+	checkBucket = "s3 bucket"
+	// checkIAM is the name of the check of the IAM permissions.
+	checkIAM = "iam permissions"
+	// maxDeniedActions is the maximum number of the denied actions in the detail of the check.
+	maxDeniedActions = 5
+)
+
+// PreflightCheckerSet is a provider set for PreflightChecker.
+//
+//nolint:gochecknoglobals
+var PreflightCheckerSet = wire.NewSet(
+	NewPreflightChecker,
+	wire.Struct(new(PreflightCheckerOptions), "*"),
+	wire.Bind(new(usecase.PreflightChecker), new(*PreflightChecker)),
+)
+
+var _ usecase.PreflightChecker = (*PreflightChecker)(nil)
+
+// PreflightChecker is an implementation for PreflightChecker.
+type PreflightChecker struct {
+	opts *PreflightCheckerOptions
+}
+
+// PreflightCheckerOptions is an option struct for PreflightChecker.
+type PreflightCheckerOptions struct {
+	service.ProfileFinder
+	service.CallerIdentityGetter
+	service.RegionOptInStatusGetter
+	service.BucketAvailabilityChecker
+	service.IAMActionsSimulator
+}
+
+// NewPreflightChecker returns a new PreflightChecker struct.
+func NewPreflightChecker(opts *PreflightCheckerOptions) *PreflightChecker {
+	return &PreflightChecker{
+		opts: opts,
+	}
+}
+
+// RunPreflightChecks checks the AWS profile, the credentials, the region, the bucket and the IAM permissions in this order.
+// The checks that need AWS are skipped if the credentials can not be resolved.
+func (p *PreflightChecker) RunPreflightChecks(ctx context.Context, input *usecase.RunPreflightChecksInput) (*usecase.RunPreflightChecksOutput, error) {
+	checklist := model.Checklist{p.checkProfile(ctx, input.Profile)}
+
+	identity, err := p.opts.CallerIdentityGetter.GetCallerIdentity(ctx, &service.CallerIdentityGetterInput{})
+	if err != nil {
+		checklist = append(checklist, model.Check{
+			Name:   checkCredentials,
+			Status: model.CheckStatusFail,
+			Detail: err.Error(),
+			Hint: fmt.Sprintf("run 'aws sts get-caller-identity --profile %s' to debug. refresh the expired keys, or run 'aws sso login --profile %s'",
+				input.Profile, input.Profile),
+		})
+		for _, name := range []string{checkRegion, checkBucket, checkIAM} {
+			checklist = append(checklist, skippedCheck(name, checkCredentials))
+		}
+		return &usecase.RunPreflightChecksOutput{Checklist: checklist}, nil
+	}
+	checklist = append(checklist, model.Check{
+		Name:   checkCredentials,
+		Status: model.CheckStatusPass,
+		Detail: fmt.Sprintf("account %s as %s", identity.Identity.Account, identity.Identity.ARN),
+	})
+
+	region := p.checkRegion(ctx, input.Region)
+	checklist = append(checklist, region)
+	if region.Status == model.CheckStatusFail {
+		checklist = append(checklist, skippedCheck(checkBucket, checkRegion))
+	} else {
+		checklist = append(checklist, p.checkBucket(ctx, input, identity.Identity.Account))
+	}
+	checklist = append(checklist, p.checkIAM(ctx, identity.Identity, input.Permissions))
+
+	return &usecase.RunPreflightChecksOutput{
+		Identity:  &identity.Identity,
+		Checklist: checklist,
+	}, nil
+}
+
+// checkProfile checks that the profile is in the shared config files.
+// The default profile is optional, because the credentials can be resolved from the instance or container role.
+func (p *PreflightChecker) checkProfile(ctx context.Context, profile model.AWSProfile) model.Check {
+	output, err := p.opts.ProfileFinder.FindProfile(ctx, &service.ProfileFinderInput{Profile: profile})
+	if err != nil {
+		return model.Check{
+			Name:   checkProfile,
+			Status: model.CheckStatusWarn,
+			Detail: err.Error(),
+			Hint:   "check the syntax of the shared config files",
+		}
+	}
+	files := strings.Join(output.Files, ", ")
+	switch {
+	case output.Found && output.SSO:
+		return model.Check{Name: checkProfile, Status: model.CheckStatusPass, Detail: fmt.Sprintf("%s (AWS SSO) is found", profile)}
+	case output.Found:
+		return model.Check{Name: checkProfile, Status: model.CheckStatusPass, Detail: fmt.Sprintf("%s is found", profile)}
+	case output.EnvCredentials:
+		return model.Check{
+			Name:   checkProfile,
+			Status: model.CheckStatusPass,
+			Detail: fmt.Sprintf("%s is not found, and the credentials in the environment variables are used", profile),
+		}
+	case profile == "default":
+		return model.Check{
+			Name:   checkProfile,
+			Status: model.CheckStatusWarn,
+			Detail: fmt.Sprintf("default profile is not in %s. the instance or container role is used if any", files),
+			Hint:   "run 'aws configure' or 'aws configure sso', or set --profile or $AWS_PROFILE",
+		}
+	default:
+		return model.Check{
+			Name:   checkProfile,
+			Status: model.CheckStatusFail,
+			Detail: fmt.Sprintf("%s is not in %s", profile, files),
+			Hint:   fmt.Sprintf("run 'aws configure --profile %s' or 'aws configure sso', or fix --profile or $AWS_PROFILE", profile),
+		}
+	}
+}
+
+// checkRegion checks that the region is enabled in the account. Only the opt-in regions are checked with AWS.
+func (p *PreflightChecker) checkRegion(ctx context.Context, region model.RegionInfo) model.Check {
+	name := region.Region.String()
+	if region.DisplayName != "" {
+		name = fmt.Sprintf("%s (%s)", region.Region, region.DisplayName)
+	}
+	if !region.OptIn {
+		return model.Check{Name: checkRegion, Status: model.CheckStatusPass, Detail: name + " is enabled by default"}
+	}
+
+	output, err := p.opts.RegionOptInStatusGetter.GetRegionOptInStatus(ctx, &service.RegionOptInStatusGetterInput{
+		Region: region.Region,
+	})
+	if err != nil {
+		return model.Check{
+			Name:   checkRegion,
+			Status: model.CheckStatusWarn,
+			Detail: err.Error(),
+			Hint:   fmt.Sprintf("%s is an opt-in region. grant account:GetRegionOptStatus to check it", region.Region),
+		}
+	}
+	if !output.Enabled {
+		return model.Check{
+			Name:   checkRegion,
+			Status: model.CheckStatusFail,
+			Detail: fmt.Sprintf("%s is an opt-in region, and its status is %s", name, output.Status),
+			Hint:   fmt.Sprintf("run 'aws account enable-region --region-name %s', or enable it in the AWS console", region.Region),
+		}
+	}
+	return model.Check{Name: checkRegion, Status: model.CheckStatusPass, Detail: fmt.Sprintf("%s is %s", name, output.Status)}
+}
+
+// checkBucket checks that the bucket is owned by the account, or the name is available.
+func (p *PreflightChecker) checkBucket(ctx context.Context, input *usecase.RunPreflightChecksInput, account string) model.Check {
+	output, err := p.opts.BucketAvailabilityChecker.CheckBucketAvailability(ctx, &service.BucketAvailabilityCheckerInput{
+		Bucket:  input.BucketName,
+		Account: account,
+	})
+	if err != nil {
+		return model.Check{
+			Name:   checkBucket,
+			Status: model.CheckStatusWarn,
+			Detail: err.Error(),
+			Hint:   "grant s3:ListBucket on the bucket to check it",
+		}
+	}
+
+	switch output.Availability {
+	case model.BucketAvailabilityOwned:
+		return model.Check{
+			Name:   checkBucket,
+			Status: model.CheckStatusPass,
+			Detail: fmt.Sprintf("%s is owned by account %s", input.BucketName, account),
+		}
+	case model.BucketAvailabilityAvailable:
+		if input.BucketMustExist {
+			return model.Check{
+				Name:   checkBucket,
+				Status: model.CheckStatusFail,
+				Detail: fmt.Sprintf("%s does not exist", input.BucketName),
+				Hint:   "run 'spare build' first",
+			}
+		}
+		return model.Check{
+			Name:   checkBucket,
+			Status: model.CheckStatusPass,
+			Detail: fmt.Sprintf("%s is available. 'spare build' creates it", input.BucketName),
+		}
+	case model.BucketAvailabilityOtherRegion:
+		return model.Check{
+			Name:   checkBucket,
+			Status: model.CheckStatusFail,
+			Detail: fmt.Sprintf("%s exists in another region than %s", input.BucketName, input.Region.Region),
+			Hint:   "set region in .spare.yml to the region of the bucket, or change s3BucketName",
+		}
+	default:
+		return model.Check{
+			Name:   checkBucket,
+			Status: model.CheckStatusFail,
+			Detail: fmt.Sprintf("%s is owned by another account, or account %s has no access to it", input.BucketName, account),
+			Hint:   "bucket names are global. change s3BucketName in .spare.yml, or grant s3:ListBucket if the bucket is yours",
+		}
+	}
+}
+
+// checkIAM checks that the identity-based policies of the identity allow the permissions.
+func (p *PreflightChecker) checkIAM(ctx context.Context, identity model.CallerIdentity, permissions []model.IAMPermission) model.Check {
+	if identity.Root() {
+		return model.Check{
+			Name:   checkIAM,
+			Status: model.CheckStatusWarn,
+			Detail: "the root user is allowed all actions",
+			Hint:   "use an IAM user or an IAM role instead of the root user",
+		}
+	}
+	principal, ok := identity.PrincipalARN()
+	if !ok {
+		return model.Check{
+			Name:   checkIAM,
+			Status: model.CheckStatusWarn,
+			Detail: fmt.Sprintf("the policies of %s can not be simulated", identity.ARN),
+			Hint:   "use an IAM user or an IAM role to check the permissions",
+		}
+	}
+
+	output, err := p.opts.IAMActionsSimulator.SimulateIAMActions(ctx, &service.IAMActionsSimulatorInput{
+		PrincipalARN: principal,
+		Permissions:  permissions,
+	})
+	if err != nil {
+		return model.Check{
+			Name:   checkIAM,
+			Status: model.CheckStatusWarn,
+			Detail: err.Error(),
+			Hint:   fmt.Sprintf("grant iam:SimulatePrincipalPolicy on %s to check the permissions", principal),
+		}
+	}
+
+	actions := 0
+	for _, perm := range permissions {
+		actions += len(perm.Actions)
+	}
+	if len(output.Denied) == 0 {
+		return model.Check{
+			Name:   checkIAM,
+			Status: model.CheckStatusPass,
+			Detail: fmt.Sprintf("%d actions are allowed for %s", actions, principal),
+		}
+	}
+	denied := output.Denied
+	if len(denied) > maxDeniedActions {
+		denied = append(denied[:maxDeniedActions:maxDeniedActions], fmt.Sprintf("and %d more", len(output.Denied)-maxDeniedActions))
+	}
+	return model.Check{
+		Name:   checkIAM,
+		Status: model.CheckStatusFail,
+		Detail: fmt.Sprintf("%d actions are denied: %s", len(output.Denied), strings.Join(denied, ", ")),
+		Hint: fmt.Sprintf("attach the policy of 'spare iam-policy' to %s. the resource-based policies and the session policies are not simulated",
+			principal),
+	}
+}
+
+// skippedCheck returns the check that is skipped because the check named dependency failed.
+func skippedCheck(name, dependency string) model.Check {
+	return model.Check{
+		Name:   name,
+		Status: model.CheckStatusSkip,
+		Detail: fmt.Sprintf("skipped because the %s check failed", dependency),
+	}
+}
@@ -0,0 +1,155 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// GarbageCollectorSet is a provider set for GarbageCollector.
+//
+//nolint:gochecknoglobals
+var GarbageCollectorSet = wire.NewSet(
+	NewGarbageCollector,
+	wire.Struct(new(GarbageCollectorOptions), "*"),
+	wire.Bind(new(usecase.GarbageCollector), new(*GarbageCollector)),
+)
+
+var _ usecase.GarbageCollector = (*GarbageCollector)(nil)
+
+// GarbageCollector is an implementation for GarbageCollector.
+type GarbageCollector struct {
+	opts *GarbageCollectorOptions
+}
+
+// GarbageCollectorOptions is an option struct for GarbageCollector.
+type GarbageCollectorOptions struct {
+	service.ReleaseHistoryGetter
+	service.ReleaseHistoryPutter
+	service.BucketObjectLister
+	service.BucketObjectDeleter
+	service.BucketObjectGetter
+}
+
+// NewGarbageCollector returns a new GarbageCollector struct.
+func NewGarbageCollector(opts *GarbageCollectorOptions) *GarbageCollector {
+	return &GarbageCollector{
+		opts: opts,
+	}
+}
+
+// CollectGarbage deletes the releases that are not kept by the retention policy.
+// An object under the release prefix is deleted only if it belongs to no manifest of the retained releases
+// and the deploys in progress. The manifests of the expired releases are deleted first, and then the expired
+// releases are removed from the release history before their objects are deleted. So 'spare rollback' never
+// switches to a release whose objects have been deleted.
+func (g *GarbageCollector) CollectGarbage(ctx context.Context, input *usecase.CollectGarbageInput) (*usecase.CollectGarbageOutput, error) {
+	historyOutput, err := g.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := historyOutput.History
+	if err := history.ValidateAbandoned(input.Abandoned); err != nil {
+		return nil, err
+	}
+	retained, expired := history.Retain(input.Policy, input.Now)
+
+	// The objects are listed before the manifests. deploy puts the manifest before it uploads the objects,
+	// so the manifest of every listed object is listed, too.
+	listOutput, err := g.opts.BucketObjectLister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: input.BucketName,
+		Prefix: model.ReleasesRootPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := g.getManifests(ctx, input.BucketName)
+	if err != nil {
+		return nil, err
+	}
+	kept, pending, deleted := history.SplitManifests(manifests, retained, input.Abandoned)
+	unreachable := listOutput.Objects.Unreachable(retained, append(kept, pending...))
+
+	output := &usecase.CollectGarbageOutput{
+		Retained: retained,
+		Expired:  expired,
+		Pending:  make([]model.ReleaseID, 0, len(pending)),
+		Objects:  unreachable,
+	}
+	for _, m := range pending {
+		output.Pending = append(output.Pending, m.ID)
+	}
+	if input.DryRun {
+		return output, nil
+	}
+
+	if len(deleted) > 0 {
+		keys := make([]string, 0, len(deleted))
+		for _, m := range deleted {
+			keys = append(keys, m.Key())
+		}
+		if _, err := g.opts.BucketObjectDeleter.DeleteBucketObjects(ctx, &service.BucketObjectDeleterInput{
+			Bucket: input.BucketName,
+			Keys:   keys,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(expired) > 0 {
+		history.Remove(expired)
+		if _, err := g.opts.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+			Bucket:  input.BucketName,
+			History: history,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(unreachable) > 0 {
+		if _, err := g.opts.BucketObjectDeleter.DeleteBucketObjects(ctx, &service.BucketObjectDeleterInput{
+			Bucket: input.BucketName,
+			Keys:   unreachable.Keys(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// getManifests gets all release manifests in the bucket.
+func (g *GarbageCollector) getManifests(ctx context.Context, bucket model.BucketName) ([]*model.ReleaseManifest, error) {
+	listOutput, err := g.opts.BucketObjectLister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: bucket,
+		Prefix: model.ReleaseManifestPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]*model.ReleaseManifest, 0, len(listOutput.Objects))
+	for _, o := range listOutput.Objects {
+		if _, ok := model.ReleaseIDFromManifestKey(o.Key); !ok {
+			continue
+		}
+		output, err := g.opts.BucketObjectGetter.GetBucketObject(ctx, &service.BucketObjectGetterInput{
+			Bucket: bucket,
+			Key:    o.Key,
+		})
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := model.ParseReleaseManifest(output.Data)
+		if err != nil {
+			return nil, errfmt.Wrap(err, o.Key)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,132 @@
+package interactor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// fakeBucket is the bucket in memory.
+type fakeBucket struct {
+	objects map[string][]byte
+	deleted []string
+}
+
+func (f *fakeBucket) ListBucketObjects(_ context.Context, input *service.BucketObjectListerInput) (*service.BucketObjectListerOutput, error) {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		if strings.HasPrefix(key, input.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	objects := make(model.BucketObjects, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, model.BucketObject{Key: key, Size: int64(len(f.objects[key]))})
+	}
+	return &service.BucketObjectListerOutput{Objects: objects}, nil
+}
+
+func (f *fakeBucket) GetBucketObject(_ context.Context, input *service.BucketObjectGetterInput) (*service.BucketObjectGetterOutput, error) {
+	return &service.BucketObjectGetterOutput{Data: f.objects[input.Key]}, nil
+}
+
+func (f *fakeBucket) DeleteBucketObjects(_ context.Context, input *service.BucketObjectDeleterInput) (*service.BucketObjectDeleterOutput, error) {
+	for _, key := range input.Keys {
+		delete(f.objects, key)
+		f.deleted = append(f.deleted, key)
+	}
+	return &service.BucketObjectDeleterOutput{}, nil
+}
+
+// putManifest puts the manifest of the release.
+func (f *fakeBucket) putManifest(t *testing.T, id model.ReleaseID, keys ...string) {
+	t.Helper()
+	data, err := model.NewReleaseManifest(id, keys).String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.objects[model.ReleaseManifestKey(id)] = []byte(data)
+}
+
+func TestGarbageCollectorCollectGarbage(t *testing.T) {
+	t.Parallel()
+
+	const (
+		live     model.ReleaseID = "20231021T090000Z-aaaaaa"
+		old      model.ReleaseID = "20231019T090000Z-bbbbbb" // deployed before the manifests were recorded
+		running  model.ReleaseID = "20231021T100000Z-cccccc" // started 3 hours ago and still uploading
+		failed   model.ReleaseID = "20231020T100000Z-dddddd"
+		orphaned model.ReleaseID = "20231020T110000Z-eeeeee" // failed before the manifests were recorded
+	)
+	now := time.Date(2023, 10, 21, 13, 0, 0, 0, time.UTC)
+
+	bucket := &fakeBucket{objects: map[string][]byte{
+		live.Prefix() + "index.html":     []byte("live"),
+		old.Prefix() + "index.html":      []byte("old"),
+		running.Prefix() + "index.html":  []byte("running"),
+		failed.Prefix() + "index.html":   []byte("failed"),
+		orphaned.Prefix() + "index.html": []byte("orphaned"),
+		model.ReleaseHistoryKey:          []byte("{}"),
+	}}
+	bucket.putManifest(t, live, live.Prefix()+"index.html")
+	bucket.putManifest(t, running, running.Prefix()+"index.html", running.Prefix()+"app.js")
+	bucket.putManifest(t, failed, failed.Prefix()+"index.html")
+
+	history := model.NewReleaseHistory()
+	for _, id := range []model.ReleaseID{old, live} {
+		createdAt, err := id.CreatedAt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		history.Add(model.Release{ID: id, CreatedAt: createdAt})
+	}
+	history.Live = live
+	store := &fakeReleaseHistoryStore{history: history}
+
+	gc := NewGarbageCollector(&GarbageCollectorOptions{
+		ReleaseHistoryGetter: store,
+		ReleaseHistoryPutter: store,
+		BucketObjectLister:   bucket,
+		BucketObjectDeleter:  bucket,
+		BucketObjectGetter:   bucket,
+	})
+	output, err := gc.CollectGarbage(context.Background(), &usecase.CollectGarbageInput{
+		BucketName: "spare-bucket",
+		Policy:     model.RetentionPolicy{KeepLast: 1},
+		Now:        now,
+		Abandoned:  []model.ReleaseID{failed},
+		DryRun:     false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]model.ReleaseID{running}, output.Pending); diff != "" {
+		t.Errorf("pending deploys are mismatch (-want +got):\n%s", diff)
+	}
+	wantDeleted := []string{
+		model.ReleaseManifestKey(failed),
+		old.Prefix() + "index.html",
+		failed.Prefix() + "index.html",
+		orphaned.Prefix() + "index.html",
+	}
+	sort.Strings(wantDeleted[1:])
+	if diff := cmp.Diff(wantDeleted, bucket.deleted); diff != "" {
+		t.Errorf("deleted keys are mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := bucket.objects[running.Prefix()+"index.html"]; !ok {
+		t.Error("the object of the running deploy is deleted")
+	}
+	if _, err := store.history.Find(old); err == nil {
+		t.Errorf("expired release %s is still in the release history", old)
+	}
+}
@@ -0,0 +1,75 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// IAMPolicyGeneratorSet is a provider set for IAMPolicyGenerator.
+//
+//nolint:gochecknoglobals
+var IAMPolicyGeneratorSet = wire.NewSet(
+	NewIAMPolicyGenerator,
+	wire.Struct(new(IAMPolicyGeneratorOptions), "*"),
+	wire.Bind(new(usecase.IAMPolicyGenerator), new(*IAMPolicyGenerator)),
+)
+
+var _ usecase.IAMPolicyGenerator = (*IAMPolicyGenerator)(nil)
+
+// IAMPolicyGenerator is an implementation for IAMPolicyGenerator.
+type IAMPolicyGenerator struct {
+	opts *IAMPolicyGeneratorOptions
+}
+
+// IAMPolicyGeneratorOptions is an option struct for IAMPolicyGenerator.
+type IAMPolicyGeneratorOptions struct {
+	service.CallerIdentityGetter
+	service.CDNFinder
+}
+
+// NewIAMPolicyGenerator returns a new IAMPolicyGenerator struct.
+func NewIAMPolicyGenerator(opts *IAMPolicyGeneratorOptions) *IAMPolicyGenerator {
+	return &IAMPolicyGenerator{
+		opts: opts,
+	}
+}
+
+// GenerateIAMPolicy returns the IAM policy for the commands and the features.
+// If the distribution does not exist yet, the distribution actions are allowed on all distributions in the account.
+func (i *IAMPolicyGenerator) GenerateIAMPolicy(ctx context.Context, input *usecase.GenerateIAMPolicyInput) (*usecase.GenerateIAMPolicyOutput, error) {
+	opts := input.Options
+	if opts.Account == "" {
+		identity, err := i.opts.CallerIdentityGetter.GetCallerIdentity(ctx, &service.CallerIdentityGetterInput{})
+		if err != nil {
+			return nil, err
+		}
+		opts.Account = identity.Identity.Account
+	}
+	if opts.DistributionID.Empty() {
+		cdn, err := i.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+			BucketName: opts.Bucket,
+		})
+		switch {
+		case errors.Is(err, service.ErrCDNNotFound):
+		case err != nil:
+			return nil, err
+		default:
+			opts.DistributionID = cdn.DistributionID
+		}
+	}
+
+	permissions, err := model.NewIAMPermissions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.GenerateIAMPolicyOutput{
+		Policy:         model.NewIAMPolicy(permissions),
+		Account:        opts.Account,
+		DistributionID: opts.DistributionID,
+	}, nil
+}
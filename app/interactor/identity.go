@@ -0,0 +1,53 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// IdentityResolverSet is a provider set for IdentityResolver.
+//
+//nolint:gochecknoglobals
+var IdentityResolverSet = wire.NewSet(
+	NewIdentityResolver,
+	wire.Struct(new(IdentityResolverOptions), "*"),
+	wire.Bind(new(usecase.IdentityResolver), new(*IdentityResolver)),
+)
+
+var _ usecase.IdentityResolver = (*IdentityResolver)(nil)
+
+// IdentityResolver is an implementation for IdentityResolver.
+type IdentityResolver struct {
+	opts *IdentityResolverOptions
+}
+
+// IdentityResolverOptions is an option struct for IdentityResolver.
+type IdentityResolverOptions struct {
+	service.CallerIdentityGetter
+	service.ProfileFinder
+}
+
+// NewIdentityResolver returns a new IdentityResolver struct.
+func NewIdentityResolver(opts *IdentityResolverOptions) *IdentityResolver {
+	return &IdentityResolver{
+		opts: opts,
+	}
+}
+
+// ResolveIdentity returns the identity of the credentials. If the credentials can not be resolved and
+// the profile is an AWS SSO profile, the SSO session is regarded as expired.
+func (i *IdentityResolver) ResolveIdentity(ctx context.Context, input *usecase.ResolveIdentityInput) (*usecase.ResolveIdentityOutput, error) {
+	identity, err := i.opts.CallerIdentityGetter.GetCallerIdentity(ctx, &service.CallerIdentityGetterInput{})
+	if err == nil {
+		return &usecase.ResolveIdentityOutput{Identity: &identity.Identity}, nil
+	}
+
+	profile, findErr := i.opts.ProfileFinder.FindProfile(ctx, &service.ProfileFinderInput{Profile: input.Profile})
+	if findErr != nil || !profile.SSO {
+		return nil, err
+	}
+	return &usecase.ResolveIdentityOutput{SSOLoginRequired: true}, nil
+}
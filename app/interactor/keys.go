@@ -0,0 +1,148 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// SigningKeyOptionsSet is a provider set for SigningKeyOptions.
+//
+//nolint:gochecknoglobals
+var SigningKeyOptionsSet = wire.NewSet(
+	wire.Struct(new(SigningKeyOptions), "*"),
+)
+
+// SigningKeyOptions is an option struct for managing the signing keys.
+// It's shared by SigningKeyCreator and SigningKeyRotator.
+type SigningKeyOptions struct {
+	service.KeyGroupGetter
+	service.KeyGroupApplier
+	service.PublicKeyCreator
+	service.PublicKeyDeleter
+}
+
+// SigningKeyCreatorSet is a provider set for SigningKeyCreator.
+//
+//nolint:gochecknoglobals
+var SigningKeyCreatorSet = wire.NewSet(
+	NewSigningKeyCreator,
+	wire.Bind(new(usecase.SigningKeyCreator), new(*SigningKeyCreator)),
+)
+
+var _ usecase.SigningKeyCreator = (*SigningKeyCreator)(nil)
+
+// SigningKeyCreator is an implementation for SigningKeyCreator.
+type SigningKeyCreator struct {
+	opts *SigningKeyOptions
+}
+
+// NewSigningKeyCreator returns a new SigningKeyCreator struct.
+func NewSigningKeyCreator(opts *SigningKeyOptions) *SigningKeyCreator {
+	return &SigningKeyCreator{
+		opts: opts,
+	}
+}
+
+// CreateSigningKey registers the public key and creates the key group that has only the public key.
+// If the key group already exists, it returns ErrKeyGroupAlreadyExists, because the keys must be rotated instead.
+func (s *SigningKeyCreator) CreateSigningKey(ctx context.Context, input *usecase.CreateSigningKeyInput) (*usecase.CreateSigningKeyOutput, error) {
+	_, err := s.opts.KeyGroupGetter.GetKeyGroup(ctx, &service.KeyGroupGetterInput{
+		BucketName: input.BucketName,
+	})
+	if err == nil {
+		return nil, errfmt.Wrap(service.ErrKeyGroupAlreadyExists, model.NewKeyGroupName(input.BucketName))
+	}
+	if !errors.Is(err, service.ErrKeyGroupNotFound) {
+		return nil, err
+	}
+
+	key, err := s.opts.PublicKeyCreator.CreatePublicKey(ctx, &service.PublicKeyCreatorInput{
+		BucketName: input.BucketName,
+		PublicKey:  input.PublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.opts.KeyGroupApplier.ApplyKeyGroup(ctx, &service.KeyGroupApplierInput{
+		BucketName:   input.BucketName,
+		PublicKeyIDs: []string{key.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.CreateSigningKeyOutput{
+		KeyPairID:  key.ID,
+		KeyGroupID: group.ID,
+	}, nil
+}
+
+// SigningKeyRotatorSet is a provider set for SigningKeyRotator.
+//
+//nolint:gochecknoglobals
+var SigningKeyRotatorSet = wire.NewSet(
+	NewSigningKeyRotator,
+	wire.Bind(new(usecase.SigningKeyRotator), new(*SigningKeyRotator)),
+)
+
+var _ usecase.SigningKeyRotator = (*SigningKeyRotator)(nil)
+
+// SigningKeyRotator is an implementation for SigningKeyRotator.
+type SigningKeyRotator struct {
+	opts *SigningKeyOptions
+}
+
+// NewSigningKeyRotator returns a new SigningKeyRotator struct.
+func NewSigningKeyRotator(opts *SigningKeyOptions) *SigningKeyRotator {
+	return &SigningKeyRotator{
+		opts: opts,
+	}
+}
+
+// RotateSigningKey registers the new public key and adds it to the key group. The previous key is kept in the key group,
+// so the URLs signed with it are valid until they expire. The older keys are removed from the key group and deleted.
+// The cache behaviors trust the key group, so the CDN does not need to be updated.
+func (s *SigningKeyRotator) RotateSigningKey(ctx context.Context, input *usecase.RotateSigningKeyInput) (*usecase.RotateSigningKeyOutput, error) {
+	group, err := s.opts.KeyGroupGetter.GetKeyGroup(ctx, &service.KeyGroupGetterInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.opts.PublicKeyCreator.CreatePublicKey(ctx, &service.PublicKeyCreatorInput{
+		BucketName: input.BucketName,
+		PublicKey:  input.PublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys, retired := model.RotateSigningKeys(group.PublicKeyIDs, key.ID)
+	if _, err := s.opts.KeyGroupApplier.ApplyKeyGroup(ctx, &service.KeyGroupApplierInput{
+		BucketName:   input.BucketName,
+		PublicKeyIDs: keys,
+	}); err != nil {
+		return nil, err
+	}
+	for _, id := range retired {
+		if _, err := s.opts.PublicKeyDeleter.DeletePublicKey(ctx, &service.PublicKeyDeleterInput{
+			ID: id,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	output := &usecase.RotateSigningKeyOutput{
+		KeyPairID:         key.ID,
+		RetiredKeyPairIDs: retired,
+	}
+	if len(keys) > 1 {
+		output.PreviousKeyPairID = keys[0]
+	}
+	return output, nil
+}
@@ -0,0 +1,124 @@
+package interactor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// AccessLogAnalyzerSet is a provider set for AccessLogAnalyzer.
+//
+//nolint:gochecknoglobals
+var AccessLogAnalyzerSet = wire.NewSet(
+	NewAccessLogAnalyzer,
+	wire.Struct(new(AccessLogAnalyzerOptions), "*"),
+	wire.Bind(new(usecase.AccessLogAnalyzer), new(*AccessLogAnalyzer)),
+)
+
+var _ usecase.AccessLogAnalyzer = (*AccessLogAnalyzer)(nil)
+
+// AccessLogAnalyzer is an implementation for AccessLogAnalyzer.
+type AccessLogAnalyzer struct {
+	opts *AccessLogAnalyzerOptions
+}
+
+// AccessLogAnalyzerOptions is an option struct for AccessLogAnalyzer.
+type AccessLogAnalyzerOptions struct {
+	service.BucketObjectLister
+	service.BucketObjectGetter
+}
+
+// NewAccessLogAnalyzer returns a new AccessLogAnalyzer struct.
+func NewAccessLogAnalyzer(opts *AccessLogAnalyzerOptions) *AccessLogAnalyzer {
+	return &AccessLogAnalyzer{
+		opts: opts,
+	}
+}
+
+// AnalyzeAccessLogs downloads the CloudFront standard logs in the time window from the log bucket, and summarizes them.
+// The log files are chosen by the hour in the file name, so only the files that can have the requests in the window are downloaded.
+func (a *AccessLogAnalyzer) AnalyzeAccessLogs(ctx context.Context, input *usecase.AnalyzeAccessLogsInput) (*usecase.AnalyzeAccessLogsOutput, error) {
+	listOutput, err := a.opts.BucketObjectLister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: input.LogBucket,
+		Prefix: model.CloudFrontLogPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(listOutput.Objects))
+	for _, o := range listOutput.Objects {
+		hour, ok := model.CloudFrontLogFileHour(o.Key)
+		if !ok {
+			continue
+		}
+		// The file has the requests in [hour, hour+1h).
+		if hour.Add(time.Hour).After(input.Since) && hour.Before(input.Until) {
+			keys = append(keys, o.Key)
+		}
+	}
+
+	files := make([][]model.CloudFrontLogEntry, len(keys))
+	eg, egCtx := errgroup.WithContext(ctx)
+	weighted := semaphore.NewWeighted(int64(runtime.NumCPU()))
+	for i, key := range keys {
+		i, key := i, key
+		eg.Go(func() error {
+			if err := weighted.Acquire(egCtx, 1); err != nil {
+				return err
+			}
+			defer weighted.Release(1)
+
+			entries, err := a.downloadAccessLog(egCtx, input.LogBucket, key)
+			if err != nil {
+				return err
+			}
+			files[i] = entries
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.CloudFrontLogEntry, 0)
+	for _, f := range files {
+		entries = append(entries, f...)
+	}
+	return &usecase.AnalyzeAccessLogsOutput{
+		Report: model.NewAccessLogReport(entries, input.Since, input.Until, input.Top),
+		Files:  len(keys),
+	}, nil
+}
+
+// downloadAccessLog downloads the gzip compressed CloudFront standard log, and parses it.
+func (a *AccessLogAnalyzer) downloadAccessLog(ctx context.Context, bucket model.BucketName, key string) ([]model.CloudFrontLogEntry, error) {
+	output, err := a.opts.BucketObjectGetter.GetBucketObject(ctx, &service.BucketObjectGetterInput{
+		Bucket: bucket,
+		Key:    key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(output.Data))
+	if err != nil {
+		return nil, errfmt.Wrap(model.ErrInvalidAccessLog, key+": "+err.Error())
+	}
+	defer r.Close() //nolint:errcheck
+
+	entries, err := model.ParseCloudFrontLog(r)
+	if err != nil {
+		return nil, errfmt.Wrap(err, key)
+	}
+	return entries, nil
+}
@@ -0,0 +1,126 @@
+package interactor
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// MaintenanceSwitcherSet is a provider set for MaintenanceSwitcher.
+//
+//nolint:gochecknoglobals
+var MaintenanceSwitcherSet = wire.NewSet(
+	NewMaintenanceSwitcher,
+	wire.Struct(new(MaintenanceSwitcherOptions), "*"),
+	wire.Bind(new(usecase.MaintenanceSwitcher), new(*MaintenanceSwitcher)),
+)
+
+var _ usecase.MaintenanceSwitcher = (*MaintenanceSwitcher)(nil)
+
+// MaintenanceSwitcher is an implementation for MaintenanceSwitcher.
+type MaintenanceSwitcher struct {
+	opts *MaintenanceSwitcherOptions
+}
+
+// MaintenanceSwitcherOptions is an option struct for MaintenanceSwitcher.
+type MaintenanceSwitcherOptions struct {
+	service.CDNFinder
+	service.ReleaseHistoryGetter
+	service.FileUploader
+	service.MaintenancePutter
+	*ViewerFunctionOptions
+}
+
+// NewMaintenanceSwitcher returns a new MaintenanceSwitcher struct.
+func NewMaintenanceSwitcher(opts *MaintenanceSwitcherOptions) *MaintenanceSwitcher {
+	return &MaintenanceSwitcher{
+		opts: opts,
+	}
+}
+
+// SwitchMaintenance turns on or off the maintenance mode.
+// When it's turned on, the maintenance page is uploaded to the live release and the canary release before
+// the viewer request function is published, so the viewers never get the missing page.
+func (m *MaintenanceSwitcher) SwitchMaintenance(ctx context.Context, input *usecase.SwitchMaintenanceInput) (*usecase.SwitchMaintenanceOutput, error) {
+	cdn, err := m.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance := input.Maintenance
+	if maintenance != nil {
+		if maintenance, err = m.keepBypassToken(ctx, input.BucketName, maintenance); err != nil {
+			return nil, err
+		}
+		if err := m.uploadPage(ctx, input.BucketName, input.Page, input.Encryption); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := m.opts.MaintenancePutter.PutMaintenance(ctx, &service.MaintenancePutterInput{
+		Bucket:      input.BucketName,
+		Maintenance: maintenance,
+	}); err != nil {
+		return nil, err
+	}
+	if err := m.opts.applyViewerRequest(ctx, cdn.DistributionID, input.BucketName, input.ViewerRequest); err != nil {
+		return nil, err
+	}
+	return &usecase.SwitchMaintenanceOutput{
+		Domain:      cdn.Domain,
+		Maintenance: maintenance,
+	}, nil
+}
+
+// keepBypassToken returns a copy of maintenance whose bypass token is replaced with the current one
+// if the maintenance mode is already on. The viewers who have the bypass cookie keep bypassing it.
+func (m *MaintenanceSwitcher) keepBypassToken(ctx context.Context, bucket model.BucketName, maintenance *model.Maintenance) (*model.Maintenance, error) {
+	current, err := m.opts.MaintenanceGetter.GetMaintenance(ctx, &service.MaintenanceGetterInput{
+		Bucket: bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *maintenance
+	if current.Maintenance != nil {
+		copied.BypassToken = current.Maintenance.BypassToken
+		copied.StartedAt = current.Maintenance.StartedAt
+	}
+	return &copied, nil
+}
+
+// uploadPage uploads the maintenance page to the live release and the canary release.
+func (m *MaintenanceSwitcher) uploadPage(ctx context.Context, bucket model.BucketName, page []byte, encryption *model.Encryption) error {
+	history, err := m.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: bucket,
+	})
+	if err != nil {
+		return err
+	}
+	if history.History.Live.Empty() {
+		return errfmt.Wrap(model.ErrReleaseNotFound, "no release has been deployed yet. run 'spare deploy' first")
+	}
+
+	for _, id := range []model.ReleaseID{history.History.Live, history.History.Staged} {
+		if id.Empty() {
+			continue
+		}
+		if _, err := m.opts.FileUploader.UploadFile(ctx, &service.FileUploaderInput{
+			BucketName: bucket,
+			Key:        id.Prefix() + model.MaintenancePagePath,
+			Data:       bytes.NewReader(page),
+			Headers:    model.MaintenancePageHeaders(),
+			Encryption: encryption,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+)
+
+// OriginAccessSet is a provider set for OriginAccessOptions.
+//
+//nolint:gochecknoglobals
+var OriginAccessSet = wire.NewSet(
+	wire.Struct(new(OriginAccessOptions), "*"),
+)
+
+// OriginAccessOptions is an option struct for allowing only the CDNs of the bucket to read the objects.
+// It's shared by the interactors that create the CDN or the staging CDN.
+type OriginAccessOptions struct {
+	service.CDNARNsLister
+	service.BucketPolicySetter
+	service.EncryptionKeyPolicyApplier
+}
+
+// restrictOriginAccess scopes the bucket policy and, with SSE-KMS, the key policy of the KMS key to the CDNs
+// of the bucket (the CDN and the staging CDN for the canary release). It must be called again
+// after the staging CDN is created, because the staging CDN has its own ARN. If the CDN does not exist, it does nothing.
+func (o *OriginAccessOptions) restrictOriginAccess(ctx context.Context, bucket model.BucketName, encryption *model.Encryption) error {
+	output, err := o.CDNARNsLister.ListCDNARNs(ctx, &service.CDNARNsListerInput{
+		BucketName: bucket,
+	})
+	if err != nil {
+		return err
+	}
+	if len(output.ARNs) == 0 {
+		return nil
+	}
+
+	if _, err := o.BucketPolicySetter.SetBucketPolicy(ctx, &service.BucketPolicySetterInput{
+		Bucket: bucket,
+		Policy: model.NewAllowCloudFrontS3BucketPolicy(bucket, model.ARNPartition(output.ARNs[0]), output.ARNs),
+	}); err != nil {
+		return err
+	}
+	if !encryption.KMS() {
+		return nil
+	}
+	if _, err := o.EncryptionKeyPolicyApplier.ApplyEncryptionKeyPolicy(ctx, &service.EncryptionKeyPolicyApplierInput{
+		KeyID:     encryption.KMSKeyID,
+		Statement: model.NewAllowCloudFrontKMSKeyStatement(output.ARNs),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
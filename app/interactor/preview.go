@@ -0,0 +1,330 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// PreviewPublisherSet is a provider set for PreviewPublisher.
+//
+//nolint:gochecknoglobals
+var PreviewPublisherSet = wire.NewSet(
+	NewPreviewPublisher,
+	wire.Struct(new(PreviewPublisherOptions), "*"),
+	wire.Bind(new(usecase.PreviewPublisher), new(*PreviewPublisher)),
+)
+
+var _ usecase.PreviewPublisher = (*PreviewPublisher)(nil)
+
+// PreviewPublisher is an implementation for PreviewPublisher.
+type PreviewPublisher struct {
+	opts *PreviewPublisherOptions
+}
+
+// PreviewPublisherOptions is an option struct for PreviewPublisher.
+type PreviewPublisherOptions struct {
+	service.CDNFinder
+	service.CDNFunctionPublisher
+	service.CDNPreviewRouteCreator
+	service.CDNCacheInvalidator
+	service.BucketObjectLister
+	service.BucketObjectDeleter
+	service.PreviewListGetter
+	service.PreviewListPutter
+}
+
+// NewPreviewPublisher returns a new PreviewPublisher struct.
+func NewPreviewPublisher(opts *PreviewPublisherOptions) *PreviewPublisher {
+	return &PreviewPublisher{
+		opts: opts,
+	}
+}
+
+// PublishPreview routes the preview path to the uploaded files and records the preview.
+// The route (previews/* cache behavior) is created only at the first preview deploy.
+func (p *PreviewPublisher) PublishPreview(ctx context.Context, input *usecase.PublishPreviewInput) (*usecase.PublishPreviewOutput, error) {
+	cdn, err := p.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	function, err := p.opts.CDNFunctionPublisher.PublishCDNFunction(ctx, &service.CDNFunctionPublisherInput{
+		Function: model.NewPreviewRouterFunction(input.BucketName, input.BasicAuth),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.opts.CDNPreviewRouteCreator.CreateCDNPreviewRoute(ctx, &service.CDNPreviewRouteCreatorInput{
+		DistributionID:    cdn.DistributionID,
+		BucketName:        input.BucketName,
+		RouterFunctionARN: function.ARN,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := p.deleteStaleObjects(ctx, input); err != nil {
+		return nil, err
+	}
+
+	listOutput, err := p.opts.PreviewListGetter.GetPreviewList(ctx, &service.PreviewListGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	listOutput.List.Upsert(*input.Preview)
+	if _, err := p.opts.PreviewListPutter.PutPreviewList(ctx, &service.PreviewListPutterInput{
+		Bucket: input.BucketName,
+		List:   listOutput.List,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.opts.CDNCacheInvalidator.InvalidateCDNCache(ctx, &service.CDNCacheInvalidatorInput{
+		DistributionID: cdn.DistributionID,
+		Paths:          []string{input.Preview.Name.Path() + "*"},
+	}); err != nil {
+		return nil, err
+	}
+
+	return &usecase.PublishPreviewOutput{
+		URL: fmt.Sprintf("https://%s%s", cdn.Domain, input.Preview.Name.Path()),
+	}, nil
+}
+
+// deleteStaleObjects deletes the objects that were uploaded by the previous deploy of the preview.
+func (p *PreviewPublisher) deleteStaleObjects(ctx context.Context, input *usecase.PublishPreviewInput) error {
+	listOutput, err := p.opts.BucketObjectLister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+		Bucket: input.BucketName,
+		Prefix: input.Preview.Name.Prefix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	uploaded := make(map[string]bool, len(input.UploadedKeys))
+	for _, key := range input.UploadedKeys {
+		uploaded[key] = true
+	}
+	stale := make([]string, 0)
+	for _, o := range listOutput.Objects {
+		if !uploaded[o.Key] {
+			stale = append(stale, o.Key)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = p.opts.BucketObjectDeleter.DeleteBucketObjects(ctx, &service.BucketObjectDeleterInput{
+		Bucket: input.BucketName,
+		Keys:   stale,
+	})
+	return err
+}
+
+// PreviewListerSet is a provider set for PreviewLister.
+//
+//nolint:gochecknoglobals
+var PreviewListerSet = wire.NewSet(
+	NewPreviewLister,
+	wire.Struct(new(PreviewListerOptions), "*"),
+	wire.Bind(new(usecase.PreviewLister), new(*PreviewLister)),
+)
+
+var _ usecase.PreviewLister = (*PreviewLister)(nil)
+
+// PreviewLister is an implementation for PreviewLister.
+type PreviewLister struct {
+	opts *PreviewListerOptions
+}
+
+// PreviewListerOptions is an option struct for PreviewLister.
+type PreviewListerOptions struct {
+	service.PreviewListGetter
+	service.CDNFinder
+}
+
+// NewPreviewLister returns a new PreviewLister struct.
+func NewPreviewLister(opts *PreviewListerOptions) *PreviewLister {
+	return &PreviewLister{
+		opts: opts,
+	}
+}
+
+// ListPreviews returns the preview list.
+func (p *PreviewLister) ListPreviews(ctx context.Context, input *usecase.ListPreviewsInput) (*usecase.ListPreviewsOutput, error) {
+	listOutput, err := p.opts.PreviewListGetter.GetPreviewList(ctx, &service.PreviewListGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var domain model.Domain
+	cdn, err := p.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	switch {
+	case err == nil:
+		domain = cdn.Domain
+	case errors.Is(err, service.ErrCDNNotFound):
+		// not error. previews are listed without URL.
+	default:
+		return nil, err
+	}
+
+	return &usecase.ListPreviewsOutput{
+		List:   listOutput.List,
+		Domain: domain,
+	}, nil
+}
+
+// PreviewRemoverSet is a provider set for PreviewRemoverOptions.
+//
+//nolint:gochecknoglobals
+var PreviewRemoverSet = wire.NewSet(
+	wire.Struct(new(PreviewRemoverOptions), "*"),
+)
+
+// PreviewRemoverOptions is an option struct for removing previews.
+// It's shared by PreviewDeleter and PreviewExpirer.
+type PreviewRemoverOptions struct {
+	service.PreviewListGetter
+	service.PreviewListPutter
+	service.BucketObjectLister
+	service.BucketObjectDeleter
+}
+
+// removePreviews removes the previews from the preview list, and then deletes their files.
+func (o *PreviewRemoverOptions) removePreviews(ctx context.Context, bucket model.BucketName, list *model.PreviewList, names []model.PreviewName) (model.BucketObjects, error) {
+	for _, name := range names {
+		list.Remove(name)
+	}
+	if _, err := o.PreviewListPutter.PutPreviewList(ctx, &service.PreviewListPutterInput{
+		Bucket: bucket,
+		List:   list,
+	}); err != nil {
+		return nil, err
+	}
+
+	deleted := make(model.BucketObjects, 0)
+	for _, name := range names {
+		listOutput, err := o.BucketObjectLister.ListBucketObjects(ctx, &service.BucketObjectListerInput{
+			Bucket: bucket,
+			Prefix: name.Prefix(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(listOutput.Objects) == 0 {
+			continue
+		}
+		if _, err := o.BucketObjectDeleter.DeleteBucketObjects(ctx, &service.BucketObjectDeleterInput{
+			Bucket: bucket,
+			Keys:   listOutput.Objects.Keys(),
+		}); err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, listOutput.Objects...)
+	}
+	return deleted, nil
+}
+
+// PreviewDeleterSet is a provider set for PreviewDeleter.
+//
+//nolint:gochecknoglobals
+var PreviewDeleterSet = wire.NewSet(
+	NewPreviewDeleter,
+	wire.Bind(new(usecase.PreviewDeleter), new(*PreviewDeleter)),
+)
+
+var _ usecase.PreviewDeleter = (*PreviewDeleter)(nil)
+
+// PreviewDeleter is an implementation for PreviewDeleter.
+type PreviewDeleter struct {
+	opts *PreviewRemoverOptions
+}
+
+// NewPreviewDeleter returns a new PreviewDeleter struct.
+func NewPreviewDeleter(opts *PreviewRemoverOptions) *PreviewDeleter {
+	return &PreviewDeleter{
+		opts: opts,
+	}
+}
+
+// DeletePreviews deletes the files of the previews and removes them from the preview list.
+// The files are deleted even if the preview is not in the preview list.
+func (p *PreviewDeleter) DeletePreviews(ctx context.Context, input *usecase.DeletePreviewsInput) (*usecase.DeletePreviewsOutput, error) {
+	listOutput, err := p.opts.PreviewListGetter.GetPreviewList(ctx, &service.PreviewListGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := p.opts.removePreviews(ctx, input.BucketName, listOutput.List, input.Names)
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.DeletePreviewsOutput{
+		Objects: deleted,
+	}, nil
+}
+
+// PreviewExpirerSet is a provider set for PreviewExpirer.
+//
+//nolint:gochecknoglobals
+var PreviewExpirerSet = wire.NewSet(
+	NewPreviewExpirer,
+	wire.Bind(new(usecase.PreviewExpirer), new(*PreviewExpirer)),
+)
+
+var _ usecase.PreviewExpirer = (*PreviewExpirer)(nil)
+
+// PreviewExpirer is an implementation for PreviewExpirer.
+type PreviewExpirer struct {
+	opts *PreviewRemoverOptions
+}
+
+// NewPreviewExpirer returns a new PreviewExpirer struct.
+func NewPreviewExpirer(opts *PreviewRemoverOptions) *PreviewExpirer {
+	return &PreviewExpirer{
+		opts: opts,
+	}
+}
+
+// ExpirePreviews deletes the previews that have not been deployed for TTL.
+func (p *PreviewExpirer) ExpirePreviews(ctx context.Context, input *usecase.ExpirePreviewsInput) (*usecase.ExpirePreviewsOutput, error) {
+	listOutput, err := p.opts.PreviewListGetter.GetPreviewList(ctx, &service.PreviewListGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stale := listOutput.List.Stale(input.TTL, input.Now)
+	if len(stale) == 0 {
+		return &usecase.ExpirePreviewsOutput{Expired: stale}, nil
+	}
+
+	names := make([]model.PreviewName, 0, len(stale))
+	for _, preview := range stale {
+		names = append(names, preview.Name)
+	}
+	if _, err := p.opts.removePreviews(ctx, input.BucketName, listOutput.List, names); err != nil {
+		return nil, err
+	}
+	return &usecase.ExpirePreviewsOutput{
+		Expired: stale,
+	}, nil
+}
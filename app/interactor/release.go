@@ -0,0 +1,271 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// ReleaseSwitcherSet is a provider set for ReleaseSwitcherOptions.
+//
+//nolint:gochecknoglobals
+var ReleaseSwitcherSet = wire.NewSet(
+	wire.Struct(new(ReleaseSwitcherOptions), "*"),
+)
+
+// ReleaseSwitcherOptions is an option struct for switching the live release.
+// It's shared by the interactors that change the live release.
+type ReleaseSwitcherOptions struct {
+	service.ReleaseHistoryGetter
+	service.ReleaseHistoryPutter
+	service.CDNFinder
+	service.CDNOriginPathUpdater
+	service.CDNCacheInvalidator
+}
+
+// switchRelease makes the release live.
+// The CDN is switched first, and then the release history is updated.
+// So, the release history never points to a release that CloudFront does not serve.
+func (o *ReleaseSwitcherOptions) switchRelease(ctx context.Context, bucket model.BucketName, history *model.ReleaseHistory, id model.ReleaseID) (model.Domain, error) {
+	cdn, err := o.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: bucket,
+	})
+	if err != nil {
+		return "", err
+	}
+	return o.switchCDN(ctx, bucket, history, id, cdn)
+}
+
+// switchCDN switches the CDN to the release, and then records the release history.
+func (o *ReleaseSwitcherOptions) switchCDN(ctx context.Context, bucket model.BucketName, history *model.ReleaseHistory, id model.ReleaseID, cdn *service.CDNFinderOutput) (model.Domain, error) {
+	if err := history.Activate(id); err != nil {
+		return "", err
+	}
+
+	if _, err := o.CDNOriginPathUpdater.UpdateCDNOriginPath(ctx, &service.CDNOriginPathUpdaterInput{
+		DistributionID: cdn.DistributionID,
+		BucketName:     bucket,
+		OriginPath:     id.OriginPath(),
+	}); err != nil {
+		return "", err
+	}
+
+	// CloudFront caches objects by the viewer path, not the origin path.
+	// Without the invalidation, users keep getting the old release until the cache expires.
+	if _, err := o.CDNCacheInvalidator.InvalidateCDNCache(ctx, &service.CDNCacheInvalidatorInput{
+		DistributionID: cdn.DistributionID,
+		Paths:          []string{"/*"},
+	}); err != nil {
+		return "", err
+	}
+
+	if _, err := o.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+		Bucket:  bucket,
+		History: history,
+	}); err != nil {
+		return "", err
+	}
+	return cdn.Domain, nil
+}
+
+// ReleasePublisherSet is a provider set for ReleasePublisher.
+//
+//nolint:gochecknoglobals
+var ReleasePublisherSet = wire.NewSet(
+	NewReleasePublisher,
+	wire.Bind(new(usecase.ReleasePublisher), new(*ReleasePublisher)),
+)
+
+var _ usecase.ReleasePublisher = (*ReleasePublisher)(nil)
+
+// ReleasePublisher is an implementation for ReleasePublisher.
+type ReleasePublisher struct {
+	opts *ReleaseSwitcherOptions
+}
+
+// NewReleasePublisher returns a new ReleasePublisher struct.
+func NewReleasePublisher(opts *ReleaseSwitcherOptions) *ReleasePublisher {
+	return &ReleasePublisher{
+		opts: opts,
+	}
+}
+
+// PublishRelease records the release in the release history and switches the CDN to the release.
+// If the CDN does not exist and input.AllowNoCDN is true, the release is only recorded and it is not live.
+// While the canary release is in progress, it returns ErrCanaryInProgress.
+func (r *ReleasePublisher) PublishRelease(ctx context.Context, input *usecase.PublishReleaseInput) (*usecase.PublishReleaseOutput, error) {
+	output, err := r.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := output.History.ValidateNotStaged(); err != nil {
+		return nil, err
+	}
+	output.History.Add(*input.Release)
+
+	cdn, err := r.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		if !input.AllowNoCDN || !errors.Is(err, service.ErrCDNNotFound) {
+			return nil, err
+		}
+		if _, err := r.opts.ReleaseHistoryPutter.PutReleaseHistory(ctx, &service.ReleaseHistoryPutterInput{
+			Bucket:  input.BucketName,
+			History: output.History,
+		}); err != nil {
+			return nil, err
+		}
+		return &usecase.PublishReleaseOutput{}, nil
+	}
+
+	domain, err := r.opts.switchCDN(ctx, input.BucketName, output.History, input.Release.ID, cdn)
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.PublishReleaseOutput{
+		Domain:   domain,
+		Switched: true,
+	}, nil
+}
+
+// ReleaseManifestRecorderSet is a provider set for ReleaseManifestRecorder.
+//
+//nolint:gochecknoglobals
+var ReleaseManifestRecorderSet = wire.NewSet(
+	NewReleaseManifestRecorder,
+	wire.Struct(new(ReleaseManifestRecorderOptions), "*"),
+	wire.Bind(new(usecase.ReleaseManifestRecorder), new(*ReleaseManifestRecorder)),
+)
+
+var _ usecase.ReleaseManifestRecorder = (*ReleaseManifestRecorder)(nil)
+
+// ReleaseManifestRecorder is an implementation for ReleaseManifestRecorder.
+type ReleaseManifestRecorder struct {
+	opts *ReleaseManifestRecorderOptions
+}
+
+// ReleaseManifestRecorderOptions is an option struct for ReleaseManifestRecorder.
+type ReleaseManifestRecorderOptions struct {
+	service.ReleaseManifestPutter
+}
+
+// NewReleaseManifestRecorder returns a new ReleaseManifestRecorder struct.
+func NewReleaseManifestRecorder(opts *ReleaseManifestRecorderOptions) *ReleaseManifestRecorder {
+	return &ReleaseManifestRecorder{
+		opts: opts,
+	}
+}
+
+// RecordReleaseManifest puts the release manifest.
+func (r *ReleaseManifestRecorder) RecordReleaseManifest(ctx context.Context, input *usecase.RecordReleaseManifestInput) (*usecase.RecordReleaseManifestOutput, error) {
+	if _, err := r.opts.ReleaseManifestPutter.PutReleaseManifest(ctx, &service.ReleaseManifestPutterInput{
+		Bucket:   input.BucketName,
+		Manifest: input.Manifest,
+	}); err != nil {
+		return nil, err
+	}
+	return &usecase.RecordReleaseManifestOutput{}, nil
+}
+
+// ReleaseListerSet is a provider set for ReleaseLister.
+//
+//nolint:gochecknoglobals
+var ReleaseListerSet = wire.NewSet(
+	NewReleaseLister,
+	wire.Struct(new(ReleaseListerOptions), "*"),
+	wire.Bind(new(usecase.ReleaseLister), new(*ReleaseLister)),
+)
+
+var _ usecase.ReleaseLister = (*ReleaseLister)(nil)
+
+// ReleaseLister is an implementation for ReleaseLister.
+type ReleaseLister struct {
+	opts *ReleaseListerOptions
+}
+
+// ReleaseListerOptions is an option struct for ReleaseLister.
+type ReleaseListerOptions struct {
+	service.ReleaseHistoryGetter
+}
+
+// NewReleaseLister returns a new ReleaseLister struct.
+func NewReleaseLister(opts *ReleaseListerOptions) *ReleaseLister {
+	return &ReleaseLister{
+		opts: opts,
+	}
+}
+
+// ListReleases returns the release history.
+func (r *ReleaseLister) ListReleases(ctx context.Context, input *usecase.ListReleasesInput) (*usecase.ListReleasesOutput, error) {
+	output, err := r.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.ListReleasesOutput{
+		History: output.History,
+	}, nil
+}
+
+// ReleaseRollbackerSet is a provider set for ReleaseRollbacker.
+//
+//nolint:gochecknoglobals
+var ReleaseRollbackerSet = wire.NewSet(
+	NewReleaseRollbacker,
+	wire.Bind(new(usecase.ReleaseRollbacker), new(*ReleaseRollbacker)),
+)
+
+var _ usecase.ReleaseRollbacker = (*ReleaseRollbacker)(nil)
+
+// ReleaseRollbacker is an implementation for ReleaseRollbacker.
+type ReleaseRollbacker struct {
+	opts *ReleaseSwitcherOptions
+}
+
+// NewReleaseRollbacker returns a new ReleaseRollbacker struct.
+func NewReleaseRollbacker(opts *ReleaseSwitcherOptions) *ReleaseRollbacker {
+	return &ReleaseRollbacker{
+		opts: opts,
+	}
+}
+
+// RollbackRelease switches the CDN to the release.
+// The files of the release are already in the bucket, so rollback does not upload anything.
+// While the canary release is in progress, it returns ErrCanaryInProgress.
+func (r *ReleaseRollbacker) RollbackRelease(ctx context.Context, input *usecase.RollbackReleaseInput) (*usecase.RollbackReleaseOutput, error) {
+	output, err := r.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := output.History
+	if err := history.ValidateNotStaged(); err != nil {
+		return nil, err
+	}
+
+	var target *model.Release
+	if input.ID.Empty() {
+		target, err = history.Previous()
+	} else {
+		target, err = history.Find(input.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.opts.switchRelease(ctx, input.BucketName, history, target.ID); err != nil {
+		return nil, err
+	}
+	return &usecase.RollbackReleaseOutput{
+		Release: target,
+	}, nil
+}
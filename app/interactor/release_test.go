@@ -0,0 +1,194 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// fakeReleaseHistoryStore is the release history in memory.
+type fakeReleaseHistoryStore struct {
+	history *model.ReleaseHistory
+	puts    int
+}
+
+func (f *fakeReleaseHistoryStore) GetReleaseHistory(_ context.Context, _ *service.ReleaseHistoryGetterInput) (*service.ReleaseHistoryGetterOutput, error) {
+	if f.history == nil {
+		f.history = model.NewReleaseHistory()
+	}
+	return &service.ReleaseHistoryGetterOutput{History: f.history}, nil
+}
+
+func (f *fakeReleaseHistoryStore) PutReleaseHistory(_ context.Context, input *service.ReleaseHistoryPutterInput) (*service.ReleaseHistoryPutterOutput, error) {
+	f.history = input.History
+	f.puts++
+	return &service.ReleaseHistoryPutterOutput{}, nil
+}
+
+// fakeCDN is the CDN that records the origin path. If cdn is nil, the CDN does not exist.
+type fakeCDN struct {
+	cdn        *service.CDNFinderOutput
+	originPath string
+}
+
+func (f *fakeCDN) FindCDN(_ context.Context, _ *service.CDNFinderInput) (*service.CDNFinderOutput, error) {
+	if f.cdn == nil {
+		return nil, service.ErrCDNNotFound
+	}
+	return f.cdn, nil
+}
+
+func (f *fakeCDN) UpdateCDNOriginPath(_ context.Context, input *service.CDNOriginPathUpdaterInput) (*service.CDNOriginPathUpdaterOutput, error) {
+	f.originPath = input.OriginPath
+	return &service.CDNOriginPathUpdaterOutput{}, nil
+}
+
+func (f *fakeCDN) InvalidateCDNCache(_ context.Context, _ *service.CDNCacheInvalidatorInput) (*service.CDNCacheInvalidatorOutput, error) {
+	return &service.CDNCacheInvalidatorOutput{}, nil
+}
+
+func TestReleasePublisherPublishRelease(t *testing.T) {
+	t.Parallel()
+
+	release := model.NewRelease(time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC), "abc123", "alice")
+	tests := []struct {
+		name           string
+		cdn            *service.CDNFinderOutput
+		allowNoCDN     bool
+		wantErr        error
+		wantSwitched   bool
+		wantLive       model.ReleaseID
+		wantOriginPath string
+		wantPuts       int
+	}{
+		{
+			name:           "success. cloudfront is switched",
+			cdn:            &service.CDNFinderOutput{DistributionID: "E123", Domain: "d123.cloudfront.net"},
+			allowNoCDN:     false,
+			wantErr:        nil,
+			wantSwitched:   true,
+			wantLive:       release.ID,
+			wantOriginPath: release.ID.OriginPath(),
+			wantPuts:       1,
+		},
+		{
+			name:           "success. no cdn in debug mode records the release",
+			cdn:            nil,
+			allowNoCDN:     true,
+			wantErr:        nil,
+			wantSwitched:   false,
+			wantLive:       "",
+			wantOriginPath: "",
+			wantPuts:       1,
+		},
+		{
+			name:           "failure. no cdn",
+			cdn:            nil,
+			allowNoCDN:     false,
+			wantErr:        service.ErrCDNNotFound,
+			wantSwitched:   false,
+			wantLive:       "",
+			wantOriginPath: "",
+			wantPuts:       0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeReleaseHistoryStore{}
+			cdn := &fakeCDN{cdn: tt.cdn}
+			publisher := NewReleasePublisher(&ReleaseSwitcherOptions{
+				ReleaseHistoryGetter: store,
+				ReleaseHistoryPutter: store,
+				CDNFinder:            cdn,
+				CDNOriginPathUpdater: cdn,
+				CDNCacheInvalidator:  cdn,
+			})
+			output, err := publisher.PublishRelease(context.Background(), &usecase.PublishReleaseInput{
+				BucketName: "spare-bucket",
+				Release:    release,
+				AllowNoCDN: tt.allowNoCDN,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReleasePublisher.PublishRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && output.Switched != tt.wantSwitched {
+				t.Errorf("ReleasePublisher.PublishRelease() switched = %t, want %t", output.Switched, tt.wantSwitched)
+			}
+			if cdn.originPath != tt.wantOriginPath {
+				t.Errorf("origin path = %s, want %s", cdn.originPath, tt.wantOriginPath)
+			}
+			if got := store.puts; got != tt.wantPuts {
+				t.Errorf("release history is put %d times, want %d", got, tt.wantPuts)
+			}
+			if store.history != nil && store.history.Live != tt.wantLive {
+				t.Errorf("live release = %s, want %s", store.history.Live, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestReleaseSwitchDuringCanary(t *testing.T) {
+	t.Parallel()
+
+	live := model.NewRelease(time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC), "abc123", "alice")
+	staged := model.NewRelease(time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC), "def456", "bob")
+	newHistory := func() *model.ReleaseHistory {
+		history := model.NewReleaseHistory()
+		history.Add(*live)
+		history.Add(*staged)
+		history.Live = live.ID
+		history.Staged = staged.ID
+		return history
+	}
+	newOptions := func(store *fakeReleaseHistoryStore, cdn *fakeCDN) *ReleaseSwitcherOptions {
+		return &ReleaseSwitcherOptions{
+			ReleaseHistoryGetter: store,
+			ReleaseHistoryPutter: store,
+			CDNFinder:            cdn,
+			CDNOriginPathUpdater: cdn,
+			CDNCacheInvalidator:  cdn,
+		}
+	}
+
+	t.Run("failure. deploy during canary", func(t *testing.T) {
+		t.Parallel()
+
+		store := &fakeReleaseHistoryStore{history: newHistory()}
+		cdn := &fakeCDN{cdn: &service.CDNFinderOutput{DistributionID: "E123", Domain: "d123.cloudfront.net"}}
+		_, err := NewReleasePublisher(newOptions(store, cdn)).PublishRelease(context.Background(), &usecase.PublishReleaseInput{
+			BucketName: "spare-bucket",
+			Release:    model.NewRelease(time.Date(2023, 10, 21, 12, 0, 0, 0, time.UTC), "ghi789", "carol"),
+		})
+		if !errors.Is(err, model.ErrCanaryInProgress) {
+			t.Fatalf("ReleasePublisher.PublishRelease() error = %v, wantErr %v", err, model.ErrCanaryInProgress)
+		}
+		if cdn.originPath != "" || store.puts != 0 {
+			t.Errorf("origin path = %s and release history is put %d times, want no change", cdn.originPath, store.puts)
+		}
+	})
+
+	t.Run("failure. rollback during canary", func(t *testing.T) {
+		t.Parallel()
+
+		store := &fakeReleaseHistoryStore{history: newHistory()}
+		cdn := &fakeCDN{cdn: &service.CDNFinderOutput{DistributionID: "E123", Domain: "d123.cloudfront.net"}}
+		_, err := NewReleaseRollbacker(newOptions(store, cdn)).RollbackRelease(context.Background(), &usecase.RollbackReleaseInput{
+			BucketName: "spare-bucket",
+			ID:         live.ID,
+		})
+		if !errors.Is(err, model.ErrCanaryInProgress) {
+			t.Fatalf("ReleaseRollbacker.RollbackRelease() error = %v, wantErr %v", err, model.ErrCanaryInProgress)
+		}
+		if cdn.originPath != "" || store.puts != 0 {
+			t.Errorf("origin path = %s and release history is put %d times, want no change", cdn.originPath, store.puts)
+		}
+	})
+}
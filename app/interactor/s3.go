@@ -33,6 +33,15 @@ type StorageCreatorOptions struct {
 	service.BucketCreator
 	service.BucketPublicAccessBlocker
 	service.BucketPolicySetter
+	service.BucketCORSSetter
+	service.BucketOwnershipSetter
+	service.BucketLifecycleSetter
+	service.BucketLoggingSetter
+	service.BucketVersioningSetter
+	service.BucketEncryptionSetter
+	service.EncryptionKeyCreator
+	service.ResourceTagger
+	service.CDNARNsLister
 }
 
 // NewStorageCreator returns a new StorageCreator struct.
@@ -55,6 +64,9 @@ func (s *StorageCreator) CreateStorage(ctx context.Context, input *usecase.Creat
 			return nil, err
 		}
 	}
+	if input.Storage != nil {
+		return s.applyCompatibleStorage(ctx, input)
+	}
 
 	if _, err := s.opts.BucketPublicAccessBlocker.BlockBucketPublicAccess(ctx, &service.BucketPublicAccessBlockerInput{
 		Bucket: input.BucketName,
@@ -63,16 +75,208 @@ func (s *StorageCreator) CreateStorage(ctx context.Context, input *usecase.Creat
 		return nil, err
 	}
 
+	// The policy is scoped to the existing CDNs, so running 'spare build' again does not open the bucket to other CDNs.
+	cdns, err := s.opts.CDNARNsLister.ListCDNARNs(ctx, &service.CDNARNsListerInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
 	if _, err := s.opts.BucketPolicySetter.SetBucketPolicy(ctx, &service.BucketPolicySetterInput{
 		Bucket: input.BucketName,
-		Policy: model.NewAllowCloudFrontS3BucketPolicy(input.BucketName),
+		Policy: model.NewAllowCloudFrontS3BucketPolicy(input.BucketName, input.Region.Partition(), cdns.ARNs),
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.opts.BucketCORSSetter.SetBucketCORS(ctx, &service.BucketCORSSetterInput{
+		Bucket: input.BucketName,
+		CORS:   input.CORS,
 	}); err != nil {
 		return nil, err
 	}
 
+	output := &usecase.CreateStorageOutput{}
+	if input.Settings != nil {
+		if err := s.applyBucketSettings(ctx, input.BucketName, input.Settings, output); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Encryption != nil {
+		if err := s.applyEncryption(ctx, input.BucketName, input.Encryption, output); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Logging != nil {
+		if err := s.createLogBucket(ctx, input); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.opts.BucketLoggingSetter.SetBucketLogging(ctx, &service.BucketLoggingSetterInput{
+		Bucket:  input.BucketName,
+		Logging: input.Logging,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagStorage(ctx, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// applyCompatibleStorage applies the public-read policy and the CORS rules to the bucket in the S3-compatible storage.
+// The storage serves the bucket without CloudFront, so the site at the root must be public, but the policy is applied
+// only when the user opts in with PublicRead. R2 has no bucket policy, and the public access is enabled in the
+// Cloudflare dashboard. If CORS is nil, the CORS rules are left as they are,
+// because some storage (e.g. MinIO) has no CORS API.
+func (s *StorageCreator) applyCompatibleStorage(ctx context.Context, input *usecase.CreateStorageInput) (*usecase.CreateStorageOutput, error) {
+	if input.PublicRead && input.Storage.Provider != model.StorageProviderR2 {
+		if _, err := s.opts.BucketPolicySetter.SetBucketPolicy(ctx, &service.BucketPolicySetterInput{
+			Bucket: input.BucketName,
+			Policy: model.NewPublicReadS3BucketPolicy(input.BucketName),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.CORS != nil {
+		if _, err := s.opts.BucketCORSSetter.SetBucketCORS(ctx, &service.BucketCORSSetterInput{
+			Bucket: input.BucketName,
+			CORS:   input.CORS,
+		}); err != nil {
+			return nil, err
+		}
+	}
 	return &usecase.CreateStorageOutput{}, nil
 }
 
+// tagStorage adds the tags to the bucket, the log bucket and the KMS key that spare creates.
+// The KMS key that the user specifies is not tagged.
+func (s *StorageCreator) tagStorage(ctx context.Context, input *usecase.CreateStorageInput, output *usecase.CreateStorageOutput) error {
+	if len(input.Tags) == 0 {
+		return nil
+	}
+	partition := input.Region.Partition()
+	arns := []string{input.BucketName.ARN(partition)}
+	if input.Logging != nil {
+		arns = append(arns, input.Logging.Bucket.ARN(partition))
+	}
+	if input.Encryption.KMS() && input.Encryption.CreateKey {
+		arns = append(arns, output.EncryptionKeyARN.String())
+	}
+	if _, err := s.opts.ResourceTagger.TagResources(ctx, &service.ResourceTaggerInput{
+		ARNs: arns,
+		Tags: input.Tags,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyBucketSettings reconciles the object ownership, the versioning and the lifecycle rules of the bucket one by one,
+// and reports the result of each setting in the output.
+func (s *StorageCreator) applyBucketSettings(ctx context.Context, bucket model.BucketName, settings *model.BucketSettings, output *usecase.CreateStorageOutput) error {
+	if settings.Ownership != "" {
+		if _, err := s.opts.BucketOwnershipSetter.SetBucketOwnership(ctx, &service.BucketOwnershipSetterInput{
+			Bucket:    bucket,
+			Ownership: settings.Ownership,
+		}); err != nil {
+			return err
+		}
+		output.Ownership = settings.Ownership
+	}
+
+	versioning, err := s.opts.BucketVersioningSetter.SetBucketVersioning(ctx, &service.BucketVersioningSetterInput{
+		Bucket:  bucket,
+		Enabled: settings.Versioning,
+	})
+	if err != nil {
+		return err
+	}
+	output.Versioning = versioning.Status
+
+	if _, err := s.opts.BucketLifecycleSetter.SetBucketLifecycle(ctx, &service.BucketLifecycleSetterInput{
+		Bucket: bucket,
+		Rules:  settings.LifecycleRules,
+	}); err != nil {
+		return err
+	}
+	output.LifecycleRules = len(settings.LifecycleRules)
+	return nil
+}
+
+// applyEncryption sets the default encryption of the bucket. If the KMS key should be created, it creates the KMS key
+// with the alias, and the bucket refers to the KMS key by the ARN.
+func (s *StorageCreator) applyEncryption(ctx context.Context, bucket model.BucketName, encryption *model.Encryption, output *usecase.CreateStorageOutput) error {
+	resolved := *encryption
+	if encryption.KMS() {
+		if encryption.CreateKey {
+			key, err := s.opts.EncryptionKeyCreator.CreateEncryptionKey(ctx, &service.EncryptionKeyCreatorInput{
+				Alias:       encryption.KMSKeyID,
+				Description: "KMS key generated by spare for " + bucket.String(),
+			})
+			if err != nil {
+				return err
+			}
+			resolved.KMSKeyID = key.ARN.String()
+			output.EncryptionKeyCreated = key.Created
+		}
+		output.EncryptionKeyARN = model.KMSKeyARN(resolved.KMSKeyID)
+	}
+
+	if _, err := s.opts.BucketEncryptionSetter.SetBucketEncryption(ctx, &service.BucketEncryptionSetterInput{
+		Bucket:     bucket,
+		Encryption: &resolved,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// createLogBucket creates the log bucket that receives the CloudFront standard logs and the S3 server access logs.
+// CloudFront delivers the logs with ACLs, so the log bucket keeps ACLs enabled (BucketOwnerPreferred).
+func (s *StorageCreator) createLogBucket(ctx context.Context, input *usecase.CreateStorageInput) error {
+	logBucket := input.Logging.Bucket
+	if _, err := s.opts.BucketCreator.CreateBucket(ctx, &service.BucketCreatorInput{
+		Bucket: logBucket,
+		Region: input.Region,
+	}); err != nil {
+		if !errors.Is(err, service.ErrBucketAlreadyOwnedByYou) {
+			return err
+		}
+		log.Info("you already create the log bucket", "bucket name", logBucket.String())
+	}
+
+	if _, err := s.opts.BucketPublicAccessBlocker.BlockBucketPublicAccess(ctx, &service.BucketPublicAccessBlockerInput{
+		Bucket: logBucket,
+		Region: input.Region,
+	}); err != nil {
+		return err
+	}
+	if _, err := s.opts.BucketOwnershipSetter.SetBucketOwnership(ctx, &service.BucketOwnershipSetterInput{
+		Bucket:    logBucket,
+		Ownership: model.ObjectOwnershipBucketOwnerPreferred,
+	}); err != nil {
+		return err
+	}
+	if _, err := s.opts.BucketLifecycleSetter.SetBucketLifecycle(ctx, &service.BucketLifecycleSetterInput{
+		Bucket: logBucket,
+		Rules:  input.Logging.LifecycleRules(),
+	}); err != nil {
+		return err
+	}
+	if _, err := s.opts.BucketPolicySetter.SetBucketPolicy(ctx, &service.BucketPolicySetterInput{
+		Bucket: logBucket,
+		Policy: model.NewS3AccessLogsBucketPolicy(logBucket, input.BucketName, input.Region.Partition()),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
 // FileUploaderSet is a provider set for FileUploader.
 //
 //nolint:gochecknoglobals
@@ -107,6 +311,8 @@ func (u *FileUploader) UploadFile(ctx context.Context, input *usecase.UploadFile
 		BucketName: input.BucketName,
 		Key:        input.Key,
 		Data:       input.Data,
+		Headers:    input.Headers,
+		Encryption: input.Encryption,
 	})
 	if err != nil {
 		return nil, err
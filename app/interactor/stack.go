@@ -0,0 +1,51 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// StackListerSet is a provider set for StackLister.
+//
+//nolint:gochecknoglobals
+var StackListerSet = wire.NewSet(
+	NewStackLister,
+	wire.Struct(new(StackListerOptions), "*"),
+	wire.Bind(new(usecase.StackLister), new(*StackLister)),
+)
+
+var _ usecase.StackLister = (*StackLister)(nil)
+
+// StackLister is an implementation for StackLister.
+type StackLister struct {
+	opts *StackListerOptions
+}
+
+// StackListerOptions is an option struct for StackLister.
+type StackListerOptions struct {
+	service.TaggedResourceLister
+}
+
+// NewStackLister returns a new StackLister struct.
+func NewStackLister(opts *StackListerOptions) *StackLister {
+	return &StackLister{
+		opts: opts,
+	}
+}
+
+// ListStacks lists the resources that have the spare:project tag, and groups them by the project.
+func (s *StackLister) ListStacks(ctx context.Context, _ *usecase.ListStacksInput) (*usecase.ListStacksOutput, error) {
+	output, err := s.opts.TaggedResourceLister.ListTaggedResources(ctx, &service.TaggedResourceListerInput{
+		TagKey: model.TagKeyProject,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.ListStacksOutput{
+		Stacks: model.NewStacks(output.Resources),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// StatusGetterSet is a provider set for StatusGetter.
+//
+//nolint:gochecknoglobals
+var StatusGetterSet = wire.NewSet(
+	NewStatusGetter,
+	wire.Struct(new(StatusGetterOptions), "*"),
+	wire.Bind(new(usecase.StatusGetter), new(*StatusGetter)),
+)
+
+var _ usecase.StatusGetter = (*StatusGetter)(nil)
+
+// StatusGetter is an implementation for StatusGetter.
+type StatusGetter struct {
+	opts *StatusGetterOptions
+}
+
+// StatusGetterOptions is an option struct for StatusGetter.
+type StatusGetterOptions struct {
+	service.CDNFinder
+	service.ReleaseHistoryGetter
+	service.CDNResponseHeadersGetter
+	service.MaintenanceGetter
+}
+
+// NewStatusGetter returns a new StatusGetter struct.
+func NewStatusGetter(opts *StatusGetterOptions) *StatusGetter {
+	return &StatusGetter{
+		opts: opts,
+	}
+}
+
+// GetStatus returns the status of the CDN and the releases.
+func (s *StatusGetter) GetStatus(ctx context.Context, input *usecase.GetStatusInput) (*usecase.GetStatusOutput, error) {
+	cdn, err := s.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.opts.ReleaseHistoryGetter.GetReleaseHistory(ctx, &service.ReleaseHistoryGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := s.opts.CDNResponseHeadersGetter.GetCDNResponseHeaders(ctx, &service.CDNResponseHeadersGetterInput{
+		DistributionID: cdn.DistributionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance, err := s.opts.MaintenanceGetter.GetMaintenance(ctx, &service.MaintenanceGetterInput{
+		Bucket: input.BucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.GetStatusOutput{
+		DistributionID:        cdn.DistributionID,
+		Domain:                cdn.Domain,
+		Live:                  history.History.Live,
+		Staged:                history.History.Staged,
+		ResponseHeadersPolicy: headers.PolicyName,
+		ResponseHeaders:       headers.Headers,
+		Maintenance:           maintenance.Maintenance,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/wire"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/domain/service"
+	"github.com/nao1215/spare/app/usecase"
+)
+
+// ViewerFunctionSet is a provider set for ViewerFunctionOptions.
+//
+//nolint:gochecknoglobals
+var ViewerFunctionSet = wire.NewSet(
+	wire.Struct(new(ViewerFunctionOptions), "*"),
+)
+
+// ViewerFunctionOptions is an option struct for applying the viewer request function.
+// It's shared by the interactors that change the features of the viewer request function.
+type ViewerFunctionOptions struct {
+	service.CDNFunctionPublisher
+	service.CDNViewerFunctionAssociator
+	service.MaintenanceGetter
+	service.ViewerRulesPutter
+}
+
+// applyViewerRequest publishes the viewer request function and associates it with the CDN.
+// If no feature is enabled, the function is disassociated from the CDN.
+// The maintenance mode is read from the bucket, and the viewer response function is associated while it's on.
+// The preview router function is published again if the CDN has the route for previews,
+// because the previews are protected by the same basic auth.
+// The rules of v are recorded in the bucket after the function is associated, so they are always the live rules.
+func (o *ViewerFunctionOptions) applyViewerRequest(ctx context.Context, id model.DistributionID, bucket model.BucketName, v *model.ViewerRequest) error {
+	maintenance, err := o.MaintenanceGetter.GetMaintenance(ctx, &service.MaintenanceGetterInput{
+		Bucket: bucket,
+	})
+	if err != nil {
+		return err
+	}
+	v = v.WithMaintenance(maintenance.Maintenance)
+
+	function, err := model.NewViewerRequestFunction(bucket, v)
+	if err != nil {
+		return err
+	}
+	arn, err := o.publishCDNFunction(ctx, function)
+	if err != nil {
+		return err
+	}
+	responseARN := ""
+	if v.Maintenance != nil {
+		if responseARN, err = o.publishCDNFunction(ctx, model.NewMaintenanceResponseFunction(bucket, v.Maintenance)); err != nil {
+			return err
+		}
+	}
+
+	output, err := o.CDNViewerFunctionAssociator.AssociateCDNViewerFunction(ctx, &service.CDNViewerFunctionAssociatorInput{
+		DistributionID:      id,
+		FunctionARN:         arn,
+		ResponseFunctionARN: responseARN,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := o.ViewerRulesPutter.PutViewerRules(ctx, &service.ViewerRulesPutterInput{
+		Bucket: bucket,
+		Rules:  v.Rules(),
+	}); err != nil {
+		return err
+	}
+	if !output.PreviewRoute {
+		return nil
+	}
+
+	if _, err := o.publishCDNFunction(ctx, model.NewPreviewRouterFunction(bucket, v.BasicAuth)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// publishCDNFunction publishes the function and returns its ARN. If the function is nil, it returns empty string.
+func (o *ViewerFunctionOptions) publishCDNFunction(ctx context.Context, function *model.CDNFunction) (string, error) {
+	if function == nil {
+		return "", nil
+	}
+	output, err := o.CDNFunctionPublisher.PublishCDNFunction(ctx, &service.CDNFunctionPublisherInput{
+		Function: function,
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.ARN, nil
+}
+
+// ViewerRequestApplierSet is a provider set for ViewerRequestApplier.
+//
+//nolint:gochecknoglobals
+var ViewerRequestApplierSet = wire.NewSet(
+	NewViewerRequestApplier,
+	wire.Struct(new(ViewerRequestApplierOptions), "*"),
+	wire.Bind(new(usecase.ViewerRequestApplier), new(*ViewerRequestApplier)),
+)
+
+var _ usecase.ViewerRequestApplier = (*ViewerRequestApplier)(nil)
+
+// ViewerRequestApplier is an implementation for ViewerRequestApplier.
+type ViewerRequestApplier struct {
+	opts *ViewerRequestApplierOptions
+}
+
+// ViewerRequestApplierOptions is an option struct for ViewerRequestApplier.
+type ViewerRequestApplierOptions struct {
+	service.CDNFinder
+	*ViewerFunctionOptions
+}
+
+// NewViewerRequestApplier returns a new ViewerRequestApplier struct.
+func NewViewerRequestApplier(opts *ViewerRequestApplierOptions) *ViewerRequestApplier {
+	return &ViewerRequestApplier{
+		opts: opts,
+	}
+}
+
+// ApplyViewerRequest publishes the viewer request function of the CDN.
+// The function name is not changed, so CloudFront serves the new function without updating the cache behaviors.
+func (v *ViewerRequestApplier) ApplyViewerRequest(ctx context.Context, input *usecase.ApplyViewerRequestInput) (*usecase.ApplyViewerRequestOutput, error) {
+	cdn, err := v.opts.CDNFinder.FindCDN(ctx, &service.CDNFinderInput{
+		BucketName: input.BucketName,
+	})
+	if err != nil {
+		if input.AllowNoCDN && errors.Is(err, service.ErrCDNNotFound) {
+			return &usecase.ApplyViewerRequestOutput{}, nil
+		}
+		return nil, err
+	}
+	if err := v.opts.applyViewerRequest(ctx, cdn.DistributionID, input.BucketName, input.ViewerRequest); err != nil {
+		return nil, err
+	}
+	return &usecase.ApplyViewerRequestOutput{
+		Domain:  cdn.Domain,
+		Applied: true,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// AuthRotator is an interface for updating the credentials of the basic auth.
+// It updates only the CloudFront Functions, so the infrastructure is not rebuilt.
+type AuthRotator interface {
+	RotateAuth(ctx context.Context, input *RotateAuthInput) (*RotateAuthOutput, error)
+}
+
+// RotateAuthInput is an input struct for AuthRotator.
+type RotateAuthInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// BasicAuth is the basic auth that contains the new credentials.
+	// The other features of the viewer request function (e.g. redirect rules) are not changed.
+	BasicAuth *model.BasicAuth
+}
+
+// RotateAuthOutput is an output struct for AuthRotator.
+type RotateAuthOutput struct {
+	// Domain is the domain of the CDN.
+	Domain model.Domain
+}
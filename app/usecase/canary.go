@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// CanaryDeployer is an interface for serving the uploaded release to a part of the viewers.
+type CanaryDeployer interface {
+	// DeployCanary switches the staging CDN to the release and routes a part of the viewers to it.
+	DeployCanary(ctx context.Context, input *DeployCanaryInput) (*DeployCanaryOutput, error)
+}
+
+// DeployCanaryInput is an input struct for CanaryDeployer.
+type DeployCanaryInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Release is the release whose files have already been uploaded.
+	Release *model.Release
+	// Traffic is how the CDN routes the viewers to the release.
+	Traffic model.CanaryTraffic
+	// Encryption is the default encryption of the bucket. With SSE-KMS, the staging CDN is allowed to use the KMS key.
+	Encryption *model.Encryption
+}
+
+// DeployCanaryOutput is an output struct for CanaryDeployer.
+type DeployCanaryOutput struct {
+	// StagingDomain is the domain of the staging CDN.
+	StagingDomain model.Domain
+}
+
+// CanaryPromoter is an interface for making the canary release live.
+type CanaryPromoter interface {
+	// PromoteCanary switches the primary CDN to the canary release and stops routing the viewers to the staging CDN.
+	PromoteCanary(ctx context.Context, input *PromoteCanaryInput) (*PromoteCanaryOutput, error)
+}
+
+// PromoteCanaryInput is an input struct for CanaryPromoter.
+type PromoteCanaryInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+}
+
+// PromoteCanaryOutput is an output struct for CanaryPromoter.
+type PromoteCanaryOutput struct {
+	// Release is the release that has become live.
+	Release *model.Release
+	// Domain is the domain of the CDN.
+	Domain model.Domain
+}
+
+// CanaryAborter is an interface for stopping the canary release.
+type CanaryAborter interface {
+	// AbortCanary stops routing the viewers to the staging CDN. The live release is not changed.
+	AbortCanary(ctx context.Context, input *AbortCanaryInput) (*AbortCanaryOutput, error)
+}
+
+// AbortCanaryInput is an input struct for CanaryAborter.
+type AbortCanaryInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+}
+
+// AbortCanaryOutput is an output struct for CanaryAborter.
+type AbortCanaryOutput struct {
+	// Release is the aborted canary release.
+	Release *model.Release
+}
@@ -7,6 +7,7 @@ import (
 )
 
 // CDNCreator is an interface for creating CDN.
+// If the CDN already exists, it reconciles the settings of the CDN with the input.
 type CDNCreator interface {
 	CreateCDN(ctx context.Context, input *CreateCDNInput) (*CreateCDNOutput, error)
 }
@@ -15,10 +16,36 @@ type CDNCreator interface {
 type CreateCDNInput struct {
 	// BucketName is the name of the  bucket.
 	BucketName model.BucketName
+	// Cache is the cache behaviors of the CDN.
+	Cache *model.CacheSettings
+	// Origins is the custom origins (e.g. API) that the CDN routes the path patterns to.
+	// If it's empty, the custom origins created by spare are removed.
+	Origins model.CustomOrigins
+	// SecurityHeaders is the security headers that the CDN adds to the responses.
+	// If it's nil, the CDN does not add the security headers.
+	SecurityHeaders *model.SecurityHeaders
+	// CORS is the CORS settings of the CDN. If it's nil, CORS is disabled.
+	CORS *model.CORS
+	// WAF is the rules of the web ACL that protects the CDN.
+	// If it's nil, the web ACL created by spare is detached and deleted.
+	WAF *model.WAF
+	// ViewerRequest is the features of the function that runs on viewer requests (e.g. basic auth).
+	// If no feature is enabled, the function is disassociated from the CDN.
+	ViewerRequest *model.ViewerRequest
+	// Logging is the access logging settings. The standard logs of the CDN are delivered to the log bucket.
+	// If it's nil, the standard logging is disabled.
+	Logging *model.AccessLogging
+	// Encryption is the default encryption of the bucket. The S3 origins are always switched to OAC, and with SSE-KMS,
+	// the key policy allows CloudFront to decrypt the objects.
+	Encryption *model.Encryption
+	// Tags is the tags of the CDN and the web ACL. If it's empty, they are not tagged.
+	Tags model.Tags
 }
 
 // CreateCDNOutput is an output struct for CDNCreator.
 type CreateCDNOutput struct {
 	// Domain is the domain of the CDN.
 	Domain model.Domain
+	// Created is whether the CDN has been created. It's false if the existing CDN has been reconciled.
+	Created bool
 }
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// PreflightChecker is an interface for checking that spare can build and deploy with the credentials.
+type PreflightChecker interface {
+	// RunPreflightChecks checks the AWS profile, the credentials, the region, the bucket and the IAM permissions.
+	// The failed checks are in the checklist, and it returns an error only if a check can not be run.
+	RunPreflightChecks(ctx context.Context, input *RunPreflightChecksInput) (*RunPreflightChecksOutput, error)
+}
+
+// RunPreflightChecksInput is an input struct for PreflightChecker.
+type RunPreflightChecksInput struct {
+	// Profile is the name of the AWS profile.
+	Profile model.AWSProfile
+	// Region is the region in .spare.yml.
+	Region model.RegionInfo
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// BucketMustExist is whether the bucket must already be owned by the account (e.g. spare deploy).
+	// If it's false, the available bucket name passes the check, because 'spare build' creates the bucket.
+	BucketMustExist bool
+	// Permissions is the IAM permissions that the command needs.
+	Permissions []model.IAMPermission
+}
+
+// RunPreflightChecksOutput is an output struct for PreflightChecker.
+type RunPreflightChecksOutput struct {
+	// Identity is the identity of the credentials. If the credentials can not be resolved, it's nil.
+	Identity *model.CallerIdentity
+	// Checklist is the results of the checks.
+	Checklist model.Checklist
+}
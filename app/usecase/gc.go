@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// GarbageCollector is an interface for deleting old releases and unreferenced objects.
+type GarbageCollector interface {
+	// CollectGarbage deletes the releases that are not kept by the retention policy.
+	CollectGarbage(ctx context.Context, input *CollectGarbageInput) (*CollectGarbageOutput, error)
+}
+
+// CollectGarbageInput is an input struct for GarbageCollector.
+type CollectGarbageInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Policy is the retention policy of releases.
+	Policy model.RetentionPolicy
+	// Now is the current time. It's used to calculate the age of releases.
+	Now time.Time
+	// Abandoned is the list of the deploys that are not recorded in the release history and will never be.
+	// Their objects are deleted. The other unrecorded deploys are kept, because they may be in progress.
+	Abandoned []model.ReleaseID
+	// DryRun is a flag that indicates whether to only report the objects to delete.
+	DryRun bool
+}
+
+// CollectGarbageOutput is an output struct for GarbageCollector.
+type CollectGarbageOutput struct {
+	// Retained is the list of releases that are kept.
+	Retained []model.Release
+	// Expired is the list of releases that are (or would be) deleted.
+	Expired []model.Release
+	// Pending is the list of the deploys that are not recorded in the release history.
+	// They are in progress or they failed. Their objects are kept.
+	Pending []model.ReleaseID
+	// Objects is the list of objects that are (or would be) deleted.
+	Objects model.BucketObjects
+}
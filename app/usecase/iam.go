@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// IAMPolicyGenerator is an interface for generating the least-privilege IAM policy that spare needs.
+type IAMPolicyGenerator interface {
+	// GenerateIAMPolicy returns the IAM policy for the commands and the features.
+	// The account and the distribution that are not in the options are looked up in AWS.
+	GenerateIAMPolicy(ctx context.Context, input *GenerateIAMPolicyInput) (*GenerateIAMPolicyOutput, error)
+}
+
+// GenerateIAMPolicyInput is an input struct for IAMPolicyGenerator.
+type GenerateIAMPolicyInput struct {
+	// Options is the commands and the features. If Account is empty, it's the account of the credentials.
+	// If DistributionID is empty, it's the distribution whose origin is the bucket.
+	Options model.IAMPermissionOptions
+}
+
+// GenerateIAMPolicyOutput is an output struct for IAMPolicyGenerator.
+type GenerateIAMPolicyOutput struct {
+	// Policy is the identity-based policy.
+	Policy *model.IAMPolicy
+	// Account is the AWS account ID that the policy is scoped to.
+	Account string
+	// DistributionID is the ID of the distribution that the policy is scoped to.
+	// If the distribution has not been created yet, it's empty.
+	DistributionID model.DistributionID
+}
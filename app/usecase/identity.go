@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// IdentityResolver is an interface for resolving the AWS account and the IAM identity of the credentials.
+type IdentityResolver interface {
+	// ResolveIdentity returns the identity of the credentials. If the credentials of the AWS SSO profile
+	// can not be resolved, it returns SSOLoginRequired instead of an error, so the caller can run 'aws sso login'.
+	ResolveIdentity(ctx context.Context, input *ResolveIdentityInput) (*ResolveIdentityOutput, error)
+}
+
+// ResolveIdentityInput is an input struct for IdentityResolver.
+type ResolveIdentityInput struct {
+	// Profile is the name of the AWS profile.
+	Profile model.AWSProfile
+}
+
+// ResolveIdentityOutput is an output struct for IdentityResolver.
+type ResolveIdentityOutput struct {
+	// Identity is the identity of the credentials. If SSOLoginRequired is true, it's nil.
+	Identity *model.CallerIdentity
+	// SSOLoginRequired is whether the profile is an AWS SSO profile whose session is expired or not logged in.
+	SSOLoginRequired bool
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// SigningKeyCreator is an interface for registering the first public key and creating the key group
+// for the signed URLs and the signed cookies.
+type SigningKeyCreator interface {
+	CreateSigningKey(ctx context.Context, input *CreateSigningKeyInput) (*CreateSigningKeyOutput, error)
+}
+
+// CreateSigningKeyInput is an input struct for SigningKeyCreator.
+type CreateSigningKeyInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// PublicKey is the PEM encoded public key.
+	PublicKey []byte
+}
+
+// CreateSigningKeyOutput is an output struct for SigningKeyCreator.
+type CreateSigningKeyOutput struct {
+	// KeyPairID is the ID of the registered public key. It's used to sign URLs with the private key.
+	KeyPairID string
+	// KeyGroupID is the ID of the key group.
+	KeyGroupID string
+}
+
+// SigningKeyRotator is an interface for adding a new public key to the key group and retiring the old ones.
+type SigningKeyRotator interface {
+	RotateSigningKey(ctx context.Context, input *RotateSigningKeyInput) (*RotateSigningKeyOutput, error)
+}
+
+// RotateSigningKeyInput is an input struct for SigningKeyRotator.
+type RotateSigningKeyInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// PublicKey is the PEM encoded new public key.
+	PublicKey []byte
+}
+
+// RotateSigningKeyOutput is an output struct for SigningKeyRotator.
+type RotateSigningKeyOutput struct {
+	// KeyPairID is the ID of the new public key.
+	KeyPairID string
+	// PreviousKeyPairID is the ID of the public key that is still trusted until the next rotation.
+	PreviousKeyPairID string
+	// RetiredKeyPairIDs is the IDs of the public keys that have been removed from the key group and deleted.
+	RetiredKeyPairIDs []string
+}
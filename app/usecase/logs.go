@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// AccessLogAnalyzer is an interface for downloading the CDN access logs and summarizing them.
+type AccessLogAnalyzer interface {
+	AnalyzeAccessLogs(ctx context.Context, input *AnalyzeAccessLogsInput) (*AnalyzeAccessLogsOutput, error)
+}
+
+// AnalyzeAccessLogsInput is an input struct for AccessLogAnalyzer.
+type AnalyzeAccessLogsInput struct {
+	// LogBucket is the name of the log bucket.
+	LogBucket model.BucketName
+	// Since is the start of the time window.
+	Since time.Time
+	// Until is the end of the time window.
+	Until time.Time
+	// Top is the number of the paths and the referrers in the report.
+	Top int
+}
+
+// AnalyzeAccessLogsOutput is an output struct for AccessLogAnalyzer.
+type AnalyzeAccessLogsOutput struct {
+	// Report is the summary of the access logs in the time window.
+	Report *model.AccessLogReport
+	// Files is the number of the log files that have been downloaded.
+	Files int
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// MaintenanceSwitcher is an interface for turning on or off the maintenance mode without deploying.
+type MaintenanceSwitcher interface {
+	SwitchMaintenance(ctx context.Context, input *SwitchMaintenanceInput) (*SwitchMaintenanceOutput, error)
+}
+
+// SwitchMaintenanceInput is an input struct for MaintenanceSwitcher.
+type SwitchMaintenanceInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// Maintenance is the maintenance mode. If it's nil, the maintenance mode is turned off.
+	// If the maintenance mode is already on, its bypass token is kept.
+	Maintenance *model.Maintenance
+	// Page is the HTML of the maintenance page. It's uploaded to the live release and the canary release.
+	// It's ignored when the maintenance mode is turned off.
+	Page []byte
+	// ViewerRequest is the other features of the viewer request function (e.g. basic auth).
+	ViewerRequest *model.ViewerRequest
+	// Encryption is the server-side encryption of the maintenance page. If it's nil, the default encryption of the bucket is used.
+	Encryption *model.Encryption
+}
+
+// SwitchMaintenanceOutput is an output struct for MaintenanceSwitcher.
+type SwitchMaintenanceOutput struct {
+	// Domain is the domain of the CDN.
+	Domain model.Domain
+	// Maintenance is the maintenance mode that has been applied. If the maintenance mode is off, it's nil.
+	Maintenance *model.Maintenance
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// PreviewPublisher is an interface for publishing the preview that has been uploaded.
+type PreviewPublisher interface {
+	// PublishPreview routes the preview path to the uploaded files and records the preview.
+	PublishPreview(ctx context.Context, input *PublishPreviewInput) (*PublishPreviewOutput, error)
+}
+
+// PublishPreviewInput is an input struct for PreviewPublisher.
+type PublishPreviewInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Preview is the preview whose files have already been uploaded.
+	Preview *model.Preview
+	// UploadedKeys is the list of S3 keys uploaded by this deploy.
+	// Other objects under the preview prefix are deleted, because they belong to the previous deploy.
+	UploadedKeys []string
+	// BasicAuth is the basic auth that protects the previews. If it's nil, the previews are public.
+	BasicAuth *model.BasicAuth
+}
+
+// PublishPreviewOutput is an output struct for PreviewPublisher.
+type PublishPreviewOutput struct {
+	// URL is the URL of the preview. e.g. https://xxx.cloudfront.net/previews/feature-x/
+	URL string
+}
+
+// PreviewLister is an interface for listing the previews.
+type PreviewLister interface {
+	// ListPreviews returns the preview list.
+	ListPreviews(ctx context.Context, input *ListPreviewsInput) (*ListPreviewsOutput, error)
+}
+
+// ListPreviewsInput is an input struct for PreviewLister.
+type ListPreviewsInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+}
+
+// ListPreviewsOutput is an output struct for PreviewLister.
+type ListPreviewsOutput struct {
+	// List is the preview list.
+	List *model.PreviewList
+	// Domain is the domain of the CDN. It's empty if the CDN does not exist.
+	Domain model.Domain
+}
+
+// PreviewDeleter is an interface for deleting the previews.
+type PreviewDeleter interface {
+	// DeletePreviews deletes the files of the previews and removes them from the preview list.
+	DeletePreviews(ctx context.Context, input *DeletePreviewsInput) (*DeletePreviewsOutput, error)
+}
+
+// DeletePreviewsInput is an input struct for PreviewDeleter.
+type DeletePreviewsInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Names is the list of previews to delete.
+	Names []model.PreviewName
+}
+
+// DeletePreviewsOutput is an output struct for PreviewDeleter.
+type DeletePreviewsOutput struct {
+	// Objects is the list of deleted objects.
+	Objects model.BucketObjects
+}
+
+// PreviewExpirer is an interface for deleting the stale previews.
+type PreviewExpirer interface {
+	// ExpirePreviews deletes the previews that have not been deployed for TTL.
+	ExpirePreviews(ctx context.Context, input *ExpirePreviewsInput) (*ExpirePreviewsOutput, error)
+}
+
+// ExpirePreviewsInput is an input struct for PreviewExpirer.
+type ExpirePreviewsInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// TTL is the time to live of previews. If it's zero, previews never expire.
+	TTL time.Duration
+	// Now is the current time.
+	Now time.Time
+}
+
+// ExpirePreviewsOutput is an output struct for PreviewExpirer.
+type ExpirePreviewsOutput struct {
+	// Expired is the list of deleted previews.
+	Expired []model.Preview
+}
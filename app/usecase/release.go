@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// ReleasePublisher is an interface for publishing the release that has been uploaded.
+type ReleasePublisher interface {
+	// PublishRelease records the release in the release history and switches the CDN to the release.
+	PublishRelease(ctx context.Context, input *PublishReleaseInput) (*PublishReleaseOutput, error)
+}
+
+// PublishReleaseInput is an input struct for ReleasePublisher.
+type PublishReleaseInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Release is the release whose files have already been uploaded.
+	Release *model.Release
+	// AllowNoCDN is whether the release is only recorded when the CDN does not exist.
+	// It's for debug mode, because localstack may have no CloudFront distribution.
+	AllowNoCDN bool
+}
+
+// PublishReleaseOutput is an output struct for ReleasePublisher.
+type PublishReleaseOutput struct {
+	// Domain is the domain of the CDN. It's empty when the CDN is not switched.
+	Domain model.Domain
+	// Switched is whether the CDN is switched to the release. It's false only when the CDN does not exist and AllowNoCDN is true.
+	Switched bool
+}
+
+// ReleaseManifestRecorder is an interface for recording the S3 keys of the release before uploading them.
+type ReleaseManifestRecorder interface {
+	// RecordReleaseManifest puts the release manifest. The garbage collection never deletes the keys in it.
+	RecordReleaseManifest(ctx context.Context, input *RecordReleaseManifestInput) (*RecordReleaseManifestOutput, error)
+}
+
+// RecordReleaseManifestInput is an input struct for ReleaseManifestRecorder.
+type RecordReleaseManifestInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// Manifest is the list of the S3 keys that the release uploads.
+	Manifest *model.ReleaseManifest
+}
+
+// RecordReleaseManifestOutput is an output struct for ReleaseManifestRecorder.
+type RecordReleaseManifestOutput struct{}
+
+// ReleaseLister is an interface for listing the releases.
+type ReleaseLister interface {
+	// ListReleases returns the release history.
+	ListReleases(ctx context.Context, input *ListReleasesInput) (*ListReleasesOutput, error)
+}
+
+// ListReleasesInput is an input struct for ReleaseLister.
+type ListReleasesInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+}
+
+// ListReleasesOutput is an output struct for ReleaseLister.
+type ListReleasesOutput struct {
+	// History is the release history.
+	History *model.ReleaseHistory
+}
+
+// ReleaseRollbacker is an interface for switching the CDN back to the past release.
+type ReleaseRollbacker interface {
+	// RollbackRelease switches the CDN to the release.
+	RollbackRelease(ctx context.Context, input *RollbackReleaseInput) (*RollbackReleaseOutput, error)
+}
+
+// RollbackReleaseInput is an input struct for ReleaseRollbacker.
+type RollbackReleaseInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+	// ID is the ID of the release to switch to. If ID is empty, the previous release is used.
+	ID model.ReleaseID
+}
+
+// RollbackReleaseOutput is an output struct for ReleaseRollbacker.
+type RollbackReleaseOutput struct {
+	// Release is the release that is live now.
+	Release *model.Release
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// StackLister is an interface for listing the spare-managed stacks in the account.
+type StackLister interface {
+	ListStacks(ctx context.Context, input *ListStacksInput) (*ListStacksOutput, error)
+}
+
+// ListStacksInput is an input struct for StackLister.
+type ListStacksInput struct{}
+
+// ListStacksOutput is an output struct for StackLister.
+type ListStacksOutput struct {
+	// Stacks is the spare-managed stacks sorted by the project.
+	Stacks []model.Stack
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// StatusGetter is an interface for getting the status of the SPA delivery infrastructure.
+type StatusGetter interface {
+	// GetStatus returns the status of the CDN and the releases.
+	GetStatus(ctx context.Context, input *GetStatusInput) (*GetStatusOutput, error)
+}
+
+// GetStatusInput is an input struct for StatusGetter.
+type GetStatusInput struct {
+	// BucketName is the name of the bucket.
+	BucketName model.BucketName
+}
+
+// GetStatusOutput is an output struct for StatusGetter.
+type GetStatusOutput struct {
+	// DistributionID is the ID of the CDN.
+	DistributionID model.DistributionID
+	// Domain is the domain of the CDN.
+	Domain model.Domain
+	// Live is the ID of the release that the CDN delivers. If no release has been deployed yet, it's empty.
+	Live model.ReleaseID
+	// Staged is the ID of the canary release. If there is no canary release, it's empty.
+	Staged model.ReleaseID
+	// ResponseHeadersPolicy is the name of the response headers policy. If no policy is attached, it's empty.
+	ResponseHeadersPolicy string
+	// ResponseHeaders is the list of headers that the CDN adds to the responses.
+	ResponseHeaders []model.HTTPHeader
+	// Maintenance is the maintenance mode. If the maintenance mode is off, it's nil.
+	Maintenance *model.Maintenance
+}
@@ -19,10 +19,39 @@ type CreateStorageInput struct {
 	BucketName model.BucketName
 	// Region is the name of the region where the bucket is located.
 	Region model.Region
+	// CORS is the CORS settings of the bucket. If it's nil, CORS is disabled.
+	CORS *model.CORS
+	// Logging is the access logging settings. The log bucket is created, and the server access logs of the bucket are
+	// delivered to it. If it's nil, the server access logging is disabled, but the log bucket is kept.
+	Logging *model.AccessLogging
+	// Settings is the versioning, the object ownership and the lifecycle rules of the bucket.
+	// If it's nil, they are left as they are.
+	Settings *model.BucketSettings
+	// Encryption is the default encryption of the bucket. If it's nil, the default encryption is left as it is.
+	Encryption *model.Encryption
+	// Tags is the tags of the bucket, the log bucket and the KMS key that spare creates. If it's empty, they are not tagged.
+	Tags model.Tags
+	// Storage is the S3-compatible storage. If it's set, only the bucket, the public-read policy and the CORS rules
+	// are applied, and the other settings are ignored, because they need AWS.
+	Storage *model.StorageEndpoint
+	// PublicRead is whether the public-read policy is applied to the bucket in the S3-compatible storage.
+	// If it's false, the bucket policy is left as it is.
+	PublicRead bool
 }
 
 // CreateStorageOutput is an output struct for StorageCreator.
-type CreateStorageOutput struct{}
+type CreateStorageOutput struct {
+	// Versioning is the versioning state of the bucket. It's empty when Settings is nil.
+	Versioning model.VersioningStatus
+	// Ownership is the object ownership of the bucket. It's empty when the object ownership is left as it is.
+	Ownership model.ObjectOwnership
+	// LifecycleRules is the number of the lifecycle rules of the bucket.
+	LifecycleRules int
+	// EncryptionKeyARN is the ARN of the KMS key that encrypts the objects. It's empty with SSE-S3.
+	EncryptionKeyARN model.KMSKeyARN
+	// EncryptionKeyCreated is whether the KMS key is created.
+	EncryptionKeyCreated bool
+}
 
 // FileUploader is an interface for uploading files to external storage.
 type FileUploader interface {
@@ -40,6 +69,11 @@ type UploadFileInput struct {
 	Key string
 	// Data is the data to upload.
 	Data io.Reader
+	// Headers is the headers that are returned with the object (e.g. Cache-Control).
+	// The Content-Type header overrides the detected MIME type.
+	Headers []model.HTTPHeader
+	// Encryption is the server-side encryption of the object. If it's nil, the default encryption of the bucket is used.
+	Encryption *model.Encryption
 }
 
 // UploadFileOutput is an output struct for FileUploader.
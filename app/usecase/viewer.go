@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// ViewerRequestApplier is an interface for applying the features of the viewer request function
+// (e.g. basic auth credentials, redirect rules) to the CDN.
+// It updates only the CloudFront Functions, so the infrastructure is not rebuilt.
+type ViewerRequestApplier interface {
+	ApplyViewerRequest(ctx context.Context, input *ApplyViewerRequestInput) (*ApplyViewerRequestOutput, error)
+}
+
+// ApplyViewerRequestInput is an input struct for ViewerRequestApplier.
+type ApplyViewerRequestInput struct {
+	// BucketName is the name of the bucket that is the origin of the CDN.
+	BucketName model.BucketName
+	// ViewerRequest is the features of the viewer request function.
+	ViewerRequest *model.ViewerRequest
+	// AllowNoCDN is whether nothing is published when the CDN does not exist. It's for debug mode.
+	AllowNoCDN bool
+}
+
+// ApplyViewerRequestOutput is an output struct for ViewerRequestApplier.
+type ApplyViewerRequestOutput struct {
+	// Domain is the domain of the CDN. It's empty when the function is not published.
+	Domain model.Domain
+	// Applied is whether the function is published. It's false only when the CDN does not exist and AllowNoCDN is true.
+	Applied bool
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/spf13/cobra"
+)
+
+// newAbortCmd return abort sub command.
+func newAbortCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abort",
+		Short: "stop the canary release",
+		Long: `abort stops sending the viewers to the canary release deployed by 'spare deploy --canary'.
+The live release is not changed.`,
+		Example: "   spare abort",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &aborter{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type aborter struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the abort command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+}
+
+// Parse parses the arguments and flags.
+func (a *aborter) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	a.ctx = commonOption.ctx
+	a.spare = commonOption.spare
+	a.config = commonOption.config
+	return nil
+}
+
+// Do stop the canary release.
+func (a *aborter) Do() error {
+	log.Info("[ ABORT  ]", "bucket name", a.config.S3BucketName)
+	output, err := a.spare.CanaryAborter.AbortCanary(a.ctx, &usecase.AbortCanaryInput{
+		BucketName: a.config.S3BucketName,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[ ABORT  ] done. the live release is not changed", "aborted release", output.Release.ID)
+	return nil
+}
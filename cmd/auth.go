@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newAuthCmd return auth sub command.
+func newAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "manage the basic auth of the site",
+		Long: `auth manages the basic auth that CloudFront Function checks.
+The basic auth is enabled by 'auth.basic.enabled' in .spare.yml and applied by 'spare build'.
+The site and the previews are protected by the same credentials.`,
+	}
+	cmd.AddCommand(newAuthRotateCmd())
+	return cmd
+}
+
+// newAuthRotateCmd return auth rotate sub command.
+func newAuthRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "update the credentials of the basic auth without rebuilding",
+		Long: `rotate updates the credentials of the basic auth and publishes the CloudFront Functions again.
+The password of --user is asked, and its hash is written to .spare.yml.
+If $SPARE_BASIC_AUTH_CREDENTIALS is set, the credentials in it are published instead, and .spare.yml is not changed.
+Only the basic auth is changed. The redirect rules and pretty URLs of the live site are kept,
+even if the deploy target has been changed since the last deploy.`,
+		Example: "   spare auth rotate --user alice\n   SPARE_BASIC_AUTH_CREDENTIALS=alice:password spare auth rotate",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &authRotator{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("user", "u", "", "name of the user whose password is changed. if the user does not exist, it's added")
+	return cmd
+}
+
+type authRotator struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the auth rotate command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// configFilePath is a path of the config file.
+	configFilePath string
+	// debug is a flag that indicates whether to run debug mode.
+	debug bool
+	// username is the name of the user whose password is changed.
+	username string
+}
+
+// Parse parses the arguments and flags.
+func (a *authRotator) Parse(cmd *cobra.Command, _ []string) (err error) {
+	a.username, err = cmd.Flags().GetString("user")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--user)")
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	a.ctx = commonOption.ctx
+	a.spare = commonOption.spare
+	a.config = commonOption.config
+	a.configFilePath = commonOption.configFilePath
+	a.debug = commonOption.debug
+	return nil
+}
+
+// Do update the credentials of the basic auth.
+func (a *authRotator) Do() error {
+	if !a.config.Auth.Basic.Enabled {
+		return errfmt.Wrap(config.ErrInvalidAuth,
+			fmt.Sprintf("basic auth is disabled. set auth.basic.enabled to true in %s, and run 'spare build'", a.configFilePath))
+	}
+
+	if os.Getenv(model.BasicAuthCredentialsEnv) != "" {
+		log.Info("[ ROTATE ] use credentials in $" + model.BasicAuthCredentialsEnv)
+	} else {
+		if err := a.askPassword(); err != nil {
+			return err
+		}
+	}
+	if err := a.config.Validate(a.debug); err != nil {
+		return err
+	}
+
+	basicAuth, err := a.config.Auth.Basic.Rules()
+	if err != nil {
+		return err
+	}
+	if err := basicAuth.Validate(); err != nil {
+		return err
+	}
+	output, err := a.spare.AuthRotator.RotateAuth(a.ctx, &usecase.RotateAuthInput{
+		BucketName: a.config.S3BucketName,
+		BasicAuth:  basicAuth,
+	})
+	if err != nil {
+		return err
+	}
+
+	if os.Getenv(model.BasicAuthCredentialsEnv) == "" {
+		if err := writeConfig(a.configFilePath, a.config); err != nil {
+			return err
+		}
+		log.Info("[ UPDATE ]", "config file name", a.configFilePath, "user", a.username)
+	}
+	log.Info("[ ROTATE ] done", "domain", output.Domain)
+	return nil
+}
+
+// askPassword asks the new password of the user, and sets its hash to the config.
+func (a *authRotator) askPassword() error {
+	if a.username == "" {
+		if err := survey.AskOne(&survey.Input{Message: "username:"}, &a.username, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	}
+
+	var password, confirm string
+	if err := survey.AskOne(&survey.Password{Message: fmt.Sprintf("new password of %s:", a.username)}, &password, survey.WithValidator(survey.Required)); err != nil {
+		return err
+	}
+	if err := survey.AskOne(&survey.Password{Message: "retype new password:"}, &confirm); err != nil {
+		return err
+	}
+	if password != confirm {
+		return errors.New("passwords do not match")
+	}
+	a.config.Auth.Basic.SetUser(model.NewBasicAuthUser(a.username, password))
+	return nil
+}
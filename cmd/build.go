@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/charmbracelet/log"
@@ -11,14 +13,22 @@ import (
 	"github.com/nao1215/spare/app/domain/model"
 	"github.com/nao1215/spare/app/usecase"
 	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
 	"github.com/spf13/cobra"
 )
 
 // newBuildCmd return build sub command.
 func newBuildCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "build",
-		Short:   "build AWS infrastructure for SPA",
+		Use:   "build",
+		Short: "build AWS infrastructure for SPA",
+		Long: `build creates the S3 bucket and the CloudFront distribution for SPA.
+If they already exist, build reconciles them with .spare.yml (e.g. cache behaviors, security headers, CORS, WAF, basic auth, pretty URLs, logging).
+Before building, the preflight checks of 'spare doctor' run, and build stops if a check fails.
+
+With storage.endpoint in .spare.yml (e.g. MinIO, Cloudflare R2), build creates only the bucket, the public-read
+policy (with storage.publicRead: true) and the CORS rules. The storage serves the bucket, so the CloudFront
+distribution is not created.`,
 		Example: "   spare build",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return Run(cmd, args, &builder{})
@@ -26,7 +36,9 @@ func newBuildCmd() *cobra.Command {
 	}
 	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
 	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
 	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().Bool("skip-doctor", false, "skip the preflight checks of 'spare doctor'")
 	return cmd
 }
 
@@ -43,10 +55,22 @@ type builder struct {
 	debug bool
 	// awsProfile is a profile name of AWS. If this is empty, use $AWS_PROFILE.
 	awsProfile model.AWSProfile
+	// credentials is how spare gets the credentials of AWS (the profile and the role to assume).
+	credentials *model.AWSCredentials
+	// identity is the AWS account and the IAM identity that build AWS infrastructure.
+	identity *model.CallerIdentity
+	// skipDoctor is whether the preflight checks are skipped. They are always skipped in debug mode.
+	skipDoctor bool
+	// storage is the S3-compatible storage. If it's nil, the bucket is in Amazon S3.
+	storage *model.StorageEndpoint
 }
 
 // Parse parses the arguments and flags.
 func (b *builder) Parse(cmd *cobra.Command, _ []string) (err error) {
+	if b.skipDoctor, err = cmd.Flags().GetBool("skip-doctor"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--skip-doctor)")
+	}
+
 	commonOption, err := parseCommon(cmd, nil)
 	if err != nil {
 		return err
@@ -57,6 +81,8 @@ func (b *builder) Parse(cmd *cobra.Command, _ []string) (err error) {
 	b.configFilePath = commonOption.configFilePath
 	b.debug = commonOption.debug
 	b.awsProfile = commonOption.awsProfile
+	b.credentials = commonOption.credentials
+	b.storage = commonOption.storage
 
 	return nil
 }
@@ -70,28 +96,121 @@ func (b *builder) Do() error {
 	}
 	log.Info(fmt.Sprintf("[VALIDATE] ok %s", b.configFilePath))
 
+	// The S3-compatible storage has no STS and no IAM, so the identity and the preflight checks are skipped.
+	// The preflight checks run first, so bad credentials are reported with the hints of 'spare doctor'.
+	if b.storage == nil {
+		var err error
+		if !b.debug && !b.skipDoctor {
+			b.identity, err = runPreflight(b.ctx, b.spare, b.config, b.awsProfile, &preflightOptions{
+				commands:          []model.IAMCommand{model.IAMCommandBuild},
+				checkDeployTarget: false,
+				bucketMustExist:   false,
+			})
+		} else {
+			b.identity, err = resolveIdentity(b.ctx, b.spare, b.awsProfile)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := b.confirm(); err != nil {
 		return err
 	}
+	if b.storage != nil {
+		return b.buildCompatibleStorage()
+	}
 
 	log.Info("[ CREATE ] start building AWS infrastructure")
+	cors := model.NewCORS(b.config.AllowOrigins)
+	logging := b.config.Logging.Settings(b.config.S3BucketName)
+	encryption := b.config.Encryption.Settings(b.config.S3BucketName)
+	tags := b.config.ResourceTags(spareVersion())
 	log.Info("[ CREATE ] s3 bucket with public access block policy", "name", b.config.S3BucketName.String())
-	if _, err := b.spare.StorageCreator.CreateStorage(b.ctx, &usecase.CreateStorageInput{
+	if logging != nil {
+		log.Info("[ CREATE ] s3 log bucket", "name", logging.Bucket.String(), "expiration days", logging.ExpirationDays)
+	}
+	createStorageOutput, err := b.spare.StorageCreator.CreateStorage(b.ctx, &usecase.CreateStorageInput{
 		BucketName: b.config.S3BucketName,
 		Region:     b.config.Region,
-	}); err != nil {
+		CORS:       cors,
+		Logging:    logging,
+		Settings:   b.config.Storage.Settings(),
+		Encryption: encryption,
+		Tags:       tags,
+	})
+	if err != nil {
 		return err
 	}
+	if createStorageOutput.Ownership != "" {
+		log.Info("[ UPDATE ] s3 bucket object ownership", "ownership", createStorageOutput.Ownership.String())
+	}
+	log.Info("[ UPDATE ] s3 bucket versioning", "status", createStorageOutput.Versioning.String())
+	log.Info("[ UPDATE ] s3 bucket lifecycle rules", "rules", createStorageOutput.LifecycleRules)
+	if createStorageOutput.EncryptionKeyCreated {
+		log.Info("[ CREATE ] kms key", "arn", createStorageOutput.EncryptionKeyARN.String(), "alias", encryption.KMSKeyID)
+	}
+	if encryption != nil {
+		log.Info("[ UPDATE ] s3 bucket default encryption", "type", encryption.Type.String(), "kms key", createStorageOutput.EncryptionKeyARN.String())
+	}
 
-	log.Info("[ CREATE ] cloudfront distribution")
+	waf, err := b.config.WAF.Rules()
+	if err != nil {
+		return err
+	}
+	viewer, err := viewerRequest(b.config)
+	if err != nil {
+		return err
+	}
+
+	log.Info("[ CREATE ] cloudfront distribution", "cache behaviors", len(b.config.Cache.Behaviors), "waf", b.config.WAF.Enabled)
 	createCDNOutput, err := b.spare.CDNCreator.CreateCDN(b.ctx, &usecase.CreateCDNInput{
-		BucketName: b.config.S3BucketName,
+		BucketName:      b.config.S3BucketName,
+		Cache:           b.config.Cache.Settings(),
+		Origins:         b.config.Origins.Settings(),
+		SecurityHeaders: b.config.SecurityHeaders.Headers(),
+		CORS:            cors,
+		WAF:             waf,
+		ViewerRequest:   viewer,
+		Logging:         logging,
+		Encryption:      encryption,
+		Tags:            tags,
 	})
 	if err != nil {
 		return err
 	}
-	log.Info("[ CREATE ] cloudfront distribution", "domain", createCDNOutput.Domain.String())
+	if createCDNOutput.Created {
+		log.Info("[ CREATE ] cloudfront distribution", "domain", createCDNOutput.Domain.String())
+	} else {
+		log.Info("[ UPDATE ] existing cloudfront distribution is reconciled", "domain", createCDNOutput.Domain.String())
+	}
+
+	return nil
+}
 
+// buildCompatibleStorage creates the bucket in the S3-compatible storage. The storage has no CloudFront,
+// so the bucket serves the site, and the CloudFront distribution is not created.
+func (b *builder) buildCompatibleStorage() error {
+	log.Info("[ CREATE ] start building the S3-compatible storage", "provider", b.storage.Provider, "endpoint", b.storage.URL)
+	log.Info("[ CREATE ] bucket", "name", b.config.S3BucketName.String())
+	if _, err := b.spare.StorageCreator.CreateStorage(b.ctx, &usecase.CreateStorageInput{
+		BucketName: b.config.S3BucketName,
+		Region:     b.config.Region,
+		CORS:       model.NewCORS(b.config.AllowOrigins),
+		Storage:    b.storage,
+		PublicRead: b.config.Storage.PublicRead,
+	}); err != nil {
+		return err
+	}
+	switch {
+	case b.storage.Provider == model.StorageProviderR2:
+		log.Warn("[  SKIP  ] bucket policy. enable the r2.dev subdomain or connect a custom domain to the bucket in the Cloudflare dashboard")
+	case b.config.Storage.PublicRead:
+		log.Info("[ UPDATE ] public-read bucket policy. releases/, previews/ and _spare/ are kept private")
+	default:
+		log.Warn("[  SKIP  ] public-read bucket policy. set storage.publicRead to true in .spare.yml to serve the site from the bucket")
+	}
+	log.Info("[  SKIP  ] cloudfront distribution. the storage serves the bucket", "domain", b.storage.SiteDomain(b.config.S3BucketName).String())
 	return nil
 }
 
@@ -103,13 +222,42 @@ func (b *builder) confirm() error {
 	fmt.Printf(" %t\n", b.debug)
 	fmt.Println("[aws profile]")
 	fmt.Printf(" %s\n", b.awsProfile.String())
+	if b.credentials.AssumeRole != nil {
+		fmt.Println("[assume role]")
+		fmt.Printf(" %s\n", b.credentials.AssumeRole)
+	}
+	if b.identity != nil {
+		fmt.Println("[aws account]")
+		fmt.Printf(" %s\n", b.identity.Account)
+		fmt.Println("[aws identity]")
+		fmt.Printf(" %s\n", b.identity.ARN)
+	}
+	if b.storage != nil {
+		fmt.Println("[storage endpoint]")
+		fmt.Printf(" %s\n", storageEndpointSummary(b.storage))
+	}
 	fmt.Printf("[%s]\n", b.configFilePath)
 	fmt.Printf(" spareTemplateVersion: %s\n", b.config.SpareTemplateVersion)
 	fmt.Printf(" deployTarget: %s\n", b.config.DeployTarget)
-	fmt.Printf(" region: %s\n", b.config.Region)
+	fmt.Printf(" region: %s\n", regionSummary(b.config))
 	fmt.Printf(" customDomain: %s\n", b.config.CustomDomain)
 	fmt.Printf(" s3BucketName: %s\n", b.config.S3BucketName)
-	fmt.Printf(" allowOrigins: %s\n", b.config.AllowOrigins.String())
+	fmt.Printf(" allowOrigins: %s\n", strings.Join(b.config.AllowOrigins.Origins(), ","))
+	fmt.Printf(" cache: default=%s\n", cacheBehaviorSummary(b.config.Cache.Default))
+	for _, behavior := range b.config.Cache.Behaviors {
+		fmt.Printf(" cache: %s=%s\n", behavior.PathPattern, cacheBehaviorSummary(behavior))
+	}
+	for _, origin := range b.config.Origins {
+		fmt.Printf(" origin: %s=%s\n", origin.Name, customOriginSummary(origin))
+	}
+	fmt.Printf(" securityHeaders: %s\n", securityHeadersSummary(b.config.SecurityHeaders))
+	fmt.Printf(" waf: %s\n", wafSummary(b.config.WAF))
+	fmt.Printf(" auth: %s\n", authSummary(b.config.Auth))
+	fmt.Printf(" prettyUrls: %s\n", prettyURLsSummary(b.config.PrettyURLs))
+	fmt.Printf(" logging: %s\n", loggingSummary(b.config.Logging, b.config.S3BucketName))
+	fmt.Printf(" storage: %s\n", storageSummary(b.config.Storage))
+	fmt.Printf(" encryption: %s\n", encryptionSummary(b.config.Encryption, b.config.S3BucketName))
+	fmt.Printf(" tags: %s\n", tagsSummary(b.config.ResourceTags(spareVersion())))
 	if b.debug {
 		fmt.Printf(" debugLocalstackEndpoint: %s\n", b.config.DebugLocalstackEndpoint)
 	}
@@ -130,3 +278,149 @@ func (b *builder) confirm() error {
 	}
 	return nil
 }
+
+// regionSummary returns the region with the display name, the partition and whether it's opt-in.
+func regionSummary(c *config.Config) string {
+	info, err := c.RegionInfo()
+	if err != nil {
+		return c.Region.String()
+	}
+	details := make([]string, 0)
+	if info.DisplayName != "" {
+		details = append(details, info.DisplayName)
+	}
+	details = append(details, "partition "+info.Partition.String())
+	if info.OptIn {
+		details = append(details, "opt-in")
+	}
+	return fmt.Sprintf("%s (%s)", info.Region, strings.Join(details, ", "))
+}
+
+// cacheBehaviorSummary returns the short description of the cache behavior.
+func cacheBehaviorSummary(b config.CacheBehavior) string {
+	summary := fmt.Sprintf("ttl(min=%d,default=%d,max=%d),compress=%t,queryStrings=%v,headers=%v",
+		b.MinTTL, b.DefaultTTL, b.MaxTTL, b.Compress, b.QueryStrings, b.Headers)
+	if b.CachePolicy != "" {
+		summary = fmt.Sprintf("%s(compress=%t)", b.CachePolicy, b.Compress)
+	}
+	if b.Signed {
+		summary += ",signed"
+	}
+	return summary
+}
+
+// customOriginSummary returns the short description of the custom origin.
+func customOriginSummary(o config.CustomOrigin) string {
+	return fmt.Sprintf("domain=%s%s,protocol=%s,pathPatterns=%v,cachePolicy=%s,headers=%v,cookies=%v,queryStrings=%v",
+		o.DomainName, o.OriginPath, o.Protocol, o.PathPatterns, o.CachePolicy, o.Headers, o.Cookies, o.QueryStrings)
+}
+
+// securityHeadersSummary returns the short description of the security headers.
+func securityHeadersSummary(s config.SecurityHeaders) string {
+	headers := s.Headers()
+	if headers == nil {
+		return "disabled"
+	}
+	names := make([]string, 0, len(headers.Headers()))
+	for _, h := range headers.Headers() {
+		names = append(names, h.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// wafSummary returns the short description of the WAF settings.
+func wafSummary(w config.WAF) string {
+	if !w.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("managedRules=%v,rateLimit=%d,allowListFiles=%v,denyListFiles=%v,blockCountries=%v",
+		w.ManagedRules, w.RateLimit, w.AllowListFiles, w.DenyListFiles, w.BlockCountries)
+}
+
+// authSummary returns the short description of the auth settings. Password hashes are not shown.
+func authSummary(a config.Auth) string {
+	if !a.Basic.Enabled {
+		return "disabled"
+	}
+	if os.Getenv(model.BasicAuthCredentialsEnv) != "" {
+		return fmt.Sprintf("basic(realm=%s,users=$%s)", a.Basic.Realm, model.BasicAuthCredentialsEnv)
+	}
+	users := make([]string, 0, len(a.Basic.Users))
+	for _, u := range a.Basic.Users {
+		users = append(users, u.Username)
+	}
+	return fmt.Sprintf("basic(realm=%s,users=%v)", a.Basic.Realm, users)
+}
+
+// prettyURLsSummary returns the short description of the pretty URLs settings.
+func prettyURLsSummary(p config.PrettyURLs) string {
+	if !p.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("trailingSlash=%s", p.TrailingSlash)
+}
+
+// loggingSummary returns the short description of the logging settings.
+func loggingSummary(l config.Logging, bucket model.BucketName) string {
+	if !l.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("bucket=%s,expirationDays=%d", l.LogBucket(bucket), l.ExpirationDays)
+}
+
+// storageSummary returns the short description of the bucket settings.
+func storageSummary(s config.Storage) string {
+	summary := fmt.Sprintf("versioning=%t,objectOwnership=%s", s.Versioning, s.ObjectOwnership)
+	if s.Versioning && s.Lifecycle.NoncurrentVersionExpirationDays > 0 {
+		summary += fmt.Sprintf(",noncurrentVersionExpirationDays=%d", s.Lifecycle.NoncurrentVersionExpirationDays)
+	}
+	if s.Lifecycle.AbortIncompleteMultipartUploadDays > 0 {
+		summary += fmt.Sprintf(",abortIncompleteMultipartUploadDays=%d", s.Lifecycle.AbortIncompleteMultipartUploadDays)
+	}
+	for _, t := range s.Lifecycle.Transitions {
+		summary += fmt.Sprintf(",%s->%s(%dd)", t.Prefix, t.StorageClass, t.Days)
+	}
+	return summary
+}
+
+// storageEndpointSummary returns the short description of the S3-compatible storage.
+func storageEndpointSummary(s *model.StorageEndpoint) string {
+	summary := fmt.Sprintf("url=%s,provider=%s,pathStyle=%t,signingRegion=%s", s.URL, s.Provider, s.PathStyle, s.SigningRegion)
+	if s.UnsignedPayload {
+		summary += ",unsignedPayload"
+	}
+	if len(s.CABundle) > 0 {
+		summary += ",caBundle"
+	}
+	if s.InsecureSkipVerify {
+		summary += ",insecureSkipVerify"
+	}
+	if s.PublicURL != "" {
+		summary += ",publicUrl=" + s.PublicURL
+	}
+	return summary
+}
+
+// encryptionSummary returns the short description of the server-side encryption.
+func encryptionSummary(e config.Encryption, bucket model.BucketName) string {
+	settings := e.Settings(bucket)
+	switch {
+	case settings == nil:
+		return "unchanged"
+	case settings.CreateKey:
+		return fmt.Sprintf("%s,kmsKey=%s(created if not exists)", settings.Type, settings.KMSKeyID)
+	case settings.KMS():
+		return fmt.Sprintf("%s,kmsKey=%s", settings.Type, settings.KMSKeyID)
+	default:
+		return settings.Type.String()
+	}
+}
+
+// tagsSummary returns the short description of the tags.
+func tagsSummary(tags model.Tags) string {
+	pairs := make([]string, 0, len(tags))
+	for _, k := range tags.Keys() {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -3,11 +3,18 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"os/user"
 	"path/filepath"
+	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
 	"github.com/nao1215/spare/app/di"
 	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
 	"github.com/nao1215/spare/config"
 	"github.com/nao1215/spare/utils/errfmt"
 	"github.com/spf13/cobra"
@@ -26,6 +33,10 @@ type commonOption struct {
 	configFilePath string
 	// awsProfile is a profile name of AWS. If this is empty, use $AWS_PROFILE.
 	awsProfile model.AWSProfile
+	// credentials is how spare gets the credentials of AWS (the profile and the role to assume).
+	credentials *model.AWSCredentials
+	// storage is the S3-compatible storage. If it's nil, the bucket is in Amazon S3.
+	storage *model.StorageEndpoint
 }
 
 // Parse parses the arguments and flags.
@@ -52,13 +63,24 @@ func parseCommon(cmd *cobra.Command, _ []string) (*commonOption, error) {
 		return nil, err
 	}
 
+	credentials, err := parseCredentials(cmd, awsProfile, config, debug)
+	if err != nil {
+		return nil, err
+	}
+
 	var endpoint *model.Endpoint
+	var storage *model.StorageEndpoint
 	if debug {
 		endpoint = &config.DebugLocalstackEndpoint
+	} else if storage, err = config.Storage.Endpoint.Settings(); err != nil {
+		return nil, err
+	}
+	if storage != nil && credentials.AssumeRole != nil {
+		return nil, errors.New("the role can not be assumed with storage.endpoint, because the S3-compatible storage is not AWS")
 	}
 
 	// Create a new instance of the Spare struct using the di.NewSpare function
-	spare, err := di.NewSpare(awsProfile, config.Region, endpoint)
+	spare, err := di.NewSpare(credentials, config.Region, endpoint, storage)
 	if err != nil {
 		return nil, err
 	}
@@ -69,9 +91,129 @@ func parseCommon(cmd *cobra.Command, _ []string) (*commonOption, error) {
 		configFilePath: configFilePath,
 		debug:          debug,
 		awsProfile:     awsProfile,
+		credentials:    credentials,
+		storage:        storage,
 	}, nil
 }
 
+// requireAWS returns an error if the bucket is in the S3-compatible storage. The command needs CloudFront
+// or the other AWS services, so it can not be used with storage.endpoint.
+func requireAWS(cmd *cobra.Command, storage *model.StorageEndpoint) error {
+	if storage == nil {
+		return nil
+	}
+	return fmt.Errorf("'%s' needs CloudFront or the other AWS services, so it can not be used with storage.endpoint (%s)",
+		cmd.CommandPath(), storage.Provider)
+}
+
+// addCredentialFlags adds the flags of the role to assume. They override assumeRole in the config file.
+func addCredentialFlags(cmd *cobra.Command) {
+	cmd.Flags().String("role-arn", "", "ARN of the IAM role to assume. if this is empty, use assumeRole.roleArn in the config file")
+	cmd.Flags().String("external-id", "", "external ID that the trust policy of the role requires")
+	cmd.Flags().String("role-session-name", "", "name of the role session in CloudTrail")
+	cmd.Flags().String("mfa-serial", "", "ARN of the MFA device that the trust policy of the role requires. spare prompts the token code")
+	cmd.Flags().String("web-identity-token-file", "", "path of the OIDC token file of the CI. the role is assumed with the token")
+}
+
+// parseCredentials returns the profile and the role to assume. The flags override assumeRole in the config file.
+// In debug mode, the role is not assumed, because localstack accepts any credentials.
+func parseCredentials(cmd *cobra.Command, profile model.AWSProfile, cfg *config.Config, debug bool) (*model.AWSCredentials, error) {
+	role := cfg.AssumeRole.Settings()
+	if role == nil {
+		role = &model.AssumeRole{}
+	}
+	flags := []struct {
+		name  string
+		value *string
+	}{
+		{name: "role-arn", value: &role.RoleARN},
+		{name: "external-id", value: &role.ExternalID},
+		{name: "role-session-name", value: &role.SessionName},
+		{name: "mfa-serial", value: &role.MFASerial},
+		{name: "web-identity-token-file", value: &role.WebIdentityTokenFile},
+	}
+	for _, f := range flags {
+		v, err := cmd.Flags().GetString(f.name)
+		if err != nil {
+			return nil, errfmt.Wrap(err, fmt.Sprintf("can not parse command line argument (--%s)", f.name))
+		}
+		if v != "" {
+			*f.value = v
+		}
+	}
+
+	credentials := model.NewAWSCredentials(profile)
+	credentials.MFATokenProvider = promptMFAToken
+	if *role != (model.AssumeRole{}) && !debug {
+		credentials.AssumeRole = role
+	}
+	if err := credentials.Validate(); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// promptMFAToken prompts the token code of the MFA device. It's called once per command,
+// because the clients share the credentials.
+func promptMFAToken() (string, error) {
+	var code string
+	if err := survey.AskOne(&survey.Input{Message: "MFA token code:"}, &code, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(code), nil
+}
+
+// resolveIdentity returns the AWS account and the IAM identity of the credentials. If the AWS SSO session
+// of the profile is expired or not logged in, it asks if you want to run 'aws sso login', and resolves it again.
+func resolveIdentity(ctx context.Context, s *di.Spare, profile model.AWSProfile) (*model.CallerIdentity, error) {
+	output, err := s.IdentityResolver.ResolveIdentity(ctx, &usecase.ResolveIdentityInput{Profile: profile})
+	if err != nil {
+		return nil, err
+	}
+	if !output.SSOLoginRequired {
+		return output.Identity, nil
+	}
+
+	if err := ssoLogin(ctx, profile); err != nil {
+		return nil, err
+	}
+	if output, err = s.IdentityResolver.ResolveIdentity(ctx, &usecase.ResolveIdentityInput{Profile: profile}); err != nil {
+		return nil, err
+	}
+	if output.SSOLoginRequired {
+		return nil, fmt.Errorf("the AWS SSO session of %s can not be used after 'aws sso login'", profile)
+	}
+	return output.Identity, nil
+}
+
+// ssoLogin asks if you want to run 'aws sso login', and runs it. The AWS CLI opens the browser to log in.
+func ssoLogin(ctx context.Context, profile model.AWSProfile) error {
+	log.Warn("[  SSO   ] the AWS SSO session is expired or not logged in", "profile", profile)
+	var result bool
+	if err := survey.AskOne(
+		&survey.Confirm{
+			Message: fmt.Sprintf("want to run 'aws sso login --profile %s'?", profile),
+			Default: true,
+		},
+		&result,
+	); err != nil {
+		return err
+	}
+	if !result {
+		return fmt.Errorf("run 'aws sso login --profile %s', and try again", profile)
+	}
+
+	login := exec.CommandContext(ctx, "aws", "sso", "login", "--profile", profile.String()) //nolint:gosec
+	login.Stdin = os.Stdin
+	login.Stdout = os.Stdout
+	login.Stderr = os.Stderr
+	if err := login.Run(); err != nil {
+		return errfmt.Wrap(err, "'aws sso login' failed")
+	}
+	log.Info("[  SSO   ] logged in", "profile", profile)
+	return nil
+}
+
 // readConfig reads .spare.yml and returns config.Config.
 func readConfig(configFilePath string) (*config.Config, error) {
 	file, err := os.Open(filepath.Clean(configFilePath))
@@ -90,3 +232,85 @@ func readConfig(configFilePath string) (*config.Config, error) {
 	}
 	return cfg, nil
 }
+
+// writeConfig writes config.Config to .spare.yml.
+// Comments in .spare.yml are not kept, because the file is generated from config.Config.
+func writeConfig(configFilePath string, cfg *config.Config) (err error) {
+	file, err := os.Create(filepath.Clean(configFilePath))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return cfg.Write(file)
+}
+
+// viewerRequest returns the features of the viewer request function in the config.
+// The warnings about the unsupported rules in the _redirects file are logged.
+// It's validated even if the command does not validate the config, because an invalid basic auth locks out all viewers.
+func viewerRequest(cfg *config.Config) (*model.ViewerRequest, error) {
+	v, warnings, err := cfg.ViewerRequest()
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		log.Warn("[REDIRECT] " + w)
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// gitRevision returns the commit hash of HEAD of the repository that contains dir (e.g. the deploy target).
+// spare can run outside the project, so git runs in dir, not in the current directory.
+// If git is not installed or dir is not in a git repository, it returns empty string.
+func gitRevision(ctx context.Context, dir string) string {
+	git := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	git.Dir = dir
+	out, err := git.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitBranch returns the branch name of HEAD of the repository that contains dir (e.g. the deploy target).
+// If git is not installed or dir is not in a git repository, it returns empty string.
+func gitBranch(ctx context.Context, dir string) string {
+	git := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	git.Dir = dir
+	out, err := git.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// currentUser returns the name of the user who runs the spare command.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// humanizeBytes returns the human readable representation of the size. e.g. 1.5 MiB
+func humanizeBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -2,16 +2,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/nao1215/spare/app/di"
 	"github.com/nao1215/spare/app/domain/model"
 	"github.com/nao1215/spare/app/usecase"
 	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
 	"github.com/nao1215/spare/utils/file"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
@@ -21,16 +24,32 @@ import (
 // newDeployCmd return deploy sub command.
 func newDeployCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "deploy",
-		Short:   "deploy SPA to AWS",
-		Example: "   spare deploy",
+		Use:   "deploy",
+		Short: "deploy SPA to AWS as a new release",
+		Long: `deploy uploads SPA to the new release prefix (releases/<RELEASE_ID>/) in the S3 bucket.
+After all files are uploaded, CloudFront is switched to the new release at once.
+If the upload fails, the live release is not changed.
+
+With --canary or --canary-header, the new release is served only to a part of the viewers
+by the CloudFront staging distribution (continuous deployment). Then, run 'spare promote'
+to make it live, or 'spare abort' to stop it.
+
+Before deploying, the preflight checks of 'spare doctor' run, and deploy stops if a check fails.
+
+With storage.endpoint in .spare.yml (e.g. MinIO, Cloudflare R2), the new release is copied to the root
+of the bucket instead of switching CloudFront. The canary release and the preflight checks are not available.`,
+		Example: "   spare deploy\n   spare deploy --canary 5\n   spare deploy --canary-header aws-cf-cd-canary=true",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return Run(cmd, args, &deployer{})
 		},
 	}
 	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
 	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
 	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().Float64("canary", 0, "percentage of the traffic sent to the new release (0 < canary <= 15)")
+	cmd.Flags().String("canary-header", "", "send the viewers with this header to the new release (NAME=VALUE, NAME starts with aws-cf-cd-)")
+	cmd.Flags().Bool("skip-doctor", false, "skip the preflight checks of 'spare doctor'")
 	return cmd
 }
 
@@ -45,10 +64,27 @@ type deployer struct {
 	debug bool
 	// awsProfile is a profile name of AWS. If this is empty, use $AWS_PROFILE.
 	awsProfile model.AWSProfile
+	// credentials is how spare gets the credentials of AWS (the profile and the role to assume).
+	credentials *model.AWSCredentials
+	// release is the release to deploy.
+	release *model.Release
+	// canary is how CloudFront routes the viewers to the new release. If this is nil, all viewers get the new release.
+	canary *model.CanaryTraffic
+	// skipDoctor is whether the preflight checks are skipped. They are always skipped in debug mode.
+	skipDoctor bool
+	// storage is the S3-compatible storage. If it's nil, the bucket is in Amazon S3.
+	storage *model.StorageEndpoint
 }
 
 // Parse parses the arguments and flags.
 func (d *deployer) Parse(cmd *cobra.Command, _ []string) (err error) {
+	if d.canary, err = parseCanary(cmd); err != nil {
+		return err
+	}
+	if d.skipDoctor, err = cmd.Flags().GetBool("skip-doctor"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--skip-doctor)")
+	}
+
 	commonOption, err := parseCommon(cmd, nil)
 	if err != nil {
 		return err
@@ -58,6 +94,12 @@ func (d *deployer) Parse(cmd *cobra.Command, _ []string) (err error) {
 	d.config = commonOption.config
 	d.debug = commonOption.debug
 	d.awsProfile = commonOption.awsProfile
+	d.credentials = commonOption.credentials
+	d.storage = commonOption.storage
+	if d.storage != nil && d.canary != nil {
+		return errors.New("the canary release needs CloudFront, so it can not be used with storage.endpoint")
+	}
+	d.release = model.NewRelease(time.Now(), gitRevision(d.ctx, d.config.DeployTarget.String()), currentUser())
 
 	return nil
 }
@@ -66,35 +108,264 @@ func (d *deployer) Parse(cmd *cobra.Command, _ []string) (err error) {
 func (d *deployer) Do() error {
 	log.Info("[  MODE  ]", "debug", d.debug)
 	log.Info("[ CONFIG ]", "profile", d.awsProfile)
+	if d.storage != nil {
+		log.Info("[ CONFIG ]", "storage", d.storage.URL, "provider", d.storage.Provider)
+	} else {
+		if d.credentials.AssumeRole != nil {
+			log.Info("[ CONFIG ]", "assume role", d.credentials.AssumeRole)
+		}
+		// The preflight checks run first, so bad credentials are reported with the hints of 'spare doctor'.
+		identity, err := d.preflight()
+		if err != nil {
+			return err
+		}
+		log.Info("[ CONFIG ]", "account", identity.Account, "identity", identity.ARN)
+	}
 	log.Info("[ DEPLOY ]", "target path", d.config.DeployTarget, "bucket name", d.config.S3BucketName)
+	log.Info("[ DEPLOY ]", "release", d.release.ID, "git sha", d.release.GitSHA, "user", d.release.User)
+
+	if d.canary == nil {
+		if err := d.validateNoCanary(); err != nil {
+			return err
+		}
+	}
+	if err := d.recordManifest(); err != nil {
+		return err
+	}
+	if _, err := uploadFiles(d.ctx, d.spare, d.config, d.release.ID.Prefix()); err != nil {
+		log.Error("[ DEPLOY ] upload failed. the live release is not changed", "release", d.release.ID)
+		return err
+	}
+	if err := uploadMaintenancePage(d.ctx, d.spare, d.config, d.release.ID.Prefix()); err != nil {
+		log.Error("[ DEPLOY ] upload failed. the live release is not changed", "release", d.release.ID)
+		return err
+	}
+
+	if d.storage != nil {
+		log.Info("[REDIRECT] skip the redirect rules and the pretty URLs. the S3-compatible storage has no CloudFront Function")
+	} else if err := d.applyViewerRequest(); err != nil {
+		return err
+	}
+
+	if d.canary != nil {
+		return d.deployCanary()
+	}
+
+	if d.storage != nil {
+		log.Info("[PUBLISH ] copy the new release to the root of the bucket", "release", d.release.ID)
+	} else {
+		log.Info("[PUBLISH ] switch cloudfront to the new release", "release", d.release.ID)
+	}
+	output, err := d.spare.ReleasePublisher.PublishRelease(d.ctx, &usecase.PublishReleaseInput{
+		BucketName: d.config.S3BucketName,
+		Release:    d.release,
+		AllowNoCDN: d.debug,
+	})
+	if err != nil {
+		return err
+	}
+	if !output.Switched {
+		log.Warn("[PUBLISH ] no cloudfront distribution in debug mode. the release is uploaded and recorded, but not live", "release", d.release.ID)
+		return nil
+	}
+	log.Info("[PUBLISH ] done", "release", d.release.ID, "domain", output.Domain)
+	return nil
+}
 
-	files, err := file.WalkDir(d.config.DeployTarget.String())
+// preflight runs the preflight checks and returns the identity of the credentials.
+// If the checks are skipped, the identity is resolved without them.
+func (d *deployer) preflight() (*model.CallerIdentity, error) {
+	if d.debug || d.skipDoctor {
+		return resolveIdentity(d.ctx, d.spare, d.awsProfile)
+	}
+	return runPreflight(d.ctx, d.spare, d.config, d.awsProfile, &preflightOptions{
+		commands:          []model.IAMCommand{model.IAMCommandDeploy},
+		checkDeployTarget: true,
+		bucketMustExist:   true,
+		canary:            d.canary != nil,
+	})
+}
+
+// validateNoCanary stops the deploy before uploading anything while the canary release is in progress.
+// 'spare promote' would undo the new live release. PublishRelease checks it again before switching.
+func (d *deployer) validateNoCanary() error {
+	output, err := d.spare.ReleaseLister.ListReleases(d.ctx, &usecase.ListReleasesInput{
+		BucketName: d.config.S3BucketName,
+	})
 	if err != nil {
 		return err
 	}
+	return output.History.ValidateNotStaged()
+}
 
-	eg, ctx := errgroup.WithContext(d.ctx)
+// recordManifest records the S3 keys of the new release before uploading them,
+// so 'spare gc' never deletes the files of this deploy while it's uploading them.
+func (d *deployer) recordManifest() error {
+	keys, err := releaseKeys(d.config, d.release.ID.Prefix())
+	if err != nil {
+		return err
+	}
+	if _, err := d.spare.ReleaseManifestRecorder.RecordReleaseManifest(d.ctx, &usecase.RecordReleaseManifestInput{
+		BucketName: d.config.S3BucketName,
+		Manifest:   model.NewReleaseManifest(d.release.ID, keys),
+	}); err != nil {
+		return err
+	}
+	log.Info("[ DEPLOY ] record the manifest", "release", d.release.ID, "files", len(keys))
+	return nil
+}
+
+// applyViewerRequest publishes the redirect rules in the _redirects file with the other features of the viewer request function.
+// The function is shared by the live release and the canary release.
+func (d *deployer) applyViewerRequest() error {
+	v, err := viewerRequest(d.config)
+	if err != nil {
+		return err
+	}
+	log.Info("[REDIRECT] apply the redirect rules", "rules", len(v.Redirects))
+	output, err := d.spare.ViewerRequestApplier.ApplyViewerRequest(d.ctx, &usecase.ApplyViewerRequestInput{
+		BucketName:    d.config.S3BucketName,
+		ViewerRequest: v,
+		AllowNoCDN:    d.debug,
+	})
+	if err != nil {
+		return err
+	}
+	if !output.Applied {
+		log.Warn("[REDIRECT] no cloudfront distribution in debug mode. the redirect rules are not applied")
+	}
+	return nil
+}
+
+// deployCanary serves the uploaded release to a part of the viewers.
+func (d *deployer) deployCanary() error {
+	log.Info("[ CANARY ] switch cloudfront staging distribution to the new release", "release", d.release.ID, "traffic", d.canary)
+	output, err := d.spare.CanaryDeployer.DeployCanary(d.ctx, &usecase.DeployCanaryInput{
+		BucketName: d.config.S3BucketName,
+		Release:    d.release,
+		Traffic:    *d.canary,
+		Encryption: d.config.Encryption.Settings(d.config.S3BucketName),
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[ CANARY ] done. run 'spare promote' or 'spare abort'", "release", d.release.ID, "staging domain", output.StagingDomain)
+	return nil
+}
+
+// parseCanary parses --canary and --canary-header. If both are not specified, it returns nil.
+func parseCanary(cmd *cobra.Command) (*model.CanaryTraffic, error) {
+	weight, err := cmd.Flags().GetFloat64("canary")
+	if err != nil {
+		return nil, errfmt.Wrap(err, "can not parse command line argument (--canary)")
+	}
+	header, err := cmd.Flags().GetString("canary-header")
+	if err != nil {
+		return nil, errfmt.Wrap(err, "can not parse command line argument (--canary-header)")
+	}
+	if !cmd.Flags().Changed("canary") && header == "" {
+		return nil, nil
+	}
+
+	traffic := model.NewWeightCanaryTraffic(weight)
+	if header != "" {
+		if traffic, err = model.ParseHeaderCanaryTraffic(header); err != nil {
+			return nil, err
+		}
+		traffic.Weight = weight
+	}
+	if err := traffic.Validate(); err != nil {
+		return nil, err
+	}
+	return &traffic, nil
+}
+
+// uploadFiles uploads all files in the deploy target to S3 under the prefix concurrently.
+// The _redirects file and the _headers file are not uploaded. The headers in the _headers file
+// are set as the object metadata. It returns the uploaded S3 keys.
+func uploadFiles(ctx context.Context, spare *di.Spare, cfg *config.Config, prefix string) ([]string, error) {
+	files, err := file.WalkDir(cfg.DeployTarget.String())
+	if err != nil {
+		return nil, err
+	}
+	headerRules, warnings, err := cfg.DeployTarget.HeaderRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		log.Warn("[ HEADER ] " + w)
+	}
+
+	keys := make([]string, 0, len(files))
+	eg, egCtx := errgroup.WithContext(ctx)
 	weighted := semaphore.NewWeighted(int64(runtime.NumCPU()))
 	for _, file := range files {
 		file := file
+		if cfg.DeployTarget.IsRulesFile(file) {
+			continue
+		}
+		path := relativePath(cfg, file)
+		key := prefix + path
+		headers := headerRules.ObjectHeaders(path)
+		keys = append(keys, key)
 		eg.Go(func() error {
-			if err := weighted.Acquire(ctx, 1); err != nil {
+			if err := weighted.Acquire(egCtx, 1); err != nil {
 				return err
 			}
 			defer weighted.Release(1)
 
-			return d.uploadFile(ctx, file)
+			return uploadFile(egCtx, spare, cfg, file, key, headers)
 		})
 	}
 
 	if err := eg.Wait(); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return keys, nil
+}
+
+// releaseKeys returns the S3 keys that uploadFiles and uploadMaintenancePage upload under the prefix.
+func releaseKeys(cfg *config.Config, prefix string) ([]string, error) {
+	files, err := file.WalkDir(cfg.DeployTarget.String())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(files)+1)
+	for _, file := range files {
+		if cfg.DeployTarget.IsRulesFile(file) {
+			continue
+		}
+		keys = append(keys, prefix+relativePath(cfg, file))
+	}
+	if cfg.Maintenance.Page != "" {
+		if _, err := os.Stat(cfg.Maintenance.Page); err == nil {
+			keys = append(keys, prefix+model.MaintenancePagePath)
+		}
+	}
+	return keys, nil
 }
 
-// uploadFile uploads a file to S3.
-func (d *deployer) uploadFile(ctx context.Context, file string) error {
+// relativePath returns the slash-separated path of the file in the deploy target.
+// e.g. src/index.html -> index.html, it's uploaded to releases/20231019T120000Z-k3x9q2/index.html
+func relativePath(cfg *config.Config, file string) string {
+	return filepath.ToSlash(strings.Replace(file, cfg.DeployTarget.String()+string(filepath.Separator), "", 1))
+}
+
+// uploadMaintenancePage uploads the maintenance page to the release under the prefix,
+// so the maintenance mode keeps working after CloudFront is switched to the release. If the page does not exist, it does nothing.
+func uploadMaintenancePage(ctx context.Context, spare *di.Spare, cfg *config.Config, prefix string) error {
+	if cfg.Maintenance.Page == "" {
+		return nil
+	}
+	if _, err := os.Stat(cfg.Maintenance.Page); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return uploadFile(ctx, spare, cfg, cfg.Maintenance.Page, prefix+model.MaintenancePagePath, model.MaintenancePageHeaders())
+}
+
+// uploadFile uploads a file to S3 with the headers.
+func uploadFile(ctx context.Context, spare *di.Spare, cfg *config.Config, file, key string, headers []model.HTTPHeader) (err error) {
 	f, err := os.Open(filepath.Clean(file))
 	if err != nil {
 		return err
@@ -105,13 +376,13 @@ func (d *deployer) uploadFile(ctx context.Context, file string) error {
 		}
 	}()
 
-	key := strings.Replace(file, d.config.DeployTarget.String()+string(filepath.Separator), "", 1)
-	output, err := d.spare.FileUploader.UploadFile(ctx, &usecase.UploadFileInput{
-		BucketName: d.config.S3BucketName,
-		Region:     d.config.Region,
-		// e.g. src/index.html -> index.html
-		Key:  key,
-		Data: f,
+	output, err := spare.FileUploader.UploadFile(ctx, &usecase.UploadFileInput{
+		BucketName: cfg.S3BucketName,
+		Region:     cfg.Region,
+		Key:        key,
+		Data:       f,
+		Headers:    headers,
+		Encryption: cfg.Encryption.Settings(cfg.S3BucketName),
 	})
 	if err != nil {
 		return err
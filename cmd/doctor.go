@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// checkDeployTarget is the name of the check of the deploy target.
+const checkDeployTarget = "deploy target"
+
+// newDoctorCmd return doctor sub command.
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "check that spare can build and deploy with your AWS credentials",
+		Long: `doctor runs the preflight checks and shows the checklist with the hint for each problem.
+
+ - deploy target: deployTarget in .spare.yml is a directory that contains index.html
+ - aws profile: the profile is in ~/.aws/config or ~/.aws/credentials
+ - credentials: the credentials are resolved (STS GetCallerIdentity)
+ - region: the region is enabled in the account (the opt-in regions only)
+ - s3 bucket: the bucket is owned by you, or the bucket name is available (S3 HeadBucket)
+ - iam permissions: your IAM policies allow the actions of 'spare build' and 'spare deploy' (IAM policy simulator)
+
+The same checks run at the start of 'spare build' and 'spare deploy'. A WARN means that spare could not
+confirm the check (e.g. no permission to run the IAM policy simulator), and does not stop them.`,
+		Example: "   spare doctor\n   spare doctor --output json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &doctor{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("output", "o", "table", "output format (table or json)")
+	return cmd
+}
+
+type doctor struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the doctor command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// awsProfile is a profile name of AWS. If this is empty, use $AWS_PROFILE.
+	awsProfile model.AWSProfile
+	// output is the output format. table or json.
+	output string
+}
+
+// Parse parses the arguments and flags.
+func (d *doctor) Parse(cmd *cobra.Command, _ []string) (err error) {
+	if d.output, err = cmd.Flags().GetString("output"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--output)")
+	}
+	if d.output != "table" && d.output != "json" {
+		return fmt.Errorf("--output must be table or json: %s", d.output)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	d.ctx = commonOption.ctx
+	d.spare = commonOption.spare
+	d.config = commonOption.config
+	d.awsProfile = commonOption.awsProfile
+	return nil
+}
+
+// Do run the preflight checks and show the checklist.
+func (d *doctor) Do() error {
+	output, err := preflight(d.ctx, d.spare, d.config, d.awsProfile, &preflightOptions{
+		commands:          []model.IAMCommand{model.IAMCommandBuild, model.IAMCommandDeploy},
+		checkDeployTarget: true,
+		bucketMustExist:   false,
+	})
+	if err != nil {
+		return err
+	}
+	checklist := output.Checklist
+
+	if d.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(checklist); err != nil {
+			return err
+		}
+		return checklist.Err()
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "STATUS\tCHECK\tDETAIL")
+	for _, c := range checklist {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Status, c.Name, c.Detail)
+		if c.Hint != "" {
+			fmt.Fprintf(w, "\t\thint: %s\n", c.Hint)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return checklist.Err()
+}
+
+// preflightOptions is the options of the preflight checks.
+type preflightOptions struct {
+	// commands is the spare commands whose IAM permissions are checked.
+	commands []model.IAMCommand
+	// checkDeployTarget is whether the deploy target is checked.
+	checkDeployTarget bool
+	// bucketMustExist is whether the bucket must already be owned by the account.
+	bucketMustExist bool
+	// canary is whether the IAM permissions of the canary release are checked.
+	canary bool
+}
+
+// preflight runs the preflight checks and returns the checklist and the identity of the credentials.
+// The deploy target is checked first, and then the checks that need AWS are run.
+func preflight(ctx context.Context, s *di.Spare, cfg *config.Config, profile model.AWSProfile, opts *preflightOptions) (*usecase.RunPreflightChecksOutput, error) {
+	checklist := model.Checklist{}
+	if opts.checkDeployTarget {
+		check := model.Check{Name: checkDeployTarget, Status: model.CheckStatusPass, Detail: cfg.DeployTarget.String() + " contains index.html"}
+		if err := cfg.DeployTarget.ValidateContents(); err != nil {
+			check.Status = model.CheckStatusFail
+			check.Detail = err.Error()
+			check.Hint = "build the SPA, or set deployTarget in .spare.yml to the directory of the build output"
+		}
+		checklist = append(checklist, check)
+	}
+
+	region, err := cfg.RegionInfo()
+	if err != nil {
+		return nil, err
+	}
+	iamOptions := cfg.IAMPermissionOptions(opts.commands...)
+	iamOptions.Canary = opts.canary
+	permissions, err := model.NewIAMPermissions(iamOptions)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.PreflightChecker.RunPreflightChecks(ctx, &usecase.RunPreflightChecksInput{
+		Profile:         profile,
+		Region:          region,
+		BucketName:      cfg.S3BucketName,
+		BucketMustExist: opts.bucketMustExist,
+		Permissions:     permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output.Checklist = append(checklist, output.Checklist...)
+	return output, nil
+}
+
+// runPreflight runs the preflight checks at the start of the command, logs the checklist, and returns
+// the identity of the credentials. It returns an error if a check failed.
+func runPreflight(ctx context.Context, s *di.Spare, cfg *config.Config, profile model.AWSProfile, opts *preflightOptions) (*model.CallerIdentity, error) {
+	log.Info("[ DOCTOR ] run the preflight checks")
+	output, err := preflight(ctx, s, cfg, profile, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range output.Checklist {
+		switch c.Status {
+		case model.CheckStatusFail:
+			log.Error("[ DOCTOR ] "+c.Name, "status", c.Status, "detail", c.Detail, "hint", c.Hint)
+		case model.CheckStatusWarn:
+			log.Warn("[ DOCTOR ] "+c.Name, "status", c.Status, "detail", c.Detail, "hint", c.Hint)
+		default:
+			log.Info("[ DOCTOR ] "+c.Name, "status", c.Status, "detail", c.Detail)
+		}
+	}
+	if err := output.Checklist.Err(); err != nil {
+		return nil, err
+	}
+	return output.Identity, nil
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newGCCmd return gc sub command.
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "delete old releases and unreferenced objects from S3",
+		Long: `gc deletes the releases that are not kept by the retention policy in .spare.yml.
+A release is kept if it is one of the last 'keepLast' releases or newer than 'keepDays' days.
+The live release and the 'pinned' releases are never deleted.
+
+deploy records the S3 keys of the release (manifest) before it uploads the files, and gc deletes only
+the objects under releases/ that belong to no manifest of the kept releases. The files of a deploy that
+is not recorded in the release history yet are kept however long the deploy takes, because it may be
+in progress. If the deploy failed, delete its files with --abandon.`,
+		Example: "   spare gc --dry-run\n   spare gc\n   spare gc --abandon 20231019T120000Z-k3x9q2",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &garbageCollector{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().Bool("dry-run", false, "only report the releases and objects to delete")
+	cmd.Flags().BoolP("yes", "y", false, "delete without confirmation")
+	cmd.Flags().StringSlice("abandon", nil, "release IDs of the failed deploys whose files are deleted")
+	return cmd
+}
+
+type garbageCollector struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the gc command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// debug is a flag that indicates whether to run debug mode.
+	debug bool
+	// dryRun is a flag that indicates whether to only report the objects to delete.
+	dryRun bool
+	// yes is a flag that indicates whether to delete without confirmation.
+	yes bool
+	// abandoned is the list of the failed deploys whose files are deleted.
+	abandoned []model.ReleaseID
+}
+
+// Parse parses the arguments and flags.
+func (g *garbageCollector) Parse(cmd *cobra.Command, _ []string) (err error) {
+	if g.dryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--dry-run)")
+	}
+	if g.yes, err = cmd.Flags().GetBool("yes"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--yes)")
+	}
+
+	abandoned, err := cmd.Flags().GetStringSlice("abandon")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--abandon)")
+	}
+	for _, a := range abandoned {
+		id := model.ReleaseID(a)
+		if err := id.Validate(); err != nil {
+			return err
+		}
+		g.abandoned = append(g.abandoned, id)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	g.ctx = commonOption.ctx
+	g.spare = commonOption.spare
+	g.config = commonOption.config
+	g.debug = commonOption.debug
+	return nil
+}
+
+// Do delete old releases and unreferenced objects.
+func (g *garbageCollector) Do() error {
+	if err := g.config.Validate(g.debug); err != nil {
+		return err
+	}
+
+	input := &usecase.CollectGarbageInput{
+		BucketName: g.config.S3BucketName,
+		Policy:     g.config.Retention.Policy(),
+		Now:        time.Now(),
+		Abandoned:  g.abandoned,
+		DryRun:     true,
+	}
+	report, err := g.spare.GarbageCollector.CollectGarbage(g.ctx, input)
+	if err != nil {
+		return err
+	}
+	g.report(report)
+
+	if g.dryRun || (len(report.Objects) == 0 && len(report.Expired) == 0) {
+		return nil
+	}
+	if !g.yes {
+		if err := g.confirm(); err != nil {
+			return err
+		}
+	}
+
+	input.DryRun = false
+	output, err := g.spare.GarbageCollector.CollectGarbage(g.ctx, input)
+	if err != nil {
+		return err
+	}
+	log.Info("[   GC   ] done", "deleted releases", len(output.Expired),
+		"deleted objects", len(output.Objects), "reclaimed", humanizeBytes(output.Objects.TotalSize()))
+	return nil
+}
+
+// report shows the releases and objects to delete.
+func (g *garbageCollector) report(output *usecase.CollectGarbageOutput) {
+	log.Info("[   GC   ] retention policy", "keepLast", g.config.Retention.KeepLast,
+		"keepDays", g.config.Retention.KeepDays, "pinned", len(g.config.Retention.Pinned))
+	for _, r := range output.Retained {
+		log.Info("[   GC   ] keep", "release", r.ID, "created at", r.CreatedAt.Format(time.RFC3339))
+	}
+	for _, r := range output.Expired {
+		log.Info("[   GC   ] delete", "release", r.ID, "created at", r.CreatedAt.Format(time.RFC3339))
+	}
+	for _, id := range output.Pending {
+		log.Warn("[   GC   ] keep the files of the deploy that is not recorded. if it failed, run 'spare gc --abandon "+id.String()+"'", "release", id)
+	}
+	log.Info("[   GC   ] unreachable objects", "count", len(output.Objects),
+		"reclaimable", humanizeBytes(output.Objects.TotalSize()), "dry run", g.dryRun)
+}
+
+// confirm asks if you want to delete the objects.
+func (g *garbageCollector) confirm() error {
+	var result bool
+	if err := survey.AskOne(
+		&survey.Confirm{
+			Message: fmt.Sprintf("want to delete the above releases and objects from %s?", g.config.S3BucketName),
+		},
+		&result,
+	); err != nil {
+		return err
+	}
+
+	if !result {
+		return errors.New("canceled")
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// iamPolicyFormatPolicy is the format of the identity-based policy.
+	iamPolicyFormatPolicy = "policy"
+	// iamPolicyFormatTrustPolicy is the format of the trust policy for GitHub OIDC.
+	iamPolicyFormatTrustPolicy = "trust-policy"
+	// iamPolicyFormatCloudFormation is the format of the CloudFormation template of the role for GitHub OIDC.
+	iamPolicyFormatCloudFormation = "cloudformation"
+)
+
+// newIAMPolicyCmd return iam-policy sub command.
+func newIAMPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam-policy",
+		Short: "print the least-privilege IAM policy that spare needs",
+		Long: `iam-policy prints the IAM policy JSON that allows only the actions of the commands and the features in .spare.yml.
+The policy is scoped to the bucket, the log bucket, the distribution, the functions of spare and the KMS key.
+The account and the distribution are looked up with your credentials unless --account and --distribution-id are set.
+If the distribution has not been built yet, the distribution actions are allowed on all distributions in the account.
+
+--command is build, deploy or both. deploy covers the commands that change only the releases
+(deploy, rollback, gc, preview, maintenance). Add --canary if you use 'spare deploy --canary', 'spare promote' and 'spare abort'.
+spare has no destroy command, so there is no policy for it.
+
+For keyless deploys from GitHub Actions, --format trust-policy prints the trust policy of the role for the GitHub OIDC provider,
+and --format cloudformation prints the CloudFormation template that creates the provider and the role with the policy.`,
+		Example: `   spare iam-policy
+   spare iam-policy --command deploy --canary
+   spare iam-policy --command deploy --format trust-policy --github-repo nao1215/spare --github-branch main
+   spare iam-policy --command deploy --format cloudformation --github-repo nao1215/spare --github-environment production`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &iamPolicyPrinter{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringSlice("command", []string{model.IAMCommandBuild.String(), model.IAMCommandDeploy.String()},
+		"commands that the policy allows (build, deploy)")
+	cmd.Flags().Bool("canary", false, "allow the canary release (deploy --canary, promote, abort)")
+	cmd.Flags().String("account", "", "AWS account ID. if this is empty, use the account of the credentials")
+	cmd.Flags().String("distribution-id", "", "CloudFront distribution ID. if this is empty, find the distribution of the bucket")
+	cmd.Flags().String("format", iamPolicyFormatPolicy, "output format (policy, trust-policy or cloudformation)")
+	cmd.Flags().String("github-repo", "", "GitHub repository (OWNER/REPO) that assumes the role with OIDC")
+	cmd.Flags().String("github-branch", "", "branch that can assume the role. if this is empty, any branch can")
+	cmd.Flags().String("github-environment", "", "GitHub environment that can assume the role")
+	cmd.Flags().String("role-name", "", "name of the role in the CloudFormation template. if this is empty, spare-<s3BucketName>")
+	return cmd
+}
+
+type iamPolicyPrinter struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the iam-policy command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// options is the commands and the features of the policy.
+	options model.IAMPermissionOptions
+	// format is the output format. policy, trust-policy or cloudformation.
+	format string
+	// github is the workflows that assume the role. It's used with trust-policy and cloudformation.
+	github model.GitHubOIDC
+	// roleName is the name of the role in the CloudFormation template.
+	roleName string
+}
+
+// Parse parses the arguments and flags.
+func (i *iamPolicyPrinter) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commands, err := cmd.Flags().GetStringSlice("command")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--command)")
+	}
+	canary, err := cmd.Flags().GetBool("canary")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--canary)")
+	}
+	account, err := cmd.Flags().GetString("account")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--account)")
+	}
+	distributionID, err := cmd.Flags().GetString("distribution-id")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--distribution-id)")
+	}
+	if i.format, err = cmd.Flags().GetString("format"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--format)")
+	}
+	if i.github.Repository, err = cmd.Flags().GetString("github-repo"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--github-repo)")
+	}
+	if i.github.Branch, err = cmd.Flags().GetString("github-branch"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--github-branch)")
+	}
+	if i.github.Environment, err = cmd.Flags().GetString("github-environment"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--github-environment)")
+	}
+	if i.roleName, err = cmd.Flags().GetString("role-name"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--role-name)")
+	}
+
+	switch i.format {
+	case iamPolicyFormatPolicy:
+	case iamPolicyFormatTrustPolicy, iamPolicyFormatCloudFormation:
+		if err := i.github.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--format must be policy, trust-policy or cloudformation: %s", i.format)
+	}
+
+	iamCommands := make([]model.IAMCommand, 0, len(commands))
+	for _, c := range commands {
+		command := model.IAMCommand(c)
+		if err := command.Validate(); err != nil {
+			return err
+		}
+		iamCommands = append(iamCommands, command)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	i.ctx = commonOption.ctx
+	i.spare = commonOption.spare
+	i.config = commonOption.config
+	if err := i.config.Validate(commonOption.debug); err != nil {
+		return err
+	}
+	i.options = i.config.IAMPermissionOptions(iamCommands...)
+	i.options.Canary = canary
+	i.options.Account = account
+	i.options.DistributionID = model.DistributionID(distributionID)
+	if i.roleName == "" {
+		i.roleName = model.NewRoleName(i.config.S3BucketName)
+	}
+	return nil
+}
+
+// Do print the IAM policy.
+func (i *iamPolicyPrinter) Do() error {
+	output, err := i.spare.IAMPolicyGenerator.GenerateIAMPolicy(i.ctx, &usecase.GenerateIAMPolicyInput{
+		Options: i.options,
+	})
+	if err != nil {
+		return err
+	}
+
+	var document interface{}
+	switch i.format {
+	case iamPolicyFormatTrustPolicy:
+		document = i.github.TrustPolicy(i.options.Partition, output.Account)
+	case iamPolicyFormatCloudFormation:
+		document = i.github.CloudFormationTemplate(i.roleName, output.Policy)
+	default:
+		document = output.Policy
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
@@ -2,31 +2,68 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/charmbracelet/log"
 	"github.com/nao1215/gorky/file"
+	"github.com/nao1215/spare/app/domain/model"
 	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
 	"github.com/spf13/cobra"
 )
 
 // newInitCmd return init sub command.
 func newInitCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "init",
-		Short:   "Generate .spare.yml at current directory",
-		Example: "   spare init",
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate .spare.yml at current directory",
+		Long: `init generates .spare.yml at current directory. The region is one of the following regions.
+The opt-in regions must be enabled in the AWS account before 'spare build'. If the region is newer
+than spare, set the region and regionOverride in .spare.yml by hand.
+
+` + regionsHelp(),
+		Example: "   spare init\n   spare init --region ap-northeast-1",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return Run(cmd, args, &initializer{})
 		},
 	}
+	cmd.Flags().StringP("region", "r", model.RegionUSEast1.String(), "AWS region of the S3 bucket and the KMS key")
+	return cmd
+}
+
+// regionsHelp returns the table of the regions in the region catalog.
+func regionsHelp() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "REGION\tNAME\tPARTITION\tOPT-IN\tCLOUDFRONT")
+	for _, r := range model.Regions() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\n", r.Region, r.DisplayName, r.Partition, r.OptIn, r.Supports(model.RegionFeatureCloudFront))
+	}
+	if err := w.Flush(); err != nil {
+		return ""
+	}
+	return b.String()
 }
 
-type initializer struct{}
+type initializer struct {
+	// region is the region in the generated .spare.yml.
+	region model.RegionInfo
+}
 
 // Parse parses the arguments and flags.
-func (i *initializer) Parse(_ *cobra.Command, _ []string) error {
-	return nil
+func (i *initializer) Parse(cmd *cobra.Command, _ []string) error {
+	region, err := cmd.Flags().GetString("region")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--region)")
+	}
+	if err := model.Region(region).Validate(); err != nil {
+		return errfmt.Wrap(err, "see 'spare init --help' for the regions")
+	}
+	i.region, _ = model.Region(region).Info()
+	return i.region.ValidateFeature(model.RegionFeatureCloudFront)
 }
 
 // Do generate .spare.yml at current directory.
@@ -46,10 +83,15 @@ func (i *initializer) Do() error {
 		}
 	}()
 
-	if err := config.NewConfig().Write(file); err != nil {
+	cfg := config.NewConfig()
+	cfg.Region = i.region.Region
+	if err := cfg.Write(file); err != nil {
 		return err
 	}
-	log.Info("[ CREATE ]", "config file name", config.ConfigFilePath)
+	log.Info("[ CREATE ]", "config file name", config.ConfigFilePath, "region", cfg.Region.String())
+	if i.region.OptIn {
+		log.Warn("[  WARN  ] the region is opt-in. enable it in the AWS account before 'spare build'", "region", cfg.Region.String())
+	}
 	log.Info("[  INFO  ] If you need to change the setting values, please refer to the documentation")
 	log.Info("[  INFO  ] https://github.com/nao1215/spare/blob/main/README.md")
 	return nil
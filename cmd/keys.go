@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newKeysCmd return keys sub command.
+func newKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "manage the keys for the signed URLs and the signed cookies",
+		Long: `keys manages the key group that the cache behaviors with 'signed: true' in .spare.yml trust.
+The public keys are registered to CloudFront, and the private keys are written to "<KEY_PAIR_ID>.pem".
+Spare never uploads the private keys. Keep them secret, and sign URLs with 'spare sign-url' or the signer package.`,
+	}
+	cmd.AddCommand(newKeysCreateCmd())
+	cmd.AddCommand(newKeysRotateCmd())
+	return cmd
+}
+
+// newKeysCreateCmd return keys create sub command.
+func newKeysCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "create the key group and the first key pair",
+		Long: `create generates a RSA key pair, registers the public key to CloudFront and creates the key group with it.
+Run it before 'spare build' if any cache behavior is signed.`,
+		Example: "   spare keys create --output-dir ./secrets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &keysCreator{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("output-dir", "o", ".", "directory where the private key is written")
+	return cmd
+}
+
+// newKeysRotateCmd return keys rotate sub command.
+func newKeysRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "add a new key pair to the key group and retire the old ones",
+		Long: `rotate generates a new RSA key pair and adds the public key to the key group.
+The previous public key is still trusted, so that the URLs signed with it are valid until they expire.
+The public keys older than the previous one are removed from the key group and deleted.`,
+		Example: "   spare keys rotate --output-dir ./secrets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &keysRotator{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("output-dir", "o", ".", "directory where the private key is written")
+	return cmd
+}
+
+// keysOption is the common option of the keys sub commands.
+type keysOption struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the keys command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// outputDir is the directory where the private key is written.
+	outputDir string
+}
+
+// parse parses the arguments and flags.
+func (k *keysOption) parse(cmd *cobra.Command) (err error) {
+	k.outputDir, err = cmd.Flags().GetString("output-dir")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--output-dir)")
+	}
+	if info, err := os.Stat(k.outputDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", k.outputDir)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	k.ctx = commonOption.ctx
+	k.spare = commonOption.spare
+	k.config = commonOption.config
+	return nil
+}
+
+// writePrivateKey writes the private key to "<KEY_PAIR_ID>.pem" in the output directory.
+func (k *keysOption) writePrivateKey(keyPairID string, pair *model.SigningKeyPair) (string, error) {
+	path := filepath.Join(k.outputDir, keyPairID+".pem")
+	if err := os.WriteFile(path, pair.PrivateKey, 0o600); err != nil {
+		return "", errfmt.Wrap(err,
+			fmt.Sprintf("failed to write the private key of %s. the private key is lost, so run 'spare keys rotate'", keyPairID))
+	}
+	return path, nil
+}
+
+type keysCreator struct {
+	keysOption
+}
+
+// Parse parses the arguments and flags.
+func (k *keysCreator) Parse(cmd *cobra.Command, _ []string) error {
+	return k.parse(cmd)
+}
+
+// Do create the key group and the first key pair.
+func (k *keysCreator) Do() error {
+	pair, err := model.NewSigningKeyPair()
+	if err != nil {
+		return err
+	}
+	output, err := k.spare.SigningKeyCreator.CreateSigningKey(k.ctx, &usecase.CreateSigningKeyInput{
+		BucketName: k.config.S3BucketName,
+		PublicKey:  pair.PublicKey,
+	})
+	if err != nil {
+		return err
+	}
+	path, err := k.writePrivateKey(output.KeyPairID, pair)
+	if err != nil {
+		return err
+	}
+	log.Info("[ CREATE ] key group", "id", output.KeyGroupID, "name", model.NewKeyGroupName(k.config.S3BucketName))
+	log.Info("[ CREATE ] key pair", "key pair id", output.KeyPairID, "private key", path)
+	return nil
+}
+
+type keysRotator struct {
+	keysOption
+}
+
+// Parse parses the arguments and flags.
+func (k *keysRotator) Parse(cmd *cobra.Command, _ []string) error {
+	return k.parse(cmd)
+}
+
+// Do add a new key pair to the key group and retire the old ones.
+func (k *keysRotator) Do() error {
+	pair, err := model.NewSigningKeyPair()
+	if err != nil {
+		return err
+	}
+	output, err := k.spare.SigningKeyRotator.RotateSigningKey(k.ctx, &usecase.RotateSigningKeyInput{
+		BucketName: k.config.S3BucketName,
+		PublicKey:  pair.PublicKey,
+	})
+	if err != nil {
+		return err
+	}
+	path, err := k.writePrivateKey(output.KeyPairID, pair)
+	if err != nil {
+		return err
+	}
+	log.Info("[ ROTATE ] key pair", "key pair id", output.KeyPairID, "private key", path)
+	if output.PreviousKeyPairID != "" {
+		log.Info("[ ROTATE ] previous key pair is trusted until the next rotation", "key pair id", output.PreviousKeyPairID)
+	}
+	for _, id := range output.RetiredKeyPairIDs {
+		log.Info("[ DELETE ] retired key pair", "key pair id", id)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newListCmd return list sub command.
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list the spare-managed stacks in the account",
+		Long: `list finds the resources that have the spare:project tag through the Resource Groups Tagging API,
+and groups them by the project. The resources in the region in .spare.yml and the CloudFront resources are listed.
+The resources are tagged by 'spare build', so the stacks built by the older spare are not listed until they are built again.`,
+		Example: "   spare list\n   spare list --output json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &stackLister{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("output", "o", "table", "output format (table or json)")
+	return cmd
+}
+
+type stackLister struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the list command.
+	spare *di.Spare
+	// output is the output format. table or json.
+	output string
+}
+
+// Parse parses the arguments and flags.
+func (s *stackLister) Parse(cmd *cobra.Command, _ []string) (err error) {
+	if s.output, err = cmd.Flags().GetString("output"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--output)")
+	}
+	if s.output != "table" && s.output != "json" {
+		return fmt.Errorf("--output must be table or json: %s", s.output)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	s.ctx = commonOption.ctx
+	s.spare = commonOption.spare
+	return nil
+}
+
+// Do list the spare-managed stacks.
+func (s *stackLister) Do() error {
+	output, err := s.spare.StackLister.ListStacks(s.ctx, &usecase.ListStacksInput{})
+	if err != nil {
+		return err
+	}
+
+	if s.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output.Stacks)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "PROJECT\tENV\tVERSION\tTYPE\tARN")
+	for _, stack := range output.Stacks {
+		for _, r := range stack.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", stack.Project, stack.Env, stack.Version, r.Type(), r.ARN)
+		}
+	}
+	return w.Flush()
+}
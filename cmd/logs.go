@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newLogsCmd return logs sub command.
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "summarize the CloudFront access logs",
+		Long: `logs downloads the CloudFront standard logs in the time window from the log bucket, and summarizes them locally.
+The report has the requests, the bytes served, the cache hit ratio, the status codes, the top paths, the top referrers
+and the 5-minute intervals where 4xx or 5xx spiked. The logs are delivered when 'logging.enabled' is true in .spare.yml.
+CloudFront delivers the logs up to an hour late, so the latest requests may not be in the report.`,
+		Example: "   spare logs\n   spare logs --since 2023-10-19T00:00:00Z --until 2023-10-20T00:00:00Z --output json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &logAnalyzer{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().String("since", "24h", "start of the time window. RFC3339 time or duration before now (e.g. 2023-10-19T00:00:00Z, 6h)")
+	cmd.Flags().String("until", "", "end of the time window. RFC3339 time or duration before now. if this is empty, use now")
+	cmd.Flags().Int("top", 10, "number of the paths and the referrers in the report") //nolint:gomnd
+	cmd.Flags().StringP("output", "o", "table", "output format (table or json)")
+	return cmd
+}
+
+type logAnalyzer struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the logs command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// since is the start of the time window.
+	since time.Time
+	// until is the end of the time window.
+	until time.Time
+	// top is the number of the paths and the referrers in the report.
+	top int
+	// output is the output format. table or json.
+	output string
+}
+
+// Parse parses the arguments and flags.
+func (l *logAnalyzer) Parse(cmd *cobra.Command, _ []string) (err error) {
+	now := time.Now().UTC()
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--since)")
+	}
+	if l.since, err = parseTimeFlag(since, now); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--since)")
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--until)")
+	}
+	if l.until, err = parseTimeFlag(until, now); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--until)")
+	}
+	if !l.since.Before(l.until) {
+		return fmt.Errorf("--since (%s) must be before --until (%s)", l.since.Format(time.RFC3339), l.until.Format(time.RFC3339))
+	}
+	if l.top, err = cmd.Flags().GetInt("top"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--top)")
+	}
+	if l.output, err = cmd.Flags().GetString("output"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--output)")
+	}
+	if l.output != "table" && l.output != "json" {
+		return fmt.Errorf("--output must be table or json: %s", l.output)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	l.ctx = commonOption.ctx
+	l.spare = commonOption.spare
+	l.config = commonOption.config
+	return nil
+}
+
+// parseTimeFlag parses the RFC3339 time or the duration before now. If s is empty, it returns now.
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return now, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is neither RFC3339 time nor duration", s)
+	}
+	return t.UTC(), nil
+}
+
+// Do summarize the CloudFront access logs.
+func (l *logAnalyzer) Do() error {
+	logBucket := l.config.Logging.LogBucket(l.config.S3BucketName)
+	if !l.config.Logging.Enabled {
+		log.Warn("[  LOGS  ] logging is disabled. only the logs delivered before are analyzed", "log bucket", logBucket.String())
+	}
+	output, err := l.spare.AccessLogAnalyzer.AnalyzeAccessLogs(l.ctx, &usecase.AnalyzeAccessLogsInput{
+		LogBucket: logBucket,
+		Since:     l.since,
+		Until:     l.until,
+		Top:       l.top,
+	})
+	if err != nil {
+		return err
+	}
+
+	if l.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output.Report)
+	}
+	log.Info("[  LOGS  ] analyzed the access logs", "log bucket", logBucket.String(), "files", output.Files)
+	return printAccessLogReport(output.Report)
+}
+
+// printAccessLogReport prints the report as tables.
+func printAccessLogReport(r *model.AccessLogReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintf(w, "SINCE\t%s\n", r.Since.Format(time.RFC3339))
+	fmt.Fprintf(w, "UNTIL\t%s\n", r.Until.Format(time.RFC3339))
+	fmt.Fprintf(w, "REQUESTS\t%d\n", r.Requests)
+	fmt.Fprintf(w, "BYTES\t%d\n", r.Bytes)
+	fmt.Fprintf(w, "CACHE HIT RATIO\t%.1f%%\n", r.CacheHitRatio*100) //nolint:gomnd
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	tables := []struct {
+		header string
+		counts []model.AccessLogCount
+	}{
+		{header: "STATUS", counts: r.StatusCodes},
+		{header: "PATH", counts: r.TopPaths},
+		{header: "REFERRER", counts: r.TopReferrers},
+	}
+	for _, t := range tables {
+		fmt.Println("")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+		fmt.Fprintf(w, "%s\tREQUESTS\tBYTES\n", t.header)
+		for _, c := range t.counts {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", c.Name, c.Requests, c.Bytes)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "ERROR SPIKE\tCLASS\tERRORS\tREQUESTS")
+	for _, s := range r.ErrorSpikes {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", s.Start.Format(time.RFC3339), s.Class, s.Errors, s.Requests)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/xrand"
+	"github.com/spf13/cobra"
+)
+
+// newMaintenanceCmd return maintenance sub command.
+func newMaintenanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "turn on or off the maintenance mode without deploying",
+		Long: `maintenance shows the maintenance page with 503 Service Unavailable and Retry-After to all viewers
+except the IP addresses in 'maintenance.allowIPs' and the viewers with the bypass cookie.
+The SPA is not redeployed. 'spare status' shows whether the maintenance mode is on.`,
+	}
+	cmd.AddCommand(newMaintenanceSwitchCmd(true))
+	cmd.AddCommand(newMaintenanceSwitchCmd(false))
+	return cmd
+}
+
+// newMaintenanceSwitchCmd return maintenance on or maintenance off sub command.
+func newMaintenanceSwitchCmd(on bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "off",
+		Short: "turn off the maintenance mode",
+		Long: `off removes the maintenance mode from the viewer request function, and detaches the viewer response function.
+The viewers get the live release again.`,
+		Example: "   spare maintenance off",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &maintenanceSwitcher{on: on})
+		},
+	}
+	if on {
+		cmd.Use = "on"
+		cmd.Short = "turn on the maintenance mode"
+		cmd.Long = `on uploads the maintenance page ('maintenance.page' in .spare.yml) to the live release and the canary release,
+and publishes the CloudFront Functions that serve it with 503 Service Unavailable.
+The value of the bypass cookie is shown. If the maintenance mode is already on, the value is not changed.`
+		cmd.Example = "   spare maintenance on"
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type maintenanceSwitcher struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the maintenance command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// on is whether the maintenance mode is turned on.
+	on bool
+}
+
+// Parse parses the arguments and flags.
+func (m *maintenanceSwitcher) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	m.ctx = commonOption.ctx
+	m.spare = commonOption.spare
+	m.config = commonOption.config
+	return nil
+}
+
+// Do turn on or off the maintenance mode.
+func (m *maintenanceSwitcher) Do() error {
+	v, err := viewerRequest(m.config)
+	if err != nil {
+		return err
+	}
+	input := &usecase.SwitchMaintenanceInput{
+		BucketName:    m.config.S3BucketName,
+		ViewerRequest: v,
+		Encryption:    m.config.Encryption.Settings(m.config.S3BucketName),
+	}
+	if m.on {
+		if input.Maintenance, input.Page, err = m.maintenance(); err != nil {
+			return err
+		}
+	}
+
+	output, err := m.spare.MaintenanceSwitcher.SwitchMaintenance(m.ctx, input)
+	if err != nil {
+		return err
+	}
+	if output.Maintenance == nil {
+		log.Info("[MAINTAIN] maintenance mode is off", "domain", output.Domain)
+		return nil
+	}
+	log.Info("[MAINTAIN] maintenance mode is on", "domain", output.Domain, "retry after", output.Maintenance.RetryAfter)
+	log.Info("[MAINTAIN] set the cookie to bypass it", "cookie", output.Maintenance.BypassCookie+"="+output.Maintenance.BypassToken)
+	return nil
+}
+
+// maintenance returns the maintenance mode and the maintenance page.
+func (m *maintenanceSwitcher) maintenance() (*model.Maintenance, []byte, error) {
+	const bypassTokenLen = 32
+	token, err := xrand.RandomLowerAlphanumericStr(bypassTokenLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	maintenance, err := m.config.Maintenance.Rules(token, currentUser(), time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	page, err := os.ReadFile(filepath.Clean(m.config.Maintenance.Page))
+	if err != nil {
+		return nil, nil, errfmt.Wrap(err, "failed to read the maintenance page")
+	}
+	return maintenance, page, nil
+}
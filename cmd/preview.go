@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/nao1215/spare/utils/file"
+	"github.com/spf13/cobra"
+)
+
+// newPreviewCmd return preview sub command.
+func newPreviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "manage preview environments for git branches",
+		Long: `preview manages the preview environments served from the same S3 bucket and CloudFront.
+Each preview is uploaded to previews/<NAME>/ and served at https://<CLOUDFRONT_DOMAIN>/previews/<NAME>/.
+Build the SPA with /previews/<NAME>/ as the base path, or the root-absolute URLs (e.g. /assets/app.js)
+are served from the live release.
+Previews that have not been deployed for 'preview.ttlDays' days are deleted automatically.`,
+	}
+	cmd.AddCommand(newPreviewDeployCmd())
+	cmd.AddCommand(newPreviewListCmd())
+	cmd.AddCommand(newPreviewDeleteCmd())
+	return cmd
+}
+
+// newPreviewDeployCmd return preview deploy sub command.
+func newPreviewDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "deploy SPA to AWS as a preview",
+		Long: `deploy uploads SPA to previews/<NAME>/ in the S3 bucket and routes /previews/<NAME>/ to it.
+If --name is omitted, the current git branch name is used. The live release is not changed.
+The SPA must be built with /previews/<NAME>/ as the base path. deploy warns about the root-absolute
+URLs in the HTML and CSS files, because they are served from the live release.
+After the deploy, stale previews are deleted.`,
+		Example: "   spare preview deploy\n   spare preview deploy --name feature-x",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &previewDeployer{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().StringP("name", "n", "", "preview name. if this is empty, use the current git branch name")
+	return cmd
+}
+
+type previewDeployer struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the preview deploy command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// debug is a flag that indicates whether to run debug mode.
+	debug bool
+	// preview is the preview to deploy.
+	preview *model.Preview
+}
+
+// Parse parses the arguments and flags.
+func (p *previewDeployer) Parse(cmd *cobra.Command, _ []string) (err error) {
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--name)")
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	p.ctx = commonOption.ctx
+	p.spare = commonOption.spare
+	p.config = commonOption.config
+	p.debug = commonOption.debug
+
+	if name == "" {
+		name = gitBranch(p.ctx, p.config.DeployTarget.String())
+	}
+	previewName := model.NewPreviewName(name)
+	if err := previewName.Validate(); err != nil {
+		return err
+	}
+	p.preview = &model.Preview{
+		Name:      previewName,
+		GitSHA:    gitRevision(p.ctx, p.config.DeployTarget.String()),
+		User:      currentUser(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+// Do deploy SPA to AWS as a preview.
+func (p *previewDeployer) Do() error {
+	if err := p.config.Validate(p.debug); err != nil {
+		return err
+	}
+	basicAuth, err := p.config.Auth.Basic.Rules()
+	if err != nil {
+		return err
+	}
+
+	log.Info("[PREVIEW ]", "name", p.preview.Name, "git sha", p.preview.GitSHA, "user", p.preview.User)
+	if err := p.checkBasePath(); err != nil {
+		return err
+	}
+	keys, err := uploadFiles(p.ctx, p.spare, p.config, p.preview.Name.Prefix())
+	if err != nil {
+		log.Error("[PREVIEW ] upload failed", "name", p.preview.Name)
+		return err
+	}
+
+	output, err := p.spare.PreviewPublisher.PublishPreview(p.ctx, &usecase.PublishPreviewInput{
+		BucketName:   p.config.S3BucketName,
+		Preview:      p.preview,
+		UploadedKeys: keys,
+		BasicAuth:    basicAuth,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[PREVIEW ] done", "name", p.preview.Name, "url", output.URL)
+
+	expired, err := p.spare.PreviewExpirer.ExpirePreviews(p.ctx, &usecase.ExpirePreviewsInput{
+		BucketName: p.config.S3BucketName,
+		TTL:        p.config.Preview.TTL(),
+		Now:        time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, preview := range expired.Expired {
+		log.Info("[PREVIEW ] expired", "name", preview.Name, "updated at", preview.UpdatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkBasePath warns about the root-absolute URLs in the HTML and CSS files. The preview is served under
+// /previews/<NAME>/, so the browser gets these URLs from the live release unless the SPA is built with the base path.
+func (p *previewDeployer) checkBasePath() error {
+	files, err := file.WalkDir(p.config.DeployTarget.String())
+	if err != nil {
+		return err
+	}
+
+	warned := 0
+	for _, f := range files {
+		ext := strings.ToLower(filepath.Ext(f))
+		if ext != ".html" && ext != ".css" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return err
+		}
+		urls := p.preview.Name.RootAbsoluteURLs(string(data))
+		if len(urls) == 0 {
+			continue
+		}
+		log.Warn("[PREVIEW ] root-absolute URLs are served from the live release, not from the preview", "file", f, "urls", len(urls), "e.g.", urls[0])
+		warned++
+	}
+	if warned > 0 {
+		log.Warn("[PREVIEW ] build the SPA with the base path of the preview (e.g. vite build --base "+p.preview.Name.Path()+")", "files", warned)
+	}
+	return nil
+}
+
+// newPreviewListCmd return preview list sub command.
+func newPreviewListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "list preview environments",
+		Long:    "list lists the previews recorded in the S3 bucket.",
+		Example: "   spare preview list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &previewLister{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type previewLister struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the preview list command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+}
+
+// Parse parses the arguments and flags.
+func (p *previewLister) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	p.ctx = commonOption.ctx
+	p.spare = commonOption.spare
+	p.config = commonOption.config
+	return nil
+}
+
+// Do list previews.
+func (p *previewLister) Do() error {
+	output, err := p.spare.PreviewLister.ListPreviews(p.ctx, &usecase.ListPreviewsInput{
+		BucketName: p.config.S3BucketName,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAME\tUPDATED AT\tGIT SHA\tUSER\tURL")
+	for _, preview := range output.List.Previews {
+		url := ""
+		if output.Domain != "" {
+			url = fmt.Sprintf("https://%s%s", output.Domain, preview.Name.Path())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			preview.Name, preview.UpdatedAt.Format(time.RFC3339), preview.GitSHA, preview.User, url)
+	}
+	return w.Flush()
+}
+
+// newPreviewDeleteCmd return preview delete sub command.
+func newPreviewDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [NAME...]",
+		Short: "delete preview environments",
+		Long: `delete deletes the files of the previews from the S3 bucket.
+If --expired is specified, the previews that have not been deployed for 'preview.ttlDays' days are deleted.`,
+		Example: "   spare preview delete feature-x\n   spare preview delete --expired",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &previewDeleter{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	cmd.Flags().Bool("expired", false, "delete the stale previews")
+	cmd.Flags().BoolP("yes", "y", false, "delete without confirmation")
+	return cmd
+}
+
+type previewDeleter struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the preview delete command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// names is the list of previews to delete.
+	names []model.PreviewName
+	// expired is a flag that indicates whether to delete the stale previews.
+	expired bool
+	// yes is a flag that indicates whether to delete without confirmation.
+	yes bool
+}
+
+// Parse parses the arguments and flags.
+func (p *previewDeleter) Parse(cmd *cobra.Command, args []string) (err error) {
+	if p.expired, err = cmd.Flags().GetBool("expired"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--expired)")
+	}
+	if p.yes, err = cmd.Flags().GetBool("yes"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--yes)")
+	}
+	if !p.expired && len(args) == 0 {
+		return errors.New("specify preview names or --expired")
+	}
+	for _, arg := range args {
+		name := model.PreviewName(arg)
+		if err := name.Validate(); err != nil {
+			return err
+		}
+		p.names = append(p.names, name)
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	p.ctx = commonOption.ctx
+	p.spare = commonOption.spare
+	p.config = commonOption.config
+	return nil
+}
+
+// Do delete previews.
+func (p *previewDeleter) Do() error {
+	if p.expired {
+		output, err := p.spare.PreviewExpirer.ExpirePreviews(p.ctx, &usecase.ExpirePreviewsInput{
+			BucketName: p.config.S3BucketName,
+			TTL:        p.config.Preview.TTL(),
+			Now:        time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, preview := range output.Expired {
+			log.Info("[PREVIEW ] expired", "name", preview.Name, "updated at", preview.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+
+	if len(p.names) == 0 {
+		return nil
+	}
+	if !p.yes {
+		if err := p.confirm(); err != nil {
+			return err
+		}
+	}
+	output, err := p.spare.PreviewDeleter.DeletePreviews(p.ctx, &usecase.DeletePreviewsInput{
+		BucketName: p.config.S3BucketName,
+		Names:      p.names,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[PREVIEW ] deleted", "names", p.names, "objects", len(output.Objects),
+		"reclaimed", humanizeBytes(output.Objects.TotalSize()))
+	return nil
+}
+
+// confirm asks if you want to delete the previews.
+func (p *previewDeleter) confirm() error {
+	var result bool
+	if err := survey.AskOne(
+		&survey.Confirm{
+			Message: fmt.Sprintf("want to delete the previews %v from %s?", p.names, p.config.S3BucketName),
+		},
+		&result,
+	); err != nil {
+		return err
+	}
+
+	if !result {
+		return errors.New("canceled")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/spf13/cobra"
+)
+
+// newPromoteCmd return promote sub command.
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "make the canary release live",
+		Long: `promote switches CloudFront to the canary release deployed by 'spare deploy --canary'.
+The config of the staging distribution (the canary release and the settings that the canary viewers
+have tested) is copied to the primary distribution. After that, all viewers get the canary release.`,
+		Example: "   spare promote",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &promoter{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type promoter struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the promote command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+}
+
+// Parse parses the arguments and flags.
+func (p *promoter) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if err := requireAWS(cmd, commonOption.storage); err != nil {
+		return err
+	}
+	p.ctx = commonOption.ctx
+	p.spare = commonOption.spare
+	p.config = commonOption.config
+	return nil
+}
+
+// Do make the canary release live.
+func (p *promoter) Do() error {
+	log.Info("[PROMOTE ]", "bucket name", p.config.S3BucketName)
+	output, err := p.spare.CanaryPromoter.PromoteCanary(p.ctx, &usecase.PromoteCanaryInput{
+		BucketName: p.config.S3BucketName,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[PROMOTE ] done", "release", output.Release.ID, "git sha", output.Release.GitSHA, "domain", output.Domain)
+	return nil
+}
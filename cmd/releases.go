@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/spf13/cobra"
+)
+
+// newReleasesCmd return releases sub command.
+func newReleasesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "releases",
+		Short:   "list releases deployed to AWS",
+		Long:    "releases lists the releases recorded in the S3 bucket. The live release is marked with '*', and the canary release is marked with '~'.",
+		Example: "   spare releases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &releaseLister{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type releaseLister struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the releases command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+}
+
+// Parse parses the arguments and flags.
+func (r *releaseLister) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	r.ctx = commonOption.ctx
+	r.spare = commonOption.spare
+	r.config = commonOption.config
+	return nil
+}
+
+// Do list releases.
+func (r *releaseLister) Do() error {
+	output, err := r.spare.ReleaseLister.ListReleases(r.ctx, &usecase.ListReleasesInput{
+		BucketName: r.config.S3BucketName,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "LIVE\tRELEASE ID\tCREATED AT\tGIT SHA\tUSER")
+	for _, release := range output.History.Releases {
+		live := ""
+		switch {
+		case release.ID == output.History.Live:
+			live = "*"
+		case release.ID == output.History.Staged:
+			live = "~"
+		case release.State == model.ReleaseStateAborted:
+			live = "x"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			live, release.ID, release.CreatedAt.Format(time.RFC3339), release.GitSHA, release.User)
+	}
+	return w.Flush()
+}
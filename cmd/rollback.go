@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/spf13/cobra"
+)
+
+// newRollbackCmd return rollback sub command.
+func newRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [RELEASE_ID]",
+		Short: "switch CloudFront back to the past release",
+		Long: `rollback switches CloudFront to the release that has already been deployed.
+If RELEASE_ID is omitted, the release deployed just before the live release is used.
+You can check RELEASE_ID with 'spare releases'.`,
+		Example: "   spare rollback\n   spare rollback 20231019T120000Z-k3x9q2",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &rollbacker{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type rollbacker struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the rollback command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+	// releaseID is the ID of the release to switch to. If this is empty, use the previous release.
+	releaseID model.ReleaseID
+}
+
+// Parse parses the arguments and flags.
+func (r *rollbacker) Parse(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 1 {
+		r.releaseID = model.ReleaseID(args[0])
+		if err := r.releaseID.Validate(); err != nil {
+			return err
+		}
+	}
+
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	r.ctx = commonOption.ctx
+	r.spare = commonOption.spare
+	r.config = commonOption.config
+	return nil
+}
+
+// Do switch CloudFront to the past release.
+func (r *rollbacker) Do() error {
+	log.Info("[ROLLBACK]", "bucket name", r.config.S3BucketName)
+	output, err := r.spare.ReleaseRollbacker.RollbackRelease(r.ctx, &usecase.RollbackReleaseInput{
+		BucketName: r.config.S3BucketName,
+		ID:         r.releaseID,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("[ROLLBACK] done", "release", output.Release.ID, "git sha", output.Release.GitSHA, "created at", output.Release.CreatedAt)
+	return nil
+}
@@ -27,6 +27,21 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newInitCmd())
 	cmd.AddCommand(newBuildCmd())
 	cmd.AddCommand(newDeployCmd())
+	cmd.AddCommand(newReleasesCmd())
+	cmd.AddCommand(newRollbackCmd())
+	cmd.AddCommand(newPromoteCmd())
+	cmd.AddCommand(newAbortCmd())
+	cmd.AddCommand(newGCCmd())
+	cmd.AddCommand(newPreviewCmd())
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newAuthCmd())
+	cmd.AddCommand(newMaintenanceCmd())
+	cmd.AddCommand(newKeysCmd())
+	cmd.AddCommand(newSignURLCmd())
+	cmd.AddCommand(newLogsCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newIAMPolicyCmd())
 	return cmd
 }
 
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nao1215/spare/signer"
+	"github.com/nao1215/spare/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newSignURLCmd return sign-url sub command.
+func newSignURLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-url URL",
+		Short: "print the signed URL or the signed cookies",
+		Long: `sign-url signs the URL with the private key that 'spare keys create' or 'spare keys rotate' wrote.
+The URL must match the cache behavior with 'signed: true' in .spare.yml. AWS is not accessed.
+With --cookies, the Set-Cookie headers for the directory of the URL (e.g. https://example.com/private/*) are printed instead.`,
+		Example: `   spare sign-url --private-key K2JCJMDEHXQW5F.pem https://example.com/private/report.pdf
+   spare sign-url --private-key K2JCJMDEHXQW5F.pem --expires 15m --ip 203.0.113.10 https://example.com/private/report.pdf
+   spare sign-url --private-key K2JCJMDEHXQW5F.pem --cookies https://example.com/private/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &urlSigner{})
+		},
+	}
+	cmd.Flags().StringP("private-key", "k", "", "private key file. the file name is <KEY_PAIR_ID>.pem")
+	cmd.Flags().String("key-pair-id", "", "ID of the public key. if this is empty, the file name of --private-key is used")
+	cmd.Flags().DurationP("expires", "e", time.Hour, "duration until the signed URL expires")
+	cmd.Flags().Duration("not-before", 0, "duration until the signed URL becomes valid")
+	cmd.Flags().String("ip", "", "IP address or CIDR that the viewers must come from")
+	cmd.Flags().Bool("cookies", false, "print the signed cookies instead of the signed URL")
+	return cmd
+}
+
+type urlSigner struct {
+	// signer signs the URL.
+	signer *signer.Signer
+	// rawURL is the URL to be signed.
+	rawURL string
+	// condition is the restrictions of the signed URL.
+	condition signer.Condition
+	// cookies is whether the signed cookies are printed.
+	cookies bool
+}
+
+// Parse parses the arguments and flags.
+func (u *urlSigner) Parse(cmd *cobra.Command, args []string) error {
+	privateKey, err := cmd.Flags().GetString("private-key")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--private-key)")
+	}
+	if privateKey == "" {
+		return errors.New("--private-key is required")
+	}
+	keyPairID, err := cmd.Flags().GetString("key-pair-id")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--key-pair-id)")
+	}
+	expires, err := cmd.Flags().GetDuration("expires")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--expires)")
+	}
+	notBefore, err := cmd.Flags().GetDuration("not-before")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--not-before)")
+	}
+	ip, err := cmd.Flags().GetString("ip")
+	if err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--ip)")
+	}
+	if u.cookies, err = cmd.Flags().GetBool("cookies"); err != nil {
+		return errfmt.Wrap(err, "can not parse command line argument (--cookies)")
+	}
+
+	parsed, err := url.Parse(args[0])
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("%s is not an absolute URL", args[0])
+	}
+	u.rawURL = args[0]
+
+	now := time.Now()
+	u.condition = signer.Condition{
+		Expires:   now.Add(expires),
+		IPAddress: ip,
+	}
+	if notBefore > 0 {
+		u.condition.NotBefore = now.Add(notBefore)
+	}
+	if err := u.condition.Validate(); err != nil {
+		return err
+	}
+
+	u.signer, err = signer.NewFromFile(privateKey, keyPairID)
+	return err
+}
+
+// Do print the signed URL or the signed cookies.
+func (u *urlSigner) Do() error {
+	if !u.cookies {
+		signed, err := u.signer.SignURL(u.rawURL, u.condition)
+		if err != nil {
+			return err
+		}
+		fmt.Println(signed)
+		return nil
+	}
+
+	cookies, err := u.signer.SignCookies(cookieResource(u.rawURL), u.condition)
+	if err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		fmt.Printf("Set-Cookie: %s\n", c.String())
+	}
+	return nil
+}
+
+// cookieResource returns the resource of the signed cookies. The signed cookies are valid for all files
+// in the directory of the URL. e.g. https://example.com/private/report.pdf -> https://example.com/private/*
+func cookieResource(rawURL string) string {
+	if strings.HasSuffix(rawURL, "*") {
+		return rawURL
+	}
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	return rawURL[:strings.LastIndex(rawURL, "/")+1] + "*"
+}
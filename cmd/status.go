@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nao1215/spare/app/di"
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/app/usecase"
+	"github.com/nao1215/spare/config"
+	"github.com/spf13/cobra"
+)
+
+// newStatusCmd return status sub command.
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show the status of AWS infrastructure for SPA",
+		Long: `status shows the CloudFront distribution, the live release and the canary release.
+It also shows the response headers that CloudFront actually adds to the responses, and whether the maintenance mode is on.`,
+		Example: "   spare status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &statusGetter{})
+		},
+	}
+	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	addCredentialFlags(cmd)
+	cmd.Flags().StringP("file", "f", config.ConfigFilePath, "config file path")
+	return cmd
+}
+
+type statusGetter struct {
+	// ctx is a context.Context.
+	ctx context.Context
+	// spare is a struct that executes the status command.
+	spare *di.Spare
+	// config is a struct that contains the settings for the spare CLI command.
+	config *config.Config
+}
+
+// Parse parses the arguments and flags.
+func (s *statusGetter) Parse(cmd *cobra.Command, _ []string) (err error) {
+	commonOption, err := parseCommon(cmd, nil)
+	if err != nil {
+		return err
+	}
+	s.ctx = commonOption.ctx
+	s.spare = commonOption.spare
+	s.config = commonOption.config
+	return nil
+}
+
+// Do show the status of AWS infrastructure.
+func (s *statusGetter) Do() error {
+	output, err := s.spare.StatusGetter.GetStatus(s.ctx, &usecase.GetStatusInput{
+		BucketName: s.config.S3BucketName,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintf(w, "BUCKET\t%s\n", s.config.S3BucketName)
+	fmt.Fprintf(w, "DISTRIBUTION\t%s\n", output.DistributionID)
+	fmt.Fprintf(w, "DOMAIN\t%s\n", output.Domain)
+	fmt.Fprintf(w, "LIVE RELEASE\t%s\n", valueOrNone(output.Live.String()))
+	fmt.Fprintf(w, "CANARY RELEASE\t%s\n", valueOrNone(output.Staged.String()))
+	fmt.Fprintf(w, "RESPONSE HEADERS POLICY\t%s\n", valueOrNone(output.ResponseHeadersPolicy))
+	fmt.Fprintf(w, "MAINTENANCE\t%s\n", maintenanceSummary(output.Maintenance))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(output.ResponseHeaders) == 0 {
+		return nil
+	}
+	fmt.Println("")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "RESPONSE HEADER\tVALUE")
+	for _, h := range output.ResponseHeaders {
+		fmt.Fprintf(w, "%s\t%s\n", h.Name, h.Value)
+	}
+	return w.Flush()
+}
+
+// maintenanceSummary returns the short description of the maintenance mode.
+func maintenanceSummary(m *model.Maintenance) string {
+	if m == nil {
+		return "off"
+	}
+	return fmt.Sprintf("on (since %s by %s, retry after %ds)",
+		m.StartedAt.Format(time.RFC3339), valueOrNone(m.User), m.RetryAfter)
+}
+
+// valueOrNone returns "-" if s is empty. Otherwise, it returns s.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -20,3 +20,11 @@ func newVersionCmd() *cobra.Command {
 func version(_ *cobra.Command, _ []string) {
 	fmt.Printf("%s version %s, revision %s (under MIT LICENSE)\n", ver.Name, ver.TagVersion, ver.Revision)
 }
+
+// spareVersion returns the spare version in the spare:version tag. The binary built without ldflags is "devel".
+func spareVersion() string {
+	if ver.TagVersion == "" {
+		return "devel"
+	}
+	return ver.TagVersion
+}
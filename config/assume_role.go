@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// AssumeRole is the IAM role that spare assumes with the credentials of the profile or the web identity token.
+// The role can be in another account, so the config file of each environment (spare deploy -f .spare.production.yml)
+// can deploy to the account of the environment. It's omitted by default, and the flags (e.g. --role-arn) override it.
+type AssumeRole struct {
+	// RoleARN is the ARN of the role. e.g. arn:aws:iam::123456789012:role/spare-deploy
+	RoleARN string `yaml:"roleArn"`
+	// ExternalID is the external ID that the trust policy of the role requires.
+	ExternalID string `yaml:"externalId,omitempty"`
+	// SessionName is the name of the role session in CloudTrail.
+	SessionName string `yaml:"sessionName,omitempty"`
+	// MFASerial is the ARN of the MFA device. If it's set, spare prompts the token code.
+	MFASerial string `yaml:"mfaSerial,omitempty"`
+	// DurationSeconds is the duration of the role session (900-43200). If it's 0, the session lasts 15 minutes.
+	DurationSeconds int `yaml:"durationSeconds,omitempty"`
+	// WebIdentityTokenFile is the path of the OIDC token file of the CI.
+	// If it's set, the role is assumed with the token instead of the credentials of the profile.
+	WebIdentityTokenFile string `yaml:"webIdentityTokenFile,omitempty"`
+}
+
+// Settings returns the role to assume. If AssumeRole is nil, it returns nil.
+func (a *AssumeRole) Settings() *model.AssumeRole {
+	if a == nil {
+		return nil
+	}
+	return &model.AssumeRole{
+		RoleARN:              a.RoleARN,
+		ExternalID:           a.ExternalID,
+		SessionName:          a.SessionName,
+		MFASerial:            a.MFASerial,
+		Duration:             time.Duration(a.DurationSeconds) * time.Second,
+		WebIdentityTokenFile: a.WebIdentityTokenFile,
+	}
+}
+
+// validateAssumeRole validates the role to assume, and that the role is in the partition of the region.
+func (c *Config) validateAssumeRole() error {
+	role := c.AssumeRole.Settings()
+	if role == nil {
+		return nil
+	}
+	if err := role.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidAssumeRole, err.Error())
+	}
+	if role.Partition() != c.Region.Partition() {
+		return errfmt.Wrap(ErrInvalidAssumeRole,
+			fmt.Sprintf("the role is in the %s partition, but the region %s is in the %s partition", role.Partition(), c.Region, c.Region.Partition()))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestConfigValidateAssumeRole(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		region     model.Region
+		assumeRole *AssumeRole
+		wantErr    error
+	}{
+		{
+			name:       "success. assume role is omitted",
+			region:     model.RegionAPNortheast1,
+			assumeRole: nil,
+			wantErr:    nil,
+		},
+		{
+			name:   "success. role in another account",
+			region: model.RegionAPNortheast1,
+			assumeRole: &AssumeRole{
+				RoleARN:         "arn:aws:iam::123456789012:role/spare-deploy",
+				ExternalID:      "spare-production",
+				DurationSeconds: 3600,
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "failure. role ARN is empty",
+			region:     model.RegionAPNortheast1,
+			assumeRole: &AssumeRole{},
+			wantErr:    ErrInvalidAssumeRole,
+		},
+		{
+			name:       "failure. duration is too short",
+			region:     model.RegionAPNortheast1,
+			assumeRole: &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy", DurationSeconds: 60},
+			wantErr:    ErrInvalidAssumeRole,
+		},
+		{
+			name:       "failure. role is in another partition",
+			region:     model.RegionCNNorth1,
+			assumeRole: &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy"},
+			wantErr:    ErrInvalidAssumeRole,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &Config{Region: tt.region, AssumeRole: tt.assumeRole}
+			if err := c.validateAssumeRole(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.validateAssumeRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssumeRoleSettings(t *testing.T) {
+	t.Parallel()
+
+	var omitted *AssumeRole
+	if got := omitted.Settings(); got != nil {
+		t.Errorf("AssumeRole.Settings() = %+v, want nil", got)
+	}
+
+	a := &AssumeRole{
+		RoleARN:              "arn:aws:iam::123456789012:role/spare-deploy",
+		SessionName:          "spare",
+		DurationSeconds:      1800,
+		WebIdentityTokenFile: "/tmp/token",
+	}
+	want := &model.AssumeRole{
+		RoleARN:              "arn:aws:iam::123456789012:role/spare-deploy",
+		SessionName:          "spare",
+		Duration:             30 * time.Minute,
+		WebIdentityTokenFile: "/tmp/token",
+	}
+	if diff := cmp.Diff(want, a.Settings()); diff != "" {
+		t.Errorf("AssumeRole.Settings() mismatch (-want +got):\n%s", diff)
+	}
+}
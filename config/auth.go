@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Auth is a type that represents the access control of the site. It's applied by 'spare build'.
+type Auth struct {
+	// Basic is the basic auth that CloudFront Function checks.
+	Basic BasicAuth `yaml:"basic"`
+}
+
+// NewAuth returns a new Auth with default values.
+func NewAuth() Auth {
+	return Auth{
+		Basic: NewBasicAuth(),
+	}
+}
+
+// Validate validates Auth. If Auth is invalid, it returns an error.
+func (a Auth) Validate() error {
+	return a.Basic.Validate()
+}
+
+// BasicAuth is a type that represents the basic auth. The site and the previews are protected by it.
+type BasicAuth struct {
+	// Enabled is whether the basic auth is enabled.
+	Enabled bool `yaml:"enabled"`
+	// Realm is the realm that the browser shows in the login dialog.
+	Realm string `yaml:"realm"`
+	// Users is the list of users who can access the site. 'spare auth rotate' updates it.
+	// If $SPARE_BASIC_AUTH_CREDENTIALS is set, it's used instead of Users.
+	Users []BasicAuthUser `yaml:"users"`
+}
+
+// BasicAuthUser is a type that represents a user of the basic auth.
+type BasicAuthUser struct {
+	// Username is the name of the user.
+	Username string `yaml:"username"`
+	// PasswordHash is the hex encoded SHA-256 hash of "USERNAME:PASSWORD". The plain password is never stored.
+	PasswordHash string `yaml:"passwordHash"`
+}
+
+// NewBasicAuth returns a new BasicAuth with default values. The basic auth is disabled by default.
+func NewBasicAuth() BasicAuth {
+	return BasicAuth{
+		Enabled: false,
+		Realm:   "spare",
+		Users:   []BasicAuthUser{},
+	}
+}
+
+// Validate validates BasicAuth. If BasicAuth is disabled, it's not validated.
+func (b BasicAuth) Validate() error {
+	auth, err := b.Rules()
+	if err != nil {
+		return err
+	}
+	if auth == nil {
+		return nil
+	}
+	if err := auth.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidAuth, err.Error())
+	}
+	return nil
+}
+
+// Rules returns the basic auth that CloudFront Function checks.
+// If $SPARE_BASIC_AUTH_CREDENTIALS is set, the users are read from it. If BasicAuth is disabled, it returns nil.
+func (b BasicAuth) Rules() (*model.BasicAuth, error) {
+	if !b.Enabled {
+		return nil, nil
+	}
+	if credentials := os.Getenv(model.BasicAuthCredentialsEnv); credentials != "" {
+		users, err := model.ParseBasicAuthCredentials(credentials)
+		if err != nil {
+			return nil, errfmt.Wrap(ErrInvalidAuth, err.Error())
+		}
+		return &model.BasicAuth{Realm: b.Realm, Users: users}, nil
+	}
+
+	users := make([]model.BasicAuthUser, 0, len(b.Users))
+	for _, u := range b.Users {
+		users = append(users, model.BasicAuthUser{
+			Username:     u.Username,
+			PasswordHash: u.PasswordHash,
+		})
+	}
+	return &model.BasicAuth{Realm: b.Realm, Users: users}, nil
+}
+
+// SetUser adds the user to Users. If the user already exists, its password hash is replaced.
+func (b *BasicAuth) SetUser(user model.BasicAuthUser) {
+	for i := range b.Users {
+		if b.Users[i].Username == user.Username {
+			b.Users[i].PasswordHash = user.PasswordHash
+			return
+		}
+	}
+	b.Users = append(b.Users, BasicAuthUser{
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+	})
+}
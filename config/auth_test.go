@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestBasicAuthValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		b       BasicAuth
+		wantErr bool
+	}{
+		{
+			name:    "success. disabled basic auth is not validated",
+			b:       NewBasicAuth(),
+			wantErr: false,
+		},
+		{
+			name: "success",
+			b: BasicAuth{
+				Enabled: true,
+				Realm:   "spare",
+				Users: []BasicAuthUser{
+					{Username: "alice", PasswordHash: model.NewBasicAuthUser("alice", "secret").PasswordHash},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failure. no users",
+			b:       BasicAuth{Enabled: true, Realm: "spare", Users: []BasicAuthUser{}},
+			wantErr: true,
+		},
+		{
+			name: "failure. password is not hashed",
+			b: BasicAuth{
+				Enabled: true,
+				Realm:   "spare",
+				Users:   []BasicAuthUser{{Username: "alice", PasswordHash: "secret"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.b.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BasicAuth.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBasicAuthRules(t *testing.T) { //nolint
+	t.Run("credentials in environment variable are used instead of users", func(t *testing.T) {
+		t.Setenv(model.BasicAuthCredentialsEnv, "bob:password")
+		b := BasicAuth{
+			Enabled: true,
+			Realm:   "spare",
+			Users:   []BasicAuthUser{{Username: "alice", PasswordHash: model.NewBasicAuthUser("alice", "secret").PasswordHash}},
+		}
+		got, err := b.Rules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.BasicAuth{
+			Realm: "spare",
+			Users: []model.BasicAuthUser{model.NewBasicAuthUser("bob", "password")},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestBasicAuthSetUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("password hash of existing user is replaced", func(t *testing.T) {
+		t.Parallel()
+		b := NewBasicAuth()
+		b.SetUser(model.NewBasicAuthUser("alice", "secret"))
+		b.SetUser(model.NewBasicAuthUser("bob", "secret"))
+		b.SetUser(model.NewBasicAuthUser("alice", "password"))
+
+		want := []BasicAuthUser{
+			{Username: "alice", PasswordHash: model.NewBasicAuthUser("alice", "password").PasswordHash},
+			{Username: "bob", PasswordHash: model.NewBasicAuthUser("bob", "secret").PasswordHash},
+		}
+		if diff := cmp.Diff(want, b.Users); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
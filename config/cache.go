@@ -0,0 +1,102 @@
+package config
+
+import (
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Cache is a type that represents the cache behaviors of CloudFront.
+// It's applied by 'spare build'.
+type Cache struct {
+	// Default is the default cache behavior.
+	Default CacheBehavior `yaml:"default"`
+	// Behaviors is the list of cache behaviors for the path patterns. The first match is used.
+	Behaviors []CacheBehavior `yaml:"behaviors"`
+}
+
+// CacheBehavior is a type that represents how CloudFront caches the responses for the path pattern.
+type CacheBehavior struct {
+	// PathPattern is the path pattern of the cache behavior. e.g. /assets/*
+	// It's empty for the default cache behavior.
+	PathPattern string `yaml:"pathPattern,omitempty"`
+	// CachePolicy is the name of the managed cache policy. e.g. CachingOptimized
+	// If it's empty, spare creates the custom cache policy from TTLs, query strings and headers.
+	CachePolicy string `yaml:"cachePolicy"`
+	// OriginRequestPolicy is the name of the managed origin request policy. e.g. CORS-S3Origin
+	OriginRequestPolicy string `yaml:"originRequestPolicy"`
+	// MinTTL is the minimum time in seconds that objects stay in the CloudFront cache.
+	MinTTL int64 `yaml:"minTTL"`
+	// DefaultTTL is the time in seconds that objects stay in the CloudFront cache if S3 does not send Cache-Control.
+	DefaultTTL int64 `yaml:"defaultTTL"`
+	// MaxTTL is the maximum time in seconds that objects stay in the CloudFront cache.
+	MaxTTL int64 `yaml:"maxTTL"`
+	// Compress is whether CloudFront compresses the responses with gzip or brotli.
+	Compress bool `yaml:"compress"`
+	// QueryStrings is the allowlist of query strings in the cache key. ["*"] means all query strings.
+	QueryStrings []string `yaml:"queryStrings"`
+	// Headers is the allowlist of headers in the cache key.
+	Headers []string `yaml:"headers"`
+	// Signed is whether the viewers need the signed URL or the signed cookies. The key group is created by 'spare keys create'.
+	Signed bool `yaml:"signed"`
+}
+
+// NewCache returns a new Cache with default values.
+func NewCache() Cache {
+	settings := model.NewCacheSettings()
+	return Cache{
+		Default:   newCacheBehavior(settings.Default),
+		Behaviors: []CacheBehavior{},
+	}
+}
+
+// newCacheBehavior converts model.CacheBehavior to CacheBehavior.
+func newCacheBehavior(b model.CacheBehavior) CacheBehavior {
+	return CacheBehavior{
+		PathPattern:         b.PathPattern,
+		CachePolicy:         b.CachePolicy,
+		OriginRequestPolicy: b.OriginRequestPolicy,
+		MinTTL:              b.MinTTL,
+		DefaultTTL:          b.DefaultTTL,
+		MaxTTL:              b.MaxTTL,
+		Compress:            b.Compress,
+		QueryStrings:        b.QueryStrings,
+		Headers:             b.Headers,
+		Signed:              b.Signed,
+	}
+}
+
+// Validate validates Cache. If Cache is invalid, it returns an error.
+func (c Cache) Validate() error {
+	if err := c.Settings().Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidCache, err.Error())
+	}
+	return nil
+}
+
+// Settings returns the cache settings of the CDN.
+func (c Cache) Settings() *model.CacheSettings {
+	behaviors := make([]model.CacheBehavior, 0, len(c.Behaviors))
+	for _, b := range c.Behaviors {
+		behaviors = append(behaviors, b.behavior())
+	}
+	return &model.CacheSettings{
+		Default:   c.Default.behavior(),
+		Behaviors: behaviors,
+	}
+}
+
+// behavior converts CacheBehavior to model.CacheBehavior.
+func (c CacheBehavior) behavior() model.CacheBehavior {
+	return model.CacheBehavior{
+		PathPattern:         c.PathPattern,
+		CachePolicy:         c.CachePolicy,
+		OriginRequestPolicy: c.OriginRequestPolicy,
+		MinTTL:              c.MinTTL,
+		DefaultTTL:          c.DefaultTTL,
+		MaxTTL:              c.MaxTTL,
+		Compress:            c.Compress,
+		QueryStrings:        c.QueryStrings,
+		Headers:             c.Headers,
+		Signed:              c.Signed,
+	}
+}
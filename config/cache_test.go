@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestCacheValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		c       Cache
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			c:       NewCache(),
+			wantErr: false,
+		},
+		{
+			name: "success. path patterns",
+			c: Cache{
+				Default: NewCache().Default,
+				Behaviors: []CacheBehavior{
+					{PathPattern: "/assets/*", MinTTL: 0, DefaultTTL: 31536000, MaxTTL: 31536000, Compress: true},
+					{PathPattern: "/index.html", CachePolicy: "CachingDisabled"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failure. unknown managed cache policy",
+			c: Cache{
+				Default:   NewCache().Default,
+				Behaviors: []CacheBehavior{{PathPattern: "/assets/*", CachePolicy: "Unknown"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Cache.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCacheSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default cache settings are the same as model", func(t *testing.T) {
+		t.Parallel()
+		if diff := cmp.Diff(model.NewCacheSettings(), NewCache().Settings()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
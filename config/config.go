@@ -30,7 +30,39 @@ type Config struct {
 	// AllowOrigins is the list of domains that are allowed to access the SPA.
 	AllowOrigins            model.AllowOrigins `yaml:"allowOrigins"`
 	DebugLocalstackEndpoint model.Endpoint     `yaml:"debugLocalstackEndpoint"`
-	// TODO: WAF, HTTPS, Cache
+	// Retention is the retention policy of releases. It's used by 'spare gc'.
+	Retention Retention `yaml:"retention"`
+	// Preview is the settings of preview environments. It's used by 'spare preview'.
+	Preview Preview `yaml:"preview"`
+	// Cache is the cache behaviors of CloudFront. It's applied by 'spare build'.
+	Cache Cache `yaml:"cache"`
+	// SecurityHeaders is the security headers that CloudFront adds to the responses. It's applied by 'spare build'.
+	SecurityHeaders SecurityHeaders `yaml:"securityHeaders"`
+	// WAF is the AWS WAF web ACL that protects CloudFront. It's applied by 'spare build'.
+	WAF WAF `yaml:"waf"`
+	// Auth is the access control of the site. It's applied by 'spare build' and 'spare auth rotate'.
+	Auth Auth `yaml:"auth"`
+	// PrettyURLs is the rewrite of the extensionless paths and the directory paths. It's applied by 'spare build' and 'spare deploy'.
+	PrettyURLs PrettyURLs `yaml:"prettyUrls"`
+	// Maintenance is the maintenance mode. It's turned on and off by 'spare maintenance'.
+	Maintenance Maintenance `yaml:"maintenance"`
+	// Origins is the custom origins (e.g. API) next to the S3 origin. It's applied by 'spare build'.
+	Origins CustomOrigins `yaml:"origins"`
+	// Logging is the access logging of the CloudFront distribution and the S3 bucket. It's applied by 'spare build'.
+	Logging Logging `yaml:"logging"`
+	// Storage is the versioning, the object ownership and the lifecycle rules of the S3 bucket. It's applied by 'spare build'.
+	Storage Storage `yaml:"storage"`
+	// Encryption is the server-side encryption of the S3 bucket. It's applied by 'spare build' and 'spare deploy'.
+	Encryption Encryption `yaml:"encryption"`
+	// Env is the environment (e.g. production, staging) in the spare:env tag.
+	Env string `yaml:"env"`
+	// Tags is the user-defined tags of the resources that spare creates. It's applied by 'spare build'.
+	Tags model.Tags `yaml:"tags"`
+	// RegionOverride accepts the region that is newer than the region catalog of spare. It's omitted by default.
+	RegionOverride *RegionOverride `yaml:"regionOverride,omitempty"`
+	// AssumeRole is the IAM role that spare assumes to build and deploy. It's omitted by default.
+	AssumeRole *AssumeRole `yaml:"assumeRole,omitempty"`
+	// TODO: HTTPS
 }
 
 // NewConfig returns a new Config.
@@ -43,6 +75,20 @@ func NewConfig() *Config {
 		S3BucketName:            "",
 		AllowOrigins:            model.AllowOrigins{},
 		DebugLocalstackEndpoint: model.DebugLocalstackEndpoint,
+		Retention:               NewRetention(),
+		Preview:                 NewPreview(),
+		Cache:                   NewCache(),
+		SecurityHeaders:         NewSecurityHeaders(),
+		WAF:                     NewWAF(),
+		Auth:                    NewAuth(),
+		PrettyURLs:              NewPrettyURLs(),
+		Maintenance:             NewMaintenance(),
+		Origins:                 NewCustomOrigins(),
+		Logging:                 NewLogging(),
+		Storage:                 NewStorage(),
+		Encryption:              NewEncryption(),
+		Env:                     defaultEnv,
+		Tags:                    model.Tags{},
 	}
 	cfg.S3BucketName = cfg.DefaultS3BucketName()
 	return cfg
@@ -84,10 +130,17 @@ func (c *Config) Validate(debugMode bool) error {
 	validators := []model.Validator{
 		c.SpareTemplateVersion,
 		c.DeployTarget,
-		c.Region,
 		c.CustomDomain,
 		c.S3BucketName,
 		c.AllowOrigins,
+		c.Retention,
+		c.Preview,
+		c.Cache,
+		c.SecurityHeaders,
+		c.WAF,
+		c.Auth,
+		c.PrettyURLs,
+		c.Maintenance,
 	}
 	if debugMode {
 		validators = append(validators, c.DebugLocalstackEndpoint)
@@ -98,5 +151,49 @@ func (c *Config) Validate(debugMode bool) error {
 			return err
 		}
 	}
-	return nil
+	if err := c.validateRegion(); err != nil {
+		return err
+	}
+	if err := c.validateAssumeRole(); err != nil {
+		return err
+	}
+	if err := c.Origins.Validate(c.Cache); err != nil {
+		return err
+	}
+	if err := c.Logging.Validate(c.S3BucketName); err != nil {
+		return err
+	}
+	if err := c.Storage.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateStorageEndpoint(); err != nil {
+		return err
+	}
+	if err := c.Encryption.Validate(c.Region); err != nil {
+		return err
+	}
+	return c.validateTags()
+}
+
+// ViewerRequest returns the features of the CloudFront Function that runs on viewer requests.
+// The redirect rules are read from the _redirects file in the deploy target.
+// The second return value is the warnings about the rules that are not supported and the pages that PrettyURLs can not serve.
+func (c *Config) ViewerRequest() (*model.ViewerRequest, []string, error) {
+	basicAuth, err := c.Auth.Basic.Rules()
+	if err != nil {
+		return nil, nil, err
+	}
+	redirects, warnings, err := c.DeployTarget.Redirects()
+	if err != nil {
+		return nil, nil, err
+	}
+	prettyURLs, prettyURLWarnings, err := c.PrettyURLs.Rules(c.DeployTarget)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &model.ViewerRequest{
+		BasicAuth:  basicAuth,
+		Redirects:  redirects,
+		PrettyURLs: prettyURLs,
+	}, append(warnings, prettyURLWarnings...), nil
 }
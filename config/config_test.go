@@ -77,6 +77,127 @@ func TestConfigRead(t *testing.T) {
 			S3BucketName:            testBucketName,
 			AllowOrigins:            model.AllowOrigins{exampleCom, exampleComWithTestSubDomain},
 			DebugLocalstackEndpoint: model.DebugLocalstackEndpoint,
+			Retention: Retention{
+				KeepLast: 5,
+				KeepDays: 7,
+				Pinned:   []model.ReleaseID{"20231019T120000Z"},
+			},
+			Preview: Preview{
+				TTLDays: 3,
+			},
+			Cache: Cache{
+				Default: CacheBehavior{
+					CachePolicy:         "CachingOptimized",
+					OriginRequestPolicy: "CORS-S3Origin",
+					Compress:            true,
+					QueryStrings:        []string{},
+					Headers:             []string{},
+				},
+				Behaviors: []CacheBehavior{
+					{
+						PathPattern:  "/assets/*",
+						MinTTL:       0,
+						DefaultTTL:   86400,
+						MaxTTL:       31536000,
+						Compress:     true,
+						QueryStrings: []string{"v"},
+						Headers:      []string{"Origin"},
+					},
+					{
+						PathPattern: "/index.html",
+						CachePolicy: "CachingDisabled",
+					},
+					{
+						PathPattern: "/private/*",
+						CachePolicy: "CachingOptimized",
+						Signed:      true,
+					},
+				},
+			},
+			SecurityHeaders: SecurityHeaders{
+				Enabled: true,
+				StrictTransportSecurity: StrictTransportSecurity{
+					MaxAge:            31536000,
+					IncludeSubdomains: true,
+					Preload:           true,
+				},
+				ContentSecurityPolicy: "default-src 'self'",
+				ContentTypeOptions:    true,
+				FrameOptions:          "SAMEORIGIN",
+				ReferrerPolicy:        "no-referrer",
+				PermissionsPolicy:     "",
+				CustomHeaders:         []Header{{Name: "X-Robots-Tag", Value: "noindex"}},
+			},
+			WAF: WAF{
+				Enabled:        false,
+				ManagedRules:   []string{"common"},
+				RateLimit:      500,
+				AllowListFiles: []string{"waf/allow.txt"},
+				DenyListFiles:  []string{},
+				BlockCountries: []string{"KP"},
+			},
+			Auth: Auth{
+				Basic: BasicAuth{
+					Enabled: true,
+					Realm:   "staging",
+					Users: []BasicAuthUser{
+						{
+							Username:     "alice",
+							PasswordHash: "3d11dc479c08e3b368773103d64766c2e420ce39727932fcf2d8f4d9d599be59",
+						},
+					},
+				},
+			},
+			PrettyURLs: PrettyURLs{
+				Enabled:       true,
+				TrailingSlash: model.TrailingSlashAlways,
+			},
+			Maintenance: Maintenance{
+				Page:         "public/maintenance.html",
+				RetryAfter:   600,
+				AllowIPs:     []string{"203.0.113.0/24"},
+				BypassCookie: "bypass",
+			},
+			Origins: CustomOrigins{
+				{
+					Name:              "api",
+					DomainName:        "api.example.com",
+					OriginPath:        "/prod",
+					Protocol:          model.OriginProtocolHTTPSOnly,
+					HTTPPort:          80,
+					HTTPSPort:         443,
+					PathPatterns:      []string{"/api/*"},
+					CachePolicy:       "CachingDisabled",
+					Headers:           []string{"*"},
+					Cookies:           []string{"session"},
+					QueryStrings:      []string{"*"},
+					ConnectionTimeout: 10,
+					ReadTimeout:       60,
+					KeepaliveTimeout:  5,
+				},
+			},
+			Logging: Logging{
+				Enabled:        true,
+				Bucket:         "",
+				ExpirationDays: 30,
+			},
+			Storage: Storage{
+				Versioning:      true,
+				ObjectOwnership: model.ObjectOwnershipBucketOwnerEnforced,
+				Lifecycle: Lifecycle{
+					NoncurrentVersionExpirationDays:    14,
+					AbortIncompleteMultipartUploadDays: 3,
+					Transitions: []LifecycleTransition{
+						{Prefix: "releases/", Days: 30, StorageClass: model.StorageClassStandardIA},
+					},
+				},
+			},
+			Encryption: Encryption{
+				Type:      model.EncryptionTypeSSEKMS,
+				KMSKeyARN: "arn:aws:kms:us-east-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			},
+			Env:  "staging",
+			Tags: model.Tags{"team": "web", "cost-center": "1234"},
 		}
 
 		if diff := cmp.Diff(want, got); diff != "" {
@@ -127,6 +248,19 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "failure. CloudFront is not available in the region",
+			fields: fields{
+				SpareTemplateVersion: "1.0.0",
+				DeployTarget:         "src",
+				Region:               model.RegionUSGovWest1,
+				CustomDomain:         exampleCom,
+				S3BucketName:         testBucketName,
+				AllowOrigins:         model.AllowOrigins{exampleCom, exampleComWithTestSubDomain},
+				Endpoint:             model.DebugLocalstackEndpoint,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Encryption is a type that represents the server-side encryption of the S3 bucket.
+// It's applied by 'spare build', and the objects uploaded by 'spare deploy' are encrypted with the same settings.
+type Encryption struct {
+	// Type is the server-side encryption. SSE-S3 or SSE-KMS. If it's empty, the default encryption is left as it is.
+	Type model.EncryptionType `yaml:"type"`
+	// KMSKeyARN is the ARN of the existing KMS key for SSE-KMS.
+	// If it's empty with SSE-KMS, spare creates the KMS key whose alias is "alias/spare-<s3BucketName>".
+	KMSKeyARN model.KMSKeyARN `yaml:"kmsKeyArn"`
+}
+
+// NewEncryption returns a new Encryption with default values. The objects are encrypted with SSE-S3 by default.
+func NewEncryption() Encryption {
+	return Encryption{
+		Type:      model.EncryptionTypeSSES3,
+		KMSKeyARN: "",
+	}
+}
+
+// Validate validates Encryption. region is the region of the bucket. The KMS key must be in the same region.
+func (e Encryption) Validate(region model.Region) error {
+	if e.Type == "" {
+		return nil
+	}
+	if err := e.Type.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidEncryption, err.Error())
+	}
+	if e.KMSKeyARN.Empty() {
+		return nil
+	}
+	if e.Type != model.EncryptionTypeSSEKMS {
+		return errfmt.Wrap(ErrInvalidEncryption, "kmsKeyArn is used only with SSE-KMS")
+	}
+	if err := e.KMSKeyARN.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidEncryption, err.Error())
+	}
+	if e.KMSKeyARN.Region() != region {
+		return errfmt.Wrap(ErrInvalidEncryption,
+			fmt.Sprintf("kms key must be in the region of the bucket (%s): %s", region, e.KMSKeyARN))
+	}
+	return nil
+}
+
+// Settings returns the server-side encryption of bucket. If Type is empty, it returns nil.
+func (e Encryption) Settings(bucket model.BucketName) *model.Encryption {
+	switch e.Type {
+	case "":
+		return nil
+	case model.EncryptionTypeSSEKMS:
+		if e.KMSKeyARN.Empty() {
+			return &model.Encryption{Type: e.Type, KMSKeyID: model.NewKMSKeyAlias(bucket), CreateKey: true}
+		}
+		return &model.Encryption{Type: e.Type, KMSKeyID: e.KMSKeyARN.String(), CreateKey: false}
+	default:
+		return &model.Encryption{Type: e.Type}
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+const testKMSKeyARN = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+func TestEncryptionValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		e       Encryption
+		wantErr bool
+	}{
+		{
+			name:    "success. default encryption",
+			e:       NewEncryption(),
+			wantErr: false,
+		},
+		{
+			name:    "success. encryption is left as it is",
+			e:       Encryption{},
+			wantErr: false,
+		},
+		{
+			name:    "success. KMS key that spare creates",
+			e:       Encryption{Type: model.EncryptionTypeSSEKMS},
+			wantErr: false,
+		},
+		{
+			name:    "success. existing KMS key",
+			e:       Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyARN: testKMSKeyARN},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown type",
+			e:       Encryption{Type: "DSSE-KMS"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. KMS key with SSE-S3",
+			e:       Encryption{Type: model.EncryptionTypeSSES3, KMSKeyARN: testKMSKeyARN},
+			wantErr: true,
+		},
+		{
+			name:    "failure. alias is not KMS key ARN",
+			e:       Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyARN: "alias/my-key"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. KMS key in another region",
+			e:       Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyARN: "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.e.Validate(model.RegionUSEast1); (err != nil) != tt.wantErr {
+				t.Errorf("Encryption.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptionSettings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		e    Encryption
+		want *model.Encryption
+	}{
+		{
+			name: "encryption is left as it is",
+			e:    Encryption{},
+			want: nil,
+		},
+		{
+			name: "SSE-S3",
+			e:    NewEncryption(),
+			want: &model.Encryption{Type: model.EncryptionTypeSSES3},
+		},
+		{
+			name: "SSE-KMS with the KMS key that spare creates",
+			e:    Encryption{Type: model.EncryptionTypeSSEKMS},
+			want: &model.Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyID: "alias/spare-my-bucket", CreateKey: true},
+		},
+		{
+			name: "SSE-KMS with the existing KMS key",
+			e:    Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyARN: testKMSKeyARN},
+			want: &model.Encryption{Type: model.EncryptionTypeSSEKMS, KMSKeyID: testKMSKeyARN, CreateKey: false},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, tt.e.Settings("my-bucket")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
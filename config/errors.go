@@ -16,4 +16,32 @@ var (
 	ErrInvalidSpareTemplateVersion = errors.New("invalid spare template version")
 	// ErrInvalidDeployTarget is an error that occurs when the deploy target is invalid.
 	ErrInvalidDeployTarget = errors.New("invalid deploy target")
+	// ErrInvalidRetention is an error that occurs when the retention policy is invalid.
+	ErrInvalidRetention = errors.New("invalid retention policy")
+	// ErrInvalidPreview is an error that occurs when the preview settings are invalid.
+	ErrInvalidPreview = errors.New("invalid preview settings")
+	// ErrInvalidCache is an error that occurs when the cache settings are invalid.
+	ErrInvalidCache = errors.New("invalid cache settings")
+	// ErrInvalidSecurityHeaders is an error that occurs when the security headers are invalid.
+	ErrInvalidSecurityHeaders = errors.New("invalid security headers")
+	// ErrInvalidWAF is an error that occurs when the WAF settings are invalid.
+	ErrInvalidWAF = errors.New("invalid WAF settings")
+	// ErrInvalidAuth is an error that occurs when the auth settings are invalid.
+	ErrInvalidAuth = errors.New("invalid auth settings")
+	// ErrInvalidPrettyURLs is an error that occurs when the pretty URLs settings are invalid.
+	ErrInvalidPrettyURLs = errors.New("invalid pretty URLs settings")
+	// ErrInvalidCustomOrigins is an error that occurs when the custom origins are invalid.
+	ErrInvalidCustomOrigins = errors.New("invalid custom origins")
+	// ErrInvalidMaintenance is an error that occurs when the maintenance mode settings are invalid.
+	ErrInvalidMaintenance = errors.New("invalid maintenance mode settings")
+	// ErrInvalidLogging is an error that occurs when the logging settings are invalid.
+	ErrInvalidLogging = errors.New("invalid logging settings")
+	// ErrInvalidStorage is an error that occurs when the bucket settings are invalid.
+	ErrInvalidStorage = errors.New("invalid storage settings")
+	// ErrInvalidEncryption is an error that occurs when the server-side encryption settings are invalid.
+	ErrInvalidEncryption = errors.New("invalid encryption settings")
+	// ErrInvalidTags is an error that occurs when the tags are invalid.
+	ErrInvalidTags = errors.New("invalid tags")
+	// ErrInvalidAssumeRole is an error that occurs when the settings of the role to assume are invalid.
+	ErrInvalidAssumeRole = errors.New("invalid assume role settings")
 )
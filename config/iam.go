@@ -0,0 +1,22 @@
+package config
+
+import "github.com/nao1215/spare/app/domain/model"
+
+// IAMPermissionOptions returns the options of the IAM permissions that the commands need with the features in the config.
+// The permissions of the disabled features (e.g. WAF, access logging) are omitted.
+func (c *Config) IAMPermissionOptions(commands ...model.IAMCommand) model.IAMPermissionOptions {
+	opts := model.IAMPermissionOptions{
+		Commands:  commands,
+		Bucket:    c.S3BucketName,
+		Partition: c.Region.Partition(),
+		WAF:       c.WAF.Enabled,
+		KMS:       c.Encryption.Type == model.EncryptionTypeSSEKMS,
+	}
+	if opts.KMS {
+		opts.KMSKeyARN = c.Encryption.KMSKeyARN
+	}
+	if c.Logging.Enabled {
+		opts.LogBucket = c.Logging.LogBucket(c.S3BucketName)
+	}
+	return opts
+}
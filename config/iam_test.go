@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestConfigIAMPermissionOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		c        *Config
+		commands []model.IAMCommand
+		want     []string
+	}{
+		{
+			name:     "deploy with the default features",
+			c:        NewConfig(),
+			commands: []model.IAMCommand{model.IAMCommandDeploy},
+			want:     []string{"SpareBucket", "SpareObjects", "SpareDistribution", "SpareFunctions", "SpareCloudFront"},
+		},
+		{
+			name: "build with logging, WAF and SSE-KMS",
+			c: func() *Config {
+				c := NewConfig()
+				c.Logging.Enabled = true
+				c.WAF.Enabled = true
+				c.Encryption.Type = model.EncryptionTypeSSEKMS
+				return c
+			}(),
+			commands: []model.IAMCommand{model.IAMCommandBuild},
+			want: []string{
+				"SpareBucket", "SpareObjects", "SpareLogBucket", "SpareDistribution", "SpareFunctions",
+				"SpareCloudFront", "SpareWAF", "SpareKMSCreate", "SpareKMS", "SpareTagging",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			permissions, err := model.NewIAMPermissions(tt.c.IAMPermissionOptions(tt.commands...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, p := range permissions {
+				got = append(got, p.Sid)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Config.IAMPermissionOptions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
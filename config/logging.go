@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// defaultLogExpirationDays is the default number of days after which the logs are deleted.
+	defaultLogExpirationDays = 90
+)
+
+// Logging is a type that represents the access logging of the CloudFront distribution and the S3 bucket.
+// It's applied by 'spare build', and the CloudFront logs are analyzed by 'spare logs'.
+type Logging struct {
+	// Enabled is whether the CloudFront standard logs and the S3 server access logs are delivered to the log bucket.
+	Enabled bool `yaml:"enabled"`
+	// Bucket is the name of the log bucket. If it's empty, "<s3BucketName>-logs" is used.
+	Bucket model.BucketName `yaml:"bucket"`
+	// ExpirationDays is the number of days after which the logs are deleted from the log bucket.
+	ExpirationDays int `yaml:"expirationDays"`
+}
+
+// NewLogging returns a new Logging with default values. Logging is disabled by default.
+func NewLogging() Logging {
+	return Logging{
+		Enabled:        false,
+		Bucket:         "",
+		ExpirationDays: defaultLogExpirationDays,
+	}
+}
+
+// Validate validates Logging. bucket is the bucket whose logs are delivered. If Logging is disabled, it's not validated.
+func (l Logging) Validate(bucket model.BucketName) error {
+	if !l.Enabled {
+		return nil
+	}
+	if l.ExpirationDays < 1 {
+		return errfmt.Wrap(ErrInvalidLogging, fmt.Sprintf("expirationDays must be greater than 0: %d", l.ExpirationDays))
+	}
+	logBucket := l.logBucket(bucket)
+	if err := logBucket.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidLogging, err.Error())
+	}
+	if logBucket == bucket {
+		return errfmt.Wrap(ErrInvalidLogging, "log bucket must be different from s3BucketName")
+	}
+	return nil
+}
+
+// Settings returns the access logging of bucket. If Logging is disabled, it returns nil.
+func (l Logging) Settings(bucket model.BucketName) *model.AccessLogging {
+	if !l.Enabled {
+		return nil
+	}
+	return &model.AccessLogging{
+		Bucket:         l.logBucket(bucket),
+		ExpirationDays: l.ExpirationDays,
+	}
+}
+
+// LogBucket returns the name of the log bucket for bucket, even if Logging is disabled.
+func (l Logging) LogBucket(bucket model.BucketName) model.BucketName {
+	return l.logBucket(bucket)
+}
+
+// logBucket returns Bucket, or the default log bucket name if Bucket is empty.
+func (l Logging) logBucket(bucket model.BucketName) model.BucketName {
+	if l.Bucket.Empty() {
+		return model.NewLogBucketName(bucket)
+	}
+	return l.Bucket
+}
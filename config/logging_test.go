@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestLoggingValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		l       Logging
+		wantErr bool
+	}{
+		{
+			name:    "success. disabled logging is not validated",
+			l:       Logging{Enabled: false, ExpirationDays: 0},
+			wantErr: false,
+		},
+		{
+			name:    "success. default log bucket",
+			l:       Logging{Enabled: true, ExpirationDays: 30},
+			wantErr: false,
+		},
+		{
+			name:    "success. custom log bucket",
+			l:       Logging{Enabled: true, Bucket: "access-logs", ExpirationDays: 30},
+			wantErr: false,
+		},
+		{
+			name:    "failure. expirationDays is 0",
+			l:       Logging{Enabled: true, ExpirationDays: 0},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid log bucket",
+			l:       Logging{Enabled: true, Bucket: "Access_Logs", ExpirationDays: 30},
+			wantErr: true,
+		},
+		{
+			name:    "failure. log bucket is the same as s3BucketName",
+			l:       Logging{Enabled: true, Bucket: "my-bucket", ExpirationDays: 30},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.l.Validate("my-bucket"); (err != nil) != tt.wantErr {
+				t.Errorf("Logging.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoggingSettings(t *testing.T) {
+	t.Parallel()
+
+	if got := NewLogging().Settings("my-bucket"); got != nil {
+		t.Errorf("Logging.Settings() = %v, want nil", got)
+	}
+
+	l := Logging{Enabled: true, ExpirationDays: 30}
+	want := &model.AccessLogging{Bucket: "my-bucket-logs", ExpirationDays: 30}
+	if diff := cmp.Diff(want, l.Settings("my-bucket")); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
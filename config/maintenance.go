@@ -0,0 +1,62 @@
+package config
+
+import (
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Maintenance is a type that represents the maintenance mode. It's turned on and off by 'spare maintenance'.
+type Maintenance struct {
+	// Page is the path of the maintenance page. It's uploaded by 'spare maintenance on' and 'spare deploy'.
+	Page string `yaml:"page"`
+	// RetryAfter is the value of the Retry-After header in seconds.
+	RetryAfter int64 `yaml:"retryAfter"`
+	// AllowIPs is the list of IP addresses or IPv4 CIDRs that can access the site during the maintenance.
+	AllowIPs []string `yaml:"allowIPs"`
+	// BypassCookie is the name of the cookie that bypasses the maintenance mode.
+	// 'spare maintenance on' shows its value.
+	BypassCookie string `yaml:"bypassCookie"`
+}
+
+// NewMaintenance returns a new Maintenance with default values.
+func NewMaintenance() Maintenance {
+	const defaultRetryAfter = 3600
+	return Maintenance{
+		Page:         "maintenance.html",
+		RetryAfter:   defaultRetryAfter,
+		AllowIPs:     []string{},
+		BypassCookie: "spare_maintenance_bypass",
+	}
+}
+
+// Validate validates Maintenance. If Maintenance is invalid, it returns an error.
+// If Maintenance is not configured (zero value), it's not validated until 'spare maintenance on' uses it.
+func (m Maintenance) Validate() error {
+	if m.Page == "" && m.RetryAfter == 0 && len(m.AllowIPs) == 0 && m.BypassCookie == "" {
+		return nil
+	}
+	_, err := m.Rules("", "", time.Time{})
+	return err
+}
+
+// Rules returns the maintenance mode that is turned on by the user at now.
+// The viewers with the bypass cookie whose value is token can access the site.
+func (m Maintenance) Rules(token, user string, now time.Time) (*model.Maintenance, error) {
+	if m.Page == "" {
+		return nil, errfmt.Wrap(ErrInvalidMaintenance, "maintenance page is empty")
+	}
+	maintenance := &model.Maintenance{
+		RetryAfter:   m.RetryAfter,
+		AllowIPs:     m.AllowIPs,
+		BypassCookie: m.BypassCookie,
+		BypassToken:  token,
+		User:         user,
+		StartedAt:    now,
+	}
+	if err := maintenance.Validate(); err != nil {
+		return nil, errfmt.Wrap(ErrInvalidMaintenance, err.Error())
+	}
+	return maintenance, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestMaintenanceValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		m       Maintenance
+		wantErr bool
+	}{
+		{
+			name:    "success. default",
+			m:       NewMaintenance(),
+			wantErr: false,
+		},
+		{
+			name:    "success. not configured",
+			m:       Maintenance{},
+			wantErr: false,
+		},
+		{
+			name:    "failure. page is empty",
+			m:       Maintenance{Page: "", RetryAfter: 3600, AllowIPs: []string{}, BypassCookie: "bypass"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid allow IP",
+			m:       Maintenance{Page: "maintenance.html", RetryAfter: 3600, AllowIPs: []string{"localhost"}, BypassCookie: "bypass"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Maintenance.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rules with bypass token", func(t *testing.T) {
+		t.Parallel()
+		now := time.Date(2023, 10, 19, 12, 0, 0, 0, time.UTC)
+		got, err := NewMaintenance().Rules("abc123", "alice", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.Maintenance{
+			RetryAfter:   3600,
+			AllowIPs:     []string{},
+			BypassCookie: "spare_maintenance_bypass",
+			BypassToken:  "abc123",
+			User:         "alice",
+			StartedAt:    now,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
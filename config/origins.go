@@ -0,0 +1,113 @@
+package config
+
+import (
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// CustomOrigins is the list of origins other than S3 (e.g. ALB, API Gateway). It's applied by 'spare build'.
+// The requests that match the path patterns are routed to them, so the SPA can call the API on the same domain.
+type CustomOrigins []CustomOrigin
+
+// CustomOrigin is a type that represents the origin other than S3.
+// The omitted fields are set to the default values: HTTPS only, no caching, and all headers (except Host),
+// cookies and query strings are forwarded.
+type CustomOrigin struct {
+	// Name is the name of the origin. e.g. api
+	Name string `yaml:"name"`
+	// DomainName is the domain name of the origin. e.g. api.example.com, my-alb-1234.us-east-1.elb.amazonaws.com
+	DomainName string `yaml:"domainName"`
+	// OriginPath is the path that CloudFront prepends to the request path. e.g. /prod for API Gateway stages
+	OriginPath string `yaml:"originPath"`
+	// Protocol is the protocol that CloudFront uses to connect to the origin. https-only, http-only or match-viewer.
+	Protocol model.OriginProtocol `yaml:"protocol"`
+	// HTTPPort is the HTTP port of the origin.
+	HTTPPort int64 `yaml:"httpPort"`
+	// HTTPSPort is the HTTPS port of the origin.
+	HTTPSPort int64 `yaml:"httpsPort"`
+	// PathPatterns is the list of path patterns that are routed to the origin. e.g. /api/*
+	PathPatterns []string `yaml:"pathPatterns"`
+	// CachePolicy is the name of the managed cache policy. e.g. CachingDisabled
+	CachePolicy string `yaml:"cachePolicy"`
+	// Headers is the allowlist of headers forwarded to the origin. ["*"] means all headers except Host.
+	Headers []string `yaml:"headers"`
+	// Cookies is the allowlist of cookies forwarded to the origin. ["*"] means all cookies.
+	Cookies []string `yaml:"cookies"`
+	// QueryStrings is the allowlist of query strings forwarded to the origin. ["*"] means all query strings.
+	QueryStrings []string `yaml:"queryStrings"`
+	// ConnectionTimeout is the time in seconds that CloudFront waits to connect to the origin.
+	ConnectionTimeout int64 `yaml:"connectionTimeout"`
+	// ReadTimeout is the time in seconds that CloudFront waits for the response from the origin.
+	ReadTimeout int64 `yaml:"readTimeout"`
+	// KeepaliveTimeout is the time in seconds that CloudFront keeps the connection to the origin.
+	KeepaliveTimeout int64 `yaml:"keepaliveTimeout"`
+}
+
+// NewCustomOrigins returns a new CustomOrigins with default values.
+func NewCustomOrigins() CustomOrigins {
+	return CustomOrigins{}
+}
+
+// UnmarshalYAML sets the default values to the fields that are omitted in .spare.yml.
+func (c *CustomOrigin) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain CustomOrigin
+	o := plain(newCustomOrigin(model.NewCustomOrigin("", "", []string{})))
+	if err := unmarshal(&o); err != nil {
+		return err
+	}
+	*c = CustomOrigin(o)
+	return nil
+}
+
+// newCustomOrigin converts model.CustomOrigin to CustomOrigin.
+func newCustomOrigin(o model.CustomOrigin) CustomOrigin {
+	return CustomOrigin{
+		Name:              o.Name,
+		DomainName:        o.DomainName,
+		OriginPath:        o.OriginPath,
+		Protocol:          o.Protocol,
+		HTTPPort:          o.HTTPPort,
+		HTTPSPort:         o.HTTPSPort,
+		PathPatterns:      o.PathPatterns,
+		CachePolicy:       o.CachePolicy,
+		Headers:           o.Headers,
+		Cookies:           o.Cookies,
+		QueryStrings:      o.QueryStrings,
+		ConnectionTimeout: o.ConnectionTimeout,
+		ReadTimeout:       o.ReadTimeout,
+		KeepaliveTimeout:  o.KeepaliveTimeout,
+	}
+}
+
+// Validate validates CustomOrigins. If CustomOrigins is invalid, it returns an error.
+// The path patterns must not be used by the cache behaviors.
+func (c CustomOrigins) Validate(cache Cache) error {
+	if err := c.Settings().Validate(cache.Settings()); err != nil {
+		return errfmt.Wrap(ErrInvalidCustomOrigins, err.Error())
+	}
+	return nil
+}
+
+// Settings returns the custom origins of the CDN.
+func (c CustomOrigins) Settings() model.CustomOrigins {
+	origins := make(model.CustomOrigins, 0, len(c))
+	for _, o := range c {
+		origins = append(origins, model.CustomOrigin{
+			Name:              o.Name,
+			DomainName:        o.DomainName,
+			OriginPath:        o.OriginPath,
+			Protocol:          o.Protocol,
+			HTTPPort:          o.HTTPPort,
+			HTTPSPort:         o.HTTPSPort,
+			PathPatterns:      o.PathPatterns,
+			CachePolicy:       o.CachePolicy,
+			Headers:           o.Headers,
+			Cookies:           o.Cookies,
+			QueryStrings:      o.QueryStrings,
+			ConnectionTimeout: o.ConnectionTimeout,
+			ReadTimeout:       o.ReadTimeout,
+			KeepaliveTimeout:  o.KeepaliveTimeout,
+		})
+	}
+	return origins
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCustomOriginUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	var got CustomOrigins
+	data := "- name: api\n  domainName: api.example.com\n  pathPatterns: [\"/api/*\"]\n  headers: []\n"
+	if err := yaml.NewDecoder(strings.NewReader(data)).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := model.NewCustomOrigin("api", "api.example.com", []string{"/api/*"})
+	want.Headers = []string{}
+	if diff := cmp.Diff(model.CustomOrigins{want}, got.Settings()); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCustomOriginsValidate(t *testing.T) {
+	t.Parallel()
+
+	api := newCustomOrigin(model.NewCustomOrigin("api", "api.example.com", []string{"/api/*"}))
+	tests := []struct {
+		name    string
+		c       CustomOrigins
+		cache   Cache
+		wantErr bool
+	}{
+		{
+			name:    "success. no custom origins",
+			c:       NewCustomOrigins(),
+			cache:   NewCache(),
+			wantErr: false,
+		},
+		{
+			name:    "success. custom origin",
+			c:       CustomOrigins{api},
+			cache:   NewCache(),
+			wantErr: false,
+		},
+		{
+			name:    "failure. domain name is empty",
+			c:       CustomOrigins{{Name: "api", PathPatterns: []string{"/api/*"}}},
+			cache:   NewCache(),
+			wantErr: true,
+		},
+		{
+			name: "failure. path pattern is used by the cache behavior",
+			c:    CustomOrigins{api},
+			cache: Cache{
+				Default:   NewCache().Default,
+				Behaviors: []CacheBehavior{{PathPattern: "/api/*", CachePolicy: "CachingDisabled"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.c.Validate(tt.cache)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CustomOrigins.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCustomOrigins) {
+				t.Errorf("CustomOrigins.Validate() error = %v, want %v", err, ErrInvalidCustomOrigins)
+			}
+		})
+	}
+}
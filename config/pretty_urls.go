@@ -0,0 +1,52 @@
+package config
+
+import (
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// PrettyURLs is a type that represents the rewrite of the extensionless paths and the directory paths.
+// e.g. /about is served from about.html, and /docs/ is served from docs/index.html. It's applied by 'spare build' and 'spare deploy'.
+type PrettyURLs struct {
+	// Enabled is whether the paths are rewritten by CloudFront Function.
+	Enabled bool `yaml:"enabled"`
+	// TrailingSlash is how the trailing slash is normalized. ignore, always (/about -> /about/) or never (/about/ -> /about).
+	TrailingSlash model.TrailingSlash `yaml:"trailingSlash"`
+}
+
+// NewPrettyURLs returns a new PrettyURLs with default values. PrettyURLs is disabled by default.
+func NewPrettyURLs() PrettyURLs {
+	return PrettyURLs{
+		Enabled:       false,
+		TrailingSlash: model.TrailingSlashIgnore,
+	}
+}
+
+// Validate validates PrettyURLs. If PrettyURLs is disabled, it's not validated.
+func (p PrettyURLs) Validate() error {
+	if !p.Enabled {
+		return nil
+	}
+	if err := p.TrailingSlash.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidPrettyURLs, err.Error())
+	}
+	return nil
+}
+
+// Rules returns the rewrite of the paths. If PrettyURLs is disabled, it returns nil.
+// The second return value is the warnings about the HTML files in the deploy target that the convention can not serve.
+func (p PrettyURLs) Rules(target DeployTarget) (*model.PrettyURLs, []string, error) {
+	if !p.Enabled {
+		return nil, nil, nil
+	}
+	files, err := target.HTMLFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+	rules := model.NewPrettyURLs(p.TrailingSlash, files)
+	warnings := []string{}
+	for _, w := range rules.Warnings(files) {
+		warnings = append(warnings, "prettyUrls: "+w)
+	}
+	return rules, warnings, nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestPrettyURLsValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		p       PrettyURLs
+		wantErr bool
+	}{
+		{
+			name:    "success. disabled pretty URLs is not validated",
+			p:       PrettyURLs{Enabled: false, TrailingSlash: "unknown"},
+			wantErr: false,
+		},
+		{
+			name:    "success",
+			p:       PrettyURLs{Enabled: true, TrailingSlash: model.TrailingSlashNever},
+			wantErr: false,
+		},
+		{
+			name:    "failure. unknown trailing slash",
+			p:       PrettyURLs{Enabled: true, TrailingSlash: "unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PrettyURLs.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrettyURLsRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		got, warnings, err := NewPrettyURLs().Rules(DeployTarget(filepath.Join("testdata", "target")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil || len(warnings) != 0 {
+			t.Errorf("PrettyURLs.Rules() = %v, %v, want nil", got, warnings)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		p := PrettyURLs{Enabled: true, TrailingSlash: model.TrailingSlashAlways}
+		got, warnings, err := p.Rules(DeployTarget(filepath.Join("testdata", "target")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.PrettyURLs{TrailingSlash: model.TrailingSlashAlways}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "prettyUrls: 3 pages (e.g. about.html) can not be served") {
+			t.Errorf("PrettyURLs.Rules() warnings = %v, want the warning about about.html", warnings)
+		}
+	})
+
+	t.Run("enabled. ignore embeds the directory pages", func(t *testing.T) {
+		t.Parallel()
+		p := PrettyURLs{Enabled: true, TrailingSlash: model.TrailingSlashIgnore}
+		got, warnings, err := p.Rules(DeployTarget(filepath.Join("testdata", "target")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.PrettyURLs{TrailingSlash: model.TrailingSlashIgnore, DirectoryPages: []string{"/docs/intro"}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("PrettyURLs.Rules() warnings = %v, want no warning", warnings)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Preview is a type that represents the settings of preview environments.
+// It's used by 'spare preview'.
+type Preview struct {
+	// TTLDays is the number of days to keep a preview after its last deploy.
+	// Stale previews are deleted by 'spare preview deploy' and 'spare preview delete --expired'.
+	// If it's zero, previews never expire.
+	TTLDays int `yaml:"ttlDays"`
+}
+
+// NewPreview returns a new Preview with default values.
+func NewPreview() Preview {
+	const defaultTTLDays = 14
+	return Preview{
+		TTLDays: defaultTTLDays,
+	}
+}
+
+// Validate validates Preview. If Preview is invalid, it returns an error.
+func (p Preview) Validate() error {
+	if p.TTLDays < 0 {
+		return errfmt.Wrap(ErrInvalidPreview, fmt.Sprintf("ttlDays must not be negative: %d", p.TTLDays))
+	}
+	return nil
+}
+
+// TTL returns the time to live of previews.
+func (p Preview) TTL() time.Duration {
+	const day = 24 * time.Hour
+	return time.Duration(p.TTLDays) * day
+}
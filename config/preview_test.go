@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreviewValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		p       Preview
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			p:       NewPreview(),
+			wantErr: false,
+		},
+		{
+			name:    "success. previews never expire",
+			p:       Preview{TTLDays: 0},
+			wantErr: false,
+		},
+		{
+			name:    "failure. ttlDays is negative",
+			p:       Preview{TTLDays: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Preview.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreviewTTL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		if got := (Preview{TTLDays: 2}).TTL(); got != 48*time.Hour {
+			t.Errorf("Preview.TTL() = %v, want %v", got, 48*time.Hour)
+		}
+	})
+}
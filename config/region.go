@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// RegionOverride is the metadata of the region that is newer than the region catalog of spare.
+// If it's set, the region in .spare.yml is accepted even if it's not in the catalog.
+// The features of the region are the default of the partition that the region belongs to.
+type RegionOverride struct {
+	// DisplayName is the name of the region in the AWS console (e.g. Asia Pacific (Tokyo)).
+	DisplayName string `yaml:"displayName"`
+	// OptIn is whether the region must be enabled in the account before it's used.
+	OptIn bool `yaml:"optIn"`
+}
+
+// RegionInfo returns the metadata of the region. If the region is not in the region catalog,
+// it's made from RegionOverride.
+func (c *Config) RegionInfo() (model.RegionInfo, error) {
+	if err := c.Region.Validate(); err != nil {
+		if !errors.Is(err, model.ErrInvalidRegion) || c.RegionOverride == nil {
+			return model.RegionInfo{}, errfmt.Wrap(ErrInvalidRegion,
+				fmt.Sprintf("%s. if the region is newer than spare, add regionOverride to %s", err.Error(), ConfigFilePath))
+		}
+		info, err := model.NewRegionOverride(c.Region, c.RegionOverride.DisplayName, c.RegionOverride.OptIn)
+		if err != nil {
+			return model.RegionInfo{}, errfmt.Wrap(ErrInvalidRegion, err.Error())
+		}
+		return info, nil
+	}
+	info, _ := c.Region.Info()
+	return info, nil
+}
+
+// validateRegion validates that the region is in the region catalog (or RegionOverride is set),
+// and that the region supports the AWS services in .spare.yml.
+func (c *Config) validateRegion() error {
+	info, err := c.RegionInfo()
+	if err != nil {
+		return err
+	}
+	features := []model.RegionFeature{model.RegionFeatureCloudFront}
+	if c.Encryption.Type == model.EncryptionTypeSSEKMS {
+		features = append(features, model.RegionFeatureKMS)
+	}
+	for _, f := range features {
+		if err := info.ValidateFeature(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestConfigValidateRegion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		region   model.Region
+		override *RegionOverride
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			region:   model.RegionILCentral1,
+			override: nil,
+			wantErr:  nil,
+		},
+		{
+			name:     "success. region override for the region newer than the catalog",
+			region:   model.Region("ap-southeast-9"),
+			override: &RegionOverride{DisplayName: "Asia Pacific (New)", OptIn: true},
+			wantErr:  nil,
+		},
+		{
+			name:     "failure. region is not in the catalog",
+			region:   model.Region("ap-southeast-9"),
+			override: nil,
+			wantErr:  ErrInvalidRegion,
+		},
+		{
+			name:     "failure. region override for the invalid region name",
+			region:   model.Region("tokyo"),
+			override: &RegionOverride{},
+			wantErr:  ErrInvalidRegion,
+		},
+		{
+			name:     "failure. CloudFront is not available",
+			region:   model.RegionUSGovWest1,
+			override: nil,
+			wantErr:  model.ErrUnsupportedRegion,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &Config{Region: tt.region, RegionOverride: tt.override}
+			if err := c.validateRegion(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.validateRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigRegionInfo(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{Region: "ap-southeast-9", RegionOverride: &RegionOverride{DisplayName: "Asia Pacific (New)", OptIn: true}}
+	got, err := c.RegionInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Region != c.Region || got.DisplayName != "Asia Pacific (New)" || !got.OptIn || got.Partition != model.PartitionAWS {
+		t.Errorf("Config.RegionInfo() = %+v", got)
+	}
+
+	// The catalog wins over the override for the known region.
+	c = &Config{Region: model.RegionAPNortheast1, RegionOverride: &RegionOverride{DisplayName: "Tokyo", OptIn: true}}
+	if got, err = c.RegionInfo(); err != nil || got.DisplayName != "Asia Pacific (Tokyo)" || got.OptIn {
+		t.Errorf("Config.RegionInfo() = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// Retention is a type that represents the retention policy of releases.
+// It's used by 'spare gc'.
+type Retention struct {
+	// KeepLast is the number of the latest releases to keep.
+	KeepLast int `yaml:"keepLast"`
+	// KeepDays is the number of days to keep releases. Releases newer than this are kept.
+	// If it's zero, releases are not kept by age.
+	KeepDays int `yaml:"keepDays"`
+	// Pinned is the list of release IDs that are never deleted.
+	Pinned []model.ReleaseID `yaml:"pinned"`
+}
+
+// NewRetention returns a new Retention with default values.
+func NewRetention() Retention {
+	const (
+		defaultKeepLast = 10
+		defaultKeepDays = 30
+	)
+	return Retention{
+		KeepLast: defaultKeepLast,
+		KeepDays: defaultKeepDays,
+		Pinned:   []model.ReleaseID{},
+	}
+}
+
+// Validate validates Retention. If Retention is invalid, it returns an error.
+func (r Retention) Validate() (err error) {
+	if r.KeepLast < 0 {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidRetention, fmt.Sprintf("keepLast must not be negative: %d", r.KeepLast)))
+	}
+	if r.KeepDays < 0 {
+		err = errors.Join(err, errfmt.Wrap(ErrInvalidRetention, fmt.Sprintf("keepDays must not be negative: %d", r.KeepDays)))
+	}
+	for _, id := range r.Pinned {
+		if e := id.Validate(); e != nil {
+			err = errors.Join(err, errfmt.Wrap(ErrInvalidRetention, e.Error()))
+		}
+	}
+	return err
+}
+
+// Policy returns the retention policy for the garbage collection.
+func (r Retention) Policy() model.RetentionPolicy {
+	const day = 24 * time.Hour
+	return model.RetentionPolicy{
+		KeepLast:      r.KeepLast,
+		KeepNewerThan: time.Duration(r.KeepDays) * day,
+		Pinned:        r.Pinned,
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestRetentionValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		r       Retention
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			r:       NewRetention(),
+			wantErr: false,
+		},
+		{
+			name:    "success. pinned release",
+			r:       Retention{KeepLast: 1, KeepDays: 0, Pinned: []model.ReleaseID{"20231019T120000Z"}},
+			wantErr: false,
+		},
+		{
+			name:    "failure. keepLast is negative",
+			r:       Retention{KeepLast: -1},
+			wantErr: true,
+		},
+		{
+			name:    "failure. keepDays is negative",
+			r:       Retention{KeepDays: -1},
+			wantErr: true,
+		},
+		{
+			name:    "failure. pinned release id is invalid",
+			r:       Retention{Pinned: []model.ReleaseID{"latest"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Retention.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		r := Retention{KeepLast: 3, KeepDays: 2, Pinned: []model.ReleaseID{"20231019T120000Z"}}
+		want := model.RetentionPolicy{
+			KeepLast:      3,
+			KeepNewerThan: 48 * time.Hour,
+			Pinned:        []model.ReleaseID{"20231019T120000Z"},
+		}
+		if diff := cmp.Diff(want, r.Policy()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// SecurityHeaders is a type that represents the security headers that CloudFront adds to the responses.
+// It's applied by 'spare build'.
+type SecurityHeaders struct {
+	// Enabled is whether CloudFront adds the security headers.
+	// If it's false, the response headers policy is detached from CloudFront.
+	Enabled bool `yaml:"enabled"`
+	// StrictTransportSecurity is the Strict-Transport-Security (HSTS) header.
+	StrictTransportSecurity StrictTransportSecurity `yaml:"strictTransportSecurity"`
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header. If it's empty, the header is not sent.
+	ContentSecurityPolicy string `yaml:"contentSecurityPolicy"`
+	// ContentTypeOptions is whether the X-Content-Type-Options: nosniff header is sent.
+	ContentTypeOptions bool `yaml:"contentTypeOptions"`
+	// FrameOptions is the value of the X-Frame-Options header. DENY or SAMEORIGIN.
+	// If it's empty, the header is not sent.
+	FrameOptions string `yaml:"frameOptions"`
+	// ReferrerPolicy is the value of the Referrer-Policy header. If it's empty, the header is not sent.
+	ReferrerPolicy string `yaml:"referrerPolicy"`
+	// PermissionsPolicy is the value of the Permissions-Policy header. If it's empty, the header is not sent.
+	PermissionsPolicy string `yaml:"permissionsPolicy"`
+	// CustomHeaders is the list of other headers that CloudFront adds to the responses.
+	CustomHeaders []Header `yaml:"customHeaders"`
+}
+
+// StrictTransportSecurity is a type that represents the Strict-Transport-Security header.
+type StrictTransportSecurity struct {
+	// MaxAge is the time in seconds that browsers access the site only with HTTPS.
+	// If it's zero, the header is not sent.
+	MaxAge int64 `yaml:"maxAge"`
+	// IncludeSubdomains is whether HSTS applies to the subdomains.
+	IncludeSubdomains bool `yaml:"includeSubdomains"`
+	// Preload is whether the site is in the HSTS preload list.
+	Preload bool `yaml:"preload"`
+}
+
+// Header is a type that represents an HTTP header.
+type Header struct {
+	// Name is the name of the header. e.g. X-Robots-Tag
+	Name string `yaml:"name"`
+	// Value is the value of the header. e.g. noindex
+	Value string `yaml:"value"`
+}
+
+// NewSecurityHeaders returns a new SecurityHeaders with secure default values.
+func NewSecurityHeaders() SecurityHeaders {
+	s := model.NewSecurityHeaders()
+	customHeaders := make([]Header, 0, len(s.CustomHeaders))
+	for _, h := range s.CustomHeaders {
+		customHeaders = append(customHeaders, Header{Name: h.Name, Value: h.Value})
+	}
+	return SecurityHeaders{
+		Enabled: true,
+		StrictTransportSecurity: StrictTransportSecurity{
+			MaxAge:            s.StrictTransportSecurity.MaxAge,
+			IncludeSubdomains: s.StrictTransportSecurity.IncludeSubdomains,
+			Preload:           s.StrictTransportSecurity.Preload,
+		},
+		ContentSecurityPolicy: s.ContentSecurityPolicy,
+		ContentTypeOptions:    s.ContentTypeOptions,
+		FrameOptions:          s.FrameOptions,
+		ReferrerPolicy:        s.ReferrerPolicy,
+		PermissionsPolicy:     s.PermissionsPolicy,
+		CustomHeaders:         customHeaders,
+	}
+}
+
+// Validate validates SecurityHeaders. If SecurityHeaders is invalid, it returns an error.
+func (s SecurityHeaders) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if err := s.securityHeaders().Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidSecurityHeaders, err.Error())
+	}
+	return nil
+}
+
+// Headers returns the security headers of the CDN. If SecurityHeaders is disabled, it returns nil.
+func (s SecurityHeaders) Headers() *model.SecurityHeaders {
+	if !s.Enabled {
+		return nil
+	}
+	return s.securityHeaders()
+}
+
+// securityHeaders converts SecurityHeaders to model.SecurityHeaders.
+func (s SecurityHeaders) securityHeaders() *model.SecurityHeaders {
+	customHeaders := make([]model.HTTPHeader, 0, len(s.CustomHeaders))
+	for _, h := range s.CustomHeaders {
+		customHeaders = append(customHeaders, model.HTTPHeader{Name: h.Name, Value: h.Value})
+	}
+	return &model.SecurityHeaders{
+		StrictTransportSecurity: model.StrictTransportSecurity{
+			MaxAge:            s.StrictTransportSecurity.MaxAge,
+			IncludeSubdomains: s.StrictTransportSecurity.IncludeSubdomains,
+			Preload:           s.StrictTransportSecurity.Preload,
+		},
+		ContentSecurityPolicy: s.ContentSecurityPolicy,
+		ContentTypeOptions:    s.ContentTypeOptions,
+		FrameOptions:          s.FrameOptions,
+		ReferrerPolicy:        s.ReferrerPolicy,
+		PermissionsPolicy:     s.PermissionsPolicy,
+		CustomHeaders:         customHeaders,
+	}
+}
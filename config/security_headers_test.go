@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSecurityHeadersValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       SecurityHeaders
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			s:       NewSecurityHeaders(),
+			wantErr: false,
+		},
+		{
+			name:    "success. disabled security headers are not validated",
+			s:       SecurityHeaders{Enabled: false, FrameOptions: "ALLOWALL"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. invalid frameOptions",
+			s:       SecurityHeaders{Enabled: true, FrameOptions: "ALLOWALL"},
+			wantErr: true,
+		},
+		{
+			name: "failure. custom header name is empty",
+			s: SecurityHeaders{
+				Enabled:       true,
+				CustomHeaders: []Header{{Name: "", Value: "noindex"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SecurityHeaders.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled security headers return nil", func(t *testing.T) {
+		t.Parallel()
+		s := NewSecurityHeaders()
+		s.Enabled = false
+		if got := s.Headers(); got != nil {
+			t.Errorf("SecurityHeaders.Headers() = %v, want nil", got)
+		}
+	})
+}
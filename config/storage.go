@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// defaultNoncurrentVersionExpirationDays is the default number of days after which the noncurrent versions are deleted.
+	defaultNoncurrentVersionExpirationDays = 30
+	// defaultAbortIncompleteMultipartUploadDays is the default number of days after which the incomplete multipart uploads are aborted.
+	defaultAbortIncompleteMultipartUploadDays = 7
+)
+
+// Storage is a type that represents the hardening of the S3 bucket that serves the SPA. It's applied by 'spare build'.
+type Storage struct {
+	// Versioning is whether the bucket keeps the previous versions of the objects.
+	// Once it's enabled, disabling it suspends the versioning, and the previous versions are kept.
+	Versioning bool `yaml:"versioning"`
+	// ObjectOwnership is the object ownership of the bucket. BucketOwnerEnforced disables ACLs.
+	// If it's empty, the object ownership is left as it is.
+	ObjectOwnership model.ObjectOwnership `yaml:"objectOwnership"`
+	// Lifecycle is the lifecycle rules of the bucket.
+	Lifecycle Lifecycle `yaml:"lifecycle"`
+	// Endpoint is the S3-compatible storage (e.g. MinIO, Cloudflare R2) that spare uses instead of Amazon S3.
+	// It's omitted by default.
+	Endpoint *StorageEndpoint `yaml:"endpoint,omitempty"`
+	// PublicRead is whether 'spare build' makes the site at the root of the bucket public with the bucket policy.
+	// It's only for the S3-compatible storage, which serves the bucket without CloudFront. It's false by default.
+	PublicRead bool `yaml:"publicRead"`
+}
+
+// Lifecycle is a type that represents the lifecycle rules of the bucket.
+type Lifecycle struct {
+	// NoncurrentVersionExpirationDays is the number of days after which the noncurrent versions are deleted.
+	// It's used only when the versioning is enabled. 0 means the noncurrent versions do not expire.
+	NoncurrentVersionExpirationDays int `yaml:"noncurrentVersionExpirationDays"`
+	// AbortIncompleteMultipartUploadDays is the number of days after which the incomplete multipart uploads are aborted.
+	// 0 means the incomplete multipart uploads are not aborted.
+	AbortIncompleteMultipartUploadDays int `yaml:"abortIncompleteMultipartUploadDays"`
+	// Transitions is the transitions of the objects under the prefixes (e.g. the old releases) to cheaper storage classes.
+	Transitions []LifecycleTransition `yaml:"transitions"`
+}
+
+// LifecycleTransition is a type that represents the transition of the objects under the prefix.
+type LifecycleTransition struct {
+	// Prefix is the S3 key prefix of the objects (e.g. releases/).
+	Prefix string `yaml:"prefix"`
+	// Days is the number of days after the upload when the objects are transitioned.
+	Days int `yaml:"days"`
+	// StorageClass is the storage class that the objects are transitioned to.
+	// STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING or GLACIER_IR.
+	StorageClass model.StorageClass `yaml:"storageClass"`
+}
+
+// NewStorage returns a new Storage with default values. ACLs are disabled, and the versioning is disabled.
+func NewStorage() Storage {
+	return Storage{
+		Versioning:      false,
+		ObjectOwnership: model.ObjectOwnershipBucketOwnerEnforced,
+		Lifecycle: Lifecycle{
+			NoncurrentVersionExpirationDays:    defaultNoncurrentVersionExpirationDays,
+			AbortIncompleteMultipartUploadDays: defaultAbortIncompleteMultipartUploadDays,
+			Transitions:                        []LifecycleTransition{},
+		},
+	}
+}
+
+// Validate validates Storage. If Storage is invalid, it returns an error.
+func (s Storage) Validate() error {
+	if s.Lifecycle.NoncurrentVersionExpirationDays < 0 {
+		return errfmt.Wrap(ErrInvalidStorage,
+			fmt.Sprintf("noncurrentVersionExpirationDays must be 0 or greater: %d", s.Lifecycle.NoncurrentVersionExpirationDays))
+	}
+	if s.Lifecycle.AbortIncompleteMultipartUploadDays < 0 {
+		return errfmt.Wrap(ErrInvalidStorage,
+			fmt.Sprintf("abortIncompleteMultipartUploadDays must be 0 or greater: %d", s.Lifecycle.AbortIncompleteMultipartUploadDays))
+	}
+	for _, t := range s.Lifecycle.Transitions {
+		if strings.HasPrefix(t.Prefix, "/") {
+			return errfmt.Wrap(ErrInvalidStorage, fmt.Sprintf("transition prefix must not start with '/': %s", t.Prefix))
+		}
+	}
+	if err := s.Settings().Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidStorage, err.Error())
+	}
+	if s.PublicRead && s.Endpoint == nil {
+		return errfmt.Wrap(ErrInvalidStorage, "publicRead needs endpoint. CloudFront serves the Amazon S3 bucket, and the bucket is private")
+	}
+	return nil
+}
+
+// Settings returns the bucket settings. The lifecycle rules are named "spare-*" so that they can be told from the others.
+func (s Storage) Settings() *model.BucketSettings {
+	rules := make([]model.LifecycleRule, 0, len(s.Lifecycle.Transitions)+2) //nolint:gomnd
+	if s.Versioning && s.Lifecycle.NoncurrentVersionExpirationDays > 0 {
+		rules = append(rules, model.LifecycleRule{
+			ID:                              "spare-noncurrent-versions",
+			NoncurrentVersionExpirationDays: s.Lifecycle.NoncurrentVersionExpirationDays,
+			ExpiredObjectDeleteMarker:       true,
+		})
+	}
+	if s.Lifecycle.AbortIncompleteMultipartUploadDays > 0 {
+		rules = append(rules, model.LifecycleRule{
+			ID:                                 "spare-abort-multipart-uploads",
+			AbortIncompleteMultipartUploadDays: s.Lifecycle.AbortIncompleteMultipartUploadDays,
+		})
+	}
+	for i, t := range s.Lifecycle.Transitions {
+		rules = append(rules, model.LifecycleRule{
+			ID:          fmt.Sprintf("spare-transition-%d", i+1),
+			Prefix:      t.Prefix,
+			Transitions: []model.LifecycleTransition{{Days: t.Days, StorageClass: t.StorageClass}},
+		})
+	}
+	return &model.BucketSettings{
+		Versioning:     s.Versioning,
+		Ownership:      s.ObjectOwnership,
+		LifecycleRules: rules,
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// StorageEndpoint is the S3-compatible storage (e.g. MinIO, Cloudflare R2, Ceph) that spare uses instead of Amazon S3.
+// It's omitted by default. The storage has no CloudFront, so 'spare build' creates only the bucket, and
+// 'spare deploy' copies the live release to the root of the bucket. The credentials are the keys of the AWS profile.
+type StorageEndpoint struct {
+	// URL is the URL of the S3 API. e.g. https://ACCOUNT_ID.r2.cloudflarestorage.com, http://localhost:9000
+	URL model.Endpoint `yaml:"url"`
+	// Provider is minio, r2, ceph or other. It decides the defaults of PathStyle and SigningRegion.
+	Provider model.StorageProvider `yaml:"provider"`
+	// PathStyle is whether the requests use the path-style URL (https://HOST/BUCKET/KEY).
+	// If it's omitted, it's true for minio and ceph.
+	PathStyle *bool `yaml:"pathStyle,omitempty"`
+	// SigningRegion is the region in the signature (SigV4). If it's empty, it's "auto" for r2 and "us-east-1" for the others.
+	SigningRegion string `yaml:"signingRegion,omitempty"`
+	// UnsignedPayload is whether the payload is not signed (UNSIGNED-PAYLOAD). It needs an https:// URL.
+	UnsignedPayload bool `yaml:"unsignedPayload,omitempty"`
+	// CABundle is the path of the PEM file of the CA certificates that verify the certificate of the storage
+	// (e.g. the private CA of MinIO). They are added to the system pool.
+	CABundle string `yaml:"caBundle,omitempty"`
+	// InsecureSkipVerify is whether the certificate of the storage is not verified. Use it only for testing.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+	// PublicURL is the URL where the storage serves the bucket (e.g. the custom domain of R2). It's shown after deploying.
+	PublicURL string `yaml:"publicUrl,omitempty"`
+}
+
+// Settings returns the S3-compatible storage with the defaults of the provider. The CA bundle is read from the file.
+// If StorageEndpoint is nil, it returns nil, and spare uses Amazon S3.
+func (s *StorageEndpoint) Settings() (*model.StorageEndpoint, error) {
+	if s == nil {
+		return nil, nil
+	}
+	provider := s.Provider
+	if provider == "" {
+		provider = model.StorageProviderOther
+	}
+	settings := &model.StorageEndpoint{
+		URL:                s.URL,
+		Provider:           provider,
+		PathStyle:          provider.DefaultPathStyle(),
+		SigningRegion:      s.SigningRegion,
+		UnsignedPayload:    s.UnsignedPayload,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+		PublicURL:          s.PublicURL,
+	}
+	if s.PathStyle != nil {
+		settings.PathStyle = *s.PathStyle
+	}
+	if settings.SigningRegion == "" {
+		settings.SigningRegion = provider.DefaultSigningRegion()
+	}
+	if s.CABundle != "" {
+		pem, err := os.ReadFile(filepath.Clean(s.CABundle))
+		if err != nil {
+			return nil, errfmt.Wrap(ErrInvalidStorage, fmt.Sprintf("can not read caBundle: %s", err.Error()))
+		}
+		settings.CABundle = pem
+	}
+	return settings, nil
+}
+
+// S3Compatible returns whether the bucket is in the S3-compatible storage instead of Amazon S3.
+// The AWS services other than S3 (e.g. CloudFront, WAF, KMS) are not used with the storage.
+func (c *Config) S3Compatible() bool {
+	return c.Storage.Endpoint != nil
+}
+
+// validateStorageEndpoint validates the S3-compatible storage. The settings that need CloudFront or the other
+// AWS services can not be used with the storage, because they would be silently ignored.
+func (c *Config) validateStorageEndpoint() error {
+	settings, err := c.Storage.Endpoint.Settings()
+	if err != nil || settings == nil {
+		return err
+	}
+	if err := settings.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidStorage, err.Error())
+	}
+
+	awsOnly := []struct {
+		name string
+		used bool
+	}{
+		{name: "assumeRole", used: c.AssumeRole != nil},
+		{name: "waf.enabled", used: c.WAF.Enabled},
+		{name: "auth.basic.enabled", used: c.Auth.Basic.Enabled},
+		{name: "origins", used: len(c.Origins) > 0},
+		{name: "logging.enabled", used: c.Logging.Enabled},
+		{name: "encryption.type sse-kms", used: c.Encryption.Type == model.EncryptionTypeSSEKMS},
+	}
+	for _, setting := range awsOnly {
+		if setting.used {
+			return errfmt.Wrap(ErrInvalidStorage, fmt.Sprintf("%s needs AWS, so it can not be used with storage.endpoint", setting.name))
+		}
+	}
+	return nil
+}
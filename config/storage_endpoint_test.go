@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestStorageEndpointSettings(t *testing.T) {
+	t.Parallel()
+
+	pathStyle := false
+	tests := []struct {
+		name    string
+		s       *StorageEndpoint
+		want    *model.StorageEndpoint
+		wantErr bool
+	}{
+		{
+			name:    "success. endpoint is omitted",
+			s:       nil,
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "success. defaults of minio",
+			s:    &StorageEndpoint{URL: "http://localhost:9000", Provider: model.StorageProviderMinIO},
+			want: &model.StorageEndpoint{
+				URL:           "http://localhost:9000",
+				Provider:      model.StorageProviderMinIO,
+				PathStyle:     true,
+				SigningRegion: "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success. defaults of r2",
+			s: &StorageEndpoint{
+				URL:             "https://0123456789abcdef.r2.cloudflarestorage.com",
+				Provider:        model.StorageProviderR2,
+				UnsignedPayload: true,
+				PublicURL:       "https://www.example.com",
+			},
+			want: &model.StorageEndpoint{
+				URL:             "https://0123456789abcdef.r2.cloudflarestorage.com",
+				Provider:        model.StorageProviderR2,
+				PathStyle:       false,
+				SigningRegion:   "auto",
+				UnsignedPayload: true,
+				PublicURL:       "https://www.example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success. path style and signing region are overridden",
+			s: &StorageEndpoint{
+				URL:           "https://minio.example.com",
+				Provider:      model.StorageProviderMinIO,
+				PathStyle:     &pathStyle,
+				SigningRegion: "ap-northeast-1",
+			},
+			want: &model.StorageEndpoint{
+				URL:           "https://minio.example.com",
+				Provider:      model.StorageProviderMinIO,
+				PathStyle:     false,
+				SigningRegion: "ap-northeast-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success. provider is omitted",
+			s:    &StorageEndpoint{URL: "https://storage.example.com"},
+			want: &model.StorageEndpoint{
+				URL:           "https://storage.example.com",
+				Provider:      model.StorageProviderOther,
+				SigningRegion: "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failure. ca bundle does not exist",
+			s:       &StorageEndpoint{URL: "https://minio.example.com", CABundle: filepath.Join("testdata", "not_exist.pem")},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.s.Settings()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StorageEndpoint.Settings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("StorageEndpoint.Settings() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigValidateStorageEndpoint(t *testing.T) {
+	t.Parallel()
+
+	minio := &StorageEndpoint{URL: "http://localhost:9000", Provider: model.StorageProviderMinIO}
+	tests := []struct {
+		name    string
+		c       func() *Config
+		wantErr error
+	}{
+		{
+			name:    "success. endpoint is omitted",
+			c:       NewConfig,
+			wantErr: nil,
+		},
+		{
+			name: "success. minio",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = minio
+				return c
+			},
+			wantErr: nil,
+		},
+		{
+			name: "failure. provider is unknown",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = &StorageEndpoint{URL: "http://localhost:9000", Provider: "gcs"}
+				return c
+			},
+			wantErr: ErrInvalidStorage,
+		},
+		{
+			name: "failure. unsigned payload over http",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = &StorageEndpoint{URL: "http://localhost:9000", UnsignedPayload: true}
+				return c
+			},
+			wantErr: ErrInvalidStorage,
+		},
+		{
+			name: "failure. assume role",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = minio
+				c.AssumeRole = &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/spare-deploy"}
+				return c
+			},
+			wantErr: ErrInvalidStorage,
+		},
+		{
+			name: "failure. waf",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = minio
+				c.WAF.Enabled = true
+				return c
+			},
+			wantErr: ErrInvalidStorage,
+		},
+		{
+			name: "failure. logging",
+			c: func() *Config {
+				c := NewConfig()
+				c.Storage.Endpoint = minio
+				c.Logging.Enabled = true
+				return c
+			},
+			wantErr: ErrInvalidStorage,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.c().validateStorageEndpoint(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.validateStorageEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestStorageValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       Storage
+		wantErr bool
+	}{
+		{
+			name:    "success. default storage",
+			s:       NewStorage(),
+			wantErr: false,
+		},
+		{
+			name: "success. transition the old releases",
+			s: Storage{
+				Versioning:      true,
+				ObjectOwnership: model.ObjectOwnershipBucketOwnerEnforced,
+				Lifecycle: Lifecycle{
+					Transitions: []LifecycleTransition{{Prefix: "releases/", Days: 30, StorageClass: model.StorageClassStandardIA}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success. public-read S3-compatible storage",
+			s: Storage{
+				Endpoint:   &StorageEndpoint{URL: "http://localhost:9000", Provider: model.StorageProviderMinIO},
+				PublicRead: true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failure. public-read Amazon S3 bucket",
+			s:       Storage{PublicRead: true},
+			wantErr: true,
+		},
+		{
+			name:    "failure. unknown object ownership",
+			s:       Storage{ObjectOwnership: "BucketOwner"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. negative noncurrentVersionExpirationDays",
+			s:       Storage{Lifecycle: Lifecycle{NoncurrentVersionExpirationDays: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "failure. negative abortIncompleteMultipartUploadDays",
+			s:       Storage{Lifecycle: Lifecycle{AbortIncompleteMultipartUploadDays: -1}},
+			wantErr: true,
+		},
+		{
+			name: "failure. prefix starts with '/'",
+			s: Storage{Lifecycle: Lifecycle{
+				Transitions: []LifecycleTransition{{Prefix: "/releases/", Days: 30, StorageClass: model.StorageClassStandardIA}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "failure. GLACIER can not be read by CloudFront",
+			s: Storage{Lifecycle: Lifecycle{
+				Transitions: []LifecycleTransition{{Prefix: "releases/", Days: 90, StorageClass: "GLACIER"}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Storage.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStorageSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("noncurrent versions do not expire without versioning", func(t *testing.T) {
+		t.Parallel()
+		want := &model.BucketSettings{
+			Versioning: false,
+			Ownership:  model.ObjectOwnershipBucketOwnerEnforced,
+			LifecycleRules: []model.LifecycleRule{
+				{ID: "spare-abort-multipart-uploads", AbortIncompleteMultipartUploadDays: 7},
+			},
+		}
+		if diff := cmp.Diff(want, NewStorage().Settings()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("all lifecycle rules", func(t *testing.T) {
+		t.Parallel()
+		s := Storage{
+			Versioning:      true,
+			ObjectOwnership: model.ObjectOwnershipBucketOwnerEnforced,
+			Lifecycle: Lifecycle{
+				NoncurrentVersionExpirationDays:    14,
+				AbortIncompleteMultipartUploadDays: 3,
+				Transitions: []LifecycleTransition{
+					{Prefix: "releases/", Days: 30, StorageClass: model.StorageClassStandardIA},
+					{Prefix: "releases/", Days: 90, StorageClass: model.StorageClassGlacierIR},
+				},
+			},
+		}
+		want := &model.BucketSettings{
+			Versioning: true,
+			Ownership:  model.ObjectOwnershipBucketOwnerEnforced,
+			LifecycleRules: []model.LifecycleRule{
+				{ID: "spare-noncurrent-versions", NoncurrentVersionExpirationDays: 14, ExpiredObjectDeleteMarker: true},
+				{ID: "spare-abort-multipart-uploads", AbortIncompleteMultipartUploadDays: 3},
+				{
+					ID: "spare-transition-1", Prefix: "releases/",
+					Transitions: []model.LifecycleTransition{{Days: 30, StorageClass: model.StorageClassStandardIA}},
+				},
+				{
+					ID: "spare-transition-2", Prefix: "releases/",
+					Transitions: []model.LifecycleTransition{{Days: 90, StorageClass: model.StorageClassGlacierIR}},
+				},
+			},
+		}
+		if diff := cmp.Diff(want, s.Settings()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
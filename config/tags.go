@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+const (
+	// defaultEnv is the default environment in the spare:env tag.
+	defaultEnv = "production"
+)
+
+// validateTags validates the environment and the user-defined tags.
+func (c *Config) validateTags() error {
+	// The environment is the value of the spare:env tag, so it's validated as a tag value.
+	if err := (model.Tags{"env": c.Env}).Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidTags, err.Error())
+	}
+	if err := c.Tags.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidTags, err.Error())
+	}
+	return nil
+}
+
+// ResourceTags returns the tags that 'spare build' applies to the resources.
+// version is the spare version in the spare:version tag. If Env is empty, "production" is used.
+func (c *Config) ResourceTags(version string) model.Tags {
+	env := c.Env
+	if env == "" {
+		env = defaultEnv
+	}
+	return model.NewSpareTags(c.S3BucketName, env, version, c.Tags)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestConfigValidateTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		env     string
+		tags    model.Tags
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			env:     "staging",
+			tags:    model.Tags{"team": "web"},
+			wantErr: false,
+		},
+		{
+			name:    "success. env is empty",
+			env:     "",
+			tags:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "failure. invalid env",
+			env:     "staging;prod",
+			tags:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "failure. reserved tag key",
+			env:     "staging",
+			tags:    model.Tags{"spare:env": "production"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &Config{Env: tt.env, Tags: tt.tags}
+			if err := c.validateTags(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.validateTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigResourceTags(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{S3BucketName: "my-bucket", Tags: model.Tags{"team": "web"}}
+	want := model.Tags{
+		"team":          "web",
+		"spare:project": "my-bucket",
+		"spare:env":     "production",
+		"spare:version": "v0.1.0",
+	}
+	if diff := cmp.Diff(want, c.ResourceTags("v0.1.0")); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
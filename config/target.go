@@ -1,6 +1,16 @@
 package config
 
-import "github.com/nao1215/spare/utils/errfmt"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
 
 // DeployTarget is a type that represents a deploy target path.
 type DeployTarget string
@@ -11,11 +21,112 @@ func (d DeployTarget) String() string {
 }
 
 // Validate validates DeployTarget. If DeployTarget is invalid, it returns an error.
-// DeployTarget is invalid if it is empty.
+// DeployTarget is invalid if it is empty. The contents are checked by ValidateContents,
+// because the SPA is often built after .spare.yml is validated.
 func (d DeployTarget) Validate() error {
 	if d == "" {
 		return errfmt.Wrap(ErrInvalidDeployTarget, "DeployTarget is empty")
 	}
-	// TODO: check if the path exists
 	return nil
 }
+
+// ValidateContents returns an error if DeployTarget is not a directory or does not contain index.html.
+func (d DeployTarget) ValidateContents() error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	info, err := os.Stat(d.String())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errfmt.Wrap(ErrInvalidDeployTarget, fmt.Sprintf("%s does not exist", d))
+		}
+		return errfmt.Wrap(ErrInvalidDeployTarget, err.Error())
+	}
+	if !info.IsDir() {
+		return errfmt.Wrap(ErrInvalidDeployTarget, fmt.Sprintf("%s is not a directory", d))
+	}
+	index := filepath.Join(d.String(), "index.html")
+	if info, err := os.Stat(index); err != nil || info.IsDir() {
+		return errfmt.Wrap(ErrInvalidDeployTarget, fmt.Sprintf("%s does not exist", index))
+	}
+	return nil
+}
+
+// IsRulesFile returns true if the file is the _redirects file or the _headers file in the deploy target.
+// They are read by spare, and are not uploaded to S3.
+func (d DeployTarget) IsRulesFile(path string) bool {
+	return filepath.Clean(path) == filepath.Join(d.String(), model.RedirectsFileName) ||
+		filepath.Clean(path) == filepath.Join(d.String(), model.HeadersFileName)
+}
+
+// Redirects reads the redirect rules from the _redirects file in the deploy target.
+// If the file does not exist, it returns no rules. The unsupported rules are returned as warnings.
+func (d DeployTarget) Redirects() (model.Redirects, []string, error) {
+	var (
+		rules    model.Redirects
+		warnings []string
+	)
+	err := d.readRulesFile(model.RedirectsFileName, func(r io.Reader) (err error) {
+		rules, warnings, err = model.ParseRedirects(r)
+		return err
+	})
+	return rules, warnings, err
+}
+
+// HeaderRules reads the header rules from the _headers file in the deploy target.
+// If the file does not exist, it returns no rules. The unsupported headers are returned as warnings.
+func (d DeployTarget) HeaderRules() (model.HeaderRules, []string, error) {
+	var (
+		rules    model.HeaderRules
+		warnings []string
+	)
+	err := d.readRulesFile(model.HeadersFileName, func(r io.Reader) (err error) {
+		rules, warnings, err = model.ParseHeaderRules(r)
+		return err
+	})
+	return rules, warnings, err
+}
+
+// HTMLFiles returns the slash-separated paths of the HTML files relative to the deploy target. e.g. about.html, docs/index.html
+// If the deploy target does not exist, it returns no files.
+func (d DeployTarget) HTMLFiles() ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(d.String(), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".html" {
+			return nil
+		}
+		rel, err := filepath.Rel(d.String(), path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to list the HTML files in the deploy target")
+	}
+	return files, nil
+}
+
+// readRulesFile opens the file in the deploy target and passes it to parse. If the file does not exist, it does nothing.
+func (d DeployTarget) readRulesFile(name string, parse func(io.Reader) error) (err error) {
+	file, err := os.Open(filepath.Join(d.String(), name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return parse(file)
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
 )
 
 func TestDeployTargetString(t *testing.T) {
@@ -56,3 +60,161 @@ func TestDeployTargetValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployTargetValidateContents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		d       DeployTarget
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			d:       DeployTarget(filepath.Join("testdata", "target")),
+			wantErr: false,
+		},
+		{
+			name:    "failure. deploy target is empty",
+			d:       "",
+			wantErr: true,
+		},
+		{
+			name:    "failure. deploy target does not exist",
+			d:       DeployTarget(filepath.Join("testdata", "not_exist")),
+			wantErr: true,
+		},
+		{
+			name:    "failure. deploy target is a file",
+			d:       DeployTarget(filepath.Join("testdata", "test.yml")),
+			wantErr: true,
+		},
+		{
+			name:    "failure. deploy target does not contain index.html",
+			d:       DeployTarget(filepath.Join("testdata", "target", "blog")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.d.ValidateContents(); (err != nil) != tt.wantErr {
+				t.Errorf("DeployTarget.ValidateContents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeployTargetIsRulesFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "_redirects",
+			path: filepath.Join("src", "_redirects"),
+			want: true,
+		},
+		{
+			name: "_headers",
+			path: filepath.Join("src", "_headers"),
+			want: true,
+		},
+		{
+			name: "_redirects in sub directory is uploaded",
+			path: filepath.Join("src", "docs", "_redirects"),
+			want: false,
+		},
+		{
+			name: "index.html",
+			path: filepath.Join("src", "index.html"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := DeployTarget("src").IsRulesFile(tt.path); got != tt.want {
+				t.Errorf("DeployTarget.IsRulesFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeployTargetRedirects(t *testing.T) {
+	t.Parallel()
+
+	t.Run("read _redirects in deploy target", func(t *testing.T) {
+		t.Parallel()
+		rules, warnings, err := DeployTarget(filepath.Join("testdata", "target")).Redirects()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.Redirects{{From: "/home", To: "/", Status: 301}}
+		if diff := cmp.Diff(want, rules); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("warnings = %v, want 1 warning", warnings)
+		}
+	})
+
+	t.Run("no _redirects in deploy target", func(t *testing.T) {
+		t.Parallel()
+		rules, warnings, err := DeployTarget("testdata").Redirects()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 0 || len(warnings) != 0 {
+			t.Errorf("Redirects() = (%v, %v), want no rules and no warnings", rules, warnings)
+		}
+	})
+}
+
+func TestDeployTargetHeaderRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("read _headers in deploy target", func(t *testing.T) {
+		t.Parallel()
+		rules, _, err := DeployTarget(filepath.Join("testdata", "target")).HeaderRules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.HeaderRules{
+			{Path: "/assets/*", Headers: []model.HTTPHeader{{Name: "Cache-Control", Value: "max-age=31536000"}}},
+		}
+		if diff := cmp.Diff(want, rules); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestDeployTargetHTMLFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("list the HTML files", func(t *testing.T) {
+		t.Parallel()
+		files, err := DeployTarget(filepath.Join("testdata", "target")).HTMLFiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"about.html", "blog/post.html", "docs/index.html", "docs/intro/index.html", "docs.html", "index.html"}
+		if diff := cmp.Diff(want, files); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("deploy target does not exist", func(t *testing.T) {
+		t.Parallel()
+		files, err := DeployTarget(filepath.Join("testdata", "not_exist")).HTMLFiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 0 {
+			t.Errorf("HTMLFiles() = %v, want no files", files)
+		}
+	})
+}
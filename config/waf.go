@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nao1215/spare/app/domain/model"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// WAF is a type that represents the AWS WAF (WAFv2) web ACL that protects CloudFront.
+// It's applied by 'spare build'.
+type WAF struct {
+	// Enabled is whether the web ACL is attached to CloudFront.
+	// If it's false, 'spare build' detaches and deletes the web ACL created by spare.
+	Enabled bool `yaml:"enabled"`
+	// ManagedRules is the list of AWS managed rule groups. common, knownBadInputs or ipReputation.
+	ManagedRules []string `yaml:"managedRules"`
+	// RateLimit is the maximum number of requests from an IP address in 5 minutes.
+	// If it's zero, the rate-based rule is not used.
+	RateLimit int64 `yaml:"rateLimit"`
+	// AllowListFiles is the list of files that contain the CIDRs that are always allowed. One CIDR per line.
+	AllowListFiles []string `yaml:"allowListFiles"`
+	// DenyListFiles is the list of files that contain the CIDRs that are always blocked. One CIDR per line.
+	DenyListFiles []string `yaml:"denyListFiles"`
+	// BlockCountries is the list of ISO 3166-1 alpha-2 country codes to block. e.g. KP
+	BlockCountries []string `yaml:"blockCountries"`
+}
+
+// NewWAF returns a new WAF with default values. WAF is disabled by default because it's charged.
+func NewWAF() WAF {
+	const defaultRateLimit = 2000
+	return WAF{
+		Enabled:        false,
+		ManagedRules:   model.ManagedRuleNames(),
+		RateLimit:      defaultRateLimit,
+		AllowListFiles: []string{},
+		DenyListFiles:  []string{},
+		BlockCountries: []string{},
+	}
+}
+
+// Validate validates WAF. If WAF is invalid, it returns an error.
+func (w WAF) Validate() error {
+	rules, err := w.Rules()
+	if err != nil {
+		return err
+	}
+	if rules == nil {
+		return nil
+	}
+	if err := rules.Validate(); err != nil {
+		return errfmt.Wrap(ErrInvalidWAF, err.Error())
+	}
+	return nil
+}
+
+// Rules returns the rules of the web ACL. The CIDRs are read from the allow/deny list files.
+// If WAF is disabled, it returns nil.
+func (w WAF) Rules() (*model.WAF, error) {
+	if !w.Enabled {
+		return nil, nil
+	}
+	allowIPs, err := readCIDRFiles(w.AllowListFiles)
+	if err != nil {
+		return nil, err
+	}
+	denyIPs, err := readCIDRFiles(w.DenyListFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WAF{
+		ManagedRules:   w.ManagedRules,
+		RateLimit:      w.RateLimit,
+		AllowIPs:       allowIPs,
+		DenyIPs:        denyIPs,
+		BlockCountries: w.BlockCountries,
+	}, nil
+}
+
+// readCIDRFiles reads the CIDRs from the files.
+func readCIDRFiles(paths []string) ([]string, error) {
+	cidrs := []string{}
+	for _, path := range paths {
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return nil, errfmt.Wrap(ErrInvalidWAF, err.Error())
+		}
+		list, err := model.ParseCIDRList(file)
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, errfmt.Wrap(ErrInvalidWAF, fmt.Sprintf("%s: %s", path, err.Error()))
+		}
+		cidrs = append(cidrs, list...)
+	}
+	return cidrs, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+func TestWAFValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		w       WAF
+		wantErr bool
+	}{
+		{
+			name:    "success. disabled",
+			w:       NewWAF(),
+			wantErr: false,
+		},
+		{
+			name: "success. enabled",
+			w: WAF{
+				Enabled:        true,
+				ManagedRules:   []string{"common"},
+				RateLimit:      2000,
+				AllowListFiles: []string{filepath.Join("testdata", "waf", "allow.txt")},
+				BlockCountries: []string{"KP"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failure. list file does not exist",
+			w: WAF{
+				Enabled:       true,
+				DenyListFiles: []string{filepath.Join("testdata", "waf", "not_exist.txt")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure. list file has invalid IP address",
+			w: WAF{
+				Enabled:       true,
+				DenyListFiles: []string{filepath.Join("testdata", "waf", "invalid.txt")},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid rate limit",
+			w:       WAF{Enabled: true, RateLimit: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.w.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("WAF.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWAFRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		w := WAF{
+			Enabled:        true,
+			ManagedRules:   []string{"common"},
+			RateLimit:      2000,
+			AllowListFiles: []string{filepath.Join("testdata", "waf", "allow.txt")},
+			DenyListFiles:  []string{},
+			BlockCountries: []string{"KP"},
+		}
+		got, err := w.Rules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.WAF{
+			ManagedRules:   []string{"common"},
+			RateLimit:      2000,
+			AllowIPs:       []string{"192.0.2.0/24", "2001:db8::/32"},
+			DenyIPs:        []string{},
+			BlockCountries: []string{"KP"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
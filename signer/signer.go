@@ -0,0 +1,172 @@
+// Package signer creates CloudFront signed URLs and signed cookies for the cache behaviors whose 'signed' is true
+// in .spare.yml. The key pair is created by 'spare keys create', which writes the private key to "<KEY_PAIR_ID>.pem".
+//
+//	s, err := signer.NewFromFile("K2JCJMDEHXQW5F.pem", "")
+//	if err != nil {
+//		return err
+//	}
+//	url, err := s.SignURL("https://example.com/private/report.pdf", signer.Condition{
+//		Expires: time.Now().Add(time.Hour),
+//	})
+package signer
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudfront/sign"
+	"github.com/nao1215/spare/utils/errfmt"
+)
+
+// ErrInvalidCondition is an error that occurs when the condition of the signed URL is invalid.
+var ErrInvalidCondition = errors.New("invalid condition of signed URL")
+
+// Condition is a type that represents the restrictions of the signed URL and the signed cookies.
+type Condition struct {
+	// Expires is the time when the signed URL expires. It's required.
+	Expires time.Time
+	// NotBefore is the time when the signed URL becomes valid. If it's zero, the signed URL is valid immediately.
+	NotBefore time.Time
+	// IPAddress is the IP address or the CIDR that the viewers must come from. e.g. 203.0.113.10, 203.0.113.0/24
+	// If it's empty, the viewers from any IP address can use the signed URL.
+	IPAddress string
+}
+
+// Validate validates Condition. If Condition is invalid, it returns an error.
+func (c Condition) Validate() error {
+	if c.Expires.IsZero() {
+		return errfmt.Wrap(ErrInvalidCondition, "expiration time is required")
+	}
+	if !c.NotBefore.IsZero() && !c.NotBefore.Before(c.Expires) {
+		return errfmt.Wrap(ErrInvalidCondition, "start time must be before the expiration time")
+	}
+	if c.IPAddress != "" {
+		if _, err := c.sourceIP(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceIP returns the CIDR of IPAddress. CloudFront requires the CIDR, so "/32" (or "/128") is added to the IP address.
+func (c Condition) sourceIP() (string, error) {
+	if strings.Contains(c.IPAddress, "/") {
+		if _, _, err := net.ParseCIDR(c.IPAddress); err != nil {
+			return "", errfmt.Wrap(ErrInvalidCondition, fmt.Sprintf("%s is not a CIDR", c.IPAddress))
+		}
+		return c.IPAddress, nil
+	}
+	ip := net.ParseIP(c.IPAddress)
+	if ip == nil {
+		return "", errfmt.Wrap(ErrInvalidCondition, fmt.Sprintf("%s is not an IP address", c.IPAddress))
+	}
+	if ip.To4() != nil {
+		return c.IPAddress + "/32", nil
+	}
+	return c.IPAddress + "/128", nil
+}
+
+// policy returns the custom policy for the resource.
+func (c Condition) policy(resource string) (*sign.Policy, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	condition := sign.Condition{
+		DateLessThan: sign.NewAWSEpochTime(c.Expires),
+	}
+	if !c.NotBefore.IsZero() {
+		condition.DateGreaterThan = sign.NewAWSEpochTime(c.NotBefore)
+	}
+	if c.IPAddress != "" {
+		ip, err := c.sourceIP()
+		if err != nil {
+			return nil, err
+		}
+		condition.IPAddress = &sign.IPAddress{SourceIP: ip}
+	}
+	return &sign.Policy{
+		Statements: []sign.Statement{{Resource: resource, Condition: condition}},
+	}, nil
+}
+
+// Signer signs URLs and cookies with the private key whose public key is in the key group of spare.
+type Signer struct {
+	// keyPairID is the ID of the public key in CloudFront.
+	keyPairID string
+	// privateKey is the private key.
+	privateKey *rsa.PrivateKey
+}
+
+// New returns a new Signer. keyPairID is the ID of the public key in CloudFront, and privateKeyPEM is the PEM encoded private key.
+func New(keyPairID string, privateKeyPEM []byte) (*Signer, error) {
+	if keyPairID == "" {
+		return nil, errors.New("key pair ID is empty")
+	}
+	key, err := sign.LoadPEMPrivKey(strings.NewReader(string(privateKeyPEM)))
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to load the private key")
+	}
+	return &Signer{
+		keyPairID:  keyPairID,
+		privateKey: key,
+	}, nil
+}
+
+// NewFromFile returns a new Signer with the private key file. If keyPairID is empty,
+// the file name without the extension is used, because 'spare keys create' writes the private key to "<KEY_PAIR_ID>.pem".
+func NewFromFile(path, keyPairID string) (*Signer, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to read the private key")
+	}
+	if keyPairID == "" {
+		keyPairID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return New(keyPairID, data)
+}
+
+// KeyPairID returns the ID of the public key in CloudFront.
+func (s *Signer) KeyPairID() string {
+	return s.keyPairID
+}
+
+// SignURL returns the signed URL of rawURL. The signed URL has the Policy, Signature and Key-Pair-Id query parameters.
+func (s *Signer) SignURL(rawURL string, c Condition) (string, error) {
+	p, err := c.policy(rawURL)
+	if err != nil {
+		return "", err
+	}
+	signed, err := sign.NewURLSigner(s.keyPairID, s.privateKey).SignWithPolicy(rawURL, p)
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to sign the URL")
+	}
+	return signed, nil
+}
+
+// SignCookies returns the signed cookies (CloudFront-Policy, CloudFront-Signature and CloudFront-Key-Pair-Id)
+// for the resource. The resource can have wildcards. e.g. https://example.com/private/*
+// The cookies are secure, HTTP only and valid for all paths of the domain until the expiration time.
+func (s *Signer) SignCookies(resource string, c Condition) ([]*http.Cookie, error) {
+	p, err := c.policy(resource)
+	if err != nil {
+		return nil, err
+	}
+	cookies, err := sign.NewCookieSigner(s.keyPairID, s.privateKey, func(o *sign.CookieOptions) {
+		o.Path = "/"
+		o.Secure = true
+	}).SignWithPolicy(p)
+	if err != nil {
+		return nil, errfmt.Wrap(err, "failed to sign the cookies")
+	}
+	for _, cookie := range cookies {
+		cookie.Expires = c.Expires
+	}
+	return cookies, nil
+}
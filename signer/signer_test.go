@@ -0,0 +1,147 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // CloudFront signs the policy with SHA-1.
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nao1215/spare/app/domain/model"
+)
+
+// newTestSigner returns the signer and the public key for the tests.
+func newTestSigner(t *testing.T) (*Signer, *rsa.PublicKey) {
+	t.Helper()
+	pair, err := model.NewSigningKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New("K2JCJMDEHXQW5F", pair.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(pair.PublicKey)
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, public.(*rsa.PublicKey) //nolint:forcetypeassert
+}
+
+// decodeCloudFrontBase64 decodes the base64 string that CloudFront uses.
+func decodeCloudFrontBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(strings.NewReplacer("-", "+", "_", "=", "~", "/").Replace(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSignerSignURL(t *testing.T) {
+	t.Parallel()
+
+	s, public := newTestSigner(t)
+	expires := time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)
+	signed, err := s.SignURL("https://example.com/private/report.pdf?v=1", Condition{Expires: expires, IPAddress: "203.0.113.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("v") != "1" || q.Get("Key-Pair-Id") != "K2JCJMDEHXQW5F" {
+		t.Errorf("Signer.SignURL() = %s, want the original query and Key-Pair-Id", signed)
+	}
+	policy := decodeCloudFrontBase64(t, q.Get("Policy"))
+	want := `{"Statement":[{"Resource":"https://example.com/private/report.pdf?v=1","Condition":{"IpAddress":{"AWS:SourceIp":"203.0.113.10/32"},"DateLessThan":{"AWS:EpochTime":1697760000}}}]}`
+	if string(policy) != want {
+		t.Errorf("policy = %s, want %s", policy, want)
+	}
+	hash := sha1.Sum(policy) //nolint:gosec
+	if err := rsa.VerifyPKCS1v15(public, crypto.SHA1, hash[:], decodeCloudFrontBase64(t, q.Get("Signature"))); err != nil {
+		t.Errorf("signature is invalid: %v", err)
+	}
+}
+
+func TestSignerSignCookies(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestSigner(t)
+	expires := time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)
+	cookies, err := s.SignCookies("https://example.com/private/*", Condition{Expires: expires})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, c := range cookies {
+		names = append(names, c.Name)
+		if !c.Secure || !c.HttpOnly || c.Path != "/" || !c.Expires.Equal(expires) {
+			t.Errorf("cookie %s must be secure, HTTP only, for all paths and expire at %s", c.Name, expires)
+		}
+	}
+	if got := strings.Join(names, ","); got != "CloudFront-Policy,CloudFront-Signature,CloudFront-Key-Pair-Id" {
+		t.Errorf("cookie names = %s", got)
+	}
+}
+
+func TestConditionValidate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		c       Condition
+		wantErr bool
+	}{
+		{
+			name:    "success. expiration time only",
+			c:       Condition{Expires: now},
+			wantErr: false,
+		},
+		{
+			name:    "success. all conditions",
+			c:       Condition{Expires: now, NotBefore: now.Add(-time.Hour), IPAddress: "2001:db8::/32"},
+			wantErr: false,
+		},
+		{
+			name:    "failure. no expiration time",
+			c:       Condition{IPAddress: "203.0.113.10"},
+			wantErr: true,
+		},
+		{
+			name:    "failure. start time is after the expiration time",
+			c:       Condition{Expires: now, NotBefore: now.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "failure. invalid IP address",
+			c:       Condition{Expires: now, IPAddress: "203.0.113.300"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Condition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCondition) {
+				t.Errorf("Condition.Validate() error = %v, want %v", err, ErrInvalidCondition)
+			}
+		})
+	}
+}